@@ -19,7 +19,6 @@ package factory
 
 import (
 	"errors"
-	"fmt"
 
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/pkcs11"
@@ -48,19 +47,11 @@ func (f *PKCS11Factory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
 
 	p11Opts := config.Pkcs11Opts
 
-	//TODO: PKCS11 does not need a keystore, but we have not migrated all of PKCS11 BCCSP to PKCS11 yet
-	var ks bccsp.KeyStore
-	if p11Opts.Ephemeral == true {
-		ks = sw.NewDummyKeyStore()
-	} else if p11Opts.FileKeystore != nil {
-		fks, err := sw.NewFileBasedKeyStore(nil, p11Opts.FileKeystore.KeyStorePath, false)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to initialize software key store: %s", err)
-		}
-		ks = fks
-	} else {
-		// Default to DummyKeystore
-		ks = sw.NewDummyKeyStore()
+	// Keys are held by the token. The software keystore only backs the
+	// fallback SW BCCSP, whose keys are never persisted
+	if p11Opts.FileKeystore != nil {
+		logger.Warningf("Ignoring the PKCS11 FileKeystore [%s]: keys are held by the token", p11Opts.FileKeystore.KeyStorePath)
 	}
+	ks := sw.NewDummyKeyStore()
 	return pkcs11.New(*p11Opts, ks)
 }
@@ -165,6 +165,8 @@ func (*KeyImportOpts) Ephemeral() bool {
 
 type EncrypterOpts struct{}
 
+type DecrypterOpts struct{}
+
 type HashOpts struct{}
 
 func (HashOpts) Algorithm() string {
@@ -80,6 +80,16 @@ const (
 	// SHA3_384
 	SHA3_384 = "SHA3_384"
 
+	// ED25519 Edwards-curve Digital Signature Algorithm over Curve25519 (key gen, import, sign, verify)
+	ED25519 = "ED25519"
+
+	// SM2 Chinese national elliptic curve public key algorithm (key gen, import, sign, verify)
+	SM2 = "SM2"
+	// SM3 Chinese national hash algorithm
+	SM3 = "SM3"
+	// SM4 Chinese national block cipher (key gen, import, encrypt, decrypt)
+	SM4 = "SM4"
+
 	// X509Certificate Label for X509 certificate related operation
 	X509Certificate = "X509Certificate"
 )
@@ -0,0 +1,197 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"crypto/aes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/miekg/pkcs11"
+	"github.com/op/go-logging"
+)
+
+// generateAESKey generates an AES key of length bytes held by the token. The
+// value of the key cannot be read from the token when the keys are sensitive,
+// so its SKI is random
+func (csp *impl) generateAESKey(length int, ephemeral bool) (ski []byte, err error) {
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("Failed generating the SKI of the AES key [%s]", err)
+	}
+	hash := sha256.Sum256(nonce)
+	ski = hash[:]
+
+	keyTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, !ephemeral),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, length),
+
+		pkcs11.NewAttribute(pkcs11.CKA_ID, ski),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, hex.EncodeToString(ski)),
+
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, csp.noPrivImport),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, !csp.noPrivImport),
+	}
+
+	key, err := p11lib.GenerateKey(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_GEN, nil)},
+		keyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("P11: AES key generate failed [%s]\n", err)
+	}
+
+	logger.Infof("Generated new P11 AES key, SKI %x\n", ski)
+	if logger.IsEnabledFor(logging.DEBUG) {
+		listAttrs(p11lib, session, key)
+	}
+
+	return ski, nil
+}
+
+// importAESKey imports the AES key into the token. Its SKI is computed as the
+// software BCCSP does
+func (csp *impl) importAESKey(raw []byte, ephemeral bool) (ski []byte, err error) {
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	hash := sha256.New()
+	hash.Write([]byte{0x01})
+	hash.Write(raw)
+	ski = hash.Sum(nil)
+
+	keyTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, !ephemeral),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+
+		pkcs11.NewAttribute(pkcs11.CKA_ID, ski),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, hex.EncodeToString(ski)),
+
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, csp.noPrivImport),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, !csp.noPrivImport),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, raw),
+	}
+
+	key, err := p11lib.CreateObject(session, keyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("P11: AES key import failed [%s]\n", err)
+	}
+
+	if logger.IsEnabledFor(logging.DEBUG) {
+		listAttrs(p11lib, session, key)
+	}
+
+	return ski, nil
+}
+
+// getAESKey looks for an AES key by SKI, stored in CKA_ID
+func (csp *impl) getAESKey(ski []byte) (*pkcs11.ObjectHandle, error) {
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	return findKeyFromSKI(p11lib, session, ski, pkcs11.CKO_SECRET_KEY)
+}
+
+// getAESKeyValue returns the value of the AES key, which the token only
+// reveals when the keys are not sensitive
+func (csp *impl) getAESKeyValue(ski []byte) ([]byte, error) {
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	key, err := findKeyFromSKI(p11lib, session, ski, pkcs11.CKO_SECRET_KEY)
+	if err != nil {
+		return nil, fmt.Errorf("Secret key not found [%s]\n", err)
+	}
+
+	attr, err := p11lib.GetAttributeValue(session, *key, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("P11: get(AES key value) [%s]\n", err)
+	}
+	if len(attr) == 0 || len(attr[0].Value) == 0 {
+		return nil, errors.New("No value found for the AES key")
+	}
+
+	return attr[0].Value, nil
+}
+
+// encryptAES encrypts the plaintext with the AES key in CBC mode with PKCS#7
+// padding. The random IV is prepended to the ciphertext, as the software
+// BCCSP does
+func (csp *impl) encryptAES(ski, plaintext []byte) ([]byte, error) {
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	key, err := findKeyFromSKI(p11lib, session, ski, pkcs11.CKO_SECRET_KEY)
+	if err != nil {
+		return nil, fmt.Errorf("Secret key not found [%s]\n", err)
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("Failed generating IV [%s]", err)
+	}
+
+	err = p11lib.EncryptInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_CBC_PAD, iv)}, *key)
+	if err != nil {
+		return nil, fmt.Errorf("Encrypt-initialize failed [%s]\n", err)
+	}
+	ciphertext, err := p11lib.Encrypt(session, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("P11: encrypt failed [%s]\n", err)
+	}
+
+	return append(iv, ciphertext...), nil
+}
+
+// decryptAES decrypts the ciphertext of encryptAES with the AES key
+func (csp *impl) decryptAES(ski, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 2*aes.BlockSize || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("Invalid ciphertext. It must be a multiple of the block size and contain the IV.")
+	}
+
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	key, err := findKeyFromSKI(p11lib, session, ski, pkcs11.CKO_SECRET_KEY)
+	if err != nil {
+		return nil, fmt.Errorf("Secret key not found [%s]\n", err)
+	}
+
+	iv := ciphertext[:aes.BlockSize]
+	err = p11lib.DecryptInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_CBC_PAD, iv)}, *key)
+	if err != nil {
+		return nil, fmt.Errorf("Decrypt-initialize failed [%s]\n", err)
+	}
+	plaintext, err := p11lib.Decrypt(session, ciphertext[aes.BlockSize:])
+	if err != nil {
+		return nil, fmt.Errorf("P11: decrypt failed [%s]\n", err)
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"errors"
+
+	"github.com/hyperledger/fabric/bccsp"
+)
+
+// aesPrivateKey is an AES key held by the token, identified by its SKI
+type aesPrivateKey struct {
+	ski []byte
+}
+
+// Bytes converts this key to its byte representation,
+// if this operation is allowed.
+func (k *aesPrivateKey) Bytes() (raw []byte, err error) {
+	return nil, errors.New("Not supported.")
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *aesPrivateKey) SKI() (ski []byte) {
+	return k.ski
+}
+
+// Symmetric returns true if this key is a symmetric key,
+// false if this key is asymmetric
+func (k *aesPrivateKey) Symmetric() bool {
+	return true
+}
+
+// Private returns true if this key is a private key,
+// false otherwise.
+func (k *aesPrivateKey) Private() bool {
+	return true
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+// This method returns an error in symmetric key schemes.
+func (k *aesPrivateKey) PublicKey() (bccsp.Key, error) {
+	return nil, errors.New("Cannot call this method on a symmetric key.")
+}
@@ -120,6 +120,33 @@ func (csp *impl) KeyGen(opts bccsp.KeyGenOpts) (k bccsp.Key, err error) {
 
 		k = &ecdsaPrivateKey{ski, ecdsaPublicKey{ski, pub}}
 
+	case *bccsp.AESKeyGenOpts:
+		return csp.generateAESKeyWithOpts(csp.conf.aesBitLength, opts)
+
+	case *bccsp.AES256KeyGenOpts:
+		return csp.generateAESKeyWithOpts(32, opts)
+
+	case *bccsp.AES192KeyGenOpts:
+		return csp.generateAESKeyWithOpts(24, opts)
+
+	case *bccsp.AES128KeyGenOpts:
+		return csp.generateAESKeyWithOpts(16, opts)
+
+	case *bccsp.RSAKeyGenOpts:
+		return csp.generateRSAKeyWithOpts(csp.conf.rsaBitLength, opts)
+
+	case *bccsp.RSA1024KeyGenOpts:
+		return csp.generateRSAKeyWithOpts(1024, opts)
+
+	case *bccsp.RSA2048KeyGenOpts:
+		return csp.generateRSAKeyWithOpts(2048, opts)
+
+	case *bccsp.RSA3072KeyGenOpts:
+		return csp.generateRSAKeyWithOpts(3072, opts)
+
+	case *bccsp.RSA4096KeyGenOpts:
+		return csp.generateRSAKeyWithOpts(4096, opts)
+
 	default:
 		return csp.BCCSP.KeyGen(opts)
 	}
@@ -127,6 +154,24 @@ func (csp *impl) KeyGen(opts bccsp.KeyGenOpts) (k bccsp.Key, err error) {
 	return k, nil
 }
 
+func (csp *impl) generateAESKeyWithOpts(length int, opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	ski, err := csp.generateAESKey(length, opts.Ephemeral())
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating AES %d key [%s]", length*8, err)
+	}
+
+	return &aesPrivateKey{ski}, nil
+}
+
+func (csp *impl) generateRSAKeyWithOpts(bits int, opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	ski, pub, err := csp.generateRSAKey(bits, opts.Ephemeral())
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating RSA %d key [%s]", bits, err)
+	}
+
+	return &rsaPrivateKey{ski, rsaPublicKey{ski, pub}}, nil
+}
+
 // KeyDeriv derives a key from k using opts.
 // The opts argument should be appropriate for the primitive used.
 func (csp *impl) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts) (dk bccsp.Key, err error) {
@@ -267,6 +312,32 @@ func (csp *impl) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts) (dk bccsp.Key, e
 
 		}
 
+	case *aesPrivateKey:
+		// Validate opts
+		if opts == nil {
+			return nil, errors.New("Invalid Opts parameter. It must not be nil.")
+		}
+
+		switch opts.(type) {
+		case *bccsp.HMACTruncated256AESDeriveKeyOpts, *bccsp.HMACDeriveKeyOpts:
+			// The derivation happens in software, which requires the value of
+			// the key. The token refuses to reveal it when the key is sensitive
+			value, err := csp.getAESKeyValue(k.SKI())
+			if err != nil {
+				return nil, fmt.Errorf("Could not obtain AES key [%s]", err)
+			}
+
+			swK, err := csp.BCCSP.KeyImport(value, &bccsp.HMACImportKeyOpts{Temporary: true})
+			if err != nil {
+				return nil, fmt.Errorf("Failed importing AES key for derivation [%s]", err)
+			}
+
+			return csp.BCCSP.KeyDeriv(swK, opts)
+
+		default:
+			return nil, fmt.Errorf("Unrecognized KeyDerivOpts provided [%s]", opts.Algorithm())
+		}
+
 	default:
 		return csp.BCCSP.KeyDeriv(k, opts)
 
@@ -287,6 +358,27 @@ func (csp *impl) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (k bccsp.K
 
 	switch opts.(type) {
 
+	case *bccsp.AES256ImportKeyOpts:
+		aesRaw, ok := raw.([]byte)
+		if !ok {
+			return nil, errors.New("[AES256ImportKeyOpts] Invalid raw material. Expected byte array.")
+		}
+
+		if aesRaw == nil {
+			return nil, errors.New("[AES256ImportKeyOpts] Invalid raw material. It must not be nil.")
+		}
+
+		if len(aesRaw) != 32 {
+			return nil, fmt.Errorf("[AES256ImportKeyOpts] Invalid Key Length [%d]. Must be 32 bytes", len(aesRaw))
+		}
+
+		ski, err := csp.importAESKey(aesRaw, opts.Ephemeral())
+		if err != nil {
+			return nil, fmt.Errorf("Failed importing AES key [%s]", err)
+		}
+
+		return &aesPrivateKey{ski}, nil
+
 	case *bccsp.ECDSAPKIXPublicKeyImportOpts:
 		der, ok := raw.([]byte)
 		if !ok {
@@ -441,6 +533,19 @@ func (csp *impl) GetKey(ski []byte) (k bccsp.Key, err error) {
 			return &ecdsaPublicKey{ski, pubKey}, nil
 		}
 	}
+
+	rsaPubKey, isPriv, err := csp.getRSAKey(ski)
+	if err == nil {
+		if isPriv {
+			return &rsaPrivateKey{ski, rsaPublicKey{ski, rsaPubKey}}, nil
+		}
+		return &rsaPublicKey{ski, rsaPubKey}, nil
+	}
+
+	if _, err = csp.getAESKey(ski); err == nil {
+		return &aesPrivateKey{ski}, nil
+	}
+
 	return csp.BCCSP.GetKey(ski)
 }
 
@@ -463,6 +568,11 @@ func (csp *impl) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) (signat
 	switch k.(type) {
 	case *ecdsaPrivateKey:
 		return csp.signECDSA(*k.(*ecdsaPrivateKey), digest, opts)
+	case *rsaPrivateKey:
+		if opts == nil {
+			return nil, errors.New("Invalid options. Must be different from nil.")
+		}
+		return csp.signP11RSA(k.(*rsaPrivateKey), digest, opts)
 	default:
 		return csp.BCCSP.Sign(k, digest, opts)
 	}
@@ -487,6 +597,10 @@ func (csp *impl) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.Signer
 		return csp.verifyECDSA(k.(*ecdsaPrivateKey).pub, signature, digest, opts)
 	case *ecdsaPublicKey:
 		return csp.verifyECDSA(*k.(*ecdsaPublicKey), signature, digest, opts)
+	case *rsaPrivateKey:
+		return verifyRSA(k.(*rsaPrivateKey).pub.pub, signature, digest, opts)
+	case *rsaPublicKey:
+		return verifyRSA(k.(*rsaPublicKey).pub, signature, digest, opts)
 	default:
 		return csp.BCCSP.Verify(k, signature, digest, opts)
 	}
@@ -495,14 +609,57 @@ func (csp *impl) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.Signer
 // Encrypt encrypts plaintext using key k.
 // The opts argument should be appropriate for the primitive used.
 func (csp *impl) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) (ciphertext []byte, err error) {
-	// TODO: Add PKCS11 support for encryption, when fabric starts requiring it
-	return csp.BCCSP.Encrypt(k, plaintext, opts)
+	// Validate arguments
+	if k == nil {
+		return nil, errors.New("Invalid Key. It must not be nil.")
+	}
+
+	switch k.(type) {
+	case *aesPrivateKey:
+		switch opts.(type) {
+		case *bccsp.AESCBCPKCS7ModeOpts, bccsp.AESCBCPKCS7ModeOpts:
+			return csp.encryptAES(k.SKI(), plaintext)
+		default:
+			return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+		}
+	case *rsaPrivateKey, *rsaPublicKey:
+		switch opts.(type) {
+		case *bccsp.RSAOAEPOpts:
+			return csp.encryptP11RSAOAEP(k.SKI(), plaintext, opts.(*bccsp.RSAOAEPOpts))
+		default:
+			return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+		}
+	default:
+		return csp.BCCSP.Encrypt(k, plaintext, opts)
+	}
 }
 
 // Decrypt decrypts ciphertext using key k.
 // The opts argument should be appropriate for the primitive used.
 func (csp *impl) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) (plaintext []byte, err error) {
-	return csp.BCCSP.Decrypt(k, ciphertext, opts)
+	// Validate arguments
+	if k == nil {
+		return nil, errors.New("Invalid Key. It must not be nil.")
+	}
+
+	switch k.(type) {
+	case *aesPrivateKey:
+		switch opts.(type) {
+		case *bccsp.AESCBCPKCS7ModeOpts, bccsp.AESCBCPKCS7ModeOpts:
+			return csp.decryptAES(k.SKI(), ciphertext)
+		default:
+			return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+		}
+	case *rsaPrivateKey:
+		switch opts.(type) {
+		case *bccsp.RSAOAEPOpts:
+			return csp.decryptP11RSAOAEP(k.SKI(), ciphertext, opts.(*bccsp.RSAOAEPOpts))
+		default:
+			return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+		}
+	default:
+		return csp.BCCSP.Decrypt(k, ciphertext, opts)
+	}
 }
 
 // THIS IS ONLY USED FOR TESTING
@@ -520,6 +677,7 @@ func FindPKCS11Lib() (lib, pin, label string) {
 			"/usr/lib/s390x-linux-gnu/softhsm/libsofthsm2.so",            //Ubuntu
 			"/usr/lib/powerpc64le-linux-gnu/softhsm/libsofthsm2.so",      //Power
 			"/usr/local/Cellar/softhsm/2.1.0/lib/softhsm/libsofthsm2.so", //MacOS
+			"/usr/local/lib/softhsm/libsofthsm2.so",                      //From source
 		}
 		for _, path := range possibilities {
 			if _, err := os.Stat(path); !os.IsNotExist(err) {
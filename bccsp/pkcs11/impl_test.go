@@ -18,10 +18,12 @@ package pkcs11
 import (
 	"bytes"
 	"crypto"
+	"crypto/aes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
 	"crypto/x509"
@@ -37,6 +39,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/mocks"
 	"github.com/hyperledger/fabric/bccsp/signer"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/bccsp/utils"
@@ -62,12 +65,8 @@ func TestMain(m *testing.M) {
 	// Activate DEBUG level to cover listAttrs function
 	logging.SetLevel(logging.DEBUG, "bccsp_p11")
 
-	ks, err := sw.NewFileBasedKeyStore(nil, os.TempDir(), false)
-	if err != nil {
-		fmt.Printf("Failed initiliazing KeyStore [%s]", err)
-		os.Exit(-1)
-	}
-	currentKS = ks
+	// Keys are held by the token
+	currentKS = sw.NewDummyKeyStore()
 
 	lib, pin, label := FindPKCS11Lib()
 	tests := []testConfig{
@@ -1233,6 +1232,9 @@ func TestAESDecrypt(t *testing.T) {
 }
 
 func TestHMACTruncated256KeyDerivOverAES256Key(t *testing.T) {
+	if currentTestConfig.noKeyImport {
+		t.Skip("Sensitive keys. Skipping AES Derivation tests as they require the value of the key.")
+	}
 
 	k, err := currentBCCSP.KeyGen(&bccsp.AESKeyGenOpts{Temporary: false})
 	if err != nil {
@@ -1282,6 +1284,9 @@ func TestHMACTruncated256KeyDerivOverAES256Key(t *testing.T) {
 }
 
 func TestHMACKeyDerivOverAES256Key(t *testing.T) {
+	if currentTestConfig.noKeyImport {
+		t.Skip("Sensitive keys. Skipping AES Derivation tests as they require the value of the key.")
+	}
 
 	k, err := currentBCCSP.KeyGen(&bccsp.AESKeyGenOpts{Temporary: false})
 	if err != nil {
@@ -1400,6 +1405,45 @@ func TestAES256KeyGenSKI(t *testing.T) {
 
 }
 
+func TestAESKeyHeldByToken(t *testing.T) {
+
+	k, err := currentBCCSP.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: false})
+	if err != nil {
+		t.Fatalf("Failed generating AES_256 key [%s]", err)
+	}
+
+	k2, err := currentBCCSP.GetKey(k.SKI())
+	if err != nil {
+		t.Fatalf("Failed getting AES_256 key [%s]", err)
+	}
+	if _, ok := k2.(*aesPrivateKey); !ok {
+		t.Fatalf("AES_256 key must be held by the token, got [%T]", k2)
+	}
+
+	msg := []byte("Hello World")
+	ct, err := currentBCCSP.Encrypt(k, msg, &bccsp.AESCBCPKCS7ModeOpts{})
+	if err != nil {
+		t.Fatalf("Failed encrypting [%s]", err)
+	}
+
+	// The ciphertext is compatible with the software implementation
+	value, err := currentBCCSP.(*impl).getAESKeyValue(k.SKI())
+	if currentTestConfig.noKeyImport {
+		assert.Error(t, err, "The value of a sensitive key must not be revealed")
+	} else {
+		assert.NoError(t, err)
+		pt, err := sw.AESCBCPKCS7Decrypt(value, ct)
+		assert.NoError(t, err)
+		assert.Equal(t, msg, pt)
+	}
+
+	_, err = currentBCCSP.Decrypt(k2, ct[:aes.BlockSize], &bccsp.AESCBCPKCS7ModeOpts{})
+	assert.Error(t, err, "Decrypting a ciphertext without blocks must fail")
+
+	_, err = currentBCCSP.Encrypt(k, msg, &mocks.EncrypterOpts{})
+	assert.Error(t, err, "Encrypting with unknown options must fail")
+}
+
 func TestSHA(t *testing.T) {
 
 	for i := 0; i < 100; i++ {
@@ -1659,13 +1703,8 @@ func TestRSAVerify(t *testing.T) {
 		t.Fatal("Failed verifying RSA signature. Signature not valid.")
 	}
 
-	// Store public key
-	err = currentKS.StoreKey(pk)
-	if err != nil {
-		t.Fatalf("Failed storing corresponding public key [%s]", err)
-	}
-
-	pk2, err := currentKS.GetKey(pk.SKI())
+	// Retrieve the key from the token
+	pk2, err := currentBCCSP.GetKey(pk.SKI())
 	if err != nil {
 		t.Fatalf("Failed retrieving corresponding public key [%s]", err)
 	}
@@ -1680,6 +1719,73 @@ func TestRSAVerify(t *testing.T) {
 
 }
 
+func TestRSASignPKCS1v15(t *testing.T) {
+
+	k, err := currentBCCSP.KeyGen(&bccsp.RSAKeyGenOpts{Temporary: false})
+	if err != nil {
+		t.Fatalf("Failed generating RSA key [%s]", err)
+	}
+
+	digest := sha256.Sum256([]byte("Hello World"))
+	signature, err := currentBCCSP.Sign(k, digest[:], crypto.SHA256)
+	if err != nil {
+		t.Fatalf("Failed generating RSA signature [%s]", err)
+	}
+
+	pub := k.(*rsaPrivateKey).pub.pub
+	assert.NoError(t, rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature))
+
+	_, err = currentBCCSP.Sign(k, digest[:], crypto.SHA384)
+	assert.Error(t, err, "Signing a digest of the wrong length must fail")
+}
+
+func TestRSAOAEPEncryptDecrypt(t *testing.T) {
+
+	k, err := currentBCCSP.KeyGen(&bccsp.RSAKeyGenOpts{Temporary: false})
+	if err != nil {
+		t.Fatalf("Failed generating RSA key [%s]", err)
+	}
+
+	pk, err := k.PublicKey()
+	if err != nil {
+		t.Fatalf("Failed getting corresponding public key [%s]", err)
+	}
+
+	// SoftHSM only supports OAEP with SHA-1
+	opts := &bccsp.RSAOAEPOpts{Hash: crypto.SHA1}
+	msg := []byte("Hello World")
+
+	ct, err := currentBCCSP.Encrypt(pk, msg, opts)
+	if err != nil {
+		t.Fatalf("Failed encrypting [%s]", err)
+	}
+
+	pt, err := currentBCCSP.Decrypt(k, ct, opts)
+	if err != nil {
+		t.Fatalf("Failed decrypting [%s]", err)
+	}
+	if !bytes.Equal(msg, pt) {
+		t.Fatalf("Failed decrypting. Decrypted plaintext is different from the original. [%x][%x]", msg, pt)
+	}
+
+	// The ciphertext of the software implementation is decrypted by the token
+	pub := pk.(*rsaPublicKey).pub
+	ct, err = rsa.EncryptOAEP(sha1.New(), rand.Reader, pub, msg, nil)
+	assert.NoError(t, err)
+	pt, err = currentBCCSP.Decrypt(k, ct, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, pt)
+
+	_, err = currentBCCSP.Decrypt(pk, ct, opts)
+	assert.Error(t, err, "Decrypting with a public key must fail")
+
+	_, err = currentBCCSP.Encrypt(pk, msg, &bccsp.RSAOAEPOpts{Hash: crypto.MD5})
+	assert.Error(t, err, "Encrypting with an unsupported hash must fail")
+
+	_, err = currentBCCSP.Decrypt(k, ct, &mocks.DecrypterOpts{})
+	assert.Error(t, err, "Decrypting with unknown options must fail")
+}
+
 func TestRSAKeyImportFromRSAPublicKey(t *testing.T) {
 
 	// Generate an RSA key
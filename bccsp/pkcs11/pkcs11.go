@@ -444,8 +444,13 @@ func findKeyPairFromSKI(mod *pkcs11.Ctx, session pkcs11.SessionHandle, ski []byt
 		ktype = pkcs11.CKO_PRIVATE_KEY
 	}
 
+	return findKeyFromSKI(mod, session, ski, ktype)
+}
+
+// findKeyFromSKI looks for the object of the given class whose CKA_ID is ski
+func findKeyFromSKI(mod *pkcs11.Ctx, session pkcs11.SessionHandle, ski []byte, class uint) (*pkcs11.ObjectHandle, error) {
 	template := []*pkcs11.Attribute{
-		pkcs11.NewAttribute(pkcs11.CKA_CLASS, ktype),
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
 		pkcs11.NewAttribute(pkcs11.CKA_ID, ski),
 	}
 	if err := mod.FindObjectsInit(session, template); err != nil {
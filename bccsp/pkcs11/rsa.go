@@ -0,0 +1,396 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/miekg/pkcs11"
+	"github.com/op/go-logging"
+)
+
+// rsaHashMechanisms maps the hash functions usable with OAEP and PSS to the
+// corresponding PKCS#11 hash mechanism and mask generation function
+var rsaHashMechanisms = map[crypto.Hash]struct{ hash, mgf uint }{
+	crypto.SHA1:   {pkcs11.CKM_SHA_1, pkcs11.CKG_MGF1_SHA1},
+	crypto.SHA224: {pkcs11.CKM_SHA224, pkcs11.CKG_MGF1_SHA224},
+	crypto.SHA256: {pkcs11.CKM_SHA256, pkcs11.CKG_MGF1_SHA256},
+	crypto.SHA384: {pkcs11.CKM_SHA384, pkcs11.CKG_MGF1_SHA384},
+	crypto.SHA512: {pkcs11.CKM_SHA512, pkcs11.CKG_MGF1_SHA512},
+}
+
+// pkcs1v15HashPrefixes are the DER encodings of the DigestInfo prefixes that
+// CKM_RSA_PKCS expects in front of the digest (RFC 3447, section 9.2)
+var pkcs1v15HashPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA1:   {0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14},
+	crypto.SHA224: {0x30, 0x2d, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x04, 0x05, 0x00, 0x04, 0x1c},
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+func (csp *impl) generateRSAKey(bits int, ephemeral bool) (ski []byte, pubKey *rsa.PublicKey, err error) {
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	id := nextIDCtr()
+	publabel := fmt.Sprintf("BCPUB%s", id.Text(16))
+	prvlabel := fmt.Sprintf("BCPRV%s", id.Text(16))
+
+	pubkey_t := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_RSA),
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, !ephemeral),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_MODULUS_BITS, bits),
+		pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, []byte{0x01, 0x00, 0x01}),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, false),
+
+		pkcs11.NewAttribute(pkcs11.CKA_ID, publabel),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, publabel),
+	}
+
+	prvkey_t := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_RSA),
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, !ephemeral),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+
+		pkcs11.NewAttribute(pkcs11.CKA_ID, prvlabel),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, prvlabel),
+
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, !csp.noPrivImport),
+	}
+
+	pub, prv, err := p11lib.GenerateKeyPair(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS_KEY_PAIR_GEN, nil)},
+		pubkey_t, prvkey_t)
+	if err != nil {
+		return nil, nil, fmt.Errorf("P11: keypair generate failed [%s]\n", err)
+	}
+
+	pubKey, err = rsaPublicKeyFromObject(p11lib, session, pub)
+	if err != nil {
+		return nil, nil, err
+	}
+	ski = rsaSKI(pubKey)
+
+	// set CKA_ID of the both keys to SKI(public key) and CKA_LABEL to hex string of SKI
+	setski_t := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_ID, ski),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, hex.EncodeToString(ski)),
+	}
+
+	logger.Infof("Generated new P11 RSA key, SKI %x\n", ski)
+	err = p11lib.SetAttributeValue(session, pub, setski_t)
+	if err != nil {
+		return nil, nil, fmt.Errorf("P11: set-ID-to-SKI[public] failed [%s]\n", err)
+	}
+
+	err = p11lib.SetAttributeValue(session, prv, setski_t)
+	if err != nil {
+		return nil, nil, fmt.Errorf("P11: set-ID-to-SKI[private] failed [%s]\n", err)
+	}
+
+	if logger.IsEnabledFor(logging.DEBUG) {
+		listAttrs(p11lib, session, prv)
+		listAttrs(p11lib, session, pub)
+	}
+
+	return ski, pubKey, nil
+}
+
+// rsaPublicKeyFromObject reads the modulus and public exponent of an RSA key
+func rsaPublicKeyFromObject(p11lib *pkcs11.Ctx, session pkcs11.SessionHandle, key pkcs11.ObjectHandle) (*rsa.PublicKey, error) {
+	attrs, err := p11lib.GetAttributeValue(session, key, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_MODULUS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, nil),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("P11: get(RSA public key) [%s]\n", err)
+	}
+
+	var modulus, exponent []byte
+	for _, a := range attrs {
+		switch a.Type {
+		case pkcs11.CKA_MODULUS:
+			modulus = a.Value
+		case pkcs11.CKA_PUBLIC_EXPONENT:
+			exponent = a.Value
+		}
+	}
+	if len(modulus) == 0 || len(exponent) == 0 {
+		return nil, errors.New("Not an RSA key")
+	}
+
+	e := new(big.Int).SetBytes(exponent)
+	if e.BitLen() > 31 {
+		return nil, fmt.Errorf("Invalid RSA public exponent [%x]", exponent)
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(e.Int64())}, nil
+}
+
+// Look for an RSA key by SKI, stored in CKA_ID
+func (csp *impl) getRSAKey(ski []byte) (pubKey *rsa.PublicKey, isPriv bool, err error) {
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	isPriv = true
+	_, err = findKeyPairFromSKI(p11lib, session, ski, privateKeyFlag)
+	if err != nil {
+		isPriv = false
+		logger.Debugf("Private key not found [%s] for SKI [%s], looking for Public key", err, hex.EncodeToString(ski))
+	}
+
+	publicKey, err := findKeyPairFromSKI(p11lib, session, ski, publicKeyFlag)
+	if err != nil {
+		return nil, false, fmt.Errorf("Public key not found [%s] for SKI [%s]", err, hex.EncodeToString(ski))
+	}
+
+	pubKey, err = rsaPublicKeyFromObject(p11lib, session, *publicKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("Public key not found [%s] for SKI [%s]", err, hex.EncodeToString(ski))
+	}
+
+	return pubKey, isPriv, nil
+}
+
+// signP11RSA signs the digest with the private key held by the token. opts
+// must be either *rsa.PSSOptions, for RSASSA-PSS, or a crypto.Hash, for
+// RSASSA-PKCS1-v1_5, as for rsa.PrivateKey.Sign
+func (csp *impl) signP11RSA(k *rsaPrivateKey, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	var mech *pkcs11.Mechanism
+	switch o := opts.(type) {
+	case *rsa.PSSOptions:
+		if !o.Hash.Available() {
+			return nil, fmt.Errorf("Hash function not available [%s]", o.Hash)
+		}
+		if len(digest) != o.Hash.Size() {
+			return nil, errors.New("Invalid digest. Its length must match the hash function.")
+		}
+
+		saltLength := o.SaltLength
+		switch saltLength {
+		case rsa.PSSSaltLengthAuto:
+			// As rsa.SignPSS, use the largest salt that fits the modulus
+			saltLength = (k.pub.pub.N.BitLen()-1+7)/8 - 2 - o.Hash.Size()
+		case rsa.PSSSaltLengthEqualsHash:
+			saltLength = o.Hash.Size()
+		}
+		if saltLength < 0 {
+			return nil, errors.New("Invalid salt length")
+		}
+
+		if m, ok := rsaHashMechanisms[o.Hash]; ok {
+			mech = pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS_PSS, pkcs11.NewPSSParams(m.hash, m.mgf, uint(saltLength)))
+			break
+		}
+
+		// Tokens do not offer PSS with the other hash functions (e.g. SHA3),
+		// so the message is encoded here and signed with raw RSA
+		salt := make([]byte, saltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("Failed generating salt [%s]", err)
+		}
+		em, err := emsaPSSEncode(digest, k.pub.pub.N.BitLen()-1, salt, o.Hash.New())
+		if err != nil {
+			return nil, err
+		}
+		digest = make([]byte, (k.pub.pub.N.BitLen()+7)/8)
+		copy(digest[len(digest)-len(em):], em)
+		mech = pkcs11.NewMechanism(pkcs11.CKM_RSA_X_509, nil)
+	case crypto.Hash:
+		prefix, ok := pkcs1v15HashPrefixes[o]
+		if !ok {
+			return nil, fmt.Errorf("Unsupported hash function for PKCS#1 v1.5 [%s]", o)
+		}
+		if len(digest) != o.Size() {
+			return nil, errors.New("Invalid digest. Its length must match the hash function.")
+		}
+		digest = append(append([]byte{}, prefix...), digest...)
+		mech = pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS, nil)
+	default:
+		return nil, fmt.Errorf("Opts type not recognized [%s]", opts)
+	}
+
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	privateKey, err := findKeyPairFromSKI(p11lib, session, k.ski, privateKeyFlag)
+	if err != nil {
+		return nil, fmt.Errorf("Private key not found [%s]\n", err)
+	}
+
+	err = p11lib.SignInit(session, []*pkcs11.Mechanism{mech}, *privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("Sign-initialize  failed [%s]\n", err)
+	}
+
+	sig, err := p11lib.Sign(session, digest)
+	if err != nil {
+		return nil, fmt.Errorf("P11: sign failed [%s]\n", err)
+	}
+
+	return sig, nil
+}
+
+// emsaPSSEncode encodes the digest as in RFC 3447, section 9.1.1
+func emsaPSSEncode(mHash []byte, emBits int, salt []byte, hash hash.Hash) ([]byte, error) {
+	hLen := hash.Size()
+	sLen := len(salt)
+	emLen := (emBits + 7) / 8
+
+	if emLen < hLen+sLen+2 {
+		return nil, errors.New("Key size too small for PSS signature")
+	}
+
+	em := make([]byte, emLen)
+	db := em[:emLen-hLen-1]
+	h := em[emLen-hLen-1 : emLen-1]
+
+	// H = Hash(0x00 00 00 00 00 00 00 00 || mHash || salt)
+	var prefix [8]byte
+	hash.Write(prefix[:])
+	hash.Write(mHash)
+	hash.Write(salt)
+	h = hash.Sum(h[:0])
+	hash.Reset()
+
+	// DB = PS || 0x01 || salt, masked with MGF1(H)
+	db[emLen-sLen-hLen-2] = 0x01
+	copy(db[emLen-sLen-hLen-1:], salt)
+	mgf1XOR(db, hash, h)
+
+	db[0] &= 0xFF >> uint(8*emLen-emBits)
+	em[emLen-1] = 0xBC
+
+	return em, nil
+}
+
+// mgf1XOR XORs out with the MGF1 mask generated from seed
+func mgf1XOR(out []byte, hash hash.Hash, seed []byte) {
+	var counter [4]byte
+	var digest []byte
+
+	done := 0
+	for i := uint32(0); done < len(out); i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		hash.Write(seed)
+		hash.Write(counter[:])
+		digest = hash.Sum(digest[:0])
+		hash.Reset()
+
+		for j := 0; j < len(digest) && done < len(out); j++ {
+			out[done] ^= digest[j]
+			done++
+		}
+	}
+}
+
+// verifyRSA verifies the signature in software, as the SW BCCSP does, since
+// the public key is known
+func verifyRSA(pub *rsa.PublicKey, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	if opts == nil {
+		return false, errors.New("Invalid options. It must not be nil.")
+	}
+	switch opts.(type) {
+	case *rsa.PSSOptions:
+		err := rsa.VerifyPSS(pub, (opts.(*rsa.PSSOptions)).Hash, digest, signature, opts.(*rsa.PSSOptions))
+
+		return err == nil, err
+	default:
+		return false, fmt.Errorf("Opts type not recognized [%s]", opts)
+	}
+}
+
+// oaepMechanism returns the CKM_RSA_PKCS_OAEP mechanism for opts
+func oaepMechanism(opts *bccsp.RSAOAEPOpts) (*pkcs11.Mechanism, error) {
+	m, ok := rsaHashMechanisms[opts.HashFunc()]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported hash function for OAEP [%s]", opts.HashFunc())
+	}
+
+	return pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS_OAEP,
+		pkcs11.NewOAEPParams(m.hash, m.mgf, pkcs11.CKZ_DATA_SPECIFIED, opts.Label)), nil
+}
+
+// encryptP11RSAOAEP encrypts the plaintext with the public key held by the
+// token
+func (csp *impl) encryptP11RSAOAEP(ski, plaintext []byte, opts *bccsp.RSAOAEPOpts) ([]byte, error) {
+	mech, err := oaepMechanism(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	publicKey, err := findKeyPairFromSKI(p11lib, session, ski, publicKeyFlag)
+	if err != nil {
+		return nil, fmt.Errorf("Public key not found [%s]\n", err)
+	}
+
+	err = p11lib.EncryptInit(session, []*pkcs11.Mechanism{mech}, *publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("Encrypt-initialize failed [%s]\n", err)
+	}
+
+	ciphertext, err := p11lib.Encrypt(session, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("P11: encrypt failed [%s]\n", err)
+	}
+
+	return ciphertext, nil
+}
+
+// decryptP11RSAOAEP decrypts the ciphertext with the private key held by the
+// token
+func (csp *impl) decryptP11RSAOAEP(ski, ciphertext []byte, opts *bccsp.RSAOAEPOpts) ([]byte, error) {
+	mech, err := oaepMechanism(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	privateKey, err := findKeyPairFromSKI(p11lib, session, ski, privateKeyFlag)
+	if err != nil {
+		return nil, fmt.Errorf("Private key not found [%s]\n", err)
+	}
+
+	err = p11lib.DecryptInit(session, []*pkcs11.Mechanism{mech}, *privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("Decrypt-initialize failed [%s]\n", err)
+	}
+
+	plaintext, err := p11lib.Decrypt(session, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("P11: decrypt failed [%s]\n", err)
+	}
+
+	return plaintext, nil
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package bccsp
 
+import "crypto"
+
 // RSA1024KeyGenOpts contains options for RSA key generation at 1024 security.
 type RSA1024KeyGenOpts struct {
 	Temporary bool
@@ -79,3 +81,20 @@ func (opts *RSA4096KeyGenOpts) Algorithm() string {
 func (opts *RSA4096KeyGenOpts) Ephemeral() bool {
 	return opts.Temporary
 }
+
+// RSAOAEPOpts contains options for RSA encryption with OAEP padding.
+type RSAOAEPOpts struct {
+	// Hash is the hash function of the padding and of its mask generation
+	// function. SHA-256 is used when it is not set.
+	Hash crypto.Hash
+	// Label is the label bound to the ciphertext, which can be empty
+	Label []byte
+}
+
+// HashFunc returns the hash function of the OAEP padding.
+func (opts *RSAOAEPOpts) HashFunc() crypto.Hash {
+	if opts.Hash == 0 {
+		return crypto.SHA256
+	}
+	return opts.Hash
+}
@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"golang.org/x/crypto/ed25519"
+)
+
+// signED25519 signs the digest as the message, Ed25519 hashing
+// the message itself
+func signED25519(k ed25519.PrivateKey, digest []byte, opts bccsp.SignerOpts) (signature []byte, err error) {
+	return ed25519.Sign(k, digest), nil
+}
+
+func verifyED25519(k ed25519.PublicKey, signature, digest []byte, opts bccsp.SignerOpts) (valid bool, err error) {
+	if len(signature) != ed25519.SignatureSize {
+		return false, fmt.Errorf("Invalid signature length. Expected [%d], got [%d]", ed25519.SignatureSize, len(signature))
+	}
+
+	return ed25519.Verify(k, digest, signature), nil
+}
+
+type ed25519Signer struct{}
+
+func (s *ed25519Signer) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) (signature []byte, err error) {
+	return signED25519(k.(*ed25519PrivateKey).privKey, digest, opts)
+}
+
+type ed25519PrivateKeyVerifier struct{}
+
+func (v *ed25519PrivateKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (valid bool, err error) {
+	return verifyED25519(k.(*ed25519PrivateKey).privKey.Public().(ed25519.PublicKey), signature, digest, opts)
+}
+
+type ed25519PublicKeyKeyVerifier struct{}
+
+func (v *ed25519PublicKeyKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (valid bool, err error) {
+	return verifyED25519(k.(*ed25519PublicKey).pubKey, signature, digest, opts)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/rand"
+	"encoding/pem"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestSignVerifyED25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	msg := []byte("Hello World")
+	sigma, err := signED25519(priv, msg, nil)
+	assert.NoError(t, err)
+
+	valid, err := verifyED25519(pub, sigma, msg, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = verifyED25519(pub, sigma, []byte("Hello World!"), nil)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	_, err = verifyED25519(pub, sigma[1:], msg, nil)
+	assert.Error(t, err)
+}
+
+func TestED25519KeyGenSignVerify(t *testing.T) {
+	k, err := currentBCCSP.KeyGen(&bccsp.ED25519KeyGenOpts{Temporary: false})
+	assert.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.False(t, k.Symmetric())
+
+	digest, err := currentBCCSP.Hash([]byte("Hello World"), &bccsp.SHAOpts{})
+	assert.NoError(t, err)
+
+	signature, err := currentBCCSP.Sign(k, digest, nil)
+	assert.NoError(t, err)
+
+	valid, err := currentBCCSP.Verify(k, signature, digest, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	pk, err := k.PublicKey()
+	assert.NoError(t, err)
+	valid, err = currentBCCSP.Verify(pk, signature, digest, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// Import the public key back
+	raw, err := pk.Bytes()
+	assert.NoError(t, err)
+	pk2, err := currentBCCSP.KeyImport(raw, &bccsp.ED25519PKIXPublicKeyImportOpts{Temporary: true})
+	assert.NoError(t, err)
+	assert.Equal(t, pk.SKI(), pk2.SKI())
+	valid, err = currentBCCSP.Verify(pk2, signature, digest, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// The keystore returns the same key
+	k2, err := currentBCCSP.GetKey(k.SKI())
+	assert.NoError(t, err)
+	assert.True(t, k2.Private())
+	signature, err = currentBCCSP.Sign(k2, digest, nil)
+	assert.NoError(t, err)
+	valid, err = currentBCCSP.Verify(pk, signature, digest, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestED25519KeyImport(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	pk, err := currentBCCSP.KeyImport(pub, &bccsp.ED25519GoPublicKeyImportOpts{Temporary: true})
+	assert.NoError(t, err)
+	assert.False(t, pk.Private())
+
+	raw, err := utils.PrivateKeyToPEM(priv, nil)
+	assert.NoError(t, err)
+	block, _ := pem.Decode(raw)
+	k, err := currentBCCSP.KeyImport(block.Bytes, &bccsp.ED25519PrivateKeyImportOpts{Temporary: true})
+	assert.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.Equal(t, pk.SKI(), k.SKI())
+
+	_, err = currentBCCSP.KeyImport([]byte{0, 1, 2}, &bccsp.ED25519PKIXPublicKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+	_, err = currentBCCSP.KeyImport("not a key", &bccsp.ED25519GoPublicKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+}
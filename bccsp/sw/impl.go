@@ -25,6 +25,7 @@ import (
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/errors"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/tjfoc/gmsm/sm3"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -67,15 +68,22 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 	// Set the encryptors
 	encryptors := make(map[reflect.Type]Encryptor)
 	encryptors[reflect.TypeOf(&aesPrivateKey{})] = &aescbcpkcs7Encryptor{}
+	encryptors[reflect.TypeOf(&rsaPublicKey{})] = &rsaOAEPEncryptor{}
+	encryptors[reflect.TypeOf(&rsaPrivateKey{})] = &rsaOAEPEncryptor{}
+	encryptors[reflect.TypeOf(&sm4PrivateKey{})] = &sm4cbcpkcs7Encryptor{}
 
 	// Set the decryptors
 	decryptors := make(map[reflect.Type]Decryptor)
 	decryptors[reflect.TypeOf(&aesPrivateKey{})] = &aescbcpkcs7Decryptor{}
+	decryptors[reflect.TypeOf(&rsaPrivateKey{})] = &rsaOAEPDecryptor{}
+	decryptors[reflect.TypeOf(&sm4PrivateKey{})] = &sm4cbcpkcs7Decryptor{}
 
 	// Set the signers
 	signers := make(map[reflect.Type]Signer)
 	signers[reflect.TypeOf(&ecdsaPrivateKey{})] = &ecdsaSigner{}
 	signers[reflect.TypeOf(&rsaPrivateKey{})] = &rsaSigner{}
+	signers[reflect.TypeOf(&ed25519PrivateKey{})] = &ed25519Signer{}
+	signers[reflect.TypeOf(&sm2PrivateKey{})] = &sm2Signer{}
 
 	// Set the verifiers
 	verifiers := make(map[reflect.Type]Verifier)
@@ -83,6 +91,10 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 	verifiers[reflect.TypeOf(&ecdsaPublicKey{})] = &ecdsaPublicKeyKeyVerifier{}
 	verifiers[reflect.TypeOf(&rsaPrivateKey{})] = &rsaPrivateKeyVerifier{}
 	verifiers[reflect.TypeOf(&rsaPublicKey{})] = &rsaPublicKeyKeyVerifier{}
+	verifiers[reflect.TypeOf(&ed25519PrivateKey{})] = &ed25519PrivateKeyVerifier{}
+	verifiers[reflect.TypeOf(&ed25519PublicKey{})] = &ed25519PublicKeyKeyVerifier{}
+	verifiers[reflect.TypeOf(&sm2PrivateKey{})] = &sm2PrivateKeyVerifier{}
+	verifiers[reflect.TypeOf(&sm2PublicKey{})] = &sm2PublicKeyKeyVerifier{}
 
 	// Set the hashers
 	hashers := make(map[reflect.Type]Hasher)
@@ -91,6 +103,7 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 	hashers[reflect.TypeOf(&bccsp.SHA384Opts{})] = &hasher{hash: sha512.New384}
 	hashers[reflect.TypeOf(&bccsp.SHA3_256Opts{})] = &hasher{hash: sha3.New256}
 	hashers[reflect.TypeOf(&bccsp.SHA3_384Opts{})] = &hasher{hash: sha3.New384}
+	hashers[reflect.TypeOf(&bccsp.SM3Opts{})] = &hasher{hash: sm3.New}
 
 	impl := &impl{
 		conf:       conf,
@@ -115,6 +128,9 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 	keyGenerators[reflect.TypeOf(&bccsp.RSA2048KeyGenOpts{})] = &rsaKeyGenerator{length: 2048}
 	keyGenerators[reflect.TypeOf(&bccsp.RSA3072KeyGenOpts{})] = &rsaKeyGenerator{length: 3072}
 	keyGenerators[reflect.TypeOf(&bccsp.RSA4096KeyGenOpts{})] = &rsaKeyGenerator{length: 4096}
+	keyGenerators[reflect.TypeOf(&bccsp.ED25519KeyGenOpts{})] = &ed25519KeyGenerator{}
+	keyGenerators[reflect.TypeOf(&bccsp.SM2KeyGenOpts{})] = &sm2KeyGenerator{}
+	keyGenerators[reflect.TypeOf(&bccsp.SM4KeyGenOpts{})] = &sm4KeyGenerator{}
 	impl.keyGenerators = keyGenerators
 
 	// Set the key generators
@@ -132,6 +148,13 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 	keyImporters[reflect.TypeOf(&bccsp.ECDSAPrivateKeyImportOpts{})] = &ecdsaPrivateKeyImportOptsKeyImporter{}
 	keyImporters[reflect.TypeOf(&bccsp.ECDSAGoPublicKeyImportOpts{})] = &ecdsaGoPublicKeyImportOptsKeyImporter{}
 	keyImporters[reflect.TypeOf(&bccsp.RSAGoPublicKeyImportOpts{})] = &rsaGoPublicKeyImportOptsKeyImporter{}
+	keyImporters[reflect.TypeOf(&bccsp.ED25519PKIXPublicKeyImportOpts{})] = &ed25519PKIXPublicKeyImportOptsKeyImporter{}
+	keyImporters[reflect.TypeOf(&bccsp.ED25519PrivateKeyImportOpts{})] = &ed25519PrivateKeyImportOptsKeyImporter{}
+	keyImporters[reflect.TypeOf(&bccsp.ED25519GoPublicKeyImportOpts{})] = &ed25519GoPublicKeyImportOptsKeyImporter{}
+	keyImporters[reflect.TypeOf(&bccsp.SM2PKIXPublicKeyImportOpts{})] = &sm2PKIXPublicKeyImportOptsKeyImporter{}
+	keyImporters[reflect.TypeOf(&bccsp.SM2PrivateKeyImportOpts{})] = &sm2PrivateKeyImportOptsKeyImporter{}
+	keyImporters[reflect.TypeOf(&bccsp.SM2GoPublicKeyImportOpts{})] = &sm2GoPublicKeyImportOptsKeyImporter{}
+	keyImporters[reflect.TypeOf(&bccsp.SM4ImportKeyOpts{})] = &sm4ImportKeyOptsKeyImporter{}
 	keyImporters[reflect.TypeOf(&bccsp.X509PublicKeyImportOpts{})] = &x509PublicKeyImportOptsKeyImporter{bccsp: impl}
 
 	impl.keyImporters = keyImporters
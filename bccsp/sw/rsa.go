@@ -70,3 +70,43 @@ func (v *rsaPublicKeyKeyVerifier) Verify(k bccsp.Key, signature, digest []byte,
 		return false, fmt.Errorf("Opts type not recognized [%s]", opts)
 	}
 }
+
+type rsaOAEPEncryptor struct{}
+
+func (*rsaOAEPEncryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) (ciphertext []byte, err error) {
+	var pub *rsa.PublicKey
+	switch k.(type) {
+	case *rsaPublicKey:
+		pub = k.(*rsaPublicKey).pubKey
+	case *rsaPrivateKey:
+		pub = &k.(*rsaPrivateKey).privKey.PublicKey
+	}
+
+	switch opts.(type) {
+	case *bccsp.RSAOAEPOpts:
+		oaepOpts := opts.(*bccsp.RSAOAEPOpts)
+		hash := oaepOpts.HashFunc()
+		if !hash.Available() {
+			return nil, fmt.Errorf("Hash function not available [%s]", hash)
+		}
+		return rsa.EncryptOAEP(hash.New(), rand.Reader, pub, plaintext, oaepOpts.Label)
+	default:
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+}
+
+type rsaOAEPDecryptor struct{}
+
+func (*rsaOAEPDecryptor) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) (plaintext []byte, err error) {
+	switch opts.(type) {
+	case *bccsp.RSAOAEPOpts:
+		oaepOpts := opts.(*bccsp.RSAOAEPOpts)
+		hash := oaepOpts.HashFunc()
+		if !hash.Available() {
+			return nil, fmt.Errorf("Hash function not available [%s]", hash)
+		}
+		return rsa.DecryptOAEP(hash.New(), rand.Reader, k.(*rsaPrivateKey).privKey, ciphertext, oaepOpts.Label)
+	default:
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+}
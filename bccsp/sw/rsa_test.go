@@ -26,6 +26,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/mocks"
 	"github.com/stretchr/testify/assert"
 )
@@ -166,3 +167,41 @@ func TestRSAVerifiersInvalidInputs(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, strings.Contains(err.Error(), "Opts type not recognized ["))
 }
+
+func TestRSAOAEPEncryptDecrypt(t *testing.T) {
+	lowLevelKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+	k := &rsaPrivateKey{lowLevelKey}
+	pk := &rsaPublicKey{&lowLevelKey.PublicKey}
+	msg := []byte("Hello World")
+
+	encryptor := &rsaOAEPEncryptor{}
+	decryptor := &rsaOAEPDecryptor{}
+
+	ct, err := encryptor.Encrypt(pk, msg, &bccsp.RSAOAEPOpts{})
+	assert.NoError(t, err)
+	pt, err := decryptor.Decrypt(k, ct, &bccsp.RSAOAEPOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, msg, pt)
+
+	// SHA-256 is the default hash function
+	pt, err = rsa.DecryptOAEP(sha256.New(), rand.Reader, lowLevelKey, ct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, pt)
+
+	ct, err = encryptor.Encrypt(k, msg, &bccsp.RSAOAEPOpts{Hash: crypto.SHA1, Label: []byte("label")})
+	assert.NoError(t, err)
+	_, err = decryptor.Decrypt(k, ct, &bccsp.RSAOAEPOpts{Hash: crypto.SHA1})
+	assert.Error(t, err)
+	pt, err = decryptor.Decrypt(k, ct, &bccsp.RSAOAEPOpts{Hash: crypto.SHA1, Label: []byte("label")})
+	assert.NoError(t, err)
+	assert.Equal(t, msg, pt)
+
+	_, err = encryptor.Encrypt(pk, msg, &mocks.EncrypterOpts{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Mode not recognized")
+
+	_, err = decryptor.Decrypt(k, ct, &mocks.DecrypterOpts{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Mode not recognized")
+}
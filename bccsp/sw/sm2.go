@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+// signSM2 signs the digest as the message of GB/T 32918, that is
+// SM3(Z||digest) with Z derived from the default user ID and the
+// public key. X.509 certificates signed with SM2 are verified the same way.
+func signSM2(k *sm2.PrivateKey, digest []byte, opts bccsp.SignerOpts) (signature []byte, err error) {
+	r, s, err := sm2.Sm2Sign(k, digest, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return sm2.SignDigitToSignData(r, s)
+}
+
+func verifySM2(k *sm2.PublicKey, signature, digest []byte, opts bccsp.SignerOpts) (valid bool, err error) {
+	r, s, err := UnmarshalECDSASignature(signature)
+	if err != nil {
+		return false, fmt.Errorf("Failed unmashalling signature [%s]", err)
+	}
+
+	return sm2.Sm2Verify(k, digest, nil, r, s), nil
+}
+
+type sm2Signer struct{}
+
+func (s *sm2Signer) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) (signature []byte, err error) {
+	return signSM2(k.(*sm2PrivateKey).privKey, digest, opts)
+}
+
+type sm2PrivateKeyVerifier struct{}
+
+func (v *sm2PrivateKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (valid bool, err error) {
+	return verifySM2(&(k.(*sm2PrivateKey).privKey.PublicKey), signature, digest, opts)
+}
+
+type sm2PublicKeyKeyVerifier struct{}
+
+func (v *sm2PublicKeyKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (valid bool, err error) {
+	return verifySM2(k.(*sm2PublicKey).pubKey, signature, digest, opts)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+func TestSignVerifySM2(t *testing.T) {
+	lowLevelKey, err := sm2.GenerateKey()
+	assert.NoError(t, err)
+
+	msg := []byte("Hello World")
+	sigma, err := signSM2(lowLevelKey, msg, nil)
+	assert.NoError(t, err)
+
+	valid, err := verifySM2(&lowLevelKey.PublicKey, sigma, msg, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = verifySM2(&lowLevelKey.PublicKey, sigma, []byte("Hello World!"), nil)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	_, err = verifySM2(&lowLevelKey.PublicKey, []byte{0, 1, 2}, msg, nil)
+	assert.Error(t, err)
+}
+
+func TestSM2KeyGenSignVerify(t *testing.T) {
+	k, err := currentBCCSP.KeyGen(&bccsp.SM2KeyGenOpts{Temporary: false})
+	assert.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.False(t, k.Symmetric())
+
+	digest, err := currentBCCSP.Hash([]byte("Hello World"), &bccsp.SM3Opts{})
+	assert.NoError(t, err)
+	assert.Len(t, digest, 32)
+
+	signature, err := currentBCCSP.Sign(k, digest, nil)
+	assert.NoError(t, err)
+
+	valid, err := currentBCCSP.Verify(k, signature, digest, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	pk, err := k.PublicKey()
+	assert.NoError(t, err)
+	raw, err := pk.Bytes()
+	assert.NoError(t, err)
+	pk2, err := currentBCCSP.KeyImport(raw, &bccsp.SM2PKIXPublicKeyImportOpts{Temporary: true})
+	assert.NoError(t, err)
+	assert.Equal(t, pk.SKI(), pk2.SKI())
+	valid, err = currentBCCSP.Verify(pk2, signature, digest, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// The keystore returns the same key
+	k2, err := currentBCCSP.GetKey(k.SKI())
+	assert.NoError(t, err)
+	assert.True(t, k2.Private())
+	signature, err = currentBCCSP.Sign(k2, digest, nil)
+	assert.NoError(t, err)
+	valid, err = currentBCCSP.Verify(pk, signature, digest, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestSM2KeyImport(t *testing.T) {
+	lowLevelKey, err := sm2.GenerateKey()
+	assert.NoError(t, err)
+
+	pk, err := currentBCCSP.KeyImport(&lowLevelKey.PublicKey, &bccsp.SM2GoPublicKeyImportOpts{Temporary: true})
+	assert.NoError(t, err)
+	assert.False(t, pk.Private())
+
+	raw, err := sm2.MarshalSm2UnecryptedPrivateKey(lowLevelKey)
+	assert.NoError(t, err)
+	k, err := currentBCCSP.KeyImport(raw, &bccsp.SM2PrivateKeyImportOpts{Temporary: true})
+	assert.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.Equal(t, pk.SKI(), k.SKI())
+
+	_, err = currentBCCSP.KeyImport([]byte{0, 1, 2}, &bccsp.SM2PKIXPublicKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+	_, err = currentBCCSP.KeyImport("not a key", &bccsp.SM2GoPublicKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+}
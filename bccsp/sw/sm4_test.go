@@ -0,0 +1,77 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSM4CBCPKCS7EncryptDecrypt(t *testing.T) {
+	key := make([]byte, 16)
+	rand.Reader.Read(key)
+
+	ptext := []byte("a message with arbitrary length (42 bytes)")
+	encrypted, err := SM4CBCPKCS7Encrypt(key, ptext)
+	assert.NoError(t, err)
+	decrypted, err := SM4CBCPKCS7Decrypt(key, encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, ptext, decrypted)
+
+	_, err = SM4CBCPKCS7Decrypt(key, encrypted[:16])
+	assert.Error(t, err)
+	_, err = SM4CBCPKCS7Decrypt(key, encrypted[:len(encrypted)-1])
+	assert.Error(t, err)
+	_, err = SM4CBCPKCS7Encrypt(key[:15], ptext)
+	assert.Error(t, err)
+}
+
+func TestSM4KeyGenEncryptDecrypt(t *testing.T) {
+	k, err := currentBCCSP.KeyGen(&bccsp.SM4KeyGenOpts{Temporary: false})
+	assert.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.True(t, k.Symmetric())
+
+	ptext := []byte("Hello World")
+	ct, err := currentBCCSP.Encrypt(k, ptext, &bccsp.SM4CBCPKCS7ModeOpts{})
+	assert.NoError(t, err)
+	pt, err := currentBCCSP.Decrypt(k, ct, bccsp.SM4CBCPKCS7ModeOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, ptext, pt)
+
+	// The keystore returns the same key
+	k2, err := currentBCCSP.GetKey(k.SKI())
+	assert.NoError(t, err)
+	assert.True(t, k2.Symmetric())
+	pt, err = currentBCCSP.Decrypt(k2, ct, &bccsp.SM4CBCPKCS7ModeOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, ptext, pt)
+
+	_, err = currentBCCSP.Encrypt(k, ptext, &bccsp.AESCBCPKCS7ModeOpts{})
+	assert.Error(t, err)
+}
+
+func TestSM4KeyImport(t *testing.T) {
+	raw := make([]byte, 16)
+	rand.Reader.Read(raw)
+
+	k, err := currentBCCSP.KeyImport(raw, &bccsp.SM4ImportKeyOpts{Temporary: true})
+	assert.NoError(t, err)
+	assert.True(t, k.Symmetric())
+
+	ct, err := currentBCCSP.Encrypt(k, []byte("Hello World"), &bccsp.SM4CBCPKCS7ModeOpts{})
+	assert.NoError(t, err)
+	pt, err := SM4CBCPKCS7Decrypt(raw, ct)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("Hello World"), pt)
+
+	_, err = currentBCCSP.KeyImport(raw[:8], &bccsp.SM4ImportKeyOpts{Temporary: true})
+	assert.Error(t, err)
+}
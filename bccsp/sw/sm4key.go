@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/hyperledger/fabric/bccsp"
+)
+
+type sm4PrivateKey struct {
+	privKey    []byte
+	exportable bool
+}
+
+// Bytes converts this key to its byte representation,
+// if this operation is allowed.
+func (k *sm4PrivateKey) Bytes() (raw []byte, err error) {
+	if k.exportable {
+		return k.privKey, nil
+	}
+
+	return nil, errors.New("Not supported.")
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *sm4PrivateKey) SKI() (ski []byte) {
+	hash := sha256.New()
+	hash.Write([]byte{0x01})
+	hash.Write(k.privKey)
+	return hash.Sum(nil)
+}
+
+// Symmetric returns true if this key is a symmetric key,
+// false if this key is asymmetric
+func (k *sm4PrivateKey) Symmetric() bool {
+	return true
+}
+
+// Private returns true if this key is a private key,
+// false otherwise.
+func (k *sm4PrivateKey) Private() bool {
+	return true
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+// This method returns an error in symmetric key schemes.
+func (k *sm4PrivateKey) PublicKey() (bccsp.Key, error) {
+	return nil, errors.New("Cannot call this method on a symmetric key.")
+}
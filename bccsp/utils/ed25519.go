@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package utils
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+// Ed25519 keys are marshalled as specified by RFC 8410, without relying on
+// the support of the standard library which is only available since Go 1.13
+
+var oidPublicKeyEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+// struct to hold info required for PKIX
+type publicKeyInfo struct {
+	Algorithm []asn1.ObjectIdentifier
+	PublicKey asn1.BitString
+}
+
+// ed25519ToPKCS8 marshals an Ed25519 private key to PKCS#8
+func ed25519ToPKCS8(k ed25519.PrivateKey) ([]byte, error) {
+	if len(k) != ed25519.PrivateKeySize {
+		return nil, errors.New("Invalid ed25519 private key. It must be different from nil.")
+	}
+
+	seed, err := asn1.Marshal(k.Seed())
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling ed25519 key to asn1 [%s]", err)
+	}
+
+	pkcs8Bytes, err := asn1.Marshal(pkcs8Info{
+		Version:             0,
+		PrivateKeyAlgorithm: []asn1.ObjectIdentifier{oidPublicKeyEd25519},
+		PrivateKey:          seed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling ed25519 key to asn1 [%s]", err)
+	}
+	return pkcs8Bytes, nil
+}
+
+// pkcs8ToEd25519 unmarshals an Ed25519 private key from PKCS#8
+func pkcs8ToEd25519(der []byte) (ed25519.PrivateKey, error) {
+	var pkcs8Key pkcs8Info
+	if rest, err := asn1.Unmarshal(der, &pkcs8Key); err != nil {
+		return nil, err
+	} else if len(rest) != 0 {
+		return nil, errors.New("trailing data after PKCS#8 private key")
+	}
+	if len(pkcs8Key.PrivateKeyAlgorithm) != 1 || !pkcs8Key.PrivateKeyAlgorithm[0].Equal(oidPublicKeyEd25519) {
+		return nil, errors.New("not an ed25519 private key")
+	}
+
+	var seed []byte
+	if _, err := asn1.Unmarshal(pkcs8Key.PrivateKey, &seed); err != nil {
+		return nil, fmt.Errorf("invalid ed25519 private key [%s]", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid ed25519 private key length [%d]", len(seed))
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// ed25519ToPKIX marshals an Ed25519 public key to PKIX
+func ed25519ToPKIX(k ed25519.PublicKey) ([]byte, error) {
+	if len(k) != ed25519.PublicKeySize {
+		return nil, errors.New("Invalid ed25519 public key. It must be different from nil.")
+	}
+
+	return asn1.Marshal(publicKeyInfo{
+		Algorithm: []asn1.ObjectIdentifier{oidPublicKeyEd25519},
+		PublicKey: asn1.BitString{Bytes: k, BitLength: 8 * len(k)},
+	})
+}
+
+// pkixToEd25519 unmarshals an Ed25519 public key from PKIX
+func pkixToEd25519(der []byte) (ed25519.PublicKey, error) {
+	var pki publicKeyInfo
+	if rest, err := asn1.Unmarshal(der, &pki); err != nil {
+		return nil, err
+	} else if len(rest) != 0 {
+		return nil, errors.New("trailing data after PKIX public key")
+	}
+	if len(pki.Algorithm) != 1 || !pki.Algorithm[0].Equal(oidPublicKeyEd25519) {
+		return nil, errors.New("not an ed25519 public key")
+	}
+
+	raw := pki.PublicKey.RightAlign()
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid ed25519 public key length [%d]", len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
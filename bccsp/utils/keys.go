@@ -26,6 +26,9 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+
+	"github.com/tjfoc/gmsm/sm2"
+	"golang.org/x/crypto/ed25519"
 )
 
 // struct to hold info required for PKCS#8
@@ -75,7 +78,7 @@ func PrivateKeyToDER(privateKey *ecdsa.PrivateKey) ([]byte, error) {
 }
 
 // PrivateKeyToPEM converts the private key to PEM format.
-// EC private keys are converted to PKCS#8 format.
+// EC, Ed25519 and SM2 private keys are converted to PKCS#8 format.
 // RSA private keys are converted to PKCS#1 format.
 func PrivateKeyToPEM(privateKey interface{}, pwd []byte) ([]byte, error) {
 	// Validate inputs
@@ -142,8 +145,35 @@ func PrivateKeyToPEM(privateKey interface{}, pwd []byte) ([]byte, error) {
 				Bytes: raw,
 			},
 		), nil
+	case ed25519.PrivateKey, *sm2.PrivateKey:
+		raw, err := privateKeyToPKCS8(k)
+		if err != nil {
+			return nil, err
+		}
+
+		return pem.EncodeToMemory(
+			&pem.Block{
+				Type:  "PRIVATE KEY",
+				Bytes: raw,
+			},
+		), nil
 	default:
-		return nil, errors.New("Invalid key type. It must be *ecdsa.PrivateKey or *rsa.PrivateKey")
+		return nil, errors.New("Invalid key type. It must be *ecdsa.PrivateKey, *rsa.PrivateKey, ed25519.PrivateKey or *sm2.PrivateKey")
+	}
+}
+
+// privateKeyToPKCS8 marshals an Ed25519 or SM2 private key to PKCS#8
+func privateKeyToPKCS8(privateKey interface{}) ([]byte, error) {
+	switch k := privateKey.(type) {
+	case ed25519.PrivateKey:
+		return ed25519ToPKCS8(k)
+	case *sm2.PrivateKey:
+		if k == nil {
+			return nil, errors.New("Invalid sm2 private key. It must be different from nil.")
+		}
+		return sm2.MarshalSm2UnecryptedPrivateKey(k)
+	default:
+		return nil, errors.New("Invalid key type. It must be ed25519.PrivateKey or *sm2.PrivateKey")
 	}
 }
 
@@ -177,8 +207,27 @@ func PrivateKeyToEncryptedPEM(privateKey interface{}, pwd []byte) ([]byte, error
 
 		return pem.EncodeToMemory(block), nil
 
+	case ed25519.PrivateKey, *sm2.PrivateKey:
+		raw, err := privateKeyToPKCS8(k)
+		if err != nil {
+			return nil, err
+		}
+
+		block, err := x509.EncryptPEMBlock(
+			rand.Reader,
+			"PRIVATE KEY",
+			raw,
+			pwd,
+			x509.PEMCipherAES256)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return pem.EncodeToMemory(block), nil
+
 	default:
-		return nil, errors.New("Invalid key type. It must be *ecdsa.PrivateKey")
+		return nil, errors.New("Invalid key type. It must be *ecdsa.PrivateKey, ed25519.PrivateKey or *sm2.PrivateKey")
 	}
 }
 
@@ -189,6 +238,10 @@ func DERToPrivateKey(der []byte) (key interface{}, err error) {
 		return key, nil
 	}
 
+	if key, err = pkcs8ToEd25519(der); err == nil {
+		return
+	}
+
 	if key, err = x509.ParsePKCS8PrivateKey(der); err == nil {
 		switch key.(type) {
 		case *rsa.PrivateKey, *ecdsa.PrivateKey:
@@ -202,7 +255,13 @@ func DERToPrivateKey(der []byte) (key interface{}, err error) {
 		return
 	}
 
-	return nil, errors.New("Invalid key type. The DER must contain an rsa.PrivareKey or ecdsa.PrivateKey")
+	// SM2 keys are wrapped in PKCS#8 with the SM2 algorithm identifier,
+	// which the standard library does not recognize
+	if key, err = sm2.ParsePKCS8UnecryptedPrivateKey(der); err == nil {
+		return
+	}
+
+	return nil, errors.New("Invalid key type. The DER must contain an rsa.PrivareKey, ecdsa.PrivateKey, ed25519.PrivateKey or sm2.PrivateKey")
 }
 
 // PEMtoPrivateKey unmarshals a pem to private key
@@ -294,6 +353,49 @@ func AEStoEncryptedPEM(raw []byte, pwd []byte) ([]byte, error) {
 	return pem.EncodeToMemory(block), nil
 }
 
+// SM4toPEM encapsulates an SM4 key in the PEM format
+func SM4toPEM(raw []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "SM4 PRIVATE KEY", Bytes: raw})
+}
+
+// SM4toEncryptedPEM encapsulates an SM4 key in the encrypted PEM format
+func SM4toEncryptedPEM(raw []byte, pwd []byte) ([]byte, error) {
+	if len(raw) == 0 {
+		return nil, errors.New("Invalid sm4 key. It must be different from nil")
+	}
+	if len(pwd) == 0 {
+		return SM4toPEM(raw), nil
+	}
+
+	block, err := x509.EncryptPEMBlock(
+		rand.Reader,
+		"SM4 PRIVATE KEY",
+		raw,
+		pwd,
+		x509.PEMCipherAES256)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(block), nil
+}
+
+// PEMtoSM4 extracts from the PEM an SM4 key
+func PEMtoSM4(raw []byte, pwd []byte) ([]byte, error) {
+	if !IsSM4PEM(raw) {
+		return nil, errors.New("Invalid PEM. It must contain an SM4 key.")
+	}
+
+	return PEMtoAES(raw, pwd)
+}
+
+// IsSM4PEM returns true if the PEM holds an SM4 key
+func IsSM4PEM(raw []byte) bool {
+	block, _ := pem.Decode(raw)
+	return block != nil && block.Type == "SM4 PRIVATE KEY"
+}
+
 // PublicKeyToPEM marshals a public key to the pem format
 func PublicKeyToPEM(publicKey interface{}, pwd []byte) ([]byte, error) {
 	if len(pwd) != 0 {
@@ -335,9 +437,21 @@ func PublicKeyToPEM(publicKey interface{}, pwd []byte) ([]byte, error) {
 				Bytes: PubASN1,
 			},
 		), nil
+	case ed25519.PublicKey, *sm2.PublicKey:
+		PubASN1, err := PublicKeyToDER(k)
+		if err != nil {
+			return nil, err
+		}
+
+		return pem.EncodeToMemory(
+			&pem.Block{
+				Type:  "PUBLIC KEY",
+				Bytes: PubASN1,
+			},
+		), nil
 
 	default:
-		return nil, errors.New("Invalid key type. It must be *ecdsa.PublicKey or *rsa.PublicKey")
+		return nil, errors.New("Invalid key type. It must be *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey or *sm2.PublicKey")
 	}
 }
 
@@ -370,8 +484,17 @@ func PublicKeyToDER(publicKey interface{}) ([]byte, error) {
 
 		return PubASN1, nil
 
+	case ed25519.PublicKey:
+		return ed25519ToPKIX(k)
+
+	case *sm2.PublicKey:
+		if k == nil {
+			return nil, errors.New("Invalid sm2 public key. It must be different from nil.")
+		}
+		return sm2.MarshalSm2PublicKey(k)
+
 	default:
-		return nil, errors.New("Invalid key type. It must be *ecdsa.PublicKey or *rsa.PublicKey")
+		return nil, errors.New("Invalid key type. It must be *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey or *sm2.PublicKey")
 	}
 }
 
@@ -407,8 +530,27 @@ func PublicKeyToEncryptedPEM(publicKey interface{}, pwd []byte) ([]byte, error)
 
 		return pem.EncodeToMemory(block), nil
 
+	case ed25519.PublicKey, *sm2.PublicKey:
+		raw, err := PublicKeyToDER(k)
+		if err != nil {
+			return nil, err
+		}
+
+		block, err := x509.EncryptPEMBlock(
+			rand.Reader,
+			"PUBLIC KEY",
+			raw,
+			pwd,
+			x509.PEMCipherAES256)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return pem.EncodeToMemory(block), nil
+
 	default:
-		return nil, errors.New("Invalid key type. It must be *ecdsa.PublicKey")
+		return nil, errors.New("Invalid key type. It must be *ecdsa.PublicKey, ed25519.PublicKey or *sm2.PublicKey")
 	}
 }
 
@@ -453,7 +595,25 @@ func DERToPublicKey(raw []byte) (pub interface{}, err error) {
 		return nil, errors.New("Invalid DER. It must be different from nil.")
 	}
 
+	if ed25519Key, ed25519Err := pkixToEd25519(raw); ed25519Err == nil {
+		return ed25519Key, nil
+	}
+
 	key, err := x509.ParsePKIXPublicKey(raw)
+	if err == nil {
+		return key, nil
+	}
+
+	// SM2 public keys come either with the SM2 algorithm identifier or,
+	// as in certificates, with the ECDSA one over the SM2 curve
+	if sm2Key, sm2Err := sm2.ParseSm2PublicKey(raw); sm2Err == nil {
+		return sm2Key, nil
+	}
+	if sm2Key, sm2Err := sm2.ParsePKIXPublicKey(raw); sm2Err == nil {
+		if ecKey, ok := sm2Key.(*ecdsa.PublicKey); ok && ecKey.Curve == sm2.P256Sm2() {
+			return &sm2.PublicKey{Curve: ecKey.Curve, X: ecKey.X, Y: ecKey.Y}, nil
+		}
+	}
 
-	return key, err
+	return nil, err
 }
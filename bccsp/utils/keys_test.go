@@ -27,6 +27,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tjfoc/gmsm/sm2"
+	"golang.org/x/crypto/ed25519"
 )
 
 func TestOidFromNamedCurve(t *testing.T) {
@@ -344,6 +346,105 @@ func TestAESKey(t *testing.T) {
 	assert.Equal(t, k, k2)
 }
 
+func TestSM4Key(t *testing.T) {
+	k := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	pem := SM4toPEM(k)
+	assert.True(t, IsSM4PEM(pem))
+	assert.False(t, IsSM4PEM(AEStoPEM(k)))
+
+	k2, err := PEMtoSM4(pem, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, k, k2)
+
+	pem, err = SM4toEncryptedPEM(k, k)
+	assert.NoError(t, err)
+	assert.True(t, IsSM4PEM(pem))
+
+	k2, err = PEMtoSM4(pem, k)
+	assert.NoError(t, err)
+	assert.Equal(t, k, k2)
+
+	_, err = PEMtoSM4(pem, nil)
+	assert.Error(t, err)
+}
+
+func TestED25519Keys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	for _, pwd := range [][]byte{nil, []byte("password")} {
+		pemKey, err := PrivateKeyToPEM(priv, pwd)
+		assert.NoError(t, err)
+		key, err := PEMtoPrivateKey(pemKey, pwd)
+		assert.NoError(t, err)
+		assert.Equal(t, priv, key)
+
+		pemKey, err = PublicKeyToPEM(pub, pwd)
+		assert.NoError(t, err)
+		key, err = PEMtoPublicKey(pemKey, pwd)
+		assert.NoError(t, err)
+		assert.Equal(t, pub, key)
+	}
+
+	der, err := PublicKeyToDER(pub)
+	assert.NoError(t, err)
+	key, err := DERToPublicKey(der)
+	assert.NoError(t, err)
+	assert.Equal(t, pub, key)
+
+	// the keys are marshalled as specified by RFC 8410
+	expected := append([]byte{0x30, 0x2a, 0x30, 0x05, 0x06, 0x03, 0x2b, 0x65, 0x70, 0x03, 0x21, 0x00}, pub...)
+	assert.Equal(t, expected, der)
+	der, err = privateKeyToPKCS8(priv)
+	assert.NoError(t, err)
+	expected = append([]byte{0x30, 0x2e, 0x02, 0x01, 0x00, 0x30, 0x05, 0x06, 0x03, 0x2b, 0x65, 0x70, 0x04, 0x22, 0x04, 0x20}, priv.Seed()...)
+	assert.Equal(t, expected, der)
+	key, err = DERToPrivateKey(der)
+	assert.NoError(t, err)
+	assert.Equal(t, priv, key)
+
+	_, err = PublicKeyToDER(ed25519.PublicKey{0, 1, 2})
+	assert.Error(t, err)
+	_, err = privateKeyToPKCS8(ed25519.PrivateKey{0, 1, 2})
+	assert.Error(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err = PublicKeyToDER(&ecKey.PublicKey)
+	assert.NoError(t, err)
+	_, err = pkixToEd25519(der)
+	assert.Error(t, err)
+}
+
+func TestSM2Keys(t *testing.T) {
+	priv, err := sm2.GenerateKey()
+	assert.NoError(t, err)
+
+	for _, pwd := range [][]byte{nil, []byte("password")} {
+		pemKey, err := PrivateKeyToPEM(priv, pwd)
+		assert.NoError(t, err)
+		key, err := PEMtoPrivateKey(pemKey, pwd)
+		assert.NoError(t, err)
+		sm2Key, ok := key.(*sm2.PrivateKey)
+		assert.True(t, ok)
+		assert.Equal(t, priv.D, sm2Key.D)
+
+		pemKey, err = PublicKeyToPEM(&priv.PublicKey, pwd)
+		assert.NoError(t, err)
+		key, err = PEMtoPublicKey(pemKey, pwd)
+		assert.NoError(t, err)
+		sm2PubKey, ok := key.(*sm2.PublicKey)
+		assert.True(t, ok)
+		assert.Equal(t, priv.X, sm2PubKey.X)
+		assert.Equal(t, priv.Y, sm2PubKey.Y)
+	}
+
+	der, err := PublicKeyToDER(&priv.PublicKey)
+	assert.NoError(t, err)
+	key, err := DERToPublicKey(der)
+	assert.NoError(t, err)
+	assert.IsType(t, &sm2.PublicKey{}, key)
+}
+
 func TestDERToPublicKey(t *testing.T) {
 	_, err := DERToPublicKey(nil)
 	assert.Error(t, err)
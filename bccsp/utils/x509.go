@@ -17,10 +17,243 @@ limitations under the License.
 package utils
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+var (
+	oidSignatureSM2WithSM3    = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 501}
+	oidSignatureSM2WithSHA1   = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 502}
+	oidSignatureSM2WithSHA256 = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 503}
 )
 
-// DERToX509Certificate converts der to x509
+type signedCertificate struct {
+	TBSCertificate     asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+// DERToX509Certificate converts der to x509.
+// Certificates with SM2 public keys, which the standard library cannot parse,
+// are parsed as SM2 certificates and their public key is an *sm2.PublicKey.
 func DERToX509Certificate(asn1Data []byte) (*x509.Certificate, error) {
-	return x509.ParseCertificate(asn1Data)
+	cert, err := x509.ParseCertificate(asn1Data)
+	if err == nil {
+		return cert, nil
+	}
+
+	sm2Cert, sm2Err := sm2.ParseCertificate(asn1Data)
+	if sm2Err != nil {
+		return nil, err
+	}
+	if ecKey, ok := sm2Cert.PublicKey.(*ecdsa.PublicKey); !ok || ecKey.Curve != sm2.P256Sm2() {
+		return nil, err
+	}
+
+	return sm2ToX509Certificate(sm2Cert), nil
+}
+
+// IsSM2SignedCertificate returns true if the certificate is signed with SM2
+func IsSM2SignedCertificate(cert *x509.Certificate) bool {
+	var c signedCertificate
+	if _, err := asn1.Unmarshal(cert.Raw, &c); err != nil {
+		return false
+	}
+
+	algo := c.SignatureAlgorithm.Algorithm
+	return algo.Equal(oidSignatureSM2WithSM3) || algo.Equal(oidSignatureSM2WithSHA1) || algo.Equal(oidSignatureSM2WithSHA256)
+}
+
+// IsSM2Certificate returns true if the certificate holds an SM2 public key
+// or is signed with SM2
+func IsSM2Certificate(cert *x509.Certificate) bool {
+	if _, ok := cert.PublicKey.(*sm2.PublicKey); ok {
+		return true
+	}
+	return IsSM2SignedCertificate(cert)
+}
+
+// SM2CertPool is a set of certificates to verify SM2 signed certificates
+// against, as the standard library cannot check SM2 signatures
+type SM2CertPool struct {
+	pool *sm2.CertPool
+}
+
+// NewSM2CertPool returns a new, empty SM2CertPool
+func NewSM2CertPool() *SM2CertPool {
+	return &SM2CertPool{pool: sm2.NewCertPool()}
+}
+
+// AddCert adds a certificate to the pool
+func (p *SM2CertPool) AddCert(cert *x509.Certificate) error {
+	sm2Cert, err := sm2.ParseCertificate(cert.Raw)
+	if err != nil {
+		return fmt.Errorf("Failed parsing certificate [%s]", err)
+	}
+
+	p.pool.AddCert(sm2Cert)
+	return nil
+}
+
+// VerifySM2Certificate verifies the SM2 signed certificate against the roots
+// and the intermediates. Of the options, only the current time and the key
+// usages are used.
+func VerifySM2Certificate(cert *x509.Certificate, roots, intermediates *SM2CertPool, opts x509.VerifyOptions) ([][]*x509.Certificate, error) {
+	if roots == nil || intermediates == nil {
+		return nil, errors.New("Invalid pools. They must be different from nil.")
+	}
+
+	sm2Cert, err := sm2.ParseCertificate(cert.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing certificate [%s]", err)
+	}
+
+	var keyUsages []sm2.ExtKeyUsage
+	for _, usage := range opts.KeyUsages {
+		keyUsages = append(keyUsages, sm2.ExtKeyUsage(usage))
+	}
+
+	sm2Chains, err := sm2Cert.Verify(sm2.VerifyOptions{
+		Roots:         roots.pool,
+		Intermediates: intermediates.pool,
+		CurrentTime:   opts.CurrentTime,
+		KeyUsages:     keyUsages,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	chains := make([][]*x509.Certificate, len(sm2Chains))
+	for i, sm2Chain := range sm2Chains {
+		chains[i] = make([]*x509.Certificate, len(sm2Chain))
+		for j, c := range sm2Chain {
+			if chains[i][j], err = DERToX509Certificate(c.Raw); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return chains, nil
+}
+
+// CheckCRLSignature checks that the signature in crl is from the CA,
+// whether the CA holds an SM2 key or a key of the standard library
+func CheckCRLSignature(ca *x509.Certificate, crl *pkix.CertificateList) error {
+	if _, ok := ca.PublicKey.(*sm2.PublicKey); !ok {
+		return ca.CheckCRLSignature(crl)
+	}
+
+	sm2Cert, err := sm2.ParseCertificate(ca.Raw)
+	if err != nil {
+		return fmt.Errorf("Failed parsing certificate [%s]", err)
+	}
+
+	return sm2Cert.CheckCRLSignature(crl)
+}
+
+// CreateSM2Certificate creates a certificate signed with SM2 based on the
+// template, as x509.CreateCertificate does. The signer must hold an SM2 key.
+func CreateSM2Certificate(template, parent *x509.Certificate, pub interface{}, priv crypto.Signer) ([]byte, error) {
+	if _, ok := priv.Public().(*sm2.PublicKey); !ok {
+		return nil, errors.New("Invalid signer. It must hold an SM2 key.")
+	}
+
+	sm2Template := x509ToSM2Certificate(template)
+	sm2Template.SignatureAlgorithm = sm2.SM2WithSM3
+
+	sm2Parent := sm2Template
+	if parent != template {
+		sm2Parent = x509ToSM2Certificate(parent)
+	}
+
+	return sm2.CreateCertificate(rand.Reader, sm2Template, sm2Parent, pub, priv)
+}
+
+func sm2ToX509Certificate(c *sm2.Certificate) *x509.Certificate {
+	cert := &x509.Certificate{
+		Raw:                         c.Raw,
+		RawTBSCertificate:           c.RawTBSCertificate,
+		RawSubjectPublicKeyInfo:     c.RawSubjectPublicKeyInfo,
+		RawSubject:                  c.RawSubject,
+		RawIssuer:                   c.RawIssuer,
+		Signature:                   c.Signature,
+		SignatureAlgorithm:          x509.UnknownSignatureAlgorithm,
+		PublicKeyAlgorithm:          x509.ECDSA,
+		Version:                     c.Version,
+		SerialNumber:                c.SerialNumber,
+		Issuer:                      c.Issuer,
+		Subject:                     c.Subject,
+		NotBefore:                   c.NotBefore,
+		NotAfter:                    c.NotAfter,
+		KeyUsage:                    x509.KeyUsage(c.KeyUsage),
+		Extensions:                  c.Extensions,
+		UnhandledCriticalExtensions: c.UnhandledCriticalExtensions,
+		UnknownExtKeyUsage:          c.UnknownExtKeyUsage,
+		BasicConstraintsValid:       c.BasicConstraintsValid,
+		IsCA:                        c.IsCA,
+		MaxPathLen:                  c.MaxPathLen,
+		MaxPathLenZero:              c.MaxPathLenZero,
+		SubjectKeyId:                c.SubjectKeyId,
+		AuthorityKeyId:              c.AuthorityKeyId,
+		OCSPServer:                  c.OCSPServer,
+		IssuingCertificateURL:       c.IssuingCertificateURL,
+		DNSNames:                    c.DNSNames,
+		EmailAddresses:              c.EmailAddresses,
+		IPAddresses:                 c.IPAddresses,
+		PermittedDNSDomainsCritical: c.PermittedDNSDomainsCritical,
+		PermittedDNSDomains:         c.PermittedDNSDomains,
+		CRLDistributionPoints:       c.CRLDistributionPoints,
+		PolicyIdentifiers:           c.PolicyIdentifiers,
+	}
+	for _, usage := range c.ExtKeyUsage {
+		cert.ExtKeyUsage = append(cert.ExtKeyUsage, x509.ExtKeyUsage(usage))
+	}
+	if ecKey, ok := c.PublicKey.(*ecdsa.PublicKey); ok {
+		cert.PublicKey = &sm2.PublicKey{Curve: ecKey.Curve, X: ecKey.X, Y: ecKey.Y}
+	}
+
+	return cert
+}
+
+// x509ToSM2Certificate converts the fields of a certificate template used
+// when creating certificates
+func x509ToSM2Certificate(cert *x509.Certificate) *sm2.Certificate {
+	c := &sm2.Certificate{
+		RawSubject:                  cert.RawSubject,
+		SerialNumber:                cert.SerialNumber,
+		Subject:                     cert.Subject,
+		NotBefore:                   cert.NotBefore,
+		NotAfter:                    cert.NotAfter,
+		KeyUsage:                    sm2.KeyUsage(cert.KeyUsage),
+		ExtraExtensions:             cert.ExtraExtensions,
+		UnknownExtKeyUsage:          cert.UnknownExtKeyUsage,
+		BasicConstraintsValid:       cert.BasicConstraintsValid,
+		IsCA:                        cert.IsCA,
+		MaxPathLen:                  cert.MaxPathLen,
+		MaxPathLenZero:              cert.MaxPathLenZero,
+		SubjectKeyId:                cert.SubjectKeyId,
+		AuthorityKeyId:              cert.AuthorityKeyId,
+		OCSPServer:                  cert.OCSPServer,
+		IssuingCertificateURL:       cert.IssuingCertificateURL,
+		DNSNames:                    cert.DNSNames,
+		EmailAddresses:              cert.EmailAddresses,
+		IPAddresses:                 cert.IPAddresses,
+		PermittedDNSDomainsCritical: cert.PermittedDNSDomainsCritical,
+		PermittedDNSDomains:         cert.PermittedDNSDomains,
+		CRLDistributionPoints:       cert.CRLDistributionPoints,
+		PolicyIdentifiers:           cert.PolicyIdentifiers,
+	}
+	for _, usage := range cert.ExtKeyUsage {
+		c.ExtKeyUsage = append(c.ExtKeyUsage, sm2.ExtKeyUsage(usage))
+	}
+
+	return c
 }
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tjfoc/gmsm/sm2"
 )
 
 func TestDERToX509Certificate(t *testing.T) {
@@ -99,3 +100,79 @@ func TestDERToX509Certificate(t *testing.T) {
 	assert.Equal(t, cert.Raw, certRaw)
 
 }
+
+func TestSM2Certificates(t *testing.T) {
+	caKey, err := sm2.GenerateKey()
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca.example.com"},
+		NotBefore:             time.Now().Add(-1 * time.Hour),
+		NotAfter:              time.Now().Add(1 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+	}
+	caRaw, err := CreateSM2Certificate(caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	caCert, err := DERToX509Certificate(caRaw)
+	assert.NoError(t, err)
+	assert.Equal(t, caRaw, caCert.Raw)
+	assert.IsType(t, &sm2.PublicKey{}, caCert.PublicKey)
+	assert.True(t, caCert.IsCA)
+	assert.True(t, IsSM2SignedCertificate(caCert))
+	assert.True(t, IsSM2Certificate(caCert))
+
+	key, err := sm2.GenerateKey()
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "peer0.example.com"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	certRaw, err := CreateSM2Certificate(template, caCert, &key.PublicKey, caKey)
+	assert.NoError(t, err)
+	cert, err := DERToX509Certificate(certRaw)
+	assert.NoError(t, err)
+	assert.Equal(t, "peer0.example.com", cert.Subject.CommonName)
+	assert.True(t, IsSM2SignedCertificate(cert))
+
+	roots := NewSM2CertPool()
+	assert.NoError(t, roots.AddCert(caCert))
+	chains, err := VerifySM2Certificate(cert, roots, NewSM2CertPool(), x509.VerifyOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, chains, 1)
+	assert.Len(t, chains[0], 2)
+	assert.Equal(t, caCert.Raw, chains[0][1].Raw)
+
+	_, err = VerifySM2Certificate(cert, NewSM2CertPool(), NewSM2CertPool(), x509.VerifyOptions{})
+	assert.Error(t, err)
+	_, err = VerifySM2Certificate(cert, nil, nil, x509.VerifyOptions{})
+	assert.Error(t, err)
+
+	// CRLs signed by the SM2 CA
+	sm2CACert, err := sm2.ParseCertificate(caRaw)
+	assert.NoError(t, err)
+	crlRaw, err := sm2CACert.CreateCRL(rand.Reader, caKey, nil, time.Now(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	crl, err := x509.ParseCRL(crlRaw)
+	assert.NoError(t, err)
+	assert.NoError(t, CheckCRLSignature(caCert, crl))
+	otherKey, err := sm2.GenerateKey()
+	assert.NoError(t, err)
+	crlRaw, err = sm2CACert.CreateCRL(rand.Reader, otherKey, nil, time.Now(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	crl, err = x509.ParseCRL(crlRaw)
+	assert.NoError(t, err)
+	assert.Error(t, CheckCRLSignature(caCert, crl))
+
+	// Only SM2 signers can create SM2 certificates
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	_, err = CreateSM2Certificate(template, caCert, &key.PublicKey, ecKey)
+	assert.Error(t, err)
+}
@@ -17,42 +17,60 @@ limitations under the License.
 package cauthdsl
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/Knetic/govaluate"
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/msp"
 	"github.com/hyperledger/fabric/protos/utils"
 )
 
-var regex *regexp.Regexp = regexp.MustCompile("^([[:alnum:]]+)([.])(member|admin)$")
+var regex *regexp.Regexp = regexp.MustCompile("^([[:alnum:]]+)([.])(member|admin|client|peer|orderer)$")
 var regexErr *regexp.Regexp = regexp.MustCompile("^No parameter '([^']+)' found[.]$")
 
 func and(args ...interface{}) (interface{}, error) {
-	toret := "outof(" + strconv.Itoa(len(args))
-	for _, arg := range args {
-		toret += ", "
-		switch t := arg.(type) {
-		case string:
-			if regex.MatchString(t) {
-				toret += "'" + t + "'"
-			} else {
-				toret += t
-			}
-		default:
-			return nil, fmt.Errorf("Unexpected type %s", reflect.TypeOf(arg))
+	args = append([]interface{}{len(args)}, args...)
+	return outof(args...)
+}
+
+func or(args ...interface{}) (interface{}, error) {
+	args = append([]interface{}{1}, args...)
+	return outof(args...)
+}
+
+func outof(args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("Expected at least two arguments to OutOf, got %d", len(args))
+	}
+
+	// govaluate hands us all numbers as float64, whereas and/or pass an int
+	var t int
+	switch n := args[0].(type) {
+	case float64:
+		if n != float64(int(n)) {
+			return nil, fmt.Errorf("Expected an integer threshold in OutOf, got %v", n)
 		}
+		t = int(n)
+	case int:
+		t = n
+	default:
+		return nil, fmt.Errorf("Unexpected type %s", reflect.TypeOf(args[0]))
 	}
 
-	return toret + ")", nil
-}
+	// a threshold below one would be satisfied by no signature at all
+	if t < 1 {
+		return nil, fmt.Errorf("Expected a positive threshold in OutOf, got %d", t)
+	}
 
-func or(args ...interface{}) (interface{}, error) {
-	toret := "outof(" + strconv.Itoa(1)
-	for _, arg := range args {
+	toret := "outof(" + strconv.Itoa(t)
+
+	for _, arg := range args[1:] {
 		toret += ", "
 		switch t := arg.(type) {
 		case string:
@@ -117,10 +135,10 @@ func secondPass(args ...interface{}) (interface{}, error) {
 	}
 
 	/* get the n in the t out of n */
-	var n int = len(args) - 1
+	var n int = len(args) - 2
 
-	/* sanity check - t better be <= n */
-	if t > n {
+	/* sanity check - t better be between 1 and n */
+	if t < 1 || t > n {
 		return nil, fmt.Errorf("Invalid t-out-of-n predicate, t %d, n %d", t, n)
 	}
 
@@ -131,7 +149,7 @@ func secondPass(args ...interface{}) (interface{}, error) {
 		switch t := principal.(type) {
 		/* if it's a string, we expect it to be formed as
 		   <MSP_ID> . <ROLE>, where MSP_ID is the MSP identifier
-		   and ROLE is either a member of an admin*/
+		   and ROLE is either a member, an admin, a client, a peer or an orderer*/
 		case string:
 			/* split the string */
 			subm := regex.FindAllStringSubmatch(t, -1)
@@ -139,12 +157,25 @@ func secondPass(args ...interface{}) (interface{}, error) {
 				return nil, fmt.Errorf("Error parsing principal %s", t)
 			}
 
+			/* if we've already seen this principal, just refer to it */
+			if idx, seen := ctx.principalIndex[t]; seen {
+				policies = append(policies, SignedBy(int32(idx)))
+				continue
+			}
+
 			/* get the right role */
 			var r msp.MSPRole_MSPRoleType
-			if subm[0][3] == "member" {
+			switch subm[0][3] {
+			case "member":
 				r = msp.MSPRole_MEMBER
-			} else {
+			case "admin":
 				r = msp.MSPRole_ADMIN
+			case "client":
+				r = msp.MSPRole_CLIENT
+			case "peer":
+				r = msp.MSPRole_PEER
+			default:
+				r = msp.MSPRole_ORDERER
 			}
 
 			/* build the principal we've been told */
@@ -152,17 +183,15 @@ func secondPass(args ...interface{}) (interface{}, error) {
 				PrincipalClassification: msp.MSPPrincipal_ROLE,
 				Principal:               utils.MarshalOrPanic(&msp.MSPRole{MspIdentifier: subm[0][1], Role: r})}
 			ctx.principals = append(ctx.principals, p)
+			ctx.principalIndex[t] = ctx.IDNum
 
 			/* create a SignaturePolicy that requires a signature from
 			   the principal we've just built*/
 			dapolicy := SignedBy(int32(ctx.IDNum))
 			policies = append(policies, dapolicy)
 
-			/* increment the identity counter. Note that this is
-			   suboptimal as we are not reusing identities. We
-			   can deduplicate them easily and make this puppy
-			   smaller. For now it's fine though */
-			// TODO: deduplicate principals
+			/* increment the identity counter; principals that appear
+			   more than once in the policy share the same identity */
 			ctx.IDNum++
 
 		/* if we've already got a policy we're good, just append it */
@@ -178,12 +207,13 @@ func secondPass(args ...interface{}) (interface{}, error) {
 }
 
 type context struct {
-	IDNum      int
-	principals []*msp.MSPPrincipal
+	IDNum          int
+	principals     []*msp.MSPPrincipal
+	principalIndex map[string]int
 }
 
 func newContext() *context {
-	return &context{IDNum: 0, principals: make([]*msp.MSPPrincipal, 0)}
+	return &context{IDNum: 0, principals: make([]*msp.MSPPrincipal, 0), principalIndex: make(map[string]int)}
 }
 
 // FromString takes a string representation of the policy,
@@ -192,9 +222,12 @@ func newContext() *context {
 //
 // GATE(P[, P])
 //
+// OutOf(N, P[, P])
+//
 // where
 //	- GATE is either "and" or "or"
-//	- P is either a principal or another nested call to GATE
+//	- N is the number of the following arguments that must be satisfied
+//	- P is either a principal or another nested call to GATE or OutOf
 //
 // a principal is defined as
 //
@@ -202,10 +235,10 @@ func newContext() *context {
 //
 // where
 //	- ORG is a string (representing the MSP identifier)
-//	- ROLE is either the string "member" or the string "admin" representing the required role
+//	- ROLE is one of the strings "member", "admin", "client", "peer" or "orderer" representing the required role
 func FromString(policy string) (*common.SignaturePolicyEnvelope, error) {
-	// first we translate the and/or business into outof gates
-	intermediate, err := govaluate.NewEvaluableExpressionWithFunctions(policy, map[string]govaluate.ExpressionFunction{"AND": and, "and": and, "OR": or, "or": or})
+	// first we translate the and/or/OutOf business into outof gates
+	intermediate, err := govaluate.NewEvaluableExpressionWithFunctions(policy, map[string]govaluate.ExpressionFunction{"AND": and, "and": and, "OR": or, "or": or, "OutOf": outof, "outof": outof, "OUTOF": outof})
 	if err != nil {
 		return nil, err
 	}
@@ -277,3 +310,90 @@ func FromString(policy string) (*common.SignaturePolicyEnvelope, error) {
 
 	return p, nil
 }
+
+// ToString returns the string representation of a SignaturePolicyEnvelope in
+// the language accepted by FromString: 1-out-of-n gates are printed as OR,
+// n-out-of-n gates as AND and the other ones as OutOf. Only role principals
+// can be expressed in the language
+func ToString(policy *common.SignaturePolicyEnvelope) (string, error) {
+	if policy == nil || policy.Rule == nil {
+		return "", errors.New("Empty policy")
+	}
+
+	principals := make([]string, len(policy.Identities))
+	for i, principal := range policy.Identities {
+		if principal.PrincipalClassification != msp.MSPPrincipal_ROLE {
+			return "", fmt.Errorf("Principal %d of type %s cannot be expressed in the policy language", i, principal.PrincipalClassification)
+		}
+
+		role := &msp.MSPRole{}
+		err := proto.Unmarshal(principal.Principal, role)
+		if err != nil {
+			return "", fmt.Errorf("Error unmarshalling principal %d: %s", i, err)
+		}
+
+		var r string
+		switch role.Role {
+		case msp.MSPRole_MEMBER:
+			r = "member"
+		case msp.MSPRole_ADMIN:
+			r = "admin"
+		case msp.MSPRole_CLIENT:
+			r = "client"
+		case msp.MSPRole_PEER:
+			r = "peer"
+		case msp.MSPRole_ORDERER:
+			r = "orderer"
+		default:
+			return "", fmt.Errorf("Unrecognized role %d of principal %d", int32(role.Role), i)
+		}
+
+		p := role.MspIdentifier + "." + r
+		if !regex.MatchString(p) {
+			return "", fmt.Errorf("Principal %s cannot be expressed in the policy language", p)
+		}
+		principals[i] = "'" + p + "'"
+	}
+
+	res, err := ruleToString(policy.Rule, principals)
+	if err != nil {
+		return "", err
+	}
+
+	/* a lone principal is not a valid policy string */
+	if _, isSignedBy := policy.Rule.Type.(*common.SignaturePolicy_SignedBy); isSignedBy {
+		res = "OR(" + res + ")"
+	}
+
+	return res, nil
+}
+
+func ruleToString(rule *common.SignaturePolicy, principals []string) (string, error) {
+	switch t := rule.Type.(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(principals) {
+			return "", fmt.Errorf("Identity index %d out of range", t.SignedBy)
+		}
+		return principals[t.SignedBy], nil
+	case *common.SignaturePolicy_NOutOf_:
+		args := make([]string, len(t.NOutOf.Rules))
+		for i, subRule := range t.NOutOf.Rules {
+			arg, err := ruleToString(subRule, principals)
+			if err != nil {
+				return "", err
+			}
+			args[i] = arg
+		}
+
+		switch {
+		case len(args) > 0 && t.NOutOf.N == 1:
+			return "OR(" + strings.Join(args, ", ") + ")", nil
+		case len(args) > 0 && int(t.NOutOf.N) == len(args):
+			return "AND(" + strings.Join(args, ", ") + ")", nil
+		default:
+			return fmt.Sprintf("OutOf(%s)", strings.Join(append([]string{strconv.Itoa(int(t.NOutOf.N))}, args...), ", ")), nil
+		}
+	default:
+		return "", fmt.Errorf("Unrecognized rule type %s", reflect.TypeOf(rule.Type))
+	}
+}
@@ -72,6 +72,31 @@ func TestOr(t *testing.T) {
 	assert.True(t, reflect.DeepEqual(p1, p2))
 }
 
+func TestNodeRoles(t *testing.T) {
+	p1, err := FromString("OR('A.client', 'B.peer', 'C.orderer', 'D.admin')")
+	assert.NoError(t, err)
+
+	principals := make([]*msp.MSPPrincipal, 0)
+
+	roles := map[string]msp.MSPRole_MSPRoleType{"A": msp.MSPRole_CLIENT, "B": msp.MSPRole_PEER, "C": msp.MSPRole_ORDERER, "D": msp.MSPRole_ADMIN}
+	for _, id := range []string{"A", "B", "C", "D"} {
+		principals = append(principals, &msp.MSPPrincipal{
+			PrincipalClassification: msp.MSPPrincipal_ROLE,
+			Principal:               utils.MarshalOrPanic(&msp.MSPRole{Role: roles[id], MspIdentifier: id})})
+	}
+
+	p2 := &common.SignaturePolicyEnvelope{
+		Version:    0,
+		Rule:       NOutOf(1, []*common.SignaturePolicy{SignedBy(0), SignedBy(1), SignedBy(2), SignedBy(3)}),
+		Identities: principals,
+	}
+
+	assert.True(t, reflect.DeepEqual(p1, p2))
+
+	_, err = FromString("OR('A.auditor', 'B.peer')")
+	assert.Error(t, err)
+}
+
 func TestComplex1(t *testing.T) {
 	p1, err := FromString("OR('A.member', AND('B.member', 'C.member'))")
 	assert.NoError(t, err)
@@ -136,3 +161,117 @@ func TestBadStringsNoPanic(t *testing.T) {
 	_, err = FromString("OR('A.member', Bmember)")
 	assert.Error(t, err)
 }
+
+func rolePrincipals(ids ...string) []*msp.MSPPrincipal {
+	principals := make([]*msp.MSPPrincipal, 0)
+	for _, id := range ids {
+		principals = append(principals, &msp.MSPPrincipal{
+			PrincipalClassification: msp.MSPPrincipal_ROLE,
+			Principal:               utils.MarshalOrPanic(&msp.MSPRole{Role: msp.MSPRole_MEMBER, MspIdentifier: id})})
+	}
+	return principals
+}
+
+func TestOutOf(t *testing.T) {
+	p1, err := FromString("OutOf(2, 'A.member', 'B.member', 'C.member')")
+	assert.NoError(t, err)
+
+	p2 := &common.SignaturePolicyEnvelope{
+		Version:    0,
+		Rule:       NOutOf(2, []*common.SignaturePolicy{SignedBy(0), SignedBy(1), SignedBy(2)}),
+		Identities: rolePrincipals("A", "B", "C"),
+	}
+	assert.True(t, reflect.DeepEqual(p1, p2))
+
+	p1, err = FromString("outof(1, 'A.member', 'B.member')")
+	assert.NoError(t, err)
+	p2, err = FromString("OR('A.member', 'B.member')")
+	assert.NoError(t, err)
+	assert.True(t, reflect.DeepEqual(p1, p2))
+}
+
+func TestOutOfNested(t *testing.T) {
+	p1, err := FromString("AND('A.member', OUTOF(2, 'B.member', OR('C.member', 'D.member'), 'E.member'))")
+	assert.NoError(t, err)
+
+	p2 := &common.SignaturePolicyEnvelope{
+		Version:    0,
+		Rule:       And(SignedBy(4), NOutOf(2, []*common.SignaturePolicy{SignedBy(2), Or(SignedBy(0), SignedBy(1)), SignedBy(3)})),
+		Identities: rolePrincipals("C", "D", "B", "E", "A"),
+	}
+	assert.True(t, reflect.DeepEqual(p1, p2))
+}
+
+func TestOutOfBad(t *testing.T) {
+	for _, policy := range []string{
+		"OutOf(4, 'A.member', 'B.member', 'C.member')",
+		"OutOf(0, 'A.member', 'B.member')",
+		"OutOf(-1, 'A.member', 'B.member')",
+		"OutOf(1.5, 'A.member', 'B.member')",
+		"OutOf('A.member', 'B.member')",
+		"OutOf(1)",
+		"OutOf(1, 'A.auditor')",
+	} {
+		_, err := FromString(policy)
+		assert.Error(t, err, "policy %s should be rejected", policy)
+	}
+}
+
+func TestDeduplicatePrincipals(t *testing.T) {
+	p1, err := FromString("OR(AND('A.member', 'B.member'), AND('A.member', 'C.member'), OutOf(2, 'B.member', 'C.member', 'A.admin'))")
+	assert.NoError(t, err)
+
+	principals := rolePrincipals("A", "B", "C")
+	principals = append(principals, &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ROLE,
+		Principal:               utils.MarshalOrPanic(&msp.MSPRole{Role: msp.MSPRole_ADMIN, MspIdentifier: "A"})})
+
+	p2 := &common.SignaturePolicyEnvelope{
+		Version: 0,
+		Rule: NOutOf(1, []*common.SignaturePolicy{
+			And(SignedBy(0), SignedBy(1)),
+			And(SignedBy(0), SignedBy(2)),
+			NOutOf(2, []*common.SignaturePolicy{SignedBy(1), SignedBy(2), SignedBy(3)}),
+		}),
+		Identities: principals,
+	}
+	assert.True(t, reflect.DeepEqual(p1, p2))
+}
+
+func TestToString(t *testing.T) {
+	for _, policy := range []string{
+		"AND('A.member', 'B.member')",
+		"OR('A.member', AND('B.admin', 'C.peer'))",
+		"OutOf(2, 'A.member', 'B.client', OR('C.orderer', 'A.member'))",
+		"OR('A.member')",
+	} {
+		p, err := FromString(policy)
+		assert.NoError(t, err)
+
+		str, err := ToString(p)
+		assert.NoError(t, err)
+		assert.Equal(t, policy, str)
+
+		p2, err := FromString(str)
+		assert.NoError(t, err)
+		assert.True(t, reflect.DeepEqual(p, p2))
+	}
+
+	str, err := ToString(SignedByMspMember("A"))
+	assert.NoError(t, err)
+	assert.Equal(t, "OR('A.member')", str)
+
+	_, err = ToString(nil)
+	assert.Error(t, err)
+
+	_, err = ToString(&common.SignaturePolicyEnvelope{Rule: SignedBy(1), Identities: rolePrincipals("A")})
+	assert.Error(t, err)
+
+	_, err = ToString(&common.SignaturePolicyEnvelope{
+		Rule: SignedBy(0),
+		Identities: []*msp.MSPPrincipal{{
+			PrincipalClassification: msp.MSPPrincipal_IDENTITY,
+			Principal:               []byte("identity")}},
+	})
+	assert.Error(t, err)
+}
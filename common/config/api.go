@@ -47,6 +47,10 @@ type ApplicationOrg interface {
 type Application interface {
 	// Organizations returns a map of org ID to ApplicationOrg
 	Organizations() map[string]ApplicationOrg
+
+	// ACLs returns a map of resource name to the references of the policies
+	// granting access to the resource on the channel
+	ACLs() map[string][]string
 }
 
 // Channel gives read only access to the channel configuration
@@ -20,13 +20,22 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/fabric/common/config/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
 const (
 	// ApplicationGroupKey is the group name for the Application config
 	ApplicationGroupKey = "Application"
+
+	// ACLsKey is the key name for the ACLs ConfigValue
+	ACLsKey = "ACLs"
 )
 
+// ApplicationProtos are the config values of the Application group
+type ApplicationProtos struct {
+	ACLs *pb.ACLs
+}
+
 // ApplicationGroup represents the application config group
 type ApplicationGroup struct {
 	*Proposer
@@ -36,6 +45,7 @@ type ApplicationGroup struct {
 
 type ApplicationConfig struct {
 	*standardValues
+	protos *ApplicationProtos
 
 	applicationGroup *ApplicationGroup
 	applicationOrgs  map[string]ApplicationOrg
@@ -61,16 +71,16 @@ func (ag *ApplicationGroup) Allocate() Values {
 }
 
 func NewApplicationConfig(ag *ApplicationGroup) *ApplicationConfig {
-	sv, err := NewStandardValues(&(struct{}{}))
+	protos := &ApplicationProtos{}
+	sv, err := NewStandardValues(protos)
 	if err != nil {
 		logger.Panicf("Programming error: %s", err)
 	}
 
 	return &ApplicationConfig{
 		applicationGroup: ag,
-
-		// Currently there are no config values
-		standardValues: sv,
+		protos:           protos,
+		standardValues:   sv,
 	}
 }
 
@@ -94,3 +104,13 @@ func (ac *ApplicationConfig) Commit() {
 func (ac *ApplicationConfig) Organizations() map[string]ApplicationOrg {
 	return ac.applicationOrgs
 }
+
+// ACLs returns the policy references of the resources of the peer whose
+// access on the channel is set by the channel configuration
+func (ac *ApplicationConfig) ACLs() map[string][]string {
+	acls := make(map[string][]string)
+	for resource, apiResource := range ac.protos.ACLs.GetAcls() {
+		acls[resource] = apiResource.GetPolicyRefs()
+	}
+	return acls
+}
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	logging "github.com/op/go-logging"
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -29,3 +30,17 @@ func init() {
 func TestApplicationInterface(t *testing.T) {
 	_ = Application((*ApplicationGroup)(nil))
 }
+
+func TestApplicationACLs(t *testing.T) {
+	ac := NewApplicationConfig(nil)
+	assert.Empty(t, ac.ACLs(), "No ACLs should be set by default")
+
+	acls := map[string][]string{
+		"qscc/GetBlockByNumber": {"/Channel/Application/Writers"},
+		"event/FilteredBlock":   {"/Channel/Application/Readers", "/Channel/Application/Writers"},
+	}
+	value := TemplateACLs(acls).Groups[ApplicationGroupKey].Values[ACLsKey]
+	_, err := ac.Deserialize(ACLsKey, value.Value)
+	assert.NoError(t, err, "The ACLs should deserialize")
+	assert.Equal(t, acls, ac.ACLs())
+}
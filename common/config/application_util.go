@@ -36,3 +36,18 @@ func applicationConfigGroup(orgID string, key string, value []byte) *cb.ConfigGr
 func TemplateAnchorPeers(orgID string, anchorPeers []*pb.AnchorPeer) *cb.ConfigGroup {
 	return applicationConfigGroup(orgID, AnchorPeersKey, utils.MarshalOrPanic(&pb.AnchorPeers{AnchorPeers: anchorPeers}))
 }
+
+// TemplateACLs creates a headerless config item representing the ACLs of the
+// resources of the peers, mapping the resource names to policy references
+func TemplateACLs(acls map[string][]string) *cb.ConfigGroup {
+	aclsProto := &pb.ACLs{Acls: make(map[string]*pb.APIResource)}
+	for resource, policyRefs := range acls {
+		aclsProto.Acls[resource] = &pb.APIResource{PolicyRefs: policyRefs}
+	}
+	result := cb.NewConfigGroup()
+	result.Groups[ApplicationGroupKey] = cb.NewConfigGroup()
+	result.Groups[ApplicationGroupKey].Values[ACLsKey] = &cb.ConfigValue{
+		Value: utils.MarshalOrPanic(aclsProto),
+	}
+	return result
+}
@@ -90,13 +90,8 @@ func (bh *MSPConfigHandler) ProposeMSP(tx interface{}, mspConfig *mspprotos.MSPC
 		panic("Programming error, called BeginConfig multiply for the same tx")
 	}
 
-	// check that the type for that MSP is supported
-	if mspConfig.Type != int32(msp.FABRIC) {
-		return nil, fmt.Errorf("Setup error: unsupported msp type %d", mspConfig.Type)
-	}
-
-	// create the msp instance
-	mspInst, err := msp.NewBccspMsp()
+	// create the msp instance of the type in the config, if supported
+	mspInst, err := msp.New(msp.ProviderType(mspConfig.Type))
 	if err != nil {
 		return nil, fmt.Errorf("Creating the MSP manager failed, err %s", err)
 	}
@@ -96,6 +96,10 @@ type Consortium struct {
 // Application encodes the application-level configuration needed in config transactions.
 type Application struct {
 	Organizations []*Organization `yaml:"Organizations"`
+
+	// Policies replace the default policies of the application with the same name,
+	// or are added to them.
+	Policies []*Policy `yaml:"Policies"`
 }
 
 // Organization encodes the organization-level configuration needed in config transactions.
@@ -103,14 +107,26 @@ type Organization struct {
 	Name           string `yaml:"Name"`
 	ID             string `yaml:"ID"`
 	MSPDir         string `yaml:"MSPDir"`
+	MSPType        string `yaml:"MSPType"`
 	AdminPrincipal string `yaml:"AdminPrincipal"`
 
+	// Policies replace the default policies of the organization with the same name,
+	// or are added to them.
+	Policies []*Policy `yaml:"Policies"`
+
 	// Note: Viper deserialization does not seem to care for
 	// embedding of types, so we use one organization struct
 	// for both orderers and applications.
 	AnchorPeers []*AnchorPeer `yaml:"AnchorPeers"`
 }
 
+// Policy encodes a signature policy, whose rule is written in the policy language
+// of the CLI, such as "OutOf(2, 'Org1.member', 'Org2.member', 'Org3.member')".
+type Policy struct {
+	Name string `yaml:"Name"`
+	Rule string `yaml:"Rule"`
+}
+
 // AnchorPeer encodes the necessary fields to identify an anchor peer.
 type AnchorPeer struct {
 	Host string `yaml:"Host"`
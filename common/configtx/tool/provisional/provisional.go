@@ -18,6 +18,7 @@ package provisional
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/config"
@@ -30,6 +31,7 @@ import (
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap"
 	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -65,6 +67,8 @@ const (
 	ConsensusTypeSolo = "solo"
 	// ConsensusTypeKafka identifies the Kafka-based consensus implementation.
 	ConsensusTypeKafka = "kafka"
+	// ConsensusTypeRaft identifies the Raft-based consensus implementation.
+	ConsensusTypeRaft = "raft"
 
 	// TestChainID is the default value of ChainID. It is used by all testing
 	// networks. It it necessary to set and export this variable so that test
@@ -127,7 +131,7 @@ func New(conf *genesisconfig.Profile) Generator {
 		}
 
 		for _, org := range conf.Orderer.Organizations {
-			mspConfig, err := msp.GetVerifyingMspConfig(org.MSPDir, org.ID)
+			mspConfig, err := getOrgMspConfig(org)
 			if err != nil {
 				logger.Panicf("1 - Error loading MSP configuration for org %s: %s", org.Name, err)
 			}
@@ -136,12 +140,14 @@ func New(conf *genesisconfig.Profile) Generator {
 					mspConfig, org.AdminPrincipal == genesisconfig.AdminRoleAdminPrincipal,
 				),
 			)
+			bs.ordererGroups = overridePolicies(bs.ordererGroups, []string{config.OrdererGroupKey, org.Name}, org.Policies)
 		}
 
 		switch conf.Orderer.OrdererType {
 		case ConsensusTypeSolo:
 		case ConsensusTypeKafka:
 			bs.ordererGroups = append(bs.ordererGroups, config.TemplateKafkaBrokers(conf.Orderer.Kafka.Brokers))
+		case ConsensusTypeRaft:
 		default:
 			panic(fmt.Errorf("Wrong consenter type value given: %s", conf.Orderer.OrdererType))
 		}
@@ -155,8 +161,10 @@ func New(conf *genesisconfig.Profile) Generator {
 			policies.TemplateImplicitMetaAnyPolicy([]string{config.ApplicationGroupKey}, configvaluesmsp.WritersPolicyKey),
 			policies.TemplateImplicitMetaMajorityPolicy([]string{config.ApplicationGroupKey}, configvaluesmsp.AdminsPolicyKey),
 		}
+		bs.applicationGroups = overridePolicies(bs.applicationGroups, []string{config.ApplicationGroupKey}, conf.Application.Policies)
+
 		for _, org := range conf.Application.Organizations {
-			mspConfig, err := msp.GetVerifyingMspConfig(org.MSPDir, org.ID)
+			mspConfig, err := getOrgMspConfig(org)
 			if err != nil {
 				logger.Panicf("2- Error loading MSP configuration for org %s: %s", org.Name, err)
 			}
@@ -166,6 +174,7 @@ func New(conf *genesisconfig.Profile) Generator {
 					mspConfig, org.AdminPrincipal == genesisconfig.AdminRoleAdminPrincipal,
 				),
 			)
+			bs.applicationGroups = overridePolicies(bs.applicationGroups, []string{config.ApplicationGroupKey, org.Name}, org.Policies)
 			var anchorProtos []*pb.AnchorPeer
 			for _, anchorPeer := range org.AnchorPeers {
 				anchorProtos = append(anchorProtos, &pb.AnchorPeer{
@@ -210,7 +219,7 @@ func New(conf *genesisconfig.Profile) Generator {
 			bs.consortiumsGroups = append(bs.consortiumsGroups, cg)
 
 			for _, org := range consortium.Organizations {
-				mspConfig, err := msp.GetVerifyingMspConfig(org.MSPDir, org.ID)
+				mspConfig, err := getOrgMspConfig(org)
 				if err != nil {
 					logger.Panicf("3 - Error loading MSP configuration for org %s: %s", org.Name, err)
 				}
@@ -220,6 +229,8 @@ func New(conf *genesisconfig.Profile) Generator {
 						mspConfig, org.AdminPrincipal == genesisconfig.AdminRoleAdminPrincipal,
 					),
 				)
+				bs.consortiumsGroups = overridePolicies(bs.consortiumsGroups,
+					[]string{config.ConsortiumsGroupKey, consortiumName, org.Name}, org.Policies)
 			}
 		}
 	}
@@ -227,6 +238,49 @@ func New(conf *genesisconfig.Profile) Generator {
 	return bs
 }
 
+// overridePolicies sets the given signature policies at the given path of the config, replacing the policies
+// with the same name of the groups. The rules of the policies are written in the policy language of the CLI,
+// e.g. "OutOf(2, 'Org1.member', 'Org2.member', 'Org3.member')"
+func overridePolicies(groups []*cb.ConfigGroup, configPath []string, sigPolicies []*genesisconfig.Policy) []*cb.ConfigGroup {
+	if len(sigPolicies) == 0 {
+		return groups
+	}
+
+	for _, group := range groups {
+		intermediate := group
+		for _, name := range configPath {
+			if intermediate = intermediate.Groups[name]; intermediate == nil {
+				break
+			}
+		}
+		if intermediate != nil {
+			for _, sigPolicy := range sigPolicies {
+				delete(intermediate.Policies, sigPolicy.Name)
+			}
+		}
+	}
+
+	result := cb.NewConfigGroup()
+	intermediate := result
+	for _, name := range configPath {
+		intermediate.Groups[name] = cb.NewConfigGroup()
+		intermediate = intermediate.Groups[name]
+	}
+	for _, sigPolicy := range sigPolicies {
+		policy, err := cauthdsl.FromString(sigPolicy.Rule)
+		if err != nil {
+			logger.Panicf("Error parsing policy %s of %s: %s", sigPolicy.Name, strings.Join(configPath, "/"), err)
+		}
+		intermediate.Policies[sigPolicy.Name] = &cb.ConfigPolicy{
+			Policy: &cb.Policy{
+				Type:  int32(cb.Policy_SIGNATURE),
+				Value: utils.MarshalOrPanic(policy),
+			},
+		}
+	}
+	return append(groups, result)
+}
+
 // ChannelTemplate TODO
 func (bs *bootstrapper) ChannelTemplate() configtx.Template {
 	return configtx.NewModPolicySettingTemplate(
@@ -274,3 +328,16 @@ func (bs *bootstrapper) GenesisBlockForChannel(channelID string) *cb.Block {
 	}
 	return block
 }
+
+// getOrgMspConfig loads the verifying MSP configuration of an organization,
+// according to the type of its MSP
+func getOrgMspConfig(org *genesisconfig.Organization) (*mspprotos.MSPConfig, error) {
+	switch org.MSPType {
+	case "", msp.ProviderTypeToString(msp.FABRIC):
+		return msp.GetVerifyingMspConfig(org.MSPDir, org.ID)
+	case msp.ProviderTypeToString(msp.IDEMIX):
+		return msp.GetIdemixMspConfig(org.MSPDir, org.ID)
+	default:
+		return nil, fmt.Errorf("unknown MSP type %s", org.MSPType)
+	}
+}
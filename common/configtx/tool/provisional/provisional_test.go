@@ -20,19 +20,27 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/common/configtx"
 	genesisconfig "github.com/hyperledger/fabric/common/configtx/tool/localconfig"
+	"github.com/hyperledger/fabric/msp"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
 )
 
 var confSolo *genesisconfig.Profile
 var confKafka *genesisconfig.Profile
+var confRaft *genesisconfig.Profile
 var testCases []*genesisconfig.Profile
 
 func init() {
 	confSolo = genesisconfig.Load(genesisconfig.SampleSingleMSPSoloProfile)
 	confKafka = genesisconfig.Load("SampleInsecureKafka")
-	testCases = []*genesisconfig.Profile{confSolo, confKafka}
+	confRaft = genesisconfig.Load("SampleInsecureRaft")
+	testCases = []*genesisconfig.Profile{confSolo, confKafka, confRaft}
 }
 
 func TestGenesisBlockHeader(t *testing.T) {
@@ -71,3 +79,56 @@ func TestGenesisBlockForChannelHeader(t *testing.T) {
 		assert.Nil(t, genesisBlock.Header.PreviousHash, "Case %s: Header previousHash to be nil", tc.Orderer.OrdererType)
 	}
 }
+
+func TestGetOrgMspConfig(t *testing.T) {
+	org := &genesisconfig.Organization{ID: "MSP1", MSPDir: "../../../../msp/testdata/idemix/MSP1OU1", MSPType: "idemix"}
+	mspConfig, err := getOrgMspConfig(org)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(msp.IDEMIX), mspConfig.Type)
+
+	org.MSPType = "bccsp"
+	_, err = getOrgMspConfig(org)
+	assert.Error(t, err)
+
+	org.MSPType = "unknown"
+	_, err = getOrgMspConfig(org)
+	assert.Error(t, err)
+}
+
+func TestProfilePolicies(t *testing.T) {
+	conf := genesisconfig.Load(genesisconfig.SampleSingleMSPSoloProfile)
+	conf.Application = genesisconfig.Load(genesisconfig.SampleSingleMSPChannelProfile).Application
+	conf.Application.Policies = []*genesisconfig.Policy{
+		{Name: "Admins", Rule: "OutOf(2, 'Org1.admin', 'Org2.admin', 'Org3.admin')"},
+		{Name: "Custom", Rule: "OR('Org1.member', 'Org2.member')"},
+	}
+	org := *conf.Application.Organizations[0]
+	org.Policies = []*genesisconfig.Policy{{Name: "Writers", Rule: "OutOf(1, 'DEFAULT.admin')"}}
+	conf.Application.Organizations = []*genesisconfig.Organization{&org}
+
+	genesisBlock := New(conf).GenesisBlockForChannel("mychannel")
+	payload, err := utils.UnmarshalPayload(utils.ExtractEnvelopeOrPanic(genesisBlock, 0).Payload)
+	assert.NoError(t, err)
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	assert.NoError(t, err)
+	appGroup := configEnv.Config.ChannelGroup.Groups[config.ApplicationGroupKey]
+
+	assertSignaturePolicy := func(rule string, configPolicy *cb.ConfigPolicy) {
+		expected, err := cauthdsl.FromString(rule)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(cb.Policy_SIGNATURE), configPolicy.Policy.Type)
+		policy := &cb.SignaturePolicyEnvelope{}
+		assert.NoError(t, proto.Unmarshal(configPolicy.Policy.Value, policy))
+		assert.True(t, proto.Equal(expected, policy), "Policy %s expected", rule)
+	}
+	assertSignaturePolicy(conf.Application.Policies[0].Rule, appGroup.Policies["Admins"])
+	assertSignaturePolicy(conf.Application.Policies[1].Rule, appGroup.Policies["Custom"])
+	assertSignaturePolicy(org.Policies[0].Rule, appGroup.Groups[org.Name].Policies["Writers"])
+
+	// the policies not overridden keep their default
+	assert.Equal(t, int32(cb.Policy_IMPLICIT_META), appGroup.Policies["Writers"].Policy.Type)
+	assert.Equal(t, int32(cb.Policy_SIGNATURE), appGroup.Groups[org.Name].Policies["Admins"].Policy.Type)
+
+	conf.Application.Policies = []*genesisconfig.Policy{{Name: "Admins", Rule: "OutOf(4, 'Org1.admin', 'Org2.admin')"}}
+	assert.Panics(t, func() { New(conf) }, "An invalid policy should have been rejected")
+}
@@ -0,0 +1,247 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crl
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+var logger = flogging.MustGetLogger("crl")
+
+// oidAuthorityKeyIdentifier is the ASN.1 tag of the authorityKeyIdentifier
+// extension (2 5 29 35), see https://tools.ietf.org/html/rfc5280
+var oidAuthorityKeyIdentifier = asn1.ObjectIdentifier{2, 5, 29, 35}
+
+/*
+   This is the definition of the ASN.1 marshalling of AuthorityKeyIdentifier
+   from https://www.ietf.org/rfc/rfc5280.txt
+
+   AuthorityKeyIdentifier ::= SEQUENCE {
+      keyIdentifier             [0] KeyIdentifier           OPTIONAL,
+      authorityCertIssuer       [1] GeneralNames            OPTIONAL,
+      authorityCertSerialNumber [2] CertificateSerialNumber OPTIONAL  }
+
+   KeyIdentifier ::= OCTET STRING
+
+   CertificateSerialNumber  ::=  INTEGER
+
+*/
+type authorityKeyIdentifier struct {
+	KeyIdentifier             []byte  `asn1:"optional,tag:0"`
+	AuthorityCertIssuer       []byte  `asn1:"optional,tag:1"`
+	AuthorityCertSerialNumber big.Int `asn1:"optional,tag:2"`
+}
+
+// AuthorityKeyIdentifier returns the Authority Key Identifier of the CRL,
+// which identifies the public key of the CA which signed the CRL
+func AuthorityKeyIdentifier(crl *pkix.CertificateList) ([]byte, error) {
+	for _, ext := range crl.TBSCertList.Extensions {
+		if ext.Id.Equal(oidAuthorityKeyIdentifier) {
+			aki := authorityKeyIdentifier{}
+			if _, err := asn1.Unmarshal(ext.Value, &aki); err != nil {
+				return nil, fmt.Errorf("Failed to unmarshal AKI, error %s", err)
+			}
+			return aki.KeyIdentifier, nil
+		}
+	}
+	return nil, errors.New("authorityKeyIdentifier not found in CRL")
+}
+
+// IsRevoked tells whether the CRL revokes the certificate
+func IsRevoked(crl *pkix.CertificateList, cert *x509.Certificate) bool {
+	for _, rc := range crl.TBSCertList.RevokedCertificates {
+		if rc.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckRevocation returns an error if one of the CRLs issued by the issuer of
+// the certificate revokes it. The CRLs carrying an Authority Key Identifier
+// other than the Subject Key Identifier of the issuer are skipped, and the
+// CRLs which the issuer did not sign are ignored
+func CheckRevocation(cert, issuer *x509.Certificate, crls []*pkix.CertificateList) error {
+	for _, crl := range crls {
+		if aki, err := AuthorityKeyIdentifier(crl); err == nil && !bytes.Equal(aki, issuer.SubjectKeyId) {
+			continue
+		}
+		if !IsRevoked(crl, cert) {
+			continue
+		}
+		if err := utils.CheckCRLSignature(issuer, crl); err != nil {
+			logger.Warningf("Invalid signature over the identified CRL, error %s", err)
+			continue
+		}
+		return errors.New("The certificate has been revoked")
+	}
+	return nil
+}
+
+// Store holds CRLs which may be replaced while they are in use
+type Store struct {
+	lock sync.RWMutex
+	crls []*pkix.CertificateList
+}
+
+// NewStore returns an empty Store
+func NewStore() *Store {
+	return &Store{}
+}
+
+var localStore = NewStore()
+
+// LocalStore returns the Store of the CRLs local to the node, which apply to
+// the certificates of all the MSPs and to the TLS client certificates in
+// addition to the CRLs of the channel configurations
+func LocalStore() *Store {
+	return localStore
+}
+
+// CRLs returns the CRLs of the store
+func (s *Store) CRLs() []*pkix.CertificateList {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.crls
+}
+
+// Update replaces the CRLs of the store with the given PEM or DER encoded
+// ones. The CRLs of the store are left unchanged if one cannot be parsed
+func (s *Store) Update(crlsBytes [][]byte) error {
+	crls := make([]*pkix.CertificateList, len(crlsBytes))
+	for i, crlBytes := range crlsBytes {
+		crl, err := x509.ParseCRL(crlBytes)
+		if err != nil {
+			return fmt.Errorf("Could not parse CRL %d, err %s", i, err)
+		}
+		crls[i] = crl
+	}
+
+	s.lock.Lock()
+	s.crls = crls
+	s.lock.Unlock()
+	return nil
+}
+
+// CheckRevocation returns an error if one of the CRLs of the store issued by
+// the issuer of the certificate revokes it
+func (s *Store) CheckRevocation(cert, issuer *x509.Certificate) error {
+	return CheckRevocation(cert, issuer, s.CRLs())
+}
+
+// CheckChain returns an error if a certificate of the verified chain, which
+// is ordered from the leaf to the root CA, is revoked by the CRLs of the store
+// issued by the next certificate of the chain. Hence the certificates issued
+// by a revoked intermediate CA are rejected as well
+func (s *Store) CheckChain(chain []*x509.Certificate) error {
+	for i := 0; i < len(chain)-1; i++ {
+		if err := s.CheckRevocation(chain[i], chain[i+1]); err != nil {
+			return fmt.Errorf("certificate of %s rejected: %s", chain[i].Subject.CommonName, err)
+		}
+	}
+	return nil
+}
+
+// CheckVerifiedChains returns an error if one of the chains verified by a
+// TLS handshake is rejected by function 'CheckChain'. It is called by the
+// gRPC server credentials once the handshake completed
+func (s *Store) CheckVerifiedChains(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	for _, chain := range verifiedChains {
+		if err := s.CheckChain(chain); err != nil {
+			return fmt.Errorf("TLS %s", err)
+		}
+	}
+	return nil
+}
+
+// LoadDir replaces the CRLs of the store with the CRLs of the files of the directory
+func (s *Store) LoadDir(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("Could not read CRL directory %s, err %s", dir, err)
+	}
+
+	var crlsBytes [][]byte
+	for _, f := range files {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		crlBytes, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return fmt.Errorf("Could not read CRL file %s, err %s", f.Name(), err)
+		}
+		crlsBytes = append(crlsBytes, crlBytes)
+	}
+	if err := s.Update(crlsBytes); err != nil {
+		return fmt.Errorf("Could not load CRL directory %s, err %s", dir, err)
+	}
+	logger.Infof("Loaded %d CRLs from %s", len(crlsBytes), dir)
+	return nil
+}
+
+// WatchDir loads the CRLs of the directory into the store, and reloads them
+// whenever the files of the directory change until the returned function is
+// called. The CRLs of the store are left unchanged when a reload fails, such
+// as while a file is being written, and are reloaded on the next change
+func (s *Store) WatchDir(dir string) (func(), error) {
+	if err := s.LoadDir(dir); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("Could not watch CRL directory %s, err %s", dir, err)
+	}
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("Could not watch CRL directory %s, err %s", dir, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				logger.Debugf("CRL directory %s changed: %s", dir, event)
+				if err := s.LoadDir(dir); err != nil {
+					logger.Warningf("Keeping the previous CRLs: %s", err)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Warningf("Error watching CRL directory %s: %s", dir, err)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			watcher.Close()
+		})
+	}, nil
+}
@@ -0,0 +1,236 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crl
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCA returns a CA with the Subject Key Identifier. The CA without
+// Subject Key Identifier is not marked as such since the Subject Key
+// Identifier of the CA certificates is otherwise generated
+func newTestCA(t *testing.T, ski []byte) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: ski != nil,
+		IsCA:                  ski != nil,
+		SubjectKeyId:          ski,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) issue(t *testing.T, serial int64) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert
+}
+
+// issueCA returns an intermediate CA of the CA with the Subject Key Identifier
+func (ca *testCA) issueCA(t *testing.T, serial int64, ski []byte) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "intermediate ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          ski,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// revoke returns a PEM encoded CRL of the CA revoking the certificates
+func (ca *testCA) revoke(t *testing.T, certs ...*x509.Certificate) []byte {
+	var revoked []pkix.RevokedCertificate
+	for _, cert := range certs {
+		revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: cert.SerialNumber, RevocationTime: time.Now()})
+	}
+	der, err := ca.cert.CreateCRL(rand.Reader, ca.key, revoked, time.Now(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+}
+
+func parseCRL(t *testing.T, crlBytes []byte) *pkix.CertificateList {
+	list, err := x509.ParseCRL(crlBytes)
+	assert.NoError(t, err)
+	return list
+}
+
+func TestAuthorityKeyIdentifier(t *testing.T) {
+	ca := newTestCA(t, []byte{1, 2, 3})
+	aki, err := AuthorityKeyIdentifier(parseCRL(t, ca.revoke(t)))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, aki)
+
+	_, err = AuthorityKeyIdentifier(&pkix.CertificateList{})
+	assert.Error(t, err)
+}
+
+func TestCheckRevocation(t *testing.T) {
+	ca := newTestCA(t, []byte{1})
+	revoked := ca.issue(t, 10)
+	valid := ca.issue(t, 11)
+	crls := []*pkix.CertificateList{parseCRL(t, ca.revoke(t, revoked))}
+
+	assert.Error(t, CheckRevocation(revoked, ca.cert, crls))
+	assert.NoError(t, CheckRevocation(valid, ca.cert, crls))
+
+	// the CRLs of other CAs are ignored
+	otherCA := newTestCA(t, []byte{2})
+	assert.NoError(t, CheckRevocation(revoked, otherCA.cert, crls))
+
+	// a CRL with the Authority Key Identifier of the issuer but signed by
+	// another CA is ignored
+	forger := newTestCA(t, []byte{1})
+	forged := []*pkix.CertificateList{parseCRL(t, forger.revoke(t, valid))}
+	assert.NoError(t, CheckRevocation(valid, ca.cert, forged))
+
+	// the CRLs without Authority Key Identifier are only identified by their
+	// signature
+	noSKICA := newTestCA(t, nil)
+	assert.Empty(t, noSKICA.cert.SubjectKeyId)
+	noAKI := []*pkix.CertificateList{parseCRL(t, noSKICA.revoke(t, revoked))}
+	assert.Error(t, CheckRevocation(revoked, noSKICA.cert, noAKI))
+	assert.NoError(t, CheckRevocation(revoked, ca.cert, noAKI))
+}
+
+func TestStoreUpdate(t *testing.T) {
+	ca := newTestCA(t, []byte{1})
+	revoked := ca.issue(t, 10)
+
+	s := NewStore()
+	assert.NoError(t, s.CheckRevocation(revoked, ca.cert))
+
+	assert.NoError(t, s.Update([][]byte{ca.revoke(t, revoked)}))
+	assert.Len(t, s.CRLs(), 1)
+	assert.Error(t, s.CheckRevocation(revoked, ca.cert))
+
+	// the CRLs are left unchanged by a failed update
+	assert.Error(t, s.Update([][]byte{[]byte("not a CRL")}))
+	assert.Error(t, s.CheckRevocation(revoked, ca.cert))
+
+	assert.NoError(t, s.Update(nil))
+	assert.NoError(t, s.CheckRevocation(revoked, ca.cert))
+}
+
+func TestCheckVerifiedChains(t *testing.T) {
+	ca := newTestCA(t, []byte{1})
+	revoked := ca.issue(t, 10)
+	valid := ca.issue(t, 11)
+
+	s := NewStore()
+	assert.NoError(t, s.Update([][]byte{ca.revoke(t, revoked)}))
+
+	assert.NoError(t, s.CheckVerifiedChains(nil, nil))
+	assert.NoError(t, s.CheckVerifiedChains(nil, [][]*x509.Certificate{{valid, ca.cert}}))
+	assert.NoError(t, s.CheckVerifiedChains(nil, [][]*x509.Certificate{{ca.cert}}))
+	assert.Error(t, s.CheckVerifiedChains(nil, [][]*x509.Certificate{{valid, ca.cert}, {revoked, ca.cert}}))
+
+	// the certificates issued by a revoked intermediate CA are rejected
+	intermediate := ca.issueCA(t, 12, []byte{2})
+	leaf := intermediate.issue(t, 20)
+	chain := []*x509.Certificate{leaf, intermediate.cert, ca.cert}
+	assert.NoError(t, s.CheckVerifiedChains(nil, [][]*x509.Certificate{chain}))
+	assert.NoError(t, s.Update([][]byte{ca.revoke(t, intermediate.cert)}))
+	err := s.CheckVerifiedChains(nil, [][]*x509.Certificate{chain})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "intermediate ca")
+
+	// as well as the certificates revoked by an intermediate CA
+	assert.NoError(t, s.Update([][]byte{intermediate.revoke(t, leaf)}))
+	assert.Error(t, s.CheckVerifiedChains(nil, [][]*x509.Certificate{chain}))
+}
+
+func TestWatchDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t, []byte{1})
+	revoked := ca.issue(t, 10)
+
+	s := NewStore()
+	_, err = s.WatchDir(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+
+	// the unparsable CRLs fail the initial load
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "bad.pem"), []byte("not a CRL"), 0644))
+	_, err = s.WatchDir(dir)
+	assert.Error(t, err)
+	assert.NoError(t, os.Remove(filepath.Join(dir, "bad.pem")))
+
+	stop, err := s.WatchDir(dir)
+	assert.NoError(t, err)
+	defer stop()
+	assert.Empty(t, s.CRLs())
+
+	revokedEventually := func(expected bool) bool {
+		for i := 0; i < 100; i++ {
+			if (s.CheckRevocation(revoked, ca.cert) != nil) == expected {
+				return true
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		return false
+	}
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "crl.pem"), ca.revoke(t, revoked), 0644))
+	assert.True(t, revokedEventually(true), "The new CRL should have been loaded")
+
+	assert.NoError(t, os.Remove(filepath.Join(dir, "crl.pem")))
+	assert.True(t, revokedEventually(false), "The removed CRL should have been unloaded")
+
+	stop()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "crl.pem"), ca.revoke(t, revoked), 0644))
+	time.Sleep(200 * time.Millisecond)
+	assert.NoError(t, s.CheckRevocation(revoked, ca.cert), "The directory should no longer be watched")
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package flogging
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"regexp"
@@ -39,6 +41,7 @@ var (
 
 	modules          map[string]string // Holds the map of all modules and their respective log level
 	peerStartModules map[string]string
+	activeSpec       string // The logging specification last applied by InitFromSpec
 
 	lock sync.RWMutex
 	once sync.Once
@@ -187,9 +190,52 @@ func InitFromSpec(spec string) string {
 	// register flogging logger in the modules map
 	MustGetLogger(pkgLogID)
 
+	lock.Lock()
+	activeSpec = spec
+	lock.Unlock()
+
 	return levelAll.String()
 }
 
+// Spec returns the logging specification last applied by InitFromSpec. The
+// default level is returned if the specification was empty.
+func Spec() string {
+	lock.RLock()
+	defer lock.RUnlock()
+	if activeSpec == "" {
+		return defaultLevel.String()
+	}
+	return activeSpec
+}
+
+// ValidateSpec checks the supplied logging specification without applying it.
+// Unlike InitFromSpec, which ignores the invalid parts of a specification, it
+// returns an error if any part of the specification is invalid.
+func ValidateSpec(spec string) error {
+	if spec == "" {
+		return errors.New("empty logging specification")
+	}
+	for _, field := range strings.Split(spec, ":") {
+		split := strings.Split(field, "=")
+		switch len(split) {
+		case 1:
+			if _, err := logging.LogLevel(field); err != nil {
+				return fmt.Errorf("invalid logging level '%s'", field)
+			}
+		case 2:
+			if split[0] == "" {
+				return fmt.Errorf("invalid logging override specification '%s' - no module specified", field)
+			}
+			if _, err := logging.LogLevel(split[1]); err != nil {
+				return fmt.Errorf("invalid logging level in '%s'", field)
+			}
+		default:
+			return fmt.Errorf("invalid logging override '%s' - missing ':'?", field)
+		}
+	}
+	return nil
+}
+
 // SetPeerStartupModulesMap saves the modules and their log levels.
 // this function should only be called at the end of peer startup.
 func SetPeerStartupModulesMap() {
@@ -159,6 +159,25 @@ func TestInitFromSpec(t *testing.T) {
 
 }
 
+func TestSpec(t *testing.T) {
+	defer flogging.Reset()
+
+	assert.Equal(t, flogging.DefaultLevel(), flogging.Spec())
+	flogging.InitFromSpec("warning:gossip=debug")
+	assert.Equal(t, "warning:gossip=debug", flogging.Spec())
+	flogging.Reset()
+	assert.Equal(t, flogging.DefaultLevel(), flogging.Spec())
+}
+
+func TestValidateSpec(t *testing.T) {
+	for _, spec := range []string{"info", "DEBUG", "a=info", "warning:a,b=debug:c=error"} {
+		assert.NoError(t, flogging.ValidateSpec(spec), "spec %s should be valid", spec)
+	}
+	for _, spec := range []string{"", "chatty", "a=chatty", "=info", "a=b=info", "info::debug"} {
+		assert.Error(t, flogging.ValidateSpec(spec), "spec %s should be invalid", spec)
+	}
+}
+
 func ExampleInitBackend() {
 	level, _ := logging.LogLevel(flogging.DefaultLevel())
 	// initializes logging backend for testing and sets time to 1970-01-01 00:00:00.000 UTC
@@ -47,6 +47,9 @@ var (
 	ErrNotFoundInIndex = errors.New("Entry not found in index")
 	// ErrAttrNotIndexed is used to indicate that an attribute is not indexed
 	ErrAttrNotIndexed = errors.New("Attribute not indexed")
+	// ErrBlockPruned is used to indicate that a block (or a transaction within it) has been removed by pruning,
+	// or precedes the snapshot that the block store has been bootstrapped from
+	ErrBlockPruned = errors.New("Block has been pruned")
 )
 
 // BlockStoreProvider provides an handle to a BlockStore
@@ -55,6 +58,14 @@ type BlockStoreProvider interface {
 	OpenBlockStore(ledgerid string) (BlockStore, error)
 	Exists(ledgerid string) (bool, error)
 	List() ([]string, error)
+	// IsPruned tells whether blocks of the BlockStore are not available, either because they have been pruned
+	// or because the BlockStore has been bootstrapped from a snapshot
+	IsPruned(ledgerid string) (bool, error)
+	// Rollback removes the blocks that follow block number `blockNum` from a BlockStore that is not open
+	Rollback(ledgerid string, blockNum uint64) error
+	// DropIndex removes the index of a BlockStore that is not open, the index is rebuilt from the stored blocks
+	// when the BlockStore is opened next
+	DropIndex(ledgerid string) error
 	Close()
 }
 
@@ -71,5 +82,25 @@ type BlockStore interface {
 	RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error)
 	RetrieveBlockByTxID(txID string) (*common.Block, error)
 	RetrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	Prune(retainFrom uint64) error // removes the blocks that precede block number `retainFrom`, config blocks excepted
+	// FirstUnprunedBlockNumber returns the number of the first block that has not been pruned, the blocks that
+	// precede it are not available apart from the retained config blocks
+	FirstUnprunedBlockNumber() (uint64, error)
+	// ExportTxIDs exports the IDs of the transactions stored, including the ones imported from a snapshot
+	ExportTxIDs(writer TxIDWriter) error
+	// BootstrapFromSnapshot starts an empty block store from the last block of a snapshot. The preceding
+	// blocks are treated as pruned, except for the last config block which is retained
+	BootstrapFromSnapshot(lastBlock *common.Block, lastConfigBlock *common.Block, txIDs TxIDReader) error
 	Shutdown()
 }
+
+// TxIDWriter receives the IDs of the transactions exported from a BlockStore, along with their validation codes
+type TxIDWriter interface {
+	WriteTxID(txID string, validationCode peer.TxValidationCode) error
+}
+
+// TxIDReader supplies the IDs of the transactions of a snapshot, along with their validation codes,
+// to a BlockStore being bootstrapped. ReadTxID returns an empty txID once all the IDs have been read
+type TxIDReader interface {
+	ReadTxID() (string, peer.TxValidationCode, error)
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -103,6 +104,97 @@ func retrieveLastFileSuffix(rootDir string) (int, error) {
 	return biggestFileNum, err
 }
 
+// constructPruneInfoFromBlockFiles derives the prune info from the first block file present in the dir (if any)
+// and the first block that this file contains
+func constructPruneInfoFromBlockFiles(rootDir string) (*pruneInfo, error) {
+	logger.Debugf("Retrieving prune info from block files")
+	fileSuffixes, err := retrieveFileSuffixes(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(fileSuffixes) == 0 || fileSuffixes[0] == 0 {
+		return &pruneInfo{}, nil
+	}
+	firstFileNum := fileSuffixes[0]
+	stream, err := newBlockfileStream(rootDir, firstFileNum, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.close()
+	blockBytes, err := stream.nextBlockBytes()
+	if err != nil {
+		return nil, err
+	}
+	if blockBytes == nil {
+		return nil, fmt.Errorf("No block found in the first block file present [file num=%d]", firstFileNum)
+	}
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return nil, err
+	}
+	pInfo := &pruneInfo{firstFileSuffixNum: firstFileNum, firstBlockNumber: info.blockHeader.Number}
+	logger.Debugf("Prune info constructed from file system = %s", pInfo)
+	return pInfo, nil
+}
+
+// removeBlockfilesBefore removes the block files with a suffix lower than `fileNum`
+func removeBlockfilesBefore(rootDir string, fileNum int) error {
+	fileSuffixes, err := retrieveFileSuffixes(rootDir)
+	if err != nil {
+		return err
+	}
+	for _, fileSuffix := range fileSuffixes {
+		if fileSuffix >= fileNum {
+			break
+		}
+		logger.Infof("Removing pruned block file [%d]", fileSuffix)
+		if err := os.Remove(deriveBlockfilePath(rootDir, fileSuffix)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeBlockfilesAfter removes the block files with a suffix higher than `fileNum`
+func removeBlockfilesAfter(rootDir string, fileNum int) error {
+	fileSuffixes, err := retrieveFileSuffixes(rootDir)
+	if err != nil {
+		return err
+	}
+	for _, fileSuffix := range fileSuffixes {
+		if fileSuffix <= fileNum {
+			continue
+		}
+		logger.Infof("Removing rolled back block file [%d]", fileSuffix)
+		if err := os.Remove(deriveBlockfilePath(rootDir, fileSuffix)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retrieveFileSuffixes returns the suffixes of the block files present in the dir, in ascending order
+func retrieveFileSuffixes(rootDir string) ([]int, error) {
+	filesInfo, err := ioutil.ReadDir(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	var fileSuffixes []int
+	for _, fileInfo := range filesInfo {
+		name := fileInfo.Name()
+		if fileInfo.IsDir() || !isBlockFileName(name) {
+			continue
+		}
+		fileNum, err := strconv.Atoi(strings.TrimPrefix(name, blockfilePrefix))
+		if err != nil {
+			return nil, err
+		}
+		fileSuffixes = append(fileSuffixes, fileNum)
+	}
+	sort.Ints(fileSuffixes)
+	return fileSuffixes, nil
+}
+
 func isBlockFileName(name string) bool {
 	return strings.HasPrefix(name, blockfilePrefix)
 }
@@ -17,8 +17,10 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"os"
 	"sync"
 	"sync/atomic"
 
@@ -41,7 +43,9 @@ const (
 )
 
 var (
-	blkMgrInfoKey = []byte("blkMgrInfo")
+	blkMgrInfoKey      = []byte("blkMgrInfo")
+	pruneInfoKey       = []byte("pruneInfo")
+	ongoingRollbackKey = []byte("ongoingRollback")
 )
 
 type blockfileMgr struct {
@@ -53,6 +57,8 @@ type blockfileMgr struct {
 	cpInfoCond        *sync.Cond
 	currentFileWriter *blockfileWriter
 	bcInfo            atomic.Value
+	pruneInfo         atomic.Value
+	pruneLock         sync.Mutex
 }
 
 /*
@@ -121,6 +127,10 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		}
 		logger.Debugf("Info constructed by scanning the blocks dir = %s", spew.Sdump(cpInfo))
 	} else {
+		//Complete a rollback before the block files are compared to the checkpoint info, which already reflects it
+		if err = mgr.completeRollback(cpInfo); err != nil {
+			panic(fmt.Sprintf("Could not complete the rollback of the block storage: %s", err))
+		}
 		logger.Debug(`Synching block information from block storage (if needed)`)
 		syncCPInfoFromFS(rootDir, cpInfo)
 	}
@@ -144,6 +154,30 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 	// Create a new KeyValue store database handler for the blocks index in the keyvalue database
 	mgr.index = newBlockIndex(indexConfig, indexStore)
 
+	// pruneInfo tracks the oldest block file (and block) that has not been pruned
+	pInfo, err := mgr.loadPruneInfo()
+	if err != nil {
+		panic(fmt.Sprintf("Could not get prune info from db: %s", err))
+	}
+	if pInfo == nil {
+		if pInfo, err = constructPruneInfoFromBlockFiles(rootDir); err != nil {
+			panic(fmt.Sprintf("Could not build prune info from block files: %s", err))
+		}
+	}
+	//Discard the prune info saved by a bootstrap from a snapshot that was interrupted before storing its block
+	if cpInfo.isChainEmpty && pInfo.firstBlockNumber != 0 {
+		logger.Infof("Discarding the prune info of an interrupted bootstrap from a snapshot: %s", pInfo)
+		pInfo = &pruneInfo{firstFileSuffixNum: pInfo.firstFileSuffixNum}
+		if err = mgr.savePruneInfo(pInfo); err != nil {
+			panic(fmt.Sprintf("Could not save prune info to db: %s", err))
+		}
+	}
+	//Remove the block files left over by a pruning that was interrupted after its index update
+	if err = removeBlockfilesBefore(rootDir, pInfo.firstFileSuffixNum); err != nil {
+		panic(fmt.Sprintf("Could not remove pruned block files: %s", err))
+	}
+	mgr.pruneInfo.Store(pInfo)
+
 	// Update the manager with the checkpoint info and the file writer
 	mgr.cpInfo = cpInfo
 	mgr.currentFileWriter = currentFileWriter
@@ -196,7 +230,7 @@ func syncCPInfoFromFS(rootDir string, cpInfo *checkpointInfo) {
 		return
 	}
 	//Scan the file system to verify that the checkpoint info stored in db is correct
-	_, endOffsetLastBlock, numBlocks, err := scanForLastCompleteBlock(
+	lastBlockBytes, endOffsetLastBlock, numBlocks, err := scanForLastCompleteBlock(
 		rootDir, cpInfo.latestFileChunkSuffixNum, int64(cpInfo.latestFileChunksize))
 	if err != nil {
 		panic(fmt.Sprintf("Could not open current file for detecting last block in the file: %s", err))
@@ -207,7 +241,12 @@ func syncCPInfoFromFS(rootDir string, cpInfo *checkpointInfo) {
 	}
 	//Updates the checkpoint info for the actual last block number stored and it's end location
 	if cpInfo.isChainEmpty {
-		cpInfo.lastBlockNumber = uint64(numBlocks - 1)
+		//The first block stored is not the genesis block when the storage is bootstrapped from a snapshot
+		info, err := extractSerializedBlockInfo(lastBlockBytes)
+		if err != nil {
+			panic(fmt.Sprintf("Could not extract the header of the last block in the file: %s", err))
+		}
+		cpInfo.lastBlockNumber = info.blockHeader.Number
 	} else {
 		cpInfo.lastBlockNumber += uint64(numBlocks)
 	}
@@ -329,13 +368,14 @@ func (mgr *blockfileMgr) syncIndex() error {
 		indexEmpty = true
 	}
 
-	//initialize index to file number:zero, offset:zero and blockNum:0
-	startFileNum := 0
+	//initialize index to the first block file and block that have not been pruned (file number:zero and blockNum:0 if none) and offset:zero
+	pInfo := mgr.getPruneInfo()
+	startFileNum := pInfo.firstFileSuffixNum
 	startOffset := 0
 	skipFirstBlock := false
 	//get the last file that blocks were added to using the checkpoint info
 	endFileNum := mgr.cpInfo.latestFileChunkSuffixNum
-	startingBlockNum := uint64(0)
+	startingBlockNum := pInfo.firstBlockNumber
 
 	//if the index stored in the db has value, update the index information with those values
 	if !indexEmpty {
@@ -423,6 +463,10 @@ func (mgr *blockfileMgr) getBlockchainInfo() *common.BlockchainInfo {
 	return mgr.bcInfo.Load().(*common.BlockchainInfo)
 }
 
+func (mgr *blockfileMgr) getPruneInfo() *pruneInfo {
+	return mgr.pruneInfo.Load().(*pruneInfo)
+}
+
 func (mgr *blockfileMgr) updateCheckpoint(cpInfo *checkpointInfo) {
 	mgr.cpInfoCond.L.Lock()
 	defer mgr.cpInfoCond.L.Unlock()
@@ -458,6 +502,10 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 		blockNum = mgr.getBlockchainInfo().Height - 1
 	}
 
+	if blockNum < mgr.getPruneInfo().firstBlockNumber {
+		return mgr.retrievePrunedConfigBlock(blockNum)
+	}
+
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -499,6 +547,9 @@ func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.B
 }
 
 func (mgr *blockfileMgr) retrieveBlocks(startNum uint64) (*blocksItr, error) {
+	if startNum < mgr.getPruneInfo().firstBlockNumber {
+		return nil, blkstorage.ErrBlockPruned
+	}
 	return newBlockItr(mgr, startNum), nil
 }
 
@@ -513,6 +564,9 @@ func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope,
 
 func (mgr *blockfileMgr) retrieveTransactionByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error) {
 	logger.Debugf("retrieveTransactionByBlockNumTranNum() - blockNum = [%d], tranNum = [%d]", blockNum, tranNum)
+	if blockNum < mgr.getPruneInfo().firstBlockNumber {
+		return nil, blkstorage.ErrBlockPruned
+	}
 	loc, err := mgr.index.getTXLocByBlockNumTranNum(blockNum, tranNum)
 	if err != nil {
 		return nil, err
@@ -520,6 +574,246 @@ func (mgr *blockfileMgr) retrieveTransactionByBlockNumTranNum(blockNum uint64, t
 	return mgr.fetchTransactionEnvelope(loc)
 }
 
+// retrievePrunedConfigBlock returns a config block that has been preserved while its block file was pruned
+func (mgr *blockfileMgr) retrievePrunedConfigBlock(blockNum uint64) (*common.Block, error) {
+	blockBytes, err := mgr.db.Get(constructPrunedConfigBlockKey(blockNum))
+	if err != nil {
+		return nil, err
+	}
+	if blockBytes == nil {
+		return nil, blkstorage.ErrBlockPruned
+	}
+	return deserializeBlock(blockBytes)
+}
+
+/*
+prune removes the block files in which every block precedes the block `retainFrom`, along with
+the index entries of these blocks. Pruning is done at the granularity of a block file, oldest file
+first, and stops at the first file that contains a block to retain. The two latest block files are never
+pruned as they are needed for constructing the checkpoint info from the file system.
+
+Config blocks are never lost: the bytes of a config block found in a pruned file are preserved in
+the index db so that the block remains retrievable by its number.
+
+For each file, the index entries are removed and the new pruneInfo is saved in a single batch
+before the file itself is deleted. If a crash happens in between, the left over file is removed on
+the next start-up.
+*/
+func (mgr *blockfileMgr) prune(retainFrom uint64) error {
+	mgr.pruneLock.Lock()
+	defer mgr.pruneLock.Unlock()
+
+	mgr.cpInfoCond.L.Lock()
+	latestFileNum := mgr.cpInfo.latestFileChunkSuffixNum
+	mgr.cpInfoCond.L.Unlock()
+
+	pInfo := mgr.getPruneInfo()
+	logger.Debugf("prune() - retainFrom = [%d], %s", retainFrom, pInfo)
+	nextBlockNum := pInfo.firstBlockNumber
+	for fileNum := pInfo.firstFileSuffixNum; fileNum < latestFileNum-1; fileNum++ {
+		blockIdxInfos, configBlocks, err := mgr.scanBlockfileForPruning(fileNum)
+		if err != nil {
+			return err
+		}
+		if len(blockIdxInfos) > 0 {
+			lastBlockNum := blockIdxInfos[len(blockIdxInfos)-1].blockNum
+			if lastBlockNum >= retainFrom {
+				break
+			}
+			nextBlockNum = lastBlockNum + 1
+		}
+
+		batch := leveldbhelper.NewUpdateBatch()
+		if err = mgr.index.deleteIndexesForBlocks(fileNum, blockIdxInfos, batch); err != nil {
+			return err
+		}
+		for blockNum, blockBytes := range configBlocks {
+			batch.Put(constructPrunedConfigBlockKey(blockNum), blockBytes)
+		}
+		newPInfo := &pruneInfo{firstFileSuffixNum: fileNum + 1, firstBlockNumber: nextBlockNum}
+		pInfoBytes, err := newPInfo.marshal()
+		if err != nil {
+			return err
+		}
+		batch.Put(pruneInfoKey, pInfoBytes)
+		if err = mgr.db.WriteBatch(batch, true); err != nil {
+			return err
+		}
+		mgr.pruneInfo.Store(newPInfo)
+
+		if err = os.Remove(deriveBlockfilePath(mgr.rootDir, fileNum)); err != nil {
+			return err
+		}
+		logger.Infof("Pruned block file [%d] holding [%d] block(s), preserved [%d] config block(s). First block retained [%d]",
+			fileNum, len(blockIdxInfos), len(configBlocks), nextBlockNum)
+	}
+	return nil
+}
+
+// bootstrapFromSnapshot starts the empty block storage with `lastBlock`, the last block of a snapshot,
+// instead of the genesis block. The blocks that precede it are treated as pruned: the last config block
+// is preserved, as pruning does, and the IDs of the transactions of the snapshot are indexed so that they
+// are known as committed. The prune info is saved before the block is added so that an interrupted
+// bootstrap is detected on the next start-up
+func (mgr *blockfileMgr) bootstrapFromSnapshot(lastBlock, lastConfigBlock *common.Block, txIDs blkstorage.TxIDReader) error {
+	mgr.pruneLock.Lock()
+	defer mgr.pruneLock.Unlock()
+
+	if mgr.getBlockchainInfo().Height != 0 {
+		return errors.New("Block storage is not empty, it cannot be bootstrapped from a snapshot")
+	}
+	blockNum := lastBlock.Header.Number
+	logger.Infof("Bootstrapping block storage from the snapshot at block [%d]", blockNum)
+	if err := mgr.index.importSnapshotTxIDs(txIDs); err != nil {
+		return err
+	}
+
+	batch := leveldbhelper.NewUpdateBatch()
+	if lastConfigBlock.Header.Number < blockNum {
+		configBlockBytes, _, err := serializeBlock(lastConfigBlock)
+		if err != nil {
+			return fmt.Errorf("Error while serializing config block: %s", err)
+		}
+		batch.Put(constructPrunedConfigBlockKey(lastConfigBlock.Header.Number), configBlockBytes)
+	}
+	newPInfo := &pruneInfo{firstFileSuffixNum: mgr.cpInfo.latestFileChunkSuffixNum, firstBlockNumber: blockNum}
+	pInfoBytes, err := newPInfo.marshal()
+	if err != nil {
+		return err
+	}
+	batch.Put(pruneInfoKey, pInfoBytes)
+	if err = mgr.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	mgr.pruneInfo.Store(newPInfo)
+
+	mgr.bcInfo.Store(&common.BlockchainInfo{
+		Height:           blockNum,
+		CurrentBlockHash: lastBlock.Header.PreviousHash})
+	if err = mgr.addBlock(lastBlock); err != nil {
+		mgr.bcInfo.Store(&common.BlockchainInfo{})
+		return err
+	}
+	return nil
+}
+
+/*
+rollback removes the blocks that follow the block `blockNum`. The checkpoint info pointing to the end of
+block `blockNum` is saved along with a rollback marker; the block files are truncated and the index is
+dropped by `completeRollback` when the manager is created next, so that an interrupted rollback is always
+completed. The whole index is dropped, and rebuilt by `syncIndex`, rather than the entries of the removed
+blocks because these blocks may hold transaction IDs that are also used by the blocks that are retained.
+
+The manager cannot be used after a rollback, it is to be closed and created anew.
+*/
+func (mgr *blockfileMgr) rollback(blockNum uint64) error {
+	mgr.pruneLock.Lock()
+	defer mgr.pruneLock.Unlock()
+
+	bcInfo := mgr.getBlockchainInfo()
+	if bcInfo.Height == 0 || blockNum >= bcInfo.Height-1 {
+		return fmt.Errorf("Cannot roll back to block [%d], the block storage has a height of [%d]", blockNum, bcInfo.Height)
+	}
+	pInfo := mgr.getPruneInfo()
+	if blockNum < pInfo.firstBlockNumber {
+		return fmt.Errorf("Cannot roll back to block [%d], the blocks preceding block [%d] are pruned", blockNum, pInfo.firstBlockNumber)
+	}
+	flp, err := mgr.index.getBlockLocByBlockNum(blockNum + 1)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Rolling back block storage from block [%d] to block [%d]", bcInfo.Height-1, blockNum)
+
+	cpInfo := &checkpointInfo{
+		latestFileChunkSuffixNum: flp.fileSuffixNum,
+		latestFileChunksize:      flp.offset,
+		isChainEmpty:             false,
+		lastBlockNumber:          blockNum}
+	cpInfoBytes, err := cpInfo.marshal()
+	if err != nil {
+		return err
+	}
+	batch := leveldbhelper.NewUpdateBatch()
+	batch.Put(blkMgrInfoKey, cpInfoBytes)
+	batch.Put(ongoingRollbackKey, encodeBlockNum(blockNum))
+	return mgr.db.WriteBatch(batch, true)
+}
+
+// completeRollback truncates the block files to the size recorded in the checkpoint info and drops the
+// index, if a rollback has been recorded
+func (mgr *blockfileMgr) completeRollback(cpInfo *checkpointInfo) error {
+	b, err := mgr.db.Get(ongoingRollbackKey)
+	if err != nil || b == nil {
+		return err
+	}
+	logger.Infof("Completing rollback to block [%d]", decodeBlockNum(b))
+	if err = removeBlockfilesAfter(mgr.rootDir, cpInfo.latestFileChunkSuffixNum); err != nil {
+		return err
+	}
+	filePath := deriveBlockfilePath(mgr.rootDir, cpInfo.latestFileChunkSuffixNum)
+	exists, _, err := util.FileExists(filePath)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err = os.Truncate(filePath, int64(cpInfo.latestFileChunksize)); err != nil {
+			return err
+		}
+	}
+	if err = dropIndex(mgr.db); err != nil {
+		return err
+	}
+	return mgr.db.Delete(ongoingRollbackKey, true)
+}
+
+// exportTxIDs writes the IDs of the transactions stored, see `blockIndex.exportTxIDs`
+func (mgr *blockfileMgr) exportTxIDs(writer blkstorage.TxIDWriter) error {
+	return mgr.index.exportTxIDs(writer)
+}
+
+// scanBlockfileForPruning reads all the blocks stored in a block file and returns their index info
+// along with the serialized bytes of the config blocks, keyed by block number
+func (mgr *blockfileMgr) scanBlockfileForPruning(fileNum int) ([]*blockIdxInfo, map[uint64][]byte, error) {
+	stream, err := newBlockfileStream(mgr.rootDir, fileNum, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer stream.close()
+
+	var blockIdxInfos []*blockIdxInfo
+	configBlocks := make(map[uint64][]byte)
+	for {
+		blockBytes, err := stream.nextBlockBytes()
+		if err != nil {
+			return nil, nil, err
+		}
+		if blockBytes == nil {
+			break
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		blockNum := info.blockHeader.Number
+		blockIdxInfos = append(blockIdxInfos, &blockIdxInfo{
+			blockNum: blockNum, blockHash: info.blockHeader.Hash(),
+			txOffsets: info.txOffsets, metadata: info.metadata})
+
+		// a config block carries a single transaction
+		if len(info.txOffsets) != 1 {
+			continue
+		}
+		block, err := deserializeBlock(blockBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		if putil.IsConfigBlock(block) {
+			configBlocks[blockNum] = blockBytes
+		}
+	}
+	return blockIdxInfos, configBlocks, nil
+}
+
 func (mgr *blockfileMgr) fetchBlock(lp *fileLocPointer) (*common.Block, error) {
 	blockBytes, err := mgr.fetchBlockBytes(lp)
 	if err != nil {
@@ -596,6 +890,29 @@ func (mgr *blockfileMgr) saveCurrentInfo(i *checkpointInfo, sync bool) error {
 	return nil
 }
 
+// Get the prune information that is stored in the database, nil if nothing has been pruned
+func (mgr *blockfileMgr) loadPruneInfo() (*pruneInfo, error) {
+	var b []byte
+	var err error
+	if b, err = mgr.db.Get(pruneInfoKey); b == nil || err != nil {
+		return nil, err
+	}
+	i := &pruneInfo{}
+	if err = i.unmarshal(b); err != nil {
+		return nil, err
+	}
+	logger.Debugf("loaded pruneInfo:%s", i)
+	return i, nil
+}
+
+func (mgr *blockfileMgr) savePruneInfo(i *pruneInfo) error {
+	b, err := i.marshal()
+	if err != nil {
+		return err
+	}
+	return mgr.db.Put(pruneInfoKey, b, true)
+}
+
 // scanForLastCompleteBlock scan a given block file and detects the last offset in the file
 // after which there may lie a block partially written (towards the end of the file in a crash scenario).
 func scanForLastCompleteBlock(rootDir string, fileNum int, startingOffset int64) ([]byte, int64, int, error) {
@@ -688,3 +1005,42 @@ func (i *checkpointInfo) String() string {
 	return fmt.Sprintf("latestFileChunkSuffixNum=[%d], latestFileChunksize=[%d], isChainEmpty=[%t], lastBlockNumber=[%d]",
 		i.latestFileChunkSuffixNum, i.latestFileChunksize, i.isChainEmpty, i.lastBlockNumber)
 }
+
+// pruneInfo
+type pruneInfo struct {
+	firstFileSuffixNum int
+	firstBlockNumber   uint64
+}
+
+func (i *pruneInfo) marshal() ([]byte, error) {
+	buffer := proto.NewBuffer([]byte{})
+	var err error
+	if err = buffer.EncodeVarint(uint64(i.firstFileSuffixNum)); err != nil {
+		return nil, err
+	}
+	if err = buffer.EncodeVarint(i.firstBlockNumber); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (i *pruneInfo) unmarshal(b []byte) error {
+	buffer := proto.NewBuffer(b)
+	var val uint64
+	var err error
+
+	if val, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	i.firstFileSuffixNum = int(val)
+
+	if val, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	i.firstBlockNumber = val
+	return nil
+}
+
+func (i *pruneInfo) String() string {
+	return fmt.Sprintf("firstFileSuffixNum=[%d], firstBlockNumber=[%d]", i.firstFileSuffixNum, i.firstBlockNumber)
+}
@@ -21,9 +21,11 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
 	putil "github.com/hyperledger/fabric/protos/utils"
 )
 
@@ -253,3 +255,297 @@ func TestBlockfileMgrGetBlockByTxID(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockfileMgrPrune(t *testing.T) {
+	// a max file size of 1 byte makes each block go to a new file (file 0 remains empty)
+	env := newTestEnv(t, NewConf(testPath(), 1))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	blkfileMgrWrapper.addBlocks(blocks)
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.cpInfo.latestFileChunkSuffixNum, 10)
+
+	err := blkfileMgrWrapper.blockfileMgr.prune(6)
+	testutil.AssertNoError(t, err, "Error while pruning blocks")
+	testBlockfileMgrPruned(t, blkfileMgrWrapper, blocks, 6)
+
+	// pruning again with a lower limit is a no-op
+	err = blkfileMgrWrapper.blockfileMgr.prune(3)
+	testutil.AssertNoError(t, err, "Error while pruning blocks")
+	testBlockfileMgrPruned(t, blkfileMgrWrapper, blocks, 6)
+
+	// the two latest block files are never pruned
+	err = blkfileMgrWrapper.blockfileMgr.prune(10)
+	testutil.AssertNoError(t, err, "Error while pruning blocks")
+	testBlockfileMgrPruned(t, blkfileMgrWrapper, blocks, 8)
+	blkfileMgrWrapper.close()
+
+	// prune info is retained across restart and new blocks can still be added
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	testBlockfileMgrPruned(t, blkfileMgrWrapper, blocks, 8)
+	bg, _ := testutil.NewBlockGenerator(t, ledgerid, false)
+	bg.NextTestBlocks(9)
+	moreBlocks := bg.NextTestBlocks(2)
+	blkfileMgrWrapper.addBlocks(moreBlocks)
+	blkfileMgrWrapper.testGetBlockByNumber(moreBlocks, 10)
+}
+
+func TestBlockfileMgrPruneRecovery(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 1))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	blkfileMgrWrapper.addBlocks(blocks)
+
+	// simulate a crash after the prune info got saved but before the block files got removed
+	pInfo := &pruneInfo{firstFileSuffixNum: 5, firstBlockNumber: 4}
+	b, err := pInfo.marshal()
+	testutil.AssertNoError(t, err, "")
+	blkfileMgrWrapper.blockfileMgr.db.Put(pruneInfoKey, b, true)
+	blkfileMgrWrapper.close()
+
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getPruneInfo(), pInfo)
+	fileSuffixes, err := retrieveFileSuffixes(blkfileMgrWrapper.blockfileMgr.rootDir)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, fileSuffixes[0], 5)
+}
+
+func TestBlockfileMgrRollback(t *testing.T) {
+	// a max file size of 1 byte makes each block go to a new file, so that block files get removed by the rollback
+	for _, maxFileSize := range []int{0, 1} {
+		env := newTestEnv(t, NewConf(testPath(), maxFileSize))
+		ledgerid := "testLedger"
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+		blocks := testutil.ConstructTestBlocks(t, 10)
+		blkfileMgrWrapper.addBlocks(blocks)
+		blkfileMgrWrapper.close()
+
+		// the last block cannot be rolled back to, nor can a ledger that does not exist
+		testutil.AssertError(t, env.provider.Rollback(ledgerid, 9), "Rolling back to the last block should have failed")
+		testutil.AssertError(t, env.provider.Rollback("unknownLedger", 5), "Rolling back an unknown ledger should have failed")
+
+		testutil.AssertNoError(t, env.provider.Rollback(ledgerid, 5), "Error while rolling back block storage")
+		blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+		testBlockfileMgrRolledBack(t, blkfileMgrWrapper, blocks, 5)
+
+		// the removed blocks can be added again
+		blkfileMgrWrapper.addBlocks(blocks[6:])
+		blkfileMgrWrapper.testGetBlockByNumber(blocks, 0)
+		blkfileMgrWrapper.testGetBlockByHash(blocks)
+		blkfileMgrWrapper.close()
+		env.Cleanup()
+	}
+}
+
+func TestBlockfileMgrRollbackRecovery(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 1))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	blkfileMgrWrapper.addBlocks(blocks)
+
+	// simulate a crash after the rollback got recorded but before the block files got truncated
+	err := blkfileMgrWrapper.blockfileMgr.rollback(3)
+	testutil.AssertNoError(t, err, "Error while rolling back block storage")
+	blkfileMgrWrapper.close()
+
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	testBlockfileMgrRolledBack(t, blkfileMgrWrapper, blocks, 3)
+	marker, err := blkfileMgrWrapper.blockfileMgr.db.Get(ongoingRollbackKey)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, marker)
+}
+
+func TestBlockfileMgrRollbackPruned(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 1))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	blkfileMgrWrapper.addBlocks(blocks)
+	testutil.AssertNoError(t, blkfileMgrWrapper.blockfileMgr.prune(6), "Error while pruning blocks")
+
+	err := blkfileMgrWrapper.blockfileMgr.rollback(4)
+	testutil.AssertError(t, err, "Rolling back to a pruned block should have failed")
+	testBlockfileMgrPruned(t, blkfileMgrWrapper, blocks, 6)
+}
+
+func testBlockfileMgrRolledBack(t *testing.T, w *testBlockfileMgrWrapper, blocks []*common.Block, blockNum int) {
+	mgr := w.blockfileMgr
+	lastBlock := blocks[blockNum]
+	bcInfo := mgr.getBlockchainInfo()
+	testutil.AssertEquals(t, bcInfo, &common.BlockchainInfo{
+		Height:            lastBlock.Header.Number + 1,
+		CurrentBlockHash:  lastBlock.Header.Hash(),
+		PreviousBlockHash: lastBlock.Header.PreviousHash})
+	w.testGetBlockByNumber(blocks[:blockNum+1], 0)
+	w.testGetBlockByHash(blocks[:blockNum+1])
+	testBlockfileMgrBlockIterator(t, mgr, 0, blockNum, blocks[:blockNum+1])
+	for i := blockNum + 1; i < len(blocks); i++ {
+		_, err := mgr.retrieveBlockByNumber(uint64(i))
+		testutil.AssertError(t, err, fmt.Sprintf("Block [%d] should have been rolled back", i))
+		_, err = mgr.retrieveBlockByHash(blocks[i].Header.Hash())
+		testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+		txID, err := extractTxID(blocks[i].Data.Data[0])
+		testutil.AssertNoError(t, err, "")
+		_, err = mgr.retrieveTransactionByID(txID)
+		testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+	}
+}
+
+func TestBlockfileMgrBootstrapFromSnapshot(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	blkfileMgrWrapper.addBlocks(blocks)
+	txIDs := &testTxIDs{}
+	testutil.AssertNoError(t, blkfileMgrWrapper.blockfileMgr.exportTxIDs(txIDs), "Error while exporting tx ids")
+	numTxs := 0
+	for _, block := range blocks {
+		numTxs += len(block.Data.Data)
+	}
+	testutil.AssertEquals(t, len(txIDs.txIDs), numTxs)
+	blkfileMgrWrapper.close()
+
+	ledgerid := "snapshotLedger"
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	err := blkfileMgrWrapper.blockfileMgr.bootstrapFromSnapshot(blocks[9], blocks[0], txIDs)
+	testutil.AssertNoError(t, err, "Error while bootstrapping from snapshot")
+	testBlockfileMgrBootstrapped(t, blkfileMgrWrapper, blocks)
+
+	// a non-empty block storage cannot be bootstrapped
+	err = blkfileMgrWrapper.blockfileMgr.bootstrapFromSnapshot(blocks[9], blocks[0], &testTxIDs{})
+	testutil.AssertError(t, err, "Bootstrapping a non-empty block storage should have failed")
+	blkfileMgrWrapper.close()
+
+	// the bootstrapped block storage is retained across restart and new blocks can be added
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	testBlockfileMgrBootstrapped(t, blkfileMgrWrapper, blocks)
+	nextBlock := testutil.ConstructBlock(t, 10, blocks[9].Header.Hash(), [][]byte{[]byte("simulation results")}, false)
+	blkfileMgrWrapper.addBlocks([]*common.Block{nextBlock})
+	blkfileMgrWrapper.testGetBlockByNumber([]*common.Block{blocks[9], nextBlock}, 9)
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height, uint64(11))
+
+	// the transactions of the snapshot are exported again, along with the new ones
+	moreTxIDs := &testTxIDs{}
+	testutil.AssertNoError(t, blkfileMgrWrapper.blockfileMgr.exportTxIDs(moreTxIDs), "Error while exporting tx ids")
+	testutil.AssertEquals(t, len(moreTxIDs.txIDs), numTxs+1)
+	testutil.AssertContainsAll(t, moreTxIDs.txIDs, txIDs.txIDs)
+}
+
+func TestBlockfileMgrBootstrapFromSnapshotRecovery(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	ledgerid := "snapshotLedger"
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+
+	// simulate a crash after the prune info got saved but before the last block of the snapshot got added
+	pInfo := &pruneInfo{firstFileSuffixNum: 0, firstBlockNumber: 9}
+	b, err := pInfo.marshal()
+	testutil.AssertNoError(t, err, "")
+	blkfileMgrWrapper.blockfileMgr.db.Put(pruneInfoKey, b, true)
+	blkfileMgrWrapper.close()
+
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getPruneInfo(), &pruneInfo{})
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height, uint64(0))
+
+	txIDs := &testTxIDs{}
+	for _, block := range blocks {
+		txID, err := extractTxID(block.Data.Data[0])
+		testutil.AssertNoError(t, err, "")
+		txIDs.WriteTxID(txID, peer.TxValidationCode_VALID)
+	}
+	err = blkfileMgrWrapper.blockfileMgr.bootstrapFromSnapshot(blocks[9], blocks[0], txIDs)
+	testutil.AssertNoError(t, err, "Error while bootstrapping from snapshot")
+	testBlockfileMgrBootstrapped(t, blkfileMgrWrapper, blocks)
+}
+
+func testBlockfileMgrBootstrapped(t *testing.T, w *testBlockfileMgrWrapper, blocks []*common.Block) {
+	mgr := w.blockfileMgr
+	lastBlock := blocks[len(blocks)-1]
+	bcInfo := mgr.getBlockchainInfo()
+	testutil.AssertEquals(t, bcInfo, &common.BlockchainInfo{
+		Height:            lastBlock.Header.Number + 1,
+		CurrentBlockHash:  lastBlock.Header.Hash(),
+		PreviousBlockHash: lastBlock.Header.PreviousHash})
+	w.testGetBlockByNumber([]*common.Block{lastBlock}, lastBlock.Header.Number)
+	w.testGetBlockByHash([]*common.Block{lastBlock})
+
+	// the config block of the snapshot is preserved, the other blocks are treated as pruned
+	b, err := mgr.retrieveBlockByNumber(0)
+	testutil.AssertNoError(t, err, "Error while retrieving config block of the snapshot")
+	testutil.AssertEquals(t, b, blocks[0])
+	for i := 1; i < len(blocks)-1; i++ {
+		_, err = mgr.retrieveBlockByNumber(uint64(i))
+		testutil.AssertEquals(t, err, blkstorage.ErrBlockPruned)
+		txID, err := extractTxID(blocks[i].Data.Data[0])
+		testutil.AssertNoError(t, err, "")
+		_, err = mgr.retrieveTransactionByID(txID)
+		testutil.AssertEquals(t, err, blkstorage.ErrBlockPruned)
+		_, err = mgr.retrieveBlockByTxID(txID)
+		testutil.AssertEquals(t, err, blkstorage.ErrBlockPruned)
+		validationCode, err := mgr.retrieveTxValidationCodeByTxID(txID)
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, validationCode, peer.TxValidationCode_VALID)
+	}
+	_, err = mgr.retrieveTransactionByID("unknownTxID")
+	testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+}
+
+// testTxIDs holds the transaction IDs exported from a block storage, to be imported into another one
+type testTxIDs struct {
+	txIDs           []string
+	validationCodes []peer.TxValidationCode
+	next            int
+}
+
+func (ids *testTxIDs) WriteTxID(txID string, validationCode peer.TxValidationCode) error {
+	ids.txIDs = append(ids.txIDs, txID)
+	ids.validationCodes = append(ids.validationCodes, validationCode)
+	return nil
+}
+
+func (ids *testTxIDs) ReadTxID() (string, peer.TxValidationCode, error) {
+	if ids.next == len(ids.txIDs) {
+		return "", 0, nil
+	}
+	ids.next++
+	return ids.txIDs[ids.next-1], ids.validationCodes[ids.next-1], nil
+}
+
+func testBlockfileMgrPruned(t *testing.T, w *testBlockfileMgrWrapper, blocks []*common.Block, firstRetained int) {
+	mgr := w.blockfileMgr
+	// the genesis block is a config block and is preserved
+	b, err := mgr.retrieveBlockByNumber(0)
+	testutil.AssertNoError(t, err, "Error while retrieving pruned config block")
+	testutil.AssertEquals(t, b, blocks[0])
+	for i := 1; i < firstRetained; i++ {
+		_, err = mgr.retrieveBlockByNumber(uint64(i))
+		testutil.AssertEquals(t, err, blkstorage.ErrBlockPruned)
+		_, err = mgr.retrieveBlockByHash(blocks[i].Header.Hash())
+		testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+		_, err = mgr.retrieveTransactionByBlockNumTranNum(uint64(i), 0)
+		testutil.AssertEquals(t, err, blkstorage.ErrBlockPruned)
+		txID, err := extractTxID(blocks[i].Data.Data[0])
+		testutil.AssertNoError(t, err, "")
+		_, err = mgr.retrieveTransactionByID(txID)
+		testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+	}
+	_, err = mgr.retrieveBlocks(uint64(firstRetained - 1))
+	testutil.AssertEquals(t, err, blkstorage.ErrBlockPruned)
+	w.testGetBlockByHash(blocks[firstRetained:])
+	w.testGetBlockByNumber(blocks[firstRetained:], uint64(firstRetained))
+	testBlockfileMgrBlockIterator(t, mgr, firstRetained, len(blocks)-1, blocks[firstRetained:])
+}
@@ -37,7 +37,14 @@ const (
 	blockNumTranNumIdxKeyPrefix    = 'a'
 	blockTxIDIdxKeyPrefix          = 'b'
 	txValidationResultIdxKeyPrefix = 'v'
+	prunedConfigBlockKeyPrefix     = 'r'
+	snapshotTxIDIdxKeyPrefix       = 's'
 	indexCheckpointKeyStr          = "indexCheckpointKey"
+
+	// maxSnapshotTxIDsBatchSize is the number of transaction IDs of a snapshot written to the index in a single batch
+	maxSnapshotTxIDsBatchSize = 10000
+	// maxDropIndexBatchSize is the number of index entries removed in a single batch when the index is dropped
+	maxDropIndexBatchSize = 10000
 )
 
 var indexCheckpointKey = []byte(indexCheckpointKeyStr)
@@ -52,6 +59,9 @@ type index interface {
 	getTXLocByBlockNumTranNum(blockNum uint64, tranNum uint64) (*fileLocPointer, error)
 	getBlockLocByTxID(txID string) (*fileLocPointer, error)
 	getTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	deleteIndexesForBlocks(fileSuffixNum int, blockIdxInfos []*blockIdxInfo, batch *leveldbhelper.UpdateBatch) error
+	exportTxIDs(writer blkstorage.TxIDWriter) error
+	importSnapshotTxIDs(reader blkstorage.TxIDReader) error
 }
 
 type blockIdxInfo struct {
@@ -204,7 +214,7 @@ func (index *blockIndex) getTxLoc(txID string) (*fileLocPointer, error) {
 		return nil, err
 	}
 	if b == nil {
-		return nil, blkstorage.ErrNotFoundInIndex
+		return nil, index.txIDNotFoundErr(txID)
 	}
 	txFLP := &fileLocPointer{}
 	txFLP.unmarshal(b)
@@ -220,7 +230,7 @@ func (index *blockIndex) getBlockLocByTxID(txID string) (*fileLocPointer, error)
 		return nil, err
 	}
 	if b == nil {
-		return nil, blkstorage.ErrNotFoundInIndex
+		return nil, index.txIDNotFoundErr(txID)
 	}
 	txFLP := &fileLocPointer{}
 	txFLP.unmarshal(b)
@@ -249,6 +259,9 @@ func (index *blockIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidati
 	}
 
 	raw, err := index.db.Get(constructTxValidationCodeIDKey(txID))
+	if err == nil && raw == nil {
+		raw, err = index.db.Get(constructSnapshotTxIDKey(txID))
+	}
 
 	if err != nil {
 		return peer.TxValidationCode(-1), err
@@ -263,6 +276,182 @@ func (index *blockIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidati
 	return result, nil
 }
 
+// deleteIndexesForBlocks adds to the batch the removal of all the index entries of the given blocks,
+// all of which are stored in the block file identified by `fileSuffixNum`
+func (index *blockIndex) deleteIndexesForBlocks(fileSuffixNum int, blockIdxInfos []*blockIdxInfo,
+	batch *leveldbhelper.UpdateBatch) error {
+	for _, blockIdxInfo := range blockIdxInfos {
+		batch.Delete(constructBlockHashKey(blockIdxInfo.blockHash))
+		batch.Delete(constructBlockNumKey(blockIdxInfo.blockNum))
+		for txIterator, txoffset := range blockIdxInfo.txOffsets {
+			batch.Delete(constructBlockNumTranNumKey(blockIdxInfo.blockNum, uint64(txIterator)))
+			// A transaction re-using the txID in a later block overwrites the txID based entries,
+			// so remove these entries only if they still point into the file being pruned
+			indexedInFile, err := index.isTxIndexedInFile(txoffset.txID, fileSuffixNum)
+			if err != nil {
+				return err
+			}
+			if !indexedInFile {
+				continue
+			}
+			batch.Delete(constructTxIDKey(txoffset.txID))
+			batch.Delete(constructBlockTxIDKey(txoffset.txID))
+			batch.Delete(constructTxValidationCodeIDKey(txoffset.txID))
+		}
+	}
+	return nil
+}
+
+// txIDNotFoundErr returns the error for a transaction ID missing from the index: ErrBlockPruned if the
+// transaction is one of the transactions imported from a snapshot, whose blocks are not available
+func (index *blockIndex) txIDNotFoundErr(txID string) error {
+	b, err := index.db.Get(constructSnapshotTxIDKey(txID))
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return blkstorage.ErrNotFoundInIndex
+	}
+	return blkstorage.ErrBlockPruned
+}
+
+// exportTxIDs writes the ID and the validation code of every transaction indexed, followed by the
+// transactions imported from a snapshot, unless a later transaction re-using their ID has been indexed
+func (index *blockIndex) exportTxIDs(writer blkstorage.TxIDWriter) error {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxValidationCode]; !ok {
+		return blkstorage.ErrAttrNotIndexed
+	}
+	if err := index.exportTxIDsWithPrefix(txValidationResultIdxKeyPrefix, writer); err != nil {
+		return err
+	}
+	return index.exportTxIDsWithPrefix(snapshotTxIDIdxKeyPrefix, writer)
+}
+
+func (index *blockIndex) exportTxIDsWithPrefix(prefix byte, writer blkstorage.TxIDWriter) error {
+	itr := index.db.GetIterator([]byte{prefix}, []byte{prefix + 1})
+	defer itr.Release()
+	for itr.Next() {
+		txID := string(itr.Key()[1:])
+		if prefix == snapshotTxIDIdxKeyPrefix {
+			b, err := index.db.Get(constructTxValidationCodeIDKey(txID))
+			if err != nil {
+				return err
+			}
+			if b != nil {
+				continue
+			}
+		}
+		raw := itr.Value()
+		if len(raw) != 1 {
+			return errors.New("Invalid value in indexItems")
+		}
+		if err := writer.WriteTxID(txID, peer.TxValidationCode(int32(raw[0]))); err != nil {
+			return err
+		}
+	}
+	return itr.Error()
+}
+
+// importSnapshotTxIDs indexes the IDs of the transactions of the snapshot that the block storage is
+// bootstrapped from, replacing the IDs left over by an interrupted bootstrap, if any
+func (index *blockIndex) importSnapshotTxIDs(reader blkstorage.TxIDReader) error {
+	batch := leveldbhelper.NewUpdateBatch()
+	itr := index.db.GetIterator([]byte{snapshotTxIDIdxKeyPrefix}, []byte{snapshotTxIDIdxKeyPrefix + 1})
+	for itr.Next() {
+		batch.Delete(itr.Key())
+	}
+	itr.Release()
+	if err := itr.Error(); err != nil {
+		return err
+	}
+
+	numTxIDs := 0
+	for {
+		txID, validationCode, err := reader.ReadTxID()
+		if err != nil {
+			return err
+		}
+		if txID == "" {
+			break
+		}
+		batch.Put(constructSnapshotTxIDKey(txID), []byte{byte(validationCode)})
+		numTxIDs++
+		if numTxIDs%maxSnapshotTxIDsBatchSize == 0 {
+			if err = index.db.WriteBatch(batch, true); err != nil {
+				return err
+			}
+			batch = leveldbhelper.NewUpdateBatch()
+		}
+	}
+	logger.Debugf("Imported [%d] transaction IDs from the snapshot", numTxIDs)
+	return index.db.WriteBatch(batch, true)
+}
+
+// dropIndex removes the index entries derived from the blocks, so that `syncIndex` rebuilds them from the block
+// files. The index checkpoint is removed first so that an interrupted drop still leads to a rebuild. The entries
+// that cannot be derived from the block files, i.e. the config blocks preserved by pruning and the transaction IDs
+// of a snapshot, are kept along with the checkpoint info and the prune info
+func dropIndex(db *leveldbhelper.DBHandle) error {
+	if err := db.Delete(indexCheckpointKey, true); err != nil {
+		return err
+	}
+	prefixes := []byte{blockNumIdxKeyPrefix, blockHashIdxKeyPrefix, txIDIdxKeyPrefix,
+		blockNumTranNumIdxKeyPrefix, blockTxIDIdxKeyPrefix, txValidationResultIdxKeyPrefix}
+	for _, prefix := range prefixes {
+		if err := dropIndexEntriesWithPrefix(db, prefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropIndexEntriesWithPrefix(db *leveldbhelper.DBHandle, prefix byte) error {
+	itr := db.GetIterator([]byte{prefix}, []byte{prefix + 1})
+	defer itr.Release()
+	batch := leveldbhelper.NewUpdateBatch()
+	for itr.Next() {
+		key := itr.Key()
+		// the key of the checkpoint info shares its first byte with the keys of an index
+		if bytes.Equal(key, blkMgrInfoKey) {
+			continue
+		}
+		batch.Delete(key)
+		if len(batch.KVs) < maxDropIndexBatchSize {
+			continue
+		}
+		if err := db.WriteBatch(batch, false); err != nil {
+			return err
+		}
+		batch = leveldbhelper.NewUpdateBatch()
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	return db.WriteBatch(batch, true)
+}
+
+func (index *blockIndex) isTxIndexedInFile(txID string, fileSuffixNum int) (bool, error) {
+	var b []byte
+	var err error
+	switch {
+	case index.indexItemsMap[blkstorage.IndexableAttrTxID]:
+		b, err = index.db.Get(constructTxIDKey(txID))
+	case index.indexItemsMap[blkstorage.IndexableAttrBlockTxID]:
+		b, err = index.db.Get(constructBlockTxIDKey(txID))
+	default:
+		// no location is indexed by txID, nothing can point elsewhere
+		return true, nil
+	}
+	if err != nil || b == nil {
+		return false, err
+	}
+	flp := &fileLocPointer{}
+	if err = flp.unmarshal(b); err != nil {
+		return false, err
+	}
+	return flp.fileSuffixNum == fileSuffixNum, nil
+}
+
 func constructBlockNumKey(blockNum uint64) []byte {
 	blkNumBytes := util.EncodeOrderPreservingVarUint64(blockNum)
 	return append([]byte{blockNumIdxKeyPrefix}, blkNumBytes...)
@@ -284,6 +473,10 @@ func constructTxValidationCodeIDKey(txID string) []byte {
 	return append([]byte{txValidationResultIdxKeyPrefix}, []byte(txID)...)
 }
 
+func constructSnapshotTxIDKey(txID string) []byte {
+	return append([]byte{snapshotTxIDIdxKeyPrefix}, []byte(txID)...)
+}
+
 func constructBlockNumTranNumKey(blockNum uint64, txNum uint64) []byte {
 	blkNumBytes := util.EncodeOrderPreservingVarUint64(blockNum)
 	tranNumBytes := util.EncodeOrderPreservingVarUint64(txNum)
@@ -291,6 +484,11 @@ func constructBlockNumTranNumKey(blockNum uint64, txNum uint64) []byte {
 	return append([]byte{blockNumTranNumIdxKeyPrefix}, key...)
 }
 
+func constructPrunedConfigBlockKey(blockNum uint64) []byte {
+	blkNumBytes := util.EncodeOrderPreservingVarUint64(blockNum)
+	return append([]byte{prunedConfigBlockKeyPrefix}, blkNumBytes...)
+}
+
 func encodeBlockNum(blockNum uint64) []byte {
 	return proto.EncodeVarint(blockNum)
 }
@@ -22,6 +22,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
@@ -58,6 +59,18 @@ func (i *noopIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidationCod
 	return peer.TxValidationCode(-1), nil
 }
 
+func (i *noopIndex) deleteIndexesForBlocks(fileSuffixNum int, blockIdxInfos []*blockIdxInfo, batch *leveldbhelper.UpdateBatch) error {
+	return nil
+}
+
+func (i *noopIndex) exportTxIDs(writer blkstorage.TxIDWriter) error {
+	return nil
+}
+
+func (i *noopIndex) importSnapshotTxIDs(reader blkstorage.TxIDReader) error {
+	return nil
+}
+
 func TestBlockIndexSync(t *testing.T) {
 	testBlockIndexSync(t, 10, 5, false)
 	testBlockIndexSync(t, 10, 5, true)
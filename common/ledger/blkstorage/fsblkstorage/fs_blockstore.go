@@ -86,6 +86,28 @@ func (store *fsBlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxV
 	return store.fileMgr.retrieveTxValidationCodeByTxID(txID)
 }
 
+// Prune removes the blocks that precede the block `retainFrom`. See `blockfileMgr.prune` for the details
+func (store *fsBlockStore) Prune(retainFrom uint64) error {
+	return store.fileMgr.prune(retainFrom)
+}
+
+// FirstUnprunedBlockNumber returns the number of the first block that has not been pruned
+func (store *fsBlockStore) FirstUnprunedBlockNumber() (uint64, error) {
+	return store.fileMgr.getPruneInfo().firstBlockNumber, nil
+}
+
+// ExportTxIDs writes the IDs of the transactions stored, including the ones imported from a snapshot
+func (store *fsBlockStore) ExportTxIDs(writer blkstorage.TxIDWriter) error {
+	return store.fileMgr.exportTxIDs(writer)
+}
+
+// BootstrapFromSnapshot starts the empty block store from the last block of a snapshot.
+// See `blockfileMgr.bootstrapFromSnapshot` for the details
+func (store *fsBlockStore) BootstrapFromSnapshot(lastBlock *common.Block, lastConfigBlock *common.Block,
+	txIDs blkstorage.TxIDReader) error {
+	return store.fileMgr.bootstrapFromSnapshot(lastBlock, lastConfigBlock, txIDs)
+}
+
 // Shutdown shuts down the block store
 func (store *fsBlockStore) Shutdown() {
 	logger.Debugf("closing fs blockStore:%s", store.id)
@@ -17,6 +17,8 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -59,6 +61,56 @@ func (p *FsBlockstoreProvider) List() ([]string, error) {
 	return util.ListSubdirs(p.conf.getChainsDir())
 }
 
+// IsPruned implements the corresponding method from interface blkstorage.BlockStoreProvider
+func (p *FsBlockstoreProvider) IsPruned(ledgerid string) (bool, error) {
+	pInfoBytes, err := p.leveldbProvider.GetDBHandle(ledgerid).Get(pruneInfoKey)
+	if err != nil {
+		return false, err
+	}
+	pInfo := &pruneInfo{}
+	if pInfoBytes != nil {
+		if err = pInfo.unmarshal(pInfoBytes); err != nil {
+			return false, err
+		}
+		return pInfo.firstBlockNumber != 0, nil
+	}
+	// the prune info is not saved until the blocks get pruned, it is derived from the block files till then
+	exists, err := p.Exists(ledgerid)
+	if err != nil || !exists {
+		return false, err
+	}
+	if pInfo, err = constructPruneInfoFromBlockFiles(p.conf.getLedgerBlockDir(ledgerid)); err != nil {
+		return false, err
+	}
+	return pInfo.firstBlockNumber != 0, nil
+}
+
+// Rollback implements the corresponding method from interface blkstorage.BlockStoreProvider
+// The block files are truncated and the index is rebuilt by opening the block store again once the rollback is recorded
+func (p *FsBlockstoreProvider) Rollback(ledgerid string, blockNum uint64) error {
+	exists, err := p.Exists(ledgerid)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("Block store of ledger [%s] does not exist", ledgerid)
+	}
+	indexStoreHandle := p.leveldbProvider.GetDBHandle(ledgerid)
+	mgr := newBlockfileMgr(ledgerid, p.conf, p.indexConfig, indexStoreHandle)
+	err = mgr.rollback(blockNum)
+	mgr.close()
+	if err != nil {
+		return err
+	}
+	newBlockfileMgr(ledgerid, p.conf, p.indexConfig, indexStoreHandle).close()
+	return nil
+}
+
+// DropIndex implements the corresponding method from interface blkstorage.BlockStoreProvider
+func (p *FsBlockstoreProvider) DropIndex(ledgerid string) error {
+	return dropIndex(p.leveldbProvider.GetDBHandle(ledgerid))
+}
+
 // Close closes the FsBlockstoreProvider
 func (p *FsBlockstoreProvider) Close() {
 	p.leveldbProvider.Close()
@@ -147,6 +147,66 @@ func TestBlockStoreProvider(t *testing.T) {
 
 }
 
+func TestBlockStoreProviderDropIndex(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+
+	provider := env.provider
+	store, _ := provider.OpenBlockStore("ledger1")
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	for _, b := range blocks {
+		store.AddBlock(b)
+	}
+	store.Shutdown()
+
+	testutil.AssertNoError(t, provider.DropIndex("ledger1"), "Error while dropping index")
+	db := provider.leveldbProvider.GetDBHandle("ledger1")
+	checkpoint, _ := db.Get(indexCheckpointKey)
+	testutil.AssertNil(t, checkpoint)
+	hashKey, _ := db.Get(constructBlockHashKey(blocks[2].Header.Hash()))
+	testutil.AssertNil(t, hashKey)
+
+	// the index is rebuilt from the block files when the block store is opened
+	store, _ = provider.OpenBlockStore("ledger1")
+	defer store.Shutdown()
+	checkBlocks(t, blocks, store)
+}
+
+func TestBlockStoreProviderIsPruned(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 1))
+	defer env.Cleanup()
+
+	provider := env.provider
+	pruned, err := provider.IsPruned("unknownLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, pruned, false)
+
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	for _, ledgerid := range []string{"ledger1", "ledger2"} {
+		store, _ := provider.OpenBlockStore(ledgerid)
+		for _, b := range blocks {
+			store.AddBlock(b)
+		}
+		store.Shutdown()
+	}
+	store, _ := provider.OpenBlockStore("ledger2")
+	firstBlockNum, err := store.FirstUnprunedBlockNumber()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, firstBlockNum, uint64(0))
+	testutil.AssertNoError(t, store.(*fsBlockStore).fileMgr.prune(6), "Error while pruning blocks")
+	firstBlockNum, err = store.FirstUnprunedBlockNumber()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, firstBlockNum, uint64(6))
+	store.Shutdown()
+
+	pruned, err = provider.IsPruned("ledger1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, pruned, false)
+	pruned, err = provider.IsPruned("ledger2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, pruned, true)
+}
+
 func constructLedgerid(id int) string {
 	return fmt.Sprintf("ledger_%d", id)
 }
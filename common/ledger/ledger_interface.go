@@ -17,6 +17,8 @@ limitations under the License.
 package ledger
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric/protos/common"
 )
 
@@ -51,3 +53,14 @@ type QueryResult interface{}
 
 // PrunePolicy - a general interface for supporting different pruning policies
 type PrunePolicy interface{}
+
+// PruneByBlockCount is a `PrunePolicy` that retains only the latest `RetainBlocks` blocks of the ledger
+type PruneByBlockCount struct {
+	RetainBlocks uint64
+}
+
+// PruneByTimestamp is a `PrunePolicy` that retains only the blocks whose transactions were created
+// at or after `RetainSince`
+type PruneByTimestamp struct {
+	RetainSince time.Time
+}
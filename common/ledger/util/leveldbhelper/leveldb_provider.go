@@ -27,6 +27,9 @@ import (
 var dbNameKeySep = []byte{0x00}
 var lastKeyIndicator = byte(0x01)
 
+// maxDeleteAllBatchSize is the number of keys removed in a single batch by DBHandle.DeleteAll
+const maxDeleteAllBatchSize = 1000
+
 // Provider enables to use a single leveldb as multiple logical leveldbs
 type Provider struct {
 	db        *DB
@@ -96,6 +99,28 @@ func (h *DBHandle) WriteBatch(batch *UpdateBatch, sync bool) error {
 	return nil
 }
 
+// DeleteAll deletes all the keys of the named db. The deletions are written in several batches,
+// the db is left partially cleared if an error occurs
+func (h *DBHandle) DeleteAll() error {
+	itr := h.GetIterator(nil, nil)
+	defer itr.Release()
+	batch := NewUpdateBatch()
+	for itr.Next() {
+		batch.Delete(itr.Key())
+		if len(batch.KVs) < maxDeleteAllBatchSize {
+			continue
+		}
+		if err := h.WriteBatch(batch, false); err != nil {
+			return err
+		}
+		batch = NewUpdateBatch()
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	return h.WriteBatch(batch, true)
+}
+
 // GetIterator gets an handle to iterator. The iterator should be released after the use.
 // The resultset contains all the keys that are present in the db between the startKey (inclusive) and the endKey (exclusive).
 // A nil startKey represents the first available key and a nil endKey represent a logical key after the last available key
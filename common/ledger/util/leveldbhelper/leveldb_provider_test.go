@@ -54,6 +54,28 @@ func TestIterator(t *testing.T) {
 	checkItrResults(t, itr3, createTestKeys(0, 19), createTestValues("db2", 0, 19))
 }
 
+func TestDeleteAll(t *testing.T) {
+	env := newTestProviderEnv(t, testDBPath)
+	defer env.cleanup()
+	p := env.provider
+
+	db1 := p.GetDBHandle("db1")
+	db10 := p.GetDBHandle("db10")
+	for i := 0; i < maxDeleteAllBatchSize+10; i++ {
+		db1.Put([]byte(createTestKey(i)), []byte(createTestValue("db1", i)), false)
+		db10.Put([]byte(createTestKey(i)), []byte(createTestValue("db10", i)), false)
+	}
+	testutil.AssertNoError(t, db1.DeleteAll(), "")
+
+	itr1 := db1.GetIterator(nil, nil)
+	defer itr1.Release()
+	checkItrResults(t, itr1, nil, nil)
+	// a db whose name starts with the name of the db cleared is not affected
+	itr10 := db10.GetIterator(nil, nil)
+	defer itr10.Release()
+	checkItrResults(t, itr10, createTestKeys(0, maxDeleteAllBatchSize+9), createTestValues("db10", 0, maxDeleteAllBatchSize+9))
+}
+
 func TestBatchedUpdates(t *testing.T) {
 	env := newTestProviderEnv(t, testDBPath)
 	defer env.cleanup()
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the Prometheus text format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// Write writes the metrics of the registry in the Prometheus text format,
+// ordered by name
+func (r *Registry) Write(w io.Writer) error {
+	r.lock.RLock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.lock.RUnlock()
+	sort.Sort(familiesByName(families))
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP serves the metrics of the registry in the Prometheus text format
+func (r *Registry) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	buf := &bytes.Buffer{}
+	if err := r.Write(buf); err != nil {
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", ContentType)
+	resp.Write(buf.Bytes())
+}
+
+type familiesByName []*family
+
+func (f familiesByName) Len() int           { return len(f) }
+func (f familiesByName) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f familiesByName) Less(i, j int) bool { return f[i].name < f[j].name }
+
+func (f *family) write(w *bufio.Writer) {
+	// the series are copied so that the lock is not held while writing
+	f.lock.Lock()
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	allSeries := make([]series, 0, len(keys))
+	for _, key := range keys {
+		s := *f.series[key]
+		s.bucketCounts = append([]uint64(nil), s.bucketCounts...)
+		allSeries = append(allSeries, s)
+	}
+	f.lock.Unlock()
+
+	if len(allSeries) == 0 {
+		return
+	}
+	if f.help != "" {
+		fmt.Fprintf(w, "# HELP %s %s\n", f.name, helpEscaper.Replace(f.help))
+	}
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.metricType)
+	for _, s := range allSeries {
+		if f.metricType != histogramType {
+			writeSample(w, f.name, f.labelNames, s.labelValues, "", "", s.value)
+			continue
+		}
+		var cumulativeCount uint64
+		for i, upperBound := range f.buckets {
+			cumulativeCount += s.bucketCounts[i]
+			writeSample(w, f.name+"_bucket", f.labelNames, s.labelValues, "le", formatFloat(upperBound), float64(cumulativeCount))
+		}
+		writeSample(w, f.name+"_bucket", f.labelNames, s.labelValues, "le", "+Inf", float64(s.count))
+		writeSample(w, f.name+"_sum", f.labelNames, s.labelValues, "", "", s.sum)
+		writeSample(w, f.name+"_count", f.labelNames, s.labelValues, "", "", float64(s.count))
+	}
+}
+
+// writeSample writes a line of the text format, with an optional extra label
+func writeSample(w *bufio.Writer, name string, labelNames, labelValues []string, extraLabelName, extraLabelValue string, value float64) {
+	w.WriteString(name)
+	if len(labelNames) > 0 || extraLabelName != "" {
+		w.WriteByte('{')
+		for i, labelName := range labelNames {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, `%s="%s"`, labelName, labelValueEscaper.Replace(labelValues[i]))
+		}
+		if extraLabelName != "" {
+			if len(labelNames) > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, `%s="%s"`, extraLabelName, extraLabelValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,279 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package metrics provides the counters, gauges and histograms with which
+// the components of the peer and of the orderer are instrumented. The metrics
+// are kept in a Registry, which exposes them in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the default histogram buckets, suited to the
+// measure of durations in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// DefaultRegistry is the registry of the metrics created by the
+// package level functions NewCounter, NewGauge and NewHistogram
+var DefaultRegistry = NewRegistry()
+
+var (
+	metricNameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRegexp  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+const (
+	counterType   = "counter"
+	gaugeType     = "gauge"
+	histogramType = "histogram"
+)
+
+// CounterOpts contains the options of a counter. The name of the metric
+// is made of the Namespace, the Subsystem and the Name, separated by
+// underscores
+type CounterOpts struct {
+	Namespace  string
+	Subsystem  string
+	Name       string
+	Help       string
+	LabelNames []string
+}
+
+// GaugeOpts contains the options of a gauge
+type GaugeOpts struct {
+	Namespace  string
+	Subsystem  string
+	Name       string
+	Help       string
+	LabelNames []string
+}
+
+// HistogramOpts contains the options of a histogram. The DefaultBuckets
+// are used unless Buckets, the upper bounds in increasing order, are set
+type HistogramOpts struct {
+	Namespace  string
+	Subsystem  string
+	Name       string
+	Help       string
+	LabelNames []string
+	Buckets    []float64
+}
+
+// Counter is a metric which only goes up
+type Counter struct {
+	family      *family
+	labelValues []string
+}
+
+// With returns the counter for the given values of the labels, in the order of the
+// LabelNames of the counter. The values may be given over several calls to With
+func (c *Counter) With(labelValues ...string) *Counter {
+	return &Counter{family: c.family, labelValues: appendLabelValues(c.labelValues, labelValues)}
+}
+
+// Add increments the counter by the given non-negative delta
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		panic(fmt.Sprintf("counter %s cannot be decremented", c.family.name))
+	}
+	c.family.update(c.labelValues, func(s *series) { s.value += delta })
+}
+
+// Gauge is a metric which may go up and down
+type Gauge struct {
+	family      *family
+	labelValues []string
+}
+
+// With returns the gauge for the given values of the labels, in the order of the
+// LabelNames of the gauge. The values may be given over several calls to With
+func (g *Gauge) With(labelValues ...string) *Gauge {
+	return &Gauge{family: g.family, labelValues: appendLabelValues(g.labelValues, labelValues)}
+}
+
+// Add adds the given delta, which may be negative, to the gauge
+func (g *Gauge) Add(delta float64) {
+	g.family.update(g.labelValues, func(s *series) { s.value += delta })
+}
+
+// Set sets the gauge to the given value
+func (g *Gauge) Set(value float64) {
+	g.family.update(g.labelValues, func(s *series) { s.value = value })
+}
+
+// Histogram counts the observations in buckets and keeps their sum
+type Histogram struct {
+	family      *family
+	labelValues []string
+}
+
+// With returns the histogram for the given values of the labels, in the order of the
+// LabelNames of the histogram. The values may be given over several calls to With
+func (h *Histogram) With(labelValues ...string) *Histogram {
+	return &Histogram{family: h.family, labelValues: appendLabelValues(h.labelValues, labelValues)}
+}
+
+// Observe adds an observation to the histogram
+func (h *Histogram) Observe(value float64) {
+	buckets := h.family.buckets
+	// the index of the first bucket whose upper bound is greater or equal to the value
+	i := sort.SearchFloat64s(buckets, value)
+	h.family.update(h.labelValues, func(s *series) {
+		if i < len(buckets) {
+			s.bucketCounts[i]++
+		}
+		s.count++
+		s.sum += value
+	})
+}
+
+// Registry keeps a set of metrics with distinct names
+type Registry struct {
+	lock     sync.RWMutex
+	families map[string]*family
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// NewCounter creates a counter in the registry. It panics if the options are invalid
+// or if the registry already holds a metric with the same name
+func (r *Registry) NewCounter(opts CounterOpts) *Counter {
+	f := r.register(newFamily(counterType, opts.Namespace, opts.Subsystem, opts.Name, opts.Help, opts.LabelNames, nil))
+	return &Counter{family: f}
+}
+
+// NewGauge creates a gauge in the registry. It panics if the options are invalid
+// or if the registry already holds a metric with the same name
+func (r *Registry) NewGauge(opts GaugeOpts) *Gauge {
+	f := r.register(newFamily(gaugeType, opts.Namespace, opts.Subsystem, opts.Name, opts.Help, opts.LabelNames, nil))
+	return &Gauge{family: f}
+}
+
+// NewHistogram creates a histogram in the registry. It panics if the options are invalid
+// or if the registry already holds a metric with the same name
+func (r *Registry) NewHistogram(opts HistogramOpts) *Histogram {
+	buckets := opts.Buckets
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("the buckets of histogram %s are not in increasing order", opts.Name))
+	}
+	f := r.register(newFamily(histogramType, opts.Namespace, opts.Subsystem, opts.Name, opts.Help, opts.LabelNames, buckets))
+	return &Histogram{family: f}
+}
+
+func (r *Registry) register(f *family) *family {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, exists := r.families[f.name]; exists {
+		panic(fmt.Sprintf("metric %s is already registered", f.name))
+	}
+	r.families[f.name] = f
+	return f
+}
+
+// NewCounter creates a counter in the DefaultRegistry
+func NewCounter(opts CounterOpts) *Counter {
+	return DefaultRegistry.NewCounter(opts)
+}
+
+// NewGauge creates a gauge in the DefaultRegistry
+func NewGauge(opts GaugeOpts) *Gauge {
+	return DefaultRegistry.NewGauge(opts)
+}
+
+// NewHistogram creates a histogram in the DefaultRegistry
+func NewHistogram(opts HistogramOpts) *Histogram {
+	return DefaultRegistry.NewHistogram(opts)
+}
+
+// family holds the series of a metric, one for each combination of label values
+type family struct {
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+	buckets    []float64
+
+	lock   sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	// the number of observations of each bucket of a histogram, not cumulated
+	bucketCounts []uint64
+	count        uint64
+	sum          float64
+}
+
+func newFamily(metricType, namespace, subsystem, name, help string, labelNames []string, buckets []float64) *family {
+	var nameParts []string
+	for _, part := range []string{namespace, subsystem, name} {
+		if part != "" {
+			nameParts = append(nameParts, part)
+		}
+	}
+	fullName := strings.Join(nameParts, "_")
+	if !metricNameRegexp.MatchString(fullName) {
+		panic(fmt.Sprintf("invalid metric name [%s]", fullName))
+	}
+	for _, labelName := range labelNames {
+		if !labelNameRegexp.MatchString(labelName) || strings.HasPrefix(labelName, "__") ||
+			(metricType == histogramType && labelName == "le") {
+			panic(fmt.Sprintf("invalid label name [%s] of metric %s", labelName, fullName))
+		}
+	}
+
+	f := &family{
+		name:       fullName,
+		help:       help,
+		metricType: metricType,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*series),
+	}
+	if len(labelNames) == 0 {
+		// a metric without labels is exposed from the start
+		f.update(nil, func(*series) {})
+	}
+	return f
+}
+
+func (f *family) update(labelValues []string, updateFunc func(s *series)) {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", f.name, len(f.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: labelValues}
+		if f.metricType == histogramType {
+			s.bucketCounts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	updateFunc(s)
+}
+
+func appendLabelValues(labelValues, newLabelValues []string) []string {
+	values := make([]string, 0, len(labelValues)+len(newLabelValues))
+	values = append(values, labelValues...)
+	return append(values, newLabelValues...)
+}
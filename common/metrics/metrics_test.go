@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterAndGauge(t *testing.T) {
+	r := NewRegistry()
+	counter := r.NewCounter(CounterOpts{Namespace: "endorser", Name: "proposals", Help: "The number of proposals.", LabelNames: []string{"channel", "status"}})
+	counter.With("mychannel", "200").Add(1)
+	counter.With("mychannel").With("200").Add(2)
+	counter.With("my\"channel\n", "500").Add(1)
+	assert.Panics(t, func() { counter.With("mychannel").Add(1) }, "a label value is missing")
+	assert.Panics(t, func() { counter.With("mychannel", "200").Add(-1) }, "a counter cannot be decremented")
+
+	gauge := r.NewGauge(GaugeOpts{Namespace: "gossip", Subsystem: "membership", Name: "peers", Help: "The number of peers.\nAlive ones only."})
+	gauge.Set(5)
+	gauge.Add(-2)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, r.Write(buf))
+	assert.Equal(t, `# HELP endorser_proposals The number of proposals.
+# TYPE endorser_proposals counter
+endorser_proposals{channel="my\"channel\n",status="500"} 1
+endorser_proposals{channel="mychannel",status="200"} 3
+# HELP gossip_membership_peers The number of peers.\nAlive ones only.
+# TYPE gossip_membership_peers gauge
+gossip_membership_peers 3
+`, buf.String())
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	histogram := r.NewHistogram(HistogramOpts{Name: "batch_size", Buckets: []float64{1, 10}, LabelNames: []string{"channel"}})
+
+	// a metric with labels is exposed once it has a series
+	buf := &bytes.Buffer{}
+	assert.NoError(t, r.Write(buf))
+	assert.Empty(t, buf.String())
+
+	for _, v := range []float64{0.5, 1, 5, 20} {
+		histogram.With("mychannel").Observe(v)
+	}
+	assert.NoError(t, r.Write(buf))
+	assert.Equal(t, `# TYPE batch_size histogram
+batch_size_bucket{channel="mychannel",le="1"} 2
+batch_size_bucket{channel="mychannel",le="10"} 3
+batch_size_bucket{channel="mychannel",le="+Inf"} 4
+batch_size_sum{channel="mychannel"} 26.5
+batch_size_count{channel="mychannel"} 4
+`, buf.String())
+
+	durations := r.NewHistogram(HistogramOpts{Name: "duration"})
+	durations.Observe(0.2)
+	buf.Reset()
+	assert.NoError(t, r.Write(buf))
+	assert.Contains(t, buf.String(), "duration_bucket{le=\"0.25\"} 1\n")
+	assert.Contains(t, buf.String(), "duration_bucket{le=\"0.1\"} 0\n")
+}
+
+func TestRegistryErrors(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter(CounterOpts{Name: "total"})
+	assert.Panics(t, func() { r.NewGauge(GaugeOpts{Name: "total"}) }, "the name is already registered")
+	assert.Panics(t, func() { r.NewCounter(CounterOpts{Name: "bad-name"}) })
+	assert.Panics(t, func() { r.NewCounter(CounterOpts{Name: "c", LabelNames: []string{"__reserved"}}) })
+	assert.Panics(t, func() { r.NewHistogram(HistogramOpts{Name: "h", LabelNames: []string{"le"}}) })
+	assert.Panics(t, func() { r.NewHistogram(HistogramOpts{Name: "h", Buckets: []float64{2, 1}}) })
+}
+
+func TestServeHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter(CounterOpts{Name: "requests"}).Add(2)
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, ContentType, resp.Header().Get("Content-Type"))
+	assert.Equal(t, "# TYPE requests counter\nrequests 2\n", resp.Body.String())
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/fabric/common/ledger"
+	coreledger "github.com/hyperledger/fabric/core/ledger"
 )
 
 type MockQueryExecutor struct {
@@ -42,6 +43,14 @@ func (m *MockQueryExecutor) GetState(namespace string, key string) ([]byte, erro
 	return ns[key], nil
 }
 
+func (m *MockQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	return nil, nil
+}
+
+func (m *MockQueryExecutor) GetPrivateData(namespace, collection, key string) ([]byte, error) {
+	return nil, nil
+}
+
 func (m *MockQueryExecutor) GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
 	return nil, nil
 
@@ -56,6 +65,14 @@ func (m *MockQueryExecutor) ExecuteQuery(namespace, query string) (ledger.Result
 	return nil, nil
 }
 
+func (m *MockQueryExecutor) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (coreledger.QueryResultsIterator, error) {
+	return nil, nil
+}
+
+func (m *MockQueryExecutor) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (coreledger.QueryResultsIterator, error) {
+	return nil, nil
+}
+
 func (m *MockQueryExecutor) Done() {
 
 }
@@ -54,6 +54,35 @@ func TestNewCA(t *testing.T) {
 
 }
 
+func TestNewCAWithKeyAlgorithm(t *testing.T) {
+
+	for _, algorithm := range []string{"ED25519", "SM2"} {
+		caDir := filepath.Join(testDir, "ca")
+		certDir := filepath.Join(testDir, "certs")
+		rootCA, err := ca.NewCAWithKeyAlgorithm(caDir, testCAName, testCAName, algorithm)
+		assert.NoError(t, err, "Error generating CA")
+		assert.Equal(t, algorithm, rootCA.KeyAlgorithm)
+		assert.Equal(t, true, rootCA.SignCert.IsCA)
+
+		priv, _, err := csp.GeneratePrivateKeyWithAlgorithm(certDir, algorithm)
+		assert.NoError(t, err, "Failed to generate signed certificate")
+		pubKey, err := csp.GetPublicKey(priv)
+		assert.NoError(t, err, "Failed to generate signed certificate")
+
+		cert, err := rootCA.SignCertificate(certDir, testName, nil, pubKey,
+			x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
+		assert.NoError(t, err, "Failed to generate signed certificate")
+		assert.Equal(t, pubKey, cert.PublicKey)
+		assert.Equal(t, rootCA.SignCert.RawSubject, cert.RawIssuer)
+		cleanup(testDir)
+	}
+
+	_, err := ca.NewCAWithKeyAlgorithm(filepath.Join(testDir, "ca"), testCAName, testCAName, "DSA")
+	assert.Error(t, err, "Unsupported algorithm should fail")
+	cleanup(testDir)
+
+}
+
 func TestGenerateSignCertificate(t *testing.T) {
 
 	caDir := filepath.Join(testDir, "ca")
@@ -17,7 +17,6 @@ package ca
 
 import (
 	"crypto"
-	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -28,7 +27,10 @@ import (
 
 	"path/filepath"
 
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/utils"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/csp"
+	"github.com/tjfoc/gmsm/sm2"
 )
 
 type CA struct {
@@ -36,22 +38,31 @@ type CA struct {
 	//SignKey  *ecdsa.PrivateKey
 	Signer   crypto.Signer
 	SignCert *x509.Certificate
+	// KeyAlgorithm is the algorithm of the signing key, which is also used
+	// for the keys of the identities issued by the CA
+	KeyAlgorithm string
 }
 
-// NewCA creates an instance of CA and saves the signing key pair in
-// baseDir/name
+// NewCA creates an instance of CA with an ECDSA signing key and saves the
+// signing key pair in baseDir/name
 func NewCA(baseDir, org, name string) (*CA, error) {
+	return NewCAWithKeyAlgorithm(baseDir, org, name, bccsp.ECDSA)
+}
+
+// NewCAWithKeyAlgorithm creates an instance of CA with a signing key of the
+// given algorithm and saves the signing key pair in baseDir/name
+func NewCAWithKeyAlgorithm(baseDir, org, name, algorithm string) (*CA, error) {
 
 	var response error
 	var ca *CA
 
 	err := os.MkdirAll(baseDir, 0755)
 	if err == nil {
-		priv, signer, err := csp.GeneratePrivateKey(baseDir)
+		priv, signer, err := csp.GeneratePrivateKeyWithAlgorithm(baseDir, algorithm)
 		response = err
 		if err == nil {
 			// get public signing certificate
-			ecPubKey, err := csp.GetECPublicKey(priv)
+			pubKey, err := csp.GetPublicKey(priv)
 			response = err
 			if err == nil {
 				template := x509Template()
@@ -70,14 +81,15 @@ func NewCA(baseDir, org, name string) (*CA, error) {
 				template.Subject = subject
 				template.SubjectKeyId = priv.SKI()
 
-				x509Cert, err := genCertificateECDSA(baseDir, name, &template, &template,
-					ecPubKey, signer)
+				x509Cert, err := genCertificate(baseDir, name, &template, &template,
+					pubKey, signer)
 				response = err
 				if err == nil {
 					ca = &CA{
-						Name:     name,
-						Signer:   signer,
-						SignCert: x509Cert,
+						Name:         name,
+						Signer:       signer,
+						SignCert:     x509Cert,
+						KeyAlgorithm: algorithm,
 					}
 				}
 			}
@@ -88,7 +100,7 @@ func NewCA(baseDir, org, name string) (*CA, error) {
 
 // SignCertificate creates a signed certificate based on a built-in template
 // and saves it in baseDir/name
-func (ca *CA) SignCertificate(baseDir, name string, sans []string, pub *ecdsa.PublicKey,
+func (ca *CA) SignCertificate(baseDir, name string, sans []string, pub crypto.PublicKey,
 	ku x509.KeyUsage, eku []x509.ExtKeyUsage) (*x509.Certificate, error) {
 
 	template := x509Template()
@@ -102,7 +114,7 @@ func (ca *CA) SignCertificate(baseDir, name string, sans []string, pub *ecdsa.Pu
 	template.Subject = subject
 	template.DNSNames = sans
 
-	cert, err := genCertificateECDSA(baseDir, name, &template, ca.SignCert,
+	cert, err := genCertificate(baseDir, name, &template, ca.SignCert,
 		pub, ca.Signer)
 
 	if err != nil {
@@ -144,12 +156,19 @@ func x509Template() x509.Certificate {
 
 }
 
-// generate a signed X509 certficate using ECDSA
-func genCertificateECDSA(baseDir, name string, template, parent *x509.Certificate, pub *ecdsa.PublicKey,
+// generate a signed X509 certficate. SM2 signers sign the certificate with
+// SM2, which the standard library does not support
+func genCertificate(baseDir, name string, template, parent *x509.Certificate, pub crypto.PublicKey,
 	priv interface{}) (*x509.Certificate, error) {
 
 	//create the x509 public cert
-	certBytes, err := x509.CreateCertificate(rand.Reader, template, parent, pub, priv)
+	var certBytes []byte
+	var err error
+	if signer, ok := priv.(crypto.Signer); ok && isSM2Signer(signer) {
+		certBytes, err = utils.CreateSM2Certificate(template, parent, pub, signer)
+	} else {
+		certBytes, err = x509.CreateCertificate(rand.Reader, template, parent, pub, priv)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -167,9 +186,14 @@ func genCertificateECDSA(baseDir, name string, template, parent *x509.Certificat
 		return nil, err
 	}
 
-	x509Cert, err := x509.ParseCertificate(certBytes)
+	x509Cert, err := utils.DERToX509Certificate(certBytes)
 	if err != nil {
 		return nil, err
 	}
 	return x509Cert, nil
 }
+
+func isSM2Signer(signer crypto.Signer) bool {
+	_, ok := signer.Public().(*sm2.PublicKey)
+	return ok
+}
@@ -19,15 +19,41 @@ import (
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/x509"
+	"fmt"
+	"strings"
 
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/bccsp/signer"
+	"github.com/hyperledger/fabric/bccsp/utils"
 )
 
-// GeneratePrivateKey creates a private key and stores it in keystorePath
+// KeyGenOpts returns the options to generate a key of the given algorithm,
+// which is one of ECDSA, ED25519 or SM2. ECDSA is used when the algorithm is
+// empty
+func KeyGenOpts(algorithm string, ephemeral bool) (bccsp.KeyGenOpts, error) {
+	switch strings.ToUpper(algorithm) {
+	case "", bccsp.ECDSA:
+		return &bccsp.ECDSAP256KeyGenOpts{Temporary: ephemeral}, nil
+	case bccsp.ED25519:
+		return &bccsp.ED25519KeyGenOpts{Temporary: ephemeral}, nil
+	case bccsp.SM2:
+		return &bccsp.SM2KeyGenOpts{Temporary: ephemeral}, nil
+	default:
+		return nil, fmt.Errorf("Unsupported key algorithm [%s]. Supported algorithms: [ECDSA, ED25519, SM2]", algorithm)
+	}
+}
+
+// GeneratePrivateKey creates an ECDSA private key and stores it in keystorePath
 func GeneratePrivateKey(keystorePath string) (bccsp.Key,
 	crypto.Signer, error) {
+	return GeneratePrivateKeyWithAlgorithm(keystorePath, bccsp.ECDSA)
+}
+
+// GeneratePrivateKeyWithAlgorithm creates a private key of the given
+// algorithm and stores it in keystorePath
+func GeneratePrivateKeyWithAlgorithm(keystorePath, algorithm string) (bccsp.Key,
+	crypto.Signer, error) {
 
 	var err error
 	var priv bccsp.Key
@@ -44,10 +70,14 @@ func GeneratePrivateKey(keystorePath string) (bccsp.Key,
 			},
 		},
 	}
+	keyGenOpts, err := KeyGenOpts(algorithm, false)
+	if err != nil {
+		return nil, nil, err
+	}
 	csp, err := factory.GetBCCSPFromOpts(opts)
 	if err == nil {
 		// generate a key
-		priv, err = csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+		priv, err = csp.KeyGen(keyGenOpts)
 		if err == nil {
 			// create a crypto.Signer
 			s, err = signer.New(csp, priv)
@@ -75,3 +105,19 @@ func GetECPublicKey(priv bccsp.Key) (*ecdsa.PublicKey, error) {
 	}
 	return ecPubKey.(*ecdsa.PublicKey), nil
 }
+
+// GetPublicKey returns the public key of priv, whatever its algorithm
+func GetPublicKey(priv bccsp.Key) (crypto.PublicKey, error) {
+
+	// get the public key
+	pubKey, err := priv.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	// marshal to bytes
+	pubKeyBytes, err := pubKey.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return utils.DERToPublicKey(pubKeyBytes)
+}
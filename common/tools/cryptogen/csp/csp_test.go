@@ -72,6 +72,28 @@ func TestGeneratePrivateKey(t *testing.T) {
 
 }
 
+func TestGeneratePrivateKeyWithAlgorithm(t *testing.T) {
+
+	for _, algorithm := range []string{"", "ecdsa", "ED25519", "SM2"} {
+		priv, signer, err := csp.GeneratePrivateKeyWithAlgorithm(testDir, algorithm)
+		assert.NoError(t, err, "Failed to generate private key")
+		assert.Equal(t, true, priv.Private(), "Failed to return private key")
+		assert.NotNil(t, signer, "Should have returned a crypto.Signer")
+		pkFile := filepath.Join(testDir, hex.EncodeToString(priv.SKI())+"_sk")
+		assert.Equal(t, true, checkForFile(pkFile),
+			"Expected to find private key file")
+
+		pubKey, err := csp.GetPublicKey(priv)
+		assert.NoError(t, err, "Failed to get public key")
+		assert.Equal(t, signer.Public(), pubKey)
+	}
+
+	_, _, err := csp.GeneratePrivateKeyWithAlgorithm(testDir, "DSA")
+	assert.Error(t, err, "Unsupported algorithm should fail")
+	cleanup(testDir)
+
+}
+
 func TestGetECPublicKey(t *testing.T) {
 
 	priv, _, err := csp.GeneratePrivateKey(testDir)
@@ -71,12 +71,13 @@ type UsersSpec struct {
 }
 
 type OrgSpec struct {
-	Name     string       `yaml:"Name"`
-	Domain   string       `yaml:"Domain"`
-	CA       NodeSpec     `yaml:"CA"`
-	Template NodeTemplate `yaml:"Template"`
-	Specs    []NodeSpec   `yaml:"Specs"`
-	Users    UsersSpec    `yaml:"Users"`
+	Name         string       `yaml:"Name"`
+	Domain       string       `yaml:"Domain"`
+	KeyAlgorithm string       `yaml:"KeyAlgorithm"`
+	CA           NodeSpec     `yaml:"CA"`
+	Template     NodeTemplate `yaml:"Template"`
+	Specs        []NodeSpec   `yaml:"Specs"`
+	Users        UsersSpec    `yaml:"Users"`
 }
 
 type Config struct {
@@ -111,6 +112,15 @@ PeerOrgs:
   - Name: Org1
     Domain: org1.example.com
 
+    # ---------------------------------------------------------------------------
+    # "KeyAlgorithm"
+    # ---------------------------------------------------------------------------
+    # Uncomment this entry to choose the algorithm of the keys of the signing CA
+    # and of the identities of this organization: ECDSA (the default), ED25519
+    # or SM2.  The TLS keys and certificates always use ECDSA.
+    # ---------------------------------------------------------------------------
+    # KeyAlgorithm: ECDSA
+
     # ---------------------------------------------------------------------------
     # "CA"
     # ---------------------------------------------------------------------------
@@ -390,7 +400,7 @@ func generatePeerOrg(baseDir string, orgSpec OrgSpec) {
 	usersDir := filepath.Join(orgDir, "users")
 	adminCertsDir := filepath.Join(mspDir, "admincerts")
 	// generate signing CA
-	signCA, err := ca.NewCA(caDir, orgName, orgSpec.CA.CommonName)
+	signCA, err := ca.NewCAWithKeyAlgorithm(caDir, orgName, orgSpec.CA.CommonName, orgSpec.KeyAlgorithm)
 	if err != nil {
 		fmt.Printf("Error generating signCA for org %s:\n%v\n", orgName, err)
 		os.Exit(1)
@@ -493,7 +503,7 @@ func generateOrdererOrg(baseDir string, orgSpec OrgSpec) {
 	usersDir := filepath.Join(orgDir, "users")
 	adminCertsDir := filepath.Join(mspDir, "admincerts")
 	// generate signing CA
-	signCA, err := ca.NewCA(caDir, orgName, orgSpec.CA.CommonName)
+	signCA, err := ca.NewCAWithKeyAlgorithm(caDir, orgName, orgSpec.CA.CommonName, orgSpec.KeyAlgorithm)
 	if err != nil {
 		fmt.Printf("Error generating signCA for org %s:\n%v\n", orgName, err)
 		os.Exit(1)
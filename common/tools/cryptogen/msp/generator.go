@@ -52,20 +52,20 @@ func GenerateLocalMSP(baseDir, name string, sans []string, signCA *ca.CA,
 	// get keystore path
 	keystore := filepath.Join(mspDir, "keystore")
 
-	// generate private key
-	priv, _, err := csp.GeneratePrivateKey(keystore)
+	// generate private key of the same algorithm as the signing CA
+	priv, _, err := csp.GeneratePrivateKeyWithAlgorithm(keystore, signCA.KeyAlgorithm)
 	if err != nil {
 		return err
 	}
 
 	// get public key
-	ecPubKey, err := csp.GetECPublicKey(priv)
+	pubKey, err := csp.GetPublicKey(priv)
 	if err != nil {
 		return err
 	}
 	// generate X509 certificate using signing CA
 	cert, err := signCA.SignCertificate(filepath.Join(mspDir, "signcerts"),
-		name, []string{}, ecPubKey, x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
+		name, []string{}, pubKey, x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
 	if err != nil {
 		return err
 	}
@@ -160,13 +160,20 @@ func GenerateVerifyingMSP(baseDir string, signCA *ca.CA, tlsCA *ca.CA) error {
 	// of unit tests
 	factory.InitFactories(nil)
 	bcsp := factory.GetDefault()
-	priv, err := bcsp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: true})
-	ecPubKey, err := csp.GetECPublicKey(priv)
+	keyGenOpts, err := csp.KeyGenOpts(signCA.KeyAlgorithm, true)
+	if err != nil {
+		return err
+	}
+	priv, err := bcsp.KeyGen(keyGenOpts)
+	if err != nil {
+		return err
+	}
+	pubKey, err := csp.GetPublicKey(priv)
 	if err != nil {
 		return err
 	}
 	_, err = signCA.SignCertificate(filepath.Join(baseDir, "admincerts"), signCA.Name,
-		[]string{""}, ecPubKey, x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
+		[]string{""}, pubKey, x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
 	if err != nil {
 		return err
 	}
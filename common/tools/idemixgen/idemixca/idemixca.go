@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemixca
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/idemix"
+	"github.com/hyperledger/fabric/msp"
+	m "github.com/hyperledger/fabric/protos/msp"
+)
+
+// GenerateIssuerKey invokes Idemix library to generate an issuer (CA) signing key pair.
+// Currently three attributes are supported by the issuer:
+// AttributeNameOU is the organization unit name
+// AttributeNameRole is the role (member or admin)
+// AttributeNameEnrollmentId is the enrollment id
+// Generated keys are serialized to bytes.
+func GenerateIssuerKey() ([]byte, []byte, error) {
+	key, err := idemix.NewIssuerKey(msp.AttributeNames, rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	isk, err := proto.Marshal(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	ipk, err := proto.Marshal(key.Ipk)
+	if err != nil {
+		return nil, nil, err
+	}
+	return isk, ipk, nil
+}
+
+// GenerateSignerConfig creates a new signer config.
+// It generates a fresh user secret and issues a credential
+// with three attributes using the issuer's key pair.
+func GenerateSignerConfig(isAdmin bool, ouString string, enrollmentId string, key *idemix.IssuerKey) ([]byte, error) {
+	if ouString == "" {
+		return nil, fmt.Errorf("the OU attribute value is empty")
+	}
+	if enrollmentId == "" {
+		return nil, fmt.Errorf("the enrollment id value is empty")
+	}
+
+	role := m.MSPRole_MEMBER
+	if isAdmin {
+		role = m.MSPRole_ADMIN
+	}
+
+	attrs := make([]*big.Int, len(msp.AttributeNames))
+	attrs[msp.AttributeIndexOU] = idemix.HashAttribute([]byte(ouString))
+	attrs[msp.AttributeIndexRole] = big.NewInt(int64(role))
+	attrs[msp.AttributeIndexEnrollmentId] = idemix.HashAttribute([]byte(enrollmentId))
+
+	// the issuance protocol is run offline, playing both the user and the issuer
+	sk, err := idemix.NewRandSk(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := idemix.NewRandSk(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	credRequest, vPrime, err := idemix.NewCredRequest(sk, idemix.BigToBytes(nonce), key.Ipk, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	cred, err := idemix.NewCredential(key, credRequest, attrs, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err = cred.Complete(vPrime); err != nil {
+		return nil, err
+	}
+	if err = cred.Ver(sk, key.Ipk); err != nil {
+		return nil, fmt.Errorf("issued credential is invalid: %s", err)
+	}
+
+	credBytes, err := proto.Marshal(cred)
+	if err != nil {
+		return nil, err
+	}
+
+	signer := &m.IdemixMSPSignerConfig{
+		Cred:                         credBytes,
+		Sk:                           idemix.BigToBytes(sk),
+		OrganizationalUnitIdentifier: ouString,
+		Role:                         int32(role),
+		EnrollmentId:                 enrollmentId,
+	}
+	return proto.Marshal(signer)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+// idemixgen is a command line tool that generates the CA's keys and
+// generates MSP configs for signing and for verification
+// This tool can be used to setup the peers and CA to support
+// the Identity Mixer MSP
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/tools/idemixgen/idemixca"
+	"github.com/hyperledger/fabric/idemix"
+	"github.com/hyperledger/fabric/msp"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const (
+	IdemixDirIssuer             = "ca"
+	IdemixConfigIssuerSecretKey = "IssuerSecretKey"
+)
+
+// command line flags
+var (
+	app = kingpin.New("idemixgen", "Utility for generating key material to be used with the Identity Mixer MSP in Hyperledger Fabric")
+
+	outputDir = app.Flag("output", "The output directory in which to place artifacts").Default("idemix-config").String()
+
+	genIssuerKey = app.Command("ca-keygen", "Generate CA key material")
+
+	genSignerConfig = app.Command("signerconfig", "Generate a default signer for this Idemix MSP")
+	genCredOU       = genSignerConfig.Flag("org-unit", "The Organizational Unit of the default signer").Short('u').String()
+	genCredIsAdmin  = genSignerConfig.Flag("admin", "Make the default signer admin").Short('a').Bool()
+	genCredEnrollId = genSignerConfig.Flag("enrollmentId", "The enrollment id of the default signer").Short('e').String()
+)
+
+func main() {
+	app.HelpFlag.Short('h')
+
+	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+
+	case genIssuerKey.FullCommand():
+		isk, ipk, err := idemixca.GenerateIssuerKey()
+		handleError(err)
+
+		// Prevent overwriting the existing key
+		path := filepath.Join(*outputDir, IdemixDirIssuer)
+		checkDirectoryNotExists(path, fmt.Sprintf("Directory %s already exists", path))
+
+		path = filepath.Join(*outputDir, msp.IdemixConfigDirMsp)
+		checkDirectoryNotExists(path, fmt.Sprintf("Directory %s already exists", path))
+
+		// write private and public keys to the file
+		handleError(os.MkdirAll(filepath.Join(*outputDir, IdemixDirIssuer), 0770))
+		handleError(os.MkdirAll(filepath.Join(*outputDir, msp.IdemixConfigDirMsp), 0770))
+		writeFile(filepath.Join(*outputDir, IdemixDirIssuer, IdemixConfigIssuerSecretKey), isk)
+		writeFile(filepath.Join(*outputDir, msp.IdemixConfigDirMsp, msp.IdemixConfigFileIssuerPublicKey), ipk)
+
+	case genSignerConfig.FullCommand():
+		config, err := idemixca.GenerateSignerConfig(*genCredIsAdmin, *genCredOU, *genCredEnrollId, readIssuerKey())
+		handleError(err)
+
+		path := filepath.Join(*outputDir, msp.IdemixConfigDirUser)
+		checkDirectoryNotExists(path, fmt.Sprintf("This MSP config already contains a directory \"%s\"", path))
+
+		// Write config to file
+		handleError(os.MkdirAll(filepath.Join(*outputDir, msp.IdemixConfigDirUser), 0770))
+		writeFile(filepath.Join(*outputDir, msp.IdemixConfigDirUser, msp.IdemixConfigFileSigner), config)
+	}
+}
+
+func writeFile(path string, contents []byte) {
+	handleError(ioutil.WriteFile(path, contents, 0640))
+}
+
+// readIssuerKey reads the issuer key from the output directory
+func readIssuerKey() *idemix.IssuerKey {
+	path := filepath.Join(*outputDir, IdemixDirIssuer, IdemixConfigIssuerSecretKey)
+	isk, err := ioutil.ReadFile(path)
+	if err != nil {
+		handleError(fmt.Errorf("failed to open issuer secret key file: %s", path))
+	}
+	key := &idemix.IssuerKey{}
+	handleError(proto.Unmarshal(isk, key))
+	if key.Ipk == nil {
+		handleError(fmt.Errorf("issuer secret key file %s does not contain a public key", path))
+	}
+	handleError(key.Ipk.Check())
+	return key
+}
+
+// checkDirectoryNotExists checks whether a directory with the given path already exists and exits if this is the case
+func checkDirectoryNotExists(path string, errorMessage string) {
+	_, err := os.Stat(path)
+	if err == nil {
+		handleError(errors.New(errorMessage))
+	}
+}
+
+func handleError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aclmgmt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/policy"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+)
+
+var aclLogger = flogging.MustGetLogger("aclmgmt")
+
+// ACLProvider checks the access to the resources of the peer
+type ACLProvider interface {
+	// CheckACL checks that the identity satisfies one of the policies
+	// the ACLs map the resource to on the channel. The identity is given
+	// either by a *pb.SignedProposal or by a []*common.SignedData
+	CheckACL(resName string, channelID string, idinfo interface{}) error
+}
+
+// ChannelACLsGetter returns the ACLs set by the configuration of the channels
+type ChannelACLsGetter interface {
+	// ChannelACLs returns the map of resource name to policy references set
+	// by the configuration of the channel, nil if the channel sets none
+	ChannelACLs(channelID string) map[string][]string
+}
+
+type aclProvider struct {
+	policyChecker policy.PolicyChecker
+	aclsGetter    ChannelACLsGetter
+	localACLs     map[string][]string
+}
+
+// NewACLProvider returns an ACLProvider which evaluates the policies with the
+// PolicyChecker. The policies of a resource are, in order of precedence, the
+// ones set by the peer.acls section of the local configuration, the ones set
+// by the configuration of the channel through the ChannelACLsGetter, if not
+// nil, and the defaults
+func NewACLProvider(policyChecker policy.PolicyChecker, aclsGetter ChannelACLsGetter) ACLProvider {
+	return &aclProvider{
+		policyChecker: policyChecker,
+		aclsGetter:    aclsGetter,
+		localACLs:     getLocalACLs(),
+	}
+}
+
+// getLocalACLs reads the ACLs overridden by the peer.acls section of the
+// local configuration, which maps the resources to comma separated policy
+// references. The resource names are matched ignoring the case as the keys
+// of the configuration are case insensitive
+func getLocalACLs() map[string][]string {
+	acls := make(map[string][]string)
+	for resName, refs := range viper.GetStringMapString("peer.acls") {
+		var policyRefs []string
+		for _, ref := range strings.Split(refs, ",") {
+			if ref = strings.TrimSpace(ref); ref != "" {
+				policyRefs = append(policyRefs, ref)
+			}
+		}
+		if len(policyRefs) == 0 {
+			aclLogger.Warningf("Ignoring the ACL of resource %s of the local configuration, which references no policy", resName)
+			continue
+		}
+		acls[strings.ToLower(resName)] = policyRefs
+	}
+	return acls
+}
+
+// policyRefs returns the references of the policies of the resource on the channel
+func (p *aclProvider) policyRefs(resName string, channelID string) ([]string, error) {
+	if refs, ok := p.localACLs[strings.ToLower(resName)]; ok {
+		return refs, nil
+	}
+	if channelID != "" && p.aclsGetter != nil {
+		if refs := p.aclsGetter.ChannelACLs(channelID)[resName]; len(refs) > 0 {
+			return refs, nil
+		}
+	}
+	if refs, ok := defaultACLs[resName]; ok {
+		return refs, nil
+	}
+	return nil, fmt.Errorf("unknown resource [%s]", resName)
+}
+
+// CheckACL checks the access to the resource on the channel. A reference to
+// a policy starting with the path separator names a policy of the channel,
+// any other reference names a role of the local MSP, which can be checked
+// only for signed proposals
+func (p *aclProvider) CheckACL(resName string, channelID string, idinfo interface{}) error {
+	refs, err := p.policyRefs(resName, channelID)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, ref := range refs {
+		err := p.checkPolicy(ref, channelID, idinfo)
+		if err == nil {
+			return nil
+		}
+		aclLogger.Debugf("Policy %s of resource %s on channel [%s] not satisfied: %s", ref, resName, channelID, err)
+		errs = append(errs, fmt.Sprintf("policy %s not satisfied: %s", ref, err))
+	}
+	return fmt.Errorf("access denied to resource %s on channel [%s]: %s", resName, channelID, strings.Join(errs, ", "))
+}
+
+func (p *aclProvider) checkPolicy(ref string, channelID string, idinfo interface{}) error {
+	channelPolicy := strings.HasPrefix(ref, policies.PathSeparator)
+	if channelPolicy && channelID == "" {
+		return fmt.Errorf("the channel policy %s cannot be checked without a channel", ref)
+	}
+
+	switch id := idinfo.(type) {
+	case *pb.SignedProposal:
+		if channelPolicy {
+			return p.policyChecker.CheckPolicy(channelID, ref, id)
+		}
+		return p.policyChecker.CheckPolicyNoChannel(ref, id)
+	case []*common.SignedData:
+		if !channelPolicy {
+			return fmt.Errorf("the local MSP role %s cannot be checked against signed data", ref)
+		}
+		return p.policyChecker.CheckPolicyBySignedData(channelID, ref, id)
+	default:
+		return fmt.Errorf("unsupported identity type %T", idinfo)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aclmgmt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockPolicyChecker satisfies the policies of the channels and the local
+// roles it is given, and records the ones it was asked to check
+type mockPolicyChecker struct {
+	channelPolicies map[string]bool
+	localRoles      map[string]bool
+	checked         []string
+}
+
+func (m *mockPolicyChecker) check(satisfied map[string]bool, name string) error {
+	m.checked = append(m.checked, name)
+	if !satisfied[name] {
+		return errors.New("policy not satisfied")
+	}
+	return nil
+}
+
+func (m *mockPolicyChecker) CheckPolicy(channelID, policyName string, signedProp *pb.SignedProposal) error {
+	return m.check(m.channelPolicies, policyName)
+}
+
+func (m *mockPolicyChecker) CheckPolicyBySignedData(channelID, policyName string, sd []*common.SignedData) error {
+	return m.check(m.channelPolicies, policyName)
+}
+
+func (m *mockPolicyChecker) CheckPolicyNoChannel(policyName string, signedProp *pb.SignedProposal) error {
+	return m.check(m.localRoles, policyName)
+}
+
+type mockChannelACLsGetter map[string]map[string][]string
+
+func (m mockChannelACLsGetter) ChannelACLs(channelID string) map[string][]string {
+	return m[channelID]
+}
+
+func TestDefaultACLs(t *testing.T) {
+	pc := &mockPolicyChecker{
+		channelPolicies: map[string]bool{policies.ChannelApplicationReaders: true},
+		localRoles:      map[string]bool{mgmt.Members: true},
+	}
+	p := NewACLProvider(pc, nil)
+	sp := &pb.SignedProposal{}
+
+	assert.NoError(t, p.CheckACL(QSCC_GetBlockByNumber, "mychannel", sp))
+	assert.NoError(t, p.CheckACL(CSCC_GetChannels, "", sp))
+	assert.NoError(t, p.CheckACL(FILTEREDBLOCKEVENT, "mychannel", []*common.SignedData{}))
+	assert.Error(t, p.CheckACL(PROPOSE, "mychannel", sp))
+	assert.Error(t, p.CheckACL(LSCC_INSTALL, "", sp))
+	assert.Equal(t, []string{
+		policies.ChannelApplicationReaders,
+		mgmt.Members,
+		policies.ChannelApplicationReaders,
+		policies.ChannelApplicationWriters,
+		mgmt.Admins,
+	}, pc.checked)
+}
+
+func TestCheckACLErrors(t *testing.T) {
+	pc := &mockPolicyChecker{
+		channelPolicies: map[string]bool{policies.ChannelApplicationReaders: true},
+		localRoles:      map[string]bool{mgmt.Admins: true},
+	}
+	p := NewACLProvider(pc, nil)
+	sp := &pb.SignedProposal{}
+
+	err := p.CheckACL("lscc/Unknown", "mychannel", sp)
+	assert.EqualError(t, err, "unknown resource [lscc/Unknown]")
+
+	// the policies of the channels cannot be checked without a channel
+	err = p.CheckACL(QSCC_GetChainInfo, "", sp)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be checked without a channel")
+
+	// the local roles cannot be checked against signed data
+	err = p.CheckACL(LSCC_INSTALL, "", []*common.SignedData{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be checked against signed data")
+
+	err = p.CheckACL(QSCC_GetChainInfo, "mychannel", "identity")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported identity type string")
+
+	assert.Empty(t, pc.checked, "No policy should have been checked")
+}
+
+func TestChannelACLs(t *testing.T) {
+	pc := &mockPolicyChecker{
+		channelPolicies: map[string]bool{policies.ChannelApplicationWriters: true},
+	}
+	getter := mockChannelACLsGetter{
+		"mychannel": {QSCC_GetBlockByNumber: {policies.ChannelApplicationWriters}},
+	}
+	p := NewACLProvider(pc, getter)
+	sp := &pb.SignedProposal{}
+
+	// the channel configuration overrides the default Readers policy
+	assert.NoError(t, p.CheckACL(QSCC_GetBlockByNumber, "mychannel", sp))
+	// the other channels and resources keep the defaults
+	assert.Error(t, p.CheckACL(QSCC_GetBlockByNumber, "otherchannel", sp))
+	assert.Error(t, p.CheckACL(QSCC_GetBlockByHash, "mychannel", sp))
+}
+
+func TestLocalACLs(t *testing.T) {
+	viper.Set("peer.acls", map[string]interface{}{
+		QSCC_GetBlockByNumber: "/Channel/Application/Admins, " + policies.ChannelApplicationReaders,
+		CSCC_GetChannels:      mgmt.Admins,
+		QSCC_GetBlockByHash:   " , ",
+	})
+	defer viper.Set("peer.acls", nil)
+
+	pc := &mockPolicyChecker{
+		channelPolicies: map[string]bool{policies.ChannelApplicationReaders: true},
+		localRoles:      map[string]bool{mgmt.Members: true},
+	}
+	getter := mockChannelACLsGetter{
+		"mychannel": {
+			QSCC_GetBlockByNumber: {policies.ChannelApplicationWriters},
+			QSCC_GetBlockByHash:   {policies.ChannelApplicationWriters},
+		},
+	}
+	p := NewACLProvider(pc, getter)
+	sp := &pb.SignedProposal{}
+
+	// the local configuration overrides the channel configuration
+	assert.NoError(t, p.CheckACL(QSCC_GetBlockByNumber, "mychannel", sp))
+	assert.Equal(t, []string{"/Channel/Application/Admins", policies.ChannelApplicationReaders}, pc.checked)
+
+	// and the defaults
+	assert.Error(t, p.CheckACL(CSCC_GetChannels, "", sp))
+
+	// the ACLs of the local configuration referencing no policy are ignored
+	assert.Error(t, p.CheckACL(QSCC_GetBlockByHash, "mychannel", sp))
+	assert.Equal(t, policies.ChannelApplicationWriters, pc.checked[len(pc.checked)-1])
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aclmgmt
+
+import (
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/msp/mgmt"
+)
+
+// The resources of the peer whose access is controlled by the ACLs
+const (
+	// Lifecycle system chaincode
+	LSCC_INSTALL                = "lscc/Install"
+	LSCC_GETCCINFO              = "lscc/ChaincodeExists"
+	LSCC_GETDEPSPEC             = "lscc/GetDeploymentSpec"
+	LSCC_GETCCDATA              = "lscc/GetChaincodeData"
+	LSCC_GETCHAINCODES          = "lscc/GetInstantiatedChaincodes"
+	LSCC_GETINSTALLEDCHAINCODES = "lscc/GetInstalledChaincodes"
+
+	// Query system chaincode
+	QSCC_GetChainInfo       = "qscc/GetChainInfo"
+	QSCC_GetBlockByNumber   = "qscc/GetBlockByNumber"
+	QSCC_GetBlockByHash     = "qscc/GetBlockByHash"
+	QSCC_GetTransactionByID = "qscc/GetTransactionByID"
+	QSCC_GetBlockByTxID     = "qscc/GetBlockByTxID"
+
+	// Configuration system chaincode
+	CSCC_JoinChain           = "cscc/JoinChain"
+	CSCC_JoinChainBySnapshot = "cscc/JoinChainBySnapshot"
+	CSCC_GetConfigBlock      = "cscc/GetConfigBlock"
+	CSCC_GetChannels         = "cscc/GetChannels"
+
+	// Cross-channel system chaincode
+	XSCC_AdminAbort = "xscc/AdminAbort"
+
+	// Endorser
+	PROPOSE = "peer/Propose"
+
+	// Events
+	BLOCKEVENT         = "event/Block"
+	FILTEREDBLOCKEVENT = "event/FilteredBlock"
+)
+
+// defaultACLs maps the resources to the policies checked when neither the
+// channel configuration nor the local configuration of the peer set them.
+// The references starting with the path separator name the policies of the
+// channel, the others name the roles of the local MSP
+var defaultACLs = map[string][]string{
+	LSCC_INSTALL:                {mgmt.Admins},
+	LSCC_GETCCINFO:              {policies.ChannelApplicationReaders},
+	LSCC_GETDEPSPEC:             {policies.ChannelApplicationReaders},
+	LSCC_GETCCDATA:              {policies.ChannelApplicationReaders},
+	LSCC_GETCHAINCODES:          {mgmt.Admins},
+	LSCC_GETINSTALLEDCHAINCODES: {mgmt.Admins},
+
+	QSCC_GetChainInfo:       {policies.ChannelApplicationReaders},
+	QSCC_GetBlockByNumber:   {policies.ChannelApplicationReaders},
+	QSCC_GetBlockByHash:     {policies.ChannelApplicationReaders},
+	QSCC_GetTransactionByID: {policies.ChannelApplicationReaders},
+	QSCC_GetBlockByTxID:     {policies.ChannelApplicationReaders},
+
+	CSCC_JoinChain:           {mgmt.Admins},
+	CSCC_JoinChainBySnapshot: {mgmt.Admins},
+	CSCC_GetConfigBlock:      {policies.ChannelApplicationReaders},
+	CSCC_GetChannels:         {mgmt.Members},
+
+	XSCC_AdminAbort: {policies.ChannelApplicationAdmins},
+
+	PROPOSE: {policies.ChannelApplicationWriters},
+
+	// The filtered blocks only tell the outcome of the transactions, so the
+	// client applications allowed to submit transactions may track them
+	BLOCKEVENT:         {policies.ChannelApplicationReaders},
+	FILTEREDBLOCKEVENT: {policies.ChannelApplicationReaders, policies.ChannelApplicationWriters},
+}
@@ -56,6 +56,9 @@ const (
 
 	//HistoryQueryExecutorKey is used to attach ledger history query executor context
 	HistoryQueryExecutorKey key = "historyqueryexecutorkey"
+
+	//CrossChannelKey is used to attach the simulators of the channels a proposal invokes chaincodes on
+	CrossChannelKey key = "crosschannelkey"
 )
 
 //this is basically the singleton that supports the
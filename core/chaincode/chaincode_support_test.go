@@ -42,6 +42,7 @@ import (
 	"github.com/hyperledger/fabric/core/policy"
 	"github.com/hyperledger/fabric/core/scc"
 	plgr "github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	putils "github.com/hyperledger/fabric/protos/utils"
 	"golang.org/x/net/context"
@@ -120,6 +121,20 @@ func (meqe *mockExecQuerySimulator) SetStateMultipleKeys(namespace string, kvs m
 	return meqe.txsim.SetStateMultipleKeys(namespace, kvs)
 }
 
+func (meqe *mockExecQuerySimulator) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	if meqe.txsim == nil {
+		return nil, fmt.Errorf("GetStateMetadata txsimulator not initialed")
+	}
+	return meqe.txsim.GetStateMetadata(namespace, key)
+}
+
+func (meqe *mockExecQuerySimulator) SetStateMetadata(namespace, key string, metadata map[string][]byte) error {
+	if meqe.txsim == nil {
+		return fmt.Errorf("SetStateMetadata txsimulator not initialed")
+	}
+	return meqe.txsim.SetStateMetadata(namespace, key, metadata)
+}
+
 func (meqe *mockExecQuerySimulator) ExecuteUpdate(query string) error {
 	if meqe.txsim == nil {
 		return fmt.Errorf("SetState txsimulator not initialed")
@@ -134,6 +149,27 @@ func (meqe *mockExecQuerySimulator) GetTxSimulationResults() ([]byte, error) {
 	return meqe.txsim.GetTxSimulationResults()
 }
 
+func (meqe *mockExecQuerySimulator) SetPrivateData(namespace, collection, key string, value []byte) error {
+	if meqe.txsim == nil {
+		return fmt.Errorf("SetPrivateData txsimulator not initialed")
+	}
+	return meqe.txsim.SetPrivateData(namespace, collection, key, value)
+}
+
+func (meqe *mockExecQuerySimulator) DeletePrivateData(namespace, collection, key string) error {
+	if meqe.txsim == nil {
+		return fmt.Errorf("DeletePrivateData txsimulator not initialed")
+	}
+	return meqe.txsim.DeletePrivateData(namespace, collection, key)
+}
+
+func (meqe *mockExecQuerySimulator) GetPvtSimulationResults() (*rwset.TxPvtReadWriteSet, error) {
+	if meqe.txsim == nil {
+		return nil, fmt.Errorf("GetPvtSimulationResults txsimulator not initialed")
+	}
+	return meqe.txsim.GetPvtSimulationResults()
+}
+
 //initialize peer and start up. If security==enabled, login as vp
 func initMockPeer(chainIDs ...string) error {
 	peer.MockInitialize()
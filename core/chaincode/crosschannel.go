@@ -0,0 +1,177 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/peer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// CrossChannelInvocation is the first invocation of a chaincode on a channel
+// other than the one of the proposal, along with the simulator holding the
+// reads and writes of the proposal on the channel
+type CrossChannelInvocation struct {
+	ChainID       string
+	ChaincodeSpec *pb.ChaincodeSpec
+	// ChaincodeData is nil if the chaincode is a system chaincode
+	ChaincodeData *ccprovider.ChaincodeData
+	// Response is nil unless the chaincode completed
+	Response  *pb.Response
+	Simulator ledger.TxSimulator
+
+	sim *writeTrackingSimulator
+}
+
+// Writes returns true if the proposal writes to the state of the channel
+func (i *CrossChannelInvocation) Writes() bool {
+	return i.sim.writes
+}
+
+// WritesPrivateData returns true if the proposal writes private data to the channel
+func (i *CrossChannelInvocation) WritesPrivateData() bool {
+	return i.sim.writesPrivateData
+}
+
+// CrossChannelSimulators holds the simulators of the channels the chaincodes
+// invoked by a proposal are on, so that the writes to the channels other
+// than the one of the proposal are kept rather than discarded. The endorser
+// attaches it to the context of the proposal with CrossChannelKey, and the
+// chaincodes invoked on the channel of the proposal use its simulator
+type CrossChannelSimulators struct {
+	sync.Mutex
+	chainID     string
+	txsim       ledger.TxSimulator
+	sims        map[string]*writeTrackingSimulator
+	invocations []*CrossChannelInvocation
+}
+
+// NewCrossChannelSimulators returns the simulators of a proposal on the
+// channel with the given simulator
+func NewCrossChannelSimulators(chainID string, txsim ledger.TxSimulator) *CrossChannelSimulators {
+	return &CrossChannelSimulators{chainID: chainID, txsim: txsim, sims: make(map[string]*writeTrackingSimulator)}
+}
+
+// Invocations returns the first invocations on the other channels, in the
+// order they were made
+func (s *CrossChannelSimulators) Invocations() []*CrossChannelInvocation {
+	s.Lock()
+	defer s.Unlock()
+	return append([]*CrossChannelInvocation(nil), s.invocations...)
+}
+
+// Done releases the simulators of the other channels
+func (s *CrossChannelSimulators) Done() {
+	s.Lock()
+	defer s.Unlock()
+	for _, sim := range s.sims {
+		sim.Done()
+	}
+}
+
+// simulator returns the simulator of the channel, which is created on the
+// first invocation on the channel
+func (s *CrossChannelSimulators) simulator(chainID string) (ledger.TxSimulator, error) {
+	if chainID == s.chainID {
+		return s.txsim, nil
+	}
+	s.Lock()
+	defer s.Unlock()
+	if sim, ok := s.sims[chainID]; ok {
+		return sim, nil
+	}
+	lgr := peer.GetLedger(chainID)
+	if lgr == nil {
+		return nil, fmt.Errorf("Failed to find ledger for called channel %s", chainID)
+	}
+	txsim, err := lgr.NewTxSimulator()
+	if err != nil {
+		return nil, err
+	}
+	sim := &writeTrackingSimulator{TxSimulator: txsim}
+	s.sims[chainID] = sim
+	return sim, nil
+}
+
+// invoking records the invocation of a chaincode on a channel and returns
+// it, unless it is on the channel of the proposal or follows another
+// invocation on the channel, in which case nil is returned
+func (s *CrossChannelSimulators) invoking(chainID string, spec *pb.ChaincodeSpec, cd *ccprovider.ChaincodeData) *CrossChannelInvocation {
+	if chainID == s.chainID {
+		return nil
+	}
+	s.Lock()
+	defer s.Unlock()
+	sim, ok := s.sims[chainID]
+	if !ok {
+		return nil
+	}
+	for _, invocation := range s.invocations {
+		if invocation.ChainID == chainID {
+			return nil
+		}
+	}
+	invocation := &CrossChannelInvocation{ChainID: chainID, ChaincodeSpec: spec, ChaincodeData: cd, Simulator: sim, sim: sim}
+	s.invocations = append(s.invocations, invocation)
+	return invocation
+}
+
+func getCrossChannelSimulators(context context.Context) *CrossChannelSimulators {
+	if s, ok := context.Value(CrossChannelKey).(*CrossChannelSimulators); ok {
+		return s
+	}
+	return nil
+}
+
+// writeTrackingSimulator records whether anything is written through the
+// simulator it wraps
+type writeTrackingSimulator struct {
+	ledger.TxSimulator
+	writes            bool
+	writesPrivateData bool
+}
+
+func (s *writeTrackingSimulator) SetState(namespace string, key string, value []byte) error {
+	s.writes = true
+	return s.TxSimulator.SetState(namespace, key, value)
+}
+
+func (s *writeTrackingSimulator) DeleteState(namespace string, key string) error {
+	s.writes = true
+	return s.TxSimulator.DeleteState(namespace, key)
+}
+
+func (s *writeTrackingSimulator) SetStateMultipleKeys(namespace string, kvs map[string][]byte) error {
+	s.writes = true
+	return s.TxSimulator.SetStateMultipleKeys(namespace, kvs)
+}
+
+func (s *writeTrackingSimulator) SetStateMetadata(namespace, key string, metadata map[string][]byte) error {
+	s.writes = true
+	return s.TxSimulator.SetStateMetadata(namespace, key, metadata)
+}
+
+func (s *writeTrackingSimulator) ExecuteUpdate(query string) error {
+	s.writes = true
+	return s.TxSimulator.ExecuteUpdate(query)
+}
+
+func (s *writeTrackingSimulator) SetPrivateData(namespace, collection, key string, value []byte) error {
+	s.writes, s.writesPrivateData = true, true
+	return s.TxSimulator.SetPrivateData(namespace, collection, key, value)
+}
+
+func (s *writeTrackingSimulator) DeletePrivateData(namespace, collection, key string) error {
+	s.writes, s.writesPrivateData = true, true
+	return s.TxSimulator.DeletePrivateData(namespace, collection, key)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/peer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestCrossChannelSimulators(t *testing.T) {
+	peer.MockInitialize()
+	defer ledgermgmt.CleanupTestEnv()
+	assert.NoError(t, peer.MockCreateChain("xchain1"))
+	assert.NoError(t, peer.MockCreateChain("xchain2"))
+
+	txsim, err := peer.GetLedger("xchain1").NewTxSimulator()
+	assert.NoError(t, err)
+	defer txsim.Done()
+	s := NewCrossChannelSimulators("xchain1", txsim)
+	defer s.Done()
+
+	ctxt := context.WithValue(context.Background(), CrossChannelKey, s)
+	assert.Equal(t, s, getCrossChannelSimulators(ctxt))
+	assert.Nil(t, getCrossChannelSimulators(context.Background()))
+
+	// the channel of the proposal has its own simulator
+	sim, err := s.simulator("xchain1")
+	assert.NoError(t, err)
+	assert.Equal(t, txsim, sim)
+	assert.Nil(t, s.invoking("xchain1", &pb.ChaincodeSpec{}, nil))
+
+	_, err = s.simulator("nochain")
+	assert.Error(t, err)
+
+	// the first invocation on another channel is recorded
+	sim, err = s.simulator("xchain2")
+	assert.NoError(t, err)
+	sim2, err := s.simulator("xchain2")
+	assert.NoError(t, err)
+	assert.Equal(t, sim, sim2)
+	spec := &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "cc2"}}
+	cd := &ccprovider.ChaincodeData{Name: "cc2", Version: "1"}
+	invocation := s.invoking("xchain2", spec, cd)
+	assert.NotNil(t, invocation)
+	assert.Nil(t, s.invoking("xchain2", &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "cc3"}}, nil))
+	assert.False(t, invocation.Writes())
+
+	assert.NoError(t, sim.SetState("cc2", "key1", []byte("value1")))
+	assert.True(t, invocation.Writes())
+	assert.False(t, invocation.WritesPrivateData())
+	assert.NoError(t, sim.SetPrivateData("cc2", "coll1", "key1", []byte("value1")))
+	assert.True(t, invocation.WritesPrivateData())
+
+	invocations := s.Invocations()
+	assert.Len(t, invocations, 1)
+	assert.Equal(t, "xchain2", invocations[0].ChainID)
+	assert.Equal(t, spec, invocations[0].ChaincodeSpec)
+	assert.Equal(t, cd, invocations[0].ChaincodeData)
+	assert.Equal(t, sim, invocations[0].Simulator)
+}
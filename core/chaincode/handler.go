@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"sort"
 	"sync"
 	"time"
 
@@ -68,6 +69,9 @@ type transactionContext struct {
 
 	txsimulator          ledger.TxSimulator
 	historyQueryExecutor ledger.HistoryQueryExecutor
+
+	// holds the simulators of the other channels if the writes to them are kept
+	crossChannelSimulators *CrossChannelSimulators
 }
 
 type nextStateInfo struct {
@@ -196,6 +200,7 @@ func (handler *Handler) createTxContext(ctxt context.Context, chainID string, tx
 	handler.txCtxs[txid] = txctx
 	txctx.txsimulator = getTxSimulator(ctxt)
 	txctx.historyQueryExecutor = getHistoryQueryExecutor(ctxt)
+	txctx.crossChannelSimulators = getCrossChannelSimulators(ctxt)
 
 	return txctx, nil
 }
@@ -418,6 +423,11 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			{Name: pb.ChaincodeMessage_INVOKE_CHAINCODE.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_COMPLETED.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_STATE.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_GET_PRIVATE_DATA.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_PUT_PRIVATE_DATA.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_DEL_PRIVATE_DATA.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_GET_STATE_METADATA.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_PUT_STATE_METADATA.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_STATE_BY_RANGE.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_QUERY_RESULT.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY.String(), Src: []string{readystate}, Dst: readystate},
@@ -432,6 +442,8 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			"before_" + pb.ChaincodeMessage_REGISTER.String():           func(e *fsm.Event) { v.beforeRegisterEvent(e, v.FSM.Current()) },
 			"before_" + pb.ChaincodeMessage_COMPLETED.String():          func(e *fsm.Event) { v.beforeCompletedEvent(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_STATE.String():           func(e *fsm.Event) { v.afterGetState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_GET_PRIVATE_DATA.String():    func(e *fsm.Event) { v.afterGetPrivateData(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_GET_STATE_METADATA.String():  func(e *fsm.Event) { v.afterGetStateMetadata(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_STATE_BY_RANGE.String():  func(e *fsm.Event) { v.afterGetStateByRange(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_QUERY_RESULT.String():    func(e *fsm.Event) { v.afterGetQueryResult(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_HISTORY_FOR_KEY.String(): func(e *fsm.Event) { v.afterGetHistoryForKey(e, v.FSM.Current()) },
@@ -439,6 +451,9 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			"after_" + pb.ChaincodeMessage_QUERY_STATE_CLOSE.String():   func(e *fsm.Event) { v.afterQueryStateClose(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_PUT_STATE.String():           func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_DEL_STATE.String():           func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_PUT_PRIVATE_DATA.String():    func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_DEL_PRIVATE_DATA.String():    func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_PUT_STATE_METADATA.String():  func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_INVOKE_CHAINCODE.String():    func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
 			"enter_" + establishedstate:                                 func(e *fsm.Event) { v.enterEstablishedState(e, v.FSM.Current()) },
 			"enter_" + readystate:                                       func(e *fsm.Event) { v.enterReadyState(e, v.FSM.Current()) },
@@ -660,6 +675,149 @@ func (handler *Handler) handleGetState(msg *pb.ChaincodeMessage) {
 	}()
 }
 
+// afterGetPrivateData handles a GET_PRIVATE_DATA request from the chaincode.
+func (handler *Handler) afterGetPrivateData(e *fsm.Event, state string) {
+	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
+	if !ok {
+		e.Cancel(fmt.Errorf("Received unexpected message type"))
+		return
+	}
+	chaincodeLogger.Debugf("[%s]Received %s, invoking get private data from ledger", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_PRIVATE_DATA)
+
+	// Query ledger for private data
+	handler.handleGetPrivateData(msg)
+}
+
+// Handles query to ledger to get private data
+func (handler *Handler) handleGetPrivateData(msg *pb.ChaincodeMessage) {
+	go func() {
+		// Check if this is the unique state request from this chaincode txid
+		uniqueReq := handler.createTXIDEntry(msg.Txid)
+		if !uniqueReq {
+			// Drop this request
+			chaincodeLogger.Error("Another state request pending for this Txid. Cannot process.")
+			return
+		}
+
+		var serialSendMsg *pb.ChaincodeMessage
+		var txContext *transactionContext
+		txContext, serialSendMsg = handler.isValidTxSim(msg.Txid,
+			"[%s]No ledger context for GetPrivateData. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+
+		defer func() {
+			handler.deleteTXIDEntry(msg.Txid)
+			if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
+				chaincodeLogger.Debugf("[%s]handleGetPrivateData serial send %s",
+					shorttxid(serialSendMsg.Txid), serialSendMsg.Type)
+			}
+			handler.serialSendAsync(serialSendMsg, nil)
+		}()
+
+		if txContext == nil {
+			return
+		}
+
+		privateDataInfo := &pb.PrivateDataInfo{}
+		unmarshalErr := proto.Unmarshal(msg.Payload, privateDataInfo)
+		if unmarshalErr != nil {
+			chaincodeLogger.Errorf("[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(unmarshalErr.Error()), Txid: msg.Txid}
+			return
+		}
+
+		chaincodeID := handler.getCCRootName()
+		if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
+			chaincodeLogger.Debugf("[%s] getting private data for chaincode %s, collection %s, key %s, channel %s",
+				shorttxid(msg.Txid), chaincodeID, privateDataInfo.Collection, privateDataInfo.Key, txContext.chainID)
+		}
+
+		res, err := txContext.txsimulator.GetPrivateData(chaincodeID, privateDataInfo.Collection, privateDataInfo.Key)
+		if err != nil {
+			// Send error msg back to chaincode. GetPrivateData will not trigger event
+			chaincodeLogger.Errorf("[%s]Failed to get private data(%s). Sending %s",
+				shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid}
+			return
+		}
+		// a nil result is sent back as an empty payload
+		serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid}
+	}()
+}
+
+// afterGetStateMetadata handles a GET_STATE_METADATA request from the chaincode.
+func (handler *Handler) afterGetStateMetadata(e *fsm.Event, state string) {
+	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
+	if !ok {
+		e.Cancel(fmt.Errorf("Received unexpected message type"))
+		return
+	}
+	chaincodeLogger.Debugf("[%s]Received %s, invoking get state metadata from ledger", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_METADATA)
+
+	// Query ledger for the metadata of the key
+	handler.handleGetStateMetadata(msg)
+}
+
+// Handles query to ledger to get the metadata of a key
+func (handler *Handler) handleGetStateMetadata(msg *pb.ChaincodeMessage) {
+	go func() {
+		// Check if this is the unique state request from this chaincode txid
+		uniqueReq := handler.createTXIDEntry(msg.Txid)
+		if !uniqueReq {
+			// Drop this request
+			chaincodeLogger.Error("Another state request pending for this Txid. Cannot process.")
+			return
+		}
+
+		var serialSendMsg *pb.ChaincodeMessage
+		var txContext *transactionContext
+		txContext, serialSendMsg = handler.isValidTxSim(msg.Txid,
+			"[%s]No ledger context for GetStateMetadata. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+
+		defer func() {
+			handler.deleteTXIDEntry(msg.Txid)
+			if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
+				chaincodeLogger.Debugf("[%s]handleGetStateMetadata serial send %s",
+					shorttxid(serialSendMsg.Txid), serialSendMsg.Type)
+			}
+			handler.serialSendAsync(serialSendMsg, nil)
+		}()
+
+		if txContext == nil {
+			return
+		}
+
+		getStateMetadata := &pb.GetStateMetadata{}
+		unmarshalErr := proto.Unmarshal(msg.Payload, getStateMetadata)
+		if unmarshalErr != nil {
+			chaincodeLogger.Errorf("[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(unmarshalErr.Error()), Txid: msg.Txid}
+			return
+		}
+
+		chaincodeID := handler.getCCRootName()
+		metadata, err := txContext.txsimulator.GetStateMetadata(chaincodeID, getStateMetadata.Key)
+		if err != nil {
+			chaincodeLogger.Errorf("[%s]Failed to get state metadata(%s). Sending %s",
+				shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid}
+			return
+		}
+
+		var metakeys []string
+		for metakey := range metadata {
+			metakeys = append(metakeys, metakey)
+		}
+		sort.Strings(metakeys)
+		result := &pb.StateMetadataResult{}
+		for _, metakey := range metakeys {
+			result.Entries = append(result.Entries, &pb.StateMetadata{Metakey: metakey, Value: metadata[metakey]})
+		}
+		//we constructed a valid object. No need to check for error
+		payload, _ := proto.Marshal(result)
+		serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payload, Txid: msg.Txid}
+	}()
+}
+
 // afterGetStateByRange handles a GET_STATE_BY_RANGE request from the chaincode.
 func (handler *Handler) afterGetStateByRange(e *fsm.Event, state string) {
 	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
@@ -725,7 +883,23 @@ func (handler *Handler) handleGetStateByRange(msg *pb.ChaincodeMessage) {
 			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
 		}
 
-		rangeIter, err := txContext.txsimulator.GetStateRangeScanIterator(chaincodeID, getStateByRange.StartKey, getStateByRange.EndKey)
+		queryMetadata, err := getQueryMetadata(getStateByRange.Metadata)
+		if err != nil {
+			errHandler(err, nil, "Failed to unmarshall query metadata. Sending %s", pb.ChaincodeMessage_ERROR)
+			return
+		}
+
+		var rangeIter commonledger.ResultsIterator
+		if queryMetadata != nil {
+			// the bookmark of a paginated range query is the startKey of the next page
+			startKey := getStateByRange.StartKey
+			if queryMetadata.Bookmark != "" {
+				startKey = queryMetadata.Bookmark
+			}
+			rangeIter, err = txContext.txsimulator.GetStateRangeScanIteratorWithPagination(chaincodeID, startKey, getStateByRange.EndKey, queryMetadata.PageSize)
+		} else {
+			rangeIter, err = txContext.txsimulator.GetStateRangeScanIterator(chaincodeID, getStateByRange.StartKey, getStateByRange.EndKey)
+		}
 		if err != nil {
 			errHandler(err, nil, "Failed to get ledger scan iterator. Sending %s", pb.ChaincodeMessage_ERROR)
 			return
@@ -733,7 +907,11 @@ func (handler *Handler) handleGetStateByRange(msg *pb.ChaincodeMessage) {
 
 		handler.putQueryIterator(txContext, iterID, rangeIter)
 		var payload *pb.QueryResponse
-		payload, err = getQueryResponse(handler, txContext, rangeIter, iterID)
+		if queryMetadata != nil {
+			payload, err = getPaginatedQueryResponse(handler, txContext, rangeIter.(ledger.QueryResultsIterator), iterID)
+		} else {
+			payload, err = getQueryResponse(handler, txContext, rangeIter, iterID)
+		}
 		if err != nil {
 			errHandler(err, rangeIter, "Failed to get query result. Sending %s", pb.ChaincodeMessage_ERROR)
 			return
@@ -791,6 +969,56 @@ func getQueryResponse(handler *Handler, txContext *transactionContext, iter comm
 	return &pb.QueryResponse{Results: queryResultsBytes, HasMore: queryResult != nil, Id: iterID}, nil
 }
 
+// getQueryMetadata unmarshals the metadata of a paginated query; nil is returned
+// if the metadata is not set, that is if the query is not paginated
+func getQueryMetadata(metadata []byte) (*pb.QueryMetadata, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	queryMetadata := &pb.QueryMetadata{}
+	if err := proto.Unmarshal(metadata, queryMetadata); err != nil {
+		return nil, err
+	}
+	if queryMetadata.PageSize <= 0 {
+		return nil, fmt.Errorf("invalid page size [%d]", queryMetadata.PageSize)
+	}
+	return queryMetadata, nil
+}
+
+//getPaginatedQueryResponse fetches the whole page of results of a paginated query iterator
+//and closes it. The response metadata carries the number of results and the bookmark
+func getPaginatedQueryResponse(handler *Handler, txContext *transactionContext, iter ledger.QueryResultsIterator,
+	iterID string) (*pb.QueryResponse, error) {
+
+	var queryResultsBytes []*pb.QueryResultBytes
+	for {
+		queryResult, err := iter.Next()
+		if err != nil {
+			chaincodeLogger.Errorf("Failed to get query result from iterator")
+			return nil, err
+		}
+		if queryResult == nil {
+			break
+		}
+		resultBytes, err := proto.Marshal(queryResult.(proto.Message))
+		if err != nil {
+			chaincodeLogger.Errorf("Failed to get encode query result as bytes")
+			return nil, err
+		}
+		queryResultsBytes = append(queryResultsBytes, &pb.QueryResultBytes{ResultBytes: resultBytes})
+	}
+
+	bookmark := iter.GetBookmarkAndClose()
+	handler.deleteQueryIterator(txContext, iterID)
+
+	//we constructed a valid object. No need to check for error
+	metadataBytes, _ := proto.Marshal(&pb.QueryResponseMetadata{
+		FetchedRecordsCount: int32(len(queryResultsBytes)),
+		Bookmark:            bookmark,
+	})
+	return &pb.QueryResponse{Results: queryResultsBytes, HasMore: false, Id: iterID, Metadata: metadataBytes}, nil
+}
+
 // afterQueryStateNext handles a QUERY_STATE_NEXT request from the chaincode.
 func (handler *Handler) afterQueryStateNext(e *fsm.Event, state string) {
 	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
@@ -1013,7 +1241,18 @@ func (handler *Handler) handleGetQueryResult(msg *pb.ChaincodeMessage) {
 
 		chaincodeID := handler.getCCRootName()
 
-		executeIter, err := txContext.txsimulator.ExecuteQuery(chaincodeID, getQueryResult.Query)
+		queryMetadata, err := getQueryMetadata(getQueryResult.Metadata)
+		if err != nil {
+			errHandler([]byte(err.Error()), nil, "Failed to unmarshall query metadata. Sending %s", pb.ChaincodeMessage_ERROR)
+			return
+		}
+
+		var executeIter commonledger.ResultsIterator
+		if queryMetadata != nil {
+			executeIter, err = txContext.txsimulator.ExecuteQueryWithPagination(chaincodeID, getQueryResult.Query, queryMetadata.Bookmark, queryMetadata.PageSize)
+		} else {
+			executeIter, err = txContext.txsimulator.ExecuteQuery(chaincodeID, getQueryResult.Query)
+		}
 		if err != nil {
 			errHandler([]byte(err.Error()), nil, "Failed to get ledger query iterator. Sending %s", pb.ChaincodeMessage_ERROR)
 			return
@@ -1021,7 +1260,11 @@ func (handler *Handler) handleGetQueryResult(msg *pb.ChaincodeMessage) {
 
 		handler.putQueryIterator(txContext, iterID, executeIter)
 		var payload *pb.QueryResponse
-		payload, err = getQueryResponse(handler, txContext, executeIter, iterID)
+		if queryMetadata != nil {
+			payload, err = getPaginatedQueryResponse(handler, txContext, executeIter.(ledger.QueryResultsIterator), iterID)
+		} else {
+			payload, err = getQueryResponse(handler, txContext, executeIter, iterID)
+		}
 		if err != nil {
 			errHandler([]byte(err.Error()), executeIter, "Failed to get query result. Sending %s", pb.ChaincodeMessage_ERROR)
 			return
@@ -1187,6 +1430,35 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 			// Invoke ledger to delete state
 			key := string(msg.Payload)
 			err = txContext.txsimulator.DeleteState(chaincodeID, key)
+		} else if msg.Type.String() == pb.ChaincodeMessage_PUT_PRIVATE_DATA.String() ||
+			msg.Type.String() == pb.ChaincodeMessage_DEL_PRIVATE_DATA.String() {
+			privateDataInfo := &pb.PrivateDataInfo{}
+			unmarshalErr := proto.Unmarshal(msg.Payload, privateDataInfo)
+			if unmarshalErr != nil {
+				errHandler([]byte(unmarshalErr.Error()), "[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+				return
+			}
+
+			if msg.Type == pb.ChaincodeMessage_PUT_PRIVATE_DATA {
+				err = txContext.txsimulator.SetPrivateData(chaincodeID, privateDataInfo.Collection, privateDataInfo.Key, privateDataInfo.Value)
+			} else {
+				err = txContext.txsimulator.DeletePrivateData(chaincodeID, privateDataInfo.Collection, privateDataInfo.Key)
+			}
+		} else if msg.Type.String() == pb.ChaincodeMessage_PUT_STATE_METADATA.String() {
+			putStateMetadata := &pb.PutStateMetadata{}
+			unmarshalErr := proto.Unmarshal(msg.Payload, putStateMetadata)
+			if unmarshalErr != nil || putStateMetadata.Metadata == nil {
+				errHandler([]byte(fmt.Sprintf("invalid %s payload", msg.Type)), "[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+				return
+			}
+
+			// the validation parameter is the only metadata entry of a key so far,
+			// hence setting an entry replaces all the metadata of the key
+			var metadata map[string][]byte
+			if putStateMetadata.Metadata.Value != nil {
+				metadata = map[string][]byte{putStateMetadata.Metadata.Metakey: putStateMetadata.Metadata.Value}
+			}
+			err = txContext.txsimulator.SetStateMetadata(chaincodeID, putStateMetadata.Key, metadata)
 		} else if msg.Type.String() == pb.ChaincodeMessage_INVOKE_CHAINCODE.String() {
 			if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
 				chaincodeLogger.Debugf("[%s] C-call-C", shorttxid(msg.Txid))
@@ -1223,7 +1495,18 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 			ctxt := context.Background()
 			txsim := txContext.txsimulator
 			historyQueryExecutor := txContext.historyQueryExecutor
-			if calledCcIns.ChainID != txContext.chainID {
+			crossChannelSimulators := txContext.crossChannelSimulators
+			if calledCcIns.ChainID != txContext.chainID && crossChannelSimulators != nil {
+				// the writes to the called channel are kept, to be endorsed
+				// as a part of a cross-channel transaction
+				txsim2, err2 := crossChannelSimulators.simulator(calledCcIns.ChainID)
+				if err2 != nil {
+					triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR,
+						Payload: []byte(err2.Error()), Txid: msg.Txid}
+					return
+				}
+				txsim = txsim2
+			} else if calledCcIns.ChainID != txContext.chainID {
 				lgr := peer.GetLedger(calledCcIns.ChainID)
 				if lgr == nil {
 					payload := "Failed to find ledger for called channel " + calledCcIns.ChainID
@@ -1242,6 +1525,9 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 			}
 			ctxt = context.WithValue(ctxt, TXSimulatorKey, txsim)
 			ctxt = context.WithValue(ctxt, HistoryQueryExecutorKey, historyQueryExecutor)
+			if crossChannelSimulators != nil {
+				ctxt = context.WithValue(ctxt, CrossChannelKey, crossChannelSimulators)
+			}
 
 			if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
 				chaincodeLogger.Debugf("[%s] calling lscc to get chaincode data for %s on channel %s",
@@ -1292,6 +1578,15 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 
 			ccMsg, _ := createCCMessage(pb.ChaincodeMessage_TRANSACTION, msg.Txid, chaincodeInput)
 
+			var invocation *CrossChannelInvocation
+			if crossChannelSimulators != nil {
+				invokedCd := cd
+				if isscc {
+					invokedCd = nil
+				}
+				invocation = crossChannelSimulators.invoking(calledCcIns.ChainID, chaincodeSpec, invokedCd)
+			}
+
 			// Execute the chaincode... this CANNOT be an init at least for now
 			response, execErr := handler.chaincodeSupport.Execute(ctxt, cccid, ccMsg, timeout)
 
@@ -1302,6 +1597,12 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 				err = execErr
 			} else {
 				res, err = proto.Marshal(response)
+				if invocation != nil && response.Type == pb.ChaincodeMessage_COMPLETED {
+					invocation.Response = &pb.Response{}
+					if unmarshalErr := proto.Unmarshal(response.Payload, invocation.Response); unmarshalErr != nil {
+						invocation.Response = nil
+					}
+				}
 			}
 		}
 
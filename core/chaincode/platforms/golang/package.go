@@ -17,8 +17,10 @@ limitations under the License.
 package golang
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"strings"
 
 	"os"
@@ -37,6 +39,13 @@ var includeFileTypes = map[string]bool{
 	".json": true,
 }
 
+// metadataDir is the directory of the chaincode which holds the metadata artifacts packaged
+// along with the code, such as the couchdb index definitions (META-INF/statedb/couchdb/indexes)
+const metadataDir = "META-INF"
+
+// indexesDirSuffix is the suffix of the directories which hold the index definitions of a statedb
+const indexesDirSuffix = "/indexes"
+
 var logger = flogging.MustGetLogger("golang-platform")
 
 func getCodeFromFS(path string) (codegopath string, err error) {
@@ -147,3 +156,65 @@ func findSource(gopath, pkg string) (SourceMap, error) {
 
 	return sources, nil
 }
+
+// findMetadata collects the files under the META-INF directory of the chaincode package. These
+// are named relative to the package directory, so that they are placed at META-INF/... in the
+// code package instead of under src/$pkg
+func findMetadata(gopath, pkg string) (SourceMap, error) {
+	sources := make(SourceMap)
+	tld := filepath.Join(gopath, "src", pkg)
+	root := filepath.Join(tld, metadataDir)
+	if exists, err := pathExists(root); err != nil || !exists {
+		return sources, err
+	}
+
+	walkFn := func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		name, err := filepath.Rel(tld, path)
+		if err != nil {
+			return fmt.Errorf("error obtaining relative path for %s: %s", path, err)
+		}
+		name = filepath.ToSlash(name)
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading metadata file %s: %s", path, err)
+		}
+		if err = validateMetadataFile(name, content); err != nil {
+			return err
+		}
+
+		logger.Debugf("including metadata file: %s", name)
+		sources[name] = SourceDescriptor{Name: name, Path: path, Info: info}
+		return nil
+	}
+
+	if err := filepath.Walk(root, walkFn); err != nil {
+		return nil, fmt.Errorf("Error walking metadata directory: %s", err)
+	}
+
+	return sources, nil
+}
+
+// validateMetadataFile checks that the index definitions of a statedb, found in the
+// directories META-INF/statedb/<db type>/indexes, are JSON files
+func validateMetadataFile(name string, content []byte) error {
+	dir := filepath.ToSlash(filepath.Dir(name))
+	if !strings.HasPrefix(dir, metadataDir+"/statedb/") || !strings.HasSuffix(dir, indexesDirSuffix) {
+		return nil
+	}
+	if filepath.Ext(name) != ".json" {
+		return fmt.Errorf("index definition %s must be a .json file", name)
+	}
+	var indexDefinition map[string]interface{}
+	if err := json.Unmarshal(content, &indexDefinition); err != nil {
+		return fmt.Errorf("index definition %s is not a valid JSON object: %s", name, err)
+	}
+	return nil
+}
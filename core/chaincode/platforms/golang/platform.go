@@ -22,6 +22,7 @@ import (
 	"compress/gzip"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -139,6 +140,8 @@ func (goPlatform *Platform) ValidateDeploymentSpec(cds *pb.ChaincodeDeploymentSp
 	// the container itself needs to be the last line of defense and be configured to be
 	// resilient in enforcing constraints. However, we should still do our best to keep as much
 	// garbage out of the system as possible.
+	//
+	// The metadata of the chaincode, e.g. the statedb index definitions, is carried under META-INF
 	re := regexp.MustCompile(`(/)?src/.*`)
 	is := bytes.NewReader(cds.CodePackage)
 	gr, err := gzip.NewReader(is)
@@ -157,7 +160,15 @@ func (goPlatform *Platform) ValidateDeploymentSpec(cds *pb.ChaincodeDeploymentSp
 		// --------------------------------------------------------------------------------------
 		// Check name for conforming path
 		// --------------------------------------------------------------------------------------
-		if !re.MatchString(header.Name) {
+		if strings.HasPrefix(header.Name, metadataDir+"/") {
+			content, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return fmt.Errorf("failure reading metadata file %s: %s", header.Name, err)
+			}
+			if err = validateMetadataFile(header.Name, content); err != nil {
+				return err
+			}
+		} else if !re.MatchString(header.Name) {
 			return fmt.Errorf("illegal file detected in payload: \"%s\"", header.Name)
 		}
 
@@ -400,6 +411,18 @@ func (goPlatform *Platform) GetDeploymentPayload(spec *pb.ChaincodeSpec) ([]byte
 	// --------------------------------------------------------------------------------------
 	vendorDependencies(code.Pkg, files)
 
+	// --------------------------------------------------------------------------------------
+	// Add the metadata of the chaincode (e.g. statedb index definitions), which is placed
+	// under META-INF rather than src/$pkg and is therefore not subject to vendoring
+	// --------------------------------------------------------------------------------------
+	metadata, err := findMetadata(code.Gopath, code.Pkg)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range metadata {
+		files = append(files, file)
+	}
+
 	// --------------------------------------------------------------------------------------
 	// Sort on the filename so the tarball at least looks sane in terms of package grouping
 	// --------------------------------------------------------------------------------------
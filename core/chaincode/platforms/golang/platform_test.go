@@ -92,6 +92,10 @@ func TestValidateCDS(t *testing.T) {
 	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/nowhere", File: "/bin/warez", Mode: 0100400, SuccessExpected: false})
 	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/somewhere", File: "/src/path/to/somewhere/main.go", Mode: 0100400, SuccessExpected: true})
 	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/somewhere", File: "/src/path/to/somewhere/warez", Mode: 0100555, SuccessExpected: false})
+	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/somewhere", File: "META-INF/README.txt", Mode: 0100400, SuccessExpected: true})
+	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/somewhere", File: "META-INF/statedb/couchdb/indexes/indexOwner.txt", Mode: 0100400, SuccessExpected: false})
+	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/somewhere", File: "META-INF/statedb/couchdb/indexes/indexOwner.json", Mode: 0100400, SuccessExpected: false})
+	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/somewhere", File: "META-INF/warez", Mode: 0100555, SuccessExpected: false})
 
 	for _, s := range specs {
 		cds, err := generateFakeCDS(s.CCName, s.Path, s.File, s.Mode)
@@ -175,6 +179,49 @@ func Test_DeploymentPayload(t *testing.T) {
 	}
 }
 
+func Test_DeploymentPayloadWithStateDBArtifacts(t *testing.T) {
+	platform := &Platform{}
+	spec := &pb.ChaincodeSpec{
+		ChaincodeId: &pb.ChaincodeID{
+			Path: "github.com/hyperledger/fabric/examples/chaincode/go/marbles02",
+		},
+	}
+
+	payload, err := platform.GetDeploymentPayload(spec)
+	assert.NoError(t, err)
+
+	gr, err := gzip.NewReader(bytes.NewReader(payload))
+	assert.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	var foundIndexArtifacts []string
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			// We only get here if there are no more entries to scan
+			break
+		}
+		if strings.HasPrefix(header.Name, "META-INF/") {
+			foundIndexArtifacts = append(foundIndexArtifacts, header.Name)
+		}
+	}
+	assert.Equal(t, []string{
+		"META-INF/statedb/couchdb/indexes/indexOwner.json",
+		"META-INF/statedb/couchdb/indexes/indexSizeSortDesc.json",
+	}, foundIndexArtifacts)
+
+	// the payload passes the validation of the deployment spec
+	cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec, CodePackage: payload}
+	assert.NoError(t, platform.ValidateDeploymentSpec(cds))
+}
+
+func Test_validateMetadataFile(t *testing.T) {
+	assert.NoError(t, validateMetadataFile("META-INF/statedb/couchdb/indexes/index.json", []byte(`{"index":{"fields":["owner"]}}`)))
+	assert.NoError(t, validateMetadataFile("META-INF/README.txt", []byte("any content")))
+	assert.Error(t, validateMetadataFile("META-INF/statedb/couchdb/indexes/index.txt", []byte(`{"index":{"fields":["owner"]}}`)))
+	assert.Error(t, validateMetadataFile("META-INF/statedb/couchdb/indexes/index.json", []byte(`{"index":`)))
+}
+
 func Test_decodeUrl(t *testing.T) {
 	cs := &pb.ChaincodeSpec{
 		ChaincodeId: &pb.ChaincodeID{
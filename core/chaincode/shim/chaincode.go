@@ -400,6 +400,52 @@ func (stub *ChaincodeStub) DelState(key string) error {
 	return stub.handler.handleDelState(key, stub.TxID)
 }
 
+// SetStateValidationParameter documentation can be found in interfaces.go
+func (stub *ChaincodeStub) SetStateValidationParameter(key string, ep []byte) error {
+	if key == "" {
+		return fmt.Errorf("key must not be an empty string")
+	}
+	return stub.handler.handlePutStateMetadataEntry(key, pb.MetaDataKeys_VALIDATION_PARAMETER.String(), ep, stub.TxID)
+}
+
+// GetStateValidationParameter documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateValidationParameter(key string) ([]byte, error) {
+	metadata, err := stub.handler.handleGetStateMetadata(key, stub.TxID)
+	if err != nil {
+		return nil, err
+	}
+	return metadata[pb.MetaDataKeys_VALIDATION_PARAMETER.String()], nil
+}
+
+// --------- Private data functions ----------
+
+// GetPrivateData documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetPrivateData(collection string, key string) ([]byte, error) {
+	if collection == "" {
+		return nil, fmt.Errorf("collection must not be an empty string")
+	}
+	return stub.handler.handleGetPrivateData(collection, key, stub.TxID)
+}
+
+// PutPrivateData documentation can be found in interfaces.go
+func (stub *ChaincodeStub) PutPrivateData(collection string, key string, value []byte) error {
+	if collection == "" {
+		return fmt.Errorf("collection must not be an empty string")
+	}
+	if key == "" {
+		return fmt.Errorf("key must not be an empty string")
+	}
+	return stub.handler.handlePutPrivateData(collection, key, value, stub.TxID)
+}
+
+// DelPrivateData documentation can be found in interfaces.go
+func (stub *ChaincodeStub) DelPrivateData(collection string, key string) error {
+	if collection == "" {
+		return fmt.Errorf("collection must not be an empty string")
+	}
+	return stub.handler.handleDelPrivateData(collection, key, stub.TxID)
+}
+
 // CommonIterator documentation can be found in interfaces.go
 type CommonIterator struct {
 	handler    *Handler
@@ -425,12 +471,32 @@ const (
 	HISTORY_QUERY_RESULT
 )
 
-func (stub *ChaincodeStub) handleGetStateByRange(startKey, endKey string) (StateQueryIteratorInterface, error) {
-	response, err := stub.handler.handleGetStateByRange(startKey, endKey, stub.TxID)
+func (stub *ChaincodeStub) handleGetStateByRange(startKey, endKey string,
+	metadata []byte) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	response, err := stub.handler.handleGetStateByRange(startKey, endKey, metadata, stub.TxID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	iterator := &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.TxID, response, 0}}
+	responseMetadata, err := createQueryResponseMetadata(response.Metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	return iterator, responseMetadata, nil
+}
+
+func (stub *ChaincodeStub) handleGetQueryResult(query string,
+	metadata []byte) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	response, err := stub.handler.handleGetQueryResult(query, metadata, stub.TxID)
+	if err != nil {
+		return nil, nil, err
+	}
+	iterator := &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.TxID, response, 0}}
+	responseMetadata, err := createQueryResponseMetadata(response.Metadata)
+	if err != nil {
+		return nil, nil, err
 	}
-	return &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.TxID, response, 0}}, nil
+	return iterator, responseMetadata, nil
 }
 
 // GetStateByRange documentation can be found in interfaces.go
@@ -441,16 +507,58 @@ func (stub *ChaincodeStub) GetStateByRange(startKey, endKey string) (StateQueryI
 	if err := validateSimpleKeys(startKey, endKey); err != nil {
 		return nil, err
 	}
-	return stub.handleGetStateByRange(startKey, endKey)
+	iterator, _, err := stub.handleGetStateByRange(startKey, endKey, nil)
+	return iterator, err
+}
+
+// GetStateByRangeWithPagination documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if startKey == "" {
+		startKey = emptyKeySubstitute
+	}
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, nil, err
+	}
+	metadata, err := createQueryMetadata(pageSize, bookmark)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stub.handleGetStateByRange(startKey, endKey, metadata)
 }
 
 // GetQueryResult documentation can be found in interfaces.go
 func (stub *ChaincodeStub) GetQueryResult(query string) (StateQueryIteratorInterface, error) {
-	response, err := stub.handler.handleGetQueryResult(query, stub.TxID)
+	iterator, _, err := stub.handleGetQueryResult(query, nil)
+	return iterator, err
+}
+
+// GetQueryResultWithPagination documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetQueryResultWithPagination(query string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	metadata, err := createQueryMetadata(pageSize, bookmark)
 	if err != nil {
+		return nil, nil, err
+	}
+	return stub.handleGetQueryResult(query, metadata)
+}
+
+func createQueryMetadata(pageSize int32, bookmark string) ([]byte, error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("pageSize must be greater than zero")
+	}
+	return proto.Marshal(&pb.QueryMetadata{PageSize: pageSize, Bookmark: bookmark})
+}
+
+func createQueryResponseMetadata(metadataBytes []byte) (*pb.QueryResponseMetadata, error) {
+	if len(metadataBytes) == 0 {
+		return nil, nil
+	}
+	metadata := &pb.QueryResponseMetadata{}
+	if err := proto.Unmarshal(metadataBytes, metadata); err != nil {
 		return nil, err
 	}
-	return &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.TxID, response, 0}}, nil
+	return metadata, nil
 }
 
 // GetHistoryForKey documentation can be found in interfaces.go
@@ -532,7 +640,8 @@ func validateSimpleKeys(simpleKeys ...string) error {
 //would be returned.
 func (stub *ChaincodeStub) GetStateByPartialCompositeKey(objectType string, attributes []string) (StateQueryIteratorInterface, error) {
 	if partialCompositeKey, err := stub.CreateCompositeKey(objectType, attributes); err == nil {
-		return stub.handleGetStateByRange(partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue))
+		iterator, _, err := stub.handleGetStateByRange(partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue), nil)
+		return iterator, err
 	} else {
 		return nil, err
 	}
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebased
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddOrg(t *testing.T) {
+	// add an org
+	ep, err := NewStateEP(nil)
+	assert.NoError(t, err)
+	err = ep.AddOrgs(RoleTypeMember, "Org1")
+	assert.NoError(t, err)
+
+	// bad role type
+	err = ep.AddOrgs("unknown", "Org2")
+	assert.Equal(t, &RoleTypeDoesNotExistError{RoleType: RoleType("unknown")}, err)
+	assert.EqualError(t, err, "role type unknown does not exist")
+
+	epBytes, err := ep.Policy()
+	assert.NoError(t, err)
+	expectedEP := cauthdsl.SignedByMspMember("Org1")
+	expectedEPBytes, err := proto.Marshal(expectedEP)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedEPBytes, epBytes)
+}
+
+func TestListOrgs(t *testing.T) {
+	expectedEP := cauthdsl.SignedByMspMember("Org1")
+	expectedEPBytes, err := proto.Marshal(expectedEP)
+	assert.NoError(t, err, "Marshal of the policy should not fail")
+
+	// retrieve the orgs
+	ep, err := NewStateEP(expectedEPBytes)
+	assert.NoError(t, err)
+	orgs := ep.ListOrgs()
+	assert.Equal(t, []string{"Org1"}, orgs)
+
+	_, err = NewStateEP([]byte("garbage"))
+	assert.Error(t, err)
+}
+
+func TestDelAddOrg(t *testing.T) {
+	expectedEP := cauthdsl.SignedByMspMember("Org1")
+	expectedEPBytes, err := proto.Marshal(expectedEP)
+	assert.NoError(t, err)
+	ep, _ := NewStateEP(expectedEPBytes)
+
+	// retrieve the orgs
+	orgs := ep.ListOrgs()
+	assert.ElementsMatch(t, []string{"Org1"}, orgs)
+
+	// mod the endorsement policy
+	ep.AddOrgs(RoleTypeAdmin, "Org2")
+	ep.DelOrgs("Org1")
+
+	// check whether what is stored is correct
+	epBytes, err := ep.Policy()
+	assert.NoError(t, err)
+	expectedEP = cauthdsl.SignedByMspAdmin("Org2")
+	expectedEPBytes, err = proto.Marshal(expectedEP)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedEPBytes, epBytes)
+}
+
+func TestPolicyRequiresAllOrgs(t *testing.T) {
+	ep, _ := NewStateEP(nil)
+	ep.AddOrgs(RoleTypeMember, "Org2", "Org1")
+	epBytes, err := ep.Policy()
+	assert.NoError(t, err)
+
+	spe := &common.SignaturePolicyEnvelope{}
+	assert.NoError(t, proto.Unmarshal(epBytes, spe))
+	assert.Len(t, spe.Identities, 2)
+	assert.Equal(t, int32(2), spe.Rule.GetNOutOf().N)
+	assert.Equal(t, []string{"Org1", "Org2"}, ep.ListOrgs())
+}
@@ -492,7 +492,84 @@ func (handler *Handler) handleDelState(key string, txid string) error {
 	return errors.New(fmt.Sprintf("[%s]Incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR))
 }
 
-func (handler *Handler) handleGetStateByRange(startKey, endKey string, txid string) (*pb.QueryResponse, error) {
+// handleGetPrivateData communicates with the validator to fetch the requested private data from a collection.
+func (handler *Handler) handleGetPrivateData(collection string, key string, txid string) ([]byte, error) {
+	return handler.sendRequest(pb.ChaincodeMessage_GET_PRIVATE_DATA, &pb.PrivateDataInfo{Collection: collection, Key: key}, txid)
+}
+
+// handlePutPrivateData communicates with the validator to put private data into a collection.
+func (handler *Handler) handlePutPrivateData(collection string, key string, value []byte, txid string) error {
+	_, err := handler.sendRequest(pb.ChaincodeMessage_PUT_PRIVATE_DATA, &pb.PrivateDataInfo{Collection: collection, Key: key, Value: value}, txid)
+	return err
+}
+
+// handleDelPrivateData communicates with the validator to delete a key from a collection.
+func (handler *Handler) handleDelPrivateData(collection string, key string, txid string) error {
+	_, err := handler.sendRequest(pb.ChaincodeMessage_DEL_PRIVATE_DATA, &pb.PrivateDataInfo{Collection: collection, Key: key}, txid)
+	return err
+}
+
+// handleGetStateMetadata communicates with the validator to fetch the metadata of a key.
+func (handler *Handler) handleGetStateMetadata(key string, txid string) (map[string][]byte, error) {
+	payload, err := handler.sendRequest(pb.ChaincodeMessage_GET_STATE_METADATA, &pb.GetStateMetadata{Key: key}, txid)
+	if err != nil {
+		return nil, err
+	}
+	result := &pb.StateMetadataResult{}
+	if err = proto.Unmarshal(payload, result); err != nil {
+		return nil, errors.New(fmt.Sprintf("[%s]unmarshal error %s", shorttxid(txid), err))
+	}
+	metadata := make(map[string][]byte)
+	for _, entry := range result.Entries {
+		metadata[entry.Metakey] = entry.Value
+	}
+	return metadata, nil
+}
+
+// handlePutStateMetadataEntry communicates with the validator to set an entry of the metadata of a key.
+func (handler *Handler) handlePutStateMetadataEntry(key string, metakey string, value []byte, txid string) error {
+	info := &pb.PutStateMetadata{Key: key, Metadata: &pb.StateMetadata{Metakey: metakey, Value: value}}
+	_, err := handler.sendRequest(pb.ChaincodeMessage_PUT_STATE_METADATA, info, txid)
+	return err
+}
+
+// sendRequest sends a message of the given type to the validator and waits for the response
+func (handler *Handler) sendRequest(msgType pb.ChaincodeMessage_Type, info proto.Message, txid string) ([]byte, error) {
+	// Create the channel on which to communicate the response from validating peer
+	var respChan chan pb.ChaincodeMessage
+	var err error
+	if respChan, err = handler.createChannel(txid); err != nil {
+		return nil, err
+	}
+
+	defer handler.deleteChannel(txid)
+
+	//we constructed a valid object. No need to check for error
+	payloadBytes, _ := proto.Marshal(info)
+	msg := &pb.ChaincodeMessage{Type: msgType, Payload: payloadBytes, Txid: txid}
+	chaincodeLogger.Debugf("[%s]Sending %s", shorttxid(msg.Txid), msgType)
+
+	var responseMsg pb.ChaincodeMessage
+	if responseMsg, err = handler.sendReceive(msg, respChan); err != nil {
+		return nil, errors.New(fmt.Sprintf("[%s]error sending %s %s", shorttxid(txid), msgType, err))
+	}
+
+	if responseMsg.Type.String() == pb.ChaincodeMessage_RESPONSE.String() {
+		// Success response
+		chaincodeLogger.Debugf("[%s]Received %s for %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_RESPONSE, msgType)
+		return responseMsg.Payload, nil
+	}
+	if responseMsg.Type.String() == pb.ChaincodeMessage_ERROR.String() {
+		// Error response
+		chaincodeLogger.Errorf("[%s]Received %s for %s. Payload: %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_ERROR, msgType, responseMsg.Payload)
+		return nil, errors.New(string(responseMsg.Payload[:]))
+	}
+
+	// Incorrect chaincode message received
+	return nil, errors.New(fmt.Sprintf("[%s]Incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR))
+}
+
+func (handler *Handler) handleGetStateByRange(startKey, endKey string, metadata []byte, txid string) (*pb.QueryResponse, error) {
 	// Create the channel on which to communicate the response from validating peer
 	var respChan chan pb.ChaincodeMessage
 	var err error
@@ -504,7 +581,7 @@ func (handler *Handler) handleGetStateByRange(startKey, endKey string, txid stri
 
 	// Send GET_STATE_BY_RANGE message to validator chaincode support
 	//we constructed a valid object. No need to check for error
-	payloadBytes, _ := proto.Marshal(&pb.GetStateByRange{StartKey: startKey, EndKey: endKey})
+	payloadBytes, _ := proto.Marshal(&pb.GetStateByRange{StartKey: startKey, EndKey: endKey, Metadata: metadata})
 
 	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE_BY_RANGE, Payload: payloadBytes, Txid: txid}
 	chaincodeLogger.Debugf("[%s]Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_BY_RANGE)
@@ -623,7 +700,7 @@ func (handler *Handler) handleQueryStateClose(id, txid string) (*pb.QueryRespons
 	return nil, errors.New(fmt.Sprintf("Incorrect chaincode message %s received. Expecting %s or %s", responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR))
 }
 
-func (handler *Handler) handleGetQueryResult(query string, txid string) (*pb.QueryResponse, error) {
+func (handler *Handler) handleGetQueryResult(query string, metadata []byte, txid string) (*pb.QueryResponse, error) {
 	// Create the channel on which to communicate the response from validating peer
 	var respChan chan pb.ChaincodeMessage
 	var err error
@@ -635,7 +712,7 @@ func (handler *Handler) handleGetQueryResult(query string, txid string) (*pb.Que
 
 	// Send GET_QUERY_RESULT message to validator chaincode support
 	//we constructed a valid object. No need to check for error
-	payloadBytes, _ := proto.Marshal(&pb.GetQueryResult{Query: query})
+	payloadBytes, _ := proto.Marshal(&pb.GetQueryResult{Query: query, Metadata: metadata})
 
 	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_QUERY_RESULT, Payload: payloadBytes, Txid: txid}
 	chaincodeLogger.Debugf("[%s]Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_QUERY_RESULT)
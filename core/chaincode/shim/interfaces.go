@@ -80,6 +80,11 @@ type ChaincodeStubInterface interface {
 	// read set and write set will be applied to the transaction. Effectively
 	// the called chaincode on a different channel is a `Query`, which does not
 	// participate in state validation checks in subsequent commit phase.
+	// When the peer enables the XSCC system chaincode, the writes of the called
+	// chaincode on a different channel are instead endorsed as the part of a
+	// cross-channel transaction on that channel, returned along with the
+	// proposal response; each part is ordered on its own channel, and the
+	// transaction is then committed or aborted on every channel through XSCC.
 	// If `channel` is empty, the caller's channel is assumed.
 	// 用于链码的互操作(只读)
 	InvokeChaincode(chaincodeName string, args [][]byte, channel string) pb.Response
@@ -108,6 +113,46 @@ type ChaincodeStubInterface interface {
 	// 删除状态
 	DelState(key string) error
 
+	// SetStateValidationParameter sets the key-level endorsement policy for `key`.
+	// The policy is a serialized SignaturePolicyEnvelope (see package
+	// `shim/ext/statebased` for a helper to build it). Once committed, the
+	// transactions which write `key` must satisfy this policy instead of the
+	// endorsement policy of the chaincode, unless they also write keys without
+	// a key-level policy or private data. A nil `ep` removes the policy.
+	// 设置键级背书策略
+	SetStateValidationParameter(key string, ep []byte) error
+
+	// GetStateValidationParameter returns the key-level endorsement policy of
+	// `key` as committed to the ledger, or nil if the key has none.
+	// 获取键级背书策略
+	GetStateValidationParameter(key string) ([]byte, error)
+
+	// GetPrivateData returns the value of the specified `key` from the specified
+	// `collection`. Note that GetPrivateData doesn't read data from the
+	// private writeset, which has not been committed to the `collection`. In
+	// other words, GetPrivateData doesn't consider data modified by PutPrivateData
+	// that has not been committed.
+	// 获取私有数据
+	GetPrivateData(collection, key string) ([]byte, error)
+
+	// PutPrivateData puts the specified `key` and `value` into the transaction's
+	// private writeset. Note that only hash of the private writeset goes into the
+	// transaction proposal response (which is sent to the client who issued the
+	// transaction) and the actual private writeset gets temporarily stored in a
+	// transient store. PutPrivateData doesn't modify the private data in the
+	// `collection` until the transaction is validated and successfully committed.
+	// 更新私有数据
+	PutPrivateData(collection string, key string, value []byte) error
+
+	// DelPrivateData records the specified `key` to be deleted in the private writeset
+	// of the transaction. Note that only hash of the private writeset goes into the
+	// transaction proposal response (which is sent to the client who issued the
+	// transaction) and the actual private writeset gets temporarily stored in a
+	// transient store. The `key` and its value will be deleted from the collection
+	// when the transaction is validated and successfully committed.
+	// 删除私有数据
+	DelPrivateData(collection, key string) error
+
 	// GetStateByRange returns a range iterator over a set of keys in the
 	// ledger. The iterator can be used to iterate over all keys
 	// between the startKey (inclusive) and endKey (exclusive).
@@ -120,6 +165,17 @@ type ChaincodeStubInterface interface {
 	// 获取状态区间
 	GetStateByRange(startKey, endKey string) (StateQueryIteratorInterface, error)
 
+	// GetStateByRangeWithPagination returns a range iterator over at most
+	// pageSize keys in the ledger between the startKey (inclusive) and endKey
+	// (exclusive), starting at the given bookmark. An empty bookmark refers to
+	// the startKey. The returned QueryResponseMetadata contains the number of
+	// fetched records and the bookmark to be passed to the next call to fetch
+	// the next page; the bookmark is empty when there are no more results.
+	// Call Close() on the returned StateQueryIteratorInterface object when done.
+	// 分页获取状态区间
+	GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+		bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
 	// GetStateByPartialCompositeKey queries the state in the ledger based on
 	// a given partial composite key. This function returns an iterator
 	// which can be used to iterate over all composite keys whose prefix matches
@@ -158,6 +214,16 @@ type ChaincodeStubInterface interface {
 	// ledger, and should limit use to read-only chaincode operations.
 	GetQueryResult(query string) (StateQueryIteratorInterface, error)
 
+	// GetQueryResultWithPagination performs a "rich" query against a state
+	// database like GetQueryResult, returning at most pageSize results which
+	// follow the given bookmark. An empty bookmark refers to the first result
+	// of the query. The returned QueryResponseMetadata contains the number of
+	// fetched records and the bookmark to be passed to the next call to fetch
+	// the next page. As for GetQueryResult, phantom reads are not detected, so
+	// the use should be limited to read-only chaincode operations.
+	GetQueryResultWithPagination(query string, pageSize int32,
+		bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
 	// GetHistoryForKey returns a history of key values across time.
 	// For each historic key update, the historic value and associated
 	// transaction id and timestamp are returned. The timestamp is the
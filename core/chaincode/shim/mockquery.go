@@ -0,0 +1,491 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+)
+
+// mockRichQuery is the subset of a CouchDB Mango query understood by the MockStub
+type mockRichQuery struct {
+	Selector map[string]interface{} `json:"selector"`
+	Fields   []string               `json:"fields"`
+	Sort     []interface{}          `json:"sort"`
+	Limit    int                    `json:"limit"`
+	Skip     int                    `json:"skip"`
+}
+
+type mockSortField struct {
+	path string
+	desc bool
+}
+
+type mockQueryMatch struct {
+	key string
+	doc map[string]interface{}
+}
+
+// executeMockQuery evaluates a Mango query over the JSON values of the state of the stub.
+// The values which are not JSON objects are never returned, as with CouchDB. Unless the
+// query specifies a sort, the results are ordered by key.
+func executeMockQuery(stub *MockStub, query string) ([]*queryresult.KV, error) {
+	q := &mockRichQuery{}
+	if err := json.Unmarshal([]byte(query), q); err != nil {
+		return nil, fmt.Errorf("invalid query [%s]: %s", query, err)
+	}
+	if q.Selector == nil {
+		return nil, fmt.Errorf("invalid query [%s]: a selector is required", query)
+	}
+	if q.Skip < 0 || q.Limit < 0 {
+		return nil, fmt.Errorf("invalid query [%s]: skip and limit must not be negative", query)
+	}
+	sortFields, err := parseMockSort(q.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*mockQueryMatch
+	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
+		key := elem.Value.(string)
+		doc := make(map[string]interface{})
+		if err := json.Unmarshal(stub.State[key], &doc); err != nil {
+			continue
+		}
+		matched, err := matchMockCondition(doc, true, q.Selector)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			matches = append(matches, &mockQueryMatch{key: key, doc: doc})
+		}
+	}
+	if len(sortFields) > 0 {
+		sort.Stable(&mockQuerySorter{matches, sortFields})
+	}
+
+	if q.Skip >= len(matches) {
+		return nil, nil
+	}
+	matches = matches[q.Skip:]
+	if q.Limit > 0 && q.Limit < len(matches) {
+		matches = matches[:q.Limit]
+	}
+
+	results := make([]*queryresult.KV, 0, len(matches))
+	for _, match := range matches {
+		value := stub.State[match.key]
+		if len(q.Fields) > 0 {
+			if value, err = json.Marshal(projectMockFields(match.doc, q.Fields)); err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, &queryresult.KV{Key: match.key, Value: value})
+	}
+	return results, nil
+}
+
+func parseMockSort(sortSpec []interface{}) ([]*mockSortField, error) {
+	var sortFields []*mockSortField
+	for _, s := range sortSpec {
+		switch field := s.(type) {
+		case string:
+			sortFields = append(sortFields, &mockSortField{path: field})
+		case map[string]interface{}:
+			if len(field) != 1 {
+				return nil, fmt.Errorf("invalid sort field %v: expected a single field", field)
+			}
+			for path, direction := range field {
+				switch direction {
+				case "asc":
+					sortFields = append(sortFields, &mockSortField{path: path})
+				case "desc":
+					sortFields = append(sortFields, &mockSortField{path: path, desc: true})
+				default:
+					return nil, fmt.Errorf("invalid sort direction %v of field %s", direction, path)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("invalid sort field %v", s)
+		}
+	}
+	return sortFields, nil
+}
+
+type mockQuerySorter struct {
+	matches    []*mockQueryMatch
+	sortFields []*mockSortField
+}
+
+func (s *mockQuerySorter) Len() int {
+	return len(s.matches)
+}
+
+func (s *mockQuerySorter) Swap(i, j int) {
+	s.matches[i], s.matches[j] = s.matches[j], s.matches[i]
+}
+
+func (s *mockQuerySorter) Less(i, j int) bool {
+	for _, field := range s.sortFields {
+		vi, _ := getMockField(s.matches[i].doc, field.path)
+		vj, _ := getMockField(s.matches[j].doc, field.path)
+		comp := compareMockValues(vi, vj)
+		if comp == 0 {
+			continue
+		}
+		if field.desc {
+			return comp > 0
+		}
+		return comp < 0
+	}
+	return false
+}
+
+// matchMockCondition evaluates a selector (or the condition on a field) against the given value.
+// 'exists' is false if the value is a field which is missing from the document
+func matchMockCondition(value interface{}, exists bool, condition interface{}) (bool, error) {
+	conditionMap, ok := condition.(map[string]interface{})
+	if !ok {
+		// implicit equality
+		return exists && reflect.DeepEqual(value, condition), nil
+	}
+	for name, arg := range conditionMap {
+		var matched bool
+		var err error
+		if strings.HasPrefix(name, "$") {
+			matched, err = matchMockOperator(value, exists, name, arg)
+		} else {
+			field, fieldExists := getMockField(value, name)
+			matched, err = matchMockCondition(field, fieldExists, arg)
+		}
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchMockOperator(value interface{}, exists bool, operator string, arg interface{}) (bool, error) {
+	switch operator {
+	case "$and", "$or", "$nor":
+		conditions, ok := arg.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("operator %s expects an array", operator)
+		}
+		for _, condition := range conditions {
+			matched, err := matchMockCondition(value, exists, condition)
+			if err != nil {
+				return false, err
+			}
+			if operator == "$and" && !matched {
+				return false, nil
+			}
+			if operator == "$or" && matched {
+				return true, nil
+			}
+			if operator == "$nor" && matched {
+				return false, nil
+			}
+		}
+		return operator != "$or", nil
+	case "$not":
+		matched, err := matchMockCondition(value, exists, arg)
+		return !matched, err
+	case "$exists":
+		expected, ok := arg.(bool)
+		if !ok {
+			return false, errors.New("operator $exists expects a boolean")
+		}
+		return exists == expected, nil
+	}
+
+	// all the other operators require the field to exist
+	if !exists {
+		return false, nil
+	}
+	switch operator {
+	case "$eq":
+		return reflect.DeepEqual(value, arg), nil
+	case "$ne":
+		return !reflect.DeepEqual(value, arg), nil
+	case "$gt":
+		return compareMockValues(value, arg) > 0, nil
+	case "$gte":
+		return compareMockValues(value, arg) >= 0, nil
+	case "$lt":
+		return compareMockValues(value, arg) < 0, nil
+	case "$lte":
+		return compareMockValues(value, arg) <= 0, nil
+	case "$in", "$nin":
+		candidates, ok := arg.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("operator %s expects an array", operator)
+		}
+		// an array field matches if any of its elements is in the candidates
+		elems, isArray := value.([]interface{})
+		if !isArray {
+			elems = []interface{}{value}
+		}
+		in := false
+		for _, elem := range elems {
+			if containsMockValue(candidates, elem) {
+				in = true
+				break
+			}
+		}
+		return in == (operator == "$in"), nil
+	case "$regex":
+		pattern, ok := arg.(string)
+		if !ok {
+			return false, errors.New("operator $regex expects a string")
+		}
+		s, ok := value.(string)
+		if !ok {
+			return false, nil
+		}
+		return regexp.MatchString(pattern, s)
+	case "$type":
+		return mockValueType(value) == arg, nil
+	case "$mod":
+		operands, ok := arg.([]interface{})
+		if !ok || len(operands) != 2 {
+			return false, errors.New("operator $mod expects an array of divisor and remainder")
+		}
+		divisor, ok1 := operands[0].(float64)
+		remainder, ok2 := operands[1].(float64)
+		if !ok1 || !ok2 || divisor == 0 {
+			return false, errors.New("operator $mod expects a non-zero divisor and a remainder")
+		}
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			return false, nil
+		}
+		return math.Mod(n, divisor) == remainder, nil
+	case "$size":
+		elems, ok := value.([]interface{})
+		return ok && reflect.DeepEqual(float64(len(elems)), arg), nil
+	case "$all":
+		expected, ok := arg.([]interface{})
+		if !ok {
+			return false, errors.New("operator $all expects an array")
+		}
+		elems, ok := value.([]interface{})
+		if !ok {
+			return false, nil
+		}
+		for _, e := range expected {
+			if !containsMockValue(elems, e) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case "$elemMatch", "$allMatch":
+		elems, ok := value.([]interface{})
+		if !ok || len(elems) == 0 {
+			return false, nil
+		}
+		for _, elem := range elems {
+			matched, err := matchMockCondition(elem, true, arg)
+			if err != nil {
+				return false, err
+			}
+			if operator == "$elemMatch" && matched {
+				return true, nil
+			}
+			if operator == "$allMatch" && !matched {
+				return false, nil
+			}
+		}
+		return operator == "$allMatch", nil
+	}
+	return false, fmt.Errorf("operator %s is not supported", operator)
+}
+
+// getMockField returns the value of a field of a JSON object; the fields of
+// the nested objects are separated by dots
+func getMockField(value interface{}, path string) (interface{}, bool) {
+	for _, name := range strings.Split(path, ".") {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = obj[name]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func projectMockFields(doc map[string]interface{}, fields []string) map[string]interface{} {
+	projection := make(map[string]interface{})
+	for _, path := range fields {
+		value, ok := getMockField(doc, path)
+		if !ok {
+			continue
+		}
+		names := strings.Split(path, ".")
+		obj := projection
+		for _, name := range names[:len(names)-1] {
+			nested, ok := obj[name].(map[string]interface{})
+			if !ok {
+				nested = make(map[string]interface{})
+				obj[name] = nested
+			}
+			obj = nested
+		}
+		obj[names[len(names)-1]] = value
+	}
+	return projection
+}
+
+func containsMockValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func mockValueType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// compareMockValues compares two JSON values following the CouchDB collation:
+// null < booleans < numbers < strings < arrays < objects
+func compareMockValues(a, b interface{}) int {
+	rankA, rankB := mockCollationRank(a), mockCollationRank(b)
+	if rankA != rankB {
+		return rankA - rankB
+	}
+	switch va := a.(type) {
+	case bool:
+		vb := b.(bool)
+		if va == vb {
+			return 0
+		}
+		if !va {
+			return -1
+		}
+		return 1
+	case float64:
+		vb := b.(float64)
+		if va < vb {
+			return -1
+		}
+		if va > vb {
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(va, b.(string))
+	case []interface{}:
+		vb := b.([]interface{})
+		for i := 0; i < len(va) && i < len(vb); i++ {
+			if comp := compareMockValues(va[i], vb[i]); comp != 0 {
+				return comp
+			}
+		}
+		return len(va) - len(vb)
+	case map[string]interface{}:
+		// objects are rarely compared, their JSON encodings (with sorted keys) give a stable order
+		ja, _ := json.Marshal(va)
+		jb, _ := json.Marshal(b)
+		return strings.Compare(string(ja), string(jb))
+	}
+	return 0
+}
+
+func mockCollationRank(value interface{}) int {
+	switch value.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64:
+		return 2
+	case string:
+		return 3
+	case []interface{}:
+		return 4
+	default:
+		return 5
+	}
+}
+
+/*****************************
+ Query Result Iterators
+*****************************/
+
+// mockQueryResultIterator iterates over the precomputed results of a rich query
+type mockQueryResultIterator struct {
+	results []*queryresult.KV
+	closed  bool
+}
+
+func (iter *mockQueryResultIterator) HasNext() bool {
+	return !iter.closed && len(iter.results) > 0
+}
+
+func (iter *mockQueryResultIterator) Next() (*queryresult.KV, error) {
+	if !iter.HasNext() {
+		return nil, errors.New("mockQueryResultIterator.Next() called when it does not HaveNext()")
+	}
+	result := iter.results[0]
+	iter.results = iter.results[1:]
+	return result, nil
+}
+
+func (iter *mockQueryResultIterator) Close() error {
+	iter.closed = true
+	return nil
+}
+
+// mockHistoryQueryIterator iterates over the committed modifications of a key
+type mockHistoryQueryIterator struct {
+	modifications []*queryresult.KeyModification
+	closed        bool
+}
+
+func (iter *mockHistoryQueryIterator) HasNext() bool {
+	return !iter.closed && len(iter.modifications) > 0
+}
+
+func (iter *mockHistoryQueryIterator) Next() (*queryresult.KeyModification, error) {
+	if !iter.HasNext() {
+		return nil, errors.New("mockHistoryQueryIterator.Next() called when it does not HaveNext()")
+	}
+	modification := iter.modifications[0]
+	iter.modifications = iter.modifications[1:]
+	return modification, nil
+}
+
+func (iter *mockHistoryQueryIterator) Close() error {
+	iter.closed = true
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"container/list"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
@@ -53,9 +54,31 @@ type MockStub struct {
 	// Keys stores the list of mapped values in lexical order
 	Keys *list.List
 
+	// PvtState keeps the private data name value pairs of each collection
+	PvtState map[string]map[string][]byte
+
+	// EndorsementPolicies keeps the key-level endorsement policies
+	EndorsementPolicies map[string][]byte
+
+	// History keeps the modifications of each key committed by the transactions, oldest first
+	History map[string][]*queryresult.KeyModification
+
 	// registered list of other MockStub chaincodes that can be called from this MockStub
 	Invokables map[string]*MockStub
 
+	// ChannelID is the channel the chaincode runs on. The chaincodes invoked on other
+	// channels can only be queried, their writes are discarded as by the peer
+	ChannelID string
+
+	// Creator is returned by GetCreator
+	Creator []byte
+
+	// TransientMap is returned by GetTransient
+	TransientMap map[string][]byte
+
+	// ChaincodeEvents keeps the events set by the transactions, in order
+	ChaincodeEvents []*pb.ChaincodeEvent
+
 	// stores a transaction uuid while being Invoked / Deployed
 	// TODO if a chaincode uses recursion this may need to be a stack of TxIDs or possibly a reference counting map
 	TxID string
@@ -64,6 +87,11 @@ type MockStub struct {
 
 	// mocked signedProposal
 	signedProposal *pb.SignedProposal
+
+	// the modifications and the event of the current transaction,
+	// recorded in History and ChaincodeEvents when it ends
+	txModifications map[string]*queryresult.KeyModification
+	txEvent         *pb.ChaincodeEvent
 }
 
 func (stub *MockStub) GetTxID() string {
@@ -101,14 +129,48 @@ func (stub *MockStub) MockTransactionStart(txid string) {
 	stub.TxID = txid
 	stub.setSignedProposal(&pb.SignedProposal{})
 	stub.setTxTimestamp(util.CreateUtcTimestamp())
+	stub.txModifications = make(map[string]*queryresult.KeyModification)
+	stub.txEvent = nil
 }
 
-// End a mocked transaction, clearing the UUID.
+// End a mocked transaction, clearing the UUID. The modifications of the keys
+// made by the transaction are recorded in the History, along with its event if any.
 func (stub *MockStub) MockTransactionEnd(uuid string) {
+	keys := make([]string, 0, len(stub.txModifications))
+	for key := range stub.txModifications {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		stub.History[key] = append(stub.History[key], stub.txModifications[key])
+	}
+	if stub.txEvent != nil {
+		stub.ChaincodeEvents = append(stub.ChaincodeEvents, stub.txEvent)
+	}
+	stub.abortTransaction()
+}
+
+// abortTransaction ends the current transaction without recording its modifications and event
+func (stub *MockStub) abortTransaction() {
+	stub.txModifications = nil
+	stub.txEvent = nil
 	stub.signedProposal = nil
 	stub.TxID = ""
 }
 
+// recordModification keeps the last modification of the key by the current transaction
+func (stub *MockStub) recordModification(key string, value []byte, isDelete bool) {
+	if stub.txModifications == nil {
+		return
+	}
+	stub.txModifications[key] = &queryresult.KeyModification{
+		TxId:      stub.TxID,
+		Value:     value,
+		Timestamp: stub.TxTimestamp,
+		IsDelete:  isDelete,
+	}
+}
+
 // Register a peer chaincode with this MockStub
 // invokableChaincodeName is the name or hash of the peer
 // otherStub is a MockStub of the peer, already intialised
@@ -160,6 +222,7 @@ func (stub *MockStub) PutState(key string, value []byte) error {
 
 	mockLogger.Debug("MockStub", stub.Name, "Putting", key, value)
 	stub.State[key] = value
+	stub.recordModification(key, value, false)
 
 	// insert key into ordered list of keys
 	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
@@ -198,6 +261,8 @@ func (stub *MockStub) PutState(key string, value []byte) error {
 func (stub *MockStub) DelState(key string) error {
 	mockLogger.Debug("MockStub", stub.Name, "Deleting", key, stub.State[key])
 	delete(stub.State, key)
+	delete(stub.EndorsementPolicies, key)
+	stub.recordModification(key, nil, true)
 
 	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
 		if strings.Compare(key, elem.Value.(string)) == 0 {
@@ -208,6 +273,57 @@ func (stub *MockStub) DelState(key string) error {
 	return nil
 }
 
+// SetStateValidationParameter sets the key-level endorsement policy of the specified `key`.
+func (stub *MockStub) SetStateValidationParameter(key string, ep []byte) error {
+	if stub.TxID == "" {
+		mockLogger.Error("Cannot SetStateValidationParameter without a transactions - call stub.MockTransactionStart()?")
+		return errors.New("Cannot SetStateValidationParameter without a transactions - call stub.MockTransactionStart()?")
+	}
+	if ep == nil {
+		delete(stub.EndorsementPolicies, key)
+		return nil
+	}
+	stub.EndorsementPolicies[key] = ep
+	return nil
+}
+
+// GetStateValidationParameter returns the key-level endorsement policy of the specified `key`.
+func (stub *MockStub) GetStateValidationParameter(key string) ([]byte, error) {
+	return stub.EndorsementPolicies[key], nil
+}
+
+// GetPrivateData returns the value of the specified `key` from the specified `collection`.
+func (stub *MockStub) GetPrivateData(collection string, key string) ([]byte, error) {
+	m, in := stub.PvtState[collection]
+	if !in {
+		return nil, nil
+	}
+	return m[key], nil
+}
+
+// PutPrivateData writes the specified `value` and `key` into the specified `collection`.
+func (stub *MockStub) PutPrivateData(collection string, key string, value []byte) error {
+	if stub.TxID == "" {
+		mockLogger.Error("Cannot PutPrivateData without a transactions - call stub.MockTransactionStart()?")
+		return errors.New("Cannot PutPrivateData without a transactions - call stub.MockTransactionStart()?")
+	}
+	if _, in := stub.PvtState[collection]; !in {
+		stub.PvtState[collection] = make(map[string][]byte)
+	}
+	mockLogger.Debug("MockStub", stub.Name, "Putting private data", collection, key)
+	stub.PvtState[collection][key] = value
+	return nil
+}
+
+// DelPrivateData removes the specified `key` from the specified `collection`.
+func (stub *MockStub) DelPrivateData(collection string, key string) error {
+	mockLogger.Debug("MockStub", stub.Name, "Deleting private data", collection, key)
+	if m, in := stub.PvtState[collection]; in {
+		delete(m, key)
+	}
+	return nil
+}
+
 func (stub *MockStub) GetStateByRange(startKey, endKey string) (StateQueryIteratorInterface, error) {
 	if err := validateSimpleKeys(startKey, endKey); err != nil {
 		return nil, err
@@ -215,22 +331,100 @@ func (stub *MockStub) GetStateByRange(startKey, endKey string) (StateQueryIterat
 	return NewMockStateRangeQueryIterator(stub, startKey, endKey), nil
 }
 
+// GetStateByRangeWithPagination returns an iterator over at most pageSize keys of the
+// range, starting at the bookmark if one is given. The bookmark of the returned
+// metadata is the first key of the next page.
+func (stub *MockStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, nil, err
+	}
+	if pageSize <= 0 {
+		return nil, nil, errors.New("pageSize must be greater than zero")
+	}
+	if bookmark != "" {
+		startKey = bookmark
+	}
+
+	var pageKeys []string
+	nextKey := ""
+	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
+		key := elem.Value.(string)
+		if key < startKey || (endKey != "" && key >= endKey) {
+			continue
+		}
+		if int32(len(pageKeys)) == pageSize {
+			nextKey = key
+			break
+		}
+		pageKeys = append(pageKeys, key)
+	}
+
+	metadata := &pb.QueryResponseMetadata{FetchedRecordsCount: int32(len(pageKeys)), Bookmark: nextKey}
+	if len(pageKeys) == 0 {
+		// an inverted range, which no key falls into
+		return NewMockStateRangeQueryIterator(stub, emptyKeySubstitute, compositeKeyNamespace), metadata, nil
+	}
+	// the end key of the mock iterator is inclusive
+	return NewMockStateRangeQueryIterator(stub, pageKeys[0], pageKeys[len(pageKeys)-1]), metadata, nil
+}
+
 // GetQueryResult function can be invoked by a chaincode to perform a
 // rich query against state database.  Only supported by state database implementations
 // that support rich query.  The query string is in the syntax of the underlying
 // state database. An iterator is returned which can be used to iterate (next) over
-// the query result set
+// the query result set.
+// The MockStub evaluates the CouchDB queries over the JSON values of its State. It supports
+// the selector operators $eq, $ne, $gt, $gte, $lt, $lte, $exists, $type, $in, $nin, $regex,
+// $mod, $size, $all, $elemMatch, $allMatch, $and, $or, $nor and $not, nested fields
+// (separated by dots) as well as the fields, sort, limit and skip properties of the query.
 func (stub *MockStub) GetQueryResult(query string) (StateQueryIteratorInterface, error) {
-	// Not implemented since the mock engine does not have a query engine.
-	// However, a very simple query engine that supports string matching
-	// could be implemented to test that the framework supports queries
-	return nil, errors.New("Not Implemented")
+	results, err := executeMockQuery(stub, query)
+	if err != nil {
+		return nil, err
+	}
+	return &mockQueryResultIterator{results: results}, nil
+}
+
+// GetQueryResultWithPagination returns an iterator over at most pageSize results of
+// the rich query, starting at the bookmark if one is given. The bookmark of the
+// returned metadata is the key of the first result of the next page.
+func (stub *MockStub) GetQueryResultWithPagination(query string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if pageSize <= 0 {
+		return nil, nil, errors.New("pageSize must be greater than zero")
+	}
+	results, err := executeMockQuery(stub, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	if bookmark != "" {
+		start := len(results)
+		for i, kv := range results {
+			if kv.Key == bookmark {
+				start = i
+				break
+			}
+		}
+		results = results[start:]
+	}
+	nextKey := ""
+	if int32(len(results)) > pageSize {
+		nextKey = results[pageSize].Key
+		results = results[:pageSize]
+	}
+	metadata := &pb.QueryResponseMetadata{FetchedRecordsCount: int32(len(results)), Bookmark: nextKey}
+	return &mockQueryResultIterator{results: results}, metadata, nil
 }
 
 // GetHistoryForKey function can be invoked by a chaincode to return a history of
 // key values across time. GetHistoryForKey is intended to be used for read-only queries.
+// The MockStub returns the modifications committed by the transactions which have
+// ended, oldest first.
 func (stub *MockStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error) {
-	return nil, errors.New("Not Implemented")
+	modifications := make([]*queryresult.KeyModification, len(stub.History[key]))
+	copy(modifications, stub.History[key])
+	return &mockHistoryQueryIterator{modifications: modifications}, nil
 }
 
 //GetStateByPartialCompositeKey function can be invoked by a chaincode to query the
@@ -262,29 +456,72 @@ func (stub *MockStub) SplitCompositeKey(compositeKey string) (string, []string,
 // InvokeChaincode calls a peered chaincode.
 // E.g. stub1.InvokeChaincode("stub2Hash", funcArgs, channel)
 // Before calling this make sure to create another MockStub stub2, call stub2.MockInit(uuid, func, args)
-// and register it with stub1 by calling stub1.MockPeerChaincode("stub2Hash", stub2)
+// and register it with stub1 by calling stub1.MockPeerChaincode("stub2Hash", stub2), or
+// stub1.MockPeerChaincode("stub2Hash/channel", stub2) for a chaincode of another channel.
+// As on a peer without XSCC, the writes of a chaincode invoked on another channel are discarded.
 func (stub *MockStub) InvokeChaincode(chaincodeName string, args [][]byte, channel string) pb.Response {
-	// Internally we use chaincode name as a composite name
+	// Internally we use chaincode name as a composite name, the chaincodes
+	// of the channel of this chaincode may be registered with their name only
+	otherStub, ok := stub.Invokables[chaincodeName]
 	if channel != "" {
-		chaincodeName = chaincodeName + "/" + channel
+		otherStub, ok = stub.Invokables[chaincodeName+"/"+channel]
+		if !ok && channel == stub.ChannelID {
+			otherStub, ok = stub.Invokables[chaincodeName]
+		}
+	}
+	if !ok {
+		mockLogger.Error("MockStub", stub.Name, "Peer chaincode", chaincodeName, "on channel", channel, "is not registered")
+		return Error(fmt.Sprintf("chaincode %s on channel [%s] is not registered - call stub.MockPeerChaincode()?", chaincodeName, channel))
 	}
 	// TODO "args" here should possibly be a serialized pb.ChaincodeInput
-	otherStub := stub.Invokables[chaincodeName]
 	mockLogger.Debug("MockStub", stub.Name, "Invoking peer chaincode", otherStub.Name, args)
-	//	function, strings := getFuncArgs(args)
-	res := otherStub.MockInvoke(stub.TxID, args)
+	var res pb.Response
+	if channel != "" && channel != stub.ChannelID {
+		res = otherStub.mockQuery(stub.TxID, args)
+	} else {
+		res = otherStub.MockInvoke(stub.TxID, args)
+	}
 	mockLogger.Debug("MockStub", stub.Name, "Invoked peer chaincode", otherStub.Name, "got", fmt.Sprintf("%+v", res))
 	return res
 }
 
-// Not implemented
+// mockQuery invokes this chaincode within a transaction whose writes are discarded
+func (stub *MockStub) mockQuery(uuid string, args [][]byte) pb.Response {
+	state := make(map[string][]byte, len(stub.State))
+	for key, value := range stub.State {
+		state[key] = value
+	}
+	keys := list.New()
+	keys.PushBackList(stub.Keys)
+	pvtState := make(map[string]map[string][]byte, len(stub.PvtState))
+	for collection, m := range stub.PvtState {
+		pvtState[collection] = make(map[string][]byte, len(m))
+		for key, value := range m {
+			pvtState[collection][key] = value
+		}
+	}
+	endorsementPolicies := make(map[string][]byte, len(stub.EndorsementPolicies))
+	for key, ep := range stub.EndorsementPolicies {
+		endorsementPolicies[key] = ep
+	}
+
+	stub.args = args
+	stub.MockTransactionStart(uuid)
+	res := stub.cc.Invoke(stub)
+	stub.abortTransaction()
+
+	stub.State, stub.Keys, stub.PvtState, stub.EndorsementPolicies = state, keys, pvtState, endorsementPolicies
+	return res
+}
+
+// GetCreator returns the Creator set on the MockStub
 func (stub *MockStub) GetCreator() ([]byte, error) {
-	return nil, nil
+	return stub.Creator, nil
 }
 
-// Not implemented
+// GetTransient returns the TransientMap set on the MockStub
 func (stub *MockStub) GetTransient() (map[string][]byte, error) {
-	return nil, nil
+	return stub.TransientMap, nil
 }
 
 // Not implemented
@@ -317,8 +554,18 @@ func (stub *MockStub) GetTxTimestamp() (*timestamp.Timestamp, error) {
 	return stub.TxTimestamp, nil
 }
 
-// Not implemented
+// SetEvent sets the event of the current transaction, which is recorded
+// in ChaincodeEvents when the transaction ends. As on the peer, a transaction
+// has at most one event, the last one set.
 func (stub *MockStub) SetEvent(name string, payload []byte) error {
+	if name == "" {
+		return errors.New("Event name can not be nil string.")
+	}
+	if stub.TxID == "" {
+		mockLogger.Error("Cannot SetEvent without a transactions - call stub.MockTransactionStart()?")
+		return errors.New("Cannot SetEvent without a transactions - call stub.MockTransactionStart()?")
+	}
+	stub.txEvent = &pb.ChaincodeEvent{ChaincodeId: stub.Name, TxId: stub.TxID, EventName: name, Payload: payload}
 	return nil
 }
 
@@ -329,6 +576,9 @@ func NewMockStub(name string, cc Chaincode) *MockStub {
 	s.Name = name
 	s.cc = cc
 	s.State = make(map[string][]byte)
+	s.PvtState = make(map[string]map[string][]byte)
+	s.EndorsementPolicies = make(map[string][]byte)
+	s.History = make(map[string][]*queryresult.KeyModification)
 	s.Invokables = make(map[string]*MockStub)
 	s.Keys = list.New()
 
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/viper"
 )
 
@@ -223,6 +224,388 @@ func TestGetStateByPartialCompositeKeyCollision(t *testing.T) {
 	}
 }
 
+func TestMockStubPrivateData(t *testing.T) {
+	stub := NewMockStub("PrivateDataTest", nil)
+	if err := stub.PutPrivateData("coll1", "key1", []byte("value1")); err == nil {
+		t.Fatal("PutPrivateData should fail outside of a transaction")
+	}
+
+	stub.MockTransactionStart("init")
+	stub.PutPrivateData("coll1", "key1", []byte("value1"))
+	stub.PutPrivateData("coll2", "key1", []byte("value2"))
+	stub.MockTransactionEnd("init")
+
+	value, err := stub.GetPrivateData("coll1", "key1")
+	if err != nil || string(value) != "value1" {
+		t.Fatalf("Expected value1, got %s (err %v)", value, err)
+	}
+	value, _ = stub.GetPrivateData("coll2", "key1")
+	if string(value) != "value2" {
+		t.Fatalf("Expected value2, got %s", value)
+	}
+	// private data is never visible through the public state
+	if value, _ = stub.GetState("key1"); value != nil {
+		t.Fatalf("Expected no public state for key1, got %s", value)
+	}
+
+	stub.DelPrivateData("coll1", "key1")
+	if value, _ = stub.GetPrivateData("coll1", "key1"); value != nil {
+		t.Fatalf("Expected key1 to be deleted from coll1, got %s", value)
+	}
+	if value, _ = stub.GetPrivateData("coll3", "key1"); value != nil {
+		t.Fatalf("Expected nil for an unknown collection, got %s", value)
+	}
+}
+
+func TestMockStubStateValidationParameter(t *testing.T) {
+	stub := NewMockStub("StateValidationParameterTest", nil)
+	if err := stub.SetStateValidationParameter("key1", []byte("policy1")); err == nil {
+		t.Fatal("SetStateValidationParameter should fail outside of a transaction")
+	}
+
+	stub.MockTransactionStart("init")
+	stub.PutState("key1", []byte("value1"))
+	stub.SetStateValidationParameter("key1", []byte("policy1"))
+	stub.MockTransactionEnd("init")
+
+	ep, err := stub.GetStateValidationParameter("key1")
+	if err != nil || string(ep) != "policy1" {
+		t.Fatalf("Expected policy1, got %s (err %v)", ep, err)
+	}
+
+	// deleting the key deletes its endorsement policy as well
+	stub.MockTransactionStart("del")
+	stub.DelState("key1")
+	stub.MockTransactionEnd("del")
+	if ep, _ = stub.GetStateValidationParameter("key1"); ep != nil {
+		t.Fatalf("Expected no endorsement policy for key1, got %s", ep)
+	}
+}
+
+func TestMockStubGetStateByRangeWithPagination(t *testing.T) {
+	stub := NewMockStub("paginationTest", nil)
+	stub.MockTransactionStart("init")
+	for _, k := range []string{"1", "0", "5", "3", "4", "6", "2"} {
+		stub.PutState(k, []byte(k))
+	}
+	stub.MockTransactionEnd("init")
+
+	var pages [][]string
+	bookmark := ""
+	for {
+		itr, metadata, err := stub.GetStateByRangeWithPagination("1", "6", 2, bookmark)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		var keys []string
+		for itr.HasNext() {
+			kv, _ := itr.Next()
+			keys = append(keys, kv.Key)
+		}
+		itr.Close()
+		if int32(len(keys)) != metadata.FetchedRecordsCount {
+			t.Fatalf("Expected %d fetched records, got %d", len(keys), metadata.FetchedRecordsCount)
+		}
+		pages = append(pages, keys)
+		if bookmark = metadata.Bookmark; bookmark == "" {
+			break
+		}
+	}
+
+	expected := [][]string{{"1", "2"}, {"3", "4"}, {"5"}}
+	if !reflect.DeepEqual(expected, pages) {
+		t.Fatalf("Expected pages %v, got %v", expected, pages)
+	}
+
+	if _, _, err := stub.GetStateByRangeWithPagination("1", "6", 0, ""); err == nil {
+		t.Fatal("Expected an error for a non-positive page size")
+	}
+}
+
+func TestMockStubGetHistoryForKey(t *testing.T) {
+	stub := NewMockStub("historyTest", nil)
+	stub.MockTransactionStart("tx1")
+	stub.PutState("asset1", []byte("v1"))
+	stub.PutState("asset2", []byte("v1"))
+	stub.MockTransactionEnd("tx1")
+
+	// only the last modification of a key by a transaction is kept
+	stub.MockTransactionStart("tx2")
+	stub.PutState("asset1", []byte("v2"))
+	stub.PutState("asset1", []byte("v3"))
+	// the modifications of the ongoing transaction are not part of the history
+	itr, err := stub.GetHistoryForKey("asset1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if itr.HasNext() {
+		km, _ := itr.Next()
+		if km.TxId != "tx1" || itr.HasNext() {
+			t.Fatalf("Expected only the modification of tx1, got %v", km)
+		}
+	}
+	stub.MockTransactionEnd("tx2")
+
+	stub.MockTransactionStart("tx3")
+	stub.DelState("asset1")
+	stub.MockTransactionEnd("tx3")
+
+	itr, err = stub.GetHistoryForKey("asset1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer itr.Close()
+	type modification struct {
+		txID     string
+		value    string
+		isDelete bool
+	}
+	var history []modification
+	for itr.HasNext() {
+		km, err := itr.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if km.Timestamp == nil {
+			t.Fatalf("Expected the timestamp of transaction %s", km.TxId)
+		}
+		history = append(history, modification{km.TxId, string(km.Value), km.IsDelete})
+	}
+	expected := []modification{{"tx1", "v1", false}, {"tx2", "v3", false}, {"tx3", "", true}}
+	if !reflect.DeepEqual(expected, history) {
+		t.Fatalf("Expected history %v, got %v", expected, history)
+	}
+
+	itr, _ = stub.GetHistoryForKey("unknown")
+	if itr.HasNext() {
+		t.Fatal("Expected no history for an unknown key")
+	}
+}
+
+func putMarbles(stub *MockStub) {
+	stub.MockTransactionStart("init")
+	stub.PutState("marble1", []byte(`{"docType":"marble","name":"marble1","color":"blue","size":35,"owner":{"name":"tom","age":30},"tags":["shiny","round"]}`))
+	stub.PutState("marble2", []byte(`{"docType":"marble","name":"marble2","color":"red","size":50,"owner":{"name":"jerry","age":25},"tags":["round"]}`))
+	stub.PutState("marble3", []byte(`{"docType":"marble","name":"marble3","color":"blue","size":70,"owner":{"name":"tom","age":30}}`))
+	stub.PutState("marble4", []byte(`{"docType":"marble","name":"marble4","color":"green","size":10,"owner":{"name":"jerry","age":25},"tags":["shiny"]}`))
+	stub.PutState("notjson", []byte("blue"))
+	stub.MockTransactionEnd("init")
+}
+
+func getQueryKeys(t *testing.T, itr StateQueryIteratorInterface) []string {
+	defer itr.Close()
+	var keys []string
+	for itr.HasNext() {
+		kv, err := itr.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		keys = append(keys, kv.Key)
+	}
+	return keys
+}
+
+func TestMockStubGetQueryResult(t *testing.T) {
+	stub := NewMockStub("richQueryTest", nil)
+	putMarbles(stub)
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{`{"selector":{"color":"blue"}}`, []string{"marble1", "marble3"}},
+		{`{"selector":{"owner.name":"jerry"}}`, []string{"marble2", "marble4"}},
+		{`{"selector":{"owner":{"name":"tom"}}}`, []string{"marble1", "marble3"}},
+		{`{"selector":{"size":{"$gt":30,"$lte":50}}}`, []string{"marble1", "marble2"}},
+		{`{"selector":{"color":{"$ne":"blue"}}}`, []string{"marble2", "marble4"}},
+		{`{"selector":{"color":{"$in":["red","green"]}}}`, []string{"marble2", "marble4"}},
+		{`{"selector":{"color":{"$nin":["red","green"]}}}`, []string{"marble1", "marble3"}},
+		{`{"selector":{"tags":{"$exists":false}}}`, []string{"marble3"}},
+		{`{"selector":{"tags":{"$elemMatch":{"$eq":"shiny"}}}}`, []string{"marble1", "marble4"}},
+		{`{"selector":{"tags":{"$all":["shiny","round"]}}}`, []string{"marble1"}},
+		{`{"selector":{"tags":{"$size":1}}}`, []string{"marble2", "marble4"}},
+		{`{"selector":{"name":{"$regex":"^marble[34]$"}}}`, []string{"marble3", "marble4"}},
+		{`{"selector":{"size":{"$type":"number","$mod":[20,10]}}}`, []string{"marble2", "marble3", "marble4"}},
+		{`{"selector":{"$or":[{"color":"red"},{"size":{"$gte":70}}]}}`, []string{"marble2", "marble3"}},
+		{`{"selector":{"$and":[{"color":"blue"},{"$not":{"size":70}}]}}`, []string{"marble1"}},
+		{`{"selector":{"$nor":[{"color":"blue"},{"color":"red"}]}}`, []string{"marble4"}},
+		{`{"selector":{"docType":"marble"},"sort":[{"size":"desc"}]}`, []string{"marble3", "marble2", "marble1", "marble4"}},
+		{`{"selector":{"docType":"marble"},"sort":["owner.age",{"color":"asc"}]}`, []string{"marble4", "marble2", "marble1", "marble3"}},
+		{`{"selector":{"docType":"marble"},"sort":["size"],"skip":1,"limit":2}`, []string{"marble1", "marble2"}},
+		{`{"selector":{"docType":"cat"}}`, nil},
+	}
+	for _, test := range tests {
+		itr, err := stub.GetQueryResult(test.query)
+		if err != nil {
+			t.Fatalf("Unexpected error for query %s: %s", test.query, err)
+		}
+		if keys := getQueryKeys(t, itr); !reflect.DeepEqual(test.expected, keys) {
+			t.Fatalf("Expected %v for query %s, got %v", test.expected, test.query, keys)
+		}
+	}
+
+	// the fields of the query restrict the fields of the returned values
+	itr, err := stub.GetQueryResult(`{"selector":{"name":"marble2"},"fields":["name","owner.name"]}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	kv, err := itr.Next()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if string(kv.Value) != `{"name":"marble2","owner":{"name":"jerry"}}` {
+		t.Fatalf("Unexpected projected value %s", kv.Value)
+	}
+
+	for _, query := range []string{
+		`not a query`,
+		`{"fields":["name"]}`,
+		`{"selector":{"size":{"$near":1}}}`,
+		`{"selector":{"$or":{"color":"red"}}}`,
+		`{"selector":{"name":"marble1"},"sort":[{"size":"up"}]}`,
+		`{"selector":{"docType":"marble"},"skip":-1}`,
+		`{"selector":{"docType":"marble"},"limit":-1}`,
+	} {
+		if _, err := stub.GetQueryResult(query); err == nil {
+			t.Fatalf("Expected an error for query %s", query)
+		}
+	}
+}
+
+func TestMockStubGetQueryResultWithPagination(t *testing.T) {
+	stub := NewMockStub("richQueryPaginationTest", nil)
+	putMarbles(stub)
+
+	query := `{"selector":{"docType":"marble"},"sort":[{"size":"desc"}]}`
+	var pages [][]string
+	bookmark := ""
+	for {
+		itr, metadata, err := stub.GetQueryResultWithPagination(query, 3, bookmark)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		keys := getQueryKeys(t, itr)
+		if int32(len(keys)) != metadata.FetchedRecordsCount {
+			t.Fatalf("Expected %d fetched records, got %d", len(keys), metadata.FetchedRecordsCount)
+		}
+		pages = append(pages, keys)
+		if bookmark = metadata.Bookmark; bookmark == "" {
+			break
+		}
+	}
+	expected := [][]string{{"marble3", "marble2", "marble1"}, {"marble4"}}
+	if !reflect.DeepEqual(expected, pages) {
+		t.Fatalf("Expected pages %v, got %v", expected, pages)
+	}
+
+	if _, _, err := stub.GetQueryResultWithPagination(query, 0, ""); err == nil {
+		t.Fatal("Expected an error for a non-positive page size")
+	}
+}
+
+type mockStubContextCC struct {
+}
+
+func (cc *mockStubContextCC) Init(stub ChaincodeStubInterface) pb.Response {
+	return Success(nil)
+}
+
+func (cc *mockStubContextCC) Invoke(stub ChaincodeStubInterface) pb.Response {
+	creator, _ := stub.GetCreator()
+	transient, _ := stub.GetTransient()
+	if err := stub.SetEvent("ignored", nil); err != nil {
+		return Error(err.Error())
+	}
+	if err := stub.SetEvent("transfer", transient["secret"]); err != nil {
+		return Error(err.Error())
+	}
+	return Success(creator)
+}
+
+func TestMockStubCreatorTransientAndEvents(t *testing.T) {
+	stub := NewMockStub("contextTest", &mockStubContextCC{})
+	stub.Creator = []byte("creator")
+	stub.TransientMap = map[string][]byte{"secret": []byte("s3cr3t")}
+
+	res := stub.MockInvoke("tx1", [][]byte{[]byte("transfer")})
+	if res.Status != OK || string(res.Payload) != "creator" {
+		t.Fatalf("Expected the creator as payload, got %v", res)
+	}
+	stub.MockInvoke("tx2", [][]byte{[]byte("transfer")})
+
+	// a transaction has a single event, the last one set
+	expected := []*pb.ChaincodeEvent{
+		{ChaincodeId: "contextTest", TxId: "tx1", EventName: "transfer", Payload: []byte("s3cr3t")},
+		{ChaincodeId: "contextTest", TxId: "tx2", EventName: "transfer", Payload: []byte("s3cr3t")},
+	}
+	if !reflect.DeepEqual(expected, stub.ChaincodeEvents) {
+		t.Fatalf("Expected events %v, got %v", expected, stub.ChaincodeEvents)
+	}
+
+	if err := stub.SetEvent("outsideTx", nil); err == nil {
+		t.Fatal("Expected an error when setting an event outside of a transaction")
+	}
+	stub.MockTransactionStart("tx3")
+	if err := stub.SetEvent("", nil); err == nil {
+		t.Fatal("Expected an error for an event without name")
+	}
+	stub.MockTransactionEnd("tx3")
+	if len(stub.ChaincodeEvents) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(stub.ChaincodeEvents))
+	}
+}
+
+func TestMockStubInvokeChaincode(t *testing.T) {
+	stub := NewMockStub("caller", &shimTestCC{})
+	stub.ChannelID = "mychan"
+
+	sameChannelCC := NewMockStub("samechannelcc", &shimTestCC{})
+	sameChannelCC.MockInit("init", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")})
+	stub.MockPeerChaincode("samechannelcc", sameChannelCC)
+
+	otherChannelCC := NewMockStub("otherchannelcc", &shimTestCC{})
+	otherChannelCC.MockInit("init", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")})
+	stub.MockPeerChaincode("otherchannelcc/otherchan", otherChannelCC)
+
+	args := [][]byte{[]byte("invoke"), []byte("a"), []byte("b"), []byte("10")}
+	stub.MockTransactionStart("tx1")
+	defer stub.MockTransactionEnd("tx1")
+
+	// the chaincodes of the same channel may be registered with their name only
+	for _, channel := range []string{"", "mychan"} {
+		if res := stub.InvokeChaincode("samechannelcc", args, channel); res.Status != OK {
+			t.Fatalf("Unexpected response %v", res)
+		}
+	}
+	if a := string(sameChannelCC.State["a"]); a != "80" {
+		t.Fatalf("Expected the writes of the chaincode of the same channel to be kept, got a=%s", a)
+	}
+
+	// the writes of a chaincode of another channel are discarded
+	if res := stub.InvokeChaincode("otherchannelcc", args, "otherchan"); res.Status != OK {
+		t.Fatalf("Unexpected response %v", res)
+	}
+	if a := string(otherChannelCC.State["a"]); a != "100" {
+		t.Fatalf("Expected the writes of the chaincode of another channel to be discarded, got a=%s", a)
+	}
+	if len(otherChannelCC.History["a"]) != 1 {
+		t.Fatalf("Expected only the history of the init transaction, got %v", otherChannelCC.History["a"])
+	}
+	res := stub.InvokeChaincode("otherchannelcc", [][]byte{[]byte("query"), []byte("a")}, "otherchan")
+	if res.Status != OK || string(res.Payload) != "100" {
+		t.Fatalf("Unexpected response %v", res)
+	}
+
+	for _, channel := range []string{"", "otherchan", "thirdchan"} {
+		if res := stub.InvokeChaincode("unknowncc", args, channel); res.Status != ERROR {
+			t.Fatalf("Expected an error response for an unregistered chaincode, got %v", res)
+		}
+	}
+	if res := stub.InvokeChaincode("samechannelcc", args, "otherchan"); res.Status != ERROR {
+		t.Fatalf("Expected an error response for a chaincode not registered on the channel, got %v", res)
+	}
+}
+
 func TestGetTxTimestamp(t *testing.T) {
 	stub := NewMockStub("GetTxTimestamp", nil)
 	stub.MockTransactionStart("init")
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"net"
 
+	"github.com/hyperledger/fabric/common/crl"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/credentials"
 )
@@ -30,18 +31,27 @@ var (
 // NewServerTransportCredentials returns a new initialized
 // grpc/credentials.TransportCredentials
 func NewServerTransportCredentials(serverConfig *tls.Config) credentials.TransportCredentials {
+	return newServerTransportCredentials(serverConfig, nil)
+}
+
+// newServerTransportCredentials returns a new initialized
+// grpc/credentials.TransportCredentials which rejects the clients whose
+// verified certificate chains are revoked by the CRLs, if any
+func newServerTransportCredentials(serverConfig *tls.Config, crls *crl.Store) credentials.TransportCredentials {
 	// NOTE: unlike the default grpc/credentials implementation, we do not
 	// clone the tls.Config which allows us to update it dynamically
 	serverConfig.NextProtos = alpnProtoStr
 	// override TLS version and ensure it is 1.2
 	serverConfig.MinVersion = tls.VersionTLS12
 	serverConfig.MaxVersion = tls.VersionTLS12
-	return &serverCreds{serverConfig}
+	return &serverCreds{serverConfig, crls}
 }
 
 // serverCreds is an implementation of grpc/credentials.TransportCredentials.
 type serverCreds struct {
 	serverConfig *tls.Config
+	// CRLs against which the verified client certificates are checked
+	crls *crl.Store
 }
 
 // ClientHandShake is not implemented for `serverCreds`.
@@ -56,7 +66,20 @@ func (sc *serverCreds) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.
 	if err := conn.Handshake(); err != nil {
 		return nil, nil, err
 	}
-	return conn, credentials.TLSInfo{conn.ConnectionState()}, nil
+	state := conn.ConnectionState()
+	// the revocation is checked once the handshake verified the client
+	// certificate chains, as tls.Config.VerifyPeerCertificate needs Go 1.8
+	if sc.crls != nil {
+		var rawCerts [][]byte
+		for _, cert := range state.PeerCertificates {
+			rawCerts = append(rawCerts, cert.Raw)
+		}
+		if err := sc.crls.CheckVerifiedChains(rawCerts, state.VerifiedChains); err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+	}
+	return conn, credentials.TLSInfo{state}, nil
 }
 
 // Info provides the ProtocolInfo of this TransportCredentials.
@@ -69,7 +92,7 @@ func (sc *serverCreds) Info() credentials.ProtocolInfo {
 
 // Clone makes a copy of this TransportCredentials.
 func (sc *serverCreds) Clone() credentials.TransportCredentials {
-	creds := NewServerTransportCredentials(sc.serverConfig)
+	creds := newServerTransportCredentials(sc.serverConfig, sc.crls)
 	return creds
 }
 
@@ -15,6 +15,7 @@ import (
 	"net"
 	"sync"
 
+	"github.com/hyperledger/fabric/common/crl"
 	"google.golang.org/grpc"
 )
 
@@ -35,6 +36,9 @@ type SecureServerConfig struct {
 	UseTLS bool
 	//Whether or not TLS client must present certificates for authentication
 	RequireClientCert bool
+	//CRLs against which the verified client certificates are checked for
+	//revocation when TLS client authentication is required
+	CRLs *crl.Store
 }
 
 //GRPCServer defines an interface representing a GRPC-based server
@@ -141,6 +145,7 @@ func NewGRPCServerFromListener(listener net.Listener, secureConfig SecureServerC
 				SessionTicketsDisabled: true,
 			}
 			grpcServer.tlsConfig.ClientAuth = tls.RequestClientCert
+			var crls *crl.Store
 			//checkif client authentication is required
 			if secureConfig.RequireClientCert {
 				//require TLS client auth
@@ -156,10 +161,12 @@ func NewGRPCServerFromListener(listener net.Listener, secureConfig SecureServerC
 						}
 					}
 				}
+				//reject the revoked client certificates
+				crls = secureConfig.CRLs
 			}
 
 			// create credentials and add to server options
-			creds := NewServerTransportCredentials(grpcServer.tlsConfig)
+			creds := newServerTransportCredentials(grpcServer.tlsConfig, crls)
 			serverOpts = append(serverOpts, grpc.Creds(creds))
 		} else {
 			return nil, errors.New("secureConfig must contain both ServerKey and " +
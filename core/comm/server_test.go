@@ -7,8 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package comm_test
 
 import (
+	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
@@ -27,6 +30,7 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/transport"
 
+	"github.com/hyperledger/fabric/common/crl"
 	"github.com/hyperledger/fabric/core/comm"
 	testpb "github.com/hyperledger/fabric/core/comm/testdata/grpc"
 )
@@ -991,6 +995,51 @@ func TestMutualAuth(t *testing.T) {
 
 }
 
+func TestMutualAuthWithRevokedClient(t *testing.T) {
+
+	t.Parallel()
+	//revoke the first client certificate of the org with a CRL of its CA
+	caKeyPEM, err := ioutil.ReadFile(fmt.Sprintf(orgCAKey, 1))
+	if err != nil {
+		t.Fatalf("Failed to load the CA key: %s", err)
+	}
+	block, _ := pem.Decode(caKeyPEM)
+	caKey, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse the CA key: %s", err)
+	}
+	block, _ = pem.Decode(testOrgs[0].rootCA)
+	caCert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse the CA certificate: %s", err)
+	}
+	revokedCert, err := x509.ParseCertificate(testOrgs[0].clientCerts[0].Certificate[0])
+	if err != nil {
+		t.Fatalf("Failed to parse the client certificate: %s", err)
+	}
+	crlBytes, err := caCert.CreateCRL(rand.Reader, caKey, []pkix.RevokedCertificate{
+		{SerialNumber: revokedCert.SerialNumber, RevocationTime: time.Now()},
+	}, time.Now(), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to create the CRL: %s", err)
+	}
+	crls := crl.NewStore()
+	err = crls.Update([][]byte{crlBytes})
+	if err != nil {
+		t.Fatalf("Failed to load the CRL: %s", err)
+	}
+
+	servers := testOrgs[0].testServers(9110, [][]byte{})
+	for i := range servers {
+		servers[i].config.CRLs = crls
+	}
+	clients := testOrgs[0].trustedClients([][]byte{})
+	testErr := runMutualAuth(t, servers, clients[1:], clients[:1])
+	if testErr != nil {
+		t.Fatalf("TestMutualAuthWithRevokedClient failed with error: %s", testErr.Error())
+	}
+}
+
 func TestAppendRemoveWithInvalidBytes(t *testing.T) {
 
 	// TODO: revisit when msp serialization without PEM type is resolved
@@ -16,7 +16,10 @@ limitations under the License.
 
 package committer
 
-import "github.com/hyperledger/fabric/protos/common"
+import (
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+)
 
 // Committer is the interface supported by committers
 // The only committer is noopssinglechain committer.
@@ -30,6 +33,10 @@ type Committer interface {
 	// Commit block to the ledger
 	Commit(block *common.Block) error
 
+	// CommitWithPvtData commits block along with the private data of its
+	// transactions to the ledger
+	CommitWithPvtData(blockAndPvtData *ledger.BlockAndPvtData) error
+
 	// Get recent block sequence number
 	LedgerHeight() (uint64, error)
 
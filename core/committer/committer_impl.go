@@ -18,6 +18,7 @@ package committer
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
@@ -68,12 +69,22 @@ func NewLedgerCommitterReactive(ledger ledger.PeerLedger, validator txvalidator.
 // Commit commits block to into the ledger
 // Note, it is important that this always be called serially
 func (lc *LedgerCommitter) Commit(block *common.Block) error {
+	return lc.CommitWithPvtData(&ledger.BlockAndPvtData{Block: block})
+}
+
+// CommitWithPvtData commits block along with the private data of its
+// transactions into the ledger
+// Note, it is important that this always be called serially
+func (lc *LedgerCommitter) CommitWithPvtData(blockAndPvtData *ledger.BlockAndPvtData) error {
+	block := blockAndPvtData.Block
 
 	// Validate and mark invalid transactions
 	logger.Debug("Validating block")
+	startTime := time.Now()
 	if err := lc.validator.Validate(block); err != nil {
 		return err
 	}
+	observeDuration(blockValidationDuration, block, startTime)
 
 	// Updating CSCC with new configuration block
 	if utils.IsConfigBlock(block) {
@@ -83,9 +94,11 @@ func (lc *LedgerCommitter) Commit(block *common.Block) error {
 		}
 	}
 
-	if err := lc.ledger.Commit(block); err != nil {
+	startTime = time.Now()
+	if err := lc.ledger.CommitWithPvtData(blockAndPvtData); err != nil {
 		return err
 	}
+	observeDuration(blockCommitDuration, block, startTime)
 
 	// send block event *after* the block has been committed
 	if err := producer.SendProducerBlockEvent(block); err != nil {
@@ -0,0 +1,39 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package committer
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+var (
+	blockValidationDuration = metrics.NewHistogram(metrics.HistogramOpts{
+		Namespace:  "committer",
+		Name:       "block_validation_duration",
+		Help:       "The time to validate a block, in seconds.",
+		LabelNames: []string{"channel"},
+	})
+	blockCommitDuration = metrics.NewHistogram(metrics.HistogramOpts{
+		Namespace:  "committer",
+		Name:       "block_commit_duration",
+		Help:       "The time to commit a validated block and its private data to the ledger, in seconds.",
+		LabelNames: []string{"channel"},
+	})
+)
+
+// observeDuration records the time elapsed since startTime in the histogram of the channel of the block
+func observeDuration(histogram *metrics.Histogram, block *common.Block, startTime time.Time) {
+	channel, err := utils.GetChainIDFromBlock(block)
+	if err != nil {
+		logger.Debugf("Cannot retrieve the channel of the block: %s", err)
+	}
+	histogram.With(channel).Observe(time.Since(startTime).Seconds())
+}
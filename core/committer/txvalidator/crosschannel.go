@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/common/crosschannel"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+)
+
+// validateCrossChannelPart checks that the record of a cross-channel
+// transaction written by a transaction of an application chaincode is the
+// one of the part of the transaction itself on this channel
+func validateCrossChannelPart(chdr *common.ChannelHeader, txRWSet *rwsetutil.TxRwSet) error {
+	key, record, err := crosschannel.GetRecord(txRWSet)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return nil
+	}
+	if key != chdr.TxId {
+		return fmt.Errorf("the record of transaction %s is written by transaction %s", key, chdr.TxId)
+	}
+	if record.Status != peer.CrossChannelRecord_PREPARED || len(record.Pending) != 0 {
+		return fmt.Errorf("the part of transaction %s must be recorded as PREPARED", key)
+	}
+
+	onChannel := false
+	seen := make(map[string]bool)
+	for _, chainID := range record.Channels {
+		if seen[chainID] {
+			return fmt.Errorf("channel %s is recorded twice", chainID)
+		}
+		seen[chainID] = true
+		onChannel = onChannel || chainID == chdr.ChannelId
+	}
+	if len(record.Channels) < 2 || !onChannel {
+		return fmt.Errorf("transaction %s must be recorded on channel %s and at least another one", key, chdr.ChannelId)
+	}
+
+	// the private data of a part could not be held until the transaction is decided
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if len(nsRWSet.CollHashedRwSets) > 0 {
+			return fmt.Errorf("the part of transaction %s writes private data", key)
+		}
+	}
+	return nil
+}
+
+// validateCrossChannelDecision validates a transaction of XSCC, which decides
+// a cross-channel transaction. It must only record the transaction as
+// COMMITTED or ABORTED, and is endorsed as required by every chaincode the
+// part of the transaction on the channel writes to, or else by any member
+func (v *vsccValidatorImpl) validateCrossChannelDecision(envBytes []byte, chdr *common.ChannelHeader, txRWSet *rwsetutil.TxRwSet) (error, peer.TxValidationCode) {
+	key, record, err := crosschannel.GetRecord(txRWSet)
+	if err != nil {
+		return err, peer.TxValidationCode_ILLEGAL_WRITESET
+	}
+	if record == nil || record.Status == peer.CrossChannelRecord_PREPARED {
+		return fmt.Errorf("XSCC must record a cross-channel transaction as COMMITTED or ABORTED"),
+			peer.TxValidationCode_ILLEGAL_WRITESET
+	}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != crosschannel.Namespace && (len(nsRWSet.KvRwSet.Writes) > 0 ||
+			len(nsRWSet.KvRwSet.MetadataWrites) > 0 || len(nsRWSet.CollHashedRwSets) > 0) {
+			return fmt.Errorf("XSCC attempted to write to namespace %s", nsRWSet.NameSpace),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+	}
+
+	namespaces, err := v.getCrossChannelNamespaces(key)
+	if err != nil {
+		return err, peer.TxValidationCode_INVALID_OTHER_REASON
+	}
+	if len(namespaces) == 0 {
+		namespaces = []string{crosschannel.Namespace}
+	}
+
+	for _, ns := range namespaces {
+		_, vscc, policy, err := v.GetInfoForValidate(chdr.TxId, chdr.ChannelId, ns)
+		if err != nil {
+			logger.Errorf("GetInfoForValidate for txId = %s returned error %s", chdr.TxId, err)
+			return err, peer.TxValidationCode_INVALID_OTHER_REASON
+		}
+		if err = v.VSCCValidateTxForCC(envBytes, chdr.TxId, chdr.ChannelId, ns, vscc.ChaincodeName, vscc.ChaincodeVersion, policy, true); err != nil {
+			switch err.(type) {
+			case *VSCCEndorsementPolicyError:
+				return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+			default:
+				return err, peer.TxValidationCode_INVALID_OTHER_REASON
+			}
+		}
+	}
+	return nil, peer.TxValidationCode_VALID
+}
+
+// getCrossChannelNamespaces returns the namespaces the part of a cross-channel
+// transaction held by the ledger writes to, if any
+func (v *vsccValidatorImpl) getCrossChannelNamespaces(txID string) ([]string, error) {
+	l := v.support.Ledger()
+	if l == nil {
+		return nil, fmt.Errorf("nil ledger instance")
+	}
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return nil, fmt.Errorf("Could not retrieve QueryExecutor, error %s", err)
+	}
+	defer qe.Done()
+
+	recordBytes, err := qe.GetState(crosschannel.Namespace, txID)
+	if err != nil {
+		return nil, fmt.Errorf("Could not retrieve the record of transaction %s, error %s", txID, err)
+	}
+	record, err := crosschannel.UnmarshalRecord(recordBytes)
+	if err != nil || record == nil || len(record.Pending) == 0 {
+		return nil, err
+	}
+	pending := &rwsetutil.TxRwSet{}
+	if err = pending.FromProtoBytes(record.Pending); err != nil {
+		return nil, err
+	}
+	namespaces := []string{}
+	for _, nsRWSet := range pending.NsRwSets {
+		if len(nsRWSet.KvRwSet.Writes) > 0 || len(nsRWSet.KvRwSet.MetadataWrites) > 0 {
+			namespaces = append(namespaces, nsRWSet.NameSpace)
+		}
+	}
+	return namespaces, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// PluginMapper maps the VSCC names of the chaincodes to validation plugins
+type PluginMapper interface {
+	// PluginFactoryByName returns the factory of the plugin with the given
+	// name, or nil if the name does not map to a plugin
+	PluginFactoryByName(name string) validation.PluginFactory
+}
+
+// pluginValidator validates transactions in-process with the validation
+// plugins of a chain. The plugin instances are created and bound to the
+// chain the first time they are used
+type pluginValidator struct {
+	sync.Mutex
+	pluginMapper PluginMapper
+	support      Support
+	plugins      map[string]validation.Plugin
+}
+
+func newPluginValidator(pluginMapper PluginMapper, support Support) *pluginValidator {
+	return &pluginValidator{
+		pluginMapper: pluginMapper,
+		support:      support,
+		plugins:      make(map[string]validation.Plugin),
+	}
+}
+
+// hasPlugin returns whether the VSCC name maps to a validation plugin
+func (pv *pluginValidator) hasPlugin(vsccName string) bool {
+	return pv.pluginMapper != nil && pv.pluginMapper.PluginFactoryByName(vsccName) != nil
+}
+
+// validateTx validates the action of the transaction on the namespace with
+// the plugin of the VSCC name, against the endorsement policy unless the
+// key-level endorsement policies replace it
+func (pv *pluginValidator) validateTx(vsccName string, envBytes []byte, namespace string, policy []byte, ccPolicyRequired bool) error {
+	plugin, err := pv.getOrCreatePlugin(vsccName)
+	if err != nil {
+		msg := fmt.Sprintf("Failed initializing validation plugin %s, error %s", vsccName, err)
+		logger.Errorf(msg)
+		return &VSCCExecutionFailureError{msg}
+	}
+
+	contextData := []validation.ContextDatum{serializedPolicy(policy)}
+	if !ccPolicyRequired {
+		contextData = append(contextData, validation.KeyLevelEndorsement{})
+	}
+	err = plugin.Validate(envBytes, namespace, contextData...)
+	if err == nil {
+		return nil
+	}
+	if _, isExecutionFailure := err.(*validation.ExecutionFailureError); isExecutionFailure {
+		msg := fmt.Sprintf("Validation plugin %s failed, error %s", vsccName, err)
+		logger.Errorf(msg)
+		return &VSCCExecutionFailureError{msg}
+	}
+	logger.Errorf("Validation plugin %s rejected the transaction, error %s", vsccName, err)
+	return &VSCCEndorsementPolicyError{err.Error()}
+}
+
+func (pv *pluginValidator) getOrCreatePlugin(vsccName string) (validation.Plugin, error) {
+	pv.Lock()
+	defer pv.Unlock()
+
+	if plugin, exists := pv.plugins[vsccName]; exists {
+		return plugin, nil
+	}
+
+	plugin := pv.pluginMapper.PluginFactoryByName(vsccName).New()
+	if err := plugin.Init(&policyEvaluator{support: pv.support}, &stateFetcher{support: pv.support}); err != nil {
+		return nil, err
+	}
+	pv.plugins[vsccName] = plugin
+	return plugin, nil
+}
+
+// serializedPolicy passes the endorsement policy of the chaincode to the plugins
+type serializedPolicy []byte
+
+func (sp serializedPolicy) Bytes() []byte {
+	return sp
+}
+
+// policyEvaluator evaluates policies against the MSPs of the chain
+type policyEvaluator struct {
+	support Support
+}
+
+func (pe *policyEvaluator) Evaluate(policyBytes []byte, signatureSet []*common.SignedData) error {
+	policy, _, err := cauthdsl.NewPolicyProvider(pe.support.MSPManager()).NewPolicy(policyBytes)
+	if err != nil {
+		return err
+	}
+	return policy.Evaluate(signatureSet)
+}
+
+// stateFetcher reads the committed state of the chain
+type stateFetcher struct {
+	support Support
+}
+
+func (sf *stateFetcher) FetchState() (validation.State, error) {
+	l := sf.support.Ledger()
+	if l == nil {
+		return nil, fmt.Errorf("nil ledger instance")
+	}
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return qe, nil
+}
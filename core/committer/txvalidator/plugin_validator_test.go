@@ -0,0 +1,196 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"errors"
+	"testing"
+
+	ctxt "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockValidationPlugin struct {
+	inits                int
+	namespaces           []string
+	policies             [][]byte
+	keyLevelEndorsements []bool
+	err                  error
+}
+
+func (p *mockValidationPlugin) Validate(txEnvelope []byte, namespace string, contextData ...validation.ContextDatum) error {
+	p.namespaces = append(p.namespaces, namespace)
+	keyLevelEndorsement := false
+	for _, datum := range contextData {
+		switch d := datum.(type) {
+		case validation.SerializedPolicy:
+			p.policies = append(p.policies, d.Bytes())
+		case validation.KeyLevelEndorsement:
+			keyLevelEndorsement = true
+		}
+	}
+	p.keyLevelEndorsements = append(p.keyLevelEndorsements, keyLevelEndorsement)
+	return p.err
+}
+
+func (p *mockValidationPlugin) Init(dependencies ...validation.Dependency) error {
+	p.inits++
+	for _, dep := range dependencies {
+		if sf, isStateFetcher := dep.(validation.StateFetcher); isStateFetcher {
+			state, err := sf.FetchState()
+			if err != nil {
+				return err
+			}
+			state.Done()
+		}
+	}
+	return nil
+}
+
+type mockValidationPluginFactory struct {
+	plugin *mockValidationPlugin
+}
+
+func (f *mockValidationPluginFactory) New() validation.Plugin {
+	return f.plugin
+}
+
+type mockPluginMapper map[string]validation.PluginFactory
+
+func (m mockPluginMapper) PluginFactoryByName(name string) validation.PluginFactory {
+	return m[name]
+}
+
+func setupLedgerAndPluginValidator(t *testing.T, plugin *mockValidationPlugin) (ledger.PeerLedger, Validator) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/validatortest")
+	ledgermgmt.InitializeTestEnv()
+	gb, err := ctxt.MakeGenesisBlock("TestLedger")
+	assert.NoError(t, err)
+	theLedger, err := ledgermgmt.CreateLedger(gb)
+	assert.NoError(t, err)
+	pm := mockPluginMapper{"myvscc": &mockValidationPluginFactory{plugin: plugin}}
+	return theLedger, NewTxValidator(&mockSupport{l: theLedger}, pm)
+}
+
+func TestPluginValidation(t *testing.T) {
+	plugin := &mockValidationPlugin{}
+	l, v := setupLedgerAndPluginValidator(t, plugin)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	ccID := "mycc"
+	policy := signedByAnyMember([]string{"DEFAULT"})
+	putCCInfoWithVSCCAndVer(l, ccID, "myvscc", ccVersion, policy, t)
+
+	// the VSCC is not invoked for the chaincodes validated by a plugin
+	c := executeChaincodeProvider.getCallback()
+	defer executeChaincodeProvider.setCallback(c)
+	executeChaincodeProvider.setCallback(func() (*peer.Response, *peer.ChaincodeEvent, error) {
+		return &peer.Response{Status: shim.ERROR}, nil, nil
+	})
+
+	newBlock := func() *common.Block {
+		tx := getEnv(ccID, createRWset(t, ccID), t)
+		return &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	}
+
+	b := newBlock()
+	assert.NoError(t, v.Validate(b))
+	assertValid(b, t)
+	assert.Equal(t, []string{ccID}, plugin.namespaces)
+	assert.Equal(t, [][]byte{policy}, plugin.policies)
+	assert.Equal(t, []bool{false}, plugin.keyLevelEndorsements)
+
+	// the transaction is rejected by the plugin
+	plugin.err = errors.New("asset invariant violated")
+	b = newBlock()
+	assert.NoError(t, v.Validate(b))
+	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+
+	// the plugin cannot validate the transaction
+	plugin.err = &validation.ExecutionFailureError{Reason: "state unavailable"}
+	err := v.Validate(newBlock())
+	assert.Error(t, err)
+	assert.IsType(t, &VSCCExecutionFailureError{}, err)
+
+	// the plugin is initialized once for the chain
+	assert.Equal(t, 1, plugin.inits)
+}
+
+func TestPluginValidationFallsBackToVSCC(t *testing.T) {
+	plugin := &mockValidationPlugin{}
+	l, v := setupLedgerAndPluginValidator(t, plugin)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	ccID := "mycc"
+	putCCInfo(l, ccID, signedByAnyMember([]string{"DEFAULT"}), t)
+
+	tx := getEnv(ccID, createRWset(t, ccID), t)
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+
+	c := executeChaincodeProvider.getCallback()
+	defer executeChaincodeProvider.setCallback(c)
+	executeChaincodeProvider.setCallback(func() (*peer.Response, *peer.ChaincodeEvent, error) {
+		return &peer.Response{Status: shim.ERROR}, nil, nil
+	})
+
+	assert.NoError(t, v.Validate(b))
+	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+	assert.Empty(t, plugin.namespaces)
+}
+
+func TestPluginValidationWithKeyLevelPolicies(t *testing.T) {
+	plugin := &mockValidationPlugin{}
+	l, v := setupLedgerAndPluginValidator(t, plugin)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	ccID := "mycc"
+	policy := signedByAnyMember([]string{"DEFAULT"})
+	putCCInfoWithVSCCAndVer(l, ccID, "myvscc", ccVersion, policy, t)
+	putKeyLevelPolicy(l, ccID, "key", policy, t)
+
+	// the key-level policy of every key written replaces the
+	// chaincode policy, which the plugin is told not to evaluate
+	tx := getEnv(ccID, createRWset(t, ccID), t)
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	assert.NoError(t, v.Validate(b))
+	assertValid(b, t)
+	assert.Equal(t, [][]byte{policy}, plugin.policies)
+	assert.Equal(t, []bool{true}, plugin.keyLevelEndorsements)
+
+	// the chaincode policy applies to a key without a key-level policy
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToWriteSet(ccID, "key", []byte("value"))
+	rwsetBuilder.AddToWriteSet(ccID, "otherkey", []byte("value"))
+	rws, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+	assert.NoError(t, err)
+	tx = getEnv(ccID, rws, t)
+	b = &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	assert.NoError(t, v.Validate(b))
+	assertValid(b, t)
+	assert.Equal(t, []bool{true, false}, plugin.keyLevelEndorsements)
+
+	// the key-level policy of every key written is satisfied, yet
+	// the plugin validating the chaincode rejects the transaction
+	plugin.err = errors.New("asset invariant violated")
+	tx = getEnv(ccID, createRWset(t, ccID), t)
+	b = &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	assert.NoError(t, v.Validate(b))
+	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+	assert.Equal(t, []string{ccID, ccID, ccID}, plugin.namespaces)
+}
@@ -18,20 +18,26 @@ package txvalidator
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	coreUtil "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/crosschannel"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/common/validation"
+	validationapi "github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/hyperledger/fabric/core/ledger"
 	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/msp"
 
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
@@ -56,6 +62,13 @@ type Support interface {
 	// GetMSPIDs returns the IDs for the application MSPs
 	// that have been defined in the channel
 	GetMSPIDs(cid string) []string
+
+	// Acquire blocks until a worker of the validator pool of the peer is
+	// available to validate a transaction
+	Acquire()
+
+	// Release returns the worker acquired by Acquire to the pool
+	Release()
 }
 
 //Validator interface which defines API to validate block transactions
@@ -75,9 +88,9 @@ type vsccValidator interface {
 // vsccValidator implementation which used to call
 // vscc chaincode and validate block transactions
 type vsccValidatorImpl struct {
-	support     Support
-	ccprovider  ccprovider.ChaincodeProvider
-	sccprovider sysccprovider.SystemChaincodeProvider
+	support         Support
+	sccprovider     sysccprovider.SystemChaincodeProvider
+	pluginValidator *pluginValidator
 }
 
 // implementation of Validator interface, keeps
@@ -129,14 +142,16 @@ func init() {
 	logger = flogging.MustGetLogger("txvalidator")
 }
 
-// NewTxValidator creates new transactions validator
-func NewTxValidator(support Support) Validator {
+// NewTxValidator creates new transactions validator. The chaincodes whose
+// VSCC name is mapped to a validation plugin by the PluginMapper, if not nil,
+// are validated by the plugin, and the other ones by invoking their VSCC
+func NewTxValidator(support Support, pluginMapper PluginMapper) Validator {
 	// Encapsulates interface implementation
 	return &txValidator{support,
 		&vsccValidatorImpl{
-			support:     support,
-			ccprovider:  ccprovider.GetChaincodeProvider(),
-			sccprovider: sysccprovider.GetSystemChaincodeProvider()}}
+			support:         support,
+			sccprovider:     sysccprovider.GetSystemChaincodeProvider(),
+			pluginValidator: newPluginValidator(pluginMapper, support)}}
 }
 
 func (v *txValidator) chainExists(chain string) bool {
@@ -144,6 +159,25 @@ func (v *txValidator) chainExists(chain string) bool {
 	return true
 }
 
+// blockValidationResult is the outcome of the validation of a transaction of
+// a block which does not depend on the other transactions of the block
+type blockValidationResult struct {
+	validationCode peer.TxValidationCode
+	err            error
+	txType         common.HeaderType
+	txID           string
+	channel        string
+	payload        *common.Payload
+	configEnvelope *common.ConfigEnvelope
+}
+
+// Validate validates the transactions of the block and records their
+// validation codes in its TRANSACTIONS_FILTER metadata. The checks of every
+// transaction which do not depend on the other transactions of the block, and
+// notably the endorsement policy checks, run concurrently on the validator
+// pool. The outcome of the checks which depend on the earlier transactions of
+// the block is then established in the order of the block, which makes the
+// result independent of the scheduling of the validation
 func (v *txValidator) Validate(block *common.Block) error {
 	logger.Debug("START Block Validation")
 	defer logger.Debug("END Block Validation")
@@ -153,114 +187,63 @@ func (v *txValidator) Validate(block *common.Block) error {
 	txsChaincodeNames := make(map[int]*sysccprovider.ChaincodeInstance)
 	// upgradedChaincodes records all the chaincodes that are upgrded in a block
 	txsUpgradedChaincodes := make(map[int]*sysccprovider.ChaincodeInstance)
-	for tIdx, d := range block.Data.Data {
-		if d != nil {
-			if env, err := utils.GetEnvelopeFromBlock(d); err != nil {
-				logger.Warningf("Error getting tx from block(%s)", err)
-				txsfltr.SetFlag(tIdx, peer.TxValidationCode_INVALID_OTHER_REASON)
-			} else if env != nil {
-				// validate the transaction: here we check that the transaction
-				// is properly formed, properly signed and that the security
-				// chain binding proposal to endorsements to tx holds. We do
-				// NOT check the validity of endorsements, though. That's a
-				// job for VSCC below
-				logger.Debug("Validating transaction peer.ValidateTransaction()")
-				var payload *common.Payload
-				var err error
-				var txResult peer.TxValidationCode
-
-				if payload, txResult = validation.ValidateTransaction(env); txResult != peer.TxValidationCode_VALID {
-					logger.Errorf("Invalid transaction with index %d", tIdx)
-					txsfltr.SetFlag(tIdx, txResult)
-					continue
-				}
-
-				chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
-				if err != nil {
-					logger.Warningf("Could not unmarshal channel header, err %s, skipping", err)
-					txsfltr.SetFlag(tIdx, peer.TxValidationCode_INVALID_OTHER_REASON)
-					continue
-				}
-
-				channel := chdr.ChannelId
-				logger.Debugf("Transaction is for chain %s", channel)
+	// updatedValidationParams records the keys whose validation parameter is updated by txs in a block
+	updatedValidationParams := make(map[string]map[string]struct{})
 
-				if !v.chainExists(channel) {
-					logger.Errorf("Dropping transaction for non-existent chain %s", channel)
-					txsfltr.SetFlag(tIdx, peer.TxValidationCode_TARGET_CHAIN_NOT_FOUND)
-					continue
-				}
+	results := make([]*blockValidationResult, len(block.Data.Data))
+	var wg sync.WaitGroup
+	for tIdx, d := range block.Data.Data {
+		v.support.Acquire()
+		wg.Add(1)
+		go func(tIdx int, d []byte) {
+			defer wg.Done()
+			defer v.support.Release()
+			results[tIdx] = v.validateTx(block, tIdx, d)
+		}(tIdx, d)
+	}
+	wg.Wait()
+
+	for tIdx, res := range results {
+		if res == nil {
+			continue
+		}
+		if res.err != nil {
+			return res.err
+		}
+		if res.validationCode != peer.TxValidationCode_VALID {
+			txsfltr.SetFlag(tIdx, res.validationCode)
+			continue
+		}
 
-				if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
-					// Check duplicate transactions
-					txID := chdr.TxId
-					if _, err := v.support.Ledger().GetTransactionByID(txID); err == nil {
-						logger.Error("Duplicate transaction found, ", txID, ", skipping")
-						txsfltr.SetFlag(tIdx, peer.TxValidationCode_DUPLICATE_TXID)
-						continue
-					}
-
-					// Validate tx with vscc and policy
-					logger.Debug("Validating transaction vscc tx validate")
-					err, cde := v.vscc.VSCCValidateTx(payload, d, env)
-					if err != nil {
-						txID := txID
-						logger.Errorf("VSCCValidateTx for transaction txId = %s returned error %s", txID, err)
-						switch err.(type) {
-						case *VSCCExecutionFailureError:
-							return err
-						case *VSCCInfoLookupFailureError:
-							return err
-						default:
-							txsfltr.SetFlag(tIdx, cde)
-							continue
-						}
-					}
-
-					invokeCC, upgradeCC, err := v.getTxCCInstance(payload)
-					if err != nil {
-						logger.Errorf("Get chaincode instance from transaction txId = %s returned error %s", txID, err)
-						txsfltr.SetFlag(tIdx, peer.TxValidationCode_INVALID_OTHER_REASON)
-						continue
-					}
-					txsChaincodeNames[tIdx] = invokeCC
-					if upgradeCC != nil {
-						logger.Infof("Find chaincode upgrade transaction for chaincode %s on chain %s with new version %s", upgradeCC.ChaincodeName, upgradeCC.ChainID, upgradeCC.ChaincodeVersion)
-						txsUpgradedChaincodes[tIdx] = upgradeCC
-					}
-				} else if common.HeaderType(chdr.Type) == common.HeaderType_CONFIG {
-					configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
-					if err != nil {
-						err := fmt.Errorf("Error unmarshaling config which passed initial validity checks: %s", err)
-						logger.Critical(err)
-						return err
-					}
-
-					if err := v.support.Apply(configEnvelope); err != nil {
-						err := fmt.Errorf("Error validating config which passed initial validity checks: %s", err)
-						logger.Critical(err)
-						return err
-					}
-					logger.Debugf("config transaction received for chain %s", channel)
-				} else {
-					logger.Warningf("Unknown transaction type [%s] in block number [%d] transaction index [%d]",
-						common.HeaderType(chdr.Type), block.Header.Number, tIdx)
-					txsfltr.SetFlag(tIdx, peer.TxValidationCode_UNKNOWN_TX_TYPE)
-					continue
-				}
+		if res.txType == common.HeaderType_ENDORSER_TRANSACTION {
+			if err := checkValidationParameterUpdates(block.Data.Data[tIdx], updatedValidationParams); err != nil {
+				logger.Errorf("Transaction txId = %s conflicts with an earlier transaction in the block: %s", res.txID, err)
+				txsfltr.SetFlag(tIdx, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+				continue
+			}
 
-				if _, err := proto.Marshal(env); err != nil {
-					logger.Warningf("Cannot marshal transaction due to %s", err)
-					txsfltr.SetFlag(tIdx, peer.TxValidationCode_MARSHAL_TX_ERROR)
-					continue
-				}
-				// Succeeded to pass down here, transaction is valid
-				txsfltr.SetFlag(tIdx, peer.TxValidationCode_VALID)
-			} else {
-				logger.Warning("Nil tx from block")
-				txsfltr.SetFlag(tIdx, peer.TxValidationCode_NIL_ENVELOPE)
+			invokeCC, upgradeCC, err := v.getTxCCInstance(res.payload)
+			if err != nil {
+				logger.Errorf("Get chaincode instance from transaction txId = %s returned error %s", res.txID, err)
+				txsfltr.SetFlag(tIdx, peer.TxValidationCode_INVALID_OTHER_REASON)
+				continue
+			}
+			txsChaincodeNames[tIdx] = invokeCC
+			if upgradeCC != nil {
+				logger.Infof("Find chaincode upgrade transaction for chaincode %s on chain %s with new version %s", upgradeCC.ChaincodeName, upgradeCC.ChainID, upgradeCC.ChaincodeVersion)
+				txsUpgradedChaincodes[tIdx] = upgradeCC
+			}
+		} else if res.txType == common.HeaderType_CONFIG {
+			if err := v.support.Apply(res.configEnvelope); err != nil {
+				err := fmt.Errorf("Error validating config which passed initial validity checks: %s", err)
+				logger.Critical(err)
+				return err
 			}
+			logger.Debugf("config transaction received for chain %s", res.channel)
 		}
+
+		// Succeeded to pass down here, transaction is valid
+		txsfltr.SetFlag(tIdx, peer.TxValidationCode_VALID)
 	}
 
 	txsfltr = v.invalidTXsForUpgradeCC(txsChaincodeNames, txsUpgradedChaincodes, txsfltr)
@@ -273,6 +256,104 @@ func (v *txValidator) Validate(block *common.Block) error {
 	return nil
 }
 
+// validateTx performs the checks of the transaction at index tIdx of the block
+// which do not depend on the other transactions of the block. It returns nil
+// for a nil transaction, which is left valid
+func (v *txValidator) validateTx(block *common.Block, tIdx int, d []byte) *blockValidationResult {
+	if d == nil {
+		return nil
+	}
+
+	env, err := utils.GetEnvelopeFromBlock(d)
+	if err != nil {
+		logger.Warningf("Error getting tx from block(%s)", err)
+		return &blockValidationResult{validationCode: peer.TxValidationCode_INVALID_OTHER_REASON}
+	}
+	if env == nil {
+		logger.Warning("Nil tx from block")
+		return &blockValidationResult{validationCode: peer.TxValidationCode_NIL_ENVELOPE}
+	}
+
+	// validate the transaction: here we check that the transaction
+	// is properly formed, properly signed and that the security
+	// chain binding proposal to endorsements to tx holds. We do
+	// NOT check the validity of endorsements, though. That's a
+	// job for VSCC below
+	logger.Debug("Validating transaction peer.ValidateTransaction()")
+	payload, txResult := validation.ValidateTransaction(env)
+	if txResult != peer.TxValidationCode_VALID {
+		logger.Errorf("Invalid transaction with index %d", tIdx)
+		return &blockValidationResult{validationCode: txResult}
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		logger.Warningf("Could not unmarshal channel header, err %s, skipping", err)
+		return &blockValidationResult{validationCode: peer.TxValidationCode_INVALID_OTHER_REASON}
+	}
+
+	channel := chdr.ChannelId
+	logger.Debugf("Transaction is for chain %s", channel)
+
+	if !v.chainExists(channel) {
+		logger.Errorf("Dropping transaction for non-existent chain %s", channel)
+		return &blockValidationResult{validationCode: peer.TxValidationCode_TARGET_CHAIN_NOT_FOUND}
+	}
+
+	res := &blockValidationResult{
+		validationCode: peer.TxValidationCode_VALID,
+		txType:         common.HeaderType(chdr.Type),
+		txID:           chdr.TxId,
+		channel:        channel,
+		payload:        payload,
+	}
+
+	if res.txType == common.HeaderType_ENDORSER_TRANSACTION {
+		// Check duplicate transactions, including the ones committed before the snapshot
+		// that the ledger has been created from, whose blocks are not available
+		if _, err := v.support.Ledger().GetTransactionByID(res.txID); err == nil || err == blkstorage.ErrBlockPruned {
+			logger.Error("Duplicate transaction found, ", res.txID, ", skipping")
+			return &blockValidationResult{validationCode: peer.TxValidationCode_DUPLICATE_TXID}
+		}
+
+		// Validate tx with vscc and policy
+		logger.Debug("Validating transaction vscc tx validate")
+		err, cde := v.vscc.VSCCValidateTx(payload, d, env)
+		if err != nil {
+			logger.Errorf("VSCCValidateTx for transaction txId = %s returned error %s", res.txID, err)
+			switch err.(type) {
+			case *VSCCExecutionFailureError:
+				return &blockValidationResult{err: err}
+			case *VSCCInfoLookupFailureError:
+				return &blockValidationResult{err: err}
+			default:
+				return &blockValidationResult{validationCode: cde}
+			}
+		}
+	} else if res.txType == common.HeaderType_CONFIG {
+		// the config is applied in the order of the block; the orderers cut
+		// config transactions in blocks of their own, hence no transaction of
+		// the block is validated against the config it replaces
+		res.configEnvelope, err = configtx.UnmarshalConfigEnvelope(payload.Data)
+		if err != nil {
+			err := fmt.Errorf("Error unmarshaling config which passed initial validity checks: %s", err)
+			logger.Critical(err)
+			return &blockValidationResult{err: err}
+		}
+	} else {
+		logger.Warningf("Unknown transaction type [%s] in block number [%d] transaction index [%d]",
+			res.txType, block.Header.Number, tIdx)
+		return &blockValidationResult{validationCode: peer.TxValidationCode_UNKNOWN_TX_TYPE}
+	}
+
+	if _, err := proto.Marshal(env); err != nil {
+		logger.Warningf("Cannot marshal transaction due to %s", err)
+		return &blockValidationResult{validationCode: peer.TxValidationCode_MARSHAL_TX_ERROR}
+	}
+
+	return res
+}
+
 // generateCCKey generates a unique identifier for chaincode in specific chain
 func (v *txValidator) generateCCKey(ccName, chainID string) string {
 	return fmt.Sprintf("%s/%s", ccName, chainID)
@@ -405,9 +486,9 @@ func (v *vsccValidatorImpl) GetInfoForValidate(txid, chID, ccID string) (*sysccp
 	vscc := &sysccprovider.ChaincodeInstance{ChainID: chID}
 	var policy []byte
 	var err error
-	if ccID != "lscc" {
+	if ccID != "lscc" && ccID != crosschannel.Namespace {
 		// when we are validating any chaincode other than
-		// LSCC, we need to ask LSCC to give us the name
+		// LSCC or XSCC, we need to ask LSCC to give us the name
 		// of VSCC and of the policy that should be used
 
 		// obtain name of the VSCC and the policy from LSCC
@@ -422,10 +503,10 @@ func (v *vsccValidatorImpl) GetInfoForValidate(txid, chID, ccID string) (*sysccp
 		vscc.ChaincodeName = cd.Vscc
 		policy = cd.Policy
 	} else {
-		// when we are validating LSCC, we use the default
+		// when we are validating LSCC or XSCC, we use the default
 		// VSCC and a default policy that requires one signature
 		// from any of the members of the channel
-		cc.ChaincodeName = "lscc"
+		cc.ChaincodeName = ccID
 		cc.ChaincodeVersion = coreUtil.GetSysCCVersion()
 		vscc.ChaincodeName = "vscc"
 		p := cauthdsl.SignedByAnyMember(v.support.GetMSPIDs(chID))
@@ -458,10 +539,12 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 	   at first, we establish a few facts about this invocation:
 	   1) which namespaces does it write to?
 	   2) does it write to LSCC's namespace?
-	   3) does it write to any cc that cannot be invoked? */
+	   3) does it write to any cc that cannot be invoked?
+	   4) does it write the record of a cross-channel transaction? */
 	wrNamespace := []string{}
 	writesToLSCC := false
 	writesToNonInvokableSCC := false
+	writesToXSCC := false
 	respPayload, err := utils.GetActionFromEnvelope(envBytes)
 	if err != nil {
 		return fmt.Errorf("GetActionFromEnvelope failed, error %s", err), peer.TxValidationCode_BAD_RESPONSE_PAYLOAD
@@ -470,9 +553,20 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
 		return fmt.Errorf("txRWSet.FromProtoBytes failed, error %s", err), peer.TxValidationCode_BAD_RWSET
 	}
+	wrKeys := make(map[string][]string)
+	wrPvtData := make(map[string]bool)
 	for _, ns := range txRWSet.NsRwSets {
-		if len(ns.KvRwSet.Writes) > 0 {
+		// the records of the cross-channel transactions are validated separately
+		if ns.NameSpace == crosschannel.Namespace {
+			writesToXSCC = writesToXSCC || len(ns.KvRwSet.Writes) > 0 || len(ns.KvRwSet.MetadataWrites) > 0
+			continue
+		}
+		// the private data written to the collections of a namespace is
+		// endorsed as required by the chaincode just like its public state
+		wrPvtData[ns.NameSpace] = writesPvtData(ns)
+		if len(ns.KvRwSet.Writes) > 0 || len(ns.KvRwSet.MetadataWrites) > 0 || wrPvtData[ns.NameSpace] {
 			wrNamespace = append(wrNamespace, ns.NameSpace)
+			wrKeys[ns.NameSpace] = writtenKeys(ns.KvRwSet)
 
 			if !writesToLSCC && ns.NameSpace == "lscc" {
 				writesToLSCC = true
@@ -546,6 +640,14 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 			return fmt.Errorf("Chaincode %s attempted to write to the namespace of a system chaincode that cannot be invoked", ccID),
 				peer.TxValidationCode_ILLEGAL_WRITESET
 		}
+		// 3) the only record of a cross-channel transaction we write to the namespace
+		//    of XSCC is the one of the part of the transaction on this channel
+		if writesToXSCC {
+			if err = validateCrossChannelPart(chdr, txRWSet); err != nil {
+				return fmt.Errorf("Chaincode %s attempted to write an invalid cross-channel record: %s", ccID, err),
+					peer.TxValidationCode_ILLEGAL_WRITESET
+			}
+		}
 
 		// validate *EACH* read write set according to its chaincode's endorsement policy
 		for _, ns := range wrNamespace {
@@ -565,8 +667,22 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 				return err, peer.TxValidationCode_EXPIRED_CHAINCODE
 			}
 
-			// do VSCC validation
-			if err = v.VSCCValidateTxForCC(envBytes, chdr.TxId, chdr.ChannelId, vscc.ChaincodeName, vscc.ChaincodeVersion, policy); err != nil {
+			// evaluate the key-level endorsement policies first; the chaincode
+			// policy only applies if a key written, or the private data, has none
+			ccPolicyRequired, err := v.validateKeyLevelPolicies(payload, chdr.TxId, ns, wrKeys[ns])
+			if err != nil {
+				switch err.(type) {
+				case *VSCCEndorsementPolicyError:
+					return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+				default:
+					return err, peer.TxValidationCode_INVALID_OTHER_REASON
+				}
+			}
+			ccPolicyRequired = ccPolicyRequired || wrPvtData[ns]
+
+			// do VSCC validation, which still performs its other checks
+			// when the key-level policies replace the chaincode policy
+			if err = v.VSCCValidateTxForCC(envBytes, chdr.TxId, chdr.ChannelId, ns, vscc.ChaincodeName, vscc.ChaincodeVersion, policy, ccPolicyRequired); err != nil {
 				switch err.(type) {
 				case *VSCCEndorsementPolicyError:
 					return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
@@ -585,6 +701,16 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 				peer.TxValidationCode_ILLEGAL_WRITESET
 		}
 
+		// the records of the cross-channel transactions are written by
+		// the chaincodes of their parts and decided by XSCC only
+		if ccID == crosschannel.Namespace {
+			return v.validateCrossChannelDecision(envBytes, chdr, txRWSet)
+		}
+		if writesToXSCC {
+			return fmt.Errorf("Chaincode %s attempted to write to the namespace of XSCC", ccID),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+
 		// Get latest chaincode version, vscc and validate policy
 		_, vscc, policy, err := v.GetInfoForValidate(chdr.TxId, chdr.ChannelId, ccID)
 		if err != nil {
@@ -597,7 +723,7 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 		// currently, VSCC does custom validation for LSCC only; if an hlf
 		// user creates a new system chaincode which is invokable from the outside
 		// they have to modify VSCC to provide appropriate validation
-		if err = v.VSCCValidateTxForCC(envBytes, chdr.TxId, vscc.ChainID, vscc.ChaincodeName, vscc.ChaincodeVersion, policy); err != nil {
+		if err = v.VSCCValidateTxForCC(envBytes, chdr.TxId, vscc.ChainID, ccID, vscc.ChaincodeName, vscc.ChaincodeVersion, policy, true); err != nil {
 			switch err.(type) {
 			case *VSCCEndorsementPolicyError:
 				return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
@@ -610,28 +736,44 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 	return nil, peer.TxValidationCode_VALID
 }
 
-func (v *vsccValidatorImpl) VSCCValidateTxForCC(envBytes []byte, txid, chid, vsccName, vsccVer string, policy []byte) error {
-	ctxt, err := v.ccprovider.GetContext(v.support.Ledger())
+// VSCCValidateTxForCC validates the action of the transaction on the
+// namespace with the validation plugin of the VSCC name, if there is one,
+// or else by invoking the VSCC. Unless ccPolicyRequired is set, the key-level
+// endorsement policies of the keys written replace the chaincode policy
+func (v *vsccValidatorImpl) VSCCValidateTxForCC(envBytes []byte, txid, chid, namespace, vsccName, vsccVer string, policy []byte, ccPolicyRequired bool) error {
+	if v.pluginValidator != nil && v.pluginValidator.hasPlugin(vsccName) {
+		logger.Debug("Validating txid", txid, "with validation plugin", vsccName)
+		return v.pluginValidator.validateTx(vsccName, envBytes, namespace, policy, ccPolicyRequired)
+	}
+
+	// a chaincode provider holds the simulator of the context it returns,
+	// hence every one of the concurrent invocations gets its own provider
+	ccp := ccprovider.GetChaincodeProvider()
+	ctxt, err := ccp.GetContext(v.support.Ledger())
 	if err != nil {
 		msg := fmt.Sprintf("Cannot obtain context for txid=%s, err %s", txid, err)
 		logger.Errorf(msg)
 		return &VSCCExecutionFailureError{msg}
 	}
-	defer v.ccprovider.ReleaseContext()
+	defer ccp.ReleaseContext()
 
 	// build arguments for VSCC invocation
 	// args[0] - function name (not used now)
 	// args[1] - serialized Envelope
 	// args[2] - serialized policy
+	// args[3] - optional, the key-level policies replace the chaincode policy
 	args := [][]byte{[]byte(""), envBytes, policy}
+	if !ccPolicyRequired {
+		args = append(args, []byte(validationapi.KeyLevelEndorsementArg))
+	}
 
 	// get context to invoke VSCC
 	vscctxid := coreUtil.GenerateUUID()
-	cccid := v.ccprovider.GetCCContext(chid, vsccName, vsccVer, vscctxid, true, nil, nil)
+	cccid := ccp.GetCCContext(chid, vsccName, vsccVer, vscctxid, true, nil, nil)
 
 	// invoke VSCC
 	logger.Debug("Invoking VSCC txid", txid, "chaindID", chid)
-	res, _, err := v.ccprovider.ExecuteChaincode(ctxt, cccid, args)
+	res, _, err := ccp.ExecuteChaincode(ctxt, cccid, args)
 	if err != nil {
 		msg := fmt.Sprintf("Invoke VSCC failed for transaction txid=%s, error %s", txid, err)
 		logger.Errorf(msg)
@@ -682,3 +824,157 @@ func (v *vsccValidatorImpl) getCDataForCC(ccid string) (*ccprovider.ChaincodeDat
 
 	return cd, err
 }
+
+// writtenKeys returns the keys whose value or metadata is written by the given rwset
+func writtenKeys(rwset *kvrwset.KVRWSet) []string {
+	keys := []string{}
+	seen := make(map[string]struct{})
+	for _, w := range rwset.Writes {
+		if _, ok := seen[w.Key]; !ok {
+			seen[w.Key] = struct{}{}
+			keys = append(keys, w.Key)
+		}
+	}
+	for _, mw := range rwset.MetadataWrites {
+		if _, ok := seen[mw.Key]; !ok {
+			seen[mw.Key] = struct{}{}
+			keys = append(keys, mw.Key)
+		}
+	}
+	return keys
+}
+
+// validateKeyLevelPolicies evaluates the key-level endorsement policies (stored as the
+// validation parameter in the metadata of each key) of the keys written in namespace ns.
+// It returns true if at least one of the keys has no key-level policy, in which case
+// the chaincode endorsement policy has to be satisfied as well
+func (v *vsccValidatorImpl) validateKeyLevelPolicies(payload *common.Payload, txid, ns string, keys []string) (bool, error) {
+	if len(keys) == 0 {
+		return true, nil
+	}
+
+	l := v.support.Ledger()
+	if l == nil {
+		return false, fmt.Errorf("nil ledger instance")
+	}
+
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return false, fmt.Errorf("Could not retrieve QueryExecutor, error %s", err)
+	}
+	defer qe.Done()
+
+	ccPolicyRequired := false
+	var signatureSet []*common.SignedData
+	for _, key := range keys {
+		metadata, err := qe.GetStateMetadata(ns, key)
+		if err != nil {
+			return false, &VSCCInfoLookupFailureError{fmt.Sprintf("Could not retrieve metadata for key %s in namespace %s, error %s", key, ns, err)}
+		}
+
+		vp := metadata[peer.MetaDataKeys_VALIDATION_PARAMETER.String()]
+		if len(vp) == 0 {
+			ccPolicyRequired = true
+			continue
+		}
+
+		if signatureSet == nil {
+			if signatureSet, err = getSignatureSet(payload); err != nil {
+				return false, err
+			}
+		}
+
+		policy, _, err := cauthdsl.NewPolicyProvider(v.support.MSPManager()).NewPolicy(vp)
+		if err != nil {
+			return false, fmt.Errorf("Invalid key-level endorsement policy for key %s in namespace %s, error %s", key, ns, err)
+		}
+
+		if err = policy.Evaluate(signatureSet); err != nil {
+			logger.Warningf("Key-level endorsement policy failure for transaction txid=%s, key %s in namespace %s, err: %s", txid, key, ns, err)
+			return false, &VSCCEndorsementPolicyError{fmt.Sprintf("Key-level endorsement policy for key %s in namespace %s not satisfied, error %s", key, ns, err)}
+		}
+	}
+
+	return ccPolicyRequired, nil
+}
+
+// writesPvtData returns whether the namespace writes to any of its collections
+func writesPvtData(ns *rwsetutil.NsRwSet) bool {
+	for _, coll := range ns.CollHashedRwSets {
+		if coll.HashedRwSet != nil && len(coll.HashedRwSet.HashedWrites) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getSignatureSet builds the set of signed data from the endorsements of
+// the transaction, skipping endorsements from duplicated identities
+func getSignatureSet(payload *common.Payload) ([]*common.SignedData, error) {
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, fmt.Errorf("GetTransaction failed, error %s", err)
+	}
+
+	signatureSet := []*common.SignedData{}
+	signatureMap := make(map[string]struct{})
+	for _, act := range tx.Actions {
+		cap, err := utils.GetChaincodeActionPayload(act.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("GetChaincodeActionPayload failed, error %s", err)
+		}
+
+		prespBytes := cap.Action.ProposalResponsePayload
+		for _, endorsement := range cap.Action.Endorsements {
+			serializedIdentity := &mspprotos.SerializedIdentity{}
+			if err := proto.Unmarshal(endorsement.Endorser, serializedIdentity); err != nil {
+				return nil, fmt.Errorf("Unmarshal endorser error: %s", err)
+			}
+			identity := serializedIdentity.Mspid + string(serializedIdentity.IdBytes)
+			if _, ok := signatureMap[identity]; ok {
+				continue
+			}
+			signatureSet = append(signatureSet, &common.SignedData{
+				Data:      append(append([]byte{}, prespBytes...), endorsement.Endorser...),
+				Identity:  endorsement.Endorser,
+				Signature: endorsement.Signature})
+			signatureMap[identity] = struct{}{}
+		}
+	}
+
+	return signatureSet, nil
+}
+
+// checkValidationParameterUpdates makes sure that the transaction does not write
+// to keys whose validation parameter was updated by a valid transaction earlier in
+// the same block, since the endorsements were collected against the old policy;
+// it then records the validation parameter updates of the transaction
+func checkValidationParameterUpdates(envBytes []byte, updated map[string]map[string]struct{}) error {
+	respPayload, err := utils.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		return fmt.Errorf("GetActionFromEnvelope failed, error %s", err)
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+		return fmt.Errorf("txRWSet.FromProtoBytes failed, error %s", err)
+	}
+
+	for _, ns := range txRWSet.NsRwSets {
+		for _, key := range writtenKeys(ns.KvRwSet) {
+			if _, ok := updated[ns.NameSpace][key]; ok {
+				return fmt.Errorf("validation parameter for key %s in namespace %s was updated in the same block", key, ns.NameSpace)
+			}
+		}
+	}
+
+	for _, ns := range txRWSet.NsRwSets {
+		for _, mw := range ns.KvRwSet.MetadataWrites {
+			if updated[ns.NameSpace] == nil {
+				updated[ns.NameSpace] = make(map[string]struct{})
+			}
+			updated[ns.NameSpace][mw.Key] = struct{}{}
+		}
+	}
+
+	return nil
+}
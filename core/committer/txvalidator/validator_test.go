@@ -59,7 +59,7 @@ func setupLedgerAndValidator(t *testing.T) (ledger.PeerLedger, Validator) {
 	assert.NoError(t, err)
 	theLedger, err := ledgermgmt.CreateLedger(gb)
 	assert.NoError(t, err)
-	theValidator := NewTxValidator(&mockSupport{l: theLedger})
+	theValidator := NewTxValidator(&mockSupport{l: theLedger}, nil)
 
 	return theLedger, theValidator
 }
@@ -133,7 +133,8 @@ func putCCInfo(theLedger ledger.PeerLedger, ccname string, policy []byte, t *tes
 }
 
 type mockSupport struct {
-	l ledger.PeerLedger
+	l       ledger.PeerLedger
+	workers chan struct{}
 }
 
 func (m *mockSupport) Ledger() ledger.PeerLedger {
@@ -152,6 +153,18 @@ func (m *mockSupport) GetMSPIDs(cid string) []string {
 	return []string{"DEFAULT"}
 }
 
+func (m *mockSupport) Acquire() {
+	if m.workers != nil {
+		m.workers <- struct{}{}
+	}
+}
+
+func (m *mockSupport) Release() {
+	if m.workers != nil {
+		<-m.workers
+	}
+}
+
 func assertInvalid(block *common.Block, t *testing.T, code peer.TxValidationCode) {
 	txsFilter := lutils.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
 	assert.True(t, txsFilter.IsInvalid(0))
@@ -212,6 +225,219 @@ func TestInvokeOK(t *testing.T) {
 	assertValid(b, t)
 }
 
+func TestInvokeNOKPvtDataOnly(t *testing.T) {
+	l, v := setupLedgerAndValidator(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	ccID := "mycc"
+
+	putCCInfo(l, ccID, signedByAnyMember([]string{"DEFAULT"}), t)
+
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToPvtAndHashedWriteSet(ccID, "coll", "key", []byte("value"))
+	rws, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+	assert.NoError(t, err)
+
+	tx := getEnv(ccID, rws, t)
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+
+	// the endorsement of a transaction writing private data only
+	// must still satisfy the policy of the chaincode
+	c := executeChaincodeProvider.getCallback()
+	executeChaincodeProvider.setCallback(func() (*peer.Response, *peer.ChaincodeEvent, error) {
+		return &peer.Response{Status: shim.ERROR}, nil, nil
+	})
+	err = v.Validate(b)
+	executeChaincodeProvider.setCallback(c)
+	assert.NoError(t, err)
+	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+}
+
+func putKeyLevelPolicy(theLedger ledger.PeerLedger, ccname, key string, policy []byte, t *testing.T) {
+	simulator, err := theLedger.NewTxSimulator()
+	assert.NoError(t, err)
+	simulator.SetState(ccname, key, []byte("value"))
+	simulator.SetStateMetadata(ccname, key, map[string][]byte{peer.MetaDataKeys_VALIDATION_PARAMETER.String(): policy})
+	simulator.Done()
+
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	bcInfo, err := theLedger.GetBlockchainInfo()
+	assert.NoError(t, err)
+	block := testutil.ConstructBlock(t, bcInfo.Height, bcInfo.CurrentBlockHash, [][]byte{simRes}, true)
+	err = theLedger.Commit(block)
+	assert.NoError(t, err)
+}
+
+func TestInvokeKeyLevelPolicyOK(t *testing.T) {
+	l, v := setupLedgerAndValidator(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	ccID := "mycc"
+
+	putCCInfo(l, ccID, signedByAnyMember([]string{"DEFAULT"}), t)
+	putKeyLevelPolicy(l, ccID, "key", signedByAnyMember([]string{"DEFAULT"}), t)
+
+	tx := getEnv(ccID, createRWset(t, ccID), t)
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+
+	err := v.Validate(b)
+	assert.NoError(t, err)
+	assertValid(b, t)
+
+	// the chaincode is still validated by its VSCC even though
+	// the only key written has a key-level endorsement policy
+	tx = getEnv(ccID, createRWset(t, ccID), t)
+	b = &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	c := executeChaincodeProvider.getCallback()
+	executeChaincodeProvider.setCallback(func() (*peer.Response, *peer.ChaincodeEvent, error) {
+		return &peer.Response{Status: shim.ERROR}, nil, nil
+	})
+	err = v.Validate(b)
+	executeChaincodeProvider.setCallback(c)
+	assert.NoError(t, err)
+	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+}
+
+func TestInvokeKeyLevelPolicyNOK(t *testing.T) {
+	l, v := setupLedgerAndValidator(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	ccID := "mycc"
+
+	putCCInfo(l, ccID, signedByAnyMember([]string{"DEFAULT"}), t)
+	putKeyLevelPolicy(l, ccID, "key", signedByAnyMember([]string{"SomeOtherMSP"}), t)
+
+	tx := getEnv(ccID, createRWset(t, ccID), t)
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+
+	err := v.Validate(b)
+	assert.NoError(t, err)
+	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+}
+
+func TestInvokeKeyLevelPolicyUpdatedInBlock(t *testing.T) {
+	l, v := setupLedgerAndValidator(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	ccID := "mycc"
+
+	putCCInfo(l, ccID, signedByAnyMember([]string{"DEFAULT"}), t)
+
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToMetadataWriteSet(ccID, "key", map[string][]byte{peer.MetaDataKeys_VALIDATION_PARAMETER.String(): signedByAnyMember([]string{"DEFAULT"})})
+	rws, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+	assert.NoError(t, err)
+
+	tx1 := getEnv(ccID, rws, t)
+	tx2 := getEnv(ccID, createRWset(t, ccID), t)
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx1), utils.MarshalOrPanic(tx2)}}}
+
+	err = v.Validate(b)
+	assert.NoError(t, err)
+	txsFilter := lutils.TxValidationFlags(b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.True(t, txsFilter.IsValid(0))
+	assert.True(t, txsFilter.IsSetTo(1, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE))
+}
+
+// getUpgradeEnv returns an endorsed transaction upgrading the chaincode
+func getUpgradeEnv(ccID, version string, t *testing.T) *common.Envelope {
+	cds := &peer.ChaincodeDeploymentSpec{ChaincodeSpec: &peer.ChaincodeSpec{
+		ChaincodeId: &peer.ChaincodeID{Name: ccID, Version: version},
+		Type:        peer.ChaincodeSpec_GOLANG}}
+	prop, _, err := utils.CreateUpgradeProposalFromCDS(util.GetTestChainID(), cds, signerSerialized, nil, nil, nil, nil)
+	assert.NoError(t, err)
+
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, createRWset(t, "lscc"), nil, &peer.ChaincodeID{Name: "lscc", Version: ccVersion}, nil, signer)
+	assert.NoError(t, err)
+
+	tx, err := utils.CreateSignedTx(prop, signer, presp)
+	assert.NoError(t, err)
+	return tx
+}
+
+func TestParallelValidation(t *testing.T) {
+	l, _ := setupLedgerAndValidator(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	ccID := "mycc"
+	otherCCID := "othercc"
+	simulator, err := l.NewTxSimulator()
+	assert.NoError(t, err)
+	for _, name := range []string{ccID, otherCCID, "lscc"} {
+		cd := &ccp.ChaincodeData{Name: name, Version: ccVersion, Vscc: "vscc", Policy: signedByAnyMember([]string{"DEFAULT"})}
+		simulator.SetState("lscc", name, utils.MarshalOrPanic(cd))
+	}
+	simulator.Done()
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	assert.NoError(t, l.Commit(testutil.ConstructBlock(t, 1, []byte("hash"), [][]byte{simRes}, true)))
+
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToMetadataWriteSet(otherCCID, "key", map[string][]byte{peer.MetaDataKeys_VALIDATION_PARAMETER.String(): signedByAnyMember([]string{"DEFAULT"})})
+	rws, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+	assert.NoError(t, err)
+
+	txs := [][]byte{
+		utils.MarshalOrPanic(getEnv(ccID, createRWset(t, ccID), t)), // invokes the chaincode upgraded later in the block
+		utils.MarshalOrPanic(getEnv(otherCCID, rws, t)),             // updates the validation parameter of the key
+		[]byte("garbage"), // not a transaction
+		utils.MarshalOrPanic(getEnv(otherCCID, createRWset(t, otherCCID), t)), // writes the key updated by an earlier transaction
+		utils.MarshalOrPanic(getEnv("nocc", createRWset(t, "nocc"), t)),       // invokes a chaincode which does not exist
+		utils.MarshalOrPanic(getUpgradeEnv(ccID, "2.0", t)),                   // upgrades the chaincode
+		utils.MarshalOrPanic(getEnv(ccID, createRWset(t, ccID), t)),           // invokes the upgraded chaincode
+	}
+	expected := []peer.TxValidationCode{
+		peer.TxValidationCode_CHAINCODE_VERSION_CONFLICT,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_INVALID_OTHER_REASON,
+		peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE,
+		peer.TxValidationCode_INVALID_OTHER_REASON,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_CHAINCODE_VERSION_CONFLICT,
+	}
+
+	// the outcome of the validation does not depend on the pool size
+	for _, poolSize := range []int{1, 2, len(txs)} {
+		v := NewTxValidator(&mockSupport{l: l, workers: make(chan struct{}, poolSize)}, nil)
+		for i := 0; i < 5; i++ {
+			b := &common.Block{Header: &common.BlockHeader{}, Data: &common.BlockData{Data: txs}}
+			assert.NoError(t, v.Validate(b))
+			txsFilter := lutils.TxValidationFlags(b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+			for tIdx, code := range expected {
+				assert.True(t, txsFilter.IsSetTo(tIdx, code), "pool size %d, transaction %d: expected %s, got %s", poolSize, tIdx, code, txsFilter.Flag(tIdx))
+			}
+		}
+	}
+}
+
+func TestParallelValidationExecutionFailure(t *testing.T) {
+	l, _ := setupLedgerAndValidator(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	ccID := "mycc"
+	putCCInfo(l, ccID, signedByAnyMember([]string{"DEFAULT"}), t)
+
+	c := executeChaincodeProvider.getCallback()
+	defer executeChaincodeProvider.setCallback(c)
+	executeChaincodeProvider.setCallback(func() (*peer.Response, *peer.ChaincodeEvent, error) {
+		return nil, nil, errors.New("vscc unavailable")
+	})
+
+	// the validation of the block fails, whatever the other transactions
+	txs := [][]byte{[]byte("garbage"), utils.MarshalOrPanic(getEnv(ccID, createRWset(t, ccID), t)), []byte("garbage")}
+	v := NewTxValidator(&mockSupport{l: l, workers: make(chan struct{}, 2)}, nil)
+	err := v.Validate(&common.Block{Header: &common.BlockHeader{}, Data: &common.BlockData{Data: txs}})
+	assert.Error(t, err)
+	assert.IsType(t, &VSCCExecutionFailureError{}, err)
+}
+
 func TestInvokeOKSCC(t *testing.T) {
 	l, v := setupLedgerAndValidator(t)
 	defer ledgermgmt.CleanupTestEnv()
@@ -389,6 +615,142 @@ func TestInvokeNoBlock(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// getCrossChannelEnv returns a transaction of ccID recording the cross-channel
+// transaction txID, or the transaction itself if empty
+func getCrossChannelEnv(ccID, txID string, status peer.CrossChannelRecord_Status, channels []string, t *testing.T) *common.Envelope {
+	prop, err := getProposal(ccID)
+	assert.NoError(t, err)
+	if txID == "" {
+		hdr, err := utils.GetHeader(prop.Header)
+		assert.NoError(t, err)
+		chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+		assert.NoError(t, err)
+		txID = chdr.TxId
+	}
+
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	if ccID != "xscc" {
+		rwsetBuilder.AddToWriteSet(ccID, "key", []byte("value"))
+	}
+	rwsetBuilder.AddToWriteSet("xscc", txID, utils.MarshalOrPanic(&peer.CrossChannelRecord{Status: status, Channels: channels}))
+	res, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+	assert.NoError(t, err)
+
+	response := &peer.Response{Status: 200}
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, response, res, nil, &peer.ChaincodeID{Name: ccID, Version: ccVersion}, nil, signer)
+	assert.NoError(t, err)
+	tx, err := utils.CreateSignedTx(prop, signer, presp)
+	assert.NoError(t, err)
+	return tx
+}
+
+// putCrossChannelRecord commits the part of a cross-channel transaction
+// writing to ccname, which is deployed first unless nil policy
+func putCrossChannelRecord(theLedger ledger.PeerLedger, txID, ccname string, policy []byte, t *testing.T) {
+	blockNum := uint64(1)
+	if policy != nil {
+		putCCInfo(theLedger, ccname, policy, t)
+		blockNum++
+	}
+	record := &peer.CrossChannelRecord{
+		Status:   peer.CrossChannelRecord_PREPARED,
+		Channels: []string{"otherchain", util.GetTestChainID()},
+	}
+
+	// the committer holds the writes of the part until it is decided
+	simulator, err := theLedger.NewTxSimulator()
+	assert.NoError(t, err)
+	simulator.SetState(ccname, "key", []byte("value"))
+	simulator.SetState("xscc", txID, utils.MarshalOrPanic(record))
+	simulator.Done()
+
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	block := testutil.ConstructBlock(t, blockNum, []byte("hash"), [][]byte{simRes}, true)
+	assert.NoError(t, theLedger.Commit(block))
+}
+
+func TestInvokeCrossChannelPart(t *testing.T) {
+	l, v := setupLedgerAndValidator(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	ccID := "mycc"
+	chainID := util.GetTestChainID()
+
+	putCCInfo(l, ccID, signedByAnyMember([]string{"DEFAULT"}), t)
+
+	tx := getCrossChannelEnv(ccID, "", peer.CrossChannelRecord_PREPARED, []string{chainID, "otherchain"}, t)
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	err := v.Validate(b)
+	assert.NoError(t, err)
+	assertValid(b, t)
+
+	// the part must be recorded on this channel and at least another one
+	tx = getCrossChannelEnv(ccID, "", peer.CrossChannelRecord_PREPARED, []string{chainID}, t)
+	b = &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	err = v.Validate(b)
+	assert.NoError(t, err)
+	assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+
+	// only the record of the transaction itself can be written...
+	tx = getCrossChannelEnv(ccID, "othertx", peer.CrossChannelRecord_PREPARED, []string{chainID, "otherchain"}, t)
+	b = &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	err = v.Validate(b)
+	assert.NoError(t, err)
+	assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+
+	// ...and only as PREPARED
+	tx = getCrossChannelEnv(ccID, "", peer.CrossChannelRecord_COMMITTED, []string{chainID, "otherchain"}, t)
+	b = &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	err = v.Validate(b)
+	assert.NoError(t, err)
+	assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+}
+
+func TestInvokeCrossChannelDecision(t *testing.T) {
+	l, v := setupLedgerAndValidator(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	channels := []string{"otherchain", util.GetTestChainID()}
+	putCrossChannelRecord(l, "xtx", "mycc", signedByAnyMember([]string{"DEFAULT"}), t)
+
+	tx := getCrossChannelEnv("xscc", "xtx", peer.CrossChannelRecord_COMMITTED, channels, t)
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	err := v.Validate(b)
+	assert.NoError(t, err)
+	assertValid(b, t)
+
+	// XSCC only decides the transaction
+	tx = getCrossChannelEnv("xscc", "xtx", peer.CrossChannelRecord_PREPARED, channels, t)
+	b = &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	err = v.Validate(b)
+	assert.NoError(t, err)
+	assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+
+	// and no other system chaincode does
+	tx = getCrossChannelEnv("lscc", "xtx", peer.CrossChannelRecord_ABORTED, channels, t)
+	b = &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	err = v.Validate(b)
+	assert.NoError(t, err)
+	assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+}
+
+func TestInvokeNOKCrossChannelDecisionCCDoesntExist(t *testing.T) {
+	l, v := setupLedgerAndValidator(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	putCrossChannelRecord(l, "xtx", "mycc", nil, t)
+
+	tx := getCrossChannelEnv("xscc", "xtx", peer.CrossChannelRecord_COMMITTED, []string{"otherchain", util.GetTestChainID()}, t)
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+	err := v.Validate(b)
+	assert.NoError(t, err)
+	assertInvalid(b, t, peer.TxValidationCode_INVALID_OTHER_REASON)
+}
+
 // mockLedger structure used to test ledger
 // failure, therefore leveraging mocking
 // library as need to simulate ledger which not
@@ -467,6 +829,14 @@ func (m *mockLedger) Commit(block *common.Block) error {
 	return nil
 }
 
+func (m *mockLedger) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error {
+	return nil
+}
+
+func (m *mockLedger) ExportSnapshot(snapshotDir string) error {
+	return nil
+}
+
 // mockQueryExecutor mock of the query executor,
 // needed to simulate inability to access state db, e.g.
 // the case where due to db failure it's not possible to
@@ -483,6 +853,16 @@ func (exec *mockQueryExecutor) GetState(namespace string, key string) ([]byte, e
 	return args.Get(0).([]byte), args.Error(1)
 }
 
+func (exec *mockQueryExecutor) GetPrivateData(namespace, collection, key string) ([]byte, error) {
+	args := exec.Called(namespace, collection, key)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (exec *mockQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	args := exec.Called(namespace, key)
+	return args.Get(0).(map[string][]byte), args.Error(1)
+}
+
 func (exec *mockQueryExecutor) GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
 	args := exec.Called(namespace, keys)
 	return args.Get(0).([][]byte), args.Error(1)
@@ -498,6 +878,16 @@ func (exec *mockQueryExecutor) ExecuteQuery(namespace, query string) (ledger2.Re
 	return args.Get(0).(ledger2.ResultsIterator), args.Error(1)
 }
 
+func (exec *mockQueryExecutor) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (ledger.QueryResultsIterator, error) {
+	args := exec.Called(namespace, startKey, endKey, pageSize)
+	return args.Get(0).(ledger.QueryResultsIterator), args.Error(1)
+}
+
+func (exec *mockQueryExecutor) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (ledger.QueryResultsIterator, error) {
+	args := exec.Called(namespace, query, bookmark, pageSize)
+	return args.Get(0).(ledger.QueryResultsIterator), args.Error(1)
+}
+
 func (exec *mockQueryExecutor) Done() {
 }
 
@@ -510,7 +900,7 @@ func (exec *mockQueryExecutor) Done() {
 // returned from the function call.
 func TestLedgerIsNoAvailable(t *testing.T) {
 	theLedger := new(mockLedger)
-	validator := NewTxValidator(&mockSupport{l: theLedger})
+	validator := NewTxValidator(&mockSupport{l: theLedger}, nil)
 
 	ccID := "mycc"
 	tx := getEnv(ccID, createRWset(t, ccID), t)
@@ -534,7 +924,7 @@ func TestLedgerIsNoAvailable(t *testing.T) {
 
 func TestValidationInvalidEndorsing(t *testing.T) {
 	theLedger := new(mockLedger)
-	validator := NewTxValidator(&mockSupport{l: theLedger})
+	validator := NewTxValidator(&mockSupport{l: theLedger}, nil)
 
 	ccID := "mycc"
 	tx := getEnv(ccID, createRWset(t, ccID), t)
@@ -552,6 +942,7 @@ func TestValidationInvalidEndorsing(t *testing.T) {
 
 	queryExecutor := new(mockQueryExecutor)
 	queryExecutor.On("GetState", "lscc", ccID).Return(cdbytes, nil)
+	queryExecutor.On("GetStateMetadata", ccID, "key").Return(map[string][]byte(nil), nil)
 	theLedger.On("NewQueryExecutor", mock.Anything).Return(queryExecutor, nil)
 
 	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
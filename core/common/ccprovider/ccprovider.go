@@ -29,6 +29,7 @@ import (
 	"bytes"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/ledger"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
@@ -316,6 +317,30 @@ func GetInstalledChaincodes() (*pb.ChaincodeQueryResponse, error) {
 	return cqr, nil
 }
 
+// IsChaincodeDeployed returns true if the chaincode with the given name, version and
+// package fingerprint is instantiated on the given channel
+func IsChaincodeDeployed(chainid, ccname, ccversion string, ccid []byte) (bool, error) {
+	qe, err := sysccprovider.GetSystemChaincodeProvider().GetQueryExecutorForLedger(chainid)
+	if err != nil {
+		return false, fmt.Errorf("Could not retrieve QueryExecutor for channel %s, error %s", chainid, err)
+	}
+	defer qe.Done()
+
+	cdbytes, err := qe.GetState("lscc", ccname)
+	if err != nil {
+		return false, fmt.Errorf("Could not retrieve state for chaincode %s on channel %s, error %s", ccname, chainid, err)
+	}
+	if cdbytes == nil {
+		return false, nil
+	}
+
+	cd := &ChaincodeData{}
+	if err = proto.Unmarshal(cdbytes, cd); err != nil {
+		return false, fmt.Errorf("Unmarshalling ChaincodeData for %s failed, error %s", ccname, err)
+	}
+	return cd.Version == ccversion && bytes.Equal(cd.Id, ccid), nil
+}
+
 //CCContext pass this around instead of string of args
 type CCContext struct {
 	//ChainID chain id
@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccprovider
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// ccPackageStatedbDir is the directory of the chaincode code package which holds
+// the state database specific artifacts, e.g. META-INF/statedb/couchdb/indexes
+const ccPackageStatedbDir = "META-INF/statedb/"
+
+// ExtractStatedbArtifactsForChaincode extracts the statedb artifacts from the code package of the
+// installed chaincode with the given name and version. The returned flag is false if the chaincode
+// is not installed on this peer
+func ExtractStatedbArtifactsForChaincode(ccname, ccversion string) (installed bool, statedbArtifactsTar []byte, err error) {
+	if exists, _ := ChaincodePackageExists(ccname, ccversion); !exists {
+		ccproviderLogger.Debugf("Chaincode %s:%s is not installed on this peer", ccname, ccversion)
+		return false, nil, nil
+	}
+	ccpackage, err := GetChaincodeFromFS(ccname, ccversion)
+	if err != nil {
+		return true, nil, err
+	}
+	statedbArtifactsTar, err = ExtractStatedbArtifactsFromCCPackage(ccpackage)
+	return true, statedbArtifactsTar, err
+}
+
+// ExtractStatedbArtifactsFromCCPackage extracts the statedb artifacts from the code package of
+// the given chaincode package and returns them as a tar, in which the entries are named relative
+// to the statedb directory, e.g. couchdb/indexes/indexOwner.json. Nil is returned if the code
+// package contains no statedb artifacts
+func ExtractStatedbArtifactsFromCCPackage(ccpackage CCPackage) ([]byte, error) {
+	cds := ccpackage.GetDepSpec()
+	if cds == nil || len(cds.CodePackage) == 0 {
+		return nil, nil
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(cds.CodePackage))
+	if err != nil {
+		return nil, fmt.Errorf("failure opening codepackage gzip stream: %s", err)
+	}
+	tr := tar.NewReader(gr)
+
+	statedbTarBuffer := bytes.NewBuffer(nil)
+	tw := tar.NewWriter(statedbTarBuffer)
+	found := false
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading the codepackage: %s", err)
+		}
+		if !strings.HasPrefix(header.Name, ccPackageStatedbDir) {
+			continue
+		}
+		ccproviderLogger.Debugf("Extracting statedb artifact %s", header.Name)
+		header.Name = strings.TrimPrefix(header.Name, ccPackageStatedbDir)
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return nil, err
+		}
+		found = true
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return statedbTarBuffer.Bytes(), nil
+}
+
+// ExtractFileEntries returns the content of the files placed directly under the given
+// directory of a statedb artifacts tar, keyed by file name
+func ExtractFileEntries(tarBytes []byte, dir string) (map[string][]byte, error) {
+	fileEntries := make(map[string][]byte)
+	if len(tarBytes) == 0 {
+		return fileEntries, nil
+	}
+	tr := tar.NewReader(bytes.NewReader(tarBytes))
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading the statedb artifacts: %s", err)
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		fileDir, fileName := path.Split(header.Name)
+		if path.Clean(fileDir) != path.Clean(dir) {
+			continue
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		fileEntries[fileName] = content
+	}
+	return fileEntries, nil
+}
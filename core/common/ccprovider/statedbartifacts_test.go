@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccprovider
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"testing"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func getCodePackage(t *testing.T, files map[string][]byte) []byte {
+	codePackage := bytes.NewBuffer(nil)
+	gw := gzip.NewWriter(codePackage)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(content)), Mode: 0100644}))
+		_, err := tw.Write(content)
+		assert.NoError(t, err)
+	}
+	tw.Close()
+	gw.Close()
+	return codePackage.Bytes()
+}
+
+func getCDSWithCodePackage(ccname, ccversion string, codePackage []byte) *pb.ChaincodeDeploymentSpec {
+	return &pb.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        1,
+			ChaincodeId: &pb.ChaincodeID{Name: ccname, Version: ccversion},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("")}},
+		},
+		CodePackage: codePackage,
+	}
+}
+
+func TestExtractStatedbArtifacts(t *testing.T) {
+	ccdir := setupccdir()
+	defer os.RemoveAll(ccdir)
+
+	indexOwner := []byte(`{"index":{"fields":["owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`)
+	indexSize := []byte(`{"index":{"fields":["size"]},"ddoc":"indexSizeDoc","name":"indexSize","type":"json"}`)
+	codePackage := getCodePackage(t, map[string][]byte{
+		"src/path/to/cc/main.go":                                []byte("package main"),
+		"META-INF/statedb/couchdb/indexes/indexOwner.json":      indexOwner,
+		"META-INF/statedb/couchdb/indexes/indexSize.json":       indexSize,
+		"META-INF/statedb/couchdb/collections/coll1/index.json": indexOwner,
+		"META-INF/README.txt":                                   []byte("readme"),
+	})
+
+	ccpack, _, _, err := processCDS(getCDSWithCodePackage("cc1", "v1", codePackage), true)
+	assert.NoError(t, err)
+
+	statedbArtifactsTar, err := ExtractStatedbArtifactsFromCCPackage(ccpack)
+	assert.NoError(t, err)
+	indexFiles, err := ExtractFileEntries(statedbArtifactsTar, "couchdb/indexes")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"indexOwner.json": indexOwner, "indexSize.json": indexSize}, indexFiles)
+
+	// only the files placed directly under the directory are extracted
+	collectionIndexFiles, err := ExtractFileEntries(statedbArtifactsTar, "couchdb/collections/coll1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"index.json": indexOwner}, collectionIndexFiles)
+
+	// the artifacts of an installed chaincode
+	installed, installedArtifactsTar, err := ExtractStatedbArtifactsForChaincode("cc1", "v1")
+	assert.NoError(t, err)
+	assert.True(t, installed)
+	assert.Equal(t, statedbArtifactsTar, installedArtifactsTar)
+
+	// a chaincode which is not installed
+	installed, installedArtifactsTar, err = ExtractStatedbArtifactsForChaincode("cc1", "v2")
+	assert.NoError(t, err)
+	assert.False(t, installed)
+	assert.Nil(t, installedArtifactsTar)
+}
+
+func TestExtractStatedbArtifactsNoArtifacts(t *testing.T) {
+	codePackage := getCodePackage(t, map[string][]byte{"src/path/to/cc/main.go": []byte("package main")})
+	ccpack, _, _, err := processCDS(getCDSWithCodePackage("cc1", "v1", codePackage), false)
+	assert.NoError(t, err)
+
+	statedbArtifactsTar, err := ExtractStatedbArtifactsFromCCPackage(ccpack)
+	assert.NoError(t, err)
+	assert.Nil(t, statedbArtifactsTar)
+
+	indexFiles, err := ExtractFileEntries(statedbArtifactsTar, "couchdb/indexes")
+	assert.NoError(t, err)
+	assert.Empty(t, indexFiles)
+
+	// a code package which is not a gzipped tar
+	ccpack, _, _, err = processCDS(getCDSWithCodePackage("cc1", "v1", []byte("garbage")), false)
+	assert.NoError(t, err)
+	_, err = ExtractStatedbArtifactsFromCCPackage(ccpack)
+	assert.Error(t, err)
+}
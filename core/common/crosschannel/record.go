@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crosschannel
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// Namespace is the namespace of XSCC, the system chaincode deciding the
+// outcome of the cross-channel transactions, which holds the records of
+// the transactions keyed by their transaction IDs
+const Namespace = "xscc"
+
+// GetRecord returns the record of a cross-channel transaction written by
+// the read-write set of a transaction, along with the transaction ID it is
+// keyed by, or nil if the read-write set writes none. The records are the
+// only writes to the namespace of XSCC, hence an error is returned if the
+// read-write set writes anything else to it
+func GetRecord(txRWSet *rwsetutil.TxRwSet) (string, *pb.CrossChannelRecord, error) {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != Namespace {
+			continue
+		}
+		kvRWSet := nsRWSet.KvRwSet
+		if len(kvRWSet.Writes) == 0 && len(kvRWSet.MetadataWrites) == 0 && len(nsRWSet.CollHashedRwSets) == 0 {
+			return "", nil, nil
+		}
+		if len(kvRWSet.Writes) != 1 || len(kvRWSet.MetadataWrites) != 0 || len(nsRWSet.CollHashedRwSets) != 0 {
+			return "", nil, fmt.Errorf("a single record must be written to the namespace %s", Namespace)
+		}
+		kvWrite := kvRWSet.Writes[0]
+		if kvWrite.IsDelete {
+			return "", nil, fmt.Errorf("the record of transaction %s cannot be deleted", kvWrite.Key)
+		}
+		record := &pb.CrossChannelRecord{}
+		if err := proto.Unmarshal(kvWrite.Value, record); err != nil {
+			return "", nil, fmt.Errorf("invalid record of transaction %s: %s", kvWrite.Key, err)
+		}
+		return kvWrite.Key, record, nil
+	}
+	return "", nil, nil
+}
+
+// UnmarshalRecord returns the record of the bytes kept in the namespace of
+// XSCC, or nil if there are none
+func UnmarshalRecord(recordBytes []byte) (*pb.CrossChannelRecord, error) {
+	if recordBytes == nil {
+		return nil, nil
+	}
+	record := &pb.CrossChannelRecord{}
+	if err := proto.Unmarshal(recordBytes, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crosschannel
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRecord(t *testing.T) {
+	recordBytes, err := proto.Marshal(&pb.CrossChannelRecord{Status: pb.CrossChannelRecord_PREPARED, Channels: []string{"ch1", "ch2"}})
+	assert.NoError(t, err)
+
+	builder := rwsetutil.NewRWSetBuilder()
+	builder.AddToWriteSet("ns1", "key1", []byte("value1"))
+	builder.AddToReadSet(Namespace, "txid1", nil)
+	key, record, err := GetRecord(builder.GetTxReadWriteSet())
+	assert.NoError(t, err)
+	assert.Equal(t, "", key)
+	assert.Nil(t, record)
+
+	builder.AddToWriteSet(Namespace, "txid1", recordBytes)
+	key, record, err = GetRecord(builder.GetTxReadWriteSet())
+	assert.NoError(t, err)
+	assert.Equal(t, "txid1", key)
+	assert.Equal(t, pb.CrossChannelRecord_PREPARED, record.Status)
+	assert.Equal(t, []string{"ch1", "ch2"}, record.Channels)
+
+	builder.AddToWriteSet(Namespace, "txid2", recordBytes)
+	_, _, err = GetRecord(builder.GetTxReadWriteSet())
+	assert.Error(t, err)
+
+	builder = rwsetutil.NewRWSetBuilder()
+	builder.AddToWriteSet(Namespace, "txid1", nil)
+	_, _, err = GetRecord(builder.GetTxReadWriteSet())
+	assert.Error(t, err)
+
+	builder = rwsetutil.NewRWSetBuilder()
+	builder.AddToWriteSet(Namespace, "txid1", []byte("garbage"))
+	_, _, err = GetRecord(builder.GetTxReadWriteSet())
+	assert.Error(t, err)
+}
+
+func TestUnmarshalRecord(t *testing.T) {
+	record, err := UnmarshalRecord(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+
+	recordBytes, err := proto.Marshal(&pb.CrossChannelRecord{Status: pb.CrossChannelRecord_ABORTED})
+	assert.NoError(t, err)
+	record, err = UnmarshalRecord(recordBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, pb.CrossChannelRecord_ABORTED, record.Status)
+
+	_, err = UnmarshalRecord([]byte("garbage"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+)
+
+// collectionSeparator is the separator used to build the KVS
+// key storing the collections of a chaincode; note that we are
+// using as separator a character which is illegal for either the
+// name or the version of a chaincode so there cannot be any
+// collisions when chosing the name
+const collectionSeparator = "~"
+
+// collectionSuffix is the suffix of the KVS key storing the
+// collections of a chaincode
+const collectionSuffix = "collection"
+
+// Collection defines a common interface for collections
+type Collection interface {
+	// CollectionID returns this collection's ID
+	CollectionID() string
+
+	// MemberOrgs returns the collection's members as MSP IDs. This serves as
+	// a human-readable way of quickly identifying who is part of a collection.
+	MemberOrgs() []string
+
+	// RequiredPeerCount returns the minimum number of peers
+	// the private data of this collection is disseminated to
+	RequiredPeerCount() int
+
+	// MaximumPeerCount returns the maximum number of peers
+	// the private data of this collection is disseminated to
+	MaximumPeerCount() int
+}
+
+// BuildCollectionKVSKey returns the KVS key string for a chaincode, given its name
+func BuildCollectionKVSKey(ccname string) string {
+	return ccname + collectionSeparator + collectionSuffix
+}
+
+// IsCollectionConfigKey detects if a key is a collection key
+func IsCollectionConfigKey(key string) bool {
+	return strings.Contains(key, collectionSeparator)
+}
+
+// ValidateCollectionConfigPackage checks that the collections are well formed
+// i.e., each collection has a unique non empty name and a signature policy
+func ValidateCollectionConfigPackage(collectionConfigPkg *common.CollectionConfigPackage) error {
+	names := make(map[string]struct{})
+	for _, collectionConfig := range collectionConfigPkg.Config {
+		staticConfig := collectionConfig.GetStaticCollectionConfig()
+		if staticConfig == nil {
+			return fmt.Errorf("unknown collection configuration type")
+		}
+		if staticConfig.Name == "" {
+			return fmt.Errorf("collection name cannot be empty")
+		}
+		if _, exists := names[staticConfig.Name]; exists {
+			return fmt.Errorf("collection [%s] is defined more than once", staticConfig.Name)
+		}
+		names[staticConfig.Name] = struct{}{}
+		if staticConfig.MemberOrgsPolicy.GetSignaturePolicy() == nil {
+			return fmt.Errorf("collection [%s] has no member orgs policy", staticConfig.Name)
+		}
+		if staticConfig.RequiredPeerCount < 0 || staticConfig.MaximumPeerCount < staticConfig.RequiredPeerCount {
+			return fmt.Errorf("collection [%s] has invalid peer counts, required [%d], maximum [%d]",
+				staticConfig.Name, staticConfig.RequiredPeerCount, staticConfig.MaximumPeerCount)
+		}
+	}
+	return nil
+}
+
+// simpleCollection implements a collection with static properties
+type simpleCollection struct {
+	name         string
+	memberOrgs   []string
+	requiredPeer int
+	maximumPeer  int
+}
+
+// newSimpleCollection constructs a collection from its static configuration
+func newSimpleCollection(collectionConfig *common.StaticCollectionConfig) (*simpleCollection, error) {
+	signaturePolicy := collectionConfig.MemberOrgsPolicy.GetSignaturePolicy()
+	if signaturePolicy == nil {
+		return nil, fmt.Errorf("collection [%s] has no member orgs policy", collectionConfig.Name)
+	}
+
+	// get member org MSP IDs from the envelope
+	var memberOrgs []string
+	for _, principal := range signaturePolicy.Identities {
+		switch principal.PrincipalClassification {
+		case msp.MSPPrincipal_ROLE:
+			mspRole := &msp.MSPRole{}
+			if err := proto.Unmarshal(principal.Principal, mspRole); err != nil {
+				return nil, fmt.Errorf("invalid MSP role in collection [%s]: %s", collectionConfig.Name, err)
+			}
+			memberOrgs = append(memberOrgs, mspRole.MspIdentifier)
+		default:
+			return nil, fmt.Errorf("unsupported principal type [%s] in collection [%s]",
+				principal.PrincipalClassification, collectionConfig.Name)
+		}
+	}
+
+	return &simpleCollection{
+		name:         collectionConfig.Name,
+		memberOrgs:   memberOrgs,
+		requiredPeer: int(collectionConfig.RequiredPeerCount),
+		maximumPeer:  int(collectionConfig.MaximumPeerCount),
+	}, nil
+}
+
+// CollectionID returns the collection's ID
+func (sc *simpleCollection) CollectionID() string {
+	return sc.name
+}
+
+// MemberOrgs returns the MSP IDs that are part of this collection
+func (sc *simpleCollection) MemberOrgs() []string {
+	return sc.memberOrgs
+}
+
+// RequiredPeerCount returns the minimum number of peers
+// required to send private data to
+func (sc *simpleCollection) RequiredPeerCount() int {
+	return sc.requiredPeer
+}
+
+// MaximumPeerCount returns the maximum number of peers
+// to which the private data will be sent
+func (sc *simpleCollection) MaximumPeerCount() int {
+	return sc.maximumPeer
+}
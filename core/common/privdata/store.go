@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// lsccNamespace is the namespace in which the collection configurations are maintained
+const lsccNamespace = "lscc"
+
+// CollectionStore retrieves stored collections based on the collection's
+// properties. It works as a collection object factory and takes care of
+// returning a collection object of an appropriate collection type.
+type CollectionStore interface {
+	// RetrieveCollection retrieves the collection identified by the given criteria
+	RetrieveCollection(common.CollectionCriteria) (Collection, error)
+
+	// RetrieveCollectionConfigPackage retrieves the configuration
+	// for the collection with the supplied criteria
+	RetrieveCollectionConfigPackage(common.CollectionCriteria) (*common.CollectionConfigPackage, error)
+}
+
+// QueryExecutorFactory creates the query executors that are used
+// to read the collection configurations from the state of a channel
+type QueryExecutorFactory interface {
+	NewQueryExecutor() (ledger.QueryExecutor, error)
+}
+
+// simpleCollectionStore implements a collection store based on the
+// collection configurations kept in the lscc namespace of a channel
+type simpleCollectionStore struct {
+	qeFactory QueryExecutorFactory
+}
+
+// NewSimpleCollectionStore returns a collection store that reads
+// the collection configurations using the given query executor factory
+func NewSimpleCollectionStore(qeFactory QueryExecutorFactory) CollectionStore {
+	return &simpleCollectionStore{qeFactory: qeFactory}
+}
+
+// RetrieveCollectionConfigPackage implements method in interface `CollectionStore`
+func (c *simpleCollectionStore) RetrieveCollectionConfigPackage(cc common.CollectionCriteria) (*common.CollectionConfigPackage, error) {
+	qe, err := c.qeFactory.NewQueryExecutor()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve query executor for collection criteria %#v: %s", cc, err)
+	}
+	defer qe.Done()
+
+	cb, err := qe.GetState(lsccNamespace, BuildCollectionKVSKey(cc.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving collection for collection criteria %#v: %s", cc, err)
+	}
+	if cb == nil {
+		return nil, fmt.Errorf("collection config for chaincode [%s] not found", cc.Namespace)
+	}
+
+	collections := &common.CollectionConfigPackage{}
+	if err = proto.Unmarshal(cb, collections); err != nil {
+		return nil, fmt.Errorf("invalid configuration for collection criteria %#v: %s", cc, err)
+	}
+	return collections, nil
+}
+
+// RetrieveCollection implements method in interface `CollectionStore`
+func (c *simpleCollectionStore) RetrieveCollection(cc common.CollectionCriteria) (Collection, error) {
+	collections, err := c.RetrieveCollectionConfigPackage(cc)
+	if err != nil {
+		return nil, err
+	}
+	for _, cconf := range collections.Config {
+		staticConfig := cconf.GetStaticCollectionConfig()
+		if staticConfig != nil && staticConfig.Name == cc.Collection {
+			return newSimpleCollection(staticConfig)
+		}
+	}
+	return nil, fmt.Errorf("collection [%s] of chaincode [%s] not found", cc.Collection, cc.Namespace)
+}
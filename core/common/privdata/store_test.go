@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	mockledger "github.com/hyperledger/fabric/common/mocks/ledger"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockQEFactory struct {
+	qe  ledger.QueryExecutor
+	err error
+}
+
+func (f *mockQEFactory) NewQueryExecutor() (ledger.QueryExecutor, error) {
+	return f.qe, f.err
+}
+
+func buildCollectionConfig(name string, required, maximum int32, orgs ...string) *common.CollectionConfig {
+	return &common.CollectionConfig{
+		Payload: &common.CollectionConfig_StaticCollectionConfig{
+			StaticCollectionConfig: &common.StaticCollectionConfig{
+				Name: name,
+				MemberOrgsPolicy: &common.CollectionPolicyConfig{
+					Payload: &common.CollectionPolicyConfig_SignaturePolicy{
+						SignaturePolicy: cauthdsl.SignedByAnyMember(orgs),
+					},
+				},
+				RequiredPeerCount: required,
+				MaximumPeerCount:  maximum,
+			},
+		},
+	}
+}
+
+func TestBuildCollectionKVSKey(t *testing.T) {
+	key := BuildCollectionKVSKey("mycc")
+	assert.Equal(t, "mycc~collection", key)
+	assert.True(t, IsCollectionConfigKey(key))
+	assert.False(t, IsCollectionConfigKey("mycc"))
+}
+
+func TestValidateCollectionConfigPackage(t *testing.T) {
+	ccp := &common.CollectionConfigPackage{Config: []*common.CollectionConfig{
+		buildCollectionConfig("coll1", 1, 2, "Org1MSP"),
+		buildCollectionConfig("coll2", 0, 1, "Org1MSP", "Org2MSP"),
+	}}
+	assert.NoError(t, ValidateCollectionConfigPackage(ccp))
+
+	ccp.Config = append(ccp.Config, buildCollectionConfig("coll1", 0, 1, "Org1MSP"))
+	assert.Error(t, ValidateCollectionConfigPackage(ccp))
+
+	ccp = &common.CollectionConfigPackage{Config: []*common.CollectionConfig{buildCollectionConfig("", 0, 1, "Org1MSP")}}
+	assert.Error(t, ValidateCollectionConfigPackage(ccp))
+
+	ccp = &common.CollectionConfigPackage{Config: []*common.CollectionConfig{buildCollectionConfig("coll1", 2, 1, "Org1MSP")}}
+	assert.Error(t, ValidateCollectionConfigPackage(ccp))
+}
+
+func TestCollectionStore(t *testing.T) {
+	ccp := &common.CollectionConfigPackage{Config: []*common.CollectionConfig{
+		buildCollectionConfig("coll1", 1, 2, "Org1MSP", "Org2MSP"),
+	}}
+	ccpBytes, err := proto.Marshal(ccp)
+	assert.NoError(t, err)
+	qe := mockledger.NewMockQueryExecutor(map[string]map[string][]byte{
+		"lscc": {BuildCollectionKVSKey("mycc"): ccpBytes, "badcc~collection": []byte("garbage")},
+	})
+	store := NewSimpleCollectionStore(&mockQEFactory{qe: qe})
+
+	retrievedCCP, err := store.RetrieveCollectionConfigPackage(common.CollectionCriteria{Namespace: "mycc"})
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(ccp, retrievedCCP))
+
+	coll, err := store.RetrieveCollection(common.CollectionCriteria{Namespace: "mycc", Collection: "coll1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "coll1", coll.CollectionID())
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, coll.MemberOrgs())
+	assert.Equal(t, 1, coll.RequiredPeerCount())
+	assert.Equal(t, 2, coll.MaximumPeerCount())
+
+	_, err = store.RetrieveCollection(common.CollectionCriteria{Namespace: "mycc", Collection: "coll2"})
+	assert.Error(t, err)
+
+	_, err = store.RetrieveCollection(common.CollectionCriteria{Namespace: "othercc", Collection: "coll1"})
+	assert.Error(t, err)
+
+	_, err = store.RetrieveCollectionConfigPackage(common.CollectionCriteria{Namespace: "badcc"})
+	assert.Error(t, err)
+
+	store = NewSimpleCollectionStore(&mockQEFactory{err: errors.New("ledger not available")})
+	_, err = store.RetrieveCollection(common.CollectionCriteria{Namespace: "mycc", Collection: "coll1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/crosschannel"
+	"github.com/hyperledger/fabric/core/ledger"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"golang.org/x/net/context"
+)
+
+// crossChannelPart is the part of a cross-channel transaction on a channel
+// other than the one of the proposal
+type crossChannelPart struct {
+	invocation *chaincode.CrossChannelInvocation
+	simRes     []byte
+}
+
+// prepareCrossChannelParts returns the parts of the transaction on the other
+// channels the chaincodes invoked by the proposal write to, if any, once the
+// transaction is recorded as PREPARED on each of its channels. The channel of
+// the proposal comes first in the record, as it coordinates the transaction
+func (e *Endorser) prepareCrossChannelParts(chainID string, txid string, txsim ledger.TxSimulator, sims *chaincode.CrossChannelSimulators) ([]*crossChannelPart, error) {
+	var invocations []*chaincode.CrossChannelInvocation
+	for _, invocation := range sims.Invocations() {
+		if !invocation.Writes() {
+			continue
+		}
+		ccName := invocation.ChaincodeSpec.ChaincodeId.Name
+		if invocation.WritesPrivateData() {
+			return nil, fmt.Errorf("private data cannot be written to channel %s by a cross-channel transaction", invocation.ChainID)
+		}
+		if invocation.ChaincodeData == nil {
+			return nil, fmt.Errorf("system chaincode %s cannot be invoked on channel %s by a cross-channel transaction", ccName, invocation.ChainID)
+		}
+		if invocation.Response == nil {
+			return nil, fmt.Errorf("chaincode %s on channel %s did not complete", ccName, invocation.ChainID)
+		}
+		invocations = append(invocations, invocation)
+	}
+	if len(invocations) == 0 {
+		return nil, nil
+	}
+
+	channels := []string{chainID}
+	simulators := []ledger.TxSimulator{txsim}
+	for _, invocation := range invocations {
+		channels = append(channels, invocation.ChainID)
+		simulators = append(simulators, invocation.Simulator)
+	}
+	record, err := proto.Marshal(&pb.CrossChannelRecord{Status: pb.CrossChannelRecord_PREPARED, Channels: channels})
+	if err != nil {
+		return nil, err
+	}
+	for i, sim := range simulators {
+		existing, err := sim.GetState(crosschannel.Namespace, txid)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, fmt.Errorf("transaction %s is already recorded on channel %s", txid, channels[i])
+		}
+		if err = sim.SetState(crosschannel.Namespace, txid, record); err != nil {
+			return nil, err
+		}
+	}
+
+	parts := []*crossChannelPart{}
+	for _, invocation := range invocations {
+		simRes, err := invocation.Simulator.GetTxSimulationResults()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, &crossChannelPart{invocation: invocation, simRes: simRes})
+	}
+	endorserLogger.Debugf("transaction %s is a cross-channel transaction on channels %v", txid, channels)
+	return parts, nil
+}
+
+// endorseCrossChannelParts endorses the parts of the transaction on the other
+// channels, each as the proposal of the chaincode first invoked on its channel
+func (e *Endorser) endorseCrossChannelParts(ctx context.Context, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, parts []*crossChannelPart) ([]*pb.CrossChannelResponse, error) {
+	responses := []*pb.CrossChannelResponse{}
+	for _, part := range parts {
+		invocation := part.invocation
+		partProp, visibility, err := crossChannelProposal(prop, invocation)
+		if err != nil {
+			return nil, err
+		}
+		ccid := &pb.ChaincodeID{Name: invocation.ChaincodeSpec.ChaincodeId.Name}
+		pResp, err := e.endorseProposal(ctx, invocation.ChainID, txid, signedProp, partProp, invocation.Response, part.simRes, nil, visibility, ccid, invocation.Simulator, invocation.ChaincodeData)
+		if err != nil {
+			return nil, err
+		}
+		if pResp.Response.Status >= shim.ERRORTHRESHOLD {
+			return nil, fmt.Errorf("failed to endorse the part on channel %s: %s", invocation.ChainID, pResp.Response.Message)
+		}
+		partPropBytes, err := proto.Marshal(partProp)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, &pb.CrossChannelResponse{ChannelId: invocation.ChainID, Proposal: partPropBytes, Response: pResp})
+	}
+	return responses, nil
+}
+
+// crossChannelProposal returns the proposal of the part of a transaction on
+// another channel, which invokes the chaincode first invoked on the channel
+// with the header of the proposal of the transaction, along with the payload
+// visibility of the proposal
+func crossChannelProposal(prop *pb.Proposal, invocation *chaincode.CrossChannelInvocation) (*pb.Proposal, []byte, error) {
+	hdr, err := putils.GetHeader(prop.Header)
+	if err != nil {
+		return nil, nil, err
+	}
+	chdr, err := putils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+	hdrExt, err := putils.GetChaincodeHeaderExtension(hdr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chdr.ChannelId = invocation.ChainID
+	chdr.Extension, err = proto.Marshal(&pb.ChaincodeHeaderExtension{
+		ChaincodeId:       &pb.ChaincodeID{Name: invocation.ChaincodeSpec.ChaincodeId.Name},
+		PayloadVisibility: hdrExt.PayloadVisibility,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if hdr.ChannelHeader, err = proto.Marshal(chdr); err != nil {
+		return nil, nil, err
+	}
+	hdrBytes, err := proto.Marshal(hdr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cisBytes, err := proto.Marshal(&pb.ChaincodeInvocationSpec{ChaincodeSpec: invocation.ChaincodeSpec})
+	if err != nil {
+		return nil, nil, err
+	}
+	payload, err := proto.Marshal(&pb.ChaincodeProposalPayload{Input: cisBytes})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &pb.Proposal{Header: hdrBytes, Payload: payload}, hdrExt.PayloadVisibility, nil
+}
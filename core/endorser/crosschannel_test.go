@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrossChannelProposal(t *testing.T) {
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{
+		ChaincodeId: &pb.ChaincodeID{Name: "cc1"},
+		Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("transfer")}},
+	}}
+	prop, txid, err := putils.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, "chain1", cis, []byte("creator"))
+	assert.NoError(t, err)
+
+	spec := &pb.ChaincodeSpec{
+		ChaincodeId: &pb.ChaincodeID{Name: "cc2"},
+		Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("pay")}},
+	}
+	partProp, _, err := crossChannelProposal(prop, &chaincode.CrossChannelInvocation{ChainID: "chain2", ChaincodeSpec: spec})
+	assert.NoError(t, err)
+
+	// the part keeps the transaction ID and creator of the proposal...
+	hdr, err := putils.GetHeader(partProp.Header)
+	assert.NoError(t, err)
+	chdr, err := putils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, txid, chdr.TxId)
+	assert.Equal(t, "chain2", chdr.ChannelId)
+	shdr, err := putils.GetSignatureHeader(hdr.SignatureHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("creator"), shdr.Creator)
+
+	// ...and invokes the chaincode first invoked on its channel
+	hdrExt, err := putils.GetChaincodeHeaderExtension(hdr)
+	assert.NoError(t, err)
+	assert.Equal(t, "cc2", hdrExt.ChaincodeId.Name)
+	cpp, err := putils.GetChaincodeProposalPayload(partProp.Payload)
+	assert.NoError(t, err)
+	partCIS := &pb.ChaincodeInvocationSpec{}
+	assert.NoError(t, proto.Unmarshal(cpp.Input, partCIS))
+	assert.True(t, proto.Equal(spec, partCIS.ChaincodeSpec))
+
+	_, _, err = crossChannelProposal(&pb.Proposal{Header: []byte("garbage")}, &chaincode.CrossChannelInvocation{ChainID: "chain2", ChaincodeSpec: spec})
+	assert.Error(t, err)
+}
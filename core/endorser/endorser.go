@@ -11,15 +11,18 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"golang.org/x/net/context"
 
 	"errors"
+	"time"
 
-	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/crosschannel"
 	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/peer"
@@ -28,6 +31,7 @@ import (
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	putils "github.com/hyperledger/fabric/protos/utils"
 )
@@ -50,27 +54,47 @@ var endorserLogger = flogging.MustGetLogger("endorser")
 // The Jira issue that documents Endorser flow along with its relationship to
 // the lifecycle chaincode - https://jira.hyperledger.org/browse/FAB-181
 
+// privateDataDistributor distributes the private data of an endorsed
+// transaction to the other peers of the channel
+type privateDataDistributor func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error
+
 // Endorser provides the Endorser service ProcessProposal
 type Endorser struct {
-	policyChecker policy.PolicyChecker
+	aclProvider           aclmgmt.ACLProvider
+	distributePrivateData privateDataDistributor
+	pluginEndorser        *pluginEndorser
+	throttle              *Throttle
+	responseCache         *responseCache
 }
 
-// NewEndorserServer creates and returns a new Endorser server instance.
-func NewEndorserServer() pb.EndorserServer {
+// NewEndorserServer creates and returns a new Endorser server instance. The
+// chaincodes whose ESCC name is mapped to an endorsement plugin by the
+// PluginMapper, if not nil, are endorsed by the plugin, and the other ones
+// by invoking their ESCC. The number of proposals processed concurrently is
+// limited by the Throttle, if not nil. The responses to the proposals are
+// cached as set by the peer.proposalResponseCache section of the configuration
+func NewEndorserServer(privDist privateDataDistributor, pluginMapper PluginMapper, throttle *Throttle) pb.EndorserServer {
 	e := new(Endorser)
-	e.policyChecker = policy.NewPolicyChecker(
-		peer.NewChannelPolicyManagerGetter(),
-		mgmt.GetLocalMSP(),
-		mgmt.NewLocalMSPPrincipalGetter(),
+	e.distributePrivateData = privDist
+	e.pluginEndorser = newPluginEndorser(pluginMapper)
+	e.throttle = throttle
+	e.responseCache = newResponseCacheFromConfig()
+	e.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			peer.NewChannelPolicyManagerGetter(),
+			mgmt.GetLocalMSP(),
+			mgmt.NewLocalMSPPrincipalGetter(),
+		),
+		peer.NewChannelACLsGetter(),
 	)
 
 	return e
 }
 
 // checkACL checks that the supplied proposal complies
-// with the writers policy of the chain
+// with the ACL of the proposals on the chain
 func (e *Endorser) checkACL(signedProp *pb.SignedProposal, chdr *common.ChannelHeader, shdr *common.SignatureHeader, hdrext *pb.ChaincodeHeaderExtension) error {
-	return e.policyChecker.CheckPolicy(chdr.ChannelId, policies.ChannelApplicationWriters, signedProp)
+	return e.aclProvider.CheckACL(aclmgmt.PROPOSE, chdr.ChannelId, signedProp)
 }
 
 //TODO - check for escc and vscc
@@ -207,7 +231,7 @@ func (e *Endorser) disableJavaCCInst(cid *pb.ChaincodeID, cis *pb.ChaincodeInvoc
 }
 
 //simulate the proposal by calling the chaincode
-func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, cid *pb.ChaincodeID, txsim ledger.TxSimulator) (*ccprovider.ChaincodeData, *pb.Response, []byte, *pb.ChaincodeEvent, error) {
+func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, cid *pb.ChaincodeID, txsim ledger.TxSimulator, crossChannelSims *chaincode.CrossChannelSimulators) (*ccprovider.ChaincodeData, *pb.Response, []byte, *pb.ChaincodeEvent, []*crossChannelPart, error) {
 	endorserLogger.Debugf("Entry - txid: %s channel id: %s", txid, chainID)
 	defer endorserLogger.Debugf("Exit")
 	//we do expect the payload to be a ChaincodeInvocationSpec
@@ -215,17 +239,17 @@ func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid st
 	//as something that should change
 	cis, err := putils.GetChaincodeInvocationSpec(prop)
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	//disable Java install,instantiate,upgrade for now
 	if err = e.disableJavaCCInst(cid, cis); err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	//---1. check ESCC and VSCC for the chaincode
 	if err = e.checkEsccAndVscc(prop); err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	var cdLedger *ccprovider.ChaincodeData
@@ -234,13 +258,13 @@ func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid st
 	if !syscc.IsSysCC(cid.Name) {
 		cdLedger, err = e.getCDSFromLSCC(ctx, chainID, txid, signedProp, prop, cid.Name, txsim)
 		if err != nil {
-			return nil, nil, nil, nil, fmt.Errorf("%s - make sure the chaincode %s has been successfully instantiated and try again", err, cid.Name)
+			return nil, nil, nil, nil, nil, fmt.Errorf("%s - make sure the chaincode %s has been successfully instantiated and try again", err, cid.Name)
 		}
 		version = cdLedger.Version
 
 		err = ccprovider.CheckInsantiationPolicy(cid.Name, version, cdLedger)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
 		}
 	} else {
 		version = util.GetSysCCVersion()
@@ -253,16 +277,40 @@ func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid st
 	res, ccevent, err = e.callChaincode(ctx, chainID, version, txid, signedProp, prop, cis, cid, txsim)
 	if err != nil {
 		endorserLogger.Errorf("failed to invoke chaincode %s on transaction %s, error: %s", cid, txid, err)
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
+	}
+
+	// the writes of the chaincodes invoked on the other channels make the
+	// transaction a cross-channel one, with a part on each of these channels
+	var parts []*crossChannelPart
+	if crossChannelSims != nil && res.Status < shim.ERROR {
+		if parts, err = e.prepareCrossChannelParts(chainID, txid, txsim, crossChannelSims); err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
 	}
 
 	if txsim != nil {
 		if simResult, err = txsim.GetTxSimulationResults(); err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
+		}
+
+		//only the hashes of the private data are part of simResult, the
+		//private data itself is sent to the authorized peers of the channel
+		var pvtSimResult *rwset.TxPvtReadWriteSet
+		if pvtSimResult, err = txsim.GetPvtSimulationResults(); err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		if pvtSimResult != nil && len(parts) > 0 {
+			return nil, nil, nil, nil, nil, fmt.Errorf("private data cannot be written by a cross-channel transaction")
+		}
+		if pvtSimResult != nil {
+			if err = e.distributePrivateData(chainID, txid, pvtSimResult); err != nil {
+				return nil, nil, nil, nil, nil, fmt.Errorf("failed to distribute private data of transaction %s: %s", txid, err)
+			}
 		}
 	}
 
-	return cdLedger, res, simResult, ccevent, nil
+	return cdLedger, res, simResult, ccevent, parts, nil
 }
 
 func (e *Endorser) getCDSFromLSCC(ctx context.Context, chainID string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, chaincodeID string, txsim ledger.TxSimulator) (*ccprovider.ChaincodeData, error) {
@@ -325,7 +373,13 @@ func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid str
 		return nil, fmt.Errorf("failed to marshal ChaincodeID - %s", err)
 	}
 
-	// 3) call the ESCC we've identified
+	// 3) endorse with the plugin of the ESCC, if there is one...
+	if e.pluginEndorser.hasPlugin(escc) {
+		endorserLogger.Debugf("info: endorsing with the plugin %s", escc)
+		return e.pluginEndorser.endorse(escc, signedProp, proposal, response, simRes, eventBytes, visibility, ccid)
+	}
+
+	// ...or else call the ESCC we've identified
 	// arguments:
 	// args[0] - function name (not used now)
 	// args[1] - serialized Header object
@@ -367,9 +421,18 @@ func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid str
 }
 
 // ProcessProposal process the Proposal
-func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (resp *pb.ProposalResponse, err error) {
 	endorserLogger.Debugf("Entry")
 	defer endorserLogger.Debugf("Exit")
+
+	startTime := time.Now()
+	proposalsReceived.Add(1)
+	// the labels of the metrics are known once the proposal is validated
+	var chainID, chaincodeName string
+	defer func() {
+		recordProposalMetrics(startTime, chainID, chaincodeName, resp, err)
+	}()
+
 	// at first, we check whether the message is valid
 	prop, hdr, hdrExt, err := validation.ValidateProposalMessage(signedProp)
 	if err != nil {
@@ -386,6 +449,9 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 	}
 
+	chainID = chdr.ChannelId
+	chaincodeName = hdrExt.ChaincodeId.Name
+
 	// block invocations to security-sensitive system chaincodes
 	if syscc.IsSysCCAndNotInvokableExternal(hdrExt.ChaincodeId.Name) {
 		endorserLogger.Errorf("Error: an attempt was made by %#v to invoke system chaincode %s",
@@ -394,8 +460,6 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 	}
 
-	chainID := chdr.ChannelId
-
 	// Check for uniqueness of prop.TxID with ledger
 	// Notice that ValidateProposalMessage has already verified
 	// that TxID is computed properly
@@ -411,7 +475,7 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		if lgr == nil {
 			return nil, fmt.Errorf("failure while looking up the ledger %s", chainID)
 		}
-		if _, err := lgr.GetTransactionByID(txid); err == nil {
+		if _, err := lgr.GetTransactionByID(txid); err == nil || err == blkstorage.ErrBlockPruned {
 			return nil, fmt.Errorf("Duplicate transaction found [%s]. Creator [%x]. [%s]", txid, shdr.Creator, err)
 		}
 
@@ -430,11 +494,43 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		// MSP of the peer instead by the call to ValidateProposalMessage above
 	}
 
+	// a proposal re-submitted, such as after a network timeout, gets the
+	// response to the original one rather than being simulated again
+	cachedResp, endProposal, err := e.responseCache.begin(ctx, txid, signedProp.ProposalBytes)
+	if err != nil {
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+	}
+	if cachedResp != nil {
+		endorserLogger.Debugf("returning the cached response for txid: %s", txid)
+		proposalCacheHits.With(chainID, chaincodeName).Add(1)
+		return cachedResp, nil
+	}
+	// only the responses to the proposals endorsed are cached
+	defer func() {
+		if err == nil && resp != nil && resp.Response != nil && resp.Response.Status < shim.ERRORTHRESHOLD {
+			endProposal(resp)
+		} else {
+			endProposal(nil)
+		}
+	}()
+
+	// wait for the proposals of the same client and of the same chaincode
+	// ahead of this one when their limits are reached. A throttled proposal
+	// gets a response rather than an error, so that the client sees its status
+	release, err := e.throttle.Acquire(ctx, chaincodeName, shdr.Creator)
+	if err != nil {
+		endorserLogger.Warningf("Throttled proposal for txid %s: %s", txid, err)
+		proposalsThrottled.With(chainID, chaincodeName).Add(1)
+		return &pb.ProposalResponse{Response: &pb.Response{Status: int32(common.Status_SERVICE_UNAVAILABLE), Message: err.Error()}}, nil
+	}
+	defer release()
+
 	// obtaining once the tx simulator for this proposal. This will be nil
 	// for chainless proposals
 	// Also obtain a history query executor for history queries, since tx simulator does not cover history
 	var txsim ledger.TxSimulator
 	var historyQueryExecutor ledger.HistoryQueryExecutor
+	var crossChannelSims *chaincode.CrossChannelSimulators
 	if chainID != "" {
 		if txsim, err = e.getTxSimulator(chainID); err != nil {
 			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
@@ -448,6 +544,14 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		ctx = context.WithValue(ctx, chaincode.HistoryQueryExecutorKey, historyQueryExecutor)
 
 		defer txsim.Done()
+
+		// the writes of the chaincodes invoked on the other channels are kept
+		// only if XSCC, which decides the cross-channel transactions, is enabled
+		if syscc.IsSysCCEnabled(crosschannel.Namespace) {
+			crossChannelSims = chaincode.NewCrossChannelSimulators(chainID, txsim)
+			ctx = context.WithValue(ctx, chaincode.CrossChannelKey, crossChannelSims)
+			defer crossChannelSims.Done()
+		}
 	}
 	//this could be a request to a chainless SysCC
 
@@ -457,7 +561,7 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	//       to validate the supplied action before endorsing it
 
 	//1 -- simulate
-	cd, res, simulationResult, ccevent, err := e.simulateProposal(ctx, chainID, txid, signedProp, prop, hdrExt.ChaincodeId, txsim)
+	cd, res, simulationResult, ccevent, crossChannelParts, err := e.simulateProposal(ctx, chainID, txid, signedProp, prop, hdrExt.ChaincodeId, txsim, crossChannelSims)
 	if err != nil {
 		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 	}
@@ -498,6 +602,12 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 				return pResp, &chaincodeError{res.Status, res.Message}
 			}
 		}
+		if len(crossChannelParts) > 0 && pResp.Response.Status < shim.ERRORTHRESHOLD {
+			pResp.CrossChannelResponses, err = e.endorseCrossChannelParts(ctx, txid, signedProp, prop, crossChannelParts)
+			if err != nil {
+				return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+			}
+		}
 	}
 
 	// Set the proposal response payload - it
@@ -47,6 +47,7 @@ import (
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	pbutils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
@@ -724,6 +725,62 @@ func TestInvokeSccFail(t *testing.T) {
 	}
 }
 
+// TestThrottledProposal makes sure that a proposal beyond the limits of its
+// client gets a response with the SERVICE_UNAVAILABLE status
+func TestThrottledProposal(t *testing.T) {
+	chainID := util.GetTestChainID()
+	e := endorserServer.(*Endorser)
+	e.throttle = NewThrottle(ThrottleConfig{ClientConcurrency: 1})
+	defer func() { e.throttle = nil }()
+
+	creator, err := signer.Serialize()
+	assert.NoError(t, err)
+	release, err := e.throttle.Acquire(context.Background(), "qscc", creator)
+	assert.NoError(t, err)
+
+	spec := &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "qscc"}, Input: &pb.ChaincodeInput{Args: util.ToChaincodeArgs("GetChainInfo", chainID)}}
+	_, resp, _, _, err := invoke(chainID, spec)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(common.Status_SERVICE_UNAVAILABLE), resp.Response.Status)
+	assert.Contains(t, resp.Response.Message, "Too many proposals of the client being processed")
+
+	release()
+	_, resp, _, _, err = invoke(chainID, spec)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(200), resp.Response.Status)
+}
+
+// TestCachedProposalResponse makes sure that a proposal re-submitted gets the
+// cached response, and that a different proposal reusing its txid is rejected
+func TestCachedProposalResponse(t *testing.T) {
+	chainID := util.GetTestChainID()
+	e := endorserServer.(*Endorser)
+	e.responseCache = newResponseCache(time.Minute, 0)
+	defer func() { e.responseCache = nil }()
+
+	creator, err := signer.Serialize()
+	assert.NoError(t, err)
+	spec := &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "qscc"}, Input: &pb.ChaincodeInput{Args: util.ToChaincodeArgs("GetChainInfo", chainID)}}
+	prop, txID, err := getInvokeProposal(&pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}, chainID, creator)
+	assert.NoError(t, err)
+	signedProp, err := getSignedProposal(prop, signer)
+	assert.NoError(t, err)
+
+	resp, err := endorserServer.ProcessProposal(context.Background(), signedProp)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(200), resp.Response.Status)
+	cachedResp, err := endorserServer.ProcessProposal(context.Background(), signedProp)
+	assert.NoError(t, err)
+	assert.True(t, cachedResp == resp)
+
+	nonce, err := pbutils.GetNonce(prop)
+	assert.NoError(t, err)
+	spec.Input = &pb.ChaincodeInput{Args: util.ToChaincodeArgs("GetBlockByNumber", chainID, "0")}
+	_, err = invokeWithOverride(txID, chainID, spec, nonce)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "A different proposal with txid "+txID+" has already been received")
+}
+
 func newTempDir() string {
 	tempDir, err := ioutil.TempDir("", "fabric-")
 	if err != nil {
@@ -744,7 +801,9 @@ func TestMain(m *testing.M) {
 		return
 	}
 
-	endorserServer = NewEndorserServer()
+	endorserServer = NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+		return nil
+	}, nil, nil)
 
 	// setup the MSP manager so that we can sign/verify
 	err = msptesttools.LoadMSPSetupForTesting()
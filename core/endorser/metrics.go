@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+var (
+	proposalsReceived = metrics.NewCounter(metrics.CounterOpts{
+		Namespace: "endorser",
+		Name:      "proposals_received",
+		Help:      "The number of proposals received.",
+	})
+	proposalsProcessed = metrics.NewCounter(metrics.CounterOpts{
+		Namespace:  "endorser",
+		Name:       "proposals_processed",
+		Help:       "The number of proposals processed, by status of the response.",
+		LabelNames: []string{"channel", "chaincode", "status"},
+	})
+	proposalsThrottled = metrics.NewCounter(metrics.CounterOpts{
+		Namespace:  "endorser",
+		Name:       "proposals_throttled",
+		Help:       "The number of proposals rejected as the limits of their client or chaincode were reached.",
+		LabelNames: []string{"channel", "chaincode"},
+	})
+	proposalCacheHits = metrics.NewCounter(metrics.CounterOpts{
+		Namespace:  "endorser",
+		Name:       "proposal_cache_hits",
+		Help:       "The number of re-submitted proposals answered with the cached response to the original one.",
+		LabelNames: []string{"channel", "chaincode"},
+	})
+	proposalDuration = metrics.NewHistogram(metrics.HistogramOpts{
+		Namespace:  "endorser",
+		Name:       "proposal_duration",
+		Help:       "The time to process a proposal, in seconds.",
+		LabelNames: []string{"channel", "chaincode", "success"},
+	})
+)
+
+// recordProposalMetrics records the outcome of a proposal. The status of a proposal
+// which failed without a response is reported as 500
+func recordProposalMetrics(startTime time.Time, channel, chaincode string, resp *pb.ProposalResponse, err error) {
+	status := int32(500)
+	if resp != nil && resp.Response != nil {
+		status = resp.Response.Status
+	}
+	proposalsProcessed.With(channel, chaincode, strconv.Itoa(int(status))).Add(1)
+	proposalDuration.With(channel, chaincode, strconv.FormatBool(err == nil)).Observe(time.Since(startTime).Seconds())
+}
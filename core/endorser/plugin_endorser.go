@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+// PluginMapper maps the ESCC names of the chaincodes to endorsement plugins
+type PluginMapper interface {
+	// EndorsementPluginFactoryByName returns the factory of the plugin with
+	// the given name, or nil if the name does not map to a plugin
+	EndorsementPluginFactoryByName(name string) endorsement.PluginFactory
+}
+
+// pluginEndorser endorses proposals in-process with the endorsement plugins
+// of the peer. The plugin instances are created the first time they are used
+type pluginEndorser struct {
+	sync.Mutex
+	pluginMapper PluginMapper
+	plugins      map[string]endorsement.Plugin
+}
+
+func newPluginEndorser(pluginMapper PluginMapper) *pluginEndorser {
+	return &pluginEndorser{
+		pluginMapper: pluginMapper,
+		plugins:      make(map[string]endorsement.Plugin),
+	}
+}
+
+// hasPlugin returns whether the ESCC name maps to an endorsement plugin
+func (pe *pluginEndorser) hasPlugin(escc string) bool {
+	return pe != nil && pe.pluginMapper != nil && pe.pluginMapper.EndorsementPluginFactoryByName(escc) != nil
+}
+
+// endorse builds the proposal response payload of the simulation results and
+// has it endorsed by the plugin of the ESCC name
+func (pe *pluginEndorser) endorse(escc string, signedProp *pb.SignedProposal, proposal *pb.Proposal, response *pb.Response, simRes []byte, events []byte, visibility []byte, ccid *pb.ChaincodeID) (*pb.ProposalResponse, error) {
+	// Status code < shim.ERRORTHRESHOLD can be endorsed
+	if response.Status >= shim.ERRORTHRESHOLD {
+		return &pb.ProposalResponse{Response: &pb.Response{
+			Status:  shim.ERROR,
+			Message: fmt.Sprintf("Status code less than %d will be endorsed, received status code: %d", shim.ERRORTHRESHOLD, response.Status),
+		}}, nil
+	}
+
+	plugin, err := pe.getOrCreatePlugin(escc)
+	if err != nil {
+		return nil, fmt.Errorf("failed initializing endorsement plugin %s - %s", escc, err)
+	}
+
+	hdr, err := putils.GetHeader(proposal.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	// obtain the proposal hash given proposal header, payload and the requested visibility
+	pHashBytes, err := putils.GetProposalHash1(hdr, proposal.Payload, visibility)
+	if err != nil {
+		return nil, fmt.Errorf("could not compute proposal hash - %s", err)
+	}
+
+	prpBytes, err := putils.GetBytesProposalResponsePayload(pHashBytes, response, simRes, events, ccid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the proposal response payload - %s", err)
+	}
+
+	endorsed, prpBytes, err := plugin.Endorse(prpBytes, signedProp)
+	if err != nil {
+		return nil, fmt.Errorf("endorsement plugin %s failed - %s", escc, err)
+	}
+
+	return &pb.ProposalResponse{
+		Version:     1,
+		Endorsement: endorsed,
+		Payload:     prpBytes,
+		Response:    &pb.Response{Status: 200, Message: "OK"},
+	}, nil
+}
+
+func (pe *pluginEndorser) getOrCreatePlugin(escc string) (endorsement.Plugin, error) {
+	pe.Lock()
+	defer pe.Unlock()
+
+	if plugin, exists := pe.plugins[escc]; exists {
+		return plugin, nil
+	}
+
+	plugin := pe.pluginMapper.EndorsementPluginFactoryByName(escc).New()
+	if err := plugin.Init(&localSigningIdentityFetcher{}); err != nil {
+		return nil, err
+	}
+	pe.plugins[escc] = plugin
+	return plugin, nil
+}
+
+// localSigningIdentityFetcher endorses with the default signing identity of the local MSP
+type localSigningIdentityFetcher struct {
+}
+
+func (*localSigningIdentityFetcher) SigningIdentityForRequest(*pb.SignedProposal) (endorsement.SigningIdentity, error) {
+	signer, err := mgmt.GetLocalMSP().GetDefaultSigningIdentity()
+	if err != nil {
+		return nil, fmt.Errorf("could not obtain the default signing identity - %s", err)
+	}
+	return signer, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/util"
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockEndorsementPlugin struct {
+	inits int
+	err   error
+}
+
+func (p *mockEndorsementPlugin) Endorse(payload []byte, sp *pb.SignedProposal) (*pb.Endorsement, []byte, error) {
+	if p.err != nil {
+		return nil, nil, p.err
+	}
+	return &pb.Endorsement{Endorser: []byte("endorser"), Signature: []byte("signature")}, payload, nil
+}
+
+func (p *mockEndorsementPlugin) Init(dependencies ...endorsement.Dependency) error {
+	p.inits++
+	for _, dep := range dependencies {
+		if _, isFetcher := dep.(endorsement.SigningIdentityFetcher); isFetcher {
+			return nil
+		}
+	}
+	return errors.New("no SigningIdentityFetcher")
+}
+
+type mockEndorsementPluginFactory struct {
+	plugin *mockEndorsementPlugin
+}
+
+func (f *mockEndorsementPluginFactory) New() endorsement.Plugin {
+	return f.plugin
+}
+
+type mockEndorsementPluginMapper map[string]endorsement.PluginFactory
+
+func (m mockEndorsementPluginMapper) EndorsementPluginFactoryByName(name string) endorsement.PluginFactory {
+	return m[name]
+}
+
+func TestPluginEndorser(t *testing.T) {
+	plugin := &mockEndorsementPlugin{}
+	pe := newPluginEndorser(mockEndorsementPluginMapper{"myescc": &mockEndorsementPluginFactory{plugin: plugin}})
+	assert.True(t, pe.hasPlugin("myescc"))
+	assert.False(t, pe.hasPlugin("escc"))
+	assert.False(t, newPluginEndorser(nil).hasPlugin("myescc"))
+
+	ccid := &pb.ChaincodeID{Name: "mycc", Version: "1.0"}
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: ccid}}
+	prop, _, err := putils.CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), cis, []byte("creator"))
+	assert.NoError(t, err)
+
+	response := &pb.Response{Status: 200, Payload: []byte("result")}
+	presp, err := pe.endorse("myescc", &pb.SignedProposal{}, prop, response, []byte("rwset"), nil, nil, ccid)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(200), presp.Response.Status)
+	assert.Equal(t, []byte("endorser"), presp.Endorsement.Endorser)
+	prp, err := putils.GetProposalResponsePayload(presp.Payload)
+	assert.NoError(t, err)
+	action, err := putils.GetChaincodeAction(prp.Extension)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("rwset"), action.Results)
+	assert.Equal(t, "mycc", action.ChaincodeId.Name)
+
+	// the error responses of the chaincode are not endorsed
+	presp, err = pe.endorse("myescc", &pb.SignedProposal{}, prop, &pb.Response{Status: 400}, nil, nil, nil, ccid)
+	assert.NoError(t, err)
+	assert.Nil(t, presp.Endorsement)
+	assert.Equal(t, int32(500), presp.Response.Status)
+
+	plugin.err = errors.New("no key")
+	_, err = pe.endorse("myescc", &pb.SignedProposal{}, prop, response, []byte("rwset"), nil, nil, ccid)
+	assert.Error(t, err)
+
+	// the plugin is initialized once
+	assert.Equal(t, 1, plugin.inits)
+}
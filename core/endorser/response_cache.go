@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/util"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+)
+
+// responseCache keeps the responses to the proposals endorsed, keyed by
+// transaction ID, so that a proposal re-submitted by a client, such as after
+// a network timeout, gets the response to the original one rather than being
+// simulated again. The responses are kept for the window of time following
+// the reception of their proposals, and the oldest ones are dropped first
+// once the cache holds its maximum number of responses
+type responseCache struct {
+	lock    sync.Mutex
+	window  time.Duration
+	maxSize int
+	entries map[string]*cacheEntry
+	// order holds the entries in the order their proposals were received
+	order *list.List
+	now   func() time.Time
+}
+
+// cacheEntry is the response to a proposal, which is being processed until
+// done is closed
+type cacheEntry struct {
+	txID         string
+	proposalHash []byte
+	expiry       time.Time
+	done         chan struct{}
+	// resp is nil if the proposal was not endorsed
+	resp *pb.ProposalResponse
+	elem *list.Element
+}
+
+// newResponseCache returns a cache keeping the responses for the given
+// window, at most maxSize of them if it is not 0. The cache is disabled,
+// and nil is returned, if the window is 0
+func newResponseCache(window time.Duration, maxSize int) *responseCache {
+	if window <= 0 {
+		return nil
+	}
+	return &responseCache{
+		window:  window,
+		maxSize: maxSize,
+		entries: make(map[string]*cacheEntry),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// newResponseCacheFromConfig returns the cache configured by the
+// peer.proposalResponseCache section of the configuration
+func newResponseCacheFromConfig() *responseCache {
+	return newResponseCache(viper.GetDuration("peer.proposalResponseCache.window"), viper.GetInt("peer.proposalResponseCache.maxSize"))
+}
+
+// begin returns the cached response to the proposal of the transaction if
+// the same proposal was received before, waiting for it to be processed if
+// it still is. Otherwise, the proposal is recorded as being processed, and
+// the returned function must be called with its response once it is
+// processed, or nil if it was not endorsed, in which case the response is
+// not cached. An error is returned if a different proposal with the same
+// transaction ID was received before
+func (c *responseCache) begin(ctx context.Context, txID string, proposalBytes []byte) (*pb.ProposalResponse, func(*pb.ProposalResponse), error) {
+	if c == nil {
+		return nil, func(*pb.ProposalResponse) {}, nil
+	}
+
+	proposalHash := util.ComputeSHA256(proposalBytes)
+	for {
+		c.lock.Lock()
+		c.evict()
+		entry, ok := c.entries[txID]
+		if !ok {
+			entry = &cacheEntry{
+				txID:         txID,
+				proposalHash: proposalHash,
+				expiry:       c.now().Add(c.window),
+				done:         make(chan struct{}),
+			}
+			entry.elem = c.order.PushBack(entry)
+			c.entries[txID] = entry
+			c.lock.Unlock()
+			return nil, func(resp *pb.ProposalResponse) { c.end(entry, resp) }, nil
+		}
+		c.lock.Unlock()
+
+		if !bytes.Equal(entry.proposalHash, proposalHash) {
+			return nil, nil, fmt.Errorf("A different proposal with txid %s has already been received", txID)
+		}
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		if entry.resp != nil {
+			return entry.resp, nil, nil
+		}
+		// the original proposal was not endorsed and its entry is removed,
+		// so the re-submitted one is processed
+	}
+}
+
+// end records the response to the proposal of the entry
+func (c *responseCache) end(entry *cacheEntry, resp *pb.ProposalResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if resp != nil {
+		entry.resp = resp
+	} else if c.entries[entry.txID] == entry {
+		c.remove(entry)
+	}
+	close(entry.done)
+}
+
+// evict drops the expired responses and the oldest ones beyond the maximum size
+func (c *responseCache) evict() {
+	now := c.now()
+	for elem := c.order.Front(); elem != nil; elem = c.order.Front() {
+		entry := elem.Value.(*cacheEntry)
+		if now.Before(entry.expiry) && (c.maxSize == 0 || c.order.Len() < c.maxSize) {
+			return
+		}
+		c.remove(entry)
+	}
+}
+
+func (c *responseCache) remove(entry *cacheEntry) {
+	delete(c.entries, entry.txID)
+	c.order.Remove(entry.elem)
+}
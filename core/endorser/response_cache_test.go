@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestResponseCache(t *testing.T) {
+	cache := newResponseCache(time.Minute, 0)
+	resp := &pb.ProposalResponse{Response: &pb.Response{Status: 200}}
+
+	cachedResp, end, err := cache.begin(context.Background(), "tx1", []byte("proposal1"))
+	assert.NoError(t, err)
+	assert.Nil(t, cachedResp)
+	end(resp)
+
+	// the same proposal gets the cached response, a different one is rejected
+	cachedResp, end, err = cache.begin(context.Background(), "tx1", []byte("proposal1"))
+	assert.NoError(t, err)
+	assert.True(t, cachedResp == resp)
+	assert.Nil(t, end)
+	_, _, err = cache.begin(context.Background(), "tx1", []byte("proposal2"))
+	assert.EqualError(t, err, "A different proposal with txid tx1 has already been received")
+
+	// the response to a proposal which was not endorsed is not cached
+	_, end, err = cache.begin(context.Background(), "tx2", []byte("proposal2"))
+	assert.NoError(t, err)
+	end(nil)
+	cachedResp, end, err = cache.begin(context.Background(), "tx2", []byte("proposal2"))
+	assert.NoError(t, err)
+	assert.Nil(t, cachedResp)
+	assert.NotNil(t, end)
+}
+
+func TestResponseCacheDisabled(t *testing.T) {
+	cache := newResponseCache(0, 10)
+	assert.Nil(t, cache)
+	for i := 0; i < 2; i++ {
+		cachedResp, end, err := cache.begin(context.Background(), "tx1", []byte("proposal1"))
+		assert.NoError(t, err)
+		assert.Nil(t, cachedResp)
+		end(&pb.ProposalResponse{})
+	}
+}
+
+func TestResponseCacheProposalInProgress(t *testing.T) {
+	cache := newResponseCache(time.Minute, 0)
+	resp := &pb.ProposalResponse{Response: &pb.Response{Status: 200}}
+
+	type result struct {
+		resp *pb.ProposalResponse
+		end  func(*pb.ProposalResponse)
+		err  error
+	}
+	resubmit := func(ctx context.Context, txID string) chan result {
+		results := make(chan result, 1)
+		go func() {
+			cachedResp, end, err := cache.begin(ctx, txID, []byte("proposal of "+txID))
+			results <- result{cachedResp, end, err}
+		}()
+		return results
+	}
+
+	// a proposal re-submitted while the original one is processed waits for its response
+	_, end, err := cache.begin(context.Background(), "tx1", []byte("proposal of tx1"))
+	assert.NoError(t, err)
+	results := resubmit(context.Background(), "tx1")
+	time.Sleep(20 * time.Millisecond)
+	end(resp)
+	r := <-results
+	assert.NoError(t, r.err)
+	assert.True(t, r.resp == resp)
+
+	// or is processed if the original one is not endorsed
+	_, end, err = cache.begin(context.Background(), "tx2", []byte("proposal of tx2"))
+	assert.NoError(t, err)
+	results = resubmit(context.Background(), "tx2")
+	time.Sleep(20 * time.Millisecond)
+	end(nil)
+	r = <-results
+	assert.NoError(t, r.err)
+	assert.Nil(t, r.resp)
+	assert.NotNil(t, r.end)
+
+	// the wait ends with the context
+	ctx, cancel := context.WithCancel(context.Background())
+	results = resubmit(ctx, "tx2")
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, (<-results).err)
+}
+
+func TestResponseCacheEviction(t *testing.T) {
+	now := time.Now()
+	cache := newResponseCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+	resp := &pb.ProposalResponse{Response: &pb.Response{Status: 200}}
+
+	for _, txID := range []string{"tx1", "tx2"} {
+		_, end, err := cache.begin(context.Background(), txID, []byte(txID))
+		assert.NoError(t, err)
+		end(resp)
+	}
+	// the oldest response is dropped once the cache is full
+	_, end, err := cache.begin(context.Background(), "tx3", []byte("tx3"))
+	assert.NoError(t, err)
+	end(resp)
+	assert.Len(t, cache.entries, 2)
+	assert.NotContains(t, cache.entries, "tx1")
+	cachedResp, _, err := cache.begin(context.Background(), "tx3", []byte("tx3"))
+	assert.NoError(t, err)
+	assert.NotNil(t, cachedResp)
+
+	// the responses expire with the window
+	now = now.Add(time.Minute)
+	cachedResp, end, err = cache.begin(context.Background(), "tx3", []byte("other proposal"))
+	assert.NoError(t, err)
+	assert.Nil(t, cachedResp)
+	assert.NotNil(t, end)
+	assert.Len(t, cache.entries, 1)
+	assert.Equal(t, 1, cache.order.Len())
+}
@@ -0,0 +1,272 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+)
+
+// ThrottleConfig limits the number of proposals which the endorser processes
+// concurrently. A limit of 0 means no limit
+type ThrottleConfig struct {
+	// ChaincodeConcurrency is the maximum number of proposals processed
+	// concurrently for each chaincode
+	ChaincodeConcurrency int
+	// Chaincodes overrides ChaincodeConcurrency for the chaincodes it names,
+	// which are in lower case
+	Chaincodes map[string]int
+	// ClientConcurrency is the maximum number of proposals processed
+	// concurrently for each client identity
+	ClientConcurrency int
+	// QueueTimeout is how long a proposal waits for the proposals ahead of it
+	// to complete before it is throttled
+	QueueTimeout time.Duration
+}
+
+func (c ThrottleConfig) chaincodeLimit(chaincodeName string) int {
+	if limit, ok := c.Chaincodes[strings.ToLower(chaincodeName)]; ok {
+		return limit
+	}
+	return c.ChaincodeConcurrency
+}
+
+func (c ThrottleConfig) clientLimit(string) int {
+	return c.ClientConcurrency
+}
+
+// LoadThrottleConfig reads the limits from the peer.limits.endorsement
+// section of the configuration. The chaincode names are matched ignoring the
+// case as the keys of the configuration are case insensitive
+func LoadThrottleConfig(v *viper.Viper) (ThrottleConfig, error) {
+	config := ThrottleConfig{
+		ChaincodeConcurrency: v.GetInt("peer.limits.endorsement.chaincodeConcurrency"),
+		Chaincodes:           make(map[string]int),
+		ClientConcurrency:    v.GetInt("peer.limits.endorsement.clientConcurrency"),
+		QueueTimeout:         v.GetDuration("peer.limits.endorsement.queueTimeout"),
+	}
+	if config.ChaincodeConcurrency < 0 || config.ClientConcurrency < 0 || config.QueueTimeout < 0 {
+		return ThrottleConfig{}, fmt.Errorf("Invalid endorsement limits %+v, they must not be negative", config)
+	}
+	for name, value := range v.GetStringMap("peer.limits.endorsement.chaincodes") {
+		limit, err := cast.ToIntE(value)
+		if err != nil || limit < 0 {
+			return ThrottleConfig{}, fmt.Errorf("Invalid endorsement limit %v of chaincode %s", value, name)
+		}
+		config.Chaincodes[strings.ToLower(name)] = limit
+	}
+	return config, nil
+}
+
+// ThrottleConfigFromFile reads the limits from the peer.limits.endorsement
+// section of a configuration file, which are overridden by the environment
+// variables prefixed with CORE as at the start of the peer
+func ThrottleConfigFromFile(configFile string) (ThrottleConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	v.SetEnvPrefix("core")
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	if err := v.ReadInConfig(); err != nil {
+		return ThrottleConfig{}, fmt.Errorf("Could not read configuration file %s, err %s", configFile, err)
+	}
+	return LoadThrottleConfig(v)
+}
+
+// Throttle limits the number of proposals processed concurrently for each
+// chaincode and for each client identity, so that a client or a chaincode
+// flooding the peer does not starve the other ones. The proposals beyond the
+// limits wait for a slot until the queue timeout. The limits may be updated
+// while proposals are processed
+type Throttle struct {
+	lock       sync.Mutex
+	config     ThrottleConfig
+	chaincodes map[string]*slots
+	clients    map[string]*slots
+}
+
+var errQueueTimeout = errors.New("queue timeout expired")
+
+// slots counts the proposals processed for a chaincode or a client
+type slots struct {
+	inUse int
+	// released is closed when a slot is released or the limits are updated
+	released chan struct{}
+}
+
+// NewThrottle returns a Throttle enforcing the given limits
+func NewThrottle(config ThrottleConfig) *Throttle {
+	return &Throttle{
+		config:     config,
+		chaincodes: make(map[string]*slots),
+		clients:    make(map[string]*slots),
+	}
+}
+
+// Config returns the limits enforced by the throttle
+func (t *Throttle) Config() ThrottleConfig {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.config
+}
+
+// Update replaces the limits of the throttle. The proposals being processed
+// are accounted against the new limits, and the waiting ones are reconsidered
+func (t *Throttle) Update(config ThrottleConfig) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.config = config
+	for _, pool := range []map[string]*slots{t.chaincodes, t.clients} {
+		for _, s := range pool {
+			close(s.released)
+			s.released = make(chan struct{})
+		}
+	}
+}
+
+// Acquire waits for a slot of the chaincode and a slot of the client to be
+// available. The slot of the client is taken first, so that the proposals of
+// a client exceeding its limit do not hold slots of the chaincode. The
+// returned function releases the slots, it must be called once the proposal
+// is processed. An error is returned if the slots are not available within
+// the queue timeout or the context is done
+func (t *Throttle) Acquire(ctx context.Context, chaincodeName string, client []byte) (func(), error) {
+	if t == nil {
+		return func() {}, nil
+	}
+
+	queueTimeout := t.Config().QueueTimeout
+	timeout := time.NewTimer(queueTimeout)
+	defer timeout.Stop()
+
+	clientKey := string(client)
+	if err := t.acquireSlot(ctx, t.clients, clientKey, ThrottleConfig.clientLimit, timeout.C); err != nil {
+		return nil, throttledError("the client", err, queueTimeout)
+	}
+	if err := t.acquireSlot(ctx, t.chaincodes, chaincodeName, ThrottleConfig.chaincodeLimit, timeout.C); err != nil {
+		t.releaseSlot(t.clients, clientKey)
+		return nil, throttledError("chaincode "+chaincodeName, err, queueTimeout)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.releaseSlot(t.chaincodes, chaincodeName)
+			t.releaseSlot(t.clients, clientKey)
+		})
+	}, nil
+}
+
+func (t *Throttle) acquireSlot(ctx context.Context, pool map[string]*slots, key string, limit func(ThrottleConfig, string) int, timeout <-chan time.Time) error {
+	for {
+		t.lock.Lock()
+		s, ok := pool[key]
+		if !ok {
+			s = &slots{released: make(chan struct{})}
+			pool[key] = s
+		}
+		// the proposals are counted even without a limit, in case one is set later
+		if max := limit(t.config, key); max == 0 || s.inUse < max {
+			s.inUse++
+			t.lock.Unlock()
+			return nil
+		}
+		released := s.released
+		t.lock.Unlock()
+
+		select {
+		case <-released:
+		case <-timeout:
+			return errQueueTimeout
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func throttledError(owner string, err error, queueTimeout time.Duration) error {
+	if err == errQueueTimeout {
+		return fmt.Errorf("Too many proposals of %s being processed, none completed within the queue timeout of %s", owner, queueTimeout)
+	}
+	return fmt.Errorf("Too many proposals of %s being processed: %s", owner, err)
+}
+
+func (t *Throttle) releaseSlot(pool map[string]*slots, key string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	s := pool[key]
+	s.inUse--
+	close(s.released)
+	if s.inUse == 0 {
+		delete(pool, key)
+	} else {
+		s.released = make(chan struct{})
+	}
+}
+
+// WatchConfigFile reloads the limits of the throttle from the configuration
+// file whenever the files of its directory change until the returned function
+// is called. The limits are left unchanged when a reload fails, such as while
+// the file is being written, and are reloaded on the next change
+func (t *Throttle) WatchConfigFile(configFile string) (func(), error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("Could not watch configuration file %s, err %s", configFile, err)
+	}
+	// the directory is watched as editors and configuration managers replace the file
+	if err := watcher.Add(filepath.Dir(configFile)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("Could not watch configuration file %s, err %s", configFile, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				endorserLogger.Debugf("Configuration directory of %s changed: %s", configFile, event)
+				config, err := ThrottleConfigFromFile(configFile)
+				if err != nil {
+					endorserLogger.Warningf("Keeping the previous endorsement limits: %s", err)
+					continue
+				}
+				if !reflect.DeepEqual(config, t.Config()) {
+					t.Update(config)
+					endorserLogger.Infof("Updated the endorsement limits to %+v", config)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				endorserLogger.Warningf("Error watching configuration file %s: %s", configFile, err)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			watcher.Close()
+		})
+	}, nil
+}
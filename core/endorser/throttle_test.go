@@ -0,0 +1,209 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestThrottleChaincodeConcurrency(t *testing.T) {
+	throttle := NewThrottle(ThrottleConfig{
+		ChaincodeConcurrency: 2,
+		Chaincodes:           map[string]int{"mycc": 1, "unlimitedcc": 0},
+		QueueTimeout:         50 * time.Millisecond,
+	})
+
+	release1, err := throttle.Acquire(context.Background(), "cc", []byte("client1"))
+	assert.NoError(t, err)
+	release2, err := throttle.Acquire(context.Background(), "cc", []byte("client2"))
+	assert.NoError(t, err)
+	_, err = throttle.Acquire(context.Background(), "cc", []byte("client3"))
+	assert.EqualError(t, err, "Too many proposals of chaincode cc being processed, none completed within the queue timeout of 50ms")
+
+	// the limit of a chaincode is overridden ignoring the case of its name
+	releaseMyCC, err := throttle.Acquire(context.Background(), "MyCC", []byte("client1"))
+	assert.NoError(t, err)
+	_, err = throttle.Acquire(context.Background(), "MyCC", []byte("client2"))
+	assert.Error(t, err)
+	releaseMyCC()
+	for i := 0; i < 5; i++ {
+		_, err = throttle.Acquire(context.Background(), "unlimitedcc", []byte("client1"))
+		assert.NoError(t, err)
+	}
+
+	// a slot released is taken by a waiting proposal
+	throttle.Update(ThrottleConfig{ChaincodeConcurrency: 2, QueueTimeout: time.Minute})
+	acquired := make(chan error)
+	go func() {
+		_, err := throttle.Acquire(context.Background(), "cc", []byte("client3"))
+		acquired <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	release1()
+	// releasing more than once has no effect
+	release1()
+	assert.NoError(t, <-acquired)
+	throttle.Update(ThrottleConfig{ChaincodeConcurrency: 2})
+	_, err = throttle.Acquire(context.Background(), "cc", []byte("client4"))
+	assert.Error(t, err)
+	release2()
+	_, err = throttle.Acquire(context.Background(), "cc", []byte("client4"))
+	assert.NoError(t, err)
+}
+
+func TestThrottleClientConcurrency(t *testing.T) {
+	throttle := NewThrottle(ThrottleConfig{ClientConcurrency: 1})
+
+	release, err := throttle.Acquire(context.Background(), "cc1", []byte("client1"))
+	assert.NoError(t, err)
+	// with no queue timeout, the proposals beyond the limits are rejected at once
+	_, err = throttle.Acquire(context.Background(), "cc2", []byte("client1"))
+	assert.EqualError(t, err, "Too many proposals of the client being processed, none completed within the queue timeout of 0s")
+	_, err = throttle.Acquire(context.Background(), "cc1", []byte("client2"))
+	assert.NoError(t, err)
+	release()
+	_, err = throttle.Acquire(context.Background(), "cc2", []byte("client1"))
+	assert.NoError(t, err)
+}
+
+func TestThrottleClientHoldsNoChaincodeSlot(t *testing.T) {
+	throttle := NewThrottle(ThrottleConfig{ChaincodeConcurrency: 2, ClientConcurrency: 1})
+
+	_, err := throttle.Acquire(context.Background(), "cc", []byte("client1"))
+	assert.NoError(t, err)
+	// the proposals of a client beyond its limit do not take the slots of the chaincode
+	for i := 0; i < 3; i++ {
+		_, err = throttle.Acquire(context.Background(), "cc", []byte("client1"))
+		assert.Error(t, err)
+	}
+	_, err = throttle.Acquire(context.Background(), "cc", []byte("client2"))
+	assert.NoError(t, err)
+	_, err = throttle.Acquire(context.Background(), "cc", []byte("client3"))
+	assert.Error(t, err)
+}
+
+func TestThrottleUpdate(t *testing.T) {
+	throttle := NewThrottle(ThrottleConfig{ChaincodeConcurrency: 1, QueueTimeout: time.Minute})
+	_, err := throttle.Acquire(context.Background(), "cc", []byte("client1"))
+	assert.NoError(t, err)
+
+	// the waiting proposals are reconsidered when the limits are raised
+	acquired := make(chan error)
+	go func() {
+		_, err := throttle.Acquire(context.Background(), "cc", []byte("client2"))
+		acquired <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	throttle.Update(ThrottleConfig{ChaincodeConcurrency: 2, QueueTimeout: time.Minute})
+	assert.NoError(t, <-acquired)
+	assert.Equal(t, 2, throttle.Config().ChaincodeConcurrency)
+
+	// the proposals being processed count against the new limits
+	throttle.Update(ThrottleConfig{ChaincodeConcurrency: 2})
+	_, err = throttle.Acquire(context.Background(), "cc", []byte("client3"))
+	assert.Error(t, err)
+}
+
+func TestThrottleContextDone(t *testing.T) {
+	throttle := NewThrottle(ThrottleConfig{ChaincodeConcurrency: 1, QueueTimeout: time.Minute})
+	_, err := throttle.Acquire(context.Background(), "cc", []byte("client1"))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = throttle.Acquire(ctx, "cc", []byte("client2"))
+	assert.EqualError(t, err, "Too many proposals of chaincode cc being processed: context canceled")
+	// the slot of the client is released when the chaincode has none
+	assert.Empty(t, throttle.clients["client2"])
+
+	var noThrottle *Throttle
+	release, err := noThrottle.Acquire(ctx, "cc", []byte("client1"))
+	assert.NoError(t, err)
+	release()
+}
+
+func TestLoadThrottleConfig(t *testing.T) {
+	v := viper.New()
+	v.Set("peer.limits.endorsement.chaincodeConcurrency", 10)
+	v.Set("peer.limits.endorsement.clientConcurrency", 2)
+	v.Set("peer.limits.endorsement.queueTimeout", "3s")
+	v.Set("peer.limits.endorsement.chaincodes", map[string]interface{}{"MyCC": 5, "othercc": "0"})
+	config, err := LoadThrottleConfig(v)
+	assert.NoError(t, err)
+	assert.Equal(t, ThrottleConfig{
+		ChaincodeConcurrency: 10,
+		Chaincodes:           map[string]int{"mycc": 5, "othercc": 0},
+		ClientConcurrency:    2,
+		QueueTimeout:         3 * time.Second,
+	}, config)
+
+	config, err = LoadThrottleConfig(viper.New())
+	assert.NoError(t, err)
+	assert.Equal(t, ThrottleConfig{Chaincodes: map[string]int{}}, config)
+
+	v.Set("peer.limits.endorsement.chaincodes", map[string]interface{}{"mycc": "many"})
+	_, err = LoadThrottleConfig(v)
+	assert.Error(t, err)
+	v.Set("peer.limits.endorsement.chaincodes", nil)
+	v.Set("peer.limits.endorsement.clientConcurrency", -1)
+	_, err = LoadThrottleConfig(v)
+	assert.Error(t, err)
+}
+
+func TestThrottleWatchConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "throttle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "core.yaml")
+	writeLimits := func(limits string) {
+		err := ioutil.WriteFile(configFile, []byte("peer:\n  limits:\n    endorsement:\n"+limits), 0644)
+		assert.NoError(t, err)
+	}
+	writeLimits("      chaincodeConcurrency: 1\n")
+	config, err := ThrottleConfigFromFile(configFile)
+	assert.NoError(t, err)
+	throttle := NewThrottle(config)
+	stop, err := throttle.WatchConfigFile(configFile)
+	assert.NoError(t, err)
+	defer stop()
+
+	writeLimits("      chaincodeConcurrency: 3\n      chaincodes:\n        mycc: 2\n")
+	assert.True(t, waitForConfig(throttle, func(c ThrottleConfig) bool { return c.ChaincodeConcurrency == 3 }))
+	assert.Equal(t, map[string]int{"mycc": 2}, throttle.Config().Chaincodes)
+
+	// invalid limits are ignored
+	writeLimits("      chaincodeConcurrency: -1\n")
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 3, throttle.Config().ChaincodeConcurrency)
+
+	stop()
+	stop()
+	writeLimits("      chaincodeConcurrency: 4\n")
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 3, throttle.Config().ChaincodeConcurrency)
+
+	_, err = throttle.WatchConfigFile(filepath.Join(dir, "missing", "core.yaml"))
+	assert.Error(t, err)
+}
+
+func waitForConfig(throttle *Throttle, check func(ThrottleConfig) bool) bool {
+	for i := 0; i < 100; i++ {
+		if check(throttle.Config()) {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsement
+
+import (
+	"github.com/hyperledger/fabric/protos/peer"
+)
+
+// Dependency marks a dependency passed to the Init method of a Plugin
+type Dependency interface{}
+
+// Plugin endorses the proposals of the chaincodes which refer to it, in
+// place of an endorsement system chaincode
+type Plugin interface {
+	// Endorse signs the marshaled ProposalResponsePayload of the simulation of
+	// the signed proposal. It returns the endorsement and the payload that was
+	// endorsed, which the plugin may have modified
+	Endorse(payload []byte, sp *peer.SignedProposal) (*peer.Endorsement, []byte, error)
+
+	// Init injects the dependencies of the plugin: a SigningIdentityFetcher
+	Init(dependencies ...Dependency) error
+}
+
+// PluginFactory creates instances of a Plugin
+type PluginFactory interface {
+	New() Plugin
+}
+
+// SigningIdentity signs messages and serializes its public identity
+type SigningIdentity interface {
+	// Serialize returns the serialized identity
+	Serialize() ([]byte, error)
+
+	// Sign signs the message
+	Sign(msg []byte) ([]byte, error)
+}
+
+// SigningIdentityFetcher fetches the identity with which a proposal is endorsed
+type SigningIdentityFetcher interface {
+	// SigningIdentityForRequest returns the signing identity for the proposal
+	SigningIdentityForRequest(*peer.SignedProposal) (SigningIdentity, error)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin
+
+import (
+	"errors"
+	"fmt"
+
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	"github.com/hyperledger/fabric/protos/peer"
+)
+
+// DefaultEndorsementFactory creates instances of the DefaultEndorsement plugin
+type DefaultEndorsementFactory struct {
+}
+
+// New returns a new DefaultEndorsement plugin
+func (*DefaultEndorsementFactory) New() endorsement.Plugin {
+	return &DefaultEndorsement{}
+}
+
+// DefaultEndorsement is the built-in endorsement plugin, which signs the
+// proposal response payload as it is, as the default endorsement system
+// chaincode does
+type DefaultEndorsement struct {
+	signingIdentityFetcher endorsement.SigningIdentityFetcher
+}
+
+// Endorse signs the concatenation of the payload and of the serialized
+// signing identity for the proposal
+func (e *DefaultEndorsement) Endorse(payload []byte, sp *peer.SignedProposal) (*peer.Endorsement, []byte, error) {
+	if e.signingIdentityFetcher == nil {
+		return nil, nil, errors.New("the plugin has not been initialized")
+	}
+
+	signer, err := e.signingIdentityFetcher.SigningIdentityForRequest(sp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Could not obtain the signing identity, err %s", err)
+	}
+
+	endorser, err := signer.Serialize()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Could not serialize the signing identity, err %s", err)
+	}
+
+	// sign the concatenation of the proposal response and the serialized endorser identity
+	signature, err := signer.Sign(append(payload, endorser...))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Could not sign the proposal response payload, err %s", err)
+	}
+
+	return &peer.Endorsement{Signature: signature, Endorser: endorser}, payload, nil
+}
+
+// Init expects a SigningIdentityFetcher as dependency
+func (e *DefaultEndorsement) Init(dependencies ...endorsement.Dependency) error {
+	for _, dep := range dependencies {
+		if sif, isSigningIdentityFetcher := dep.(endorsement.SigningIdentityFetcher); isSigningIdentityFetcher {
+			e.signingIdentityFetcher = sif
+			return nil
+		}
+	}
+	return errors.New("no SigningIdentityFetcher passed as a dependency")
+}
@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin
+
+import (
+	"errors"
+	"testing"
+
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSigningIdentity struct {
+	signErr error
+}
+
+func (id *mockSigningIdentity) Serialize() ([]byte, error) {
+	return []byte("endorser"), nil
+}
+
+func (id *mockSigningIdentity) Sign(msg []byte) ([]byte, error) {
+	if id.signErr != nil {
+		return nil, id.signErr
+	}
+	return append([]byte("signed:"), msg...), nil
+}
+
+type mockSigningIdentityFetcher struct {
+	identity *mockSigningIdentity
+	err      error
+}
+
+func (f *mockSigningIdentityFetcher) SigningIdentityForRequest(*peer.SignedProposal) (endorsement.SigningIdentity, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.identity, nil
+}
+
+func TestDefaultEndorsementInit(t *testing.T) {
+	plugin := (&DefaultEndorsementFactory{}).New()
+
+	_, _, err := plugin.Endorse([]byte("payload"), &peer.SignedProposal{})
+	assert.Error(t, err)
+
+	assert.Error(t, plugin.Init())
+	assert.Error(t, plugin.Init("not a dependency"))
+	assert.NoError(t, plugin.Init(&mockSigningIdentityFetcher{}))
+}
+
+func TestDefaultEndorsement(t *testing.T) {
+	fetcher := &mockSigningIdentityFetcher{identity: &mockSigningIdentity{}}
+	plugin := (&DefaultEndorsementFactory{}).New()
+	assert.NoError(t, plugin.Init(fetcher))
+
+	endorsed, payload, err := plugin.Endorse([]byte("payload"), &peer.SignedProposal{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("payload"), payload)
+	assert.Equal(t, []byte("endorser"), endorsed.Endorser)
+	assert.Equal(t, []byte("signed:payloadendorser"), endorsed.Signature)
+
+	fetcher.identity.signErr = errors.New("no key")
+	_, _, err = plugin.Endorse([]byte("payload"), &peer.SignedProposal{})
+	assert.Error(t, err)
+
+	fetcher.err = errors.New("no identity")
+	_, _, err = plugin.Endorse([]byte("payload"), &peer.SignedProposal{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package library
+
+import (
+	"github.com/spf13/viper"
+)
+
+// Config is the configuration of the endorsement and validation handlers of
+// the peer, keyed by the ESCC and VSCC names with which the chaincodes refer
+// to them
+type Config struct {
+	Endorsers  map[string]*HandlerConfig `mapstructure:"endorsers"`
+	Validators map[string]*HandlerConfig `mapstructure:"validators"`
+}
+
+// HandlerConfig selects the plugin of a handler: either a built-in plugin by
+// its name, or a Go plugin loaded from the shared library at the given path
+type HandlerConfig struct {
+	Name    string `mapstructure:"name"`
+	Library string `mapstructure:"library"`
+}
+
+// LoadConfig reads the handlers from the peer.handlers section of the peer configuration
+func LoadConfig() (Config, error) {
+	var config Config
+	err := viper.UnmarshalKey("peer.handlers", &config)
+	return config, err
+}
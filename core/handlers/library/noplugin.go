@@ -0,0 +1,17 @@
+// +build !go1.8
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package library
+
+import "fmt"
+
+// loadPlugin fails as Go plugins are only supported since Go 1.8, hence only
+// the built-in plugins can be used
+func loadPlugin(path string) (interface{}, error) {
+	return nil, fmt.Errorf("failed opening plugin %s: Go plugins require Go 1.8 or later", path)
+}
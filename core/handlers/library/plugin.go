@@ -0,0 +1,40 @@
+// +build go1.8
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package library
+
+import (
+	"fmt"
+	"plugin"
+
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+)
+
+// loadPlugin opens the Go plugin at the path and returns the plugin factory
+// returned by its NewPluginFactory function
+func loadPlugin(path string) (interface{}, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening plugin %s: %s", path, err)
+	}
+
+	symbol, err := p.Lookup(pluginFactorySymbol)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s does not export %s: %s", path, pluginFactorySymbol, err)
+	}
+
+	switch newFactory := symbol.(type) {
+	case func() endorsement.PluginFactory:
+		return newFactory(), nil
+	case func() validation.PluginFactory:
+		return newFactory(), nil
+	default:
+		return nil, fmt.Errorf("%s of plugin %s has type %T, which is not a plugin factory constructor", pluginFactorySymbol, path, symbol)
+	}
+}
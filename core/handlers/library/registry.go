@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package library
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	endorsementbuiltin "github.com/hyperledger/fabric/core/handlers/endorsement/builtin"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	validationbuiltin "github.com/hyperledger/fabric/core/handlers/validation/builtin"
+)
+
+var logger = flogging.MustGetLogger("core/handlers")
+
+// pluginFactorySymbol is the function which a Go plugin must export to
+// return its endorsement.PluginFactory or validation.PluginFactory
+const pluginFactorySymbol = "NewPluginFactory"
+
+// builtinEndorsers are the built-in endorsement plugins, by name
+var builtinEndorsers = map[string]endorsement.PluginFactory{
+	"DefaultEndorsement": &endorsementbuiltin.DefaultEndorsementFactory{},
+}
+
+// builtinValidators are the built-in validation plugins, by name
+var builtinValidators = map[string]validation.PluginFactory{
+	"DefaultValidation": &validationbuiltin.DefaultValidationFactory{},
+}
+
+// Registry holds the factories of the endorsement and validation plugins of
+// the peer, keyed by the ESCC and VSCC names with which the chaincodes refer
+// to them
+type Registry struct {
+	endorsers  map[string]endorsement.PluginFactory
+	validators map[string]validation.PluginFactory
+}
+
+// InitRegistry creates the registry of the handlers of the configuration,
+// loading the Go plugins from their libraries
+func InitRegistry(config Config) (*Registry, error) {
+	r := &Registry{
+		endorsers:  make(map[string]endorsement.PluginFactory),
+		validators: make(map[string]validation.PluginFactory),
+	}
+
+	for name, handler := range config.Endorsers {
+		factory, err := loadHandler(name, handler, func(builtinName string) (interface{}, bool) {
+			f, exists := builtinEndorsers[builtinName]
+			return f, exists
+		})
+		if err != nil {
+			return nil, err
+		}
+		endorser, isEndorser := factory.(endorsement.PluginFactory)
+		if !isEndorser {
+			return nil, fmt.Errorf("endorser %s: the plugin factory is not an endorsement.PluginFactory", name)
+		}
+		r.endorsers[name] = endorser
+	}
+
+	for name, handler := range config.Validators {
+		factory, err := loadHandler(name, handler, func(builtinName string) (interface{}, bool) {
+			f, exists := builtinValidators[builtinName]
+			return f, exists
+		})
+		if err != nil {
+			return nil, err
+		}
+		validator, isValidator := factory.(validation.PluginFactory)
+		if !isValidator {
+			return nil, fmt.Errorf("validator %s: the plugin factory is not a validation.PluginFactory", name)
+		}
+		r.validators[name] = validator
+	}
+
+	return r, nil
+}
+
+// EndorsementPluginFactoryByName returns the factory of the endorsement
+// plugin with the given ESCC name, or nil if there is none
+func (r *Registry) EndorsementPluginFactoryByName(name string) endorsement.PluginFactory {
+	return r.endorsers[name]
+}
+
+// PluginFactoryByName returns the factory of the validation plugin with the
+// given VSCC name, or nil if there is none
+func (r *Registry) PluginFactoryByName(name string) validation.PluginFactory {
+	return r.validators[name]
+}
+
+func loadHandler(name string, handler *HandlerConfig, builtin func(string) (interface{}, bool)) (interface{}, error) {
+	if handler == nil || (handler.Name == "") == (handler.Library == "") {
+		return nil, fmt.Errorf("handler %s: exactly one of name and library must be set", name)
+	}
+
+	if handler.Name != "" {
+		factory, exists := builtin(handler.Name)
+		if !exists {
+			return nil, fmt.Errorf("handler %s: there is no built-in plugin named %s", name, handler.Name)
+		}
+		logger.Infof("Using the built-in plugin %s for handler %s", handler.Name, name)
+		return factory, nil
+	}
+
+	factory, err := loadPlugin(handler.Library)
+	if err != nil {
+		return nil, fmt.Errorf("handler %s: %s", name, err)
+	}
+	logger.Infof("Loaded the plugin %s for handler %s", handler.Library, name)
+	return factory, nil
+}
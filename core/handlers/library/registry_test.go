@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package library
+
+import (
+	"testing"
+
+	endorsementbuiltin "github.com/hyperledger/fabric/core/handlers/endorsement/builtin"
+	validationbuiltin "github.com/hyperledger/fabric/core/handlers/validation/builtin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.handlers.endorsers.escc.name", "DefaultEndorsement")
+	viper.Set("peer.handlers.validators.vscc.name", "DefaultValidation")
+	viper.Set("peer.handlers.validators.assetvscc.library", "/opt/plugins/asset.so")
+
+	config, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, &HandlerConfig{Name: "DefaultEndorsement"}, config.Endorsers["escc"])
+	assert.Equal(t, &HandlerConfig{Name: "DefaultValidation"}, config.Validators["vscc"])
+	assert.Equal(t, &HandlerConfig{Library: "/opt/plugins/asset.so"}, config.Validators["assetvscc"])
+}
+
+func TestInitRegistry(t *testing.T) {
+	registry, err := InitRegistry(Config{
+		Endorsers:  map[string]*HandlerConfig{"escc": {Name: "DefaultEndorsement"}},
+		Validators: map[string]*HandlerConfig{"vscc": {Name: "DefaultValidation"}},
+	})
+	assert.NoError(t, err)
+	assert.IsType(t, &endorsementbuiltin.DefaultEndorsementFactory{}, registry.EndorsementPluginFactoryByName("escc"))
+	assert.IsType(t, &validationbuiltin.DefaultValidationFactory{}, registry.PluginFactoryByName("vscc"))
+	assert.Nil(t, registry.EndorsementPluginFactoryByName("myescc"))
+	assert.Nil(t, registry.PluginFactoryByName("myvscc"))
+
+	// an empty configuration maps no names to plugins
+	registry, err = InitRegistry(Config{})
+	assert.NoError(t, err)
+	assert.Nil(t, registry.PluginFactoryByName("vscc"))
+}
+
+func TestInitRegistryBadConfig(t *testing.T) {
+	for name, config := range map[string]Config{
+		"no plugin":              {Validators: map[string]*HandlerConfig{"vscc": {}}},
+		"nil handler":            {Endorsers: map[string]*HandlerConfig{"escc": nil}},
+		"name and library":       {Validators: map[string]*HandlerConfig{"vscc": {Name: "DefaultValidation", Library: "/tmp/vscc.so"}}},
+		"unknown builtin":        {Validators: map[string]*HandlerConfig{"vscc": {Name: "NoSuchValidation"}}},
+		"builtin of other type":  {Endorsers: map[string]*HandlerConfig{"escc": {Name: "DefaultValidation"}}},
+		"missing plugin library": {Validators: map[string]*HandlerConfig{"vscc": {Library: "/nonexistent/vscc.so"}}},
+	} {
+		_, err := InitRegistry(config)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+import (
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// SerializedPolicy is the serialized endorsement policy of the chaincode
+// being validated, passed as context datum to Validate
+type SerializedPolicy interface {
+	// Bytes returns the bytes of the policy
+	Bytes() []byte
+}
+
+// KeyLevelEndorsement is passed as context datum to Validate, along with the
+// SerializedPolicy, when every key written by the transaction in the namespace
+// has a key-level endorsement policy. These policies, which the peer evaluated
+// already, replace the endorsement policy of the chaincode
+type KeyLevelEndorsement struct{}
+
+// KeyLevelEndorsementArg is the argument which follows the policy in the
+// invocations of the validation system chaincodes to stand for a
+// KeyLevelEndorsement
+const KeyLevelEndorsementArg = "KeyLevelEndorsement"
+
+// PolicyEvaluator evaluates policies against the MSPs of a channel
+type PolicyEvaluator interface {
+	// Evaluate returns nil if the signature set satisfies the serialized policy
+	Evaluate(policyBytes []byte, signatureSet []*common.SignedData) error
+}
+
+// StateFetcher fetches the committed state of a channel
+type StateFetcher interface {
+	// FetchState returns a view of the committed state, which must be
+	// released with its Done method
+	FetchState() (State, error)
+}
+
+// State is a read-only view of the committed state of a channel
+type State interface {
+	// GetState returns the value of the key in the namespace, nil if it does not exist
+	GetState(namespace string, key string) ([]byte, error)
+
+	// Done releases the resources of the view
+	Done()
+}
@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+// Dependency marks a dependency passed to the Init method of a Plugin
+type Dependency interface{}
+
+// ContextDatum marks additional data passed to the Validate method of a Plugin
+type ContextDatum interface{}
+
+// Plugin validates the transactions of the chaincodes which refer to it, in
+// place of a validation system chaincode. An instance of the plugin is created
+// for every channel, and its dependencies are bound to the channel. The
+// transactions of a block are validated concurrently, hence Validate must be
+// safe for concurrent use
+type Plugin interface {
+	// Validate returns nil if the action of the serialized transaction envelope
+	// on the given chaincode namespace is valid. It returns an
+	// *ExecutionFailureError if the validation could not be carried out, and
+	// any other error if the transaction is invalid. The context data contains
+	// the SerializedPolicy of the chaincode, and a KeyLevelEndorsement if the
+	// key-level endorsement policies replace it
+	Validate(txEnvelope []byte, namespace string, contextData ...ContextDatum) error
+
+	// Init injects the dependencies of the plugin: a PolicyEvaluator and a
+	// StateFetcher of the channel of the plugin instance
+	Init(dependencies ...Dependency) error
+}
+
+// PluginFactory creates instances of a Plugin
+type PluginFactory interface {
+	New() Plugin
+}
+
+// ExecutionFailureError indicates that the validation failed because of a
+// failure of the peer, and not because the transaction is invalid
+type ExecutionFailureError struct {
+	Reason string
+}
+
+// Error returns the reason of the failure
+func (e *ExecutionFailureError) Error() string {
+	return e.Reason
+}
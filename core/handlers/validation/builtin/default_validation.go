@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin
+
+import (
+	"errors"
+
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	"github.com/hyperledger/fabric/core/scc/vscc"
+)
+
+// DefaultValidationFactory creates instances of the DefaultValidation plugin
+type DefaultValidationFactory struct {
+}
+
+// New returns a new DefaultValidation plugin
+func (*DefaultValidationFactory) New() validation.Plugin {
+	return &DefaultValidation{}
+}
+
+// DefaultValidation is the built-in validation plugin, which validates the
+// transactions as the default validation system chaincode does: their
+// endorsements must satisfy the endorsement policy of the chaincode, and the
+// invocations of lscc must be well formed
+type DefaultValidation struct {
+	validator *vscc.Validator
+}
+
+// Validate validates the transaction against the SerializedPolicy of the context data,
+// unless the context data holds a KeyLevelEndorsement
+func (v *DefaultValidation) Validate(txEnvelope []byte, namespace string, contextData ...validation.ContextDatum) error {
+	if v.validator == nil {
+		return &validation.ExecutionFailureError{Reason: "the plugin has not been initialized"}
+	}
+
+	var policy validation.SerializedPolicy
+	keyLevelEndorsement := false
+	for _, datum := range contextData {
+		switch d := datum.(type) {
+		case validation.SerializedPolicy:
+			if policy == nil {
+				policy = d
+			}
+		case validation.KeyLevelEndorsement:
+			keyLevelEndorsement = true
+		}
+	}
+	if policy == nil {
+		return &validation.ExecutionFailureError{Reason: "no endorsement policy supplied"}
+	}
+
+	// the key-level endorsement policies replace the one of the chaincode
+	if keyLevelEndorsement {
+		return v.validator.Validate(txEnvelope, nil)
+	}
+	return v.validator.Validate(txEnvelope, policy.Bytes())
+}
+
+// Init expects a PolicyEvaluator and a StateFetcher as dependencies
+func (v *DefaultValidation) Init(dependencies ...validation.Dependency) error {
+	var policyEvaluator validation.PolicyEvaluator
+	var stateFetcher validation.StateFetcher
+	for _, dep := range dependencies {
+		if pe, isPolicyEvaluator := dep.(validation.PolicyEvaluator); isPolicyEvaluator {
+			policyEvaluator = pe
+		}
+		if sf, isStateFetcher := dep.(validation.StateFetcher); isStateFetcher {
+			stateFetcher = sf
+		}
+	}
+	if policyEvaluator == nil {
+		return errors.New("no PolicyEvaluator passed as a dependency")
+	}
+	if stateFetcher == nil {
+		return errors.New("no StateFetcher passed as a dependency")
+	}
+
+	v.validator = vscc.New(policyEvaluator, stateFetcher)
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/util"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/msp/mgmt/testtools"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPolicyEvaluator struct {
+	policy       []byte
+	signatureSet []*common.SignedData
+	err          error
+}
+
+func (pe *mockPolicyEvaluator) Evaluate(policyBytes []byte, signatureSet []*common.SignedData) error {
+	pe.policy = policyBytes
+	pe.signatureSet = signatureSet
+	return pe.err
+}
+
+type mockStateFetcher struct {
+}
+
+func (sf *mockStateFetcher) FetchState() (validation.State, error) {
+	return nil, errors.New("no state")
+}
+
+type serializedPolicy []byte
+
+func (sp serializedPolicy) Bytes() []byte {
+	return sp
+}
+
+func createEndorsedTx(t *testing.T) []byte {
+	signer, err := mspmgmt.GetLocalMSP().GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	creator, err := signer.Serialize()
+	assert.NoError(t, err)
+
+	ccid := &peer.ChaincodeID{Name: "foo", Version: "v1"}
+	cis := &peer.ChaincodeInvocationSpec{ChaincodeSpec: &peer.ChaincodeSpec{ChaincodeId: ccid}}
+	prop, _, err := utils.CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), cis, creator)
+	assert.NoError(t, err)
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, []byte("res"), nil, ccid, nil, signer)
+	assert.NoError(t, err)
+	env, err := utils.CreateSignedTx(prop, signer, presp)
+	assert.NoError(t, err)
+	return utils.MarshalOrPanic(env)
+}
+
+func TestDefaultValidationInit(t *testing.T) {
+	plugin := (&DefaultValidationFactory{}).New()
+
+	err := plugin.Validate([]byte("tx"), "foo", serializedPolicy("policy"))
+	assert.IsType(t, &validation.ExecutionFailureError{}, err)
+
+	assert.Error(t, plugin.Init())
+	assert.Error(t, plugin.Init(&mockPolicyEvaluator{}))
+	assert.Error(t, plugin.Init(&mockStateFetcher{}))
+	assert.NoError(t, plugin.Init(&mockStateFetcher{}, &mockPolicyEvaluator{}))
+}
+
+func TestDefaultValidation(t *testing.T) {
+	tx := createEndorsedTx(t)
+
+	pe := &mockPolicyEvaluator{}
+	plugin := (&DefaultValidationFactory{}).New()
+	assert.NoError(t, plugin.Init(pe, &mockStateFetcher{}))
+
+	// the policy is passed as context datum
+	err := plugin.Validate(tx, "foo")
+	assert.IsType(t, &validation.ExecutionFailureError{}, err)
+
+	assert.NoError(t, plugin.Validate(tx, "foo", serializedPolicy("policy")))
+	assert.Equal(t, []byte("policy"), pe.policy)
+	assert.Len(t, pe.signatureSet, 1)
+
+	// the policy is not satisfied
+	pe.err = errors.New("not satisfied")
+	err = plugin.Validate(tx, "foo", serializedPolicy("policy"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "policy evaluation failed")
+	assert.False(t, isExecutionFailure(err))
+
+	// the key-level endorsement policies replace the policy
+	pe.policy = nil
+	assert.NoError(t, plugin.Validate(tx, "foo", serializedPolicy("policy"), validation.KeyLevelEndorsement{}))
+	assert.Nil(t, pe.policy)
+
+	// malformed transaction
+	err = plugin.Validate([]byte("garbage"), "foo", serializedPolicy("policy"))
+	assert.Error(t, err)
+	assert.False(t, isExecutionFailure(err))
+}
+
+func isExecutionFailure(err error) bool {
+	_, isExecutionFailure := err.(*validation.ExecutionFailureError)
+	return isExecutionFailure
+}
+
+func TestMain(m *testing.M) {
+	msptesttools.LoadMSPSetupForTesting()
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cceventmgmt
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
+)
+
+// lsccNamespace is the namespace in which the chaincode definitions are maintained
+const lsccNamespace = "lscc"
+
+// KVLedgerLSCCStateListener listens for state changes on 'lscc' namespace
+type KVLedgerLSCCStateListener struct {
+}
+
+// InterestedInNamespaces implements function from interface `ledger.StateListener`
+func (listener *KVLedgerLSCCStateListener) InterestedInNamespaces() []string {
+	return []string{lsccNamespace}
+}
+
+// HandleStateUpdates iterates over key-values being written in the 'lscc' namespace (which indicates deployment of a chaincode)
+// and invokes `HandleChaincodeDeploy` function on chaincode event manager (which in turn is responsible for creation of statedb
+// artifacts for the chaincode statedata)
+func (listener *KVLedgerLSCCStateListener) HandleStateUpdates(channelName string, stateUpdates ledger.StateUpdates) error {
+	var chaincodeDefs []*ChaincodeDefinition
+	for _, kvWrite := range stateUpdates[lsccNamespace] {
+		// the collection configurations are maintained in the same namespace
+		if kvWrite.IsDelete || privdata.IsCollectionConfigKey(kvWrite.Key) {
+			continue
+		}
+		chaincodeData := &ccprovider.ChaincodeData{}
+		if err := proto.Unmarshal(kvWrite.Value, chaincodeData); err != nil {
+			logger.Errorf("Ignoring the write of key [%s] in namespace [%s]: it is not a chaincode definition (%s)",
+				kvWrite.Key, lsccNamespace, err)
+			continue
+		}
+		chaincodeDefs = append(chaincodeDefs, &ChaincodeDefinition{Name: chaincodeData.Name, Version: chaincodeData.Version, Hash: chaincodeData.Id})
+	}
+	if len(chaincodeDefs) == 0 {
+		return nil
+	}
+	return GetMgr().HandleChaincodeDeploy(channelName, chaincodeDefs)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cceventmgmt
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+var logger = flogging.MustGetLogger("cceventmgmt")
+
+var mgr = newMgr(&chaincodeInfoProviderImpl{})
+
+// GetMgr returns the reference to singleton event manager
+func GetMgr() *Mgr {
+	return mgr
+}
+
+// Mgr encapsulate important interactions for events related to the interest of ledger
+type Mgr struct {
+	rwlock       sync.RWMutex
+	infoProvider ChaincodeInfoProvider
+	listeners    map[string]ChaincodeLifecycleEventListener
+}
+
+func newMgr(chaincodeInfoProvider ChaincodeInfoProvider) *Mgr {
+	return &Mgr{
+		infoProvider: chaincodeInfoProvider,
+		listeners:    make(map[string]ChaincodeLifecycleEventListener),
+	}
+}
+
+// Register registers the ChaincodeLifecycleEventListener of a ledger,
+// replacing the listener registered previously for the ledger, if any
+func (m *Mgr) Register(ledgerID string, l ChaincodeLifecycleEventListener) {
+	m.rwlock.Lock()
+	defer m.rwlock.Unlock()
+	m.listeners[ledgerID] = l
+}
+
+// Deregister removes the ChaincodeLifecycleEventListener of a ledger
+func (m *Mgr) Deregister(ledgerID string) {
+	m.rwlock.Lock()
+	defer m.rwlock.Unlock()
+	delete(m.listeners, ledgerID)
+}
+
+// HandleChaincodeDeploy is expected to be invoked when chaincodes are deployed (instantiated or upgraded)
+// on a channel. The listener of the channel is notified of the chaincodes that are installed on the peer;
+// the other ones are handled when they get installed (see function `HandleChaincodeInstall`)
+func (m *Mgr) HandleChaincodeDeploy(chainid string, chaincodeDefinitions []*ChaincodeDefinition) error {
+	l := m.getListener(chainid)
+	if l == nil {
+		return nil
+	}
+	for _, chaincodeDefinition := range chaincodeDefinitions {
+		installed, dbArtifacts, err := m.infoProvider.RetrieveChaincodeArtifacts(chaincodeDefinition)
+		if err != nil {
+			return err
+		}
+		if !installed {
+			logger.Infof("Chaincode [%s] is not installed, its statedb artifacts on channel [%s] will be created upon installation",
+				chaincodeDefinition, chainid)
+			continue
+		}
+		logger.Debugf("Invoking listener of channel [%s] for the deploy of chaincode [%s]", chainid, chaincodeDefinition)
+		if err := l.HandleChaincodeDeploy(chaincodeDefinition, dbArtifacts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleChaincodeInstall is expected to be invoked when a chaincode is installed on the peer.
+// The listeners of the channels on which the chaincode is already deployed are notified
+func (m *Mgr) HandleChaincodeInstall(chaincodeDefinition *ChaincodeDefinition, dbArtifacts []byte) error {
+	// the listeners are collected upfront, the lock is not held while checking the deployments
+	// as these need access to the ledgers, which may in turn be committing and invoking this mgr
+	m.rwlock.RLock()
+	listeners := make(map[string]ChaincodeLifecycleEventListener, len(m.listeners))
+	for ledgerID, l := range m.listeners {
+		listeners[ledgerID] = l
+	}
+	m.rwlock.RUnlock()
+
+	for ledgerID, l := range listeners {
+		deployed, err := m.infoProvider.IsChaincodeDeployed(ledgerID, chaincodeDefinition)
+		if err != nil {
+			return err
+		}
+		if !deployed {
+			continue
+		}
+		logger.Debugf("Invoking listener of channel [%s] for the install of chaincode [%s]", ledgerID, chaincodeDefinition)
+		if err := l.HandleChaincodeDeploy(chaincodeDefinition, dbArtifacts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Mgr) getListener(ledgerID string) ChaincodeLifecycleEventListener {
+	m.rwlock.RLock()
+	defer m.rwlock.RUnlock()
+	return m.listeners[ledgerID]
+}
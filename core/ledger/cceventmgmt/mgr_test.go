@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cceventmgmt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+)
+
+func TestCCEventMgmt(t *testing.T) {
+	cc1Def := &ChaincodeDefinition{Name: "cc1", Version: "v1", Hash: []byte("cc1")}
+	cc1DBArtifactsTar := []byte("cc1DBArtifacts")
+
+	cc2Def := &ChaincodeDefinition{Name: "cc2", Version: "v1", Hash: []byte("cc2")}
+	cc2DBArtifactsTar := []byte("cc2DBArtifacts")
+
+	cc3Def := &ChaincodeDefinition{Name: "cc3", Version: "v1", Hash: []byte("cc3")}
+	cc3DBArtifactsTar := []byte("cc3DBArtifacts")
+
+	// cc1 is deployed and installed. cc2 is deployed but not installed. cc3 is not deployed but installed
+	mockProvider := newMockProvider()
+	mockProvider.setChaincodeInstalled(cc1Def, cc1DBArtifactsTar)
+	mockProvider.setChaincodeDeployed("channel1", cc1Def)
+	mockProvider.setChaincodeDeployed("channel1", cc2Def)
+	mockProvider.setChaincodeInstalled(cc3Def, cc3DBArtifactsTar)
+	setEventMgrForTest(newMgr(mockProvider))
+	defer clearEventMgrForTest()
+
+	handler1, handler2 := &mockHandler{}, &mockHandler{}
+	eventMgr := GetMgr()
+	testutil.AssertNotNil(t, eventMgr)
+	eventMgr.Register("channel1", handler1)
+	eventMgr.Register("channel2", handler2)
+
+	// Deploy cc1 on channel1 - handler1 should be invoked as cc1 is installed
+	eventMgr.HandleChaincodeDeploy("channel1", []*ChaincodeDefinition{cc1Def})
+	testutil.AssertEquals(t, handler1.eventsReceived, []*mockEvent{{cc1Def, cc1DBArtifactsTar}})
+	testutil.AssertNil(t, handler2.eventsReceived)
+
+	// Deploy cc2 on channel1 - no handler should be invoked as cc2 is not installed
+	eventMgr.HandleChaincodeDeploy("channel1", []*ChaincodeDefinition{cc2Def})
+	testutil.AssertEquals(t, len(handler1.eventsReceived), 1)
+
+	// Install cc2 - handler1 should be invoked as cc2 is deployed on channel1 only
+	mockProvider.setChaincodeInstalled(cc2Def, cc2DBArtifactsTar)
+	eventMgr.HandleChaincodeInstall(cc2Def, cc2DBArtifactsTar)
+	testutil.AssertEquals(t, handler1.eventsReceived, []*mockEvent{{cc1Def, cc1DBArtifactsTar}, {cc2Def, cc2DBArtifactsTar}})
+	testutil.AssertNil(t, handler2.eventsReceived)
+
+	// Install cc3 - no handler should be invoked as cc3 is not deployed
+	eventMgr.HandleChaincodeInstall(cc3Def, cc3DBArtifactsTar)
+	testutil.AssertEquals(t, len(handler1.eventsReceived), 2)
+	testutil.AssertNil(t, handler2.eventsReceived)
+
+	// Deploy on a channel with no registered handler is a no-op
+	testutil.AssertNoError(t, eventMgr.HandleChaincodeDeploy("channel3", []*ChaincodeDefinition{cc1Def}), "")
+
+	// Once deregistered, the handler of channel1 is no longer invoked
+	eventMgr.Deregister("channel1")
+	eventMgr.HandleChaincodeDeploy("channel1", []*ChaincodeDefinition{cc1Def})
+	testutil.AssertEquals(t, len(handler1.eventsReceived), 2)
+}
+
+func TestCCEventMgmtErrors(t *testing.T) {
+	ccDef := &ChaincodeDefinition{Name: "cc1", Version: "v1", Hash: []byte("cc1")}
+
+	mockProvider := newMockProvider()
+	mockProvider.setChaincodeInstalled(ccDef, nil)
+	mockProvider.setChaincodeDeployed("channel1", ccDef)
+	setEventMgrForTest(newMgr(mockProvider))
+	defer clearEventMgrForTest()
+
+	eventMgr := GetMgr()
+	eventMgr.Register("channel1", &mockHandler{err: errors.New("handler error")})
+	testutil.AssertError(t, eventMgr.HandleChaincodeDeploy("channel1", []*ChaincodeDefinition{ccDef}), "Expected the error of the handler")
+	testutil.AssertError(t, eventMgr.HandleChaincodeInstall(ccDef, nil), "Expected the error of the handler")
+
+	mockProvider.err = errors.New("provider error")
+	eventMgr.Register("channel1", &mockHandler{})
+	testutil.AssertError(t, eventMgr.HandleChaincodeDeploy("channel1", []*ChaincodeDefinition{ccDef}), "Expected the error of the provider")
+	testutil.AssertError(t, eventMgr.HandleChaincodeInstall(ccDef, nil), "Expected the error of the provider")
+}
+
+func TestLSCCListener(t *testing.T) {
+	channelName := "testChannel"
+
+	cc1Def := &ChaincodeDefinition{Name: "testChaincode1", Version: "v1", Hash: []byte("hash_testChaincode1")}
+	cc2Def := &ChaincodeDefinition{Name: "testChaincode2", Version: "v1", Hash: []byte("hash_testChaincode2")}
+	ccDBArtifactsTar := []byte("ccDBArtifacts")
+
+	mockProvider := newMockProvider()
+	mockProvider.setChaincodeInstalled(cc1Def, ccDBArtifactsTar)
+	mockProvider.setChaincodeInstalled(cc2Def, ccDBArtifactsTar)
+	setEventMgrForTest(newMgr(mockProvider))
+	defer clearEventMgrForTest()
+	handler := &mockHandler{}
+	GetMgr().Register(channelName, handler)
+
+	lsccStateListener := &KVLedgerLSCCStateListener{}
+	testutil.AssertEquals(t, lsccStateListener.InterestedInNamespaces(), []string{"lscc"})
+
+	cc1DataBytes, _ := proto.Marshal(&ccprovider.ChaincodeData{Name: cc1Def.Name, Version: cc1Def.Version, Id: cc1Def.Hash})
+	cc2DataBytes, _ := proto.Marshal(&ccprovider.ChaincodeData{Name: cc2Def.Name, Version: cc2Def.Version, Id: cc2Def.Hash})
+	stateUpdates := ledger.StateUpdates{"lscc": {
+		{Key: cc1Def.Name, Value: cc1DataBytes},
+		{Key: privdata.BuildCollectionKVSKey(cc1Def.Name), Value: []byte("collection config")},
+		{Key: cc2Def.Name, Value: cc2DataBytes},
+		{Key: "deletedChaincode", IsDelete: true},
+	}}
+	testutil.AssertNoError(t, lsccStateListener.HandleStateUpdates(channelName, stateUpdates), "")
+	testutil.AssertEquals(t, handler.eventsReceived, []*mockEvent{{cc1Def, ccDBArtifactsTar}, {cc2Def, ccDBArtifactsTar}})
+
+	// no chaincode definition in the updates
+	testutil.AssertNoError(t, lsccStateListener.HandleStateUpdates(channelName, ledger.StateUpdates{"lscc": []*kvrwset.KVWrite{}}), "")
+	testutil.AssertEquals(t, len(handler.eventsReceived), 2)
+}
+
+type mockProvider struct {
+	chaincodesDeployed  map[string]map[string]bool
+	chaincodesInstalled map[string][]byte
+	err                 error
+}
+
+type mockHandler struct {
+	eventsReceived []*mockEvent
+	err            error
+}
+
+type mockEvent struct {
+	def            *ChaincodeDefinition
+	dbArtifactsTar []byte
+}
+
+func (l *mockHandler) HandleChaincodeDeploy(chaincodeDefinition *ChaincodeDefinition, dbArtifactsTar []byte) error {
+	if l.err != nil {
+		return l.err
+	}
+	l.eventsReceived = append(l.eventsReceived, &mockEvent{def: chaincodeDefinition, dbArtifactsTar: dbArtifactsTar})
+	return nil
+}
+
+func newMockProvider() *mockProvider {
+	return &mockProvider{
+		make(map[string]map[string]bool),
+		make(map[string][]byte),
+		nil,
+	}
+}
+
+func (p *mockProvider) setChaincodeDeployed(chainid string, chaincodeDefinition *ChaincodeDefinition) {
+	if p.chaincodesDeployed[chainid] == nil {
+		p.chaincodesDeployed[chainid] = make(map[string]bool)
+	}
+	p.chaincodesDeployed[chainid][chaincodeDefinition.Name] = true
+}
+
+func (p *mockProvider) setChaincodeInstalled(chaincodeDefinition *ChaincodeDefinition, dbArtifactsTar []byte) {
+	p.chaincodesInstalled[chaincodeDefinition.Name] = dbArtifactsTar
+}
+
+func (p *mockProvider) IsChaincodeDeployed(chainid string, chaincodeDefinition *ChaincodeDefinition) (bool, error) {
+	if p.err != nil {
+		return false, p.err
+	}
+	return p.chaincodesDeployed[chainid][chaincodeDefinition.Name], nil
+}
+
+func (p *mockProvider) RetrieveChaincodeArtifacts(chaincodeDefinition *ChaincodeDefinition) (installed bool, dbArtifactsTar []byte, err error) {
+	if p.err != nil {
+		return false, nil, p.err
+	}
+	dbArtifactsTar, ok := p.chaincodesInstalled[chaincodeDefinition.Name]
+	return ok, dbArtifactsTar, nil
+}
+
+func setEventMgrForTest(eventMgr *Mgr) {
+	mgr = eventMgr
+}
+
+func clearEventMgrForTest() {
+	mgr = newMgr(&chaincodeInfoProviderImpl{})
+}
@@ -27,6 +27,8 @@ import (
 type HistoryDBProvider interface {
 	// GetDBHandle returns a handle to a HistoryDB
 	GetDBHandle(id string) (HistoryDB, error)
+	// Drop removes all the data of the HistoryDB with the given id. The HistoryDB must not be in use
+	Drop(id string) error
 	// Close closes all the HistoryDB instances and releases any resources held by HistoryDBProvider
 	Close()
 }
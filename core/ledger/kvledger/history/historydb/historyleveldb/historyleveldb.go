@@ -20,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/common/crosschannel"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
@@ -27,6 +28,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
 	putils "github.com/hyperledger/fabric/protos/utils"
 )
 
@@ -35,6 +37,10 @@ var logger = flogging.MustGetLogger("historyleveldb")
 var savePointKey = []byte{0x00}
 var emptyValue = []byte{}
 
+// crossChannelPartKeyPrefix prefixes the keys under which the writes of the part
+// of a cross-channel transaction are kept until the transaction is decided
+var crossChannelPartKeyPrefix = []byte{0x00, 'x'}
+
 // HistoryDBProvider implements interface HistoryDBProvider
 type HistoryDBProvider struct {
 	dbProvider *leveldbhelper.Provider
@@ -53,6 +59,11 @@ func (provider *HistoryDBProvider) GetDBHandle(dbName string) (historydb.History
 	return newHistoryDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
 }
 
+// Drop removes all the keys of a named database, including its savepoint
+func (provider *HistoryDBProvider) Drop(dbName string) error {
+	return provider.dbProvider.GetDBHandle(dbName).DeleteAll()
+}
+
 // Close closes the underlying db
 func (provider *HistoryDBProvider) Close() {
 	provider.dbProvider.Close()
@@ -100,6 +111,9 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
 	}
 
+	// the writes of the parts of the cross-channel transactions prepared in the block
+	parts := make(map[string][]byte)
+
 	// write each tran's write set to history db
 	for _, envBytes := range block.Data.Data {
 
@@ -142,10 +156,23 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 			if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
 				return err
 			}
+			// the writes of the part of a cross-channel transaction are held until the
+			// transaction is decided, and are recorded in the history once committed
+			xtxID, record, err := crosschannel.GetRecord(txRWSet)
+			isCrossChannelPart := err == nil && record != nil && record.Status == peer.CrossChannelRecord_PREPARED
+			if err == nil && record != nil {
+				if err = historyDB.addCrossChannelDecision(dbBatch, parts, xtxID, record, txRWSet, blockNo, tranNo); err != nil {
+					return err
+				}
+			}
+
 			// for each transaction, loop through the namespaces and writesets
 			// and add a history record for each write
 			for _, nsRWSet := range txRWSet.NsRwSets {
 				ns := nsRWSet.NameSpace
+				if isCrossChannelPart && ns != crosschannel.Namespace {
+					continue
+				}
 
 				for _, kvWrite := range nsRWSet.KvRwSet.Writes {
 					writeKey := kvWrite.Key
@@ -209,6 +236,57 @@ func (historyDB *historyDB) ShouldRecover(lastAvailableBlock uint64) (bool, uint
 }
 
 // CommitLostBlock implements method in interface kvledger.Recoverer
+// addCrossChannelDecision keeps the writes of the part of a cross-channel transaction
+// while it is prepared, along with the height of the part. Once the transaction is
+// committed, a history record is added for each of them at the height of the
+// transaction committing it, whose value is the height of the part writing the key
+func (historyDB *historyDB) addCrossChannelDecision(dbBatch *leveldbhelper.UpdateBatch, parts map[string][]byte,
+	xtxID string, record *peer.CrossChannelRecord, txRWSet *rwsetutil.TxRwSet, blockNo, tranNo uint64) error {
+	partKey := append(append([]byte{}, crossChannelPartKeyPrefix...), xtxID...)
+
+	if record.Status == peer.CrossChannelRecord_PREPARED {
+		pending := &rwsetutil.TxRwSet{}
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			if nsRWSet.NameSpace != crosschannel.Namespace {
+				pending.NsRwSets = append(pending.NsRwSets, nsRWSet)
+			}
+		}
+		pendingBytes, err := pending.ToProtoBytes()
+		if err != nil {
+			return err
+		}
+		part := append(version.NewHeight(blockNo, tranNo).ToBytes(), pendingBytes...)
+		parts[xtxID] = part
+		dbBatch.Put(partKey, part)
+		return nil
+	}
+
+	part, inBlock := parts[xtxID]
+	if !inBlock {
+		var err error
+		if part, err = historyDB.db.Get(partKey); err != nil {
+			return err
+		}
+	}
+	parts[xtxID] = nil
+	dbBatch.Delete(partKey)
+	if part == nil || record.Status != peer.CrossChannelRecord_COMMITTED {
+		return nil
+	}
+
+	partHeight, n := version.NewHeightFromBytes(part)
+	pending := &rwsetutil.TxRwSet{}
+	if err := pending.FromProtoBytes(part[n:]); err != nil {
+		return err
+	}
+	for _, nsRWSet := range pending.NsRwSets {
+		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			dbBatch.Put(historydb.ConstructCompositeHistoryKey(nsRWSet.NameSpace, kvWrite.Key, blockNo, tranNo), partHeight.ToBytes())
+		}
+	}
+	return nil
+}
+
 func (historyDB *historyDB) CommitLostBlock(block *common.Block) error {
 	if err := historyDB.Commit(block); err != nil {
 		return err
@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
@@ -69,22 +70,39 @@ func newHistoryScanner(compositePartialKey []byte, namespace string, key string,
 }
 
 func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
-	if !scanner.dbItr.Next() {
-		return nil, nil
-	}
-	historyKey := scanner.dbItr.Key() // history key is in the form namespace~key~blocknum~trannum
-
-	// SplitCompositeKey(namespace~key~blocknum~trannum, namespace~key~) will return the blocknum~trannum in second position
-	_, blockNumTranNumBytes := historydb.SplitCompositeHistoryKey(historyKey, scanner.compositePartialKey)
-	blockNum, bytesConsumed := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[0:])
-	tranNum, _ := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[bytesConsumed:])
-	logger.Debugf("Found history record for namespace:%s key:%s at blockNumTranNum %v:%v\n",
-		scanner.namespace, scanner.key, blockNum, tranNum)
-
-	// Get the transaction from block storage that is associated with this history record
-	tranEnvelope, err := scanner.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
-	if err != nil {
-		return nil, err
+	var tranEnvelope *common.Envelope
+	for tranEnvelope == nil {
+		if !scanner.dbItr.Next() {
+			return nil, nil
+		}
+		historyKey := scanner.dbItr.Key() // history key is in the form namespace~key~blocknum~trannum
+
+		// SplitCompositeKey(namespace~key~blocknum~trannum, namespace~key~) will return the blocknum~trannum in second position
+		_, blockNumTranNumBytes := historydb.SplitCompositeHistoryKey(historyKey, scanner.compositePartialKey)
+		blockNum, bytesConsumed := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[0:])
+		tranNum, _ := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[bytesConsumed:])
+		logger.Debugf("Found history record for namespace:%s key:%s at blockNumTranNum %v:%v\n",
+			scanner.namespace, scanner.key, blockNum, tranNum)
+
+		// the key is written by the part of a cross-channel transaction committed at this
+		// height, whose height is the value of the history record
+		if partHeightBytes := scanner.dbItr.Value(); len(partHeightBytes) > 0 {
+			partHeight, _ := version.NewHeightFromBytes(partHeightBytes)
+			blockNum, tranNum = partHeight.BlockNum, partHeight.TxNum
+		}
+
+		// Get the transaction from block storage that is associated with this history record
+		var err error
+		tranEnvelope, err = scanner.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+		if err == blkstorage.ErrBlockPruned {
+			// the history of the key prior to the pruned blocks is no longer available, skip the record
+			logger.Debugf("Skipping history record for namespace:%s key:%s, block %v has been pruned",
+				scanner.namespace, scanner.key, blockNum)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Get the txid, key write value, timestamp, and delete indicator associated with this transaction
@@ -21,8 +21,10 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/common/crosschannel"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
@@ -231,6 +233,120 @@ func TestHistoryForInvalidTran(t *testing.T) {
 	testutil.AssertNil(t, kmod)
 }
 
+func TestHistoryForCrossChannelPart(t *testing.T) {
+
+	env := NewTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	testutil.AssertNoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	testutil.AssertNoError(t, store1.AddBlock(gb), "")
+	testutil.AssertNoError(t, env.testHistoryDB.Commit(gb), "")
+
+	//block1 holds the part of a cross-channel transaction
+	record, _ := proto.Marshal(&peer.CrossChannelRecord{Status: peer.CrossChannelRecord_PREPARED, Channels: []string{"ledger1", "ledger2"}})
+	simulator, _ := env.txmgr.NewTxSimulator()
+	simulator.SetState("ns1", "key7", []byte("value1"))
+	simulator.SetState(crosschannel.Namespace, "txid1", record)
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	block1 := bg.NextBlock([][]byte{simRes})
+	testutil.AssertNoError(t, store1.AddBlock(block1), "")
+	testutil.AssertNoError(t, env.testHistoryDB.Commit(block1), "")
+
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1)
+	testutil.AssertNoError(t, err, "Error upon NewHistoryQueryExecutor")
+
+	// the writes held by the part are not in the history, unlike its record
+	itr, err2 := qhistory.GetHistoryForKey("ns1", "key7")
+	testutil.AssertNoError(t, err2, "Error upon GetHistoryForKey()")
+	kmod, _ := itr.Next()
+	testutil.AssertNil(t, kmod)
+
+	itr, err2 = qhistory.GetHistoryForKey(crosschannel.Namespace, "txid1")
+	testutil.AssertNoError(t, err2, "Error upon GetHistoryForKey()")
+	kmod, _ = itr.Next()
+	testutil.AssertNotNil(t, kmod)
+}
+
+func TestHistoryForCommittedCrossChannelPart(t *testing.T) {
+
+	env := NewTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	testutil.AssertNoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	testutil.AssertNoError(t, store1.AddBlock(gb), "")
+	testutil.AssertNoError(t, env.testHistoryDB.Commit(gb), "")
+
+	recordAs := func(status peer.CrossChannelRecord_Status) []byte {
+		record, _ := proto.Marshal(&peer.CrossChannelRecord{Status: status, Channels: []string{"ledger1", "ledger2"}})
+		return record
+	}
+
+	//block1 holds the parts of two cross-channel transactions
+	simulator, _ := env.txmgr.NewTxSimulator()
+	simulator.SetState("ns1", "key7", []byte("value1"))
+	simulator.SetState(crosschannel.Namespace, "txid1", recordAs(peer.CrossChannelRecord_PREPARED))
+	simulator.Done()
+	simRes1, _ := simulator.GetTxSimulationResults()
+	simulator, _ = env.txmgr.NewTxSimulator()
+	simulator.SetState("ns1", "key8", []byte("value2"))
+	simulator.SetState(crosschannel.Namespace, "txid2", recordAs(peer.CrossChannelRecord_PREPARED))
+	simulator.Done()
+	simRes2, _ := simulator.GetTxSimulationResults()
+	block1 := bg.NextBlock([][]byte{simRes1, simRes2})
+	testutil.AssertNoError(t, store1.AddBlock(block1), "")
+	testutil.AssertNoError(t, env.testHistoryDB.Commit(block1), "")
+
+	//block2 commits the first transaction and aborts the second one
+	simulator, _ = env.txmgr.NewTxSimulator()
+	simulator.SetState(crosschannel.Namespace, "txid1", recordAs(peer.CrossChannelRecord_COMMITTED))
+	simulator.Done()
+	simRes1, _ = simulator.GetTxSimulationResults()
+	simulator, _ = env.txmgr.NewTxSimulator()
+	simulator.SetState(crosschannel.Namespace, "txid2", recordAs(peer.CrossChannelRecord_ABORTED))
+	simulator.Done()
+	simRes2, _ = simulator.GetTxSimulationResults()
+	block2 := bg.NextBlock([][]byte{simRes1, simRes2})
+	testutil.AssertNoError(t, store1.AddBlock(block2), "")
+	testutil.AssertNoError(t, env.testHistoryDB.Commit(block2), "")
+
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1)
+	testutil.AssertNoError(t, err, "Error upon NewHistoryQueryExecutor")
+
+	// the write of the committed part is in the history, with the value the part writes
+	itr, err2 := qhistory.GetHistoryForKey("ns1", "key7")
+	testutil.AssertNoError(t, err2, "Error upon GetHistoryForKey()")
+	kmod, err := itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNotNil(t, kmod)
+	testutil.AssertEquals(t, kmod.(*queryresult.KeyModification).Value, []byte("value1"))
+	kmod, _ = itr.Next()
+	testutil.AssertNil(t, kmod)
+
+	// unlike the write of the aborted one
+	itr, err2 = qhistory.GetHistoryForKey("ns1", "key8")
+	testutil.AssertNoError(t, err2, "Error upon GetHistoryForKey()")
+	kmod, _ = itr.Next()
+	testutil.AssertNil(t, kmod)
+
+	// the writes of the parts are no longer kept once they are decided
+	for _, xtxID := range []string{"txid1", "txid2"} {
+		part, err := env.testHistoryDB.(*historyDB).db.Get(append(append([]byte{}, crossChannelPartKeyPrefix...), xtxID...))
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertNil(t, part)
+	}
+}
+
 //TestSavepoint tests that save points get written after each block and get returned via GetBlockNumfromSavepoint
 func TestHistoryDisabled(t *testing.T) {
 
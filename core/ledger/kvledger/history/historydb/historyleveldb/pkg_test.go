@@ -52,8 +52,10 @@ func NewTestHistoryEnv(t *testing.T) *levelDBLockBasedHistoryEnv {
 	testDBEnv := stateleveldb.NewTestVDBEnv(t)
 	testDB, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
 	testutil.AssertNoError(t, err, "")
+	testPvtDB, err := testDBEnv.DBProvider.GetDBHandle("TestPvtDB")
+	testutil.AssertNoError(t, err, "")
 
-	txMgr := lockbasedtxmgr.NewLockBasedTxMgr(testDB)
+	txMgr := lockbasedtxmgr.NewLockBasedTxMgr("TestDB", testDB, testPvtDB, nil)
 
 	testHistoryDBProvider := NewHistoryDBProvider()
 	testHistoryDB, err := testHistoryDBProvider.GetDBHandle("TestHistoryDB")
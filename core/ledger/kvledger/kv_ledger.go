@@ -17,13 +17,17 @@ limitations under the License.
 package kvledger
 
 import (
-	"errors"
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
@@ -31,6 +35,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
 )
 
 var logger = flogging.MustGetLogger("kvledger")
@@ -40,23 +45,33 @@ var logger = flogging.MustGetLogger("kvledger")
 type kvLedger struct {
 	ledgerID   string
 	blockStore blkstorage.BlockStore
+	stateDB    statedb.VersionedDB
 	txtmgmt    txmgr.TxMgr
 	historyDB  historydb.HistoryDB
+	commitLock sync.Mutex
 }
 
 // NewKVLedger constructs new `KVLedger`
 func newKVLedger(ledgerID string, blockStore blkstorage.BlockStore,
-	versionedDB statedb.VersionedDB, historyDB historydb.HistoryDB) (*kvLedger, error) {
+	versionedDB statedb.VersionedDB, pvtDB statedb.VersionedDB, historyDB historydb.HistoryDB) (*kvLedger, error) {
 
 	logger.Debugf("Creating KVLedger ledgerID=%s: ", ledgerID)
 
-	//Initialize transaction manager using state database
+	//Initialize transaction manager using state database, the deploys of chaincodes are
+	//tracked through the updates of the lscc namespace
 	var txmgmt txmgr.TxMgr
-	txmgmt = lockbasedtxmgr.NewLockBasedTxMgr(versionedDB)
+	stateListeners := []ledger.StateListener{&cceventmgmt.KVLedgerLSCCStateListener{}}
+	txmgmt = lockbasedtxmgr.NewLockBasedTxMgr(ledgerID, versionedDB, pvtDB, stateListeners)
 
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying
 	// id store, blockstore, txmgr (state database), history database
-	l := &kvLedger{ledgerID, blockStore, txmgmt, historyDB}
+	l := &kvLedger{ledgerID: ledgerID, blockStore: blockStore, stateDB: versionedDB, txtmgmt: txmgmt, historyDB: historyDB}
+
+	//Create the statedb artifacts packaged with the chaincodes, such as couchdb indexes, upon their deploy.
+	//This is registered before the recovery so that the recommitted deploys are handled as well
+	if indexCapable, ok := versionedDB.(statedb.IndexCapable); ok {
+		cceventmgmt.GetMgr().Register(ledgerID, &indexCreator{indexCapable})
+	}
 
 	//Recover both state DB and history DB if they are out of sync with block storage
 	if err := l.recoverDBs(); err != nil {
@@ -182,9 +197,91 @@ func (l *kvLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode
 	return l.blockStore.RetrieveTxValidationCodeByTxID(txID)
 }
 
-//Prune prunes the blocks/transactions that satisfy the given policy
+// Prune prunes the blocks/transactions that satisfy the given policy.
+// Supported policies are `commonledger.PruneByBlockCount` and `commonledger.PruneByTimestamp`.
+// Config blocks are always kept and the blocks that the state DB or the history DB may still
+// need for their recovery are never pruned
 func (l *kvLedger) Prune(policy commonledger.PrunePolicy) error {
-	return errors.New("Not yet implemented")
+	info, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	if info.Height == 0 {
+		logger.Debugf("Channel [%s]: Block storage is empty, nothing to prune", l.ledgerID)
+		return nil
+	}
+
+	var retainFrom uint64
+	switch p := policy.(type) {
+	case *commonledger.PruneByBlockCount:
+		if p.RetainBlocks < info.Height {
+			retainFrom = info.Height - p.RetainBlocks
+		}
+	case *commonledger.PruneByTimestamp:
+		if retainFrom, err = l.firstBlockCreatedSince(p.RetainSince, info.Height); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Unsupported prune policy type %T", policy)
+	}
+
+	lastAvailableBlockNum := info.Height - 1
+	for _, r := range []recoverable{l.txtmgmt, l.historyDB} {
+		recoverFlag, firstBlockNum, err := r.ShouldRecover(lastAvailableBlockNum)
+		if err != nil {
+			return err
+		}
+		if recoverFlag && firstBlockNum < retainFrom {
+			retainFrom = firstBlockNum
+		}
+	}
+
+	logger.Infof("Channel [%s]: Pruning blocks preceding block [%d]", l.ledgerID, retainFrom)
+	return l.blockStore.Prune(retainFrom)
+}
+
+// firstBlockCreatedSince returns the number of the oldest unpruned block that has been created
+// at or after the given time, or height if there is no such block. The blocks that have already
+// been pruned are not scanned
+func (l *kvLedger) firstBlockCreatedSince(t time.Time, height uint64) (uint64, error) {
+	firstBlockNumber, err := l.blockStore.FirstUnprunedBlockNumber()
+	if err != nil {
+		return 0, err
+	}
+	for blockNumber := firstBlockNumber; blockNumber < height; blockNumber++ {
+		block, err := l.blockStore.RetrieveBlockByNumber(blockNumber)
+		if err != nil {
+			return 0, err
+		}
+		blockTime, err := getBlockTimestamp(block)
+		if err != nil {
+			return 0, err
+		}
+		if !blockTime.Before(t) {
+			return blockNumber, nil
+		}
+	}
+	return height, nil
+}
+
+// getBlockTimestamp returns the timestamp of the first transaction of the block
+func getBlockTimestamp(block *common.Block) (time.Time, error) {
+	env, err := putils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	payload, err := putils.GetPayload(env)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if payload.Header == nil {
+		return time.Time{}, fmt.Errorf("Missing header in the first transaction of block [%d]", block.Header.Number)
+	}
+	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ptypes.Timestamp(chdr.Timestamp)
 }
 
 // NewTxSimulator returns new `ledger.TxSimulator`
@@ -209,11 +306,21 @@ func (l *kvLedger) NewHistoryQueryExecutor() (ledger.HistoryQueryExecutor, error
 
 // Commit commits the valid block (returned in the method RemoveInvalidTransactionsAndPrepare) and related state changes
 func (l *kvLedger) Commit(block *common.Block) error {
+	return l.CommitWithPvtData(&ledger.BlockAndPvtData{Block: block})
+}
+
+// CommitWithPvtData commits the block and the private data of its transactions.
+// The private data of a transaction is committed only if the transaction is valid
+// and the private data matches the hashes present in the block
+func (l *kvLedger) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error {
+	l.commitLock.Lock()
+	defer l.commitLock.Unlock()
 	var err error
+	block := blockAndPvtdata.Block
 	blockNo := block.Header.Number
 
 	logger.Debugf("Channel [%s]: Validating block [%d]", l.ledgerID, blockNo)
-	err = l.txtmgmt.ValidateAndPrepare(block, true)
+	err = l.txtmgmt.ValidateAndPrepare(blockAndPvtdata, true)
 	if err != nil {
 		return err
 	}
@@ -242,6 +349,26 @@ func (l *kvLedger) Commit(block *common.Block) error {
 
 // Close closes `KVLedger`
 func (l *kvLedger) Close() {
+	cceventmgmt.GetMgr().Deregister(l.ledgerID)
 	l.blockStore.Shutdown()
 	l.txtmgmt.Shutdown()
 }
+
+// indexCreator creates the indexes packaged with a chaincode in the directory
+// META-INF/statedb/<db type>/indexes, when the chaincode is deployed
+type indexCreator struct {
+	db statedb.IndexCapable
+}
+
+// HandleChaincodeDeploy implements function in the interface cceventmgmt.ChaincodeLifecycleEventListener
+func (c *indexCreator) HandleChaincodeDeploy(chaincodeDefinition *cceventmgmt.ChaincodeDefinition, dbArtifactsTar []byte) error {
+	indexFiles, err := ccprovider.ExtractFileEntries(dbArtifactsTar, c.db.GetDBType()+"/indexes")
+	if err != nil {
+		return err
+	}
+	if len(indexFiles) == 0 {
+		return nil
+	}
+	logger.Infof("Creating %d index(es) for chaincode [%s]", len(indexFiles), chaincodeDefinition)
+	return c.db.ProcessIndexesForChaincodeDeploy(chaincodeDefinition.Name, indexFiles)
+}
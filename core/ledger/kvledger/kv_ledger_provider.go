@@ -24,6 +24,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
@@ -45,8 +46,9 @@ var (
 	// ErrLedgerNotOpened is thrown by a CloseLedger call if a ledger with the given id has not been opened
 	ErrLedgerNotOpened = errors.New("Ledger is not opened yet")
 
-	underConstructionLedgerKey = []byte("underConstructionLedgerKey")
-	ledgerKeyPrefix            = []byte("l")
+	underConstructionLedgerKey   = []byte("underConstructionLedgerKey")
+	underConstructionSnapshotKey = []byte("underConstructionSnapshotKey")
+	ledgerKeyPrefix              = []byte("l")
 )
 
 // Provider implements interface ledger.PeerLedgerProvider
@@ -54,6 +56,7 @@ type Provider struct {
 	idStore            *idStore
 	blockStoreProvider blkstorage.BlockStoreProvider
 	vdbProvider        statedb.VersionedDBProvider
+	pvtdbProvider      statedb.VersionedDBProvider
 	historydbProvider  historydb.HistoryDBProvider
 }
 
@@ -94,12 +97,17 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 		}
 	}
 
+	// Initialize the private state database (plain text private data of the collections).
+	// This is always a leveldb so that the private data never leaves the peer
+	var pvtdbProvider statedb.VersionedDBProvider
+	pvtdbProvider = stateleveldb.NewPvtVersionedDBProvider()
+
 	// Initialize the history database (index for history of values by key)
 	var historydbProvider historydb.HistoryDBProvider
 	historydbProvider = historyleveldb.NewHistoryDBProvider()
 
 	logger.Info("ledger provider Initialized")
-	provider := &Provider{idStore, blockStoreProvider, vdbProvider, historydbProvider}
+	provider := &Provider{idStore, blockStoreProvider, vdbProvider, pvtdbProvider, historydbProvider}
 	provider.recoverUnderConstructionLedger()
 	return provider, nil
 }
@@ -139,6 +147,40 @@ func (provider *Provider) Create(genesisBlock *common.Block) (ledger.PeerLedger,
 	return ledger, nil
 }
 
+// CreateFromSnapshot implements the corresponding method from interface ledger.PeerLedgerProvider
+// As in function 'Create', the under construction flag is set before the databases of the ledger are
+// populated from the snapshot, along with the height that the block storage has once bootstrapped from
+// the snapshot, which is how 'recoverUnderConstructionLedger' tells whether the import has completed
+func (provider *Provider) CreateFromSnapshot(snapshotDir string) (ledger.PeerLedger, error) {
+	s, err := openSnapshot(snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	ledgerID := s.metadata.ChannelName
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrLedgerIDExists
+	}
+	if err = provider.idStore.setUnderConstructionFlagForSnapshot(ledgerID, s.lastBlock.Header.Number+1); err != nil {
+		return nil, err
+	}
+	if err = provider.importSnapshot(ledgerID, s); err != nil {
+		logger.Errorf("Error in importing the snapshot of ledger [%s]. Unsetting under construction flag. Err: %s", ledgerID, err)
+		panicOnErr(provider.runCleanup(ledgerID), "Error while running cleanup for ledger id [%s]", ledgerID)
+		panicOnErr(provider.idStore.unsetUnderConstructionFlag(), "Error while unsetting under construction flag")
+		return nil, err
+	}
+	ledger, err := provider.openInternal(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	panicOnErr(provider.idStore.createLedgerID(ledgerID, s.lastConfigBlock), "Error while marking ledger as created")
+	return ledger, nil
+}
+
 // Open implements the corresponding method from interface ledger.PeerLedgerProvider
 func (provider *Provider) Open(ledgerID string) (ledger.PeerLedger, error) {
 	logger.Debugf("Open() opening kvledger: %s", ledgerID)
@@ -166,6 +208,12 @@ func (provider *Provider) openInternal(ledgerID string) (ledger.PeerLedger, erro
 		return nil, err
 	}
 
+	// Get the private state database for a chain/ledger
+	pvtDB, err := provider.pvtdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the history database (index for history of values by key) for a chain/ledger
 	historyDB, err := provider.historydbProvider.GetDBHandle(ledgerID)
 	if err != nil {
@@ -174,7 +222,7 @@ func (provider *Provider) openInternal(ledgerID string) (ledger.PeerLedger, erro
 
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying data stores
 	// (id store, blockstore, state database, history database)
-	l, err := newKVLedger(ledgerID, blockStore, vDB, historyDB)
+	l, err := newKVLedger(ledgerID, blockStore, vDB, pvtDB, historyDB)
 	if err != nil {
 		return nil, err
 	}
@@ -196,13 +244,15 @@ func (provider *Provider) Close() {
 	provider.idStore.close()
 	provider.blockStoreProvider.Close()
 	provider.vdbProvider.Close()
+	provider.pvtdbProvider.Close()
 	provider.historydbProvider.Close()
 }
 
 // recoverUnderConstructionLedger checks whether the under construction flag is set - this would be the case
 // if a crash had happened during creation of ledger and the ledger creation could have been left in intermediate
-// state. Recovery checks if the ledger was created and the genesis block was committed successfully then it completes
-// the last step of adding the ledger id to the list of created ledgers. Else, it clears the under construction flag
+// state. Recovery checks if the ledger was created and the genesis block was committed successfully (or the block
+// storage was bootstrapped from a snapshot) then it completes the last step of adding the ledger id to the list of
+// created ledgers. Else, it clears the under construction flag
 func (provider *Provider) recoverUnderConstructionLedger() {
 	logger.Debugf("Recovering under construction ledger")
 	ledgerID, err := provider.idStore.getUnderConstructionFlag()
@@ -212,22 +262,29 @@ func (provider *Provider) recoverUnderConstructionLedger() {
 		return
 	}
 	logger.Infof("ledger [%s] found as under construction", ledgerID)
+	snapshotHeight, err := provider.idStore.getUnderConstructionSnapshotHeight()
+	panicOnErr(err, "Error while checking whether the under construction ledger is created from a snapshot")
 	ledger, err := provider.openInternal(ledgerID)
 	panicOnErr(err, "Error while opening under construction ledger [%s]", ledgerID)
 	bcInfo, err := ledger.GetBlockchainInfo()
 	panicOnErr(err, "Error while getting blockchain info for the under construction ledger [%s]", ledgerID)
 	ledger.Close()
 
-	switch bcInfo.Height {
-	case 0:
+	switch {
+	case bcInfo.Height == 0:
 		logger.Infof("Genesis block was not committed. Hence, the peer ledger not created. unsetting the under construction flag")
 		panicOnErr(provider.runCleanup(ledgerID), "Error while running cleanup for ledger id [%s]", ledgerID)
 		panicOnErr(provider.idStore.unsetUnderConstructionFlag(), "Error while unsetting under construction flag")
-	case 1:
+	case snapshotHeight == 0 && bcInfo.Height == 1:
 		logger.Infof("Genesis block was committed. Hence, marking the peer ledger as created")
 		genesisBlock, err := ledger.GetBlockByNumber(0)
 		panicOnErr(err, "Error while retrieving genesis block from blockchain for ledger [%s]", ledgerID)
 		panicOnErr(provider.idStore.createLedgerID(ledgerID, genesisBlock), "Error while adding ledgerID [%s] to created list", ledgerID)
+	case snapshotHeight != 0 && bcInfo.Height == snapshotHeight:
+		logger.Infof("Block storage was bootstrapped from the snapshot. Hence, marking the peer ledger as created")
+		lastBlock, err := ledger.GetBlockByNumber(snapshotHeight - 1)
+		panicOnErr(err, "Error while retrieving last block from blockchain for ledger [%s]", ledgerID)
+		panicOnErr(provider.idStore.createLedgerID(ledgerID, lastBlock), "Error while adding ledgerID [%s] to created list", ledgerID)
 	default:
 		panic(fmt.Errorf(
 			"Data inconsistency: under construction flag is set for ledger [%s] while the height of the blockchain is [%d]",
@@ -272,8 +329,20 @@ func (s *idStore) setUnderConstructionFlag(ledgerID string) error {
 	return s.db.Put(underConstructionLedgerKey, []byte(ledgerID), true)
 }
 
+// setUnderConstructionFlagForSnapshot sets the under construction flag for a ledger created from a snapshot,
+// along with the height of the block storage once bootstrapped from the snapshot
+func (s *idStore) setUnderConstructionFlagForSnapshot(ledgerID string, snapshotHeight uint64) error {
+	batch := &leveldb.Batch{}
+	batch.Put(underConstructionLedgerKey, []byte(ledgerID))
+	batch.Put(underConstructionSnapshotKey, util.EncodeOrderPreservingVarUint64(snapshotHeight))
+	return s.db.WriteBatch(batch, true)
+}
+
 func (s *idStore) unsetUnderConstructionFlag() error {
-	return s.db.Delete(underConstructionLedgerKey, true)
+	batch := &leveldb.Batch{}
+	batch.Delete(underConstructionLedgerKey)
+	batch.Delete(underConstructionSnapshotKey)
+	return s.db.WriteBatch(batch, true)
 }
 
 func (s *idStore) getUnderConstructionFlag() (string, error) {
@@ -284,6 +353,16 @@ func (s *idStore) getUnderConstructionFlag() (string, error) {
 	return string(val), nil
 }
 
+// getUnderConstructionSnapshotHeight returns 0 if the under construction ledger is not created from a snapshot
+func (s *idStore) getUnderConstructionSnapshotHeight() (uint64, error) {
+	val, err := s.db.Get(underConstructionSnapshotKey)
+	if err != nil || val == nil {
+		return 0, err
+	}
+	height, _ := util.DecodeOrderPreservingVarUint64(val)
+	return height, nil
+}
+
 func (s *idStore) createLedgerID(ledgerID string, gb *common.Block) error {
 	key := s.encodeLedgerKey(ledgerID)
 	var val []byte
@@ -300,6 +379,7 @@ func (s *idStore) createLedgerID(ledgerID string, gb *common.Block) error {
 	batch := &leveldb.Batch{}
 	batch.Put(key, val)
 	batch.Delete(underConstructionLedgerKey)
+	batch.Delete(underConstructionSnapshotKey)
 	return s.db.WriteBatch(batch, true)
 }
 
@@ -318,11 +398,10 @@ func (s *idStore) getAllLedgerIds() ([]string, error) {
 	itr := s.db.GetIterator(nil, nil)
 	itr.First()
 	for itr.Valid() {
-		if bytes.Equal(itr.Key(), underConstructionLedgerKey) {
-			continue
+		if bytes.HasPrefix(itr.Key(), ledgerKeyPrefix) {
+			id := string(s.decodeLedgerID(itr.Key()))
+			ids = append(ids, id)
 		}
-		id := string(s.decodeLedgerID(itr.Key()))
-		ids = append(ids, id)
 		itr.Next()
 	}
 	return ids, nil
@@ -20,8 +20,11 @@ import (
 	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
+	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	lgr "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/hyperledger/fabric/protos/common"
@@ -158,7 +161,7 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	block2 := bg.NextBlock([][]byte{simRes})
 
 	//performing validation of read and write set to find valid transactions
-	ledger.(*kvLedger).txtmgmt.ValidateAndPrepare(block2, true)
+	ledger.(*kvLedger).txtmgmt.ValidateAndPrepare(&lgr.BlockAndPvtData{Block: block2}, true)
 	//writing the validated block to block storage but not committing the transaction
 	//to state DB and history DB (if exist)
 	err = ledger.(*kvLedger).blockStore.AddBlock(block2)
@@ -269,7 +272,7 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	//generating a block based on the simulation result
 	block3 := bg.NextBlock([][]byte{simRes})
 	//performing validation of read and write set to find valid transactions
-	ledger.(*kvLedger).txtmgmt.ValidateAndPrepare(block3, true)
+	ledger.(*kvLedger).txtmgmt.ValidateAndPrepare(&lgr.BlockAndPvtData{Block: block3}, true)
 	//writing the validated block to block storage
 	err = ledger.(*kvLedger).blockStore.AddBlock(block3)
 	//committing the transaction to state DB
@@ -369,7 +372,7 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	//generating a block based on the simulation result
 	block4 := bg.NextBlock([][]byte{simRes})
 	//performing validation of read and write set to find valid transactions
-	ledger.(*kvLedger).txtmgmt.ValidateAndPrepare(block4, true)
+	ledger.(*kvLedger).txtmgmt.ValidateAndPrepare(&lgr.BlockAndPvtData{Block: block4}, true)
 	//writing the validated block to block storage but fails to commit to state DB but
 	//successfully commits to history DB (if exists)
 	err = ledger.(*kvLedger).blockStore.AddBlock(block4)
@@ -549,3 +552,45 @@ func TestLedgerWithCouchDbEnabledWithBinaryAndJSONData(t *testing.T) {
 
 	}
 }
+
+func TestKVLedgerPrune(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	defer ledger.Close()
+
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	block1 := bg.NextBlock([][]byte{simRes})
+	ledger.Commit(block1)
+
+	err := ledger.Prune("unknown-policy")
+	testutil.AssertError(t, err, "Expected an error for an unsupported prune policy")
+
+	// all the blocks are in the current block file, which is never pruned
+	err = ledger.Prune(&commonledger.PruneByBlockCount{RetainBlocks: 1})
+	testutil.AssertNoError(t, err, "Error while pruning by block count")
+	err = ledger.Prune(&commonledger.PruneByTimestamp{RetainSince: time.Now().Add(time.Hour)})
+	testutil.AssertNoError(t, err, "Error while pruning by timestamp")
+
+	b0, _ := ledger.GetBlockByNumber(0)
+	testutil.AssertEquals(t, b0, gb)
+	b1, _ := ledger.GetBlockByNumber(1)
+	testutil.AssertEquals(t, b1, block1)
+
+	// block1 is the first block created after the genesis block
+	blockTime, err := getBlockTimestamp(block1)
+	testutil.AssertNoError(t, err, "")
+	blockNum, err := ledger.(*kvLedger).firstBlockCreatedSince(blockTime, 2)
+	testutil.AssertNoError(t, err, "")
+	assert.True(t, blockNum <= 1)
+	blockNum, err = ledger.(*kvLedger).firstBlockCreatedSince(blockTime.Add(time.Hour), 2)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, blockNum, uint64(2))
+}
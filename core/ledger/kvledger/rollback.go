@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+)
+
+// Rollback implements the corresponding method from interface ledger.PeerLedgerProvider
+// The state database and the history database of the ledger are dropped before the block storage is rolled back,
+// so that these databases are never ahead of the block storage if the rollback is interrupted. They are rebuilt by
+// the recovery performed when the ledger is opened (see function 'recoverDBs'), which recommits the blocks from the
+// genesis block on. The private data cannot be recovered from the blocks, hence the private state database is only
+// truncated to the block rolled back to (see function 'truncatePvtDB')
+func (provider *Provider) Rollback(ledgerID string, blockNum uint64) error {
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNonExistingLedgerID
+	}
+	if err = provider.checkDBsRebuildable(ledgerID); err != nil {
+		return err
+	}
+	blockStore, err := provider.blockStoreProvider.OpenBlockStore(ledgerID)
+	if err != nil {
+		return err
+	}
+	bcInfo, err := blockStore.GetBlockchainInfo()
+	if err != nil {
+		blockStore.Shutdown()
+		return err
+	}
+	if blockNum >= bcInfo.Height-1 {
+		blockStore.Shutdown()
+		return fmt.Errorf("Cannot roll back ledger [%s] to block [%d], its last block is [%d]", ledgerID, blockNum, bcInfo.Height-1)
+	}
+	block, err := blockStore.RetrieveBlockByNumber(blockNum)
+	blockStore.Shutdown()
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Rolling back ledger [%s] to block [%d]", ledgerID, blockNum)
+	if err = provider.dropDBs(ledgerID); err != nil {
+		return err
+	}
+	if err = provider.truncatePvtDB(ledgerID, version.NewHeight(blockNum, uint64(len(block.Data.Data)-1))); err != nil {
+		return err
+	}
+	if err = provider.blockStoreProvider.Rollback(ledgerID, blockNum); err != nil {
+		return err
+	}
+	logger.Infof("Rolled back ledger [%s] to block [%d]. Its databases are rebuilt when it is opened next", ledgerID, blockNum)
+	return nil
+}
+
+// RebuildDBs implements the corresponding method from interface ledger.PeerLedgerProvider
+// Every ledger is checked to be rebuildable before anything is dropped. The private state databases are kept
+// since they are in sync with the blocks, which are all recommitted
+func (provider *Provider) RebuildDBs() error {
+	ledgerIDs, err := provider.List()
+	if err != nil {
+		return err
+	}
+	for _, ledgerID := range ledgerIDs {
+		if err = provider.checkDBsRebuildable(ledgerID); err != nil {
+			return err
+		}
+	}
+	for _, ledgerID := range ledgerIDs {
+		logger.Infof("Dropping the databases and the block index of ledger [%s]", ledgerID)
+		if err = provider.dropDBs(ledgerID); err != nil {
+			return err
+		}
+		if err = provider.blockStoreProvider.DropIndex(ledgerID); err != nil {
+			return err
+		}
+	}
+	logger.Infof("Dropped the databases and the block index of [%d] ledger(s). They are rebuilt when the ledgers are opened next",
+		len(ledgerIDs))
+	return nil
+}
+
+// checkDBsRebuildable returns an error if some blocks of the ledger are not available for rebuilding its databases
+func (provider *Provider) checkDBsRebuildable(ledgerID string) error {
+	pruned, err := provider.blockStoreProvider.IsPruned(ledgerID)
+	if err != nil {
+		return err
+	}
+	if pruned {
+		return fmt.Errorf("The databases of ledger [%s] cannot be rebuilt, its blocks have been pruned or it has been created from a snapshot",
+			ledgerID)
+	}
+	return nil
+}
+
+func (provider *Provider) dropDBs(ledgerID string) error {
+	if err := provider.vdbProvider.Drop(ledgerID); err != nil {
+		return err
+	}
+	return provider.historydbProvider.Drop(ledgerID)
+}
+
+// truncatePvtDB removes the private data written after the given savepoint from the private state database
+// of the ledger. Otherwise, a private write rolled back could be served for a later write of the same key at
+// the same height whose private data has not been received. The values overwritten or deleted after the
+// savepoint cannot be recovered, they are missing from the private state of the rolled back ledger
+func (provider *Provider) truncatePvtDB(ledgerID string, savepoint *version.Height) error {
+	pvtDB, err := provider.pvtdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return err
+	}
+	fullScanDB, ok := pvtDB.(statedb.FullScanCapable)
+	if !ok {
+		return fmt.Errorf("The private state database of ledger [%s] cannot be truncated", ledgerID)
+	}
+	itr, err := fullScanDB.GetFullScanIterator()
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	batch := statedb.NewUpdateBatch()
+	removed := 0
+	for {
+		res, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			break
+		}
+		kv := res.(*statedb.VersionedKV)
+		if kv.Version.BlockNum > savepoint.BlockNum {
+			batch.Delete(kv.Namespace, kv.Key, kv.Version)
+			removed++
+		}
+	}
+	logger.Infof("Removing [%d] private data item(s) written after block [%d] from ledger [%s]",
+		removed, savepoint.BlockNum, ledgerID)
+	return pvtDB.ApplyUpdates(batch, savepoint)
+}
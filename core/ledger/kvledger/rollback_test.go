@@ -0,0 +1,222 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+)
+
+func TestRollback(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.Create(gb)
+	testutil.AssertNoError(t, err, "")
+	blocks := append([]*common.Block{gb}, commitTestBlocks(t, l, 5)...)
+	l.Close()
+
+	// a ledger cannot be rolled back to its last block, or beyond
+	testutil.AssertError(t, provider.Rollback("testLedger", 5), "Rolling back to the last block should have failed")
+	testutil.AssertError(t, provider.Rollback("testLedger", 6), "Rolling back beyond the last block should have failed")
+	testutil.AssertEquals(t, provider.Rollback("unknownLedger", 2), ErrNonExistingLedgerID)
+
+	testutil.AssertNoError(t, provider.Rollback("testLedger", 2), "Error while rolling back ledger")
+	l, err = provider.Open("testLedger")
+	testutil.AssertNoError(t, err, "Error while opening rolled back ledger")
+	checkTestLedger(t, l, blocks[:3])
+
+	// the ledger continues from the block rolled back to
+	blocks = append(blocks[:3], commitTestBlocks(t, l, 2)...)
+	checkTestLedger(t, l, blocks)
+	l.Close()
+	provider.Close()
+}
+
+func TestRollbackPvtData(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+	_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.Create(gb)
+	testutil.AssertNoError(t, err, "")
+	commitTestBlocks(t, l, 2)
+	commitPvtTestBlock(t, l, "pvtkey1", []byte("value.3"), true)
+	commitPvtTestBlock(t, l, "pvtkey2", []byte("value.4"), true)
+	l.Close()
+
+	testutil.AssertNoError(t, provider.Rollback("testLedger", 3), "Error while rolling back ledger")
+	l, err = provider.Open("testLedger")
+	testutil.AssertNoError(t, err, "Error while opening rolled back ledger")
+	defer l.Close()
+	qe, _ := l.NewQueryExecutor()
+	value, err := qe.GetPrivateData("ns1", "coll1", "pvtkey1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, value, []byte("value.3"))
+	value, err = qe.GetPrivateData("ns1", "coll1", "pvtkey2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, value)
+	qe.Done()
+
+	// the private data rolled back is not served for a new write at the same height,
+	// whose private data has not been received
+	commitPvtTestBlock(t, l, "pvtkey2", []byte("value.4.1"), false)
+	qe, _ = l.NewQueryExecutor()
+	defer qe.Done()
+	_, err = qe.GetPrivateData("ns1", "coll1", "pvtkey2")
+	testutil.AssertError(t, err, "The private data rolled back should not have been served")
+}
+
+func TestRebuildDBs(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	var allBlocks [][]*common.Block
+	for i := 0; i < 2; i++ {
+		_, gb := testutil.NewBlockGenerator(t, constructTestLedgerID(i), false)
+		l, err := provider.Create(gb)
+		testutil.AssertNoError(t, err, "")
+		allBlocks = append(allBlocks, append([]*common.Block{gb}, commitTestBlocks(t, l, 3)...))
+		l.Close()
+	}
+
+	testutil.AssertNoError(t, provider.RebuildDBs(), "Error while rebuilding databases")
+	for i := 0; i < 2; i++ {
+		l, err := provider.Open(constructTestLedgerID(i))
+		testutil.AssertNoError(t, err, "Error while opening ledger with rebuilt databases")
+		checkTestLedger(t, l, allBlocks[i])
+		l.Close()
+	}
+	provider.Close()
+}
+
+func TestRollbackSnapshotLedger(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	os.RemoveAll(testSnapshotDir)
+	defer os.RemoveAll(testSnapshotDir)
+
+	provider, _ := NewProvider()
+	_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, _ := provider.Create(gb)
+	commitTestBlocks(t, l, 3)
+	testutil.AssertNoError(t, l.ExportSnapshot(testSnapshotDir), "Error while exporting snapshot")
+	l.Close()
+	provider.Close()
+	env.cleanup()
+
+	provider, _ = NewProvider()
+	defer provider.Close()
+	l, err := provider.CreateFromSnapshot(testSnapshotDir)
+	testutil.AssertNoError(t, err, "Error while creating ledger from snapshot")
+	l.Close()
+
+	// the blocks preceding the snapshot are not available for rebuilding the databases
+	testutil.AssertError(t, provider.Rollback("testLedger", 2), "Rolling back a ledger created from a snapshot should have failed")
+	testutil.AssertError(t, provider.RebuildDBs(), "Rebuilding the databases of a ledger created from a snapshot should have failed")
+	l, err = provider.Open("testLedger")
+	testutil.AssertNoError(t, err, "")
+	defer l.Close()
+	bcInfo, _ := l.GetBlockchainInfo()
+	testutil.AssertEquals(t, bcInfo.Height, uint64(4))
+}
+
+// commitTestBlocks commits 'numBlocks' blocks on top of the ledger, each one setting the key 'key' to
+// the value 'value.<blockNum>' and the key 'key<blockNum>' to the value 'value'
+func commitTestBlocks(t *testing.T, l ledger.PeerLedger, numBlocks int) []*common.Block {
+	var blocks []*common.Block
+	bcInfo, _ := l.GetBlockchainInfo()
+	previousHash := bcInfo.CurrentBlockHash
+	for i := int(bcInfo.Height); i < int(bcInfo.Height)+numBlocks; i++ {
+		simulator, _ := l.NewTxSimulator()
+		simulator.SetState("ns1", "key", []byte(fmt.Sprintf("value.%d", i)))
+		simulator.SetState("ns1", fmt.Sprintf("key%d", i), []byte("value"))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		block := testutil.ConstructBlock(t, uint64(i), previousHash, [][]byte{simRes}, false)
+		testutil.AssertNoError(t, l.Commit(block), "")
+		blocks = append(blocks, block)
+		previousHash = block.Header.Hash()
+	}
+	return blocks
+}
+
+// commitPvtTestBlock commits a block on top of the ledger, setting the key 'key' of the collection 'coll1'
+// to the value 'value'. The private data is committed along with the block only if 'withPvtData' is set
+func commitPvtTestBlock(t *testing.T, l ledger.PeerLedger, key string, value []byte, withPvtData bool) *common.Block {
+	bcInfo, _ := l.GetBlockchainInfo()
+	simulator, _ := l.NewTxSimulator()
+	simulator.SetPrivateData("ns1", "coll1", key, value)
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	pvtSimRes, _ := simulator.GetPvtSimulationResults()
+	block := testutil.ConstructBlock(t, bcInfo.Height, bcInfo.CurrentBlockHash, [][]byte{simRes}, false)
+	blockAndPvtData := &ledger.BlockAndPvtData{Block: block}
+	if withPvtData {
+		blockAndPvtData.BlockPvtData = map[uint64]*ledger.TxPvtData{0: {SeqInBlock: 0, WriteSet: pvtSimRes}}
+	}
+	testutil.AssertNoError(t, l.CommitWithPvtData(blockAndPvtData), "")
+	return block
+}
+
+// checkTestLedger checks the blocks, the state and the history of a ledger to which the blocks created
+// by function 'commitTestBlocks' have been committed after the genesis block
+func checkTestLedger(t *testing.T, l ledger.PeerLedger, blocks []*common.Block) {
+	lastBlockNum := len(blocks) - 1
+	bcInfo, _ := l.GetBlockchainInfo()
+	testutil.AssertEquals(t, bcInfo, &common.BlockchainInfo{
+		Height:            uint64(lastBlockNum + 1),
+		CurrentBlockHash:  blocks[lastBlockNum].Header.Hash(),
+		PreviousBlockHash: blocks[lastBlockNum].Header.PreviousHash})
+	for i, block := range blocks {
+		b, err := l.GetBlockByNumber(uint64(i))
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, b, block)
+	}
+
+	qe, _ := l.NewQueryExecutor()
+	defer qe.Done()
+	value, _ := qe.GetState("ns1", "key")
+	testutil.AssertEquals(t, value, []byte(fmt.Sprintf("value.%d", lastBlockNum)))
+	for i := 1; i <= lastBlockNum+2; i++ {
+		value, _ = qe.GetState("ns1", fmt.Sprintf("key%d", i))
+		if i <= lastBlockNum {
+			testutil.AssertEquals(t, value, []byte("value"))
+		} else {
+			testutil.AssertNil(t, value)
+		}
+	}
+	stateDBSavepoint, _ := l.(*kvLedger).txtmgmt.GetLastSavepoint()
+	testutil.AssertEquals(t, stateDBSavepoint.BlockNum, uint64(lastBlockNum))
+
+	if ledgerconfig.IsHistoryDBEnabled() == true {
+		qhistory, _ := l.NewHistoryQueryExecutor()
+		itr, _ := qhistory.GetHistoryForKey("ns1", "key")
+		count := 0
+		for {
+			kmod, err := itr.Next()
+			testutil.AssertNoError(t, err, "Error upon Next()")
+			if kmod == nil {
+				break
+			}
+			count++
+			testutil.AssertNotNil(t, kmod.(*queryresult.KeyModification).Value)
+		}
+		testutil.AssertEquals(t, count, lastBlockNum)
+		historyDBSavepoint, _ := l.(*kvLedger).historyDB.GetLastSavepoint()
+		testutil.AssertEquals(t, historyDBSavepoint.BlockNum, uint64(lastBlockNum))
+	}
+}
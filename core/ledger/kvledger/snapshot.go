@@ -0,0 +1,486 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+// A snapshot of a ledger is a directory holding the following files. The metadata file is written
+// last, hence a snapshot without it is incomplete. It records the SHA256 hash of the other files,
+// which are verified before they are imported
+const (
+	snapshotMetadataFileName        = "_snapshot_metadata.json"
+	snapshotStateFileName           = "public_state.data"
+	snapshotTxIDsFileName           = "txids.data"
+	snapshotLastBlockFileName       = "last_block.data"
+	snapshotLastConfigBlockFileName = "last_config_block.data"
+
+	// maxSnapshotStateBatchSize is the number of keys of a snapshot written to the state database in a single batch
+	maxSnapshotStateBatchSize = 1000
+)
+
+// snapshotMetadata is the content of the metadata file of a snapshot
+type snapshotMetadata struct {
+	ChannelName       string            `json:"channel_name"`
+	LastBlockNumber   uint64            `json:"last_block_number"`
+	LastBlockHash     string            `json:"last_block_hash"`
+	PreviousBlockHash string            `json:"previous_block_hash"`
+	FileHashes        map[string]string `json:"file_hashes"`
+}
+
+// ExportSnapshot writes a snapshot of the ledger to the given directory, which must not exist or be empty.
+// The snapshot holds the public state (including the hashes of the private data) with the versions of the keys,
+// the IDs of the transactions committed, the last block and the last config block. Neither the private data nor
+// the history database are part of a snapshot. Commits are blocked while the snapshot is written so that the
+// snapshot is consistent with the last block. Only goleveldb, the state database implementing
+// statedb.FullScanCapable, supports exporting snapshots
+func (l *kvLedger) ExportSnapshot(snapshotDir string) error {
+	l.commitLock.Lock()
+	defer l.commitLock.Unlock()
+
+	fullScanCapable, ok := l.stateDB.(statedb.FullScanCapable)
+	if !ok {
+		return errors.New("The state database does not support exporting snapshots")
+	}
+	info, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	if info.Height == 0 {
+		return errors.New("Block storage is empty, no snapshot can be exported")
+	}
+	lastBlockNum := info.Height - 1
+	savepoint, err := l.stateDB.GetLatestSavePoint()
+	if err != nil {
+		return err
+	}
+	if savepoint == nil || savepoint.BlockNum != lastBlockNum {
+		return fmt.Errorf("The state database is not in sync with block [%d], the last block of the block storage", lastBlockNum)
+	}
+	lastBlock, err := l.blockStore.RetrieveBlockByNumber(lastBlockNum)
+	if err != nil {
+		return err
+	}
+	lastConfigBlockNum, err := putils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return fmt.Errorf("Error while retrieving the index of the last config block from block [%d]: %s", lastBlockNum, err)
+	}
+	lastConfigBlock, err := l.blockStore.RetrieveBlockByNumber(lastConfigBlockNum)
+	if err != nil {
+		return err
+	}
+
+	empty, err := util.CreateDirIfMissing(snapshotDir)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return fmt.Errorf("Snapshot directory [%s] is not empty", snapshotDir)
+	}
+	logger.Infof("Channel [%s]: Exporting snapshot at block [%d] to [%s]", l.ledgerID, lastBlockNum, snapshotDir)
+
+	metadata := &snapshotMetadata{
+		ChannelName:       l.ledgerID,
+		LastBlockNumber:   lastBlockNum,
+		LastBlockHash:     hex.EncodeToString(lastBlock.Header.Hash()),
+		PreviousBlockHash: hex.EncodeToString(lastBlock.Header.PreviousHash),
+		FileHashes:        map[string]string{},
+	}
+	if metadata.FileHashes[snapshotStateFileName], err = exportState(fullScanCapable, snapshotDir); err != nil {
+		return err
+	}
+	if metadata.FileHashes[snapshotTxIDsFileName], err = l.exportTxIDs(snapshotDir); err != nil {
+		return err
+	}
+	if metadata.FileHashes[snapshotLastBlockFileName], err = exportBlock(lastBlock, snapshotDir, snapshotLastBlockFileName); err != nil {
+		return err
+	}
+	if metadata.FileHashes[snapshotLastConfigBlockFileName], err = exportBlock(lastConfigBlock, snapshotDir,
+		snapshotLastConfigBlockFileName); err != nil {
+		return err
+	}
+	metadataBytes, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(filepath.Join(snapshotDir, snapshotMetadataFileName), metadataBytes, 0644); err != nil {
+		return err
+	}
+	logger.Infof("Channel [%s]: Exported snapshot at block [%d]", l.ledgerID, lastBlockNum)
+	return nil
+}
+
+// exportState writes the keys of all the namespaces along with their values, metadata and versions
+func exportState(db statedb.FullScanCapable, snapshotDir string) (string, error) {
+	w, err := newSnapshotFileWriter(snapshotDir, snapshotStateFileName)
+	if err != nil {
+		return "", err
+	}
+	defer w.close()
+
+	itr, err := db.GetFullScanIterator()
+	if err != nil {
+		return "", err
+	}
+	defer itr.Close()
+	numKeys := 0
+	for {
+		res, err := itr.Next()
+		if err != nil {
+			return "", err
+		}
+		if res == nil {
+			break
+		}
+		kv := res.(*statedb.VersionedKV)
+		for _, b := range [][]byte{[]byte(kv.Namespace), []byte(kv.Key), kv.Value, kv.Metadata, kv.Version.ToBytes()} {
+			if err = w.encodeBytes(b); err != nil {
+				return "", err
+			}
+		}
+		numKeys++
+	}
+	logger.Debugf("Exported [%d] keys", numKeys)
+	return w.done()
+}
+
+// exportTxIDs writes the IDs of the transactions committed along with their validation codes
+func (l *kvLedger) exportTxIDs(snapshotDir string) (string, error) {
+	w, err := newSnapshotFileWriter(snapshotDir, snapshotTxIDsFileName)
+	if err != nil {
+		return "", err
+	}
+	defer w.close()
+
+	if err = l.blockStore.ExportTxIDs(w); err != nil {
+		return "", err
+	}
+	return w.done()
+}
+
+func exportBlock(block *common.Block, snapshotDir string, fileName string) (string, error) {
+	w, err := newSnapshotFileWriter(snapshotDir, fileName)
+	if err != nil {
+		return "", err
+	}
+	defer w.close()
+
+	blockBytes, err := proto.Marshal(block)
+	if err != nil {
+		return "", err
+	}
+	if _, err = w.Write(blockBytes); err != nil {
+		return "", err
+	}
+	return w.done()
+}
+
+// snapshot is a snapshot opened for import, whose blocks have been verified against the metadata
+type snapshot struct {
+	dir             string
+	metadata        *snapshotMetadata
+	lastBlock       *common.Block
+	lastConfigBlock *common.Block
+}
+
+func openSnapshot(snapshotDir string) (*snapshot, error) {
+	metadataBytes, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotMetadataFileName))
+	if err != nil {
+		return nil, fmt.Errorf("Error while reading the snapshot metadata, the snapshot may be incomplete: %s", err)
+	}
+	metadata := &snapshotMetadata{}
+	if err = json.Unmarshal(metadataBytes, metadata); err != nil {
+		return nil, fmt.Errorf("Error while unmarshaling the snapshot metadata: %s", err)
+	}
+	s := &snapshot{dir: snapshotDir, metadata: metadata}
+	if s.lastBlock, err = s.readBlock(snapshotLastBlockFileName); err != nil {
+		return nil, err
+	}
+	if s.lastConfigBlock, err = s.readBlock(snapshotLastConfigBlockFileName); err != nil {
+		return nil, err
+	}
+
+	if s.lastBlock.Header.Number != metadata.LastBlockNumber ||
+		hex.EncodeToString(s.lastBlock.Header.Hash()) != metadata.LastBlockHash {
+		return nil, errors.New("The last block of the snapshot does not match the snapshot metadata")
+	}
+	chainID, err := putils.GetChainIDFromBlock(s.lastConfigBlock)
+	if err != nil {
+		return nil, err
+	}
+	if chainID != metadata.ChannelName {
+		return nil, fmt.Errorf("The last config block of the snapshot belongs to channel [%s] instead of [%s]",
+			chainID, metadata.ChannelName)
+	}
+	lastConfigBlockNum, err := putils.GetLastConfigIndexFromBlock(s.lastBlock)
+	if err != nil {
+		return nil, err
+	}
+	if s.lastConfigBlock.Header.Number != lastConfigBlockNum || !putils.IsConfigBlock(s.lastConfigBlock) {
+		return nil, fmt.Errorf("The last config block of the snapshot is not block [%d], the last config block of block [%d]",
+			lastConfigBlockNum, metadata.LastBlockNumber)
+	}
+	return s, nil
+}
+
+func (s *snapshot) readBlock(fileName string) (*common.Block, error) {
+	r, err := s.openFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer r.close()
+
+	blockBytes, err := ioutil.ReadAll(r.reader)
+	if err != nil {
+		return nil, err
+	}
+	block := &common.Block{}
+	if err = proto.Unmarshal(blockBytes, block); err != nil {
+		return nil, fmt.Errorf("Error while unmarshaling block from snapshot file [%s]: %s", fileName, err)
+	}
+	return block, nil
+}
+
+func (s *snapshot) openFile(fileName string) (*snapshotFileReader, error) {
+	expectedHash, ok := s.metadata.FileHashes[fileName]
+	if !ok {
+		return nil, fmt.Errorf("No hash found in the snapshot metadata for file [%s]", fileName)
+	}
+	return newSnapshotFileReader(s.dir, fileName, expectedHash)
+}
+
+// importSnapshot populates the empty databases of the ledger from the snapshot. The block storage is
+// bootstrapped last, as its height is what tells whether the ledger has been created
+// (see `recoverUnderConstructionLedger`)
+func (provider *Provider) importSnapshot(ledgerID string, s *snapshot) error {
+	logger.Infof("Channel [%s]: Importing snapshot at block [%d] from [%s]", ledgerID, s.lastBlock.Header.Number, s.dir)
+	savepoint := version.NewHeight(s.lastBlock.Header.Number, uint64(len(s.lastBlock.Data.Data)-1))
+
+	vdb, err := provider.vdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return err
+	}
+	if err = s.importState(vdb, savepoint); err != nil {
+		return err
+	}
+
+	if ledgerconfig.IsHistoryDBEnabled() {
+		// the history starts with the last block of the snapshot
+		historyDB, err := provider.historydbProvider.GetDBHandle(ledgerID)
+		if err != nil {
+			return err
+		}
+		if err = historyDB.Commit(s.lastBlock); err != nil {
+			return err
+		}
+	}
+
+	txIDs, err := s.openFile(snapshotTxIDsFileName)
+	if err != nil {
+		return err
+	}
+	defer txIDs.close()
+	blockStore, err := provider.blockStoreProvider.OpenBlockStore(ledgerID)
+	if err != nil {
+		return err
+	}
+	defer blockStore.Shutdown()
+	return blockStore.BootstrapFromSnapshot(s.lastBlock, s.lastConfigBlock, txIDs)
+}
+
+func (s *snapshot) importState(vdb statedb.VersionedDB, savepoint *version.Height) error {
+	r, err := s.openFile(snapshotStateFileName)
+	if err != nil {
+		return err
+	}
+	defer r.close()
+
+	if err = vdb.Open(); err != nil {
+		return err
+	}
+	defer vdb.Close()
+	batch := statedb.NewUpdateBatch()
+	numKeys := 0
+	for {
+		ns, err := r.decodeBytes()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		fields := make([][]byte, 4)
+		for i := range fields {
+			if fields[i], err = r.decodeBytes(); err != nil {
+				return fmt.Errorf("Error while reading the state from the snapshot: %s", err)
+			}
+		}
+		key, value, metadata, versionBytes := string(fields[0]), fields[1], fields[2], fields[3]
+		if err = vdb.ValidateKey(key); err != nil {
+			return err
+		}
+		if len(metadata) == 0 {
+			metadata = nil
+		}
+		ver, _ := version.NewHeightFromBytes(versionBytes)
+		batch.PutValAndMetadata(string(ns), key, value, metadata, ver)
+		numKeys++
+		if numKeys%maxSnapshotStateBatchSize == 0 {
+			if err = vdb.ApplyUpdates(batch, savepoint); err != nil {
+				return err
+			}
+			batch = statedb.NewUpdateBatch()
+		}
+	}
+	logger.Debugf("Imported [%d] keys from the snapshot", numKeys)
+	return vdb.ApplyUpdates(batch, savepoint)
+}
+
+// snapshotFileWriter writes a file of a snapshot while computing its hash
+type snapshotFileWriter struct {
+	file   *os.File
+	writer *bufio.Writer
+	hash   hash.Hash
+	buf    []byte
+}
+
+func newSnapshotFileWriter(snapshotDir string, fileName string) (*snapshotFileWriter, error) {
+	file, err := os.OpenFile(filepath.Join(snapshotDir, fileName), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	return &snapshotFileWriter{
+		file:   file,
+		writer: bufio.NewWriter(io.MultiWriter(file, h)),
+		hash:   h,
+		buf:    make([]byte, binary.MaxVarintLen64),
+	}, nil
+}
+
+func (w *snapshotFileWriter) Write(b []byte) (int, error) {
+	return w.writer.Write(b)
+}
+
+func (w *snapshotFileWriter) encodeUvarint(i uint64) error {
+	n := binary.PutUvarint(w.buf, i)
+	_, err := w.writer.Write(w.buf[:n])
+	return err
+}
+
+func (w *snapshotFileWriter) encodeBytes(b []byte) error {
+	if err := w.encodeUvarint(uint64(len(b))); err != nil {
+		return err
+	}
+	_, err := w.writer.Write(b)
+	return err
+}
+
+// WriteTxID implements method in interface blkstorage.TxIDWriter
+func (w *snapshotFileWriter) WriteTxID(txID string, validationCode peer.TxValidationCode) error {
+	if err := w.encodeBytes([]byte(txID)); err != nil {
+		return err
+	}
+	return w.encodeUvarint(uint64(validationCode))
+}
+
+// done flushes the file to disk and returns its hash
+func (w *snapshotFileWriter) done() (string, error) {
+	if err := w.writer.Flush(); err != nil {
+		return "", err
+	}
+	if err := w.file.Sync(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(w.hash.Sum(nil)), nil
+}
+
+func (w *snapshotFileWriter) close() {
+	w.file.Close()
+}
+
+// snapshotFileReader reads a file of a snapshot, once its hash has been verified
+type snapshotFileReader struct {
+	file   *os.File
+	reader *bufio.Reader
+}
+
+func newSnapshotFileReader(snapshotDir string, fileName string, expectedHash string) (*snapshotFileReader, error) {
+	file, err := os.Open(filepath.Join(snapshotDir, fileName))
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	if _, err = io.Copy(h, file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != expectedHash {
+		file.Close()
+		return nil, fmt.Errorf("The hash of snapshot file [%s] does not match the snapshot metadata", fileName)
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &snapshotFileReader{file: file, reader: bufio.NewReader(file)}, nil
+}
+
+// decodeBytes returns io.EOF if the end of the file has been reached
+func (r *snapshotFileReader) decodeBytes() ([]byte, error) {
+	size, err := binary.ReadUvarint(r.reader)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, size)
+	if _, err = io.ReadFull(r.reader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ReadTxID implements method in interface blkstorage.TxIDReader
+func (r *snapshotFileReader) ReadTxID() (string, peer.TxValidationCode, error) {
+	txID, err := r.decodeBytes()
+	if err == io.EOF {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	validationCode, err := binary.ReadUvarint(r.reader)
+	if err != nil {
+		return "", 0, fmt.Errorf("Error while reading the transaction IDs from the snapshot: %s", err)
+	}
+	return string(txID), peer.TxValidationCode(validationCode), nil
+}
+
+func (r *snapshotFileReader) close() {
+	r.file.Close()
+}
+
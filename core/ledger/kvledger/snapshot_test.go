@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+const testSnapshotDir = "/tmp/fabric/ledgertests/kvledgersnapshot"
+
+func TestSnapshotExportImport(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	os.RemoveAll(testSnapshotDir)
+	defer os.RemoveAll(testSnapshotDir)
+
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	testutil.AssertNoError(t, err, "")
+
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.SetState("ns1", "key2", []byte("value2"))
+	simulator.SetState("ns2", "key1", []byte("value3"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	block1 := bg.NextBlock([][]byte{simRes})
+	testutil.AssertNoError(t, ledger.Commit(block1), "")
+
+	simulator, _ = ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key2", []byte("value4"))
+	simulator.DeleteState("ns2", "key1")
+	simulator.Done()
+	simRes, _ = simulator.GetTxSimulationResults()
+	block2 := bg.NextBlock([][]byte{simRes})
+	testutil.AssertNoError(t, ledger.Commit(block2), "")
+
+	testutil.AssertNoError(t, ledger.ExportSnapshot(testSnapshotDir), "Error while exporting snapshot")
+	// a snapshot is not exported into a non-empty directory
+	testutil.AssertError(t, ledger.ExportSnapshot(testSnapshotDir), "Exporting into a non-empty directory should have failed")
+	bcInfo, _ := ledger.GetBlockchainInfo()
+	ledger.Close()
+	provider.Close()
+
+	// a snapshot can only be imported into a peer that does not have the ledger
+	provider, _ = NewProvider()
+	_, err = provider.CreateFromSnapshot(testSnapshotDir)
+	testutil.AssertEquals(t, err, ErrLedgerIDExists)
+	provider.Close()
+	env.cleanup()
+
+	provider, _ = NewProvider()
+	defer provider.Close()
+	ledger, err = provider.CreateFromSnapshot(testSnapshotDir)
+	testutil.AssertNoError(t, err, "Error while creating ledger from snapshot")
+	defer ledger.Close()
+	ledgerIds, _ := provider.List()
+	testutil.AssertEquals(t, ledgerIds, []string{"testLedger"})
+
+	importedBCInfo, _ := ledger.GetBlockchainInfo()
+	testutil.AssertEquals(t, importedBCInfo, bcInfo)
+	b, _ := ledger.GetBlockByNumber(2)
+	testutil.AssertEquals(t, b, block2)
+	b, _ = ledger.GetBlockByNumber(0)
+	testutil.AssertEquals(t, b, gb)
+	_, err = ledger.GetBlockByNumber(1)
+	testutil.AssertEquals(t, err, blkstorage.ErrBlockPruned)
+
+	// the transactions committed before the snapshot are still known
+	txID := extractTxID(t, block1)
+	_, err = ledger.GetTransactionByID(txID)
+	testutil.AssertEquals(t, err, blkstorage.ErrBlockPruned)
+	validationCode, err := ledger.GetTxValidationCodeByTxID(txID)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, validationCode, peer.TxValidationCode_VALID)
+
+	qe, _ := ledger.NewQueryExecutor()
+	value, _ := qe.GetState("ns1", "key1")
+	testutil.AssertEquals(t, value, []byte("value1"))
+	value, _ = qe.GetState("ns1", "key2")
+	testutil.AssertEquals(t, value, []byte("value4"))
+	value, _ = qe.GetState("ns2", "key1")
+	testutil.AssertNil(t, value)
+	qe.Done()
+
+	// the versions are imported along with the values, so a transaction that reads
+	// the state of the snapshot passes the mvcc check
+	simulator, _ = ledger.NewTxSimulator()
+	value, _ = simulator.GetState("ns1", "key2")
+	testutil.AssertEquals(t, value, []byte("value4"))
+	simulator.SetState("ns1", "key3", []byte("value5"))
+	simulator.Done()
+	simRes, _ = simulator.GetTxSimulationResults()
+	block3 := bg.NextBlock([][]byte{simRes})
+	testutil.AssertNoError(t, ledger.Commit(block3), "")
+	validationCode, _ = ledger.GetTxValidationCodeByTxID(extractTxID(t, block3))
+	testutil.AssertEquals(t, validationCode, peer.TxValidationCode_VALID)
+	bcInfo, _ = ledger.GetBlockchainInfo()
+	testutil.AssertEquals(t, bcInfo, &common.BlockchainInfo{
+		Height: 4, CurrentBlockHash: block3.Header.Hash(), PreviousBlockHash: block2.Header.Hash()})
+}
+
+func TestSnapshotImportTamperedFile(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	os.RemoveAll(testSnapshotDir)
+	defer os.RemoveAll(testSnapshotDir)
+
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	testutil.AssertNoError(t, ledger.Commit(bg.NextBlock([][]byte{simRes})), "")
+	testutil.AssertNoError(t, ledger.ExportSnapshot(testSnapshotDir), "Error while exporting snapshot")
+	ledger.Close()
+	provider.Close()
+	env.cleanup()
+
+	stateFile := filepath.Join(testSnapshotDir, snapshotStateFileName)
+	stateBytes, err := ioutil.ReadFile(stateFile)
+	testutil.AssertNoError(t, err, "")
+	stateBytes[len(stateBytes)-1]++
+	testutil.AssertNoError(t, ioutil.WriteFile(stateFile, stateBytes, 0644), "")
+
+	provider, _ = NewProvider()
+	defer provider.Close()
+	_, err = provider.CreateFromSnapshot(testSnapshotDir)
+	testutil.AssertError(t, err, "Importing a tampered snapshot should have failed")
+	exists, _ := provider.Exists("testLedger")
+	testutil.AssertEquals(t, exists, false)
+	flag, _ := provider.(*Provider).idStore.getUnderConstructionFlag()
+	testutil.AssertEquals(t, flag, "")
+}
+
+func extractTxID(t *testing.T, block *common.Block) string {
+	txEnv, err := putils.GetEnvelopeFromBlock(block.Data.Data[0])
+	testutil.AssertNoError(t, err, "")
+	payload, err := putils.GetPayload(txEnv)
+	testutil.AssertNoError(t, err, "")
+	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	testutil.AssertNoError(t, err, "")
+	return chdr.TxId
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package rwsetutil
 
 import (
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/util"
@@ -28,14 +29,29 @@ var logger = flogging.MustGetLogger("rwsetutil")
 type nsRWs struct {
 	readMap          map[string]*kvrwset.KVRead //for mvcc validation
 	writeMap         map[string]*kvrwset.KVWrite
+	metadataWriteMap map[string]*kvrwset.KVMetadataWrite
 	rangeQueriesMap  map[rangeQueryKey]*kvrwset.RangeQueryInfo //for phantom read validation
 	rangeQueriesKeys []rangeQueryKey
+	collHashedRWs    map[string]*collHashedRWs //hashes of the private data, these go to the public rwset
+	collPvtRWs       map[string]*collPvtRWs    //private data, these never go to the public rwset
 }
 
 func newNsRWs() *nsRWs {
 	return &nsRWs{make(map[string]*kvrwset.KVRead),
 		make(map[string]*kvrwset.KVWrite),
-		make(map[rangeQueryKey]*kvrwset.RangeQueryInfo), nil}
+		make(map[string]*kvrwset.KVMetadataWrite),
+		make(map[rangeQueryKey]*kvrwset.RangeQueryInfo), nil,
+		make(map[string]*collHashedRWs),
+		make(map[string]*collPvtRWs)}
+}
+
+type collHashedRWs struct {
+	readMap  map[string]*kvrwset.KVReadHash
+	writeMap map[string]*kvrwset.KVWriteHash
+}
+
+type collPvtRWs struct {
+	writeMap map[string]*kvrwset.KVWrite
 }
 
 type rangeQueryKey struct {
@@ -66,6 +82,13 @@ func (rws *RWSetBuilder) AddToWriteSet(ns string, key string, value []byte) {
 	nsRWs.writeMap[key] = newKVWrite(key, value)
 }
 
+// AddToMetadataWriteSet adds the metadata of a key to the metadata write-set.
+// A nil or empty metadata map deletes all the metadata entries of the key
+func (rws *RWSetBuilder) AddToMetadataWriteSet(ns string, key string, metadata map[string][]byte) {
+	nsRWs := rws.getOrCreateNsRW(ns)
+	nsRWs.metadataWriteMap[key] = newKVMetadataWrite(key, metadata)
+}
+
 // AddToRangeQuerySet adds a range query info for performing phantom read validation
 func (rws *RWSetBuilder) AddToRangeQuerySet(ns string, rqi *kvrwset.RangeQueryInfo) {
 	nsRWs := rws.getOrCreateNsRW(ns)
@@ -77,6 +100,59 @@ func (rws *RWSetBuilder) AddToRangeQuerySet(ns string, rqi *kvrwset.RangeQueryIn
 	}
 }
 
+// AddToHashedReadSet adds a key and corresponding version to the hashed read-set of a collection.
+// Only the hash of the key goes into the read-set; the key itself is never made public
+func (rws *RWSetBuilder) AddToHashedReadSet(ns string, coll string, key string, version *version.Height) {
+	collHashedRWs := rws.getOrCreateCollHashedRWs(ns, coll)
+	collHashedRWs.readMap[key] = newPvtKVReadHash(key, version)
+}
+
+// AddToPvtAndHashedWriteSet adds a key and value to the private write-set of a collection
+// and the hashes of the key and the value to the corresponding hashed write-set
+func (rws *RWSetBuilder) AddToPvtAndHashedWriteSet(ns string, coll string, key string, value []byte) {
+	kvWrite, kvWriteHash := newPvtKVWriteAndHash(key, value)
+	rws.getOrCreateCollPvtRWs(ns, coll).writeMap[key] = kvWrite
+	rws.getOrCreateCollHashedRWs(ns, coll).writeMap[key] = kvWriteHash
+}
+
+// GetTxPvtReadWriteSet returns the private read-write set of the transaction.
+// It returns nil if the transaction did not write any private data
+func (rws *RWSetBuilder) GetTxPvtReadWriteSet() *TxPvtRwSet {
+	var txPvtRWSet *TxPvtRwSet
+	for _, ns := range util.GetSortedKeys(rws.rwMap) {
+		nsPvtRwSet := rws.getNsPvtRwSet(ns)
+		if nsPvtRwSet == nil {
+			continue
+		}
+		if txPvtRWSet == nil {
+			txPvtRWSet = &TxPvtRwSet{}
+		}
+		txPvtRWSet.NsPvtRwSet = append(txPvtRWSet.NsPvtRwSet, nsPvtRwSet)
+	}
+	return txPvtRWSet
+}
+
+func (rws *RWSetBuilder) getNsPvtRwSet(ns string) *NsPvtRwSet {
+	collPvtRWsMap := rws.rwMap[ns].collPvtRWs
+	if len(collPvtRWsMap) == 0 {
+		return nil
+	}
+	nsPvtRwSet := &NsPvtRwSet{NameSpace: ns}
+	for _, coll := range util.GetSortedKeys(collPvtRWsMap) {
+		nsPvtRwSet.CollPvtRwSets = append(nsPvtRwSet.CollPvtRwSets,
+			&CollPvtRwSet{CollectionName: coll, KvRwSet: collPvtRWsMap[coll].toKVRWSet()})
+	}
+	return nsPvtRwSet
+}
+
+func (pvtRWs *collPvtRWs) toKVRWSet() *kvrwset.KVRWSet {
+	var writes []*kvrwset.KVWrite
+	for _, key := range util.GetSortedKeys(pvtRWs.writeMap) {
+		writes = append(writes, pvtRWs.writeMap[key])
+	}
+	return &kvrwset.KVRWSet{Writes: writes}
+}
+
 // GetTxReadWriteSet returns the read-write set in the form that can be serialized
 func (rws *RWSetBuilder) GetTxReadWriteSet() *TxRwSet {
 	txRWSet := &TxRwSet{}
@@ -99,19 +175,73 @@ func (rws *RWSetBuilder) GetTxReadWriteSet() *TxRwSet {
 			writes = append(writes, nsReadWriteMap.writeMap[key])
 		}
 
+		//add metadata write set
+		var metadataWrites []*kvrwset.KVMetadataWrite
+		for _, key := range util.GetSortedKeys(nsReadWriteMap.metadataWriteMap) {
+			metadataWrites = append(metadataWrites, nsReadWriteMap.metadataWriteMap[key])
+		}
+
 		//add range query info
 		var rangeQueriesInfo []*kvrwset.RangeQueryInfo
 		rangeQueriesMap := nsReadWriteMap.rangeQueriesMap
 		for _, key := range nsReadWriteMap.rangeQueriesKeys {
 			rangeQueriesInfo = append(rangeQueriesInfo, rangeQueriesMap[key])
 		}
-		kvRWs := &kvrwset.KVRWSet{Reads: reads, Writes: writes, RangeQueriesInfo: rangeQueriesInfo}
-		nsRWs := &NsRwSet{ns, kvRWs}
+		kvRWs := &kvrwset.KVRWSet{Reads: reads, Writes: writes, RangeQueriesInfo: rangeQueriesInfo, MetadataWrites: metadataWrites}
+		nsRWs := &NsRwSet{ns, kvRWs, rws.getCollHashedRwSets(ns)}
 		txRWSet.NsRwSets = append(txRWSet.NsRwSets, nsRWs)
 	}
 	return txRWSet
 }
 
+func (rws *RWSetBuilder) getCollHashedRwSets(ns string) []*CollHashedRwSet {
+	nsReadWriteMap := rws.rwMap[ns]
+	var collHashedRwSets []*CollHashedRwSet
+	for _, coll := range util.GetSortedKeys(nsReadWriteMap.collHashedRWs) {
+		hashedRWs := nsReadWriteMap.collHashedRWs[coll]
+		hashedRWSet := &kvrwset.HashedRWSet{}
+		for _, key := range util.GetSortedKeys(hashedRWs.readMap) {
+			hashedRWSet.HashedReads = append(hashedRWSet.HashedReads, hashedRWs.readMap[key])
+		}
+		for _, key := range util.GetSortedKeys(hashedRWs.writeMap) {
+			hashedRWSet.HashedWrites = append(hashedRWSet.HashedWrites, hashedRWs.writeMap[key])
+		}
+		var pvtRwSetHash []byte
+		if pvtRWs, ok := nsReadWriteMap.collPvtRWs[coll]; ok {
+			// the hash of the private rwset is computed over the same serialized bytes
+			// that are disseminated to the other peers, see function 'TxPvtRwSet.ToProtoMsg'
+			pvtRwSetBytes, err := proto.Marshal(pvtRWs.toKVRWSet())
+			if err != nil {
+				// marshaling a well formed proto message in-memory is not expected to fail
+				panic(err)
+			}
+			pvtRwSetHash = util.ComputeHash(pvtRwSetBytes)
+		}
+		collHashedRwSets = append(collHashedRwSets, &CollHashedRwSet{coll, hashedRWSet, pvtRwSetHash})
+	}
+	return collHashedRwSets
+}
+
+func (rws *RWSetBuilder) getOrCreateCollHashedRWs(ns string, coll string) *collHashedRWs {
+	nsRWs := rws.getOrCreateNsRW(ns)
+	hashedRWs, ok := nsRWs.collHashedRWs[coll]
+	if !ok {
+		hashedRWs = &collHashedRWs{make(map[string]*kvrwset.KVReadHash), make(map[string]*kvrwset.KVWriteHash)}
+		nsRWs.collHashedRWs[coll] = hashedRWs
+	}
+	return hashedRWs
+}
+
+func (rws *RWSetBuilder) getOrCreateCollPvtRWs(ns string, coll string) *collPvtRWs {
+	nsRWs := rws.getOrCreateNsRW(ns)
+	pvtRWs, ok := nsRWs.collPvtRWs[coll]
+	if !ok {
+		pvtRWs = &collPvtRWs{make(map[string]*kvrwset.KVWrite)}
+		nsRWs.collPvtRWs[coll] = pvtRWs
+	}
+	return pvtRWs
+}
+
 func (rws *RWSetBuilder) getOrCreateNsRW(ns string) *nsRWs {
 	var nsRWs *nsRWs
 	var ok bool
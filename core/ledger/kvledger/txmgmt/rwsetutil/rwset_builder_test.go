@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 )
 
@@ -63,14 +64,75 @@ func TestRWSetHolder(t *testing.T) {
 	ns1RWSet := &NsRwSet{"ns1", &kvrwset.KVRWSet{
 		Reads:            []*kvrwset.KVRead{NewKVRead("key1", version.NewHeight(1, 1)), NewKVRead("key2", version.NewHeight(1, 2))},
 		RangeQueriesInfo: []*kvrwset.RangeQueryInfo{rqi1, rqi3},
-		Writes:           []*kvrwset.KVWrite{newKVWrite("key2", []byte("value2"))}}}
+		Writes:           []*kvrwset.KVWrite{newKVWrite("key2", []byte("value2"))}}, nil}
 
 	ns2RWSet := &NsRwSet{"ns2", &kvrwset.KVRWSet{
 		Reads:            []*kvrwset.KVRead{NewKVRead("key2", version.NewHeight(1, 2))},
 		RangeQueriesInfo: nil,
-		Writes:           []*kvrwset.KVWrite{newKVWrite("key3", []byte("value3"))}}}
+		Writes:           []*kvrwset.KVWrite{newKVWrite("key3", []byte("value3"))}}, nil}
 
 	expectedTxRWSet := &TxRwSet{[]*NsRwSet{ns1RWSet, ns2RWSet}}
 	t.Logf("Actual=%s\n Expected=%s", txRWSet, expectedTxRWSet)
 	testutil.AssertEquals(t, txRWSet, expectedTxRWSet)
 }
+
+func TestMetadataWriteSet(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilder()
+	rwSetBuilder.AddToWriteSet("ns1", "key1", []byte("value1"))
+	rwSetBuilder.AddToMetadataWriteSet("ns1", "key2", map[string][]byte{"entry2": []byte("meta2"), "entry1": []byte("meta1")})
+	rwSetBuilder.AddToMetadataWriteSet("ns1", "key1", nil)
+
+	txRWSet := rwSetBuilder.GetTxReadWriteSet()
+	expectedTxRWSet := &TxRwSet{[]*NsRwSet{{"ns1", &kvrwset.KVRWSet{
+		Writes: []*kvrwset.KVWrite{newKVWrite("key1", []byte("value1"))},
+		MetadataWrites: []*kvrwset.KVMetadataWrite{
+			{Key: "key1"},
+			{Key: "key2", Entries: []*kvrwset.KVMetadataEntry{
+				{Name: "entry1", Value: []byte("meta1")},
+				{Name: "entry2", Value: []byte("meta2")},
+			}},
+		}}, nil}}}
+	testutil.AssertEquals(t, txRWSet, expectedTxRWSet)
+}
+
+func TestPvtRWSetBuilder(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilder()
+
+	rwSetBuilder.AddToReadSet("ns1", "key1", version.NewHeight(1, 1))
+	rwSetBuilder.AddToHashedReadSet("ns1", "coll1", "pvtKey1", version.NewHeight(1, 2))
+	rwSetBuilder.AddToPvtAndHashedWriteSet("ns1", "coll1", "pvtKey2", []byte("pvtValue2"))
+	rwSetBuilder.AddToPvtAndHashedWriteSet("ns1", "coll1", "pvtKey3", nil)
+
+	// public rwset should carry only the hashes of the private keys and values
+	txRWSet := rwSetBuilder.GetTxReadWriteSet()
+	testutil.AssertEquals(t, len(txRWSet.NsRwSets), 1)
+	collHashedRwSets := txRWSet.NsRwSets[0].CollHashedRwSets
+	testutil.AssertEquals(t, len(collHashedRwSets), 1)
+	testutil.AssertEquals(t, collHashedRwSets[0].CollectionName, "coll1")
+	testutil.AssertEquals(t, collHashedRwSets[0].HashedRwSet, &kvrwset.HashedRWSet{
+		HashedReads: []*kvrwset.KVReadHash{newPvtKVReadHash("pvtKey1", version.NewHeight(1, 2))},
+		HashedWrites: []*kvrwset.KVWriteHash{
+			{KeyHash: util.ComputeStringHash("pvtKey2"), ValueHash: util.ComputeHash([]byte("pvtValue2"))},
+			{KeyHash: util.ComputeStringHash("pvtKey3"), IsDelete: true},
+		},
+	})
+
+	// private rwset should carry the actual private keys and values
+	txPvtRWSet := rwSetBuilder.GetTxPvtReadWriteSet()
+	expectedCollPvtRwSet := &CollPvtRwSet{"coll1", &kvrwset.KVRWSet{
+		Writes: []*kvrwset.KVWrite{newKVWrite("pvtKey2", []byte("pvtValue2")), newKVWrite("pvtKey3", nil)}}}
+	testutil.AssertEquals(t, txPvtRWSet, &TxPvtRwSet{[]*NsPvtRwSet{{"ns1", []*CollPvtRwSet{expectedCollPvtRwSet}}}})
+
+	// hash of the private rwset should match the hash recorded in the public rwset
+	protoTxPvtRWSet, err := txPvtRWSet.ToProtoMsg()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, collHashedRwSets[0].PvtRwSetHash,
+		util.ComputeHash(protoTxPvtRWSet.NsPvtRwset[0].CollectionPvtRwset[0].Rwset))
+}
+
+func TestPvtRWSetBuilderNoPvtData(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilder()
+	rwSetBuilder.AddToWriteSet("ns1", "key1", []byte("value1"))
+	testutil.AssertNil(t, rwSetBuilder.GetTxPvtReadWriteSet())
+	testutil.AssertNil(t, rwSetBuilder.GetTxReadWriteSet().NsRwSets[0].CollHashedRwSets)
+}
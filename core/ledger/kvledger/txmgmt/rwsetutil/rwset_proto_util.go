@@ -19,6 +19,7 @@ package rwsetutil
 import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 )
@@ -30,8 +31,34 @@ type TxRwSet struct {
 
 // NsRwSet encapsulates 'kvrwset.KVRWSet' proto message for a specific name space (chaincode)
 type NsRwSet struct {
-	NameSpace string
-	KvRwSet   *kvrwset.KVRWSet
+	NameSpace        string
+	KvRwSet          *kvrwset.KVRWSet
+	CollHashedRwSets []*CollHashedRwSet
+}
+
+// CollHashedRwSet encapsulates 'kvrwset.HashedRWSet' proto message for a specific collection
+type CollHashedRwSet struct {
+	CollectionName string
+	HashedRwSet    *kvrwset.HashedRWSet
+	PvtRwSetHash   []byte
+}
+
+// TxPvtRwSet represents 'rwset.TxPvtReadWriteSet' proto message
+type TxPvtRwSet struct {
+	NsPvtRwSet []*NsPvtRwSet
+}
+
+// NsPvtRwSet represents 'rwset.NsPvtReadWriteSet' proto message
+type NsPvtRwSet struct {
+	NameSpace     string
+	CollPvtRwSets []*CollPvtRwSet
+}
+
+// CollPvtRwSet encapsulates 'kvrwset.KVRWSet' proto message for a private rwset for a specific collection
+// KvRwSet in private rwset are not expected to contain the read-set
+type CollPvtRwSet struct {
+	CollectionName string
+	KvRwSet        *kvrwset.KVRWSet
 }
 
 // ToProtoBytes constructs TxReadWriteSet proto message and serializes using protobuf Marshal
@@ -46,6 +73,18 @@ func (txRwSet *TxRwSet) ToProtoBytes() ([]byte, error) {
 			return nil, err
 		}
 		protoNsRwSet.Rwset = protoRwSetBytes
+		for _, collHashedRwSet := range nsRwSet.CollHashedRwSets {
+			protoHashedRwSetBytes, err := proto.Marshal(collHashedRwSet.HashedRwSet)
+			if err != nil {
+				return nil, err
+			}
+			protoNsRwSet.CollectionHashedRwset = append(protoNsRwSet.CollectionHashedRwset,
+				&rwset.CollectionHashedReadWriteSet{
+					CollectionName: collHashedRwSet.CollectionName,
+					HashedRwset:    protoHashedRwSetBytes,
+					PvtRwsetHash:   collHashedRwSet.PvtRwSetHash,
+				})
+		}
 		protoTxRWSet.NsRwset = append(protoTxRWSet.NsRwset, protoNsRwSet)
 	}
 	protoTxRwSetBytes, err := proto.Marshal(protoTxRWSet)
@@ -73,11 +112,57 @@ func (txRwSet *TxRwSet) FromProtoBytes(protoBytes []byte) error {
 			return err
 		}
 		nsRwSet.KvRwSet = protoKvRwSet
+		for _, protoCollHashedRwSet := range protoNsRwSet.CollectionHashedRwset {
+			protoHashedRwSet := &kvrwset.HashedRWSet{}
+			if err := proto.Unmarshal(protoCollHashedRwSet.HashedRwset, protoHashedRwSet); err != nil {
+				return err
+			}
+			nsRwSet.CollHashedRwSets = append(nsRwSet.CollHashedRwSets, &CollHashedRwSet{
+				CollectionName: protoCollHashedRwSet.CollectionName,
+				HashedRwSet:    protoHashedRwSet,
+				PvtRwSetHash:   protoCollHashedRwSet.PvtRwsetHash,
+			})
+		}
 		txRwSet.NsRwSets = append(txRwSet.NsRwSets, nsRwSet)
 	}
 	return nil
 }
 
+// ToProtoMsg transforms the struct into equivalent proto message
+func (txPvtRwSet *TxPvtRwSet) ToProtoMsg() (*rwset.TxPvtReadWriteSet, error) {
+	protoTxPvtRwSet := &rwset.TxPvtReadWriteSet{DataModel: rwset.TxReadWriteSet_KV}
+	for _, nsPvtRwSet := range txPvtRwSet.NsPvtRwSet {
+		protoNsPvtRwSet := &rwset.NsPvtReadWriteSet{Namespace: nsPvtRwSet.NameSpace}
+		for _, collPvtRwSet := range nsPvtRwSet.CollPvtRwSets {
+			protoRwSetBytes, err := proto.Marshal(collPvtRwSet.KvRwSet)
+			if err != nil {
+				return nil, err
+			}
+			protoNsPvtRwSet.CollectionPvtRwset = append(protoNsPvtRwSet.CollectionPvtRwset,
+				&rwset.CollectionPvtReadWriteSet{CollectionName: collPvtRwSet.CollectionName, Rwset: protoRwSetBytes})
+		}
+		protoTxPvtRwSet.NsPvtRwset = append(protoTxPvtRwSet.NsPvtRwset, protoNsPvtRwSet)
+	}
+	return protoTxPvtRwSet, nil
+}
+
+// FromProtoMsg transforms the proto message into a struct for ease of use
+func (txPvtRwSet *TxPvtRwSet) FromProtoMsg(protoTxPvtRwSet *rwset.TxPvtReadWriteSet) error {
+	for _, protoNsPvtRwSet := range protoTxPvtRwSet.NsPvtRwset {
+		nsPvtRwSet := &NsPvtRwSet{NameSpace: protoNsPvtRwSet.Namespace}
+		for _, protoCollPvtRwSet := range protoNsPvtRwSet.CollectionPvtRwset {
+			protoKvRwSet := &kvrwset.KVRWSet{}
+			if err := proto.Unmarshal(protoCollPvtRwSet.Rwset, protoKvRwSet); err != nil {
+				return err
+			}
+			nsPvtRwSet.CollPvtRwSets = append(nsPvtRwSet.CollPvtRwSets,
+				&CollPvtRwSet{CollectionName: protoCollPvtRwSet.CollectionName, KvRwSet: protoKvRwSet})
+		}
+		txPvtRwSet.NsPvtRwSet = append(txPvtRwSet.NsPvtRwSet, nsPvtRwSet)
+	}
+	return nil
+}
+
 // NewKVRead helps constructing proto message kvrwset.KVRead
 func NewKVRead(key string, version *version.Height) *kvrwset.KVRead {
 	return &kvrwset.KVRead{Key: key, Version: newProtoVersion(version)}
@@ -101,3 +186,30 @@ func newProtoVersion(height *version.Height) *kvrwset.Version {
 func newKVWrite(key string, value []byte) *kvrwset.KVWrite {
 	return &kvrwset.KVWrite{Key: key, IsDelete: value == nil, Value: value}
 }
+
+func newKVMetadataWrite(key string, metadata map[string][]byte) *kvrwset.KVMetadataWrite {
+	return &kvrwset.KVMetadataWrite{Key: key, Entries: NewMetadataEntries(metadata)}
+}
+
+// NewMetadataEntries converts the metadata of a key into a list of entries sorted by name
+func NewMetadataEntries(metadata map[string][]byte) []*kvrwset.KVMetadataEntry {
+	var entries []*kvrwset.KVMetadataEntry
+	for _, name := range util.GetSortedKeys(metadata) {
+		entries = append(entries, &kvrwset.KVMetadataEntry{Name: name, Value: metadata[name]})
+	}
+	return entries
+}
+
+func newPvtKVReadHash(key string, version *version.Height) *kvrwset.KVReadHash {
+	return &kvrwset.KVReadHash{KeyHash: util.ComputeStringHash(key), Version: newProtoVersion(version)}
+}
+
+func newPvtKVWriteAndHash(key string, value []byte) (*kvrwset.KVWrite, *kvrwset.KVWriteHash) {
+	kvWrite := newKVWrite(key, value)
+	var keyHash, valueHash []byte
+	keyHash = util.ComputeStringHash(key)
+	if !kvWrite.IsDelete {
+		valueHash = util.ComputeHash(value)
+	}
+	return kvWrite, &kvrwset.KVWriteHash{KeyHash: keyHash, IsDelete: kvWrite.IsDelete, ValueHash: valueHash}
+}
@@ -42,19 +42,22 @@ func TestTxRWSetMarshalUnmarshal(t *testing.T) {
 			[]*kvrwset.KVRead{&kvrwset.KVRead{Key: "key1", Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
 			[]*kvrwset.RangeQueryInfo{rqi1},
 			[]*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key2", IsDelete: false, Value: []byte("value2")}},
-		}},
+			nil,
+		}, nil},
 
 		&NsRwSet{"ns2", &kvrwset.KVRWSet{
 			[]*kvrwset.KVRead{&kvrwset.KVRead{Key: "key3", Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
 			[]*kvrwset.RangeQueryInfo{rqi2},
 			[]*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key3", IsDelete: false, Value: []byte("value3")}},
-		}},
+			nil,
+		}, nil},
 
 		&NsRwSet{"ns3", &kvrwset.KVRWSet{
 			[]*kvrwset.KVRead{&kvrwset.KVRead{Key: "key4", Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
 			nil,
 			[]*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key4", IsDelete: false, Value: []byte("value4")}},
-		}},
+			nil,
+		}, nil},
 	}
 
 	protoBytes, err := txRwSet.ToProtoBytes()
@@ -65,6 +68,41 @@ func TestTxRWSetMarshalUnmarshal(t *testing.T) {
 	testutil.AssertEquals(t, txRwSet1, txRwSet)
 }
 
+func TestTxRWSetWithCollHashedRWSetMarshalUnmarshal(t *testing.T) {
+	txRwSet := &TxRwSet{}
+	txRwSet.NsRwSets = []*NsRwSet{
+		{"ns1", &kvrwset.KVRWSet{
+			Writes: []*kvrwset.KVWrite{{Key: "key1", Value: []byte("value1")}},
+		}, []*CollHashedRwSet{
+			{"coll1", &kvrwset.HashedRWSet{
+				HashedReads:  []*kvrwset.KVReadHash{{KeyHash: []byte("keyHash1"), Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
+				HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("keyHash2"), ValueHash: []byte("valueHash2")}},
+			}, []byte("pvtRwSetHash1")},
+		}},
+	}
+
+	protoBytes, err := txRwSet.ToProtoBytes()
+	testutil.AssertNoError(t, err, "")
+	txRwSet1 := &TxRwSet{}
+	testutil.AssertNoError(t, txRwSet1.FromProtoBytes(protoBytes), "")
+	testutil.AssertEquals(t, txRwSet1, txRwSet)
+}
+
+func TestTxPvtRWSetConversion(t *testing.T) {
+	txPvtRwSet := &TxPvtRwSet{[]*NsPvtRwSet{
+		{"ns1", []*CollPvtRwSet{
+			{"coll1", &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "key1", Value: []byte("value1")}}}},
+			{"coll2", &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "key2", IsDelete: true}}}},
+		}},
+	}}
+
+	protoMsg, err := txPvtRwSet.ToProtoMsg()
+	testutil.AssertNoError(t, err, "")
+	txPvtRwSet1 := &TxPvtRwSet{}
+	testutil.AssertNoError(t, txPvtRwSet1.FromProtoMsg(protoMsg), "")
+	testutil.AssertEquals(t, txPvtRwSet1, txPvtRwSet)
+}
+
 func TestVersionConversion(t *testing.T) {
 	protoVer := &kvrwset.Version{BlockNum: 5, TxNum: 2}
 	internalVer := version.NewHeight(5, 2)
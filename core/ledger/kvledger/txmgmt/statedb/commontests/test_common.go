@@ -132,6 +132,45 @@ func TestMultiDBBasicRW(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	testutil.AssertEquals(t, sp, savePoint2)
 }
 
+// TestDrop tests the removal of all the data of a db
+func TestDrop(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db1, err := dbProvider.GetDBHandle("testdrop")
+	testutil.AssertNoError(t, err, "")
+	db2, err := dbProvider.GetDBHandle("testdrop2")
+	testutil.AssertNoError(t, err, "")
+
+	batch := statedb.NewUpdateBatch()
+	vv1 := statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}
+	batch.Put("ns1", "key1", vv1.Value, vv1.Version)
+	batch.Put("ns2", "key2", []byte("value2"), version.NewHeight(1, 2))
+	savePoint := version.NewHeight(1, 2)
+	db1.ApplyUpdates(batch, savePoint)
+	db2.ApplyUpdates(batch, savePoint)
+
+	testutil.AssertNoError(t, dbProvider.Drop("testdrop"), "")
+	db1, err = dbProvider.GetDBHandle("testdrop")
+	testutil.AssertNoError(t, err, "")
+	vv, _ := db1.GetState("ns1", "key1")
+	testutil.AssertNil(t, vv)
+	vv, _ = db1.GetState("ns2", "key2")
+	testutil.AssertNil(t, vv)
+	sp, err := db1.GetLatestSavePoint()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, sp)
+
+	// the other dbs are not affected
+	vv, _ = db2.GetState("ns1", "key1")
+	testutil.AssertEquals(t, vv, &vv1)
+	sp, err = db2.GetLatestSavePoint()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, sp, savePoint)
+
+	// a dropped db can be written again
+	db1.ApplyUpdates(batch, savePoint)
+	vv, _ = db1.GetState("ns1", "key1")
+	testutil.AssertEquals(t, vv, &vv1)
+}
+
 // TestDeletes tests deteles
 func TestDeletes(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	db, err := dbProvider.GetDBHandle("testdeletes")
@@ -197,6 +236,52 @@ func TestIterator(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	testItr(t, itr4, []string{"key5", "key6"})
 }
 
+// TestPaginatedRangeQuery tests the range query with pagination
+func TestPaginatedRangeQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("testpaginatedrangequery")
+	testutil.AssertNoError(t, err, "")
+	db.Open()
+	defer db.Close()
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns1", "key3", []byte("value3"), version.NewHeight(1, 3))
+	batch.Put("ns1", "key4", []byte("value4"), version.NewHeight(1, 4))
+	batch.Put("ns1", "key5", []byte("value5"), version.NewHeight(1, 5))
+	batch.Put("ns2", "key6", []byte("value6"), version.NewHeight(1, 6))
+	savePoint := version.NewHeight(2, 6)
+	db.ApplyUpdates(batch, savePoint)
+
+	itr, err := db.GetStateRangeScanIteratorWithPagination("ns1", "", "", 2)
+	testutil.AssertNoError(t, err, "")
+	testPaginatedItr(t, itr, []string{"key1", "key2"}, "key3")
+
+	itr, err = db.GetStateRangeScanIteratorWithPagination("ns1", "key3", "", 2)
+	testutil.AssertNoError(t, err, "")
+	testPaginatedItr(t, itr, []string{"key3", "key4"}, "key5")
+
+	itr, err = db.GetStateRangeScanIteratorWithPagination("ns1", "key5", "", 2)
+	testutil.AssertNoError(t, err, "")
+	testPaginatedItr(t, itr, []string{"key5"}, "")
+
+	// the end key is exclusive, so no further page is left
+	itr, err = db.GetStateRangeScanIteratorWithPagination("ns1", "key2", "key4", 2)
+	testutil.AssertNoError(t, err, "")
+	testPaginatedItr(t, itr, []string{"key2", "key3"}, "")
+}
+
+func testPaginatedItr(t *testing.T, itr statedb.QueryResultsIterator, expectedKeys []string, expectedBookmark string) {
+	for _, expectedKey := range expectedKeys {
+		queryResult, err := itr.Next()
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, queryResult.(*statedb.VersionedKV).Key, expectedKey)
+	}
+	last, err := itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, last)
+	testutil.AssertEquals(t, itr.GetBookmarkAndClose(), expectedBookmark)
+}
+
 func testItr(t *testing.T, itr statedb.ResultsIterator, expectedKeys []string) {
 	defer itr.Close()
 	for _, expectedKey := range expectedKeys {
@@ -29,6 +29,9 @@ const jsonQuerySelector = "selector"
 const jsonQueryUseIndex = "use_index"
 const jsonQueryLimit = "limit"
 const jsonQuerySkip = "skip"
+const jsonQueryBookmark = "bookmark"
+const jsonIndexDefinition = "index"
+const jsonIndexPartialFilterSelector = "partial_filter_selector"
 
 var validOperators = []string{"$and", "$or", "$not", "$nor", "$all", "$elemMatch",
 	"$lt", "$lte", "$eq", "$ne", "$gte", "$gt", "$exits", "$type", "$in", "$nin",
@@ -62,6 +65,12 @@ Result Wrapped Query:
 
 */
 func ApplyQueryWrapper(namespace, queryString string, queryLimit, querySkip int) (string, error) {
+	return ApplyQueryWrapperWithBookmark(namespace, queryString, queryLimit, querySkip, "")
+}
+
+// ApplyQueryWrapperWithBookmark works like ApplyQueryWrapper and additionally sets
+// the bookmark returned by a previous execution of the query, for fetching the next page
+func ApplyQueryWrapperWithBookmark(namespace, queryString string, queryLimit, querySkip int, bookmark string) (string, error) {
 
 	//create a generic map for the query json
 	jsonQueryMap := make(map[string]interface{})
@@ -103,6 +112,11 @@ func ApplyQueryWrapper(namespace, queryString string, queryLimit, querySkip int)
 	//Add skip
 	jsonQueryMap[jsonQuerySkip] = querySkip
 
+	//Add bookmark
+	if bookmark != "" {
+		jsonQueryMap[jsonQueryBookmark] = bookmark
+	}
+
 	//Marshal the updated json query
 	editedQuery, _ := json.Marshal(jsonQueryMap)
 
@@ -112,6 +126,82 @@ func ApplyQueryWrapper(namespace, queryString string, queryLimit, querySkip int)
 
 }
 
+/*
+ApplyIndexWrapper parses an index definition packaged with a chaincode and prepends
+the wrapper "data." to all fields of the index, including the fields of a partial
+filter selector, so that the index applies to the values stored by the chaincode.
+The field "chaincodeid", maintained by the state database, is not wrapped, so that
+it can be part of the index for the namespace filter added to the queries
+
+Example:
+
+Source Index Definition:
+{"index":{"fields":["chaincodeid","docType","owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}
+
+Result Wrapped Index Definition:
+{"ddoc":"indexOwnerDoc","index":{"fields":["chaincodeid","data.docType","data.owner"]},"name":"indexOwner","type":"json"}
+
+*/
+func ApplyIndexWrapper(indexDefinition string) (string, error) {
+
+	//create a generic map for the index definition
+	jsonIndexMap := make(map[string]interface{})
+
+	//unmarshal the index definition into the generic map
+	decoder := json.NewDecoder(bytes.NewBuffer([]byte(indexDefinition)))
+	decoder.UseNumber()
+	err := decoder.Decode(&jsonIndexMap)
+	if err != nil {
+		return "", err
+	}
+
+	jsonValue, ok := jsonIndexMap[jsonIndexDefinition]
+	if !ok {
+		return "", fmt.Errorf("index definition does not contain the \"%s\" field", jsonIndexDefinition)
+	}
+	indexFields, ok := jsonValue.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("the \"%s\" field of the index definition is not a JSON object", jsonIndexDefinition)
+	}
+
+	//wrap the fields of the index, which are either field names or field names with a sort order
+	if fields, ok := indexFields[jsonQueryFields].([]interface{}); ok {
+		for i, field := range fields {
+			switch fieldValue := field.(type) {
+			case string:
+				fields[i] = wrapIndexFieldName(fieldValue)
+			case map[string]interface{}:
+				wrappedField := make(map[string]interface{})
+				for fieldName, sortOrder := range fieldValue {
+					wrappedField[wrapIndexFieldName(fieldName)] = sortOrder
+				}
+				fields[i] = wrappedField
+			}
+		}
+	}
+
+	//wrap the fields of the partial filter selector, if any
+	if selector, ok := indexFields[jsonIndexPartialFilterSelector].(map[string]interface{}); ok {
+		processInterfaceMap(selector)
+	}
+
+	//Marshal the updated index definition
+	editedIndex, _ := json.Marshal(jsonIndexMap)
+
+	logger.Debugf("Rewritten index definition with data wrapper: %s", editedIndex)
+
+	return string(editedIndex), nil
+}
+
+//wrapIndexFieldName prepends the data wrapper to the name of a field of an index,
+//unless it is the chaincodeid field maintained by the state database
+func wrapIndexFieldName(fieldName string) string {
+	if fieldName == "chaincodeid" {
+		return fieldName
+	}
+	return fmt.Sprintf("%v.%v", dataWrapper, fieldName)
+}
+
 //setNamespaceInSelector adds an additional hierarchy in the "selector"
 //{"owner": {"$eq": "tom"}}
 //would be mapped as (assuming a namespace of "marble"):
@@ -381,3 +381,52 @@ func TestQueryWithLargeInteger(t *testing.T) {
 	testutil.AssertEquals(t, strings.Count(wrappedQuery, "{\"$eq\":1000007}"), 1)
 
 }
+
+//TestQueryWithBookmark tests query with the bookmark of a paginated query
+func TestQueryWithBookmark(t *testing.T) {
+
+	rawQuery := []byte(`{"selector":{"owner":{"$eq":"jerry"}}}`)
+
+	wrappedQuery, err := ApplyQueryWrapperWithBookmark("ns1", string(rawQuery), 5, 0, "g1AAAABteJ")
+
+	//Make sure the query did not throw an exception
+	testutil.AssertNoError(t, err, "Unexpected error thrown when for query JSON")
+
+	//check to make sure the bookmark and the page size are added
+	testutil.AssertEquals(t, strings.Count(wrappedQuery, "\"bookmark\":\"g1AAAABteJ\""), 1)
+	testutil.AssertEquals(t, strings.Count(wrappedQuery, "\"limit\":5"), 1)
+
+	//no bookmark is added if it is empty
+	wrappedQuery, err = ApplyQueryWrapperWithBookmark("ns1", string(rawQuery), 5, 0, "")
+	testutil.AssertNoError(t, err, "Unexpected error thrown when for query JSON")
+	testutil.AssertEquals(t, strings.Count(wrappedQuery, "\"bookmark\""), 0)
+
+}
+
+//TestIndexWrapper tests the wrapping of the fields of an index definition
+func TestIndexWrapper(t *testing.T) {
+
+	rawIndex := `{"index":{"fields":["chaincodeid","owner",{"size":"desc"}],"partial_filter_selector":{"color":{"$eq":"blue"}}},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`
+
+	wrappedIndex, err := ApplyIndexWrapper(rawIndex)
+
+	//Make sure the index definition did not throw an exception
+	testutil.AssertNoError(t, err, "Unexpected error thrown when for index JSON")
+
+	//check to make sure the fields are wrapped
+	testutil.AssertEquals(t, strings.Count(wrappedIndex, "\"fields\":[\"chaincodeid\",\"data.owner\",{\"data.size\":\"desc\"}]"), 1)
+	testutil.AssertEquals(t, strings.Count(wrappedIndex, "\"data.color\":{\"$eq\":\"blue\"}"), 1)
+
+	//check to make sure the index names are kept
+	testutil.AssertEquals(t, strings.Count(wrappedIndex, "\"ddoc\":\"indexOwnerDoc\""), 1)
+	testutil.AssertEquals(t, strings.Count(wrappedIndex, "\"name\":\"indexOwner\""), 1)
+
+	//an index definition without the index field is rejected
+	_, err = ApplyIndexWrapper(`{"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`)
+	testutil.AssertError(t, err, "Expected an error for an index definition without fields")
+
+	//an invalid JSON is rejected
+	_, err = ApplyIndexWrapper(`{"index":`)
+	testutil.AssertError(t, err, "Expected an error for an invalid index definition")
+
+}
@@ -18,9 +18,11 @@ package statecouchdb
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -40,6 +42,9 @@ var lastKeyIndicator = byte(0x01)
 
 var binaryWrapper = "valueBytes"
 
+//metadataField is the field of the document which holds the metadata of the key
+var metadataField = "~metadata"
+
 //querySkip is implemented for future use by query paging
 //currently defaulted to 0 and is not used
 var querySkip = 0
@@ -82,6 +87,22 @@ func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.Version
 	return vdb, nil
 }
 
+// Drop drops the couch database of a named database, which is created anew when its handle is requested next
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+
+	db, err := couchdb.CreateCouchDatabase(*provider.couchInstance, dbName)
+	if err != nil {
+		return err
+	}
+	if _, err = db.DropDatabase(); err != nil {
+		return err
+	}
+	delete(provider.databases, dbName)
+	return nil
+}
+
 // Close closes the underlying db instance
 func (provider *VersionedDBProvider) Close() {
 	// No close needed on Couch
@@ -137,12 +158,12 @@ func (vdb *VersionedDB) GetState(namespace string, key string) (*statedb.Version
 	}
 
 	//remove the data wrapper and return the value and version
-	returnValue, returnVersion := removeDataWrapper(couchDoc.JSONValue, couchDoc.Attachments)
+	returnValue, returnMetadata, returnVersion := removeDataWrapper(couchDoc.JSONValue, couchDoc.Attachments)
 
-	return &statedb.VersionedValue{Value: returnValue, Version: &returnVersion}, nil
+	return &statedb.VersionedValue{Value: returnValue, Metadata: returnMetadata, Version: &returnVersion}, nil
 }
 
-func removeDataWrapper(wrappedValue []byte, attachments []*couchdb.Attachment) ([]byte, version.Height) {
+func removeDataWrapper(wrappedValue []byte, attachments []*couchdb.Attachment) ([]byte, []byte, version.Height) {
 
 	//initialize the return value
 	returnValue := []byte{}
@@ -187,7 +208,13 @@ func removeDataWrapper(wrappedValue []byte, attachments []*couchdb.Attachment) (
 	//create the version based on the blockNum and txNum
 	returnVersion = version.NewHeight(blockNum, txNum)
 
-	return returnValue, *returnVersion
+	//the metadata of the key is kept base64 encoded
+	var returnMetadata []byte
+	if encodedMetadata, ok := jsonResult[metadataField].(string); ok {
+		returnMetadata, _ = base64.StdEncoding.DecodeString(encodedMetadata)
+	}
+
+	return returnValue, returnMetadata, *returnVersion
 
 }
 
@@ -214,40 +241,101 @@ func (vdb *VersionedDB) GetStateRangeScanIterator(namespace string, startKey str
 	//Get the querylimit from core.yaml
 	queryLimit := ledgerconfig.GetQueryLimit()
 
-	compositeStartKey := constructCompositeKey(namespace, startKey)
-	compositeEndKey := constructCompositeKey(namespace, endKey)
-	if endKey == "" {
-		compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
-	}
+	compositeStartKey, compositeEndKey := constructRangeKeys(namespace, startKey, endKey)
 	queryResult, err := vdb.db.ReadDocRange(string(compositeStartKey), string(compositeEndKey), queryLimit, querySkip)
 	if err != nil {
 		logger.Debugf("Error calling ReadDocRange(): %s\n", err.Error())
 		return nil, err
 	}
 	logger.Debugf("Exiting GetStateRangeScanIterator")
-	return newKVScanner(namespace, *queryResult), nil
+	return newKVScanner(namespace, *queryResult, ""), nil
 
 }
 
+// GetStateRangeScanIteratorWithPagination implements method in VersionedDB interface
+// One more document than pageSize is read so that its key can be returned as the bookmark
+func (vdb *VersionedDB) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (statedb.QueryResultsIterator, error) {
+
+	compositeStartKey, compositeEndKey := constructRangeKeys(namespace, startKey, endKey)
+	queryResult, err := vdb.db.ReadDocRange(string(compositeStartKey), string(compositeEndKey), int(pageSize)+1, querySkip)
+	if err != nil {
+		logger.Debugf("Error calling ReadDocRange(): %s\n", err.Error())
+		return nil, err
+	}
+
+	results := *queryResult
+	bookmark := ""
+	if len(results) > int(pageSize) {
+		_, bookmark = splitCompositeKey([]byte(results[pageSize].ID))
+		results = results[:pageSize]
+	}
+	logger.Debugf("Exiting GetStateRangeScanIteratorWithPagination")
+	return newKVScanner(namespace, results, bookmark), nil
+}
+
 // ExecuteQuery implements method in VersionedDB interface
 func (vdb *VersionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
 
 	//Get the querylimit from core.yaml
 	queryLimit := ledgerconfig.GetQueryLimit()
 
-	queryString, err := ApplyQueryWrapper(namespace, query, queryLimit, 0)
+	return vdb.executeQuery(namespace, query, "", queryLimit)
+}
+
+// ExecuteQueryWithPagination implements method in VersionedDB interface
+func (vdb *VersionedDB) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	return vdb.executeQuery(namespace, query, bookmark, int(pageSize))
+}
+
+func (vdb *VersionedDB) executeQuery(namespace, query, bookmark string, limit int) (*queryScanner, error) {
+
+	queryString, err := ApplyQueryWrapperWithBookmark(namespace, query, limit, 0, bookmark)
 	if err != nil {
 		logger.Debugf("Error calling ApplyQueryWrapper(): %s\n", err.Error())
 		return nil, err
 	}
 
-	queryResult, err := vdb.db.QueryDocuments(queryString)
+	queryResult, nextBookmark, err := vdb.db.QueryDocumentsWithBookmark(queryString)
 	if err != nil {
 		logger.Debugf("Error calling QueryDocuments(): %s\n", err.Error())
 		return nil, err
 	}
 	logger.Debugf("Exiting ExecuteQuery")
-	return newQueryScanner(*queryResult), nil
+	return newQueryScanner(*queryResult, nextBookmark), nil
+}
+
+// GetDBType implements method in statedb.IndexCapable interface
+func (vdb *VersionedDB) GetDBType() string {
+	return "couchdb"
+}
+
+// ProcessIndexesForChaincodeDeploy implements method in statedb.IndexCapable interface.
+// An invalid index definition is logged and skipped, so that it does not prevent the
+// creation of the other indexes of the chaincode
+func (vdb *VersionedDB) ProcessIndexesForChaincodeDeploy(namespace string, indexFiles map[string][]byte) error {
+	fileNames := make([]string, 0, len(indexFiles))
+	for fileName := range indexFiles {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	for _, fileName := range fileNames {
+		indexDefinition, err := ApplyIndexWrapper(string(indexFiles[fileName]))
+		if err != nil {
+			logger.Errorf("Invalid index definition in file [%s] for chaincode [%s] on database [%s]: %s",
+				fileName, namespace, vdb.dbName, err)
+			continue
+		}
+		resp, err := vdb.db.CreateIndex(indexDefinition)
+		if err != nil {
+			logger.Errorf("Error creating index from file [%s] for chaincode [%s] on database [%s]: %s",
+				fileName, namespace, vdb.dbName, err)
+			continue
+		}
+		logger.Infof("Index [%s] from file [%s] for chaincode [%s] on database [%s]: %s",
+			resp.Name, fileName, namespace, vdb.dbName, resp.Result)
+	}
+	return nil
 }
 
 // ApplyUpdates implements method in VersionedDB interface
@@ -272,7 +360,7 @@ func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 				//If this is not a valid JSON, then store as an attachment
 				if couchdb.IsJSON(string(vv.Value)) {
 					// Handle it as json
-					couchDoc.JSONValue = addVersionAndChainCodeID(vv.Value, ns, vv.Version)
+					couchDoc.JSONValue = addVersionAndChainCodeID(vv.Value, ns, vv.Version, vv.Metadata)
 				} else { // if the data is not JSON, save as binary attachment in Couch
 
 					attachment := &couchdb.Attachment{}
@@ -282,7 +370,7 @@ func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 					attachments := append([]*couchdb.Attachment{}, attachment)
 
 					couchDoc.Attachments = attachments
-					couchDoc.JSONValue = addVersionAndChainCodeID(nil, ns, vv.Version)
+					couchDoc.JSONValue = addVersionAndChainCodeID(nil, ns, vv.Version, vv.Metadata)
 				}
 
 				// SaveDoc using couchdb client and use attachment to persist the binary data
@@ -308,8 +396,8 @@ func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 	return nil
 }
 
-//addVersionAndChainCodeID adds keys for version and chaincodeID to the JSON value
-func addVersionAndChainCodeID(value []byte, chaincodeID string, version *version.Height) []byte {
+//addVersionAndChainCodeID adds keys for version, chaincodeID and metadata to the JSON value
+func addVersionAndChainCodeID(value []byte, chaincodeID string, version *version.Height, metadata []byte) []byte {
 
 	//create a version mapping
 	jsonMap := map[string]interface{}{"version": fmt.Sprintf("%v:%v", version.BlockNum, version.TxNum)}
@@ -317,6 +405,11 @@ func addVersionAndChainCodeID(value []byte, chaincodeID string, version *version
 	//add the chaincodeID
 	jsonMap["chaincodeid"] = chaincodeID
 
+	//add the metadata of the key, if any
+	if metadata != nil {
+		jsonMap[metadataField] = base64.StdEncoding.EncodeToString(metadata)
+	}
+
 	//Add the wrapped data if the value is not null
 	if value != nil {
 
@@ -419,6 +512,17 @@ func constructCompositeKey(ns string, key string) []byte {
 	return compositeKey
 }
 
+// constructRangeKeys returns the composite start and end keys of a range query;
+// an empty endKey refers to the last key of the namespace
+func constructRangeKeys(ns string, startKey string, endKey string) ([]byte, []byte) {
+	compositeStartKey := constructCompositeKey(ns, startKey)
+	compositeEndKey := constructCompositeKey(ns, endKey)
+	if endKey == "" {
+		compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
+	}
+	return compositeStartKey, compositeEndKey
+}
+
 func splitCompositeKey(compositeKey []byte) (string, string) {
 	split := bytes.SplitN(compositeKey, compositeKeySep, 2)
 	return string(split[0]), string(split[1])
@@ -428,10 +532,11 @@ type kvScanner struct {
 	cursor    int
 	namespace string
 	results   []couchdb.QueryResult
+	bookmark  string
 }
 
-func newKVScanner(namespace string, queryResults []couchdb.QueryResult) *kvScanner {
-	return &kvScanner{-1, namespace, queryResults}
+func newKVScanner(namespace string, queryResults []couchdb.QueryResult, bookmark string) *kvScanner {
+	return &kvScanner{-1, namespace, queryResults, bookmark}
 }
 
 func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
@@ -447,24 +552,32 @@ func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
 	_, key := splitCompositeKey([]byte(selectedKV.ID))
 
 	//remove the data wrapper and return the value and version
-	returnValue, returnVersion := removeDataWrapper(selectedKV.Value, selectedKV.Attachments)
+	returnValue, returnMetadata, returnVersion := removeDataWrapper(selectedKV.Value, selectedKV.Attachments)
 
 	return &statedb.VersionedKV{
 		CompositeKey:   statedb.CompositeKey{Namespace: scanner.namespace, Key: key},
-		VersionedValue: statedb.VersionedValue{Value: returnValue, Version: &returnVersion}}, nil
+		VersionedValue: statedb.VersionedValue{Value: returnValue, Metadata: returnMetadata, Version: &returnVersion}}, nil
 }
 
 func (scanner *kvScanner) Close() {
 	scanner = nil
 }
 
+// GetBookmarkAndClose returns the startKey of the next page of the range
+func (scanner *kvScanner) GetBookmarkAndClose() string {
+	bookmark := scanner.bookmark
+	scanner.Close()
+	return bookmark
+}
+
 type queryScanner struct {
-	cursor  int
-	results []couchdb.QueryResult
+	cursor   int
+	results  []couchdb.QueryResult
+	bookmark string
 }
 
-func newQueryScanner(queryResults []couchdb.QueryResult) *queryScanner {
-	return &queryScanner{-1, queryResults}
+func newQueryScanner(queryResults []couchdb.QueryResult, bookmark string) *queryScanner {
+	return &queryScanner{-1, queryResults, bookmark}
 }
 
 func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
@@ -480,13 +593,20 @@ func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
 	namespace, key := splitCompositeKey([]byte(selectedResultRecord.ID))
 
 	//remove the data wrapper and return the value and version
-	returnValue, returnVersion := removeDataWrapper(selectedResultRecord.Value, selectedResultRecord.Attachments)
+	returnValue, returnMetadata, returnVersion := removeDataWrapper(selectedResultRecord.Value, selectedResultRecord.Attachments)
 
 	return &statedb.VersionedKV{
 		CompositeKey:   statedb.CompositeKey{Namespace: namespace, Key: key},
-		VersionedValue: statedb.VersionedValue{Value: returnValue, Version: &returnVersion}}, nil
+		VersionedValue: statedb.VersionedValue{Value: returnValue, Metadata: returnMetadata, Version: &returnVersion}}, nil
 }
 
 func (scanner *queryScanner) Close() {
 	scanner = nil
 }
+
+// GetBookmarkAndClose returns the bookmark returned by CouchDB for the query
+func (scanner *queryScanner) GetBookmarkAndClose() string {
+	bookmark := scanner.bookmark
+	scanner.Close()
+	return bookmark
+}
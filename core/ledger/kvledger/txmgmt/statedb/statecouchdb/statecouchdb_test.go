@@ -81,6 +81,17 @@ func TestMultiDBBasicRW(t *testing.T) {
 	}
 }
 
+func TestDrop(t *testing.T) {
+	if ledgerconfig.IsCouchDBEnabled() == true {
+		env := NewTestVDBEnv(t)
+		env.Cleanup("testdrop")
+		env.Cleanup("testdrop2")
+		defer env.Cleanup("testdrop")
+		defer env.Cleanup("testdrop2")
+		commontests.TestDrop(t, env.DBProvider)
+	}
+}
+
 func TestDeletes(t *testing.T) {
 	if ledgerconfig.IsCouchDBEnabled() == true {
 		env := NewTestVDBEnv(t)
@@ -101,6 +112,17 @@ func TestIterator(t *testing.T) {
 	}
 }
 
+func TestPaginatedRangeQuery(t *testing.T) {
+	if ledgerconfig.IsCouchDBEnabled() == true {
+
+		env := NewTestVDBEnv(t)
+		env.Cleanup("testpaginatedrangequery")
+		defer env.Cleanup("testpaginatedrangequery")
+		commontests.TestPaginatedRangeQuery(t, env.DBProvider)
+
+	}
+}
+
 func TestEncodeDecodeValueAndVersion(t *testing.T) {
 	testValueAndVersionEncoding(t, []byte("value1"), version.NewHeight(1, 2))
 	testValueAndVersionEncoding(t, []byte{}, version.NewHeight(50, 50))
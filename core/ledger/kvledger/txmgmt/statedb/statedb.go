@@ -27,6 +27,8 @@ import (
 type VersionedDBProvider interface {
 	// GetDBHandle returns a handle to a VersionedDB
 	GetDBHandle(id string) (VersionedDB, error)
+	// Drop removes all the data of the VersionedDB with the given id. The VersionedDB must not be in use
+	Drop(id string) error
 	// Close closes all the VersionedDB instances and releases any resources held by VersionedDBProvider
 	Close()
 }
@@ -44,6 +46,13 @@ type VersionedDB interface {
 	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (ResultsIterator, error)
 	// ExecuteQuery executes the given query and returns an iterator that contains results of type *VersionedKV.
 	ExecuteQuery(namespace, query string) (ResultsIterator, error)
+	// GetStateRangeScanIteratorWithPagination returns an iterator that contains at most pageSize key-values
+	// between given key ranges, starting at startKey. The bookmark returned by the iterator is the startKey
+	// to be used for fetching the next page and is empty if there are no more results
+	GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (QueryResultsIterator, error)
+	// ExecuteQueryWithPagination executes the given query and returns an iterator that contains at most pageSize
+	// results of type *VersionedKV, resuming after the given bookmark (if any) of a previous execution of the query
+	ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (QueryResultsIterator, error)
 	// ApplyUpdates applies the batch to the underlying db.
 	// height is the height of the highest transaction in the Batch that
 	// a state db implementation is expected to ues as a save point
@@ -66,10 +75,12 @@ type CompositeKey struct {
 	Key       string
 }
 
-// VersionedValue encloses value and corresponding version
+// VersionedValue encloses value and corresponding version.
+// Metadata holds the serialized metadata entries of the key, if any
 type VersionedValue struct {
-	Value   []byte
-	Version *version.Height
+	Value    []byte
+	Version  *version.Height
+	Metadata []byte
 }
 
 // VersionedKV encloses key and corresponding VersionedValue
@@ -84,6 +95,31 @@ type ResultsIterator interface {
 	Close()
 }
 
+// IndexCapable is implemented by the VersionedDB implementations which support the creation of
+// indexes from the index definitions packaged with a chaincode (META-INF/statedb/<db type>/indexes)
+type IndexCapable interface {
+	// GetDBType returns the name of the state database, as used in the chaincode package
+	GetDBType() string
+	// ProcessIndexesForChaincodeDeploy creates the indexes of the given namespace
+	// from the index definitions, which are keyed by their file name
+	ProcessIndexesForChaincodeDeploy(namespace string, indexFiles map[string][]byte) error
+}
+
+// FullScanCapable is implemented by the VersionedDB implementations which can iterate over the keys of all
+// the namespaces, as needed for exporting a snapshot of the state
+type FullScanCapable interface {
+	// GetFullScanIterator returns an iterator over all the keys of all the namespaces, ordered by namespace and key.
+	// The returned ResultsIterator contains results of type *VersionedKV
+	GetFullScanIterator() (ResultsIterator, error)
+}
+
+// QueryResultsIterator adds support for paginated queries to ResultsIterator
+type QueryResultsIterator interface {
+	ResultsIterator
+	// GetBookmarkAndClose returns the bookmark for fetching the next page of results and closes the iterator
+	GetBookmarkAndClose() string
+}
+
 // QueryResult - a general interface for supporting different types of query results. Actual types differ for different queries
 type QueryResult interface{}
 
@@ -120,17 +156,22 @@ func (batch *UpdateBatch) Get(ns string, key string) *VersionedValue {
 
 // Put adds a VersionedKV
 func (batch *UpdateBatch) Put(ns string, key string, value []byte, version *version.Height) {
+	batch.PutValAndMetadata(ns, key, value, nil, version)
+}
+
+// PutValAndMetadata adds a VersionedKV along with the serialized metadata of the key
+func (batch *UpdateBatch) PutValAndMetadata(ns string, key string, value []byte, metadata []byte, version *version.Height) {
 	if value == nil {
 		panic("Nil value not allowed")
 	}
 	nsUpdates := batch.getOrCreateNsUpdates(ns)
-	nsUpdates.m[key] = &VersionedValue{value, version}
+	nsUpdates.m[key] = &VersionedValue{Value: value, Metadata: metadata, Version: version}
 }
 
 // Delete deletes a Key and associated value
 func (batch *UpdateBatch) Delete(ns string, key string, version *version.Height) {
 	nsUpdates := batch.getOrCreateNsUpdates(ns)
-	nsUpdates.m[key] = &VersionedValue{nil, version}
+	nsUpdates.m[key] = &VersionedValue{Value: nil, Version: version}
 }
 
 // Exists checks whether the given key exists in the batch
@@ -220,7 +261,7 @@ func (itr *nsIterator) Next() (QueryResult, error) {
 	key := itr.sortedKeys[itr.nextIndex]
 	vv := itr.nsUpdates.m[key]
 	itr.nextIndex++
-	return &VersionedKV{CompositeKey{itr.ns, key}, VersionedValue{vv.Value, vv.Version}}, nil
+	return &VersionedKV{CompositeKey{itr.ns, key}, VersionedValue{Value: vv.Value, Metadata: vv.Metadata, Version: vv.Version}}, nil
 }
 
 // Close implements the method from QueryResult interface
@@ -105,19 +105,19 @@ func TestUpdateBatchIterator(t *testing.T) {
 	batch.Put("ns2", "key4", []byte("value4"), version.NewHeight(2, 1))
 
 	checkItrResults(t, batch.GetRangeScanIterator("ns1", "key2", "key3"), []*VersionedKV{
-		&VersionedKV{CompositeKey{"ns1", "key2"}, VersionedValue{[]byte("value2"), version.NewHeight(1, 2)}},
+		&VersionedKV{CompositeKey{"ns1", "key2"}, VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)}},
 	})
 
 	checkItrResults(t, batch.GetRangeScanIterator("ns2", "key0", "key8"), []*VersionedKV{
-		&VersionedKV{CompositeKey{"ns2", "key4"}, VersionedValue{[]byte("value4"), version.NewHeight(2, 1)}},
-		&VersionedKV{CompositeKey{"ns2", "key5"}, VersionedValue{[]byte("value5"), version.NewHeight(2, 2)}},
-		&VersionedKV{CompositeKey{"ns2", "key6"}, VersionedValue{[]byte("value6"), version.NewHeight(2, 3)}},
+		&VersionedKV{CompositeKey{"ns2", "key4"}, VersionedValue{Value: []byte("value4"), Version: version.NewHeight(2, 1)}},
+		&VersionedKV{CompositeKey{"ns2", "key5"}, VersionedValue{Value: []byte("value5"), Version: version.NewHeight(2, 2)}},
+		&VersionedKV{CompositeKey{"ns2", "key6"}, VersionedValue{Value: []byte("value6"), Version: version.NewHeight(2, 3)}},
 	})
 
 	checkItrResults(t, batch.GetRangeScanIterator("ns2", "", ""), []*VersionedKV{
-		&VersionedKV{CompositeKey{"ns2", "key4"}, VersionedValue{[]byte("value4"), version.NewHeight(2, 1)}},
-		&VersionedKV{CompositeKey{"ns2", "key5"}, VersionedValue{[]byte("value5"), version.NewHeight(2, 2)}},
-		&VersionedKV{CompositeKey{"ns2", "key6"}, VersionedValue{[]byte("value6"), version.NewHeight(2, 3)}},
+		&VersionedKV{CompositeKey{"ns2", "key4"}, VersionedValue{Value: []byte("value4"), Version: version.NewHeight(2, 1)}},
+		&VersionedKV{CompositeKey{"ns2", "key5"}, VersionedValue{Value: []byte("value5"), Version: version.NewHeight(2, 2)}},
+		&VersionedKV{CompositeKey{"ns2", "key6"}, VersionedValue{Value: []byte("value6"), Version: version.NewHeight(2, 3)}},
 	})
 
 	checkItrResults(t, batch.GetRangeScanIterator("non-existing-ns", "", ""), nil)
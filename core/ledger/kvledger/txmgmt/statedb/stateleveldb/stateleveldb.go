@@ -41,7 +41,15 @@ type VersionedDBProvider struct {
 
 // NewVersionedDBProvider instantiates VersionedDBProvider
 func NewVersionedDBProvider() *VersionedDBProvider {
-	dbPath := ledgerconfig.GetStateLevelDBPath()
+	return newVersionedDBProvider(ledgerconfig.GetStateLevelDBPath())
+}
+
+// NewPvtVersionedDBProvider instantiates VersionedDBProvider for maintaining the private state
+func NewPvtVersionedDBProvider() *VersionedDBProvider {
+	return newVersionedDBProvider(ledgerconfig.GetPvtStateLevelDBPath())
+}
+
+func newVersionedDBProvider(dbPath string) *VersionedDBProvider {
 	logger.Debugf("constructing VersionedDBProvider dbPath=%s", dbPath)
 	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
 	return &VersionedDBProvider{dbProvider}
@@ -52,6 +60,11 @@ func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.Version
 	return newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
 }
 
+// Drop removes all the keys of a named database, including its savepoint
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	return provider.dbProvider.GetDBHandle(dbName).DeleteAll()
+}
+
 // Close closes the underlying db
 func (provider *VersionedDBProvider) Close() {
 	provider.dbProvider.Close()
@@ -95,8 +108,8 @@ func (vdb *versionedDB) GetState(namespace string, key string) (*statedb.Version
 	if dbVal == nil {
 		return nil, nil
 	}
-	val, ver := statedb.DecodeValue(dbVal)
-	return &statedb.VersionedValue{Value: val, Version: ver}, nil
+	val, metadata, ver := statedb.DecodeValueAndMetadata(dbVal)
+	return &statedb.VersionedValue{Value: val, Metadata: metadata, Version: ver}, nil
 }
 
 // GetStateMultipleKeys implements method in VersionedDB interface
@@ -116,13 +129,19 @@ func (vdb *versionedDB) GetStateMultipleKeys(namespace string, keys []string) ([
 // startKey is inclusive
 // endKey is exclusive
 func (vdb *versionedDB) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (statedb.ResultsIterator, error) {
+	return vdb.GetStateRangeScanIteratorWithPagination(namespace, startKey, endKey, 0)
+}
+
+// GetStateRangeScanIteratorWithPagination implements method in VersionedDB interface
+// A pageSize of zero returns all the keys in the range
+func (vdb *versionedDB) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (statedb.QueryResultsIterator, error) {
 	compositeStartKey := constructCompositeKey(namespace, startKey)
 	compositeEndKey := constructCompositeKey(namespace, endKey)
 	if endKey == "" {
 		compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
 	}
 	dbItr := vdb.db.GetIterator(compositeStartKey, compositeEndKey)
-	return newKVScanner(namespace, dbItr), nil
+	return newKVScanner(namespace, dbItr, pageSize), nil
 }
 
 // ExecuteQuery implements method in VersionedDB interface
@@ -130,6 +149,11 @@ func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIt
 	return nil, errors.New("ExecuteQuery not supported for leveldb")
 }
 
+// ExecuteQueryWithPagination implements method in VersionedDB interface
+func (vdb *versionedDB) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	return nil, errors.New("ExecuteQuery not supported for leveldb")
+}
+
 // ApplyUpdates implements method in VersionedDB interface
 func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
 	dbBatch := leveldbhelper.NewUpdateBatch()
@@ -143,7 +167,7 @@ func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 			if vv.Value == nil {
 				dbBatch.Delete(compositeKey)
 			} else {
-				dbBatch.Put(compositeKey, statedb.EncodeValue(vv.Value, vv.Version))
+				dbBatch.Put(compositeKey, statedb.EncodeValueAndMetadata(vv.Value, vv.Metadata, vv.Version))
 			}
 		}
 	}
@@ -155,6 +179,11 @@ func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 	return nil
 }
 
+// GetFullScanIterator implements method in interface statedb.FullScanCapable
+func (vdb *versionedDB) GetFullScanIterator() (statedb.ResultsIterator, error) {
+	return &fullScanner{vdb.db.GetIterator(nil, nil)}, nil
+}
+
 // GetLatestSavePoint implements method in VersionedDB interface
 func (vdb *versionedDB) GetLatestSavePoint() (*version.Height, error) {
 	versionBytes, err := vdb.db.Get(savePointKey)
@@ -178,29 +207,73 @@ func splitCompositeKey(compositeKey []byte) (string, string) {
 }
 
 type kvScanner struct {
-	namespace string
-	dbItr     iterator.Iterator
+	namespace            string
+	dbItr                iterator.Iterator
+	requestedLimit       int32
+	totalRecordsReturned int32
 }
 
-func newKVScanner(namespace string, dbItr iterator.Iterator) *kvScanner {
-	return &kvScanner{namespace, dbItr}
+func newKVScanner(namespace string, dbItr iterator.Iterator, requestedLimit int32) *kvScanner {
+	return &kvScanner{namespace, dbItr, requestedLimit, 0}
 }
 
 func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
+	if scanner.requestedLimit > 0 && scanner.totalRecordsReturned >= scanner.requestedLimit {
+		return nil, nil
+	}
 	if !scanner.dbItr.Next() {
 		return nil, nil
 	}
+	scanner.totalRecordsReturned++
 	dbKey := scanner.dbItr.Key()
 	dbVal := scanner.dbItr.Value()
 	dbValCopy := make([]byte, len(dbVal))
 	copy(dbValCopy, dbVal)
 	_, key := splitCompositeKey(dbKey)
-	value, version := statedb.DecodeValue(dbValCopy)
+	value, metadata, version := statedb.DecodeValueAndMetadata(dbValCopy)
 	return &statedb.VersionedKV{
 		CompositeKey:   statedb.CompositeKey{Namespace: scanner.namespace, Key: key},
-		VersionedValue: statedb.VersionedValue{Value: value, Version: version}}, nil
+		VersionedValue: statedb.VersionedValue{Value: value, Metadata: metadata, Version: version}}, nil
 }
 
 func (scanner *kvScanner) Close() {
 	scanner.dbItr.Release()
 }
+
+// GetBookmarkAndClose returns the key that follows the last returned key, which is the
+// startKey of the next page, or an empty string if the range has been exhausted
+func (scanner *kvScanner) GetBookmarkAndClose() string {
+	bookmark := ""
+	if scanner.requestedLimit > 0 && scanner.totalRecordsReturned >= scanner.requestedLimit && scanner.dbItr.Next() {
+		_, bookmark = splitCompositeKey(scanner.dbItr.Key())
+	}
+	scanner.Close()
+	return bookmark
+}
+
+// fullScanner iterates over the keys of all the namespaces, skipping the savepoint
+type fullScanner struct {
+	dbItr iterator.Iterator
+}
+
+func (scanner *fullScanner) Next() (statedb.QueryResult, error) {
+	for scanner.dbItr.Next() {
+		dbKey := scanner.dbItr.Key()
+		if bytes.Equal(dbKey, savePointKey) {
+			continue
+		}
+		dbVal := scanner.dbItr.Value()
+		dbValCopy := make([]byte, len(dbVal))
+		copy(dbValCopy, dbVal)
+		namespace, key := splitCompositeKey(dbKey)
+		value, metadata, version := statedb.DecodeValueAndMetadata(dbValCopy)
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: namespace, Key: key},
+			VersionedValue: statedb.VersionedValue{Value: value, Metadata: metadata, Version: version}}, nil
+	}
+	return nil, nil
+}
+
+func (scanner *fullScanner) Close() {
+	scanner.dbItr.Release()
+}
@@ -44,6 +44,12 @@ func TestMultiDBBasicRW(t *testing.T) {
 	commontests.TestMultiDBBasicRW(t, env.DBProvider)
 }
 
+func TestDrop(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestDrop(t, env.DBProvider)
+}
+
 func TestDeletes(t *testing.T) {
 	env := NewTestVDBEnv(t)
 	defer env.Cleanup()
@@ -56,6 +62,12 @@ func TestIterator(t *testing.T) {
 	commontests.TestIterator(t, env.DBProvider)
 }
 
+func TestPaginatedRangeQuery(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestPaginatedRangeQuery(t, env.DBProvider)
+}
+
 func TestEncodeDecodeValueAndVersion(t *testing.T) {
 	testValueAndVersionEncodeing(t, []byte("value1"), version.NewHeight(1, 2))
 	testValueAndVersionEncodeing(t, []byte{}, version.NewHeight(50, 50))
@@ -109,3 +121,46 @@ func TestGetStateMultipleKeys(t *testing.T) {
 	defer env.Cleanup()
 	commontests.TestGetStateMultipleKeys(t, env.DBProvider)
 }
+
+func TestFullScanIterator(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testfullscan")
+	testutil.AssertNoError(t, err, "")
+	otherDB, err := env.DBProvider.GetDBHandle("testfullscan_other")
+	testutil.AssertNoError(t, err, "")
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns2", "key1", []byte("value3"), version.NewHeight(1, 3))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.PutValAndMetadata("ns1", "key1", []byte("value1"), []byte("metadata1"), version.NewHeight(1, 1))
+	batch.Put("", "key0", []byte("value0"), version.NewHeight(1, 0))
+	db.ApplyUpdates(batch, version.NewHeight(1, 3))
+
+	otherBatch := statedb.NewUpdateBatch()
+	otherBatch.Put("ns1", "key3", []byte("other"), version.NewHeight(1, 1))
+	otherDB.ApplyUpdates(otherBatch, version.NewHeight(1, 1))
+
+	itr, err := db.(statedb.FullScanCapable).GetFullScanIterator()
+	testutil.AssertNoError(t, err, "")
+	defer itr.Close()
+	var kvs []*statedb.VersionedKV
+	for {
+		res, err := itr.Next()
+		testutil.AssertNoError(t, err, "")
+		if res == nil {
+			break
+		}
+		kvs = append(kvs, res.(*statedb.VersionedKV))
+	}
+	testutil.AssertEquals(t, kvs, []*statedb.VersionedKV{
+		{CompositeKey: statedb.CompositeKey{Namespace: "", Key: "key0"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value0"), Version: version.NewHeight(1, 0)}},
+		{CompositeKey: statedb.CompositeKey{Namespace: "ns1", Key: "key1"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value1"), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)}},
+		{CompositeKey: statedb.CompositeKey{Namespace: "ns1", Key: "key2"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)}},
+		{CompositeKey: statedb.CompositeKey{Namespace: "ns2", Key: "key1"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value3"), Version: version.NewHeight(1, 3)}},
+	})
+}
@@ -16,7 +16,24 @@ limitations under the License.
 
 package statedb
 
-import "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+)
+
+const (
+	pvtDataNsSep    = "$$p"
+	hashedDataNsSep = "$$h"
+
+	// metadataMarker prefixes the encoded values which carry metadata. The encoding of a
+	// version starts with the number of bytes of the block number, which never exceeds 8
+	metadataMarker = byte(0xff)
+)
 
 //EncodeValue appends the value to the version, allows storage of version and value in binary form
 func EncodeValue(value []byte, version *version.Height) []byte {
@@ -29,7 +46,70 @@ func EncodeValue(value []byte, version *version.Height) []byte {
 
 //DecodeValue separates the version and value from a binary value
 func DecodeValue(encodedValue []byte) ([]byte, *version.Height) {
-	version, n := version.NewHeightFromBytes(encodedValue)
-	value := encodedValue[n:]
+	value, _, version := DecodeValueAndMetadata(encodedValue)
 	return value, version
 }
+
+//EncodeValueAndMetadata encodes the value, the serialized metadata and the version in binary form.
+//The encoding is the same as the one of EncodeValue if the metadata is nil
+func EncodeValueAndMetadata(value []byte, metadata []byte, version *version.Height) []byte {
+	if metadata == nil {
+		return EncodeValue(value, version)
+	}
+	encodedValue := append([]byte{metadataMarker}, version.ToBytes()...)
+	encodedValue = append(encodedValue, proto.EncodeVarint(uint64(len(metadata)))...)
+	encodedValue = append(encodedValue, metadata...)
+	return append(encodedValue, value...)
+}
+
+//DecodeValueAndMetadata separates the value, the serialized metadata and the version from a binary value
+func DecodeValueAndMetadata(encodedValue []byte) ([]byte, []byte, *version.Height) {
+	if len(encodedValue) == 0 || encodedValue[0] != metadataMarker {
+		version, n := version.NewHeightFromBytes(encodedValue)
+		return encodedValue[n:], nil, version
+	}
+	version, n := version.NewHeightFromBytes(encodedValue[1:])
+	rest := encodedValue[1+n:]
+	metadataLen, n := binary.Uvarint(rest)
+	metadata := rest[n : n+int(metadataLen)]
+	return rest[n+int(metadataLen):], metadata, version
+}
+
+//SerializeMetadata serializes the metadata entries of a key. It returns nil if there are no entries
+func SerializeMetadata(entries []*kvrwset.KVMetadataEntry) ([]byte, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return proto.Marshal(&kvrwset.KVMetadataWrite{Entries: entries})
+}
+
+//DeserializeMetadata converts the serialized metadata of a key back into a map of named entries
+func DeserializeMetadata(metadataBytes []byte) (map[string][]byte, error) {
+	if metadataBytes == nil {
+		return nil, nil
+	}
+	metadata := &kvrwset.KVMetadataWrite{}
+	if err := proto.Unmarshal(metadataBytes, metadata); err != nil {
+		return nil, fmt.Errorf("error unmarshaling metadata: %s", err)
+	}
+	m := make(map[string][]byte)
+	for _, entry := range metadata.Entries {
+		m[entry.Name] = entry.Value
+	}
+	return m, nil
+}
+
+//DerivePvtDataNs returns the namespace under which the private data of a collection is maintained
+func DerivePvtDataNs(namespace, collection string) string {
+	return namespace + pvtDataNsSep + collection
+}
+
+//DeriveHashedDataNs returns the namespace under which the hashes of the private data of a collection are maintained
+func DeriveHashedDataNs(namespace, collection string) string {
+	return namespace + hashedDataNsSep + collection
+}
+
+//EncodeHashedKey converts the hash of a private key into a string that can be used as a key in the state db
+func EncodeHashedKey(keyHash []byte) string {
+	return hex.EncodeToString(keyHash)
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 )
 
 // TestEncodeString tests encoding and decoding a string value
@@ -52,3 +53,37 @@ func TestEncodeDecodeJSON(t *testing.T) {
 	testutil.AssertEquals(t, decodedVersion, version2)
 
 }
+
+// TestEncodeDecodeValueAndMetadata tests encoding and decoding a value along with its metadata
+func TestEncodeDecodeValueAndMetadata(t *testing.T) {
+	value := []byte("value1")
+	version1 := version.NewHeight(1, 1)
+
+	encodedValue := EncodeValueAndMetadata(value, nil, version1)
+	testutil.AssertEquals(t, encodedValue, EncodeValue(value, version1))
+
+	metadata, err := SerializeMetadata([]*kvrwset.KVMetadataEntry{{Name: "entry1", Value: []byte("meta1")}})
+	testutil.AssertNoError(t, err, "")
+	encodedValue = EncodeValueAndMetadata(value, metadata, version1)
+	decodedValue, decodedMetadata, decodedVersion := DecodeValueAndMetadata(encodedValue)
+	testutil.AssertEquals(t, decodedValue, value)
+	testutil.AssertEquals(t, decodedMetadata, metadata)
+	testutil.AssertEquals(t, decodedVersion, version1)
+
+	// the values encoded with metadata can be decoded by DecodeValue as well
+	decodedValue, decodedVersion = DecodeValue(encodedValue)
+	testutil.AssertEquals(t, decodedValue, value)
+	testutil.AssertEquals(t, decodedVersion, version1)
+
+	m, err := DeserializeMetadata(decodedMetadata)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, m, map[string][]byte{"entry1": []byte("meta1")})
+}
+
+// TestDeriveCollectionNs tests the namespaces derived for the private data and its hashes
+func TestDeriveCollectionNs(t *testing.T) {
+	testutil.AssertEquals(t, DerivePvtDataNs("ns1", "coll1"), "ns1$$pcoll1")
+	testutil.AssertEquals(t, DeriveHashedDataNs("ns1", "coll1"), "ns1$$hcoll1")
+	testutil.AssertNotEquals(t, DerivePvtDataNs("ns1", "coll1"), DeriveHashedDataNs("ns1", "coll1"))
+	testutil.AssertEquals(t, EncodeHashedKey([]byte{0x01, 0xab}), "01ab")
+}
@@ -17,11 +17,14 @@ limitations under the License.
 package lockbasedtxmgr
 
 import (
+	"fmt"
+
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 )
@@ -47,6 +50,50 @@ func (h *queryHelper) getState(ns string, key string) ([]byte, error) {
 	return val, nil
 }
 
+// getStateMetadata returns the metadata of a key. The key is added to the read-set
+// so that a concurrent change of its metadata invalidates the transaction
+func (h *queryHelper) getStateMetadata(ns string, key string) (map[string][]byte, error) {
+	h.checkDone()
+	versionedValue, err := h.txmgr.db.GetState(ns, key)
+	if err != nil {
+		return nil, err
+	}
+	var metadataBytes []byte
+	var ver *version.Height
+	if versionedValue != nil {
+		metadataBytes, ver = versionedValue.Metadata, versionedValue.Version
+	}
+	if h.rwsetBuilder != nil {
+		h.rwsetBuilder.AddToReadSet(ns, key, ver)
+	}
+	return statedb.DeserializeMetadata(metadataBytes)
+}
+
+// getPrivateData reads the private data from the private state and verifies that the data is
+// in sync with the hash maintained in the public state. Only the hash of the key goes to the read-set
+func (h *queryHelper) getPrivateData(ns, coll, key string) ([]byte, error) {
+	h.checkDone()
+	hashedVersionedValue, err := h.txmgr.db.GetState(statedb.DeriveHashedDataNs(ns, coll),
+		statedb.EncodeHashedKey(util.ComputeStringHash(key)))
+	if err != nil {
+		return nil, err
+	}
+	versionedValue, err := h.txmgr.pvtdb.GetState(statedb.DerivePvtDataNs(ns, coll), key)
+	if err != nil {
+		return nil, err
+	}
+	_, hashVer := decomposeVersionedValue(hashedVersionedValue)
+	val, ver := decomposeVersionedValue(versionedValue)
+	if !version.AreSame(hashVer, ver) {
+		return nil, fmt.Errorf("private data for key [%s] in collection [%s:%s] is not available or not yet in sync with the committed hash",
+			key, ns, coll)
+	}
+	if h.rwsetBuilder != nil {
+		h.rwsetBuilder.AddToHashedReadSet(ns, coll, key, ver)
+	}
+	return val, nil
+}
+
 func (h *queryHelper) getStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
 	h.checkDone()
 	versionedValues, err := h.txmgr.db.GetStateMultipleKeys(namespace, keys)
@@ -65,8 +112,17 @@ func (h *queryHelper) getStateMultipleKeys(namespace string, keys []string) ([][
 }
 
 func (h *queryHelper) getStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error) {
+	return h.getStateRangeScanIteratorWithPagination(namespace, startKey, endKey, 0)
+}
+
+// getStateRangeScanIteratorWithPagination returns an iterator over at most pageSize keys of the range;
+// a pageSize of zero returns all the keys in the range
+func (h *queryHelper) getStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (*resultsItr, error) {
 	h.checkDone()
-	itr, err := newResultsItr(namespace, startKey, endKey, h.txmgr.db, h.rwsetBuilder,
+	if pageSize < 0 {
+		return nil, fmt.Errorf("invalid page size [%d]", pageSize)
+	}
+	itr, err := newResultsItr(namespace, startKey, endKey, pageSize, h.txmgr.db, h.rwsetBuilder,
 		ledgerconfig.IsQueryReadsHashingEnabled(), ledgerconfig.GetMaxDegreeQueryReadsHashing())
 	if err != nil {
 		return nil, err
@@ -83,6 +139,17 @@ func (h *queryHelper) executeQuery(namespace, query string) (commonledger.Result
 	return &queryResultsItr{DBItr: dbItr, RWSetBuilder: h.rwsetBuilder}, nil
 }
 
+func (h *queryHelper) executeQueryWithPagination(namespace, query, bookmark string, pageSize int32) (*queryResultsItr, error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("invalid page size [%d]", pageSize)
+	}
+	dbItr, err := h.txmgr.db.ExecuteQueryWithPagination(namespace, query, bookmark, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &queryResultsItr{DBItr: dbItr, RWSetBuilder: h.rwsetBuilder}, nil
+}
+
 func (h *queryHelper) done() {
 	if h.doneInvoked {
 		return
@@ -127,19 +194,27 @@ func (h *queryHelper) checkDone() {
 type resultsItr struct {
 	ns                      string
 	endKey                  string
+	pageSize                int32
+	numReturned             int32
 	dbItr                   statedb.ResultsIterator
 	rwSetBuilder            *rwsetutil.RWSetBuilder
 	rangeQueryInfo          *kvrwset.RangeQueryInfo
 	rangeQueryResultsHelper *rwsetutil.RangeQueryResultsHelper
 }
 
-func newResultsItr(ns string, startKey string, endKey string,
+func newResultsItr(ns string, startKey string, endKey string, pageSize int32,
 	db statedb.VersionedDB, rwsetBuilder *rwsetutil.RWSetBuilder, enableHashing bool, maxDegree uint32) (*resultsItr, error) {
-	dbItr, err := db.GetStateRangeScanIterator(ns, startKey, endKey)
+	var dbItr statedb.ResultsIterator
+	var err error
+	if pageSize > 0 {
+		dbItr, err = db.GetStateRangeScanIteratorWithPagination(ns, startKey, endKey, pageSize)
+	} else {
+		dbItr, err = db.GetStateRangeScanIterator(ns, startKey, endKey)
+	}
 	if err != nil {
 		return nil, err
 	}
-	itr := &resultsItr{ns: ns, dbItr: dbItr}
+	itr := &resultsItr{ns: ns, pageSize: pageSize, dbItr: dbItr}
 	// it's a simulation request so, enable capture of range query info
 	if rwsetBuilder != nil {
 		itr.rwSetBuilder = rwsetBuilder
@@ -171,6 +246,7 @@ func (itr *resultsItr) Next() (commonledger.QueryResult, error) {
 	if queryResult == nil {
 		return nil, nil
 	}
+	itr.numReturned++
 	versionedKV := queryResult.(*statedb.VersionedKV)
 	return &queryresult.KV{Namespace: versionedKV.Namespace, Key: versionedKV.Key, Value: versionedKV.Value}, nil
 }
@@ -186,6 +262,11 @@ func (itr *resultsItr) updateRangeQueryInfo(queryResult statedb.QueryResult) {
 	}
 
 	if queryResult == nil {
+		if itr.pageSize > 0 && itr.numReturned >= itr.pageSize {
+			// the iterator stopped at the end of the page and not at the end of the range.
+			// So, the endKey remains the last key retrieved by the caller
+			return
+		}
 		// caller scanned till the iterator got exhausted.
 		// So, set the endKey to the actual endKey supplied in the query
 		itr.rangeQueryInfo.ItrExhausted = true
@@ -204,6 +285,11 @@ func (itr *resultsItr) Close() {
 	itr.dbItr.Close()
 }
 
+// GetBookmarkAndClose implements method in interface ledger.QueryResultsIterator
+func (itr *resultsItr) GetBookmarkAndClose() string {
+	return getBookmarkAndClose(itr.dbItr)
+}
+
 type queryResultsItr struct {
 	DBItr        statedb.ResultsIterator
 	RWSetBuilder *rwsetutil.RWSetBuilder
@@ -233,6 +319,19 @@ func (itr *queryResultsItr) Close() {
 	itr.DBItr.Close()
 }
 
+// GetBookmarkAndClose implements method in interface ledger.QueryResultsIterator
+func (itr *queryResultsItr) GetBookmarkAndClose() string {
+	return getBookmarkAndClose(itr.DBItr)
+}
+
+func getBookmarkAndClose(dbItr statedb.ResultsIterator) string {
+	if queryResultsItr, ok := dbItr.(statedb.QueryResultsIterator); ok {
+		return queryResultsItr.GetBookmarkAndClose()
+	}
+	dbItr.Close()
+	return ""
+}
+
 func decomposeVersionedValue(versionedValue *statedb.VersionedValue) ([]byte, *version.Height) {
 	var value []byte
 	var ver *version.Height
@@ -17,8 +17,11 @@ limitations under the License.
 package lockbasedtxmgr
 
 import (
-	"github.com/hyperledger/fabric/common/ledger"
+	"fmt"
+
+	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger"
 )
 
 // LockBasedQueryExecutor is a query executor used in `LockBasedTxMgr`
@@ -39,6 +42,16 @@ func (q *lockBasedQueryExecutor) GetState(ns string, key string) ([]byte, error)
 	return q.helper.getState(ns, key)
 }
 
+// GetStateMetadata implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	return q.helper.getStateMetadata(namespace, key)
+}
+
+// GetPrivateData implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) GetPrivateData(namespace, collection, key string) ([]byte, error) {
+	return q.helper.getPrivateData(namespace, collection, key)
+}
+
 // GetStateMultipleKeys implements method in interface `ledger.QueryExecutor`
 func (q *lockBasedQueryExecutor) GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
 	return q.helper.getStateMultipleKeys(namespace, keys)
@@ -48,15 +61,36 @@ func (q *lockBasedQueryExecutor) GetStateMultipleKeys(namespace string, keys []s
 // startKey is included in the results and endKey is excluded. An empty startKey refers to the first available key
 // and an empty endKey refers to the last available key. For scanning all the keys, both the startKey and the endKey
 // can be supplied as empty strings. However, a full scan shuold be used judiciously for performance reasons.
-func (q *lockBasedQueryExecutor) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (ledger.ResultsIterator, error) {
+func (q *lockBasedQueryExecutor) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error) {
 	return q.helper.getStateRangeScanIterator(namespace, startKey, endKey)
 }
 
 // ExecuteQuery implements method in interface `ledger.QueryExecutor`
-func (q *lockBasedQueryExecutor) ExecuteQuery(namespace, query string) (ledger.ResultsIterator, error) {
+func (q *lockBasedQueryExecutor) ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error) {
 	return q.helper.executeQuery(namespace, query)
 }
 
+// GetStateRangeScanIteratorWithPagination implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (ledger.QueryResultsIterator, error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("invalid page size [%d]", pageSize)
+	}
+	itr, err := q.helper.getStateRangeScanIteratorWithPagination(namespace, startKey, endKey, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return itr, nil
+}
+
+// ExecuteQueryWithPagination implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (ledger.QueryResultsIterator, error) {
+	itr, err := q.helper.executeQueryWithPagination(namespace, query, bookmark, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return itr, nil
+}
+
 // Done implements method in interface `ledger.QueryExecutor`
 func (q *lockBasedQueryExecutor) Done() {
 	logger.Debugf("Done with transaction simulation / query execution [%s]", q.id)
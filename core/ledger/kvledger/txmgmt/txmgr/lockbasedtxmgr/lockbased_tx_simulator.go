@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 )
 
 // LockBasedTxSimulator is a transaction simulator used in `LockBasedTxMgr`
@@ -67,6 +68,16 @@ func (s *lockBasedTxSimulator) SetStateMultipleKeys(namespace string, kvs map[st
 	return nil
 }
 
+// SetStateMetadata implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) SetStateMetadata(namespace, key string, metadata map[string][]byte) error {
+	s.helper.checkDone()
+	if err := s.helper.txmgr.db.ValidateKey(key); err != nil {
+		return err
+	}
+	s.rwsetBuilder.AddToMetadataWriteSet(namespace, key, metadata)
+	return nil
+}
+
 // GetTxSimulationResults implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) GetTxSimulationResults() ([]byte, error) {
 	logger.Debugf("Simulation completed, getting simulation results")
@@ -77,6 +88,35 @@ func (s *lockBasedTxSimulator) GetTxSimulationResults() ([]byte, error) {
 	return s.rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
 }
 
+// SetPrivateData implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) SetPrivateData(ns, coll, key string, value []byte) error {
+	s.helper.checkDone()
+	if err := s.helper.txmgr.pvtdb.ValidateKey(key); err != nil {
+		return err
+	}
+	s.rwsetBuilder.AddToPvtAndHashedWriteSet(ns, coll, key, value)
+	return nil
+}
+
+// DeletePrivateData implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) DeletePrivateData(ns, coll, key string) error {
+	return s.SetPrivateData(ns, coll, key, nil)
+}
+
+// GetPvtSimulationResults implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) GetPvtSimulationResults() (*rwset.TxPvtReadWriteSet, error) {
+	logger.Debugf("Simulation completed, getting private simulation results")
+	s.Done()
+	if s.helper.err != nil {
+		return nil, s.helper.err
+	}
+	txPvtRWSet := s.rwsetBuilder.GetTxPvtReadWriteSet()
+	if txPvtRWSet == nil {
+		return nil, nil
+	}
+	return txPvtRWSet.ToProtoMsg()
+}
+
 // ExecuteUpdate implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) ExecuteUpdate(query string) error {
 	return errors.New("Not supported")
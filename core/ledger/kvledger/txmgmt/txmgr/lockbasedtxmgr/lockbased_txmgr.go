@@ -17,7 +17,9 @@ limitations under the License.
 package lockbasedtxmgr
 
 import (
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -26,6 +28,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/statebasedval"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 )
 
 var logger = flogging.MustGetLogger("lockbasedtxmgr")
@@ -33,17 +36,27 @@ var logger = flogging.MustGetLogger("lockbasedtxmgr")
 // LockBasedTxMgr a simple implementation of interface `txmgmt.TxMgr`.
 // This implementation uses a read-write lock to prevent conflicts between transaction simulation and committing
 type LockBasedTxMgr struct {
-	db           statedb.VersionedDB
-	validator    validator.Validator
-	batch        *statedb.UpdateBatch
-	currentBlock *common.Block
-	commitRWLock sync.RWMutex
+	ledgerid       string
+	db             statedb.VersionedDB
+	pvtdb          statedb.VersionedDB
+	validator      validator.Validator
+	stateListeners []ledger.StateListener
+	batch          *statedb.UpdateBatch
+	pvtBatch       *statedb.UpdateBatch
+	currentBlock   *common.Block
+	commitRWLock   sync.RWMutex
 }
 
-// NewLockBasedTxMgr constructs a new instance of NewLockBasedTxMgr
-func NewLockBasedTxMgr(db statedb.VersionedDB) *LockBasedTxMgr {
+// NewLockBasedTxMgr constructs a new instance of NewLockBasedTxMgr.
+// The private data of the collections is maintained in `pvtdb` whereas `db` maintains
+// the public state along with the hashes of the private data. The `stateListeners` are
+// notified of the committed updates of the namespaces they are interested in
+func NewLockBasedTxMgr(ledgerid string, db statedb.VersionedDB, pvtdb statedb.VersionedDB,
+	stateListeners []ledger.StateListener) *LockBasedTxMgr {
 	db.Open()
-	return &LockBasedTxMgr{db: db, validator: statebasedval.NewValidator(db)}
+	pvtdb.Open()
+	return &LockBasedTxMgr{ledgerid: ledgerid, db: db, pvtdb: pvtdb,
+		validator: statebasedval.NewValidator(db), stateListeners: stateListeners}
 }
 
 // GetLastSavepoint returns the block num recorded in savepoint,
@@ -68,20 +81,27 @@ func (txmgr *LockBasedTxMgr) NewTxSimulator() (ledger.TxSimulator, error) {
 }
 
 // ValidateAndPrepare implements method in interface `txmgmt.TxMgr`
-func (txmgr *LockBasedTxMgr) ValidateAndPrepare(block *common.Block, doMVCCValidation bool) error {
+func (txmgr *LockBasedTxMgr) ValidateAndPrepare(blockAndPvtdata *ledger.BlockAndPvtData, doMVCCValidation bool) error {
+	block := blockAndPvtdata.Block
 	logger.Debugf("Validating new block with num trans = [%d]", len(block.Data.Data))
 	batch, err := txmgr.validator.ValidateAndPrepareBatch(block, doMVCCValidation)
 	if err != nil {
 		return err
 	}
+	pvtBatch, err := txmgr.validator.ValidateAndPreparePvtBatch(block, blockAndPvtdata.BlockPvtData)
+	if err != nil {
+		return err
+	}
 	txmgr.currentBlock = block
 	txmgr.batch = batch
+	txmgr.pvtBatch = pvtBatch
 	return err
 }
 
 // Shutdown implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Shutdown() {
 	txmgr.db.Close()
+	txmgr.pvtdb.Close()
 }
 
 // Commit implements method in interface `txmgmt.TxMgr`
@@ -93,18 +113,63 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 	if txmgr.batch == nil {
 		panic("validateAndPrepare() method should have been called before calling commit()")
 	}
-	defer func() { txmgr.batch = nil }()
-	if err := txmgr.db.ApplyUpdates(txmgr.batch,
-		version.NewHeight(txmgr.currentBlock.Header.Number, uint64(len(txmgr.currentBlock.Data.Data)-1))); err != nil {
+	defer func() { txmgr.batch, txmgr.pvtBatch = nil, nil }()
+	savepoint := version.NewHeight(txmgr.currentBlock.Header.Number, uint64(len(txmgr.currentBlock.Data.Data)-1))
+	// the private data is committed first so that the private state is never behind the hashes
+	// present in the public state, which otherwise could be seen by a concurrent reader
+	startTime := time.Now()
+	if err := txmgr.pvtdb.ApplyUpdates(txmgr.pvtBatch, savepoint); err != nil {
 		return err
 	}
+	if err := txmgr.db.ApplyUpdates(txmgr.batch, savepoint); err != nil {
+		return err
+	}
+	stateDBCommitTime.With(txmgr.ledgerid).Observe(time.Since(startTime).Seconds())
 	logger.Debugf("Updates committed to state database")
+	txmgr.invokeNamespaceListeners(txmgr.batch)
 	return nil
 }
 
+// invokeNamespaceListeners notifies the state listeners of the committed updates.
+// The updates are durable at this point, hence a failing listener is only logged
+func (txmgr *LockBasedTxMgr) invokeNamespaceListeners(batch *statedb.UpdateBatch) {
+	for _, listener := range txmgr.stateListeners {
+		stateUpdates := ledger.StateUpdates{}
+		for _, ns := range listener.InterestedInNamespaces() {
+			if kvWrites := getKVWrites(batch, ns); len(kvWrites) > 0 {
+				stateUpdates[ns] = kvWrites
+			}
+		}
+		if len(stateUpdates) == 0 {
+			continue
+		}
+		if err := listener.HandleStateUpdates(txmgr.ledgerid, stateUpdates); err != nil {
+			logger.Errorf("Error in state listener for the updates of block [%d] on ledger [%s]: %s",
+				txmgr.currentBlock.Header.Number, txmgr.ledgerid, err)
+		}
+	}
+}
+
+// getKVWrites returns the updates of a namespace as writes, ordered by key
+func getKVWrites(batch *statedb.UpdateBatch, ns string) []*kvrwset.KVWrite {
+	updates := batch.GetUpdates(ns)
+	keys := make([]string, 0, len(updates))
+	for key := range updates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	kvWrites := make([]*kvrwset.KVWrite, 0, len(keys))
+	for _, key := range keys {
+		vv := updates[key]
+		kvWrites = append(kvWrites, &kvrwset.KVWrite{Key: key, IsDelete: vv.Value == nil, Value: vv.Value})
+	}
+	return kvWrites
+}
+
 // Rollback implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Rollback() {
 	txmgr.batch = nil
+	txmgr.pvtBatch = nil
 }
 
 // ShouldRecover implements method in interface kvledger.Recoverer
@@ -122,7 +187,8 @@ func (txmgr *LockBasedTxMgr) ShouldRecover(lastAvailableBlock uint64) (bool, uin
 // CommitLostBlock implements method in interface kvledger.Recoverer
 func (txmgr *LockBasedTxMgr) CommitLostBlock(block *common.Block) error {
 	logger.Debugf("Constructing updateSet for the block %d", block.Header.Number)
-	if err := txmgr.ValidateAndPrepare(block, false); err != nil {
+	// the private data of the lost blocks is not available during recovery
+	if err := txmgr.ValidateAndPrepare(&ledger.BlockAndPvtData{Block: block}, false); err != nil {
 		return err
 	}
 	logger.Debugf("Committing block %d to state database", block.Header.Number)
@@ -0,0 +1,16 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lockbasedtxmgr
+
+import "github.com/hyperledger/fabric/common/metrics"
+
+var stateDBCommitTime = metrics.NewHistogram(metrics.HistogramOpts{
+	Namespace:  "ledger",
+	Name:       "statedb_commit_time",
+	Help:       "The time to commit the updates of a block to the state database, private data included, in seconds.",
+	LabelNames: []string{"channel"},
+})
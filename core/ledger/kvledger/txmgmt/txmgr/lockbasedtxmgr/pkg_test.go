@@ -21,12 +21,14 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/spf13/viper"
 )
 
@@ -66,8 +68,10 @@ func (env *levelDBLockBasedEnv) init(t *testing.T, testLedgerID string) {
 	testDBEnv := stateleveldb.NewTestVDBEnv(t)
 	testDB, err := testDBEnv.DBProvider.GetDBHandle(testLedgerID)
 	testutil.AssertNoError(t, err, "")
+	testPvtDB, err := testDBEnv.DBProvider.GetDBHandle(testLedgerID + "_pvt")
+	testutil.AssertNoError(t, err, "")
 
-	txMgr := NewLockBasedTxMgr(testDB)
+	txMgr := NewLockBasedTxMgr(testLedgerID, testDB, testPvtDB, nil)
 	env.testLedgerID = testLedgerID
 	env.testDBEnv = testDBEnv
 	env.testDB = testDB
@@ -94,6 +98,7 @@ const couchDBtestEnvName = "couchDB_LockBasedTxMgr"
 type couchDBLockBasedEnv struct {
 	testLedgerID string
 	testDBEnv    *statecouchdb.TestVDBEnv
+	testPvtDBEnv *stateleveldb.TestVDBEnv
 	testDB       statedb.VersionedDB
 	txmgr        txmgr.TxMgr
 }
@@ -116,10 +121,15 @@ func (env *couchDBLockBasedEnv) init(t *testing.T, testLedgerID string) {
 	testDBEnv := statecouchdb.NewTestVDBEnv(t)
 	testDB, err := testDBEnv.DBProvider.GetDBHandle(testLedgerID)
 	testutil.AssertNoError(t, err, "")
+	// private data is always maintained in a leveldb
+	testPvtDBEnv := stateleveldb.NewTestVDBEnv(t)
+	testPvtDB, err := testPvtDBEnv.DBProvider.GetDBHandle(testLedgerID)
+	testutil.AssertNoError(t, err, "")
 
-	txMgr := NewLockBasedTxMgr(testDB)
+	txMgr := NewLockBasedTxMgr(testLedgerID, testDB, testPvtDB, nil)
 	env.testLedgerID = testLedgerID
 	env.testDBEnv = testDBEnv
+	env.testPvtDBEnv = testPvtDBEnv
 	env.testDB = testDB
 	env.txmgr = txMgr
 }
@@ -135,6 +145,7 @@ func (env *couchDBLockBasedEnv) getVDB() statedb.VersionedDB {
 func (env *couchDBLockBasedEnv) cleanup() {
 	defer env.txmgr.Shutdown()
 	defer env.testDBEnv.Cleanup(env.testLedgerID)
+	defer env.testPvtDBEnv.Cleanup()
 }
 
 //////////// txMgrTestHelper /////////////
@@ -151,8 +162,16 @@ func newTxMgrTestHelper(t *testing.T, txMgr txmgr.TxMgr) *txMgrTestHelper {
 }
 
 func (h *txMgrTestHelper) validateAndCommitRWSet(txRWSet []byte) {
+	h.validateAndCommitRWSetWithPvtData(txRWSet, nil)
+}
+
+func (h *txMgrTestHelper) validateAndCommitRWSetWithPvtData(txRWSet []byte, txPvtRWSet *rwset.TxPvtReadWriteSet) {
 	block := h.bg.NextBlock([][]byte{txRWSet})
-	err := h.txMgr.ValidateAndPrepare(block, true)
+	blockAndPvtData := &ledger.BlockAndPvtData{Block: block}
+	if txPvtRWSet != nil {
+		blockAndPvtData.BlockPvtData = map[uint64]*ledger.TxPvtData{0: {SeqInBlock: 0, WriteSet: txPvtRWSet}}
+	}
+	err := h.txMgr.ValidateAndPrepare(blockAndPvtData, true)
 	testutil.AssertNoError(h.t, err, "")
 	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
 	invalidTxNum := 0
@@ -168,7 +187,7 @@ func (h *txMgrTestHelper) validateAndCommitRWSet(txRWSet []byte) {
 
 func (h *txMgrTestHelper) checkRWsetInvalid(txRWSet []byte) {
 	block := h.bg.NextBlock([][]byte{txRWSet})
-	err := h.txMgr.ValidateAndPrepare(block, true)
+	err := h.txMgr.ValidateAndPrepare(&ledger.BlockAndPvtData{Block: block}, true)
 	testutil.AssertNoError(h.t, err, "")
 	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
 	invalidTxNum := 0
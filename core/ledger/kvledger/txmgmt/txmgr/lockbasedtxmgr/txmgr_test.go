@@ -25,8 +25,12 @@ import (
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 )
 
@@ -117,6 +121,76 @@ func testTxSimulatorWithExistingData(t *testing.T, env testEnv) {
 	testutil.AssertEquals(t, vv.Version, version.NewHeight(1, 0))
 }
 
+func TestTxSimulatorWithStateMetadata(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Run(testEnv.getName(), func(t *testing.T) {
+			testLedgerID := "testtxsimulatorwithstatemetadata"
+			testEnv.init(t, testLedgerID)
+			testTxSimulatorWithStateMetadata(t, testEnv)
+			testEnv.cleanup()
+		})
+	}
+}
+
+func testTxSimulatorWithStateMetadata(t *testing.T, env testEnv) {
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	// simulate tx1 that sets a value along with its metadata and the metadata of a non-existing key
+	s1, _ := txMgr.NewTxSimulator()
+	s1.SetState("ns1", "key1", []byte("value1"))
+	s1.SetStateMetadata("ns1", "key1", map[string][]byte{"entry1": []byte("meta1")})
+	s1.SetStateMetadata("ns1", "key2", map[string][]byte{"entry1": []byte("meta2")})
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1)
+
+	// simulate tx2 that updates the value only, the metadata is retained
+	s2, _ := txMgr.NewTxSimulator()
+	metadata, err := s2.GetStateMetadata("ns1", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"entry1": []byte("meta1")})
+	metadata, err = s2.GetStateMetadata("ns1", "key2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, metadata)
+	s2.SetState("ns1", "key1", []byte("value1_1"))
+	s2.Done()
+	txRWSet2, _ := s2.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet2)
+
+	// simulate tx3 that updates the metadata only, the value is retained
+	s3, _ := txMgr.NewTxSimulator()
+	metadata, _ = s3.GetStateMetadata("ns1", "key1")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"entry1": []byte("meta1")})
+	s3.SetStateMetadata("ns1", "key1", map[string][]byte{"entry1": []byte("meta1_1"), "entry2": []byte("meta2")})
+	s3.Done()
+	txRWSet3, _ := s3.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet3)
+
+	vv, _ := env.getVDB().GetState("ns1", "key1")
+	testutil.AssertEquals(t, vv.Value, []byte("value1_1"))
+	testutil.AssertEquals(t, vv.Version, version.NewHeight(3, 0))
+	s4, _ := txMgr.NewTxSimulator()
+	metadata, _ = s4.GetStateMetadata("ns1", "key1")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"entry1": []byte("meta1_1"), "entry2": []byte("meta2")})
+	// deleting the key deletes its metadata as well
+	s4.DeleteState("ns1", "key1")
+	s4.Done()
+	txRWSet4, _ := s4.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet4)
+
+	s5, _ := txMgr.NewTxSimulator()
+	s5.SetState("ns1", "key1", []byte("value1_2"))
+	s5.Done()
+	txRWSet5, _ := s5.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet5)
+
+	qe, _ := txMgr.NewQueryExecutor()
+	defer qe.Done()
+	metadata, err = qe.GetStateMetadata("ns1", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, metadata)
+}
+
 func TestTxValidation(t *testing.T) {
 	for _, testEnv := range testEnvs {
 		t.Logf("Running test for TestEnv = %s", testEnv.getName())
@@ -363,6 +437,75 @@ func testIterator(t *testing.T, env testEnv, numKeys int, startKeyNum int, endKe
 	testutil.AssertEquals(t, count, expectedCount)
 }
 
+func TestIteratorWithPagination(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Run(testEnv.getName(), func(t *testing.T) {
+			testLedgerID := "testiteratorwithpagination"
+			testEnv.init(t, testLedgerID)
+			testIteratorWithPagination(t, testEnv)
+			testEnv.cleanup()
+		})
+	}
+}
+
+func testIteratorWithPagination(t *testing.T, env testEnv) {
+	cID := "cID"
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	s, _ := txMgr.NewTxSimulator()
+	for i := 1; i <= 10; i++ {
+		s.SetState(cID, createTestKey(i), createTestValue(i))
+	}
+	s.Done()
+	txRWSet, _ := s.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet)
+
+	queryExecuter, _ := txMgr.NewQueryExecutor()
+	defer queryExecuter.Done()
+	_, err := queryExecuter.GetStateRangeScanIteratorWithPagination(cID, "", "", 0)
+	testutil.AssertError(t, err, "Expected an error for a zero page size")
+
+	// page through all the keys, three at a time
+	var keys []string
+	numPages := 0
+	startKey := ""
+	for {
+		itr, err := queryExecuter.GetStateRangeScanIteratorWithPagination(cID, startKey, "", 3)
+		testutil.AssertNoError(t, err, "")
+		for {
+			kv, _ := itr.Next()
+			if kv == nil {
+				break
+			}
+			keys = append(keys, kv.(*queryresult.KV).Key)
+		}
+		numPages++
+		if startKey = itr.GetBookmarkAndClose(); startKey == "" {
+			break
+		}
+	}
+	testutil.AssertEquals(t, numPages, 4)
+	testutil.AssertEquals(t, len(keys), 10)
+	for i, k := range keys {
+		testutil.AssertEquals(t, k, createTestKey(i+1))
+	}
+
+	// a page that does not exhaust the range must not be recorded as an exhausted range query
+	s, _ = txMgr.NewTxSimulator()
+	itr, err := s.GetStateRangeScanIteratorWithPagination(cID, createTestKey(1), "", 3)
+	testutil.AssertNoError(t, err, "")
+	for kv, _ := itr.Next(); kv != nil; kv, _ = itr.Next() {
+	}
+	testutil.AssertEquals(t, itr.GetBookmarkAndClose(), createTestKey(4))
+	s.Done()
+	simResBytes, _ := s.GetTxSimulationResults()
+	simRes := &rwsetutil.TxRwSet{}
+	testutil.AssertNoError(t, simRes.FromProtoBytes(simResBytes), "")
+	rqi := simRes.NsRwSets[0].KvRwSet.RangeQueriesInfo[0]
+	testutil.AssertEquals(t, rqi.EndKey, createTestKey(3))
+	testutil.AssertEquals(t, rqi.ItrExhausted, false)
+}
+
 func TestIteratorWithDeletes(t *testing.T) {
 	for _, testEnv := range testEnvs {
 		t.Logf("Running test for TestEnv = %s", testEnv.getName())
@@ -621,3 +764,182 @@ func TestValidateKey(t *testing.T) {
 		testEnv.cleanup()
 	}
 }
+
+func TestTxSimulatorWithPvtData(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Run(testEnv.getName(), func(t *testing.T) {
+			testLedgerID := "testtxsimulatorwithpvtdata"
+			testEnv.init(t, testLedgerID)
+			testTxSimulatorWithPvtData(t, testEnv)
+			testEnv.cleanup()
+		})
+	}
+}
+
+func testTxSimulatorWithPvtData(t *testing.T, env testEnv) {
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	// simulate tx1 that writes public and private data
+	s1, _ := txMgr.NewTxSimulator()
+	s1.SetState("ns1", "key1", []byte("value1"))
+	s1.SetPrivateData("ns1", "coll1", "key1", []byte("pvt_value1"))
+	s1.SetPrivateData("ns1", "coll1", "key2", []byte("pvt_value2"))
+	s1.Done()
+	txRWSet1, err := s1.GetTxSimulationResults()
+	testutil.AssertNoError(t, err, "")
+	txPvtRWSet1, err := s1.GetPvtSimulationResults()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNotNil(t, txPvtRWSet1)
+	txMgrHelper.validateAndCommitRWSetWithPvtData(txRWSet1, txPvtRWSet1)
+
+	// the public state contains only the hashes of the private data
+	vv, _ := env.getVDB().GetState(statedb.DeriveHashedDataNs("ns1", "coll1"), statedb.EncodeHashedKey(util.ComputeStringHash("key1")))
+	testutil.AssertEquals(t, vv.Value, util.ComputeHash([]byte("pvt_value1")))
+	vv, _ = env.getVDB().GetState(statedb.DerivePvtDataNs("ns1", "coll1"), "key1")
+	testutil.AssertNil(t, vv)
+
+	// simulate tx2 that reads and updates private data
+	s2, _ := txMgr.NewTxSimulator()
+	value, err := s2.GetPrivateData("ns1", "coll1", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, value, []byte("pvt_value1"))
+	s2.SetPrivateData("ns1", "coll1", "key1", []byte("pvt_value1_1"))
+	s2.DeletePrivateData("ns1", "coll1", "key2")
+	s2.Done()
+	txRWSet2, _ := s2.GetTxSimulationResults()
+	txPvtRWSet2, _ := s2.GetPvtSimulationResults()
+
+	// simulate tx3 that reads the same private key before tx2 is committed
+	s3, _ := txMgr.NewTxSimulator()
+	s3.GetPrivateData("ns1", "coll1", "key1")
+	s3.SetState("ns1", "key3", []byte("value3"))
+	s3.Done()
+	txRWSet3, _ := s3.GetTxSimulationResults()
+	txPvtRWSet3, _ := s3.GetPvtSimulationResults()
+	testutil.AssertNil(t, txPvtRWSet3)
+
+	txMgrHelper.validateAndCommitRWSetWithPvtData(txRWSet2, txPvtRWSet2)
+	// tx3 should be invalid because of the read conflict on the hashed key
+	txMgrHelper.checkRWsetInvalid(txRWSet3)
+
+	qe, _ := txMgr.NewQueryExecutor()
+	value, err = qe.GetPrivateData("ns1", "coll1", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, value, []byte("pvt_value1_1"))
+	value, err = qe.GetPrivateData("ns1", "coll1", "key2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, value)
+	qe.Done()
+
+	// simulate tx4 whose private data is not made available at the time of commit
+	s4, _ := txMgr.NewTxSimulator()
+	s4.SetPrivateData("ns1", "coll1", "key1", []byte("pvt_value1_2"))
+	s4.Done()
+	txRWSet4, _ := s4.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet4)
+
+	qe, _ = txMgr.NewQueryExecutor()
+	_, err = qe.GetPrivateData("ns1", "coll1", "key1")
+	testutil.AssertError(t, err, "Expected an error while reading private data that is not in sync with the committed hash")
+	qe.Done()
+}
+
+func TestTxPvtDataHashMismatch(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Run(testEnv.getName(), func(t *testing.T) {
+			testLedgerID := "testtxpvtdatahashmismatch"
+			testEnv.init(t, testLedgerID)
+			testTxPvtDataHashMismatch(t, testEnv)
+			testEnv.cleanup()
+		})
+	}
+}
+
+func testTxPvtDataHashMismatch(t *testing.T, env testEnv) {
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	s1, _ := txMgr.NewTxSimulator()
+	s1.SetPrivateData("ns1", "coll1", "key1", []byte("pvt_value1"))
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+
+	s2, _ := txMgr.NewTxSimulator()
+	s2.SetPrivateData("ns1", "coll1", "key1", []byte("tampered_value"))
+	s2.Done()
+	tamperedPvtRWSet, _ := s2.GetPvtSimulationResults()
+
+	// the private data that does not match the hash in the block is not committed
+	txMgrHelper.validateAndCommitRWSetWithPvtData(txRWSet1, tamperedPvtRWSet)
+	qe, _ := txMgr.NewQueryExecutor()
+	defer qe.Done()
+	_, err := qe.GetPrivateData("ns1", "coll1", "key1")
+	testutil.AssertError(t, err, "Expected an error while reading private data that failed the hash check")
+}
+
+type mockStateListener struct {
+	namespaces []string
+	ledgerID   string
+	updates    []ledger.StateUpdates
+}
+
+func (l *mockStateListener) InterestedInNamespaces() []string {
+	return l.namespaces
+}
+
+func (l *mockStateListener) HandleStateUpdates(ledgerID string, stateUpdates ledger.StateUpdates) error {
+	l.ledgerID = ledgerID
+	l.updates = append(l.updates, stateUpdates)
+	return nil
+}
+
+func TestStateListener(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Run(testEnv.getName(), func(t *testing.T) {
+			testLedgerID := "teststatelistener"
+			testEnv.init(t, testLedgerID)
+			testStateListener(t, testEnv, testLedgerID)
+			testEnv.cleanup()
+		})
+	}
+}
+
+func testStateListener(t *testing.T, env testEnv, testLedgerID string) {
+	listener := &mockStateListener{namespaces: []string{"ns1", "ns3"}}
+	txMgr := env.getTxMgr()
+	txMgr.(*LockBasedTxMgr).stateListeners = []ledger.StateListener{listener}
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+
+	// the updates of the namespaces the listener is interested in are notified, in the order of the keys
+	s1, _ := txMgr.NewTxSimulator()
+	s1.SetState("ns1", "key2", []byte("value2"))
+	s1.SetState("ns1", "key1", []byte("value1"))
+	s1.SetState("ns2", "key1", []byte("value1"))
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1)
+	testutil.AssertEquals(t, listener.ledgerID, testLedgerID)
+	testutil.AssertEquals(t, listener.updates, []ledger.StateUpdates{
+		{"ns1": {
+			{Key: "key1", Value: []byte("value1")},
+			{Key: "key2", Value: []byte("value2")},
+		}},
+	})
+
+	// the listener is not invoked for the updates of other namespaces
+	s2, _ := txMgr.NewTxSimulator()
+	s2.SetState("ns2", "key2", []byte("value2"))
+	s2.Done()
+	txRWSet2, _ := s2.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet2)
+	testutil.AssertEquals(t, len(listener.updates), 1)
+
+	// deletes are notified
+	s3, _ := txMgr.NewTxSimulator()
+	s3.DeleteState("ns1", "key1")
+	s3.Done()
+	txRWSet3, _ := s3.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet3)
+	testutil.AssertEquals(t, listener.updates[1], ledger.StateUpdates{
+		"ns1": {{Key: "key1", IsDelete: true}},
+	})
+}
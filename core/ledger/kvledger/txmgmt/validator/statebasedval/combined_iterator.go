@@ -147,7 +147,7 @@ func (itr *combinedIterator) serveEndKeyIfNeeded() (statedb.QueryResult, error)
 	}
 	vkv := &statedb.VersionedKV{
 		CompositeKey:   statedb.CompositeKey{Namespace: itr.ns, Key: itr.endKey},
-		VersionedValue: statedb.VersionedValue{Value: vv.Value, Version: vv.Version}}
+		VersionedValue: statedb.VersionedValue{Value: vv.Value, Metadata: vv.Metadata, Version: vv.Version}}
 
 	if isDelete(vkv) {
 		return nil, nil
@@ -0,0 +1,380 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebasedval
+
+import (
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/common/crosschannel"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/protos/peer"
+)
+
+// The part of a cross-channel transaction on the channel holds, while it is
+// prepared, an exclusive lock on every key it writes, a shared lock on every
+// other key it reads and a range lock on every range of keys it queries. The
+// locks are kept in the namespace of XSCC along with the records of the
+// transactions, under keys which cannot be transaction IDs. An exclusive lock
+// is keyed by the key locked, whereas a shared lock is keyed by the key locked
+// and the transaction holding it. A range lock is keyed by the namespace, the
+// transaction holding it and the index of the range query, and holds the range
+const (
+	exclusiveLockPrefix    = "\x00x\x00"
+	exclusiveLockPrefixEnd = "\x00x\x01"
+	sharedLockPrefix       = "\x00s\x00"
+	sharedLockPrefixEnd    = "\x00s\x01"
+	rangeLockPrefix        = "\x00r\x00"
+	rangeLockPrefixEnd     = "\x00r\x01"
+)
+
+func exclusiveLockKey(ns, key string) string {
+	return exclusiveLockPrefix + ns + "\x00" + key
+}
+
+func sharedLockKeyPrefix(ns, key string) string {
+	return sharedLockPrefix + ns + "\x00" + key + "\x00"
+}
+
+func rangeLockKey(ns, txID string, i int) string {
+	return rangeLockPrefix + ns + "\x00" + txID + "\x00" + strconv.Itoa(i)
+}
+
+// crossChannelBatch adds the valid transactions of a block to the batch of
+// its updates, holding the writes of the parts of the cross-channel
+// transactions until the transactions are decided
+type crossChannelBatch struct {
+	v       *Validator
+	updates *statedb.UpdateBatch
+	// locksChecked is set once the locks held before the block are looked
+	// up, and locksHeld if there may be any lock held
+	locksChecked bool
+	locksHeld    bool
+}
+
+func newCrossChannelBatch(v *Validator, updates *statedb.UpdateBatch) *crossChannelBatch {
+	return &crossChannelBatch{v: v, updates: updates}
+}
+
+// addTx adds the updates of a transaction which passed the MVCC validation to
+// the batch. The writes of the part of a cross-channel transaction are held in
+// its record until the transaction is decided, and the keys the part reads or
+// writes, as well as the ranges of keys it queries, are locked meanwhile, which
+// makes the transactions reading a key it writes, or writing a key it reads,
+// writes or queries, invalid. The writes held are
+// applied if the transaction is committed, or dropped if it is aborted. The
+// checks of the transaction against the locks and against the record of the
+// cross-channel transaction are skipped if doChecks is false
+func (b *crossChannelBatch) addTx(txID string, txRWSet *rwsetutil.TxRwSet, txHeight *version.Height, doChecks bool) (peer.TxValidationCode, error) {
+	key, record, err := crosschannel.GetRecord(txRWSet)
+	if err != nil {
+		logger.Warningf("Transaction %s writes an invalid cross-channel record: %s", txID, err)
+		return peer.TxValidationCode_INVALID_CROSS_CHANNEL_TRANSITION, nil
+	}
+
+	if record == nil || record.Status == peer.CrossChannelRecord_PREPARED {
+		if doChecks {
+			if conflict, err := b.conflictsWithLocks(txRWSet); err != nil || conflict {
+				return peer.TxValidationCode_CROSS_CHANNEL_LOCK_CONFLICT, err
+			}
+		}
+		if record == nil {
+			return peer.TxValidationCode_VALID, b.v.addWriteSetToBatch(txRWSet, txHeight, b.updates)
+		}
+	}
+
+	current, err := b.getRecord(key)
+	if err != nil {
+		return peer.TxValidationCode(-1), err
+	}
+	if doChecks && !isValidTransition(current, record) {
+		logger.Warningf("Transaction %s records cross-channel transaction %s as %s, which is invalid after %s",
+			txID, key, record.Status, statusOf(current))
+		return peer.TxValidationCode_INVALID_CROSS_CHANNEL_TRANSITION, nil
+	}
+
+	switch record.Status {
+	case peer.CrossChannelRecord_PREPARED:
+		pending := &rwsetutil.TxRwSet{}
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			if nsRWSet.NameSpace != crosschannel.Namespace {
+				pending.NsRwSets = append(pending.NsRwSets, nsRWSet)
+			}
+		}
+		if record.Pending, err = pending.ToProtoBytes(); err != nil {
+			return peer.TxValidationCode(-1), err
+		}
+		if err = b.lock(key, pending, txHeight); err != nil {
+			return peer.TxValidationCode(-1), err
+		}
+	default:
+		if current != nil && current.Status == peer.CrossChannelRecord_PREPARED {
+			pending := &rwsetutil.TxRwSet{}
+			if err = pending.FromProtoBytes(current.Pending); err != nil {
+				return peer.TxValidationCode(-1), err
+			}
+			b.unlock(key, pending, txHeight)
+			if record.Status == peer.CrossChannelRecord_COMMITTED {
+				if err = b.v.addWriteSetToBatch(pending, txHeight, b.updates); err != nil {
+					return peer.TxValidationCode(-1), err
+				}
+			}
+		}
+		// the record keeps the channels of the prepared transaction, if any
+		channels := record.Channels
+		if current != nil {
+			channels = current.Channels
+		}
+		record = &peer.CrossChannelRecord{Status: record.Status, Channels: channels}
+	}
+
+	recordBytes, err := proto.Marshal(record)
+	if err != nil {
+		return peer.TxValidationCode(-1), err
+	}
+	b.updates.Put(crosschannel.Namespace, key, recordBytes, txHeight)
+	return peer.TxValidationCode_VALID, nil
+}
+
+// isValidTransition returns true if a cross-channel transaction with the
+// current record, or none, can be recorded as the record written
+func isValidTransition(current, written *peer.CrossChannelRecord) bool {
+	switch written.Status {
+	case peer.CrossChannelRecord_PREPARED:
+		return current == nil
+	case peer.CrossChannelRecord_COMMITTED:
+		return current != nil && current.Status == peer.CrossChannelRecord_PREPARED
+	case peer.CrossChannelRecord_ABORTED:
+		return current == nil || current.Status == peer.CrossChannelRecord_PREPARED
+	}
+	return false
+}
+
+func statusOf(record *peer.CrossChannelRecord) string {
+	if record == nil {
+		return "no record"
+	}
+	return record.Status.String()
+}
+
+// getRecord returns the record of a cross-channel transaction as updated by
+// the preceding valid transactions of the block, if any, or else as committed
+func (b *crossChannelBatch) getRecord(txID string) (*peer.CrossChannelRecord, error) {
+	vv, err := b.v.getLatestState(crosschannel.Namespace, txID, b.updates)
+	if err != nil || vv == nil {
+		return nil, err
+	}
+	return crosschannel.UnmarshalRecord(vv.Value)
+}
+
+func (b *crossChannelBatch) lock(txID string, pending *rwsetutil.TxRwSet, txHeight *version.Height) error {
+	forEachKey(pending, func(ns, key string, written bool) {
+		if written {
+			b.updates.Put(crosschannel.Namespace, exclusiveLockKey(ns, key), []byte(txID), txHeight)
+		} else {
+			b.updates.Put(crosschannel.Namespace, sharedLockKeyPrefix(ns, key)+txID, []byte(txID), txHeight)
+		}
+	})
+	for _, nsRWSet := range pending.NsRwSets {
+		for i, rqi := range nsRWSet.KvRwSet.RangeQueriesInfo {
+			rangeBytes, err := proto.Marshal(&kvrwset.RangeQueryInfo{StartKey: rqi.StartKey, EndKey: rqi.EndKey})
+			if err != nil {
+				return err
+			}
+			b.updates.Put(crosschannel.Namespace, rangeLockKey(nsRWSet.NameSpace, txID, i), rangeBytes, txHeight)
+		}
+	}
+	b.locksHeld = true
+	return nil
+}
+
+func (b *crossChannelBatch) unlock(txID string, pending *rwsetutil.TxRwSet, txHeight *version.Height) {
+	forEachKey(pending, func(ns, key string, written bool) {
+		if written {
+			b.updates.Delete(crosschannel.Namespace, exclusiveLockKey(ns, key), txHeight)
+		} else {
+			b.updates.Delete(crosschannel.Namespace, sharedLockKeyPrefix(ns, key)+txID, txHeight)
+		}
+	})
+	for _, nsRWSet := range pending.NsRwSets {
+		for i := range nsRWSet.KvRwSet.RangeQueriesInfo {
+			b.updates.Delete(crosschannel.Namespace, rangeLockKey(nsRWSet.NameSpace, txID, i), txHeight)
+		}
+	}
+}
+
+// conflictsWithLocks returns true if the transaction reads a key locked
+// exclusively, queries a range of keys holding one, or writes a key locked
+// by a cross-channel transaction or in a range it locks
+func (b *crossChannelBatch) conflictsWithLocks(txRWSet *rwsetutil.TxRwSet) (bool, error) {
+	if held, err := b.anyLockHeld(); err != nil || !held {
+		return false, err
+	}
+	conflict := false
+	var err error
+	forEachKey(txRWSet, func(ns, key string, written bool) {
+		if conflict || err != nil {
+			return
+		}
+		var vv *statedb.VersionedValue
+		if vv, err = b.v.getLatestState(crosschannel.Namespace, exclusiveLockKey(ns, key), b.updates); err != nil || vv != nil {
+			conflict = vv != nil
+			return
+		}
+		if written {
+			prefix := sharedLockKeyPrefix(ns, key)
+			conflict, err = b.anyKeyInRange(prefix, prefix[:len(prefix)-1]+"\x01")
+		}
+	})
+	if !conflict && err == nil {
+		conflict, err = b.conflictsWithRangeLocks(txRWSet)
+	}
+	if conflict {
+		logger.Debugf("The transaction conflicts with the locks of a cross-channel transaction")
+	}
+	return conflict, err
+}
+
+// conflictsWithRangeLocks returns true if the transaction queries a range of
+// keys holding an exclusive lock, or writes a key in a range locked
+func (b *crossChannelBatch) conflictsWithRangeLocks(txRWSet *rwsetutil.TxRwSet) (bool, error) {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		ns := nsRWSet.NameSpace
+		if ns == crosschannel.Namespace {
+			continue
+		}
+		for _, rqi := range nsRWSet.KvRwSet.RangeQueriesInfo {
+			endKey := exclusiveLockPrefix + ns + "\x01"
+			if rqi.EndKey != "" {
+				endKey = exclusiveLockKey(ns, rqi.EndKey)
+			}
+			if conflict, err := b.anyKeyInRange(exclusiveLockKey(ns, rqi.StartKey), endKey); err != nil || conflict {
+				return conflict, err
+			}
+		}
+
+		if len(nsRWSet.KvRwSet.Writes) == 0 && len(nsRWSet.KvRwSet.MetadataWrites) == 0 {
+			continue
+		}
+		ranges, err := b.lockedRanges(ns)
+		if err != nil {
+			return false, err
+		}
+		for _, r := range ranges {
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				if inRange(kvWrite.Key, r) {
+					return true, nil
+				}
+			}
+			for _, metadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+				if inRange(metadataWrite.Key, r) {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// lockedRanges returns the ranges of keys of the namespace locked by the
+// cross-channel transactions
+func (b *crossChannelBatch) lockedRanges(ns string) ([]*kvrwset.RangeQueryInfo, error) {
+	itr, err := newCombinedIterator(b.v.db, b.updates, crosschannel.Namespace, rangeLockPrefix+ns+"\x00", rangeLockPrefix+ns+"\x01", false)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+	var ranges []*kvrwset.RangeQueryInfo
+	for {
+		result, err := itr.Next()
+		if err != nil || result == nil {
+			return ranges, err
+		}
+		r := &kvrwset.RangeQueryInfo{}
+		if err = proto.Unmarshal(result.(*statedb.VersionedKV).Value, r); err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+}
+
+// inRange returns true if the key is in the range queried, whose end key is
+// excluded, unless empty as the range is then unbounded
+func inRange(key string, r *kvrwset.RangeQueryInfo) bool {
+	return key >= r.StartKey && (r.EndKey == "" || key < r.EndKey)
+}
+
+// anyLockHeld returns false if no lock is held by the cross-channel
+// transactions, and true if there may be any
+func (b *crossChannelBatch) anyLockHeld() (bool, error) {
+	if b.locksChecked || b.locksHeld {
+		return b.locksHeld, nil
+	}
+	exclusive, err := b.anyKeyInRange(exclusiveLockPrefix, exclusiveLockPrefixEnd)
+	if err != nil {
+		return false, err
+	}
+	shared, err := b.anyKeyInRange(sharedLockPrefix, sharedLockPrefixEnd)
+	if err != nil {
+		return false, err
+	}
+	ranges, err := b.anyKeyInRange(rangeLockPrefix, rangeLockPrefixEnd)
+	if err != nil {
+		return false, err
+	}
+	b.locksChecked = true
+	b.locksHeld = exclusive || shared || ranges
+	return b.locksHeld, nil
+}
+
+func (b *crossChannelBatch) anyKeyInRange(startKey, endKey string) (bool, error) {
+	itr, err := newCombinedIterator(b.v.db, b.updates, crosschannel.Namespace, startKey, endKey, false)
+	if err != nil {
+		return false, err
+	}
+	defer itr.Close()
+	result, err := itr.Next()
+	return result != nil, err
+}
+
+// forEachKey calls f for every key a transaction reads or writes outside of
+// the namespace of XSCC, once per key, with written set if it writes the key
+func forEachKey(txRWSet *rwsetutil.TxRwSet, f func(ns, key string, written bool)) {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		ns := nsRWSet.NameSpace
+		if ns == crosschannel.Namespace {
+			continue
+		}
+		written := make(map[string]bool)
+		var keys []string
+		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			if !written[kvWrite.Key] {
+				written[kvWrite.Key] = true
+				keys = append(keys, kvWrite.Key)
+			}
+		}
+		for _, metadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			if !written[metadataWrite.Key] {
+				written[metadataWrite.Key] = true
+				keys = append(keys, metadataWrite.Key)
+			}
+		}
+		for _, key := range keys {
+			f(ns, key, true)
+		}
+		read := make(map[string]bool)
+		for _, kvRead := range nsRWSet.KvRwSet.Reads {
+			if !written[kvRead.Key] && !read[kvRead.Key] {
+				read[kvRead.Key] = true
+				f(ns, kvRead.Key, false)
+			}
+		}
+	}
+}
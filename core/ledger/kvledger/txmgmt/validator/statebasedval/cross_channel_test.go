@@ -0,0 +1,235 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebasedval
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/common/crosschannel"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestCrossChannelCommit(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
+	db.ApplyUpdates(batch, version.NewHeight(1, 1))
+	validator := NewValidator(db)
+
+	// the part is valid, but its writes are held until the transaction is decided
+	part := rwsetutil.NewRWSetBuilder()
+	part.AddToReadSet("ns1", "key2", version.NewHeight(1, 1))
+	part.AddToWriteSet("ns1", "key1", []byte("value1_new"))
+	addTestRecord(t, part, "xtx1", peer.CrossChannelRecord_PREPARED)
+	checkCrossChannelValidation(t, db, validator, 2, []*rwsetutil.TxRwSet{part.GetTxReadWriteSet()},
+		[]peer.TxValidationCode{peer.TxValidationCode_VALID})
+	checkTestValue(t, db, "ns1", "key1", []byte("value1"))
+	checkTestRecordStatus(t, db, "xtx1", peer.CrossChannelRecord_PREPARED)
+
+	// the keys the part reads or writes are locked
+	writeKey1 := rwsetutil.NewRWSetBuilder()
+	writeKey1.AddToWriteSet("ns1", "key1", []byte("value1_other"))
+	readKey1 := rwsetutil.NewRWSetBuilder()
+	readKey1.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	writeKey2 := rwsetutil.NewRWSetBuilder()
+	writeKey2.AddToWriteSet("ns1", "key2", []byte("value2_other"))
+	readKey2 := rwsetutil.NewRWSetBuilder()
+	readKey2.AddToReadSet("ns1", "key2", version.NewHeight(1, 1))
+	checkCrossChannelValidation(t, db, validator, 3,
+		[]*rwsetutil.TxRwSet{writeKey1.GetTxReadWriteSet(), readKey1.GetTxReadWriteSet(),
+			writeKey2.GetTxReadWriteSet(), readKey2.GetTxReadWriteSet()},
+		[]peer.TxValidationCode{peer.TxValidationCode_CROSS_CHANNEL_LOCK_CONFLICT, peer.TxValidationCode_CROSS_CHANNEL_LOCK_CONFLICT,
+			peer.TxValidationCode_CROSS_CHANNEL_LOCK_CONFLICT, peer.TxValidationCode_VALID})
+
+	// committing the transaction applies the writes held and releases the locks
+	commit := rwsetutil.NewRWSetBuilder()
+	addTestRecord(t, commit, "xtx1", peer.CrossChannelRecord_COMMITTED)
+	checkCrossChannelValidation(t, db, validator, 4,
+		[]*rwsetutil.TxRwSet{commit.GetTxReadWriteSet(), writeKey2.GetTxReadWriteSet()},
+		[]peer.TxValidationCode{peer.TxValidationCode_VALID, peer.TxValidationCode_VALID})
+	checkTestValue(t, db, "ns1", "key1", []byte("value1_new"))
+	checkTestValue(t, db, "ns1", "key2", []byte("value2_other"))
+	checkTestRecordStatus(t, db, "xtx1", peer.CrossChannelRecord_COMMITTED)
+	anyLock, err := newCrossChannelBatch(validator, statedb.NewUpdateBatch()).anyLockHeld()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, anyLock, false)
+}
+
+func TestCrossChannelAbort(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+	validator := NewValidator(db)
+
+	// the part and its abort within the same block
+	part := rwsetutil.NewRWSetBuilder()
+	part.AddToWriteSet("ns1", "key1", []byte("value1"))
+	addTestRecord(t, part, "xtx1", peer.CrossChannelRecord_PREPARED)
+	abort := rwsetutil.NewRWSetBuilder()
+	addTestRecord(t, abort, "xtx1", peer.CrossChannelRecord_ABORTED)
+	writeKey1 := rwsetutil.NewRWSetBuilder()
+	writeKey1.AddToWriteSet("ns1", "key1", []byte("value1_other"))
+	checkCrossChannelValidation(t, db, validator, 1,
+		[]*rwsetutil.TxRwSet{part.GetTxReadWriteSet(), abort.GetTxReadWriteSet(), writeKey1.GetTxReadWriteSet()},
+		[]peer.TxValidationCode{peer.TxValidationCode_VALID, peer.TxValidationCode_VALID, peer.TxValidationCode_VALID})
+	checkTestValue(t, db, "ns1", "key1", []byte("value1_other"))
+	checkTestRecordStatus(t, db, "xtx1", peer.CrossChannelRecord_ABORTED)
+
+	// a transaction aborted before its part is prepared cannot be prepared
+	abort2 := rwsetutil.NewRWSetBuilder()
+	addTestRecord(t, abort2, "xtx2", peer.CrossChannelRecord_ABORTED)
+	part2 := rwsetutil.NewRWSetBuilder()
+	part2.AddToWriteSet("ns1", "key2", []byte("value2"))
+	addTestRecord(t, part2, "xtx2", peer.CrossChannelRecord_PREPARED)
+	checkCrossChannelValidation(t, db, validator, 2,
+		[]*rwsetutil.TxRwSet{abort2.GetTxReadWriteSet(), part2.GetTxReadWriteSet()},
+		[]peer.TxValidationCode{peer.TxValidationCode_VALID, peer.TxValidationCode_INVALID_CROSS_CHANNEL_TRANSITION})
+	checkTestValue(t, db, "ns1", "key2", nil)
+}
+
+func TestCrossChannelRangeLocks(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+	validator := NewValidator(db)
+
+	// the part queries a range of keys and writes a key out of it
+	part := rwsetutil.NewRWSetBuilder()
+	part.AddToRangeQuerySet("ns1", newTestRangeQuery("key1", "key5"))
+	part.AddToWriteSet("ns1", "key9", []byte("value9"))
+	addTestRecord(t, part, "xtx1", peer.CrossChannelRecord_PREPARED)
+	checkCrossChannelValidation(t, db, validator, 1, []*rwsetutil.TxRwSet{part.GetTxReadWriteSet()},
+		[]peer.TxValidationCode{peer.TxValidationCode_VALID})
+
+	// a key cannot be inserted in the range the part queries...
+	insertKey3 := rwsetutil.NewRWSetBuilder()
+	insertKey3.AddToWriteSet("ns1", "key3", []byte("value3"))
+	insertKey7 := rwsetutil.NewRWSetBuilder()
+	insertKey7.AddToWriteSet("ns1", "key7", []byte("value7"))
+	// ...nor can a range holding the key it writes be queried
+	queryKey9 := rwsetutil.NewRWSetBuilder()
+	queryKey9.AddToRangeQuerySet("ns1", newTestRangeQuery("key8", ""))
+	queryBeforeKey9 := rwsetutil.NewRWSetBuilder()
+	queryBeforeKey9.AddToRangeQuerySet("ns1", newTestRangeQuery("key8", "key9"))
+	checkCrossChannelValidation(t, db, validator, 2,
+		[]*rwsetutil.TxRwSet{insertKey3.GetTxReadWriteSet(), insertKey7.GetTxReadWriteSet(),
+			queryKey9.GetTxReadWriteSet(), queryBeforeKey9.GetTxReadWriteSet()},
+		[]peer.TxValidationCode{peer.TxValidationCode_CROSS_CHANNEL_LOCK_CONFLICT, peer.TxValidationCode_VALID,
+			peer.TxValidationCode_CROSS_CHANNEL_LOCK_CONFLICT, peer.TxValidationCode_VALID})
+	checkTestValue(t, db, "ns1", "key3", nil)
+
+	// the range locks are released once the transaction is decided
+	commit := rwsetutil.NewRWSetBuilder()
+	addTestRecord(t, commit, "xtx1", peer.CrossChannelRecord_COMMITTED)
+	checkCrossChannelValidation(t, db, validator, 3,
+		[]*rwsetutil.TxRwSet{commit.GetTxReadWriteSet(), insertKey3.GetTxReadWriteSet()},
+		[]peer.TxValidationCode{peer.TxValidationCode_VALID, peer.TxValidationCode_VALID})
+	checkTestValue(t, db, "ns1", "key3", []byte("value3"))
+	anyLock, err := newCrossChannelBatch(validator, statedb.NewUpdateBatch()).anyLockHeld()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, anyLock, false)
+}
+
+// newTestRangeQuery returns a range query which reads no key
+func newTestRangeQuery(startKey, endKey string) *kvrwset.RangeQueryInfo {
+	rqi := &kvrwset.RangeQueryInfo{StartKey: startKey, EndKey: endKey, ItrExhausted: true}
+	rqi.SetRawReads([]*kvrwset.KVRead{})
+	return rqi
+}
+
+func TestCrossChannelTransitions(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+	validator := NewValidator(db)
+
+	commit := rwsetutil.NewRWSetBuilder()
+	addTestRecord(t, commit, "xtx1", peer.CrossChannelRecord_COMMITTED)
+	part := rwsetutil.NewRWSetBuilder()
+	addTestRecord(t, part, "xtx2", peer.CrossChannelRecord_PREPARED)
+	partAgain := rwsetutil.NewRWSetBuilder()
+	addTestRecord(t, partAgain, "xtx2", peer.CrossChannelRecord_PREPARED)
+	twoRecords := rwsetutil.NewRWSetBuilder()
+	twoRecords.AddToWriteSet(crosschannel.Namespace, "xtx3", testRecordBytes(t, peer.CrossChannelRecord_ABORTED))
+	twoRecords.AddToWriteSet(crosschannel.Namespace, "xtx4", testRecordBytes(t, peer.CrossChannelRecord_ABORTED))
+	deleteRecord := rwsetutil.NewRWSetBuilder()
+	deleteRecord.AddToWriteSet(crosschannel.Namespace, "xtx2", nil)
+	checkCrossChannelValidation(t, db, validator, 1,
+		[]*rwsetutil.TxRwSet{commit.GetTxReadWriteSet(), part.GetTxReadWriteSet(), partAgain.GetTxReadWriteSet(),
+			twoRecords.GetTxReadWriteSet(), deleteRecord.GetTxReadWriteSet()},
+		[]peer.TxValidationCode{peer.TxValidationCode_INVALID_CROSS_CHANNEL_TRANSITION, peer.TxValidationCode_VALID,
+			peer.TxValidationCode_INVALID_CROSS_CHANNEL_TRANSITION, peer.TxValidationCode_INVALID_CROSS_CHANNEL_TRANSITION,
+			peer.TxValidationCode_INVALID_CROSS_CHANNEL_TRANSITION})
+	checkTestRecordStatus(t, db, "xtx2", peer.CrossChannelRecord_PREPARED)
+}
+
+func testRecordBytes(t *testing.T, status peer.CrossChannelRecord_Status) []byte {
+	recordBytes, err := proto.Marshal(&peer.CrossChannelRecord{Status: status, Channels: []string{"ch1", "ch2"}})
+	testutil.AssertNoError(t, err, "")
+	return recordBytes
+}
+
+func addTestRecord(t *testing.T, builder *rwsetutil.RWSetBuilder, txID string, status peer.CrossChannelRecord_Status) {
+	builder.AddToWriteSet(crosschannel.Namespace, txID, testRecordBytes(t, status))
+}
+
+func checkTestValue(t *testing.T, db statedb.VersionedDB, ns, key string, expectedValue []byte) {
+	vv, err := db.GetState(ns, key)
+	testutil.AssertNoError(t, err, "")
+	if expectedValue == nil {
+		testutil.AssertNil(t, vv)
+		return
+	}
+	testutil.AssertNotNil(t, vv)
+	testutil.AssertEquals(t, vv.Value, expectedValue)
+}
+
+func checkTestRecordStatus(t *testing.T, db statedb.VersionedDB, txID string, expectedStatus peer.CrossChannelRecord_Status) {
+	vv, err := db.GetState(crosschannel.Namespace, txID)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNotNil(t, vv)
+	record, err := crosschannel.UnmarshalRecord(vv.Value)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, record.Status, expectedStatus)
+}
+
+// checkCrossChannelValidation validates a block of the transactions and
+// commits the updates to the db
+func checkCrossChannelValidation(t *testing.T, db statedb.VersionedDB, validator *Validator, blockNum uint64,
+	rwsets []*rwsetutil.TxRwSet, expectedCodes []peer.TxValidationCode) {
+	simulationResults := [][]byte{}
+	for _, txRWS := range rwsets {
+		sr, err := txRWS.ToProtoBytes()
+		testutil.AssertNoError(t, err, "")
+		simulationResults = append(simulationResults, sr)
+	}
+	block := testutil.ConstructBlock(t, blockNum, []byte("dummyPreviousHash"), simulationResults, false)
+	updates, err := validator.ValidateAndPrepareBatch(block, true)
+	testutil.AssertNoError(t, err, "")
+	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for i, expectedCode := range expectedCodes {
+		testutil.AssertEquals(t, txsFltr.Flag(i), expectedCode)
+	}
+	testutil.AssertNoError(t, db.ApplyUpdates(updates, version.NewHeight(blockNum, uint64(len(rwsets)))), "")
+}
@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebasedval
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+// ValidateAndPreparePvtBatch implements method in Validator interface.
+// It prepares the updates for the private state from the private data of the valid transactions in the block.
+// The private data of a collection is ignored if its hash does not match the hash present in the public
+// read-write set of the transaction. This function is expected to be invoked after 'ValidateAndPrepareBatch'
+// so that the validation flags in the block reflect the result of mvcc validation as well
+func (v *Validator) ValidateAndPreparePvtBatch(block *common.Block, pvtData map[uint64]*ledger.TxPvtData) (*statedb.UpdateBatch, error) {
+	pvtUpdates := statedb.NewUpdateBatch()
+	txsFilter := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+
+	for txNum, txPvtData := range pvtData {
+		if txNum >= uint64(len(block.Data.Data)) {
+			return nil, fmt.Errorf("private data present for tx num [%d] that does not exist in block [%d]",
+				txNum, block.Header.Number)
+		}
+		if txsFilter.IsInvalid(int(txNum)) {
+			logger.Debugf("Block [%d] Transaction index [%d] is invalid, ignoring its private data",
+				block.Header.Number, txNum)
+			continue
+		}
+		if txPvtData == nil || txPvtData.WriteSet == nil {
+			continue
+		}
+		respPayload, err := putils.GetActionFromEnvelope(block.Data.Data[txNum])
+		if err != nil {
+			return nil, err
+		}
+		txRWSet := &rwsetutil.TxRwSet{}
+		if err := txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+			return nil, err
+		}
+		committingTxHeight := version.NewHeight(block.Header.Number, txNum)
+		for _, nsPvtRWSet := range txPvtData.WriteSet.NsPvtRwset {
+			ns := nsPvtRWSet.Namespace
+			for _, collPvtRWSet := range nsPvtRWSet.CollectionPvtRwset {
+				coll := collPvtRWSet.CollectionName
+				expectedHash := getPvtRwSetHash(txRWSet, ns, coll)
+				if expectedHash == nil || !bytes.Equal(expectedHash, util.ComputeHash(collPvtRWSet.Rwset)) {
+					logger.Warningf("Block [%d] Transaction index [%d]: hash of private data for [%s:%s] does not match, ignoring it",
+						block.Header.Number, txNum, ns, coll)
+					continue
+				}
+				kvRWSet := &kvrwset.KVRWSet{}
+				if err := proto.Unmarshal(collPvtRWSet.Rwset, kvRWSet); err != nil {
+					return nil, err
+				}
+				pvtNs := statedb.DerivePvtDataNs(ns, coll)
+				for _, kvWrite := range kvRWSet.Writes {
+					if kvWrite.IsDelete {
+						pvtUpdates.Delete(pvtNs, kvWrite.Key, committingTxHeight)
+					} else {
+						pvtUpdates.Put(pvtNs, kvWrite.Key, kvWrite.Value, committingTxHeight)
+					}
+				}
+			}
+		}
+	}
+	return pvtUpdates, nil
+}
+
+func getPvtRwSetHash(txRWSet *rwsetutil.TxRwSet, ns string, coll string) []byte {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != ns {
+			continue
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			if collHashedRWSet.CollectionName == coll {
+				return collHashedRWSet.PvtRwSetHash
+			}
+		}
+	}
+	return nil
+}
@@ -88,6 +88,7 @@ func (v *Validator) ValidateAndPrepareBatch(block *common.Block, doMVCCValidatio
 		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
 	}
 
+	crossChannelUpdates := newCrossChannelBatch(v, updates)
 	for txIndex, envBytes := range block.Data.Data {
 		if txsFilter.IsInvalid(txIndex) {
 			// Skiping invalid transaction
@@ -127,11 +128,14 @@ func (v *Validator) ValidateAndPrepareBatch(block *common.Block, doMVCCValidatio
 
 		txsFilter.SetFlag(txIndex, txResult)
 
-		//txRWSet != nil => t is valid
+		//txRWSet != nil => t is valid, unless it conflicts with a cross-channel transaction
 		if txRWSet != nil {
 			committingTxHeight := version.NewHeight(block.Header.Number, uint64(txIndex))
-			addWriteSetToBatch(txRWSet, committingTxHeight, updates)
-			txsFilter.SetFlag(txIndex, peer.TxValidationCode_VALID)
+			txResult, err = crossChannelUpdates.addTx(chdr.TxId, txRWSet, committingTxHeight, doMVCCValidation)
+			if err != nil {
+				return nil, err
+			}
+			txsFilter.SetFlag(txIndex, txResult)
 		}
 
 		if txsFilter.IsValid(txIndex) {
@@ -146,17 +150,78 @@ func (v *Validator) ValidateAndPrepareBatch(block *common.Block, doMVCCValidatio
 	return updates, nil
 }
 
-func addWriteSetToBatch(txRWSet *rwsetutil.TxRwSet, txHeight *version.Height, batch *statedb.UpdateBatch) {
+// addWriteSetToBatch adds the writes of a valid transaction to the batch. A write of a value retains
+// the metadata of the key unless the transaction also writes the metadata, whereas a write of the
+// metadata alone retains the value of the key. Deleting a key deletes its metadata as well
+func (v *Validator) addWriteSetToBatch(txRWSet *rwsetutil.TxRwSet, txHeight *version.Height, batch *statedb.UpdateBatch) error {
 	for _, nsRWSet := range txRWSet.NsRwSets {
 		ns := nsRWSet.NameSpace
+		metadataWrites := make(map[string][]byte)
+		for _, metadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			metadataBytes, err := statedb.SerializeMetadata(metadataWrite.Entries)
+			if err != nil {
+				return err
+			}
+			metadataWrites[metadataWrite.Key] = metadataBytes
+		}
 		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
 			if kvWrite.IsDelete {
 				batch.Delete(ns, kvWrite.Key, txHeight)
+				continue
+			}
+			metadataBytes, ok := metadataWrites[kvWrite.Key]
+			if ok {
+				delete(metadataWrites, kvWrite.Key)
 			} else {
-				batch.Put(ns, kvWrite.Key, kvWrite.Value, txHeight)
+				existing, err := v.getLatestState(ns, kvWrite.Key, batch)
+				if err != nil {
+					return err
+				}
+				if existing != nil {
+					metadataBytes = existing.Metadata
+				}
+			}
+			batch.PutValAndMetadata(ns, kvWrite.Key, kvWrite.Value, metadataBytes, txHeight)
+		}
+		for key, metadataBytes := range metadataWrites {
+			existing, err := v.getLatestState(ns, key, batch)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				// the metadata of a key which does not exist is ignored
+				continue
+			}
+			batch.PutValAndMetadata(ns, key, existing.Value, metadataBytes, txHeight)
+		}
+		// only the hashes of the private data go to the public state, the private data
+		// itself is added to a separate batch (see function 'ValidateAndPreparePvtBatch')
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			hashedNs := statedb.DeriveHashedDataNs(ns, collHashedRWSet.CollectionName)
+			for _, kvWriteHash := range collHashedRWSet.HashedRwSet.HashedWrites {
+				hashedKey := statedb.EncodeHashedKey(kvWriteHash.KeyHash)
+				if kvWriteHash.IsDelete {
+					batch.Delete(hashedNs, hashedKey, txHeight)
+				} else {
+					batch.Put(hashedNs, hashedKey, kvWriteHash.ValueHash, txHeight)
+				}
 			}
 		}
 	}
+	return nil
+}
+
+// getLatestState returns the value of a key as updated by the preceding valid transactions
+// of the block, if any, or else as committed. It returns nil if the key does not exist
+func (v *Validator) getLatestState(ns, key string, batch *statedb.UpdateBatch) (*statedb.VersionedValue, error) {
+	if batch.Exists(ns, key) {
+		vv := batch.Get(ns, key)
+		if vv.Value == nil {
+			return nil, nil
+		}
+		return vv, nil
+	}
+	return v.db.GetState(ns, key)
 }
 
 func (v *Validator) validateTx(txRWSet *rwsetutil.TxRwSet, updates *statedb.UpdateBatch) (peer.TxValidationCode, error) {
@@ -175,10 +240,31 @@ func (v *Validator) validateTx(txRWSet *rwsetutil.TxRwSet, updates *statedb.Upda
 			}
 			return peer.TxValidationCode_PHANTOM_READ_CONFLICT, nil
 		}
+		if valid, err := v.validateCollHashedReadSets(ns, nsRWSet.CollHashedRwSets, updates); !valid || err != nil {
+			if err != nil {
+				return peer.TxValidationCode(-1), err
+			}
+			return peer.TxValidationCode_MVCC_READ_CONFLICT, nil
+		}
 	}
 	return peer.TxValidationCode_VALID, nil
 }
 
+// validateCollHashedReadSets performs mvcc check for the private keys read during transaction simulation.
+// The check is performed against the hashes of the private data that are maintained in the public state
+func (v *Validator) validateCollHashedReadSets(ns string, collHashedRWSets []*rwsetutil.CollHashedRwSet, updates *statedb.UpdateBatch) (bool, error) {
+	for _, collHashedRWSet := range collHashedRWSets {
+		hashedNs := statedb.DeriveHashedDataNs(ns, collHashedRWSet.CollectionName)
+		for _, kvReadHash := range collHashedRWSet.HashedRwSet.HashedReads {
+			kvRead := &kvrwset.KVRead{Key: statedb.EncodeHashedKey(kvReadHash.KeyHash), Version: kvReadHash.Version}
+			if valid, err := v.validateKVRead(hashedNs, kvRead, updates); !valid || err != nil {
+				return valid, err
+			}
+		}
+	}
+	return true, nil
+}
+
 func (v *Validator) validateReadSet(ns string, kvReads []*kvrwset.KVRead, updates *statedb.UpdateBatch) (bool, error) {
 	for _, kvRead := range kvReads {
 		if valid, err := v.validateKVRead(ns, kvRead, updates); !valid || err != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package validator
 
 import (
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/protos/common"
 )
@@ -24,4 +25,5 @@ import (
 // Validator validates a rwset
 type Validator interface {
 	ValidateAndPrepareBatch(block *common.Block, doMVCCValidation bool) (*statedb.UpdateBatch, error)
+	ValidateAndPreparePvtBatch(block *common.Block, pvtData map[uint64]*ledger.TxPvtData) (*statedb.UpdateBatch, error)
 }
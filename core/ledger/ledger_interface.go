@@ -19,6 +19,8 @@ package ledger
 import (
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -28,12 +30,22 @@ type PeerLedgerProvider interface {
 	// This function guarantees that the creation of ledger and committing the genesis block would an atomic action
 	// The chain id retrieved from the genesis block is treated as a ledger id
 	Create(genesisBlock *common.Block) (PeerLedger, error)
+	// CreateFromSnapshot creates a new ledger from a snapshot exported by PeerLedger.ExportSnapshot.
+	// The block storage of the ledger starts at the last block of the snapshot, the preceding blocks
+	// are treated as pruned. The ledger id is the channel name recorded in the snapshot
+	CreateFromSnapshot(snapshotDir string) (PeerLedger, error)
 	// Open opens an already created ledger
 	Open(ledgerID string) (PeerLedger, error)
 	// Exists tells whether the ledger with given id exists
 	Exists(ledgerID string) (bool, error)
 	// List lists the ids of the existing ledgers
 	List() ([]string, error)
+	// Rollback removes the blocks that follow block number `blockNum` from a ledger that is not open. The state
+	// and history databases of the ledger are rebuilt from the remaining blocks when the ledger is opened next
+	Rollback(ledgerID string, blockNum uint64) error
+	// RebuildDBs drops the state and history databases and the block index of all the ledgers, none of which may
+	// be open. They are rebuilt from the blocks when the ledgers are opened next
+	RebuildDBs() error
 	// Close closes the PeerLedgerProvider
 	Close()
 }
@@ -64,6 +76,13 @@ type PeerLedger interface {
 	NewHistoryQueryExecutor() (HistoryQueryExecutor, error)
 	//Prune prunes the blocks/transactions that satisfy the given policy
 	Prune(policy commonledger.PrunePolicy) error
+	// CommitWithPvtData commits the block and the corresponding private data atomically.
+	// The private data is applied only for the transactions that are found valid and
+	// for which the hashes present in the block match the supplied private data
+	CommitWithPvtData(blockAndPvtdata *BlockAndPvtData) error
+	// ExportSnapshot writes a snapshot of the ledger at its last block to the given directory, which must
+	// not exist or be empty. Commits are blocked while the snapshot is written. Not supported with CouchDB
+	ExportSnapshot(snapshotDir string) error
 }
 
 // ValidatedLedger represents the 'final ledger' after filtering out invalid transactions from PeerLedger.
@@ -82,6 +101,9 @@ type QueryExecutor interface {
 	GetState(namespace string, key string) ([]byte, error)
 	// GetStateMultipleKeys gets the values for multiple keys in a single call
 	GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error)
+	// GetStateMetadata returns the metadata of the given namespace and key, such as the key-level
+	// endorsement policy of the key. A nil map is returned if the key has no metadata
+	GetStateMetadata(namespace, key string) (map[string][]byte, error)
 	// GetStateRangeScanIterator returns an iterator that contains all the key-values between given key ranges.
 	// startKey is included in the results and endKey is excluded. An empty startKey refers to the first available key
 	// and an empty endKey refers to the last available key. For scanning all the keys, both the startKey and the endKey
@@ -93,10 +115,28 @@ type QueryExecutor interface {
 	// For a chaincode, the namespace corresponds to the chaincodeId
 	// The returned ResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
 	ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error)
+	// GetStateRangeScanIteratorWithPagination works like GetStateRangeScanIterator but the returned iterator
+	// contains at most pageSize results. The bookmark of the iterator is the startKey of the next page
+	// and is empty when the range has been exhausted
+	GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (QueryResultsIterator, error)
+	// ExecuteQueryWithPagination works like ExecuteQuery but the returned iterator contains at most pageSize results,
+	// starting after the bookmark returned by a previous execution of the same query
+	ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (QueryResultsIterator, error)
+	// GetPrivateData gets the value of a private data item identified by a tuple <namespace, collection, key>
+	// The value is retrieved from the side database that holds the private data and is available only
+	// on the peers that are members of the collection
+	GetPrivateData(namespace, collection, key string) ([]byte, error)
 	// Done releases resources occupied by the QueryExecutor
 	Done()
 }
 
+// QueryResultsIterator - an iterator for the results of a paginated query
+type QueryResultsIterator interface {
+	commonledger.ResultsIterator
+	// GetBookmarkAndClose returns the bookmark for fetching the next page of results and closes the iterator
+	GetBookmarkAndClose() string
+}
+
 // HistoryQueryExecutor executes the history queries
 type HistoryQueryExecutor interface {
 	// GetHistoryForKey retrieves the history of values for a key.
@@ -114,6 +154,9 @@ type TxSimulator interface {
 	DeleteState(namespace string, key string) error
 	// SetMultipleKeys sets the values for multiple keys in a single call
 	SetStateMultipleKeys(namespace string, kvs map[string][]byte) error
+	// SetStateMetadata sets the metadata of the given namespace and key, replacing all its existing
+	// metadata entries. A nil or empty map removes the metadata of the key
+	SetStateMetadata(namespace, key string, metadata map[string][]byte) error
 	// ExecuteUpdate for supporting rich data model (see comments on QueryExecutor above)
 	ExecuteUpdate(query string) error
 	// GetTxSimulationResults encapsulates the results of the transaction simulation.
@@ -124,4 +167,38 @@ type TxSimulator interface {
 	// Different ledger implementation (or configurations of a single implementation) may want to represent the above two pieces
 	// of information in different way in order to support different data-models or optimize the information representations.
 	GetTxSimulationResults() ([]byte, error)
+	// SetPrivateData sets the given value to a key in the private data state represented by the tuple <namespace, collection, key>
+	SetPrivateData(namespace, collection, key string, value []byte) error
+	// DeletePrivateData deletes the given tuple <namespace, collection, key> from private data
+	DeletePrivateData(namespace, collection, key string) error
+	// GetPvtSimulationResults returns the private read-write set of the transaction simulation.
+	// The public results returned by GetTxSimulationResults carry only the hashes of this data.
+	// A nil value is returned if the transaction did not write any private data
+	GetPvtSimulationResults() (*rwset.TxPvtReadWriteSet, error)
 }
+
+// TxPvtData encapsulates the transaction number and pvt write-set for a transaction
+type TxPvtData struct {
+	SeqInBlock uint64
+	WriteSet   *rwset.TxPvtReadWriteSet
+}
+
+// BlockAndPvtData encapsulates the block and a map that contains the tuples <seqInBlock, *TxPvtData>
+// The map is expected to contain the entries only for the transactions that have associated pvt data
+type BlockAndPvtData struct {
+	Block        *common.Block
+	BlockPvtData map[uint64]*TxPvtData
+}
+
+// StateListener allows a custom code for performing additional stuff upon state change
+// for a particular namespace against which the listener is registered
+type StateListener interface {
+	// InterestedInNamespaces returns the namespaces the listener wants to be notified about
+	InterestedInNamespaces() []string
+	// HandleStateUpdates is invoked with the updates of the interested namespaces,
+	// once the state changes of a block are committed
+	HandleStateUpdates(ledgerID string, stateUpdates StateUpdates) error
+}
+
+// StateUpdates carries the committed writes of a block, keyed by namespace
+type StateUpdates map[string][]*kvrwset.KVWrite
@@ -49,6 +49,17 @@ func GetStateLevelDBPath() string {
 	return filepath.Join(GetRootPath(), "stateLeveldb")
 }
 
+// GetPvtStateLevelDBPath returns the filesystem path that is used to maintain the private state level db
+func GetPvtStateLevelDBPath() string {
+	return filepath.Join(GetRootPath(), "pvtStateLeveldb")
+}
+
+// GetTransientStorePath returns the filesystem path that is used to maintain the transient store
+// holding the private data of endorsed but not yet committed transactions
+func GetTransientStorePath() string {
+	return filepath.Join(GetRootPath(), "transientStore")
+}
+
 // GetHistoryLevelDBPath returns the filesystem path that is used to maintain the history level db
 func GetHistoryLevelDBPath() string {
 	return filepath.Join(GetRootPath(), "historyLeveldb")
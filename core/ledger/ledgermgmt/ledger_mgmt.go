@@ -18,6 +18,7 @@ package ledgermgmt
 
 import (
 	"errors"
+	"math"
 	"sync"
 
 	"fmt"
@@ -89,6 +90,49 @@ func CreateLedger(genesisBlock *common.Block) (ledger.PeerLedger, error) {
 	return l, nil
 }
 
+// CreateLedgerFromSnapshot creates a new ledger from the snapshot in the given directory.
+// The channel name recorded in the snapshot is treated as a ledger id
+func CreateLedgerFromSnapshot(snapshotDir string) (ledger.PeerLedger, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return nil, ErrLedgerMgmtNotInitialized
+	}
+
+	logger.Infof("Creating ledger from snapshot [%s]", snapshotDir)
+	l, err := ledgerProvider.CreateFromSnapshot(snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	id, err := getLedgerIDFromLastConfigBlock(l)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	l = wrapLedger(id, l)
+	openedLedgers[id] = l
+	logger.Infof("Created ledger [%s] from snapshot", id)
+	return l, nil
+}
+
+// getLedgerIDFromLastConfigBlock returns the chain id of the last config block of the ledger,
+// which is kept by a ledger created from a snapshot, unlike the genesis block
+func getLedgerIDFromLastConfigBlock(l ledger.PeerLedger) (string, error) {
+	lastBlock, err := l.GetBlockByNumber(math.MaxUint64)
+	if err != nil {
+		return "", err
+	}
+	lastConfigBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return "", err
+	}
+	lastConfigBlock, err := l.GetBlockByNumber(lastConfigBlockNum)
+	if err != nil {
+		return "", err
+	}
+	return utils.GetChainIDFromBlock(lastConfigBlock)
+}
+
 // OpenLedger returns a ledger for the given id
 func OpenLedger(id string) (ledger.PeerLedger, error) {
 	logger.Infof("Opening ledger with id = %s", id)
@@ -121,6 +165,34 @@ func GetLedgerIDs() ([]string, error) {
 	return ledgerProvider.List()
 }
 
+// RollbackLedger removes the blocks that follow the block `blockNum` from the ledger with the given id,
+// which must not be opened. The databases of the ledger are rebuilt when it is opened next
+func RollbackLedger(id string, blockNum uint64) error {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return ErrLedgerMgmtNotInitialized
+	}
+	if _, ok := openedLedgers[id]; ok {
+		return ErrLedgerAlreadyOpened
+	}
+	return ledgerProvider.Rollback(id, blockNum)
+}
+
+// RebuildDBs drops the databases and the block indexes of all the ledgers, none of which must be opened.
+// They are rebuilt when the ledgers are opened next
+func RebuildDBs() error {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return ErrLedgerMgmtNotInitialized
+	}
+	if len(openedLedgers) != 0 {
+		return ErrLedgerAlreadyOpened
+	}
+	return ledgerProvider.RebuildDBs()
+}
+
 // Close closes all the opened ledgers and any resources held for ledger management
 func Close() {
 	logger.Infof("Closing ledger mgmt")
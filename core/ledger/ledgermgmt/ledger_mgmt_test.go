@@ -91,6 +91,28 @@ func TestLedgerMgmt(t *testing.T) {
 	Close()
 }
 
+func TestRollbackAndRebuildDBs(t *testing.T) {
+	InitializeTestEnv()
+	defer CleanupTestEnv()
+	ledgerID := constructTestLedgerID(0)
+	gb, _ := test.MakeGenesisBlock(ledgerID)
+	l, err := CreateLedger(gb)
+	testutil.AssertNoError(t, err, "")
+
+	// the databases of an opened ledger are not dropped
+	testutil.AssertEquals(t, RollbackLedger(ledgerID, 0), ErrLedgerAlreadyOpened)
+	testutil.AssertEquals(t, RebuildDBs(), ErrLedgerAlreadyOpened)
+
+	l.Close()
+	testutil.AssertError(t, RollbackLedger(ledgerID, 0), "Rolling back to the last block should have failed")
+	testutil.AssertNoError(t, RebuildDBs(), "Error while rebuilding databases")
+	l, err = OpenLedger(ledgerID)
+	testutil.AssertNoError(t, err, "")
+	bcInfo, _ := l.GetBlockchainInfo()
+	testutil.AssertEquals(t, bcInfo.Height, uint64(1))
+	Close()
+}
+
 func constructTestLedgerID(i int) string {
 	return fmt.Sprintf("ledger_%06d", i)
 }
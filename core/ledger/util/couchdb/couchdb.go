@@ -100,8 +100,9 @@ type RangeQueryResponse struct {
 
 //QueryResponse is used for processing REST query responses from CouchDB
 type QueryResponse struct {
-	Warning string            `json:"warning"`
-	Docs    []json.RawMessage `json:"docs"`
+	Warning  string            `json:"warning"`
+	Docs     []json.RawMessage `json:"docs"`
+	Bookmark string            `json:"bookmark"`
 }
 
 //Doc is used for capturing if attachments are return in the query from CouchDB
@@ -201,6 +202,13 @@ type BatchUpdateResponse struct {
 	Rev    string `json:"rev"`
 }
 
+//CreateIndexResponse contains the result of an index creation in couchdb
+type CreateIndexResponse struct {
+	Result string `json:"result"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+}
+
 //Base64Attachment contains the definition for an attached file for couchdb
 type Base64Attachment struct {
 	ContentType    string `json:"content_type"`
@@ -936,6 +944,13 @@ func (dbclient *CouchDatabase) DeleteDoc(id, rev string) error {
 
 //QueryDocuments method provides function for processing a query
 func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, error) {
+	results, _, err := dbclient.QueryDocumentsWithBookmark(query)
+	return results, err
+}
+
+//QueryDocumentsWithBookmark method provides function for processing a query and also
+//returns the bookmark that can be set in the query to fetch the next page of results
+func (dbclient *CouchDatabase) QueryDocumentsWithBookmark(query string) (*[]QueryResult, string, error) {
 
 	logger.Debugf("Entering QueryDocuments()  query=%s", query)
 
@@ -944,7 +959,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 	queryURL, err := url.Parse(dbclient.CouchInstance.conf.URL)
 	if err != nil {
 		logger.Errorf("URL parse error: %s", err.Error())
-		return nil, err
+		return nil, "", err
 	}
 
 	queryURL.Path = dbclient.DBName + "/_find"
@@ -954,7 +969,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 
 	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodPost, queryURL.String(), []byte(query), "", "", maxRetries, true)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer closeResponseBody(resp)
 
@@ -969,14 +984,14 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 	//handle as JSON document
 	jsonResponseRaw, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var jsonResponse = &QueryResponse{}
 
 	err2 := json.Unmarshal(jsonResponseRaw, &jsonResponse)
 	if err2 != nil {
-		return nil, err2
+		return nil, "", err2
 	}
 
 	for _, row := range jsonResponse.Docs {
@@ -984,7 +999,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 		var jsonDoc = &Doc{}
 		err3 := json.Unmarshal(row, &jsonDoc)
 		if err3 != nil {
-			return nil, err3
+			return nil, "", err3
 		}
 
 		if jsonDoc.Attachments != nil {
@@ -993,7 +1008,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 
 			couchDoc, _, err := dbclient.ReadDoc(jsonDoc.ID)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 			var addDocument = &QueryResult{ID: jsonDoc.ID, Value: couchDoc.JSONValue, Attachments: couchDoc.Attachments}
 			results = append(results, *addDocument)
@@ -1008,8 +1023,52 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 	}
 	logger.Debugf("Exiting QueryDocuments()")
 
-	return &results, nil
+	return &results, jsonResponse.Bookmark, nil
+
+}
+
+//CreateIndex method provides a function creating an index in the database.
+//Creating an index which already exists is not an error, couchdb reports it with the result "exists"
+func (dbclient *CouchDatabase) CreateIndex(indexdefinition string) (*CreateIndexResponse, error) {
+
+	logger.Debugf("Entering CreateIndex()  indexdefinition=%s", indexdefinition)
+
+	//validate the index definition
+	if !IsJSON(indexdefinition) {
+		return nil, fmt.Errorf("JSON format is not valid for the index definition: %s", indexdefinition)
+	}
+
+	indexURL, err := url.Parse(dbclient.CouchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return nil, err
+	}
+
+	indexURL.Path = dbclient.DBName + "/_index"
+
+	//get the number of retries
+	maxRetries := dbclient.CouchInstance.conf.MaxRetries
+
+	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodPost, indexURL.String(), []byte(indexdefinition), "", "", maxRetries, true)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	couchDBReturn := &CreateIndexResponse{}
+	if err = json.Unmarshal(respBody, couchDBReturn); err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Index %s for database %s: %s", couchDBReturn.Name, dbclient.DBName, couchDBReturn.Result)
+	logger.Debugf("Exiting CreateIndex()")
 
+	return couchDBReturn, nil
 }
 
 //BatchRetrieveIDRevision - batch method to retrieve IDs and revisions
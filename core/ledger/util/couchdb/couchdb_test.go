@@ -846,6 +846,46 @@ func TestDBDeleteNonExistingDocument(t *testing.T) {
 	}
 }
 
+func TestDBCreateIndex(t *testing.T) {
+
+	if ledgerconfig.IsCouchDBEnabled() {
+
+		database := "testdbcreateindex"
+		err := cleanup(database)
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to cleanup  Error: %s", err))
+		defer cleanup(database)
+
+		if err == nil {
+			//create a new instance and database object
+			couchInstance, err := CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
+				couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout)
+			testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to create couch instance"))
+			db := CouchDatabase{CouchInstance: *couchInstance, DBName: database}
+
+			//create a new database
+			_, errdb := db.CreateDatabaseIfNotExist()
+			testutil.AssertNoError(t, errdb, fmt.Sprintf("Error when trying to create database"))
+
+			indexDef := `{"index":{"fields":["data.owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`
+
+			//create the index
+			resp, err := db.CreateIndex(indexDef)
+			testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to create an index"))
+			testutil.AssertEquals(t, resp.Result, "created")
+			testutil.AssertEquals(t, resp.Name, "indexOwner")
+
+			//creating the same index again is reported as existing
+			resp, err = db.CreateIndex(indexDef)
+			testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to recreate an index"))
+			testutil.AssertEquals(t, resp.Result, "exists")
+
+			//an invalid index definition is rejected
+			_, err = db.CreateIndex(`{"index"`)
+			testutil.AssertError(t, err, fmt.Sprintf("Expected an error for an invalid index definition"))
+		}
+	}
+}
+
 func TestCouchDBVersion(t *testing.T) {
 
 	err := checkCouchDBVersion("2.0.0")
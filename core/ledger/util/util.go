@@ -17,6 +17,7 @@ limitations under the License.
 package util
 
 import (
+	"crypto/sha256"
 	"reflect"
 	"sort"
 )
@@ -32,3 +33,15 @@ func GetSortedKeys(m interface{}) []string {
 	sort.Strings(keys)
 	return keys
 }
+
+// ComputeStringHash computes the hash of the given string
+func ComputeStringHash(input string) []byte {
+	return ComputeHash([]byte(input))
+}
+
+// ComputeHash computes the hash of the given bytes. This is used for computing the
+// hashes of the keys and values of the private data that go into the public read-write set
+func ComputeHash(input []byte) []byte {
+	hash := sha256.Sum256(input)
+	return hash[:]
+}
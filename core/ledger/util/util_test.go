@@ -17,6 +17,7 @@ limitations under the License.
 package util
 
 import (
+	"crypto/sha256"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,3 +33,9 @@ func TestGetSortedKeys(t *testing.T) {
 	mapKeyValue[""] = 30
 	assert.Equal(t, []string{"", "123", "a", "apple", "blue", "red"}, GetSortedKeys(mapKeyValue))
 }
+
+func TestComputeHash(t *testing.T) {
+	expected := sha256.Sum256([]byte("value1"))
+	assert.Equal(t, expected[:], ComputeHash([]byte("value1")))
+	assert.Equal(t, expected[:], ComputeStringHash("value1"))
+}
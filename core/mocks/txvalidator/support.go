@@ -52,3 +52,11 @@ func (ms *Support) PolicyManager() policies.Manager {
 func (cs *Support) GetMSPIDs(cid string) []string {
 	return []string{"DEFAULT"}
 }
+
+// Acquire does nothing
+func (cs *Support) Acquire() {
+}
+
+// Release does nothing
+func (cs *Support) Release() {
+}
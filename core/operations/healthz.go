@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HealthCheckTimeout is the time given to the HealthCheckers to complete
+var HealthCheckTimeout = 30 * time.Second
+
+// HealthChecker is implemented by the components whose health is reported on /healthz
+type HealthChecker interface {
+	// HealthCheck returns an error if the component is not healthy
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthStatus is the body of the responses of /healthz
+type HealthStatus struct {
+	Status       string        `json:"status"`
+	Time         time.Time     `json:"time"`
+	FailedChecks []FailedCheck `json:"failed_checks,omitempty"`
+}
+
+// FailedCheck reports a component which is not healthy
+type FailedCheck struct {
+	Component string `json:"component"`
+	Reason    string `json:"reason"`
+}
+
+// HealthHandler serves /healthz: it responds with 200 if all the registered
+// checkers succeed, with 503 and the failed checks otherwise
+type HealthHandler struct {
+	lock     sync.RWMutex
+	checkers map[string]HealthChecker
+}
+
+// NewHealthHandler creates a HealthHandler without checkers
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{checkers: make(map[string]HealthChecker)}
+}
+
+// RegisterChecker registers the HealthChecker of a component
+func (h *HealthHandler) RegisterChecker(component string, checker HealthChecker) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if _, exists := h.checkers[component]; exists {
+		return fmt.Errorf("a health checker is already registered for component [%s]", component)
+	}
+	h.checkers[component] = checker
+	return nil
+}
+
+func (h *HealthHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(resp, http.StatusMethodNotAllowed, &errorResponse{Error: fmt.Sprintf("invalid request method: %s", req.Method)})
+		return
+	}
+
+	h.lock.RLock()
+	components := make([]string, 0, len(h.checkers))
+	for component := range h.checkers {
+		components = append(components, component)
+	}
+	checkers := make(map[string]HealthChecker, len(h.checkers))
+	for component, checker := range h.checkers {
+		checkers[component] = checker
+	}
+	h.lock.RUnlock()
+	sort.Strings(components)
+
+	ctx, cancel := context.WithTimeout(req.Context(), HealthCheckTimeout)
+	defer cancel()
+	status := &HealthStatus{Status: "OK", Time: time.Now().UTC()}
+	for _, component := range components {
+		if err := checkers[component].HealthCheck(ctx); err != nil {
+			status.FailedChecks = append(status.FailedChecks, FailedCheck{Component: component, Reason: err.Error()})
+		}
+	}
+	if len(status.FailedChecks) > 0 {
+		status.Status = "Service Unavailable"
+		writeJSON(resp, http.StatusServiceUnavailable, status)
+		return
+	}
+	writeJSON(resp, http.StatusOK, status)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(resp http.ResponseWriter, statusCode int, body interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(statusCode)
+	if err := json.NewEncoder(resp).Encode(body); err != nil {
+		logger.Errorf("Failed to encode the response: %s", err)
+	}
+}
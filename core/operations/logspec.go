@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+// LogSpec is the body of the requests and responses of /logspec
+type LogSpec struct {
+	Spec string `json:"spec"`
+}
+
+// LogSpecHandler serves /logspec: GET returns the current logging
+// specification and PUT applies a new one
+type LogSpecHandler struct {
+}
+
+func (h *LogSpecHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		writeJSON(resp, http.StatusOK, &LogSpec{Spec: flogging.Spec()})
+	case http.MethodPut:
+		logSpec := &LogSpec{}
+		if err := json.NewDecoder(req.Body).Decode(logSpec); err != nil {
+			writeJSON(resp, http.StatusBadRequest, &errorResponse{Error: fmt.Sprintf("invalid request body: %s", err)})
+			return
+		}
+		if err := flogging.ValidateSpec(logSpec.Spec); err != nil {
+			writeJSON(resp, http.StatusBadRequest, &errorResponse{Error: err.Error()})
+			return
+		}
+		logger.Infof("Setting the logging specification to [%s]", logSpec.Spec)
+		flogging.InitFromSpec(logSpec.Spec)
+		resp.WriteHeader(http.StatusNoContent)
+	default:
+		writeJSON(resp, http.StatusMethodNotAllowed, &errorResponse{Error: fmt.Sprintf("invalid request method: %s", req.Method)})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package operations provides the HTTP server through which the peer and the
+// orderer are operated: it exposes their metrics, their health and allows to
+// change their logging specification.
+package operations
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+var logger = flogging.MustGetLogger("operations")
+
+const (
+	// PrometheusProvider exposes the metrics in the Prometheus format on /metrics
+	PrometheusProvider = "prometheus"
+	// DisabledProvider does not expose the metrics
+	DisabledProvider = "disabled"
+)
+
+// TLS contains the TLS configuration of the operations server
+type TLS struct {
+	Enabled            bool
+	CertFile           string
+	KeyFile            string
+	ClientCertRequired bool
+	ClientRootCAs      []string
+}
+
+// Options contains the configuration of the operations server
+type Options struct {
+	ListenAddress string
+	TLS           TLS
+	// MetricsProvider is either PrometheusProvider or DisabledProvider (the default)
+	MetricsProvider string
+	// Registry holds the metrics exposed on /metrics, metrics.DefaultRegistry if nil
+	Registry *metrics.Registry
+}
+
+// System is the operations server of a process
+type System struct {
+	options  Options
+	health   *HealthHandler
+	mux      *http.ServeMux
+	server   *http.Server
+	listener net.Listener
+	lock     sync.Mutex
+}
+
+// NewSystem creates the operations server with the given options
+func NewSystem(options Options) *System {
+	if options.Registry == nil {
+		options.Registry = metrics.DefaultRegistry
+	}
+	s := &System{
+		options: options,
+		health:  NewHealthHandler(),
+		mux:     http.NewServeMux(),
+	}
+	s.mux.Handle("/healthz", s.health)
+	s.mux.Handle("/logspec", &LogSpecHandler{})
+	if options.MetricsProvider == PrometheusProvider {
+		s.mux.Handle("/metrics", options.Registry)
+	}
+	return s
+}
+
+// RegisterChecker registers the HealthChecker of a component, which is
+// invoked on every request to /healthz
+func (s *System) RegisterChecker(component string, checker HealthChecker) error {
+	return s.health.RegisterChecker(component, checker)
+}
+
+// Start starts serving the operations requests
+func (s *System) Start() error {
+	switch s.options.MetricsProvider {
+	case PrometheusProvider, DisabledProvider, "":
+	default:
+		return fmt.Errorf("unknown metrics provider [%s], expected [%s] or [%s]",
+			s.options.MetricsProvider, PrometheusProvider, DisabledProvider)
+	}
+
+	listener, err := net.Listen("tcp", s.options.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %s", s.options.ListenAddress, err)
+	}
+	if s.options.TLS.Enabled {
+		tlsConfig, err := s.options.TLS.config()
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	s.lock.Lock()
+	s.listener = listener
+	s.server = &http.Server{Handler: s.mux, ReadTimeout: 10 * time.Second, WriteTimeout: 2 * time.Minute}
+	server := s.server
+	s.lock.Unlock()
+
+	logger.Infof("Starting operations server on %s", listener.Addr())
+	go func() {
+		err := server.Serve(listener)
+		// Serve fails once Stop closes the listener, which is then no longer the one of the system
+		s.lock.Lock()
+		stopped := s.listener != listener
+		s.lock.Unlock()
+		if !stopped {
+			logger.Errorf("Operations server failed: %s", err)
+		}
+	}()
+	return nil
+}
+
+// Stop stops the operations server
+func (s *System) Stop() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.server == nil {
+		return errors.New("the operations server is not started")
+	}
+	// the connections kept alive are closed after their next request
+	s.server.SetKeepAlivesEnabled(false)
+	err := s.listener.Close()
+	s.server, s.listener = nil, nil
+	return err
+}
+
+// Addr returns the address on which the operations server listens, once started
+func (s *System) Addr() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+func (t TLS) config() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS certificate and key of the operations server: %s", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if t.ClientCertRequired {
+		clientRootCAs := x509.NewCertPool()
+		for _, caFile := range t.ClientRootCAs {
+			caPEM, err := ioutil.ReadFile(caFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read client root CA %s: %s", caFile, err)
+			}
+			if !clientRootCAs.AppendCertsFromPEM(caPEM) {
+				return nil, fmt.Errorf("no certificate found in client root CA %s", caFile)
+			}
+		}
+		tlsConfig.ClientCAs = clientRootCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockChecker struct {
+	err error
+}
+
+func (c *mockChecker) HealthCheck(ctx context.Context) error {
+	return c.err
+}
+
+func startSystem(t *testing.T, metricsProvider string, registry *metrics.Registry) *System {
+	system := NewSystem(Options{ListenAddress: "127.0.0.1:0", MetricsProvider: metricsProvider, Registry: registry})
+	assert.NoError(t, system.Start())
+	return system
+}
+
+func doRequest(t *testing.T, method, url, body string) (int, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	assert.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp.StatusCode, string(respBody)
+}
+
+func TestMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.NewCounter(metrics.CounterOpts{Name: "test_total"}).Add(3)
+
+	system := startSystem(t, PrometheusProvider, registry)
+	defer system.Stop()
+	code, body := doRequest(t, http.MethodGet, "http://"+system.Addr()+"/metrics", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "# TYPE test_total counter\ntest_total 3\n", body)
+
+	// the metrics are not exposed when the provider is disabled
+	disabledSystem := startSystem(t, DisabledProvider, registry)
+	defer disabledSystem.Stop()
+	code, _ = doRequest(t, http.MethodGet, "http://"+disabledSystem.Addr()+"/metrics", "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestHealthz(t *testing.T) {
+	system := startSystem(t, DisabledProvider, nil)
+	defer system.Stop()
+	url := "http://" + system.Addr() + "/healthz"
+
+	code, body := doRequest(t, http.MethodGet, url, "")
+	assert.Equal(t, http.StatusOK, code)
+	status := &HealthStatus{}
+	assert.NoError(t, json.Unmarshal([]byte(body), status))
+	assert.Equal(t, "OK", status.Status)
+
+	assert.NoError(t, system.RegisterChecker("ledger", &mockChecker{}))
+	assert.NoError(t, system.RegisterChecker("couchdb", &mockChecker{err: errors.New("couchdb is unreachable")}))
+	assert.Error(t, system.RegisterChecker("ledger", &mockChecker{}), "the component already has a checker")
+
+	code, body = doRequest(t, http.MethodGet, url, "")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	status = &HealthStatus{}
+	assert.NoError(t, json.Unmarshal([]byte(body), status))
+	assert.Equal(t, "Service Unavailable", status.Status)
+	assert.Equal(t, []FailedCheck{{Component: "couchdb", Reason: "couchdb is unreachable"}}, status.FailedChecks)
+
+	code, _ = doRequest(t, http.MethodPost, url, "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestLogSpec(t *testing.T) {
+	defer flogging.Reset()
+	system := startSystem(t, DisabledProvider, nil)
+	defer system.Stop()
+	url := "http://" + system.Addr() + "/logspec"
+
+	code, _ := doRequest(t, http.MethodPut, url, `{"spec":"warning:gossip=debug"}`)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, "DEBUG", flogging.GetModuleLevel("gossip"))
+	assert.Equal(t, "WARNING", flogging.GetModuleLevel("endorser"))
+
+	code, body := doRequest(t, http.MethodGet, url, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"spec":"warning:gossip=debug"}`, body)
+
+	for _, reqBody := range []string{`{"spec":"chatty"}`, `{"spec":""}`, `not json`} {
+		code, _ = doRequest(t, http.MethodPut, url, reqBody)
+		assert.Equal(t, http.StatusBadRequest, code)
+	}
+	assert.Equal(t, "warning:gossip=debug", flogging.Spec())
+
+	code, _ = doRequest(t, http.MethodDelete, url, "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestStop(t *testing.T) {
+	system := startSystem(t, DisabledProvider, nil)
+	addr := system.Addr()
+	code, _ := doRequest(t, http.MethodGet, "http://"+addr+"/healthz", "")
+	assert.Equal(t, http.StatusOK, code)
+
+	assert.NoError(t, system.Stop())
+	assert.Equal(t, "", system.Addr())
+	_, err := net.Dial("tcp", addr)
+	assert.Error(t, err, "the listener should have been closed")
+	assert.Error(t, system.Stop(), "the system is already stopped")
+
+	// the system can be started again once stopped
+	assert.NoError(t, system.Start())
+	defer system.Stop()
+	code, _ = doRequest(t, http.MethodGet, "http://"+system.Addr()+"/healthz", "")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestSystemErrors(t *testing.T) {
+	system := NewSystem(Options{ListenAddress: "127.0.0.1:0", MetricsProvider: "statsd"})
+	assert.Error(t, system.Start(), "the metrics provider is unknown")
+
+	system = NewSystem(Options{ListenAddress: "127.0.0.1:0", MetricsProvider: DisabledProvider,
+		TLS: TLS{Enabled: true, CertFile: "missing.crt", KeyFile: "missing.key"}})
+	assert.Error(t, system.Start(), "the TLS certificate cannot be loaded")
+	assert.Equal(t, "", system.Addr())
+	assert.Error(t, system.Stop(), "the system is not started")
+
+	system = NewSystem(Options{ListenAddress: "256.0.0.1:0", MetricsProvider: DisabledProvider})
+	assert.Error(t, system.Start(), "the address is invalid")
+}
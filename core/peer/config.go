@@ -33,9 +33,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"runtime"
 
 	"github.com/spf13/viper"
 
+	"github.com/hyperledger/fabric/common/crl"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/config"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -121,10 +123,21 @@ func GetPeerEndpoint() (*pb.PeerEndpoint, error) {
 	return peerEndpoint, peerEndpointError
 }
 
+// GetValidatorPoolSize returns the peer.validatorPoolSize property, the number
+// of transactions validated concurrently, which defaults to the number of CPUs
+func GetValidatorPoolSize() int {
+	poolSize := viper.GetInt("peer.validatorPoolSize")
+	if poolSize <= 0 {
+		poolSize = runtime.NumCPU()
+	}
+	return poolSize
+}
+
 // GetSecureConfig returns the secure server configuration for the peer
 func GetSecureConfig() (comm.SecureServerConfig, error) {
 	secureConfig := comm.SecureServerConfig{
 		UseTLS: viper.GetBool("peer.tls.enabled"),
+		CRLs:   crl.LocalStore(),
 	}
 	if secureConfig.UseTLS {
 		// get the certs from the file system
@@ -17,6 +17,7 @@ package peer
 
 import (
 	"net"
+	"runtime"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -123,3 +124,16 @@ func TestConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestGetValidatorPoolSize(t *testing.T) {
+	defer viper.Set("peer.validatorPoolSize", nil)
+
+	viper.Set("peer.validatorPoolSize", 3)
+	assert.Equal(t, 3, GetValidatorPoolSize())
+
+	// the pool size defaults to the number of CPUs
+	viper.Set("peer.validatorPoolSize", 0)
+	assert.Equal(t, runtime.NumCPU(), GetValidatorPoolSize())
+	viper.Set("peer.validatorPoolSize", -1)
+	assert.Equal(t, runtime.NumCPU(), GetValidatorPoolSize())
+}
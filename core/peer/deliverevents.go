@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/orderer/common/deliver"
+	ordererledger "github.com/hyperledger/fabric/orderer/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+var closedChan chan struct{}
+
+func init() {
+	closedChan = make(chan struct{})
+	close(closedChan)
+}
+
+// DeliverEventsServer implements the Deliver service of the peer, through which the
+// client applications receive the blocks of a channel from a seek position, as they
+// do from the ordering service. The requests must be signed by an identity satisfying
+// the Readers policy of the channel
+type DeliverEventsServer struct {
+	dh deliver.Handler
+}
+
+// NewDeliverEventsServer creates a DeliverEventsServer serving the blocks of the
+// channels joined by the peer
+func NewDeliverEventsServer() pb.DeliverServer {
+	return &DeliverEventsServer{dh: deliver.NewHandlerImpl(&deliverSupportManager{})}
+}
+
+// Deliver sends the blocks requested by the seek infos received on the stream
+func (s *DeliverEventsServer) Deliver(srv pb.Deliver_DeliverServer) error {
+	return s.dh.Handle(srv)
+}
+
+type deliverSupportManager struct{}
+
+func (dsm *deliverSupportManager) GetChain(chainID string) (deliver.Support, bool) {
+	chains.RLock()
+	defer chains.RUnlock()
+	c, ok := chains.list[chainID]
+	if !ok {
+		return nil, false
+	}
+	return &deliverSupport{cs: c.cs, reader: c.reader}, true
+}
+
+// deliverSupport provides the deliver handler with the resources of a channel
+type deliverSupport struct {
+	cs     *chainSupport
+	reader *ledgerReader
+}
+
+func (ds *deliverSupport) Sequence() uint64 {
+	return ds.cs.Sequence()
+}
+
+func (ds *deliverSupport) PolicyManager() policies.Manager {
+	return ds.cs.PolicyManager()
+}
+
+func (ds *deliverSupport) Reader() ordererledger.Reader {
+	return ds.reader
+}
+
+// Errored returns nil, on which the deliver handler never returns, as the peer
+// has no consenter whose failures abort the deliveries
+func (ds *deliverSupport) Errored() <-chan struct{} {
+	return nil
+}
+
+// ledgerReader reads the blocks of the ledger of a channel for the deliver service.
+// Its iterators waiting for new blocks are released by notifyCommit
+type ledgerReader struct {
+	ledger ledger.PeerLedger
+
+	lock   sync.Mutex
+	signal chan struct{}
+}
+
+func newLedgerReader(ledger ledger.PeerLedger) *ledgerReader {
+	return &ledgerReader{ledger: ledger, signal: make(chan struct{})}
+}
+
+// notifyCommit is called once a block is committed to the ledger
+func (lr *ledgerReader) notifyCommit() {
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+	close(lr.signal)
+	lr.signal = make(chan struct{})
+}
+
+func (lr *ledgerReader) currentSignal() chan struct{} {
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+	return lr.signal
+}
+
+// Iterator returns an Iterator, as specified by a cb.SeekInfo message, and its
+// starting block number
+func (lr *ledgerReader) Iterator(startPosition *ab.SeekPosition) (ordererledger.Iterator, uint64) {
+	switch start := startPosition.Type.(type) {
+	case *ab.SeekPosition_Oldest:
+		return &ledgerReaderIterator{reader: lr, blockNumber: 0}, 0
+	case *ab.SeekPosition_Newest:
+		newestBlockNumber := lr.Height() - 1
+		return &ledgerReaderIterator{reader: lr, blockNumber: newestBlockNumber}, newestBlockNumber
+	case *ab.SeekPosition_Specified:
+		if start.Specified.Number > lr.Height() {
+			return &ordererledger.NotFoundErrorIterator{}, 0
+		}
+		return &ledgerReaderIterator{reader: lr, blockNumber: start.Specified.Number}, start.Specified.Number
+	default:
+		return &ordererledger.NotFoundErrorIterator{}, 0
+	}
+}
+
+// Height returns the number of blocks on the ledger
+func (lr *ledgerReader) Height() uint64 {
+	info, err := lr.ledger.GetBlockchainInfo()
+	if err != nil {
+		peerLogger.Panicf("Failed to get the height of the ledger: %s", err)
+	}
+	return info.Height
+}
+
+type ledgerReaderIterator struct {
+	reader      *ledgerReader
+	blockNumber uint64
+}
+
+// Next blocks until there is a new block available, or returns an error if the
+// next block is no longer retrievable
+func (i *ledgerReaderIterator) Next() (*common.Block, common.Status) {
+	for {
+		// the signal is taken before checking the height so that a block
+		// committed in between is not missed
+		signal := i.reader.currentSignal()
+		if i.blockNumber < i.reader.Height() {
+			block, err := i.reader.ledger.GetBlockByNumber(i.blockNumber)
+			if err != nil {
+				peerLogger.Errorf("Failed to retrieve block %d: %s", i.blockNumber, err)
+				return nil, common.Status_SERVICE_UNAVAILABLE
+			}
+			i.blockNumber++
+			return block, common.Status_SUCCESS
+		}
+		<-signal
+	}
+}
+
+// ReadyChan supplies a channel which will block until Next will not block
+func (i *ledgerReaderIterator) ReadyChan() <-chan struct{} {
+	signal := i.reader.currentSignal()
+	if i.blockNumber >= i.reader.Height() {
+		return signal
+	}
+	return closedChan
+}
+
+// notifyingCommitter notifies the readers of the ledger of the committed blocks
+type notifyingCommitter struct {
+	committer.Committer
+	reader *ledgerReader
+}
+
+func (nc *notifyingCommitter) Commit(block *common.Block) error {
+	if err := nc.Committer.Commit(block); err != nil {
+		return err
+	}
+	nc.reader.notifyCommit()
+	return nil
+}
+
+func (nc *notifyingCommitter) CommitWithPvtData(blockAndPvtData *ledger.BlockAndPvtData) error {
+	if err := nc.Committer.CommitWithPvtData(blockAndPvtData); err != nil {
+		return err
+	}
+	nc.reader.notifyCommit()
+	return nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type mockDeliverStream struct {
+	grpc.ServerStream
+	recvChan chan *cb.Envelope
+	sendChan chan *ab.DeliverResponse
+}
+
+func newMockDeliverStream() *mockDeliverStream {
+	return &mockDeliverStream{
+		recvChan: make(chan *cb.Envelope),
+		sendChan: make(chan *ab.DeliverResponse),
+	}
+}
+
+func (m *mockDeliverStream) Send(resp *ab.DeliverResponse) error {
+	m.sendChan <- resp
+	return nil
+}
+
+func (m *mockDeliverStream) Recv() (*cb.Envelope, error) {
+	msg, ok := <-m.recvChan
+	if !ok {
+		return msg, io.EOF
+	}
+	return msg, nil
+}
+
+func (m *mockDeliverStream) expectResponse(t *testing.T) *ab.DeliverResponse {
+	select {
+	case resp := <-m.sendChan:
+		return resp
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a deliver response")
+		return nil
+	}
+}
+
+func makeSeek(chainID string, seekInfo *ab.SeekInfo) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader:   utils.MarshalOrPanic(&cb.ChannelHeader{ChannelId: chainID}),
+				SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{}),
+			},
+			Data: utils.MarshalOrPanic(seekInfo),
+		}),
+	}
+}
+
+func seekSpecified(number uint64) *ab.SeekPosition {
+	return &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: number}}}
+}
+
+// commitTestBlock commits a block to the ledger of the chain and notifies its readers
+// as the committer of the chain does
+func commitTestBlock(t *testing.T, chainID string) *cb.Block {
+	bg, _ := testutil.NewBlockGenerator(t, chainID, false)
+	ledger := GetLedger(chainID)
+	simulator, err := ledger.NewTxSimulator()
+	assert.NoError(t, err)
+	assert.NoError(t, simulator.SetState("ns", "key", []byte("value")))
+	simulator.Done()
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	block := bg.NextBlock([][]byte{simRes})
+	assert.NoError(t, ledger.Commit(block))
+
+	chains.RLock()
+	reader := chains.list[chainID].reader
+	chains.RUnlock()
+	reader.notifyCommit()
+	return block
+}
+
+func TestDeliverEventsServer(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/hyperledger/test/deliverevents")
+	MockInitialize()
+	defer ledgermgmt.CleanupTestEnv()
+	defer MockInitialize()
+
+	chainID := "deliverchannel"
+	assert.NoError(t, MockCreateChain(chainID))
+
+	stream := newMockDeliverStream()
+	defer close(stream.recvChan)
+	go NewDeliverEventsServer().Deliver(stream)
+
+	// the blocks already committed are replayed
+	stream.recvChan <- makeSeek(chainID, &ab.SeekInfo{
+		Start:    &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}},
+		Stop:     &ab.SeekPosition{Type: &ab.SeekPosition_Newest{Newest: &ab.SeekNewest{}}},
+		Behavior: ab.SeekInfo_FAIL_IF_NOT_READY,
+	})
+	assert.Equal(t, uint64(0), stream.expectResponse(t).GetBlock().Header.Number)
+	assert.Equal(t, cb.Status_SUCCESS, stream.expectResponse(t).GetStatus())
+
+	// the next block is delivered once committed
+	stream.recvChan <- makeSeek(chainID, &ab.SeekInfo{
+		Start:    seekSpecified(1),
+		Stop:     seekSpecified(1),
+		Behavior: ab.SeekInfo_BLOCK_UNTIL_READY,
+	})
+	select {
+	case resp := <-stream.sendChan:
+		t.Fatalf("unexpected response before the block is committed: %v", resp)
+	case <-time.After(100 * time.Millisecond):
+	}
+	block := commitTestBlock(t, chainID)
+	assert.Equal(t, block.Header, stream.expectResponse(t).GetBlock().Header)
+	assert.Equal(t, cb.Status_SUCCESS, stream.expectResponse(t).GetStatus())
+
+	// the blocks beyond the height of the ledger are not found
+	stream.recvChan <- makeSeek(chainID, &ab.SeekInfo{
+		Start:    seekSpecified(5),
+		Stop:     seekSpecified(5),
+		Behavior: ab.SeekInfo_FAIL_IF_NOT_READY,
+	})
+	assert.Equal(t, cb.Status_NOT_FOUND, stream.expectResponse(t).GetStatus())
+}
+
+func TestDeliverEventsServerAccessControl(t *testing.T) {
+	chains.Lock()
+	chains.list["deniedchannel"] = &chain{
+		cs: &chainSupport{
+			Manager: &mockconfigtx.Manager{
+				Initializer: mockconfigtx.Initializer{
+					Resources: mockconfigtx.Resources{
+						PolicyManagerVal: &mockpolicies.Manager{
+							Policy: &mockpolicies.Policy{Err: errors.New("signature set did not satisfy policy")},
+						},
+					},
+				},
+			},
+		},
+	}
+	chains.Unlock()
+	defer func() {
+		chains.Lock()
+		delete(chains.list, "deniedchannel")
+		chains.Unlock()
+	}()
+
+	seekInfo := &ab.SeekInfo{
+		Start:    &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}},
+		Stop:     &ab.SeekPosition{Type: &ab.SeekPosition_Newest{Newest: &ab.SeekNewest{}}},
+		Behavior: ab.SeekInfo_FAIL_IF_NOT_READY,
+	}
+	for chainID, status := range map[string]cb.Status{
+		"deniedchannel":  cb.Status_FORBIDDEN,
+		"missingchannel": cb.Status_NOT_FOUND,
+	} {
+		stream := newMockDeliverStream()
+		go NewDeliverEventsServer().Deliver(stream)
+		stream.recvChan <- makeSeek(chainID, seekInfo)
+		assert.Equal(t, status, stream.expectResponse(t).GetStatus(), "unexpected status for channel %s", chainID)
+		close(stream.recvChan)
+	}
+}
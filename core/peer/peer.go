@@ -20,11 +20,14 @@ import (
 	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
 	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/committer"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/transientstore"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/service"
 	"github.com/hyperledger/fabric/msp"
@@ -57,11 +60,36 @@ func (cs *chainSupport) GetMSPIDs(cid string) []string {
 	return GetMSPIDs(cid)
 }
 
+// Acquire takes a worker of the validator pool shared by the chains
+func (cs *chainSupport) Acquire() {
+	getValidationWorkers() <- struct{}{}
+}
+
+// Release returns a worker to the validator pool shared by the chains
+func (cs *chainSupport) Release() {
+	<-getValidationWorkers()
+}
+
+// validationWorkers bounds the number of transactions validated concurrently
+// by all the chains of the peer to the configured validator pool size
+var validationWorkers struct {
+	sync.Once
+	sem chan struct{}
+}
+
+func getValidationWorkers() chan struct{} {
+	validationWorkers.Do(func() {
+		validationWorkers.sem = make(chan struct{}, GetValidatorPoolSize())
+	})
+	return validationWorkers.sem
+}
+
 // chain is a local struct to manage objects in a chain
 type chain struct {
 	cs        *chainSupport
 	cb        *common.Block
 	committer committer.Committer
+	reader    *ledgerReader
 }
 
 // chains is a local map of chainID->chainObject
@@ -76,10 +104,15 @@ func MockInitialize() {
 	chains.list = nil
 	chains.list = make(map[string]*chain)
 	chainInitializer = func(string) { return }
+	validationPluginMapper = nil
 }
 
 var chainInitializer func(string)
 
+// validationPluginMapper maps the VSCC names of the chaincodes to the
+// validation plugins of the peer
+var validationPluginMapper txvalidator.PluginMapper
+
 var mockMSPIDGetter func(string) []string
 
 func MockSetMSPIDGetter(mspIDGetter func(string) []string) {
@@ -88,9 +121,11 @@ func MockSetMSPIDGetter(mspIDGetter func(string) []string) {
 
 // Initialize sets up any chains that the peer has from the persistence. This
 // function should be called at the start up when the ledger and gossip
-// ready
-func Initialize(init func(string)) {
+// ready. The chains validate the transactions with the validation plugins
+// of the PluginMapper, if not nil
+func Initialize(init func(string), pm txvalidator.PluginMapper) {
 	chainInitializer = init
+	validationPluginMapper = pm
 
 	var cb *common.Block
 	var ledger ledger.PeerLedger
@@ -160,6 +195,21 @@ func getCurrConfigBlockFromLedger(ledger ledger.PeerLedger) (*common.Block, erro
 	return configBlock, nil
 }
 
+// transientStoreProvider provides the transient stores which hold the private
+// data of the transactions of a channel until their blocks are committed
+var transientStoreProvider struct {
+	sync.Once
+	provider transientstore.StoreProvider
+}
+
+// openTransientStore opens the transient store of the given chain
+func openTransientStore(cid string) (transientstore.Store, error) {
+	transientStoreProvider.Do(func() {
+		transientStoreProvider.provider = transientstore.NewStoreProvider()
+	})
+	return transientStoreProvider.provider.OpenStore(cid)
+}
+
 // createChain creates a new chain object and insert it into the chains
 func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block) error {
 
@@ -217,19 +267,30 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block) error {
 		ledger:      ledger,
 	}
 
-	c := committer.NewLedgerCommitterReactive(ledger, txvalidator.NewTxValidator(cs), func(block *common.Block) error {
-		chainID, err := utils.GetChainIDFromBlock(block)
-		if err != nil {
-			return err
-		}
-		return SetCurrConfigBlock(block, chainID)
-	})
+	reader := newLedgerReader(ledger)
+	c := &notifyingCommitter{
+		Committer: committer.NewLedgerCommitterReactive(ledger, txvalidator.NewTxValidator(cs, validationPluginMapper), func(block *common.Block) error {
+			chainID, err := utils.GetChainIDFromBlock(block)
+			if err != nil {
+				return err
+			}
+			return SetCurrConfigBlock(block, chainID)
+		}),
+		reader: reader,
+	}
 
 	ordererAddresses := configtxManager.ChannelConfig().OrdererAddresses()
 	if len(ordererAddresses) == 0 {
 		return errors.New("No ordering service endpoint provided in configuration block")
 	}
-	service.GetGossipService().InitializeChannel(cs.ChainID(), c, ordererAddresses)
+	store, err := openTransientStore(cid)
+	if err != nil {
+		return fmt.Errorf("Failed opening transient store for %s: %s", cid, err)
+	}
+	service.GetGossipService().InitializeChannel(cs.ChainID(), c, ordererAddresses, service.Support{
+		Store: store,
+		Cs:    privdata.NewSimpleCollectionStore(ledger),
+	})
 
 	chains.Lock()
 	defer chains.Unlock()
@@ -237,6 +298,7 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block) error {
 		cs:        cs,
 		cb:        cb,
 		committer: c,
+		reader:    reader,
 	}
 	return nil
 }
@@ -256,6 +318,27 @@ func CreateChainFromBlock(cb *common.Block) error {
 	return createChain(cid, l, cb)
 }
 
+// CreateChainFromSnapshot creates a new chain from the ledger snapshot in the given
+// directory, configured with the last config block of the snapshot. It returns the
+// chain ID
+func CreateChainFromSnapshot(snapshotDir string) (string, error) {
+	l, err := ledgermgmt.CreateLedgerFromSnapshot(snapshotDir)
+	if err != nil {
+		return "", fmt.Errorf("Cannot create ledger from snapshot, due to %s", err)
+	}
+
+	cb, err := getCurrConfigBlockFromLedger(l)
+	if err != nil {
+		return "", err
+	}
+	cid, err := utils.GetChainIDFromBlock(cb)
+	if err != nil {
+		return "", err
+	}
+
+	return cid, createChain(cid, l, cb)
+}
+
 // MockCreateChain used for creating a ledger for a chain for tests
 // without havin to join
 func MockCreateChain(cid string) error {
@@ -295,6 +378,7 @@ func MockCreateChain(cid string) error {
 		cs: &chainSupport{
 			Manager: manager,
 			ledger:  ledger},
+		reader: newLedgerReader(ledger),
 	}
 
 	return nil
@@ -542,6 +626,25 @@ func (c *channelPolicyManagerGetter) Manager(channelID string) (policies.Manager
 	return policyManager, policyManager != nil
 }
 
+// NewChannelACLsGetter returns a new instance of ChannelACLsGetter, which
+// returns the ACLs set by the Application config of the chains of the peer
+func NewChannelACLsGetter() aclmgmt.ChannelACLsGetter {
+	return &channelACLsGetter{}
+}
+
+type channelACLsGetter struct{}
+
+func (g *channelACLsGetter) ChannelACLs(channelID string) map[string][]string {
+	chains.RLock()
+	defer chains.RUnlock()
+	if c, ok := chains.list[channelID]; ok {
+		if ac, _ := c.cs.ApplicationConfig(); ac != nil {
+			return ac.ACLs()
+		}
+	}
+	return nil
+}
+
 // CreatePeerServer creates an instance of comm.GRPCServer
 // This server is used for peer communications
 func CreatePeerServer(listenAddress string,
@@ -127,7 +127,7 @@ func TestInitialize(t *testing.T) {
 	ccp.RegisterChaincodeProviderFactory(&ccprovider.MockCcProviderFactory{})
 	sysccprovider.RegisterSystemChaincodeProviderFactory(&mscc.MocksccProviderFactory{})
 
-	Initialize(nil)
+	Initialize(nil, nil)
 }
 
 func TestCreateChainFromBlock(t *testing.T) {
@@ -220,7 +220,7 @@ func TestCreateChainFromBlock(t *testing.T) {
 	assert.Equal(t, true, ok, "expected Manage() to return true")
 
 	// Chaos monkey test
-	Initialize(nil)
+	Initialize(nil, nil)
 
 	SetCurrConfigBlock(block, testChainID)
 
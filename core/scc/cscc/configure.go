@@ -28,7 +28,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/flogging"
-	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
@@ -43,16 +43,17 @@ import (
 // configuration transaction coming in from the ordering service, the
 // committer calls this system chaincode to process the transaction.
 type PeerConfiger struct {
-	policyChecker policy.PolicyChecker
+	aclProvider aclmgmt.ACLProvider
 }
 
 var cnflogger = flogging.MustGetLogger("cscc")
 
 // These are function names from Invoke first parameter
 const (
-	JoinChain      string = "JoinChain"
-	GetConfigBlock string = "GetConfigBlock"
-	GetChannels    string = "GetChannels"
+	JoinChain           string = "JoinChain"
+	JoinChainBySnapshot string = "JoinChainBySnapshot"
+	GetConfigBlock      string = "GetConfigBlock"
+	GetChannels         string = "GetChannels"
 )
 
 // Init is called once per chain when the chain is created.
@@ -61,11 +62,14 @@ const (
 func (e *PeerConfiger) Init(stub shim.ChaincodeStubInterface) pb.Response {
 	cnflogger.Info("Init CSCC")
 
-	// Init policy checker for access control
-	e.policyChecker = policy.NewPolicyChecker(
-		peer.NewChannelPolicyManagerGetter(),
-		mgmt.GetLocalMSP(),
-		mgmt.NewLocalMSPPrincipalGetter(),
+	// Init ACL provider for access control
+	e.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			peer.NewChannelPolicyManagerGetter(),
+			mgmt.GetLocalMSP(),
+			mgmt.NewLocalMSPPrincipalGetter(),
+		),
+		peer.NewChannelACLsGetter(),
 	)
 
 	return shim.Success(nil)
@@ -76,10 +80,11 @@ func (e *PeerConfiger) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # to get the current configuration block (called by app)
 // # to update the configuration block (called by commmitter)
 // Peer calls this function with 2 arguments:
-// # args[0] is the function name, which must be JoinChain, GetConfigBlock or
-// UpdateConfigBlock
+// # args[0] is the function name, which must be JoinChain, JoinChainBySnapshot,
+// GetConfigBlock or UpdateConfigBlock
 // # args[1] is a configuration Block if args[0] is JoinChain or
-// UpdateConfigBlock; otherwise it is the chain id
+// UpdateConfigBlock, the path on the peer of a ledger snapshot if args[0] is
+// JoinChainBySnapshot; otherwise it is the chain id
 // TODO: Improve the scc interface to avoid marshal/unmarshal args
 func (e *PeerConfiger) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
@@ -125,22 +130,36 @@ func (e *PeerConfiger) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 				"of configuration block, because of %s", cid, err))
 		}
 
-		// 2. check local MSP Admins policy
-		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+		// 2. check the ACL of JoinChain, which the peer checks before
+		// having the channel
+		if err = e.aclProvider.CheckACL(aclmgmt.CSCC_JoinChain, "", sp); err != nil {
 			return shim.Error(fmt.Sprintf("\"JoinChain\" request failed authorization check "+
 				"for channel [%s]: [%s]", cid, err))
 		}
 
 		return joinChain(cid, block)
+	case JoinChainBySnapshot:
+		if len(args[1]) == 0 {
+			return shim.Error("Cannot join the channel, no snapshot path provided")
+		}
+
+		// 2. check the ACL of JoinChainBySnapshot, which the peer checks
+		// before having the channel
+		if err = e.aclProvider.CheckACL(aclmgmt.CSCC_JoinChainBySnapshot, "", sp); err != nil {
+			return shim.Error(fmt.Sprintf("\"JoinChainBySnapshot\" request failed authorization check "+
+				"for snapshot [%s]: [%s]", args[1], err))
+		}
+
+		return joinChainBySnapshot(string(args[1]))
 	case GetConfigBlock:
-		// 2. check the channel reader policy
-		if err = e.policyChecker.CheckPolicy(string(args[1]), policies.ChannelApplicationReaders, sp); err != nil {
+		// 2. check the ACL of GetConfigBlock on the channel
+		if err = e.aclProvider.CheckACL(aclmgmt.CSCC_GetConfigBlock, string(args[1]), sp); err != nil {
 			return shim.Error(fmt.Sprintf("\"GetConfigBlock\" request failed authorization check for channel [%s]: [%s]", args[1], err))
 		}
 		return getConfigBlock(args[1])
 	case GetChannels:
-		// 2. check local MSP Members policy
-		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Members, sp); err != nil {
+		// 2. check the ACL of GetChannels, which spans the channels
+		if err = e.aclProvider.CheckACL(aclmgmt.CSCC_GetChannels, "", sp); err != nil {
 			return shim.Error(fmt.Sprintf("\"GetChannels\" request failed authorization check: [%s]", err))
 		}
 
@@ -201,6 +220,20 @@ func joinChain(chainID string, block *common.Block) pb.Response {
 	return shim.Success(nil)
 }
 
+// joinChainBySnapshot will join the chain whose ledger snapshot is in the given
+// directory of the peer. The ledger starts at the last block of the snapshot and
+// the Chain object is configured with the last config block of the snapshot
+func joinChainBySnapshot(snapshotDir string) pb.Response {
+	chainID, err := peer.CreateChainFromSnapshot(snapshotDir)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	peer.InitChain(chainID)
+
+	return shim.Success(nil)
+}
+
 // Return the current configuration block for the specified chainID. If the
 // peer doesn't belong to the chain, return error
 func getConfigBlock(chainID []byte) pb.Response {
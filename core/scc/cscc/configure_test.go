@@ -28,6 +28,7 @@ import (
 	"github.com/hyperledger/fabric/common/localmsp"
 	"github.com/hyperledger/fabric/common/mocks/scc"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
@@ -191,10 +192,13 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 
 	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
 
-	e.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	e.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 
 	identity, _ := mgmt.GetLocalSigningIdentityOrPanic().Serialize()
@@ -276,6 +280,49 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	}
 }
 
+func TestConfigerInvokeJoinChainBySnapshot(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/hyperledgertest/")
+	os.Mkdir("/tmp/hyperledgertest", 0755)
+
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+	defer os.RemoveAll("/tmp/hyperledgertest/")
+
+	e := new(PeerConfiger)
+	stub := shim.NewMockStub("PeerConfiger", e)
+
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
+	e.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			&policymocks.MockChannelPolicyManagerGetter{},
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
+	)
+
+	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+	identityDeserializer.Msg = sProp.ProposalBytes
+	sProp.Signature = sProp.ProposalBytes
+
+	// Failed path: no snapshot path
+	res := stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(JoinChainBySnapshot), nil}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Cannot join the channel, no snapshot path provided", res.Message)
+
+	// Failed path: there is no snapshot at the given path
+	args := [][]byte{[]byte(JoinChainBySnapshot), []byte("/tmp/hyperledgertest/nosnapshot")}
+	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "Cannot create ledger from snapshot")
+
+	// Failed path: the creator is not authorized
+	sProp.Signature = nil
+	res = stub.MockInvokeWithSignedProposal("3", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.True(t, strings.HasPrefix(res.Message, "\"JoinChainBySnapshot\" request failed authorization check for snapshot"))
+}
+
 func TestPeerConfiger_SubmittingOrdererGenesis(t *testing.T) {
 	viper.Set("peer.fileSystemPath", "/tmp/hyperledgertest/")
 	os.Mkdir("/tmp/hyperledgertest", 0755)
@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/core/scc/lscc"
 	"github.com/hyperledger/fabric/core/scc/qscc"
 	"github.com/hyperledger/fabric/core/scc/vscc"
+	"github.com/hyperledger/fabric/core/scc/xscc"
 )
 
 //see systemchaincode_test.go for an example using "sample_syscc"
@@ -67,6 +68,14 @@ var systemChaincodes = []*SystemChaincode{
 		InvokableExternal: true, // qscc can be invoked to retrieve blocks
 		InvokableCC2CC:    true, // qscc can be invoked to retrieve blocks also by a cc
 	},
+	{
+		Enabled:           true,
+		Name:              "xscc",
+		Path:              "github.com/hyperledger/fabric/core/scc/xscc",
+		InitArgs:          [][]byte{[]byte("")},
+		Chaincode:         &xscc.CrossChannelSysCC{},
+		InvokableExternal: true, // xscc is invoked to decide the cross-channel transactions
+	},
 }
 
 //RegisterSysCCs is the hook for system chaincodes where system chaincodes are registered with the fabric
@@ -105,6 +114,17 @@ func IsSysCC(name string) bool {
 	return false
 }
 
+// IsSysCCEnabled returns true if the chaincode is a system chaincode
+// which is enabled and whitelisted by the configuration of the peer
+func IsSysCCEnabled(name string) bool {
+	for _, sysCC := range systemChaincodes {
+		if sysCC.Name == name {
+			return sysCC.Enabled && isWhitelisted(sysCC)
+		}
+	}
+	return false
+}
+
 // IsSysCCAndNotInvokableExternal returns true if the chaincode
 // is a system chaincode and *CANNOT* be invoked through
 // a proposal to this peer
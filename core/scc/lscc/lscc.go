@@ -23,14 +23,14 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/flogging"
-	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/peer"
-	"github.com/hyperledger/fabric/core/policy"
 	"github.com/hyperledger/fabric/core/policyprovider"
-	"github.com/hyperledger/fabric/msp/mgmt"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -80,6 +80,14 @@ const (
 	allowedCharsVersion       = "[A-Za-z0-9_.-]+"
 )
 
+// resources maps the query functions on a chaincode to the resources of
+// their ACLs
+var resources = map[string]string{
+	GETCCINFO:  aclmgmt.LSCC_GETCCINFO,
+	GETDEPSPEC: aclmgmt.LSCC_GETDEPSPEC,
+	GETCCDATA:  aclmgmt.LSCC_GETCCDATA,
+}
+
 //---------- the LSCC -----------------
 
 // LifeCycleSysCC implements chaincode lifecycle and policies around it
@@ -89,9 +97,9 @@ type LifeCycleSysCC struct {
 	// import cycles
 	sccprovider sysccprovider.SystemChaincodeProvider
 
-	// policyChecker is the interface used to perform
+	// aclProvider is the interface used to perform
 	// access control
-	policyChecker policy.PolicyChecker
+	aclProvider aclmgmt.ACLProvider
 }
 
 //----------------errors---------------
@@ -271,6 +279,24 @@ func (lscc *LifeCycleSysCC) putChaincodeData(stub shim.ChaincodeStubInterface, c
 	return err
 }
 
+// putChaincodeCollectionData stores the collection configuration of the chaincode, if any
+func (lscc *LifeCycleSysCC) putChaincodeCollectionData(stub shim.ChaincodeStubInterface, cd *ccprovider.ChaincodeData, collectionConfigBytes []byte) error {
+	if len(collectionConfigBytes) == 0 {
+		logger.Debugf("No collection configuration specified for chaincode %s", cd.Name)
+		return nil
+	}
+
+	collections := &common.CollectionConfigPackage{}
+	if err := proto.Unmarshal(collectionConfigBytes, collections); err != nil {
+		return fmt.Errorf("invalid collection configuration supplied for chaincode %s:%s", cd.Name, cd.Version)
+	}
+	if err := privdata.ValidateCollectionConfigPackage(collections); err != nil {
+		return fmt.Errorf("invalid collection configuration supplied for chaincode %s:%s, error %s", cd.Name, cd.Version, err)
+	}
+
+	return stub.PutState(privdata.BuildCollectionKVSKey(cd.Name), collectionConfigBytes)
+}
+
 //checks for existence of chaincode on the given channel
 func (lscc *LifeCycleSysCC) getCCInstance(stub shim.ChaincodeStubInterface, ccname string) ([]byte, error) {
 	cdbytes, err := stub.GetState(ccname)
@@ -345,6 +371,11 @@ func (lscc *LifeCycleSysCC) getChaincodes(stub shim.ChaincodeStubInterface) pb.R
 			return shim.Error(err.Error())
 		}
 
+		// collection configurations are kept next to the chaincode data
+		if privdata.IsCollectionConfigKey(response.Key) {
+			continue
+		}
+
 		ccdata := &ccprovider.ChaincodeData{}
 		if err = proto.Unmarshal(response.Value, ccdata); err != nil {
 			return shim.Error(err.Error())
@@ -475,9 +506,28 @@ func (lscc *LifeCycleSysCC) executeInstall(stub shim.ChaincodeStubInterface, ccb
 		return fmt.Errorf("Error installing chaincode code %s:%s(%s)", cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version, err)
 	}
 
+	//the chaincode may already be instantiated on the channels of this peer, in which
+	//case its statedb artifacts (e.g. couchdb indexes) are created now. The package
+	//is installed at this point, so a failure is logged rather than returned
+	lscc.handleChaincodeInstall(ccpack)
+
 	return err
 }
 
+// handleChaincodeInstall notifies the ledgers of the installation of a chaincode
+func (lscc *LifeCycleSysCC) handleChaincodeInstall(ccpack ccprovider.CCPackage) {
+	ccid := ccpack.GetDepSpec().ChaincodeSpec.ChaincodeId
+	dbArtifacts, err := ccprovider.ExtractStatedbArtifactsFromCCPackage(ccpack)
+	if err != nil {
+		logger.Errorf("Error extracting the statedb artifacts of chaincode %s:%s: %s", ccid.Name, ccid.Version, err)
+		return
+	}
+	chaincodeDefinition := &cceventmgmt.ChaincodeDefinition{Name: ccid.Name, Version: ccid.Version, Hash: ccpack.GetId()}
+	if err = cceventmgmt.GetMgr().HandleChaincodeInstall(chaincodeDefinition, dbArtifacts); err != nil {
+		logger.Errorf("Error creating the statedb artifacts of chaincode %s:%s: %s", ccid.Name, ccid.Version, err)
+	}
+}
+
 // getInstantiationPolicy retrieves the instantiation policy from a SignedCDSPackage
 func (lscc *LifeCycleSysCC) getInstantiationPolicy(channel string, ccpack ccprovider.CCPackage) ([]byte, error) {
 	var ip []byte
@@ -548,7 +598,7 @@ func (lscc *LifeCycleSysCC) checkInstantiationPolicy(stub shim.ChaincodeStubInte
 }
 
 // executeDeploy implements the "instantiate" Invoke transaction
-func (lscc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chainname string, depSpec []byte, policy []byte, escc []byte, vscc []byte) (*ccprovider.ChaincodeData, error) {
+func (lscc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chainname string, depSpec []byte, policy []byte, escc []byte, vscc []byte, collectionConfigBytes []byte) (*ccprovider.ChaincodeData, error) {
 	cds, err := utils.GetChaincodeDeploymentSpec(depSpec)
 
 	if err != nil {
@@ -597,13 +647,18 @@ func (lscc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chai
 		return nil, err
 	}
 
+	err = lscc.putChaincodeCollectionData(stub, cd, collectionConfigBytes)
+	if err != nil {
+		return nil, err
+	}
+
 	err = lscc.createChaincode(stub, cd)
 
 	return cd, err
 }
 
 // executeUpgrade implements the "upgrade" Invoke transaction.
-func (lscc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, chainName string, depSpec []byte, policy []byte, escc []byte, vscc []byte) (*ccprovider.ChaincodeData, error) {
+func (lscc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, chainName string, depSpec []byte, policy []byte, escc []byte, vscc []byte, collectionConfigBytes []byte) (*ccprovider.ChaincodeData, error) {
 	cds, err := utils.GetChaincodeDeploymentSpec(depSpec)
 	if err != nil {
 		return nil, err
@@ -673,6 +728,11 @@ func (lscc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, cha
 		return nil, err
 	}
 
+	err = lscc.putChaincodeCollectionData(stub, cd, collectionConfigBytes)
+	if err != nil {
+		return nil, err
+	}
+
 	err = lscc.upgradeChaincode(stub, cd)
 	if err != nil {
 		return nil, err
@@ -681,14 +741,31 @@ func (lscc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, cha
 	return cd, nil
 }
 
+// getChannelID returns the channel of the signed proposal
+func getChannelID(sp *pb.SignedProposal) (string, error) {
+	prop, err := utils.GetProposal(sp.ProposalBytes)
+	if err != nil {
+		return "", fmt.Errorf("Failed extracting proposal: %s", err)
+	}
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return "", fmt.Errorf("Failed extracting proposal header: %s", err)
+	}
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return "", fmt.Errorf("Failed extracting proposal channel header: %s", err)
+	}
+	return chdr.ChannelId, nil
+}
+
 //-------------- the chaincode stub interface implementation ----------
 
 //Init only initializes the system chaincode provider
 func (lscc *LifeCycleSysCC) Init(stub shim.ChaincodeStubInterface) pb.Response {
 	lscc.sccprovider = sysccprovider.GetSystemChaincodeProvider()
 
-	// Init policy checker for access control
-	lscc.policyChecker = policyprovider.GetPolicyChecker()
+	// Init ACL provider for access control
+	lscc.aclProvider = aclmgmt.NewACLProvider(policyprovider.GetPolicyChecker(), peer.NewChannelACLsGetter())
 
 	return shim.Success(nil)
 }
@@ -719,8 +796,8 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			return shim.Error(InvalidArgsLenErr(len(args)).Error())
 		}
 
-		// 2. check local MSP Admins policy
-		if err = lscc.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+		// 2. check the ACL of INSTALL, which is not bound to a channel
+		if err = lscc.aclProvider.CheckACL(aclmgmt.LSCC_INSTALL, "", sp); err != nil {
 			return shim.Error(fmt.Sprintf("Authorization for INSTALL has been denied (error-%s)", err))
 		}
 
@@ -732,13 +809,10 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		}
 		return shim.Success([]byte("OK"))
 	case DEPLOY:
-		if len(args) < 3 || len(args) > 6 {
+		if len(args) < 3 || len(args) > 7 {
 			return shim.Error(InvalidArgsLenErr(len(args)).Error())
 		}
 
-		// TODO: add access control check
-		// once the instantiation process will be completed.
-
 		//chain the chaincode shoud be associated with. It
 		//should be created with a register call
 		chainname := string(args[1])
@@ -747,12 +821,17 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			return shim.Error(InvalidChainNameErr(chainname).Error())
 		}
 
+		// The access to DEPLOY is controlled by the ACL of the proposals on
+		// the channel, checked by the endorser, and by the instantiation
+		// policy of the chaincode package, checked by executeDeploy
+
 		depSpec := args[2]
 
 		// optional arguments here (they can each be nil and may or may not be present)
 		// args[3] is a marshalled SignaturePolicyEnvelope representing the endorsement policy
 		// args[4] is the name of escc
 		// args[5] is the name of vscc
+		// args[6] is a marshalled CollectionConfigPackage struct
 		var policy []byte
 		if len(args) > 3 && len(args[3]) > 0 {
 			policy = args[3]
@@ -778,7 +857,12 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			vscc = []byte("vscc")
 		}
 
-		cd, err := lscc.executeDeploy(stub, chainname, depSpec, policy, escc, vscc)
+		var collectionsConfig []byte
+		if len(args) > 6 {
+			collectionsConfig = args[6]
+		}
+
+		cd, err := lscc.executeDeploy(stub, chainname, depSpec, policy, escc, vscc, collectionsConfig)
 		if err != nil {
 			return shim.Error(err.Error())
 		}
@@ -788,7 +872,7 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		}
 		return shim.Success(cdbytes)
 	case UPGRADE:
-		if len(args) < 3 || len(args) > 6 {
+		if len(args) < 3 || len(args) > 7 {
 			return shim.Error(InvalidArgsLenErr(len(args)).Error())
 		}
 
@@ -797,8 +881,9 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			return shim.Error(InvalidChainNameErr(chainname).Error())
 		}
 
-		// TODO: add access control check
-		// once the instantiation process will be completed.
+		// The access to UPGRADE is controlled by the ACL of the proposals on
+		// the channel, checked by the endorser, and by the instantiation
+		// policy of the chaincode package, checked by executeUpgrade
 
 		depSpec := args[2]
 
@@ -806,6 +891,7 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		// args[3] is a marshalled SignaturePolicyEnvelope representing the endorsement policy
 		// args[4] is the name of escc
 		// args[5] is the name of vscc
+		// args[6] is a marshalled CollectionConfigPackage struct
 		var policy []byte
 		if len(args) > 3 && len(args[3]) > 0 {
 			policy = args[3]
@@ -831,7 +917,12 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			vscc = []byte("vscc")
 		}
 
-		cd, err := lscc.executeUpgrade(stub, chainname, depSpec, policy, escc, vscc)
+		var collectionsConfig []byte
+		if len(args) > 6 {
+			collectionsConfig = args[6]
+		}
+
+		cd, err := lscc.executeUpgrade(stub, chainname, depSpec, policy, escc, vscc, collectionsConfig)
 		if err != nil {
 			return shim.Error(err.Error())
 		}
@@ -848,10 +939,10 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		chain := string(args[1])
 		ccname := string(args[2])
 
-		// 2. check local Channel Readers policy
+		// 2. check the ACL of the function on the channel
 		// Notice that this information are already available on the ledger
-		// therefore we enforce here that the caller is reader of the channel.
-		if err = lscc.policyChecker.CheckPolicy(chain, policies.ChannelApplicationReaders, sp); err != nil {
+		// therefore by default the caller must be reader of the channel.
+		if err = lscc.aclProvider.CheckACL(resources[function], chain, sp); err != nil {
 			return shim.Error(fmt.Sprintf("Authorization for %s on channel %s has been denied with error %s", function, args[1], err))
 		}
 
@@ -882,8 +973,13 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			return shim.Error(InvalidArgsLenErr(len(args)).Error())
 		}
 
-		// 2. check local MSP Admins policy
-		if err = lscc.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+		chain, err := getChannelID(sp)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+
+		// 2. check the ACL of GETCHAINCODES on the channel of the proposal
+		if err = lscc.aclProvider.CheckACL(aclmgmt.LSCC_GETCHAINCODES, chain, sp); err != nil {
 			return shim.Error(fmt.Sprintf("Authorization for GETCHAINCODES on channel %s has been denied with error %s", args[0], err))
 		}
 
@@ -893,8 +989,9 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			return shim.Error(InvalidArgsLenErr(len(args)).Error())
 		}
 
-		// 2. check local MSP Admins policy
-		if err = lscc.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+		// 2. check the ACL of GETINSTALLEDCHAINCODES, which is not bound
+		// to a channel
+		if err = lscc.aclProvider.CheckACL(aclmgmt.LSCC_GETINSTALLEDCHAINCODES, "", sp); err != nil {
 			return shim.Error(fmt.Sprintf("Authorization for GETINSTALLEDCHAINCODES on channel %s has been denied with error %s", args[0], err))
 		}
 
@@ -30,9 +30,11 @@ import (
 	"github.com/hyperledger/fabric/common/mocks/scc"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccpackage"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	cutil "github.com/hyperledger/fabric/core/container/util"
 	"github.com/hyperledger/fabric/core/peer"
@@ -105,10 +107,13 @@ func testInstall(t *testing.T, ccname string, version string, path string, expec
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
 		},
 	}
-	scc.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 
 	cds, err := constructDeploymentSpec(ccname, path, version, [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")}, false)
@@ -259,10 +264,13 @@ func testDeploy(t *testing.T, ccname string, version string, path string, forceB
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
 		},
 	}
-	scc.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
 	identityDeserializer.Msg = sProp.ProposalBytes
@@ -336,6 +344,82 @@ func testDeploy(t *testing.T, ccname string, version string, path string, forceB
 	}
 }
 
+// TestDeployWithCollections tests that the collection configuration supplied at
+// instantiation is stored next to the chaincode data and is validated
+func TestDeployWithCollections(t *testing.T) {
+	path := "github.com/hyperledger/fabric/examples/chaincode/go/chaincode_example02"
+	collName := "mycollection"
+	ccp := &common.CollectionConfigPackage{Config: []*common.CollectionConfig{
+		{
+			Payload: &common.CollectionConfig_StaticCollectionConfig{
+				StaticCollectionConfig: &common.StaticCollectionConfig{
+					Name: collName,
+					MemberOrgsPolicy: &common.CollectionPolicyConfig{
+						Payload: &common.CollectionPolicyConfig_SignaturePolicy{
+							SignaturePolicy: cauthdsl.SignedByAnyMember([]string{"SampleOrg"}),
+						},
+					},
+					RequiredPeerCount: 0,
+					MaximumPeerCount:  1,
+				},
+			},
+		},
+	}}
+	ccpBytes, err := proto.Marshal(ccp)
+	assert.NoError(t, err)
+
+	scc := new(LifeCycleSysCC)
+	stub := shim.NewMockStub("lscc", scc)
+	res := stub.MockInit("1", nil)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			&policymocks.MockChannelPolicyManagerGetter{
+				Managers: map[string]policies.Manager{
+					"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
+				},
+			},
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
+	)
+
+	cds, err := constructDeploymentSpec("example02", path, "0", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")}, true)
+	assert.NoError(t, err)
+	defer os.Remove(lscctestpath + "/example02.0")
+	b, err := proto.Marshal(cds)
+	assert.NoError(t, err)
+	sProp, _ := putils.MockSignedEndorserProposal2OrPanic(chainid, &pb.ChaincodeSpec{}, id)
+
+	// a malformed collection configuration makes the instantiation fail
+	args := [][]byte{[]byte(DEPLOY), []byte("test"), b, nil, nil, nil, []byte("barf")}
+	res = stub.MockInvokeWithSignedProposal("1", args, sProp)
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+
+	args = [][]byte{[]byte(DEPLOY), []byte("test"), b, nil, nil, nil, ccpBytes}
+	res = stub.MockInvokeWithSignedProposal("1", args, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.Equal(t, ccpBytes, stub.State[privdata.BuildCollectionKVSKey("example02")])
+
+	// the collection configuration should not be listed as a chaincode
+	sProp2, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+	identityDeserializer.Msg = sProp2.ProposalBytes
+	sProp2.Signature = sProp2.ProposalBytes
+	args = [][]byte{[]byte(GETCHAINCODES)}
+	res = stub.MockInvokeWithSignedProposal("1", args, sProp2)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	cqr := &pb.ChaincodeQueryResponse{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, cqr))
+	assert.Len(t, cqr.GetChaincodes(), 1)
+
+	// more than 7 arguments are not accepted
+	args = [][]byte{[]byte(DEPLOY), []byte("test"), b, nil, nil, nil, ccpBytes, nil}
+	res = stub.MockInvokeWithSignedProposal("1", args, sProp)
+	assert.Equal(t, InvalidArgsLenErr(8).Error(), res.Message)
+}
+
 //TestRedeploy tests the redeploying will fail function(and fail with "exists" error)
 func TestRedeploy(t *testing.T) {
 	scc := new(LifeCycleSysCC)
@@ -388,10 +472,13 @@ func TestMultipleDeploy(t *testing.T) {
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
 		},
 	}
-	scc.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
 	identityDeserializer.Msg = sProp.ProposalBytes
@@ -474,10 +561,13 @@ func TestRetryFailedDeploy(t *testing.T) {
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
 		},
 	}
-	scc.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
 	identityDeserializer.Msg = sProp.ProposalBytes
@@ -538,10 +628,13 @@ func TestTamperChaincode(t *testing.T) {
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
 		},
 	}
-	scc.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
 	identityDeserializer.Msg = sProp.ProposalBytes
@@ -635,10 +728,13 @@ func TestIPolDeployDefaultFail(t *testing.T) {
 			chainid: &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
 		},
 	}
-	scc.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
 	identityDeserializer.Msg = sProp.ProposalBytes
@@ -681,10 +777,13 @@ func testIPolDeploy(t *testing.T, iPol string, successExpected bool) {
 			chainid: &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
 		},
 	}
-	scc.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
 	identityDeserializer.Msg = sProp.ProposalBytes
@@ -835,10 +934,13 @@ func testIPolUpgrade(t *testing.T, iPol string, successExpected bool) {
 			chainid: &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
 		},
 	}
-	scc.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
 	identityDeserializer.Msg = sProp.ProposalBytes
@@ -925,10 +1027,13 @@ func TestGetAPIsWithoutInstall(t *testing.T) {
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
 		},
 	}
-	scc.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
 	identityDeserializer.Msg = sProp.ProposalBytes
@@ -1026,10 +1131,13 @@ func TestGetInstalledChaincodesAccessRights(t *testing.T) {
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
 		},
 	}
-	scc.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 
 	// Should pass
@@ -1070,10 +1178,13 @@ func TestGetChaincodesAccessRights(t *testing.T) {
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
 		},
 	}
-	scc.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 
 	// Should pass
@@ -1114,10 +1225,13 @@ func TestGetCCAccessRights(t *testing.T) {
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
 		},
 	}
-	scc.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	scc.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			identityDeserializer,
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 
 	cds, err := constructDeploymentSpec("example02", "github.com/hyperledger/fabric/examples/chaincode/go/chaincode_example02", "0", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")}, true)
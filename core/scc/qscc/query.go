@@ -22,7 +22,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/flogging"
 
-	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/peer"
@@ -38,7 +38,7 @@ import (
 // - GetBlockByHash returns a block
 // - GetTransactionByID returns a transaction
 type LedgerQuerier struct {
-	policyChecker policy.PolicyChecker
+	aclProvider aclmgmt.ACLProvider
 }
 
 var qscclogger = flogging.MustGetLogger("qscc")
@@ -52,17 +52,29 @@ const (
 	GetBlockByTxID     string = "GetBlockByTxID"
 )
 
+// resources maps the query functions to the resources of their ACLs
+var resources = map[string]string{
+	GetChainInfo:       aclmgmt.QSCC_GetChainInfo,
+	GetBlockByNumber:   aclmgmt.QSCC_GetBlockByNumber,
+	GetBlockByHash:     aclmgmt.QSCC_GetBlockByHash,
+	GetTransactionByID: aclmgmt.QSCC_GetTransactionByID,
+	GetBlockByTxID:     aclmgmt.QSCC_GetBlockByTxID,
+}
+
 // Init is called once per chain when the chain is created.
 // This allows the chaincode to initialize any variables on the ledger prior
 // to any transaction execution on the chain.
 func (e *LedgerQuerier) Init(stub shim.ChaincodeStubInterface) pb.Response {
 	qscclogger.Info("Init QSCC")
 
-	// Init policy checker for access control
-	e.policyChecker = policy.NewPolicyChecker(
-		peer.NewChannelPolicyManagerGetter(),
-		mgmt.GetLocalMSP(),
-		mgmt.NewLocalMSPPrincipalGetter(),
+	// Init ACL provider for access control
+	e.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			peer.NewChannelPolicyManagerGetter(),
+			mgmt.GetLocalMSP(),
+			mgmt.NewLocalMSPPrincipalGetter(),
+		),
+		peer.NewChannelACLsGetter(),
 	)
 
 	return shim.Success(nil)
@@ -84,6 +96,11 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	fname := string(args[0])
 	cid := string(args[1])
 
+	resName, ok := resources[fname]
+	if !ok {
+		return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
+	}
+
 	if fname != GetChainInfo && len(args) < 3 {
 		return shim.Error(fmt.Sprintf("missing 3rd argument for %s", fname))
 	}
@@ -102,8 +119,8 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return shim.Error(fmt.Sprintf("Failed getting signed proposal from stub, %s: %s", cid, err))
 	}
 
-	// 2. check the channel reader policy
-	if err = e.policyChecker.CheckPolicy(cid, policies.ChannelApplicationReaders, sp); err != nil {
+	// 2. check the ACL of the function on the channel
+	if err = e.aclProvider.CheckACL(resName, cid, sp); err != nil {
 		return shim.Error(fmt.Sprintf("Authorization request failed %s: %s", cid, err))
 	}
 
@@ -23,6 +23,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
@@ -164,10 +165,13 @@ func TestFailingAccessControl(t *testing.T) {
 			chainid: &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}}},
 		},
 	}
-	e.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		&policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")},
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	e.aclProvider = aclmgmt.NewACLProvider(
+		policy.NewPolicyChecker(
+			policyManagerGetter,
+			&policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")},
+			&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		),
+		nil,
 	)
 	stub := shim.NewMockStub("LedgerQuerier", e)
 
@@ -181,10 +181,34 @@ func (*mockStub) DelState(key string) error {
 	panic("implement me")
 }
 
+func (*mockStub) GetPrivateData(collection string, key string) ([]byte, error) {
+	panic("implement me")
+}
+
+func (*mockStub) PutPrivateData(collection string, key string, value []byte) error {
+	panic("implement me")
+}
+
+func (*mockStub) DelPrivateData(collection string, key string) error {
+	panic("implement me")
+}
+
+func (*mockStub) SetStateValidationParameter(key string, ep []byte) error {
+	panic("implement me")
+}
+
+func (*mockStub) GetStateValidationParameter(key string) ([]byte, error) {
+	panic("implement me")
+}
+
 func (*mockStub) GetStateByRange(startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
 	panic("implement me")
 }
 
+func (*mockStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	panic("implement me")
+}
+
 func (*mockStub) GetStateByPartialCompositeKey(objectType string, keys []string) (shim.StateQueryIteratorInterface, error) {
 	panic("implement me")
 }
@@ -201,6 +225,10 @@ func (*mockStub) GetQueryResult(query string) (shim.StateQueryIteratorInterface,
 	panic("implement me")
 }
 
+func (*mockStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	panic("implement me")
+}
+
 func (*mockStub) GetHistoryForKey(key string) (shim.HistoryQueryIteratorInterface, error) {
 	panic("implement me")
 }
@@ -87,6 +87,12 @@ func TestIsSysCCAndNotInvokableExternal(t *testing.T) {
 	assert.True(t, (&sccProviderImpl{}).IsSysCCAndNotInvokableExternal("vscc"))
 }
 
+func TestIsSysCCEnabled(t *testing.T) {
+	assert.True(t, IsSysCCEnabled("lscc"))
+	assert.False(t, IsSysCCEnabled("xscc"))
+	assert.False(t, IsSysCCEnabled("noSCC"))
+}
+
 func TestSccProviderImpl_GetQueryExecutorForLedger(t *testing.T) {
 	qe, err := (&sccProviderImpl{}).GetQueryExecutorForLedger("")
 	assert.Nil(t, qe)
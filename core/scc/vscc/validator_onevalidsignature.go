@@ -27,7 +27,9 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/scc/lscc"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
@@ -69,12 +71,15 @@ func (vscc *ValidatorOneValidSignature) Init(stub shim.ChaincodeStubInterface) p
 // selecting which policy to use for validation using parameter function
 // @return serialized Block of valid and invalid transactions identified
 // Note that Peer calls this function with 3 arguments, where args[0] is the
-// function name, args[1] is the Envelope and args[2] is the validation policy
+// function name, args[1] is the Envelope and args[2] is the validation policy,
+// followed by validation.KeyLevelEndorsementArg if the key-level endorsement
+// policies of the keys written replace the validation policy
 func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	// TODO: document the argument in some white paper or design document
 	// args[0] - function name (not used now)
 	// args[1] - serialized Envelope
 	// args[2] - serialized policy
+	// args[3] - optional, validation.KeyLevelEndorsementArg
 	args := stub.GetArgs()
 	if len(args) < 3 {
 		return shim.Error("Incorrect number of arguments")
@@ -109,26 +114,79 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 		return shim.Error(err.Error())
 	}
 
-	// get the policy
-	mgr := mspmgmt.GetManagerForChain(chdr.ChannelId)
-	pProvider := cauthdsl.NewPolicyProvider(mgr)
-	policy, _, err := pProvider.NewPolicy(args[2])
-	if err != nil {
-		logger.Errorf("VSCC error: pProvider.NewPolicy failed, err %s", err)
+	// the key-level endorsement policies, which the committer evaluated
+	// already, replace the policy of the chaincode
+	policy := args[2]
+	if len(args) > 3 && string(args[3]) == validation.KeyLevelEndorsementArg {
+		policy = nil
+	}
+
+	// the policies and the state are the ones of the channel of the transaction
+	v := New(&channelPolicyEvaluator{chainID: chdr.ChannelId}, &sccStateFetcher{chainID: chdr.ChannelId, sccprovider: vscc.sccprovider})
+	if err = v.validate(env, payl, chdr, policy); err != nil {
 		return shim.Error(err.Error())
 	}
 
+	logger.Debugf("VSCC exists successfully")
+
+	return shim.Success(nil)
+}
+
+// Validator implements the default validation logic of the transactions,
+// which checks that their endorsements satisfy the endorsement policy of the
+// chaincode and, for the invocations of lscc, that the chaincode deployments
+// and upgrades are well formed and authorized by the instantiation policy
+type Validator struct {
+	policyEvaluator validation.PolicyEvaluator
+	stateFetcher    validation.StateFetcher
+}
+
+// New creates a Validator which evaluates the policies and reads the state
+// of the channel of the transactions with the given dependencies
+func New(policyEvaluator validation.PolicyEvaluator, stateFetcher validation.StateFetcher) *Validator {
+	return &Validator{policyEvaluator: policyEvaluator, stateFetcher: stateFetcher}
+}
+
+// Validate validates the serialized transaction envelope against the
+// serialized endorsement policy. A nil policy skips the evaluation of the
+// endorsements, as done when the key-level endorsement policies of the keys
+// written replace it. Failures to read the state are returned as
+// *validation.ExecutionFailureError
+func (v *Validator) Validate(envBytes []byte, policyBytes []byte) error {
+	// get the envelope...
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		logger.Errorf("VSCC error: GetEnvelope failed, err %s", err)
+		return err
+	}
+
+	// ...and the payload...
+	payl, err := utils.GetPayload(env)
+	if err != nil {
+		logger.Errorf("VSCC error: GetPayload failed, err %s", err)
+		return err
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(payl.Header.ChannelHeader)
+	if err != nil {
+		return err
+	}
+
+	return v.validate(env, payl, chdr, policyBytes)
+}
+
+func (v *Validator) validate(env *common.Envelope, payl *common.Payload, chdr *common.ChannelHeader, policyBytes []byte) error {
 	// validate the payload type
 	if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
 		logger.Errorf("Only Endorser Transactions are supported, provided type %d", chdr.Type)
-		return shim.Error(fmt.Sprintf("Only Endorser Transactions are supported, provided type %d", chdr.Type))
+		return fmt.Errorf("Only Endorser Transactions are supported, provided type %d", chdr.Type)
 	}
 
 	// ...and the transaction...
 	tx, err := utils.GetTransaction(payl.Data)
 	if err != nil {
 		logger.Errorf("VSCC error: GetTransaction failed, err %s", err)
-		return shim.Error(err.Error())
+		return err
 	}
 
 	// loop through each of the actions within
@@ -136,63 +194,51 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 		cap, err := utils.GetChaincodeActionPayload(act.Payload)
 		if err != nil {
 			logger.Errorf("VSCC error: GetChaincodeActionPayload failed, err %s", err)
-			return shim.Error(err.Error())
+			return err
 		}
 
-		signatureSet, err := vscc.deduplicateIdentity(cap)
+		signatureSet, err := v.deduplicateIdentity(cap)
 		if err != nil {
-			return shim.Error(err.Error())
+			return err
 		}
 
-		// evaluate the signature set against the policy
-		err = policy.Evaluate(signatureSet)
-		if err != nil {
-			logger.Warningf("Endorsement policy failure for transaction txid=%s, err: %s", chdr.GetTxId(), err.Error())
-			if len(signatureSet) < len(cap.Action.Endorsements) {
-				// Warning: duplicated identities exist, endorsement failure might be cause by this reason
-				return shim.Error(DUPLICATED_IDENTITY_ERROR)
+		// evaluate the signature set against the policy, if any
+		if policyBytes != nil {
+			err = v.policyEvaluator.Evaluate(policyBytes, signatureSet)
+			if err != nil {
+				logger.Warningf("Endorsement policy failure for transaction txid=%s, err: %s", chdr.GetTxId(), err.Error())
+				if len(signatureSet) < len(cap.Action.Endorsements) {
+					// Warning: duplicated identities exist, endorsement failure might be cause by this reason
+					return errors.New(DUPLICATED_IDENTITY_ERROR)
+				}
+				return fmt.Errorf("VSCC error: policy evaluation failed, err %s", err)
 			}
-			return shim.Error(fmt.Sprintf("VSCC error: policy evaluation failed, err %s", err))
 		}
 
 		hdrExt, err := utils.GetChaincodeHeaderExtension(payl.Header)
 		if err != nil {
 			logger.Errorf("VSCC error: GetChaincodeHeaderExtension failed, err %s", err)
-			return shim.Error(err.Error())
+			return err
 		}
 
 		// do some extra validation that is specific to lscc
 		if hdrExt.ChaincodeId.Name == "lscc" {
 			logger.Debugf("VSCC info: doing special validation for LSCC")
 
-			err = vscc.ValidateLSCCInvocation(stub, chdr.ChannelId, env, cap, payl)
+			err = v.ValidateLSCCInvocation(chdr.ChannelId, env, cap, payl)
 			if err != nil {
 				logger.Errorf("VSCC error: ValidateLSCCInvocation failed, err %s", err)
-				return shim.Error(err.Error())
+				return err
 			}
 		}
 	}
 
-	logger.Debugf("VSCC exists successfully")
-
-	return shim.Success(nil)
+	return nil
 }
 
 // checkInstantiationPolicy evaluates an instantiation policy against a signed proposal
-func (vscc *ValidatorOneValidSignature) checkInstantiationPolicy(chainName string, env *common.Envelope, instantiationPolicy []byte, payl *common.Payload) error {
-	// create a policy object from the policy bytes
-	mgr := mspmgmt.GetManagerForChain(chainName)
-	if mgr == nil {
-		return fmt.Errorf("MSP manager for channel %s is nil, aborting", chainName)
-	}
-
-	npp := cauthdsl.NewPolicyProvider(mgr)
-	instPol, _, err := npp.NewPolicy(instantiationPolicy)
-	if err != nil {
-		return err
-	}
-
-	logger.Debugf("VSCC info: checkInstantiationPolicy starts, policy is %#v", instPol)
+func (v *Validator) checkInstantiationPolicy(env *common.Envelope, instantiationPolicy []byte, payl *common.Payload) error {
+	logger.Debugf("VSCC info: checkInstantiationPolicy starts")
 
 	// get the signature header
 	shdr, err := utils.GetSignatureHeader(payl.Header.SignatureHeader)
@@ -206,14 +252,15 @@ func (vscc *ValidatorOneValidSignature) checkInstantiationPolicy(chainName strin
 		Identity:  shdr.Creator,
 		Signature: env.Signature,
 	}}
-	err = instPol.Evaluate(sd)
+	err = v.policyEvaluator.Evaluate(instantiationPolicy, sd)
 	if err != nil {
 		return fmt.Errorf("chaincode instantiation policy violated, error %s", err)
 	}
 	return nil
 }
 
-func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(stub shim.ChaincodeStubInterface, chid string, env *common.Envelope, cap *pb.ChaincodeActionPayload, payl *common.Payload) error {
+// ValidateLSCCInvocation validates the deployments and upgrades of chaincodes
+func (v *Validator) ValidateLSCCInvocation(chid string, env *common.Envelope, cap *pb.ChaincodeActionPayload, payl *common.Payload) error {
 	cpp, err := utils.GetChaincodeProposalPayload(cap.ChaincodeProposalPayload)
 	if err != nil {
 		logger.Errorf("VSCC error: GetChaincodeProposalPayload failed, err %s", err)
@@ -243,8 +290,8 @@ func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(stub shim.Chainco
 	case lscc.UPGRADE, lscc.DEPLOY:
 		logger.Debugf("VSCC info: validating invocation of lscc function %s on arguments %#v", lsccFunc, lsccArgs)
 
-		if len(lsccArgs) < 2 || len(lsccArgs) > 5 {
-			return fmt.Errorf("Wrong number of arguments for invocation lscc(%s): expected between 2 and 5, received %d", lsccFunc, len(lsccArgs))
+		if len(lsccArgs) < 2 || len(lsccArgs) > 6 {
+			return fmt.Errorf("Wrong number of arguments for invocation lscc(%s): expected between 2 and 6, received %d", lsccFunc, len(lsccArgs))
 		}
 
 		cdsArgs, err := utils.GetChaincodeDeploymentSpec(lsccArgs[1])
@@ -285,7 +332,7 @@ func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(stub shim.Chainco
 		}
 
 		// retrieve from the ledger the entry for the chaincode at hand
-		cdLedger, ccExistsOnLedger, err := vscc.getInstantiatedCC(chid, cdsArgs.ChaincodeSpec.ChaincodeId.Name)
+		cdLedger, ccExistsOnLedger, err := v.getInstantiatedCC(chid, cdsArgs.ChaincodeSpec.ChaincodeId.Name)
 		if err != nil {
 			return err
 		}
@@ -297,10 +344,24 @@ func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(stub shim.Chainco
 		if lsccrwset == nil {
 			return errors.New("No read write set for lscc was found")
 		}
-		// there can only be a single one
-		if len(lsccrwset.Writes) != 1 {
+		// there can only be a single one, except for the optional collection configuration
+		if len(lsccrwset.Writes) < 1 || len(lsccrwset.Writes) > 2 {
 			return errors.New("LSCC can only issue a single putState upon deploy/upgrade")
 		}
+		// the second write, if any, must be the collection configuration of the chaincode
+		if len(lsccrwset.Writes) == 2 {
+			collectionKey := privdata.BuildCollectionKVSKey(cdsArgs.ChaincodeSpec.ChaincodeId.Name)
+			if lsccrwset.Writes[1].Key != collectionKey {
+				return fmt.Errorf("Expected key %s, found %s", collectionKey, lsccrwset.Writes[1].Key)
+			}
+			collections := &common.CollectionConfigPackage{}
+			if err = proto.Unmarshal(lsccrwset.Writes[1].Value, collections); err != nil {
+				return fmt.Errorf("Unmarshalling of CollectionConfigPackage failed, error %s", err)
+			}
+			if err = privdata.ValidateCollectionConfigPackage(collections); err != nil {
+				return fmt.Errorf("Invalid collection configuration, error %s", err)
+			}
+		}
 		// the key name must be the chaincode id
 		if lsccrwset.Writes[0].Key != cdsArgs.ChaincodeSpec.ChaincodeId.Name {
 			return fmt.Errorf("Expected key %s, found %s", cdsArgs.ChaincodeSpec.ChaincodeId.Name, lsccrwset.Writes[0].Key)
@@ -342,7 +403,7 @@ func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(stub shim.Chainco
 			// here is the same as the one on disk?
 			// PROS: we prevent attacks where the policy is replaced
 			// CONS: this would be a point of non-determinism
-			err = vscc.checkInstantiationPolicy(chid, env, pol, payl)
+			err = v.checkInstantiationPolicy(env, pol, payl)
 			if err != nil {
 				return err
 			}
@@ -374,7 +435,7 @@ func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(stub shim.Chainco
 			// here is the same as the one on disk?
 			// PROS: we prevent attacks where the policy is replaced
 			// CONS: this would be a point of non-determinism
-			err = vscc.checkInstantiationPolicy(chid, env, pol, payl)
+			err = v.checkInstantiationPolicy(env, pol, payl)
 			if err != nil {
 				return err
 			}
@@ -394,17 +455,17 @@ func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(stub shim.Chainco
 	}
 }
 
-func (vscc *ValidatorOneValidSignature) getInstantiatedCC(chid, ccid string) (cd *ccprovider.ChaincodeData, exists bool, err error) {
-	qe, err := vscc.sccprovider.GetQueryExecutorForLedger(chid)
+func (v *Validator) getInstantiatedCC(chid, ccid string) (cd *ccprovider.ChaincodeData, exists bool, err error) {
+	state, err := v.stateFetcher.FetchState()
 	if err != nil {
-		err = fmt.Errorf("Could not retrieve QueryExecutor for channel %s, error %s", chid, err)
+		err = &validation.ExecutionFailureError{Reason: fmt.Sprintf("Could not retrieve QueryExecutor for channel %s, error %s", chid, err)}
 		return
 	}
-	defer qe.Done()
+	defer state.Done()
 
-	bytes, err := qe.GetState("lscc", ccid)
+	bytes, err := state.GetState("lscc", ccid)
 	if err != nil {
-		err = fmt.Errorf("Could not retrieve state for chaincode %s on channel %s, error %s", ccid, chid, err)
+		err = &validation.ExecutionFailureError{Reason: fmt.Sprintf("Could not retrieve state for chaincode %s on channel %s, error %s", ccid, chid, err)}
 		return
 	}
 
@@ -423,7 +484,7 @@ func (vscc *ValidatorOneValidSignature) getInstantiatedCC(chid, ccid string) (cd
 	return
 }
 
-func (vscc *ValidatorOneValidSignature) deduplicateIdentity(cap *pb.ChaincodeActionPayload) ([]*common.SignedData, error) {
+func (v *Validator) deduplicateIdentity(cap *pb.ChaincodeActionPayload) ([]*common.SignedData, error) {
 	// this is the first part of the signed message
 	prespBytes := cap.Action.ProposalResponsePayload
 
@@ -457,3 +518,37 @@ func (vscc *ValidatorOneValidSignature) deduplicateIdentity(cap *pb.ChaincodeAct
 	logger.Debugf("Signature set is of size %d out of %d endorsement(s)", len(signatureSet), len(cap.Action.Endorsements))
 	return signatureSet, nil
 }
+
+// channelPolicyEvaluator evaluates policies against the MSPs of a channel
+type channelPolicyEvaluator struct {
+	chainID string
+}
+
+func (pe *channelPolicyEvaluator) Evaluate(policyBytes []byte, signatureSet []*common.SignedData) error {
+	mgr := mspmgmt.GetManagerForChain(pe.chainID)
+	if mgr == nil {
+		return fmt.Errorf("MSP manager for channel %s is nil, aborting", pe.chainID)
+	}
+
+	policy, _, err := cauthdsl.NewPolicyProvider(mgr).NewPolicy(policyBytes)
+	if err != nil {
+		logger.Errorf("VSCC error: pProvider.NewPolicy failed, err %s", err)
+		return err
+	}
+	return policy.Evaluate(signatureSet)
+}
+
+// sccStateFetcher reads the committed state of a channel through the
+// system chaincode provider
+type sccStateFetcher struct {
+	chainID     string
+	sccprovider sysccprovider.SystemChaincodeProvider
+}
+
+func (sf *sccStateFetcher) FetchState() (validation.State, error) {
+	qe, err := sf.sccprovider.GetQueryExecutorForLedger(sf.chainID)
+	if err != nil {
+		return nil, err
+	}
+	return qe, nil
+}
@@ -34,8 +34,10 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccpackage"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	cutils "github.com/hyperledger/fabric/core/container/util"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	per "github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
@@ -352,6 +354,12 @@ func TestInvoke(t *testing.T) {
 		t.Fatalf("vscc invoke should have failed")
 	}
 
+	// good path: the key-level endorsement policies replace the policy
+	args = [][]byte{[]byte("dv"), envBytes, policy, []byte(validation.KeyLevelEndorsementArg)}
+	if res := stub.MockInvoke("1", args); res.Status != shim.OK {
+		t.Fatalf("vscc invoke returned err %s", res.Message)
+	}
+
 	// bad path: signed by duplicated MSP identity
 	policy, err = getSignedByOneMemberTwicePolicy(mspid)
 	if err != nil {
@@ -955,6 +963,72 @@ func TestValidateDeployOK(t *testing.T) {
 	}
 }
 
+func TestValidateDeployWithCollections(t *testing.T) {
+	v := new(ValidatorOneValidSignature)
+	stub := shim.NewMockStub("validatoronevalidsignature", v)
+
+	lccc := new(lscc.LifeCycleSysCC)
+	stublccc := shim.NewMockStub("lscc", lccc)
+
+	State := make(map[string]map[string][]byte)
+	State["lscc"] = stublccc.State
+	sysccprovider.RegisterSystemChaincodeProviderFactory(&scc.MocksccProviderFactory{Qe: lm.NewMockQueryExecutor(State)})
+	stub.MockPeerChaincode("lscc", stublccc)
+
+	r1 := stub.MockInit("1", [][]byte{})
+	assert.Equal(t, int32(shim.OK), r1.Status, r1.Message)
+	r := stublccc.MockInit("1", [][]byte{})
+	assert.Equal(t, int32(shim.OK), r.Status, r.Message)
+
+	ccname := "mycc"
+	ccver := "1"
+	defaultPolicy, err := getSignedByMSPAdminPolicy(mspid)
+	assert.NoError(t, err)
+	cdbytes := utils.MarshalOrPanic(&ccprovider.ChaincodeData{Name: ccname, Version: ccver, InstantiationPolicy: defaultPolicy})
+	collections := &common.CollectionConfigPackage{Config: []*common.CollectionConfig{
+		{
+			Payload: &common.CollectionConfig_StaticCollectionConfig{
+				StaticCollectionConfig: &common.StaticCollectionConfig{
+					Name: "mycollection",
+					MemberOrgsPolicy: &common.CollectionPolicyConfig{
+						Payload: &common.CollectionPolicyConfig_SignaturePolicy{
+							SignaturePolicy: cauthdsl.SignedByMspMember(mspid),
+						},
+					},
+					MaximumPeerCount: 1,
+				},
+			},
+		},
+	}}
+	policy, err := getSignedByMSPMemberPolicy(mspid)
+	assert.NoError(t, err)
+
+	invokeVSCC := func(collectionKey string, collectionBytes []byte) peer.Response {
+		rwsetBuilder := rwsetutil.NewRWSetBuilder()
+		rwsetBuilder.AddToWriteSet("lscc", ccname, cdbytes)
+		rwsetBuilder.AddToWriteSet("lscc", collectionKey, collectionBytes)
+		res, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+		assert.NoError(t, err)
+		tx, err := createLSCCTx(ccname, ccver, lscc.DEPLOY, res)
+		assert.NoError(t, err)
+		envBytes, err := utils.GetBytesEnvelope(tx)
+		assert.NoError(t, err)
+		return stub.MockInvoke("1", [][]byte{[]byte("dv"), envBytes, policy})
+	}
+
+	// good path: the collection configuration is written next to the chaincode data
+	res := invokeVSCC(privdata.BuildCollectionKVSKey(ccname), utils.MarshalOrPanic(collections))
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	// bad path: the collection configuration is not well formed
+	res = invokeVSCC(privdata.BuildCollectionKVSKey(ccname), []byte("barf"))
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+
+	// bad path: the collection configuration is written under a different key
+	res = invokeVSCC("mycc~collections", utils.MarshalOrPanic(collections))
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+}
+
 func TestValidateDeployWithPolicies(t *testing.T) {
 	v := new(ValidatorOneValidSignature)
 	stub := shim.NewMockStub("validatoronevalidsignature", v)
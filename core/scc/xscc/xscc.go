@@ -0,0 +1,249 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package xscc
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/aclmgmt"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/crosschannel"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/policy"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+var logger = flogging.MustGetLogger("xscc")
+
+// These are function names from Invoke first parameter
+const (
+	Commit     string = "commit"
+	Abort      string = "abort"
+	AdminAbort string = "adminabort"
+	GetRecord  string = "getrecord"
+)
+
+// CrossChannelSysCC decides the outcome of the cross-channel transactions.
+// The part of a cross-channel transaction on each of its channels records
+// the transaction as PREPARED. The transaction is then decided on the channel
+// it was proposed on, which is the first of its channels, and likewise on
+// every other channel once it is decided there:
+// - it is committed if it is prepared on every one of its channels
+// - it is aborted by its creator, at any time before it is committed
+// - it is aborted by the channel admins, as the xscc/AdminAbort ACL allows
+// The admins thereby release the keys locked by a transaction its creator
+// never decides.
+type CrossChannelSysCC struct {
+	// getRecord returns the record of a transaction on another channel;
+	// the ledgers of the peer are looked up if nil
+	getRecord func(chainID, txID string) (*pb.CrossChannelRecord, error)
+
+	aclProvider aclmgmt.ACLProvider
+}
+
+// Init is called once per chain when the chain is created
+func (x *CrossChannelSysCC) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	logger.Info("Init XSCC")
+
+	// Init ACL provider for access control
+	if x.aclProvider == nil {
+		x.aclProvider = aclmgmt.NewACLProvider(
+			policy.NewPolicyChecker(
+				peer.NewChannelPolicyManagerGetter(),
+				mgmt.GetLocalMSP(),
+				mgmt.NewLocalMSPPrincipalGetter(),
+			),
+			peer.NewChannelACLsGetter(),
+		)
+	}
+	return shim.Success(nil)
+}
+
+// Invoke is called with args[0] containing the function name and args[1] the
+// ID of the cross-channel transaction:
+// # commit: records the transaction as COMMITTED on the channel
+// # abort: records the transaction as ABORTED on the channel; args[2] is the
+//   nonce of the proposal of the transaction, proving the caller created it
+// # adminabort: records the transaction as ABORTED on behalf of the admins
+// # getrecord: returns the record of the transaction on the channel
+func (x *CrossChannelSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	args := stub.GetArgs()
+	if len(args) < 2 {
+		return shim.Error(fmt.Sprintf("Incorrect number of arguments, %d", len(args)))
+	}
+	fname := string(args[0])
+	txID := string(args[1])
+
+	sp, err := stub.GetSignedProposal()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed getting signed proposal from stub: %s", err))
+	}
+	chainID, err := channelOf(sp)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	recordBytes, err := stub.GetState(txID)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get the record of transaction %s: %s", txID, err))
+	}
+	record, err := crosschannel.UnmarshalRecord(recordBytes)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Invalid record of transaction %s: %s", txID, err))
+	}
+
+	switch fname {
+	case GetRecord:
+		if record == nil {
+			return shim.Error(fmt.Sprintf("Transaction %s is not recorded on channel %s", txID, chainID))
+		}
+		return shim.Success(recordBytes)
+	case Commit:
+		return x.commit(stub, chainID, txID, record)
+	case Abort:
+		if len(args) < 3 {
+			return shim.Error(fmt.Sprintf("missing 3rd argument for %s", fname))
+		}
+		creator, err := stub.GetCreator()
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get the creator of the proposal: %s", err))
+		}
+		if creatorTxID, err := utils.ComputeProposalTxID(args[2], creator); err != nil || creatorTxID != txID {
+			return shim.Error(fmt.Sprintf("Transaction %s can only be aborted by its creator", txID))
+		}
+		return x.abort(stub, chainID, txID, record)
+	case AdminAbort:
+		if x.aclProvider == nil {
+			return shim.Error("XSCC is not initialized")
+		}
+		if err = x.aclProvider.CheckACL(aclmgmt.XSCC_AdminAbort, chainID, sp); err != nil {
+			return shim.Error(fmt.Sprintf("Authorization request for [%s][%s] failed: %s", fname, chainID, err))
+		}
+		logger.Infof("Transaction %s aborted on channel %s by an admin", txID, chainID)
+		return x.abort(stub, chainID, txID, record)
+	}
+
+	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
+}
+
+func (x *CrossChannelSysCC) commit(stub shim.ChaincodeStubInterface, chainID, txID string, record *pb.CrossChannelRecord) pb.Response {
+	if record == nil || record.Status != pb.CrossChannelRecord_PREPARED {
+		return shim.Error(fmt.Sprintf("Transaction %s is not prepared on channel %s", txID, chainID))
+	}
+	if len(record.Channels) == 0 {
+		return shim.Error(fmt.Sprintf("Transaction %s has no channels", txID))
+	}
+
+	coordinator := record.Channels[0]
+	if coordinator == chainID {
+		// the transaction is committed once prepared on every channel...
+		for _, otherChainID := range record.Channels[1:] {
+			other, err := x.recordOn(otherChainID, txID)
+			if err != nil {
+				return shim.Error(err.Error())
+			}
+			if other == nil || other.Status != pb.CrossChannelRecord_PREPARED {
+				return shim.Error(fmt.Sprintf("Transaction %s is not prepared on channel %s", txID, otherChainID))
+			}
+		}
+	} else {
+		// ...and follows on the other channels
+		other, err := x.recordOn(coordinator, txID)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		if other == nil || other.Status != pb.CrossChannelRecord_COMMITTED {
+			return shim.Error(fmt.Sprintf("Transaction %s is not committed on channel %s", txID, coordinator))
+		}
+	}
+
+	return putRecord(stub, chainID, txID, &pb.CrossChannelRecord{Status: pb.CrossChannelRecord_COMMITTED, Channels: record.Channels})
+}
+
+func (x *CrossChannelSysCC) abort(stub shim.ChaincodeStubInterface, chainID, txID string, record *pb.CrossChannelRecord) pb.Response {
+	if record != nil && record.Status != pb.CrossChannelRecord_PREPARED {
+		return shim.Error(fmt.Sprintf("Transaction %s is already %s on channel %s", txID, record.Status, chainID))
+	}
+
+	// a transaction which is not prepared on the channel yet is aborted
+	// there at once, so that it cannot be prepared anymore; otherwise it
+	// is aborted on the channel it was proposed on, and then on the others
+	if record != nil && len(record.Channels) > 0 && record.Channels[0] != chainID {
+		coordinator := record.Channels[0]
+		other, err := x.recordOn(coordinator, txID)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		if other == nil || other.Status != pb.CrossChannelRecord_ABORTED {
+			return shim.Error(fmt.Sprintf("Transaction %s is not aborted on channel %s", txID, coordinator))
+		}
+	}
+
+	var channels []string
+	if record != nil {
+		channels = record.Channels
+	}
+	return putRecord(stub, chainID, txID, &pb.CrossChannelRecord{Status: pb.CrossChannelRecord_ABORTED, Channels: channels})
+}
+
+func putRecord(stub shim.ChaincodeStubInterface, chainID, txID string, record *pb.CrossChannelRecord) pb.Response {
+	recordBytes, err := proto.Marshal(record)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	if err = stub.PutState(txID, recordBytes); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to record transaction %s as %s: %s", txID, record.Status, err))
+	}
+	logger.Debugf("Transaction %s recorded as %s on channel %s", txID, record.Status, chainID)
+	return shim.Success(recordBytes)
+}
+
+// channelOf returns the channel of the proposal invoking XSCC
+func channelOf(sp *pb.SignedProposal) (string, error) {
+	if sp == nil {
+		return "", fmt.Errorf("XSCC must be invoked through a proposal")
+	}
+	prop, err := utils.GetProposal(sp.ProposalBytes)
+	if err != nil {
+		return "", err
+	}
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return "", err
+	}
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return "", err
+	}
+	return chdr.ChannelId, nil
+}
+
+// recordOn returns the record of a transaction on another channel, which must
+// be joined by the peer
+func (x *CrossChannelSysCC) recordOn(chainID, txID string) (*pb.CrossChannelRecord, error) {
+	if x.getRecord != nil {
+		return x.getRecord(chainID, txID)
+	}
+	lgr := peer.GetLedger(chainID)
+	if lgr == nil {
+		return nil, fmt.Errorf("Channel %s of transaction %s is not joined by the peer", chainID, txID)
+	}
+	qe, err := lgr.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer qe.Done()
+	recordBytes, err := qe.GetState(crosschannel.Namespace, txID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get the record of transaction %s on channel %s: %s", txID, chainID, err)
+	}
+	return crosschannel.UnmarshalRecord(recordBytes)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package xscc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/crosschannel"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockACLProvider grants the access to the resources to the creator "admin" only
+type mockACLProvider struct{}
+
+func (m *mockACLProvider) CheckACL(resName string, channelID string, idinfo interface{}) error {
+	prop, err := utils.GetProposal(idinfo.(*pb.SignedProposal).ProposalBytes)
+	if err != nil {
+		return err
+	}
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return err
+	}
+	shdr, err := utils.GetSignatureHeader(hdr.SignatureHeader)
+	if err != nil {
+		return err
+	}
+	if string(shdr.Creator) != "admin" {
+		return errors.New("not an admin")
+	}
+	return nil
+}
+
+type testChannels map[string]*shim.MockStub
+
+func newTestChannels(chainIDs ...string) testChannels {
+	channels := testChannels{}
+	for _, chainID := range chainIDs {
+		x := &CrossChannelSysCC{aclProvider: &mockACLProvider{}}
+		x.getRecord = func(chainID, txID string) (*pb.CrossChannelRecord, error) {
+			return crosschannel.UnmarshalRecord(channels[chainID].State[txID])
+		}
+		channels[chainID] = shim.NewMockStub("xscc", x)
+	}
+	return channels
+}
+
+func (channels testChannels) setRecord(t *testing.T, chainID, txID string, status pb.CrossChannelRecord_Status, chainIDs ...string) {
+	recordBytes, err := proto.Marshal(&pb.CrossChannelRecord{Status: status, Channels: chainIDs})
+	assert.NoError(t, err)
+	channels[chainID].State[txID] = recordBytes
+}
+
+func (channels testChannels) status(t *testing.T, chainID, txID string) pb.CrossChannelRecord_Status {
+	record, err := crosschannel.UnmarshalRecord(channels[chainID].State[txID])
+	assert.NoError(t, err)
+	assert.NotNil(t, record)
+	return record.Status
+}
+
+func (channels testChannels) invoke(t *testing.T, chainID string, creator []byte, args ...string) pb.Response {
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "xscc"}}}
+	prop, _, err := utils.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, chainID, cis, creator)
+	assert.NoError(t, err)
+	propBytes, err := proto.Marshal(prop)
+	assert.NoError(t, err)
+	stub := channels[chainID]
+	stub.Creator = creator
+	byteArgs := [][]byte{}
+	for _, arg := range args {
+		byteArgs = append(byteArgs, []byte(arg))
+	}
+	return stub.MockInvokeWithSignedProposal("decision", byteArgs, &pb.SignedProposal{ProposalBytes: propBytes})
+}
+
+func newTestTxID(t *testing.T, creator []byte) (string, string) {
+	nonce := []byte("nonce")
+	txID, err := utils.ComputeProposalTxID(nonce, creator)
+	assert.NoError(t, err)
+	return txID, string(nonce)
+}
+
+func TestCommit(t *testing.T) {
+	channels := newTestChannels("cha", "chb")
+	creator := []byte("creator")
+	txID, _ := newTestTxID(t, creator)
+
+	// the transaction is prepared on the channel it was proposed on only
+	channels.setRecord(t, "cha", txID, pb.CrossChannelRecord_PREPARED, "cha", "chb")
+	res := channels.invoke(t, "cha", creator, Commit, txID)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "is not prepared on channel chb")
+	res = channels.invoke(t, "chb", creator, Commit, txID)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+
+	// it is decided on the channel it was proposed on first
+	channels.setRecord(t, "chb", txID, pb.CrossChannelRecord_PREPARED, "cha", "chb")
+	res = channels.invoke(t, "chb", creator, Commit, txID)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "is not committed on channel cha")
+	res = channels.invoke(t, "cha", []byte("anyone"), Commit, txID)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.Equal(t, pb.CrossChannelRecord_COMMITTED, channels.status(t, "cha", txID))
+	res = channels.invoke(t, "chb", []byte("anyone"), Commit, txID)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.Equal(t, pb.CrossChannelRecord_COMMITTED, channels.status(t, "chb", txID))
+
+	// a committed transaction is final
+	res = channels.invoke(t, "cha", creator, Commit, txID)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	_, nonce := newTestTxID(t, creator)
+	res = channels.invoke(t, "cha", creator, Abort, txID, nonce)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+
+	res = channels.invoke(t, "chb", creator, GetRecord, txID)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	record, err := crosschannel.UnmarshalRecord(res.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cha", "chb"}, record.Channels)
+}
+
+func TestAbort(t *testing.T) {
+	channels := newTestChannels("cha", "chb", "chc")
+	creator := []byte("creator")
+	txID, nonce := newTestTxID(t, creator)
+	channels.setRecord(t, "cha", txID, pb.CrossChannelRecord_PREPARED, "cha", "chb", "chc")
+	channels.setRecord(t, "chb", txID, pb.CrossChannelRecord_PREPARED, "cha", "chb", "chc")
+
+	// only the creator can abort the transaction
+	res := channels.invoke(t, "cha", []byte("other"), Abort, txID, nonce)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "can only be aborted by its creator")
+	res = channels.invoke(t, "cha", creator, Abort, txID)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+
+	// it is aborted on the channel it was proposed on first
+	res = channels.invoke(t, "chb", creator, Abort, txID, nonce)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "is not aborted on channel cha")
+	res = channels.invoke(t, "cha", creator, Abort, txID, nonce)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.Equal(t, pb.CrossChannelRecord_ABORTED, channels.status(t, "cha", txID))
+	res = channels.invoke(t, "chb", creator, Abort, txID, nonce)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.Equal(t, pb.CrossChannelRecord_ABORTED, channels.status(t, "chb", txID))
+
+	// a part not prepared yet can be aborted so it cannot be prepared anymore
+	res = channels.invoke(t, "chc", creator, Abort, txID, nonce)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.Equal(t, pb.CrossChannelRecord_ABORTED, channels.status(t, "chc", txID))
+
+	// an aborted transaction cannot be committed
+	res = channels.invoke(t, "cha", creator, Commit, txID)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+}
+
+func TestAdminAbort(t *testing.T) {
+	channels := newTestChannels("cha", "chb")
+	creator := []byte("creator")
+	txID, _ := newTestTxID(t, creator)
+	channels.setRecord(t, "cha", txID, pb.CrossChannelRecord_PREPARED, "cha", "chb")
+	channels.setRecord(t, "chb", txID, pb.CrossChannelRecord_PREPARED, "cha", "chb")
+
+	// only the admins can abort a transaction they did not create
+	res := channels.invoke(t, "cha", []byte("other"), AdminAbort, txID)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "Authorization request")
+
+	// it is aborted on the channel it was proposed on first
+	res = channels.invoke(t, "chb", []byte("admin"), AdminAbort, txID)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "is not aborted on channel cha")
+	res = channels.invoke(t, "cha", []byte("admin"), AdminAbort, txID)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.Equal(t, pb.CrossChannelRecord_ABORTED, channels.status(t, "cha", txID))
+	res = channels.invoke(t, "chb", []byte("admin"), AdminAbort, txID)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.Equal(t, pb.CrossChannelRecord_ABORTED, channels.status(t, "chb", txID))
+
+	// a committed transaction cannot be aborted by the admins either
+	txID2, _ := newTestTxID(t, []byte("creator2"))
+	channels.setRecord(t, "cha", txID2, pb.CrossChannelRecord_COMMITTED, "cha", "chb")
+	res = channels.invoke(t, "cha", []byte("admin"), AdminAbort, txID2)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+}
+
+func TestInvalidInvocations(t *testing.T) {
+	channels := newTestChannels("cha")
+	creator := []byte("creator")
+	txID, _ := newTestTxID(t, creator)
+
+	res := channels.invoke(t, "cha", creator, Commit)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	res = channels.invoke(t, "cha", creator, "unknown", txID)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	res = channels.invoke(t, "cha", creator, GetRecord, txID)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "is not recorded on channel cha")
+
+	channels["cha"].State[txID] = []byte("garbage")
+	res = channels.invoke(t, "cha", creator, GetRecord, txID)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+
+	// XSCC must be invoked through a proposal
+	res = channels["cha"].MockInvoke("decision", [][]byte{[]byte(Commit), []byte(txID)})
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transientstore
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+)
+
+var logger = flogging.MustGetLogger("transientstore")
+
+// StoreProvider provides an instance of a TransientStore
+type StoreProvider interface {
+	OpenStore(ledgerID string) (Store, error)
+	Close()
+}
+
+// Store manages the storage of private read-write sets for a ledger.
+// The private data is held in the transient store only from the time of endorsement
+// until the corresponding transaction is committed, after which it is purged
+type Store interface {
+	// Persist stores the private read-write set of a transaction along with the
+	// block height of the ledger at the time the private data was received
+	Persist(txid string, blockHeight uint64, privateSimulationResults *rwset.TxPvtReadWriteSet) error
+	// GetTxPvtRWSetByTxid returns all the private read-write sets that were persisted for the given txid
+	GetTxPvtRWSetByTxid(txid string) ([]*EndorserPvtSimulationResults, error)
+	// PurgeByTxids removes the private read-write sets of the given transactions
+	PurgeByTxids(txids []string) error
+	// PurgeByHeight removes the private read-write sets that were received
+	// at a block height lesser than the given maxBlockNumToRetain
+	PurgeByHeight(maxBlockNumToRetain uint64) error
+	// Shutdown closes the store
+	Shutdown()
+}
+
+// EndorserPvtSimulationResults captures the private simulation results of a transaction
+// along with the block height at which these were received
+type EndorserPvtSimulationResults struct {
+	ReceivedAtBlockHeight uint64
+	PvtSimulationResults  *rwset.TxPvtReadWriteSet
+}
+
+type storeProvider struct {
+	dbProvider *leveldbhelper.Provider
+}
+
+type store struct {
+	db       *leveldbhelper.DBHandle
+	ledgerID string
+}
+
+// NewStoreProvider instantiates TransientStoreProvider
+func NewStoreProvider() StoreProvider {
+	dbPath := ledgerconfig.GetTransientStorePath()
+	logger.Debugf("constructing transient StoreProvider dbPath=%s", dbPath)
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
+	return &storeProvider{dbProvider: dbProvider}
+}
+
+// OpenStore returns a handle to a ledgerID in Store
+func (provider *storeProvider) OpenStore(ledgerID string) (Store, error) {
+	return &store{db: provider.dbProvider.GetDBHandle(ledgerID), ledgerID: ledgerID}, nil
+}
+
+// Close closes the TransientStoreProvider
+func (provider *storeProvider) Close() {
+	provider.dbProvider.Close()
+}
+
+// Persist implements method in interface `Store`.
+// An index entry keyed by the block height is added so that
+// the stale private data can be purged by height
+func (s *store) Persist(txid string, blockHeight uint64, privateSimulationResults *rwset.TxPvtReadWriteSet) error {
+	logger.Debugf("Persisting private data to transient store for txid = %s", txid)
+	dbBatch := leveldbhelper.NewUpdateBatch()
+
+	// a uuid is appended to the key so that the private data received
+	// from multiple endorsers for the same txid does not overwrite each other
+	uuid := util.GenerateUUID()
+	compositeKeyPvtRWSet := createCompositeKeyForPvtRWSet(txid, uuid, blockHeight)
+	privateSimulationResultsBytes, err := proto.Marshal(privateSimulationResults)
+	if err != nil {
+		return err
+	}
+	dbBatch.Put(compositeKeyPvtRWSet, privateSimulationResultsBytes)
+
+	compositeKeyPurgeIndexByHeight := createCompositeKeyForPurgeIndexByHeight(blockHeight, txid, uuid)
+	dbBatch.Put(compositeKeyPurgeIndexByHeight, emptyValue)
+
+	return s.db.WriteBatch(dbBatch, true)
+}
+
+// GetTxPvtRWSetByTxid implements method in interface `Store`
+func (s *store) GetTxPvtRWSetByTxid(txid string) ([]*EndorserPvtSimulationResults, error) {
+	logger.Debugf("Getting private data from transient store for transaction %s", txid)
+	startKey := createTxidRangeStartKey(txid)
+	endKey := createTxidRangeEndKey(txid)
+	iter := s.db.GetIterator(startKey, endKey)
+	defer iter.Release()
+
+	var results []*EndorserPvtSimulationResults
+	for iter.Next() {
+		blockHeight := splitCompositeKeyOfPvtRWSet(iter.Key())
+		txPvtRWSet := &rwset.TxPvtReadWriteSet{}
+		if err := proto.Unmarshal(iter.Value(), txPvtRWSet); err != nil {
+			return nil, err
+		}
+		results = append(results, &EndorserPvtSimulationResults{
+			ReceivedAtBlockHeight: blockHeight,
+			PvtSimulationResults:  txPvtRWSet,
+		})
+	}
+	return results, iter.Error()
+}
+
+// PurgeByTxids implements method in interface `Store`
+func (s *store) PurgeByTxids(txids []string) error {
+	logger.Debugf("Purging private data from transient store for committed txids")
+	dbBatch := leveldbhelper.NewUpdateBatch()
+	for _, txid := range txids {
+		iter := s.db.GetIterator(createTxidRangeStartKey(txid), createTxidRangeEndKey(txid))
+		for iter.Next() {
+			compositeKeyPvtRWSet := iter.Key()
+			uuid, blockHeight := splitUUIDAndHeightOfPvtRWSetKey(compositeKeyPvtRWSet)
+			dbBatch.Delete(append([]byte(nil), compositeKeyPvtRWSet...))
+			dbBatch.Delete(createCompositeKeyForPurgeIndexByHeight(blockHeight, txid, uuid))
+		}
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			return err
+		}
+	}
+	return s.db.WriteBatch(dbBatch, true)
+}
+
+// PurgeByHeight implements method in interface `Store`
+func (s *store) PurgeByHeight(maxBlockNumToRetain uint64) error {
+	logger.Debugf("Purging private data from transient store for block height lesser than %d", maxBlockNumToRetain)
+	startKey := createPurgeIndexByHeightRangeStartKey(0)
+	endKey := createPurgeIndexByHeightRangeStartKey(maxBlockNumToRetain)
+	iter := s.db.GetIterator(startKey, endKey)
+	defer iter.Release()
+
+	dbBatch := leveldbhelper.NewUpdateBatch()
+	for iter.Next() {
+		compositeKeyPurgeIndexByHeight := iter.Key()
+		blockHeight, txid, uuid := splitCompositeKeyOfPurgeIndexByHeight(compositeKeyPurgeIndexByHeight)
+		dbBatch.Delete(append([]byte(nil), compositeKeyPurgeIndexByHeight...))
+		dbBatch.Delete(createCompositeKeyForPvtRWSet(txid, uuid, blockHeight))
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return s.db.WriteBatch(dbBatch, true)
+}
+
+// Shutdown implements method in interface `Store`
+func (s *store) Shutdown() {
+	// do nothing because shared db is used
+}
@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transientstore
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/fabric/common/ledger/util"
+)
+
+var (
+	prwsetPrefix             = []byte("P")[0] // key prefix for storing private read-write set in transient store.
+	purgeIndexByHeightPrefix = []byte("H")[0] // key prefix for storing index on private read-write set using height.
+	compositeKeySep          = byte(0x00)
+	lastKeyIndicator         = byte(0x01)
+	emptyValue               = []byte{}
+)
+
+// createCompositeKeyForPvtRWSet creates a key for storing private read-write set
+// in the transient store. The structure of the key is <prwsetPrefix>~txid~uuid~blockHeight.
+func createCompositeKeyForPvtRWSet(txid string, uuid string, blockHeight uint64) []byte {
+	var compositeKey []byte
+	compositeKey = append(compositeKey, prwsetPrefix)
+	compositeKey = append(compositeKey, compositeKeySep)
+	compositeKey = append(compositeKey, []byte(txid)...)
+	compositeKey = append(compositeKey, compositeKeySep)
+	compositeKey = append(compositeKey, []byte(uuid)...)
+	compositeKey = append(compositeKey, compositeKeySep)
+	compositeKey = append(compositeKey, util.EncodeOrderPreservingVarUint64(blockHeight)...)
+	return compositeKey
+}
+
+// createCompositeKeyForPurgeIndexByHeight creates a key to index private read-write set based on
+// the block height such that purge based on block height can be achieved. The structure
+// of the key is <purgeIndexByHeightPrefix>~blockHeight~txid~uuid.
+func createCompositeKeyForPurgeIndexByHeight(blockHeight uint64, txid string, uuid string) []byte {
+	var compositeKey []byte
+	compositeKey = append(compositeKey, purgeIndexByHeightPrefix)
+	compositeKey = append(compositeKey, compositeKeySep)
+	compositeKey = append(compositeKey, util.EncodeOrderPreservingVarUint64(blockHeight)...)
+	compositeKey = append(compositeKey, compositeKeySep)
+	compositeKey = append(compositeKey, []byte(txid)...)
+	compositeKey = append(compositeKey, compositeKeySep)
+	compositeKey = append(compositeKey, []byte(uuid)...)
+	return compositeKey
+}
+
+// splitCompositeKeyOfPvtRWSet splits the compositeKey (<prwsetPrefix>~txid~uuid~blockHeight)
+// and returns the blockHeight
+func splitCompositeKeyOfPvtRWSet(compositeKey []byte) uint64 {
+	_, blockHeight := splitUUIDAndHeightOfPvtRWSetKey(compositeKey)
+	return blockHeight
+}
+
+// splitUUIDAndHeightOfPvtRWSetKey splits the compositeKey (<prwsetPrefix>~txid~uuid~blockHeight)
+// and returns the uuid and the blockHeight
+func splitUUIDAndHeightOfPvtRWSetKey(compositeKey []byte) (string, uint64) {
+	// txid and uuid never contain the separator whereas the encoded block height may
+	splits := bytes.SplitN(compositeKey, []byte{compositeKeySep}, 4)
+	blockHeight, _ := util.DecodeOrderPreservingVarUint64(splits[3])
+	return string(splits[2]), blockHeight
+}
+
+// splitCompositeKeyOfPurgeIndexByHeight splits the compositeKey
+// (<purgeIndexByHeightPrefix>~blockHeight~txid~uuid) and returns the blockHeight, txid and uuid
+func splitCompositeKeyOfPurgeIndexByHeight(compositeKey []byte) (uint64, string, string) {
+	blockHeight, n := util.DecodeOrderPreservingVarUint64(compositeKey[2:])
+	splits := bytes.Split(compositeKey[2+n+1:], []byte{compositeKeySep})
+	return blockHeight, string(splits[0]), string(splits[1])
+}
+
+// createTxidRangeStartKey returns a startKey to do a range query on transient store using txid
+func createTxidRangeStartKey(txid string) []byte {
+	var startKey []byte
+	startKey = append(startKey, prwsetPrefix)
+	startKey = append(startKey, compositeKeySep)
+	startKey = append(startKey, []byte(txid)...)
+	startKey = append(startKey, compositeKeySep)
+	return startKey
+}
+
+// createTxidRangeEndKey returns a endKey to do a range query on transient store using txid
+func createTxidRangeEndKey(txid string) []byte {
+	var endKey []byte
+	endKey = append(endKey, prwsetPrefix)
+	endKey = append(endKey, compositeKeySep)
+	endKey = append(endKey, []byte(txid)...)
+	endKey = append(endKey, lastKeyIndicator)
+	return endKey
+}
+
+// createPurgeIndexByHeightRangeStartKey returns a startKey to do a range query on index stored in transient store
+// using blockHeight
+func createPurgeIndexByHeightRangeStartKey(blockHeight uint64) []byte {
+	var startKey []byte
+	startKey = append(startKey, purgeIndexByHeightPrefix)
+	startKey = append(startKey, compositeKeySep)
+	startKey = append(startKey, util.EncodeOrderPreservingVarUint64(blockHeight)...)
+	return startKey
+}
@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transientstore
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/core/transientstore")
+	flogging.SetModuleLevel("transientstore", "debug")
+	os.Exit(m.Run())
+}
+
+type testEnv struct {
+	storeProvider StoreProvider
+	store         Store
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	os.RemoveAll(ledgerconfig.GetTransientStorePath())
+	storeProvider := NewStoreProvider()
+	store, err := storeProvider.OpenStore("TestStore")
+	assert.NoError(t, err)
+	return &testEnv{storeProvider, store}
+}
+
+func (env *testEnv) cleanup() {
+	env.store.Shutdown()
+	env.storeProvider.Close()
+	os.RemoveAll(ledgerconfig.GetTransientStorePath())
+}
+
+func TestCompositeKeys(t *testing.T) {
+	compositeKey := createCompositeKeyForPvtRWSet("txid1", "uuid1", 256)
+	uuid, blockHeight := splitUUIDAndHeightOfPvtRWSetKey(compositeKey)
+	assert.Equal(t, "uuid1", uuid)
+	assert.Equal(t, uint64(256), blockHeight)
+
+	compositeKey = createCompositeKeyForPurgeIndexByHeight(256, "txid1", "uuid1")
+	blockHeight, txid, uuid := splitCompositeKeyOfPurgeIndexByHeight(compositeKey)
+	assert.Equal(t, uint64(256), blockHeight)
+	assert.Equal(t, "txid1", txid)
+	assert.Equal(t, "uuid1", uuid)
+}
+
+func TestTransientStorePersistAndRetrieve(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+
+	pvtRWSet1 := samplePvtRWSet("ns1", "coll1")
+	pvtRWSet2 := samplePvtRWSet("ns1", "coll2")
+	assert.NoError(t, env.store.Persist("txid1", 10, pvtRWSet1))
+	assert.NoError(t, env.store.Persist("txid1", 11, pvtRWSet2))
+	assert.NoError(t, env.store.Persist("txid2", 11, pvtRWSet1))
+	// a txid that is a prefix of another txid should not match
+	assert.NoError(t, env.store.Persist("txid", 11, pvtRWSet1))
+
+	results, err := env.store.GetTxPvtRWSetByTxid("txid1")
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	var collections []string
+	for _, result := range results {
+		collections = append(collections, result.PvtSimulationResults.NsPvtRwset[0].CollectionPvtRwset[0].CollectionName)
+		if result.ReceivedAtBlockHeight == 10 {
+			assert.Equal(t, pvtRWSet1, result.PvtSimulationResults)
+		}
+	}
+	assert.ElementsMatch(t, []string{"coll1", "coll2"}, collections)
+
+	results, err = env.store.GetTxPvtRWSetByTxid("txid3")
+	assert.NoError(t, err)
+	assert.Len(t, results, 0)
+}
+
+func TestTransientStorePurge(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+
+	pvtRWSet := samplePvtRWSet("ns1", "coll1")
+	assert.NoError(t, env.store.Persist("txid1", 10, pvtRWSet))
+	assert.NoError(t, env.store.Persist("txid2", 11, pvtRWSet))
+	assert.NoError(t, env.store.Persist("txid3", 12, pvtRWSet))
+	assert.NoError(t, env.store.Persist("txid4", 300, pvtRWSet))
+
+	assert.NoError(t, env.store.PurgeByTxids([]string{"txid2"}))
+	assertNumResults(t, env.store, "txid2", 0)
+	assertNumResults(t, env.store, "txid1", 1)
+
+	assert.NoError(t, env.store.PurgeByHeight(12))
+	assertNumResults(t, env.store, "txid1", 0)
+	assertNumResults(t, env.store, "txid3", 1)
+	assertNumResults(t, env.store, "txid4", 1)
+
+	assert.NoError(t, env.store.PurgeByHeight(301))
+	assertNumResults(t, env.store, "txid3", 0)
+	assertNumResults(t, env.store, "txid4", 0)
+}
+
+func assertNumResults(t *testing.T, store Store, txid string, expected int) {
+	results, err := store.GetTxPvtRWSetByTxid(txid)
+	assert.NoError(t, err)
+	assert.Len(t, results, expected)
+}
+
+func samplePvtRWSet(ns, coll string) *rwset.TxPvtReadWriteSet {
+	return &rwset.TxPvtReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+			{
+				Namespace: ns,
+				CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+					{CollectionName: coll, Rwset: []byte("RWSet for " + coll)},
+				},
+			},
+		},
+	}
+}
@@ -14,6 +14,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperledger/fabric/core/aclmgmt"
+	"github.com/hyperledger/fabric/core/policy"
+	policymocks "github.com/hyperledger/fabric/core/policy/mocks"
 	coreutil "github.com/hyperledger/fabric/core/testutil"
 	"github.com/hyperledger/fabric/events/producer"
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
@@ -256,7 +259,8 @@ func TestMain(m *testing.M) {
 
 	ehServer := producer.NewEventsServer(
 		uint(viper.GetInt("peer.events.buffersize")),
-		viper.GetDuration("peer.events.timeout"))
+		viper.GetDuration("peer.events.timeout"),
+		aclmgmt.NewACLProvider(policy.NewPolicyChecker(&policymocks.MockChannelPolicyManagerGetter{}, nil, nil), nil))
 	ehpb.RegisterEventsServer(grpcServer, ehServer)
 
 	go grpcServer.Serve(lis)
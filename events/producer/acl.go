@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package producer
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/aclmgmt"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// eventResources maps the event types which carry the data of a channel to
+// the resources of the ACLs the consumers must be granted access to. The
+// CHAINCODE events are not gated, as they do not carry the ID of the channel
+// of their transaction, and thus reach any member of the local MSP
+var eventResources = map[pb.EventType]string{
+	pb.EventType_BLOCK:         aclmgmt.BLOCKEVENT,
+	pb.EventType_FILTEREDBLOCK: aclmgmt.FILTEREDBLOCKEVENT,
+}
+
+// checkChannelAccess checks that the signed data of the registration of a consumer
+// is granted access to the events of the event type on the channel
+func checkChannelAccess(channelID string, eventType pb.EventType, signedData []*common.SignedData) error {
+	resName, ok := eventResources[eventType]
+	if !ok {
+		return nil
+	}
+	if channelID == "" {
+		return fmt.Errorf("the channel of the %s event is unknown", eventType)
+	}
+	if gEventProcessor.aclProvider == nil {
+		return fmt.Errorf("the ACLs of channel [%s] are not available", channelID)
+	}
+	if err := gEventProcessor.aclProvider.CheckACL(resName, channelID, signedData); err != nil {
+		return fmt.Errorf("access denied to the %s events of channel [%s]: %s", eventType, channelID, err)
+	}
+	return nil
+}
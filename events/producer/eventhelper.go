@@ -19,19 +19,22 @@ package producer
 import (
 	"fmt"
 
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 )
 
-// SendProducerBlockEvent sends block event to clients
+// SendProducerBlockEvent sends block event and filtered block event to clients
 func SendProducerBlockEvent(block *common.Block) error {
 	bevent := &common.Block{}
 	bevent.Header = block.Header
 	bevent.Metadata = block.Metadata
 	bevent.Data = &common.BlockData{}
+	fblock := &pb.FilteredBlock{Number: block.Header.Number}
+	txsFilter := getTxValidationFlags(block)
 	var channelID string
-	for _, d := range block.Data.Data {
+	for txIndex, d := range block.Data.Data {
 		ebytes := d
 		if ebytes != nil {
 			if env, err := utils.GetEnvelopeFromBlock(ebytes); err != nil {
@@ -49,6 +52,13 @@ func SendProducerBlockEvent(block *common.Block) error {
 				}
 				channelID = chdr.ChannelId
 
+				filteredTx := &pb.FilteredTransaction{
+					Txid:             chdr.TxId,
+					Type:             common.HeaderType(chdr.Type),
+					TxValidationCode: getTxValidationCode(txsFilter, txIndex),
+				}
+				fblock.FilteredTx = append(fblock.FilteredTx, filteredTx)
+
 				if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
 					logger.Debugf("Channel [%s]: Block event for block number [%d] contains transaction id: %s", channelID, block.Header.Number, chdr.TxId)
 					tx, err := utils.GetTransaction(payload.Data)
@@ -68,6 +78,15 @@ func SendProducerBlockEvent(block *common.Block) error {
 					if err != nil {
 						return fmt.Errorf("error unmarshalling chaincode action for block event: %s", err)
 					}
+					if caPayload.Events != nil {
+						ccEvent, err := utils.GetChaincodeEvents(caPayload.Events)
+						if err != nil {
+							return fmt.Errorf("error unmarshalling chaincode event for filtered block event: %s", err)
+						}
+						if ccEvent.EventName != "" {
+							filteredTx.ChaincodeEvents = append(filteredTx.ChaincodeEvents, &pb.FilteredChaincodeEvent{ChaincodeId: ccEvent.ChaincodeId, EventName: ccEvent.EventName})
+						}
+					}
 					// Drop read write set from transaction before sending block event
 					// Performance issue with chaincode deploy txs and causes nodejs grpc
 					// to hit max message size bug
@@ -102,7 +121,55 @@ func SendProducerBlockEvent(block *common.Block) error {
 
 	logger.Infof("Channel [%s]: Sending event for block number [%d]", channelID, block.Header.Number)
 
-	return Send(CreateBlockEvent(bevent))
+	if err := Send(CreateBlockEvent(bevent)); err != nil {
+		return err
+	}
+	fblock.ChannelId = channelID
+	return Send(CreateFilteredBlockEvent(fblock))
+}
+
+// getTxValidationFlags returns the validation flags of the transactions of the
+// block, which are not set in the blocks that are not validated by the committer
+func getTxValidationFlags(block *common.Block) util.TxValidationFlags {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		return nil
+	}
+	return util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+}
+
+func getTxValidationCode(txsFilter util.TxValidationFlags, txIndex int) pb.TxValidationCode {
+	if txIndex >= len(txsFilter) {
+		return pb.TxValidationCode_VALID
+	}
+	return txsFilter.Flag(txIndex)
+}
+
+// getEventChannelID returns the channel of the block and filtered block events,
+// and an empty string for the other events
+func getEventChannelID(e *pb.Event) string {
+	switch evt := e.Event.(type) {
+	case *pb.Event_FilteredBlock:
+		return evt.FilteredBlock.ChannelId
+	case *pb.Event_Block:
+		if evt.Block.Data == nil || len(evt.Block.Data.Data) == 0 {
+			return ""
+		}
+		env, err := utils.GetEnvelopeFromBlock(evt.Block.Data.Data[0])
+		if err != nil {
+			return ""
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil || payload.Header == nil {
+			return ""
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return ""
+		}
+		return chdr.ChannelId
+	default:
+		return ""
+	}
 }
 
 //CreateBlockEvent creates a Event from a Block
@@ -110,6 +177,11 @@ func CreateBlockEvent(te *common.Block) *pb.Event {
 	return &pb.Event{Event: &pb.Event_Block{Block: te}}
 }
 
+//CreateFilteredBlockEvent creates an Event from a FilteredBlock
+func CreateFilteredBlockEvent(fb *pb.FilteredBlock) *pb.Event {
+	return &pb.Event{Event: &pb.Event_FilteredBlock{FilteredBlock: fb}}
+}
+
 //CreateChaincodeEvent creates a Event from a ChaincodeEvent
 func CreateChaincodeEvent(te *pb.ChaincodeEvent) *pb.Event {
 	return &pb.Event{Event: &pb.Event_ChaincodeEvent{ChaincodeEvent: te}}
@@ -21,6 +21,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hyperledger/fabric/core/aclmgmt"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -210,6 +211,10 @@ type eventProcessor struct {
 	//if 0, if buffer full, will block and guarantee the event will be sent out
 	//if > 0, if buffer full, blocks till timeout
 	timeout time.Duration
+
+	//checks the access of the consumers to the block and filtered
+	//block events of the channels
+	aclProvider aclmgmt.ACLProvider
 }
 
 //global eventProcessor singleton created by initializeEvents. Openchain producers
@@ -224,6 +229,7 @@ func (ep *eventProcessor) start() {
 
 		var hl handlerList
 		eType := getMessageType(e)
+		channelID := getEventChannelID(e)
 		ep.Lock()
 		if hl, _ = ep.eventConsumers[eType]; hl == nil {
 			logger.Errorf("Event of type %s does not exist", eType)
@@ -234,7 +240,7 @@ func (ep *eventProcessor) start() {
 		ep.Unlock()
 
 		hl.foreach(e, func(h *handler) {
-			if e.Event != nil {
+			if e.Event != nil && h.hasChannelAccess(eType, channelID) {
 				h.SendMessage(e)
 			}
 		})
@@ -243,12 +249,12 @@ func (ep *eventProcessor) start() {
 }
 
 //initialize and start
-func initializeEvents(bufferSize uint, tout time.Duration) {
+func initializeEvents(bufferSize uint, tout time.Duration, aclProvider aclmgmt.ACLProvider) {
 	if gEventProcessor != nil {
 		panic("should not be called twice")
 	}
 
-	gEventProcessor = &eventProcessor{eventConsumers: make(map[pb.EventType]handlerList), eventChannel: make(chan *pb.Event, bufferSize), timeout: tout}
+	gEventProcessor = &eventProcessor{eventConsumers: make(map[pb.EventType]handlerList), eventChannel: make(chan *pb.Event, bufferSize), timeout: tout, aclProvider: aclProvider}
 
 	addInternalEventTypes()
 
@@ -272,6 +278,8 @@ func AddEventType(eventType pb.EventType) error {
 		gEventProcessor.eventConsumers[eventType] = &chaincodeHandlerList{handlers: make(map[string]map[string]map[*handler]bool)}
 	case pb.EventType_REJECTION:
 		gEventProcessor.eventConsumers[eventType] = &genericHandlerList{handlers: make(map[*handler]bool)}
+	case pb.EventType_FILTEREDBLOCK:
+		gEventProcessor.eventConsumers[eventType] = &genericHandlerList{handlers: make(map[*handler]bool)}
 	}
 	gEventProcessor.Unlock()
 
@@ -160,7 +160,8 @@ func TestInitializeEvents_twice(t *testing.T) {
 	initializeEventsTwice := func() {
 		initializeEvents(
 			uint(viper.GetInt("peer.events.buffersize")),
-			viper.GetDuration("peer.events.timeout"))
+			viper.GetDuration("peer.events.timeout"),
+			nil)
 	}
 	assert.Panics(t, initializeEventsTwice)
 }
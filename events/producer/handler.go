@@ -19,18 +19,24 @@ package producer
 import (
 	"fmt"
 	"strconv"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
 type handler struct {
+	sync.RWMutex
 	ChatStream       pb.Events_ChatServer
 	interestedEvents map[string]*pb.Interest
 	RemoteAddr       string
+	// the signed data of the last registration, against which the
+	// channel policies are evaluated when the events are sent
+	signedData []*common.SignedData
 }
 
 func newEventHandler(stream pb.Events_ChatServer) (*handler, error) {
@@ -46,7 +52,9 @@ func newEventHandler(stream pb.Events_ChatServer) (*handler, error) {
 // Stop stops this handler
 func (h *handler) Stop() error {
 	h.deregisterAll()
+	h.Lock()
 	h.interestedEvents = nil
+	h.Unlock()
 	logger.Debug("handler stopped for", h.RemoteAddr)
 	return nil
 }
@@ -56,6 +64,8 @@ func getInterestKey(interest pb.Interest) string {
 	switch interest.EventType {
 	case pb.EventType_BLOCK:
 		key = "/" + strconv.Itoa(int(pb.EventType_BLOCK))
+	case pb.EventType_FILTEREDBLOCK:
+		key = "/" + strconv.Itoa(int(pb.EventType_FILTEREDBLOCK))
 	case pb.EventType_REJECTION:
 		key = "/" + strconv.Itoa(int(pb.EventType_REJECTION))
 	case pb.EventType_CHAINCODE:
@@ -67,15 +77,26 @@ func getInterestKey(interest pb.Interest) string {
 	return key
 }
 
-func (h *handler) register(iMsg []*pb.Interest) error {
+func (h *handler) register(iMsg []*pb.Interest, signedData []*common.SignedData) error {
 	// Could consider passing interest array to registerHandler
 	// and only lock once for entire array here
 	for _, v := range iMsg {
+		// the interests restricted to a channel are rejected upfront if the
+		// consumer may not receive the events of the channel
+		if v.ChainID != "" {
+			if err := checkChannelAccess(v.ChainID, v.EventType, signedData); err != nil {
+				logger.Errorf("could not register %s for %s: %s", v, h.RemoteAddr, err)
+				continue
+			}
+		}
 		if err := registerHandler(v, h); err != nil {
 			logger.Errorf("could not register %s for %s: %s", v, h.RemoteAddr, err)
 			continue
 		}
+		h.Lock()
 		h.interestedEvents[getInterestKey(*v)] = v
+		h.signedData = signedData
+		h.Unlock()
 	}
 
 	return nil
@@ -87,19 +108,57 @@ func (h *handler) deregister(iMsg []*pb.Interest) error {
 			logger.Errorf("could not deregister %s for %s: %s", v, h.RemoteAddr, err)
 			continue
 		}
+		h.Lock()
 		delete(h.interestedEvents, getInterestKey(*v))
+		h.Unlock()
 	}
 	return nil
 }
 
 func (h *handler) deregisterAll() {
+	// the handler lock is not held while deregistering, as the event processor
+	// acquires it while holding the lock of the handler lists
+	h.RLock()
+	interests := make(map[string]*pb.Interest, len(h.interestedEvents))
 	for k, v := range h.interestedEvents {
+		interests[k] = v
+	}
+	h.RUnlock()
+
+	for k, v := range interests {
 		if err := deRegisterHandler(v, h); err != nil {
 			logger.Errorf("could not deregister %s for %s: %s", v, h.RemoteAddr, err)
 			continue
 		}
+		h.Lock()
 		delete(h.interestedEvents, k)
+		h.Unlock()
+	}
+}
+
+// hasChannelAccess tells whether an event of the given type and channel may be sent
+// to the consumer. The events carrying the data of a channel are only sent if the
+// consumer registered for the channel, or for all of them, and is granted access by
+// the ACLs of the channel. They are checked on every event, so that the consumers no
+// longer granted access after a configuration update stop receiving the events.
+// The events of the other types, CHAINCODE events included, are always sent
+func (h *handler) hasChannelAccess(eventType pb.EventType, channelID string) bool {
+	if _, ok := eventResources[eventType]; !ok {
+		return true
+	}
+
+	h.RLock()
+	interest := h.interestedEvents[getInterestKey(pb.Interest{EventType: eventType})]
+	signedData := h.signedData
+	h.RUnlock()
+	if interest == nil || (interest.ChainID != "" && interest.ChainID != channelID) {
+		return false
 	}
+	if err := checkChannelAccess(channelID, eventType, signedData); err != nil {
+		logger.Warningf("Not sending event to %s: %s", h.RemoteAddr, err)
+		return false
+	}
+	return true
 }
 
 // HandleMessage handles the Openchain messages for the Peer.
@@ -112,7 +171,8 @@ func (h *handler) HandleMessage(msg *pb.SignedEvent) error {
 	switch evt.Event.(type) {
 	case *pb.Event_Register:
 		eventsObj := evt.GetRegister()
-		if err := h.register(eventsObj.Events); err != nil {
+		signedData := []*common.SignedData{{Data: msg.EventBytes, Identity: evt.Creator, Signature: msg.Signature}}
+		if err := h.register(eventsObj.Events, signedData); err != nil {
 			return fmt.Errorf("could not register events for %s: %s", h.RemoteAddr, err)
 		}
 	case *pb.Event_Unregister:
@@ -149,12 +209,12 @@ func (h *handler) SendMessage(msg *pb.Event) error {
 // Validation of the creator identity's validity is done by checking with local MSP to ensure the
 // submitter is a member in the same organization as the peer
 //
-// TODO: ideally this should also check each channel's "Readers" policy to ensure the identity satisfies
-// each channel's access control policy. This step is necessary because the registered listener is going
-// to get read access to all channels by receiving Block events from all channels.
-// However, this is not being done for v1.0 due to complexity concerns and the need to complex a stable,
-// minimally viable release. Eventually events will be made channel-specific, at which point this method
-// should be revisited
+// The access to the BLOCK and FILTEREDBLOCK events of each channel is controlled separately with
+// the ACLs of the channel, see checkChannelAccess. The CHAINCODE events are not, so any member of
+// the local MSP may receive the chaincode events of all the channels.
+//
+// TODO: ideally the CHAINCODE events should also be checked against the ACLs of their channel, which
+// requires the chaincode events to carry the ID of the channel of their transaction
 func validateEventMessage(signedEvt *pb.SignedEvent) (*pb.Event, error) {
 	logger.Debugf("ValidateEventMessage starts for signed event %p", signedEvt)
 
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/aclmgmt"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -34,13 +35,15 @@ type EventsServer struct {
 //singleton - if we want to create multiple servers, we need to subsume events.gEventConsumers into EventsServer
 var globalEventsServer *EventsServer
 
-// NewEventsServer returns a EventsServer
-func NewEventsServer(bufferSize uint, timeout time.Duration) *EventsServer {
+// NewEventsServer returns a EventsServer. The block and filtered block events of a
+// channel are only sent to the consumers granted access to them on the channel
+// by the given ACLProvider
+func NewEventsServer(bufferSize uint, timeout time.Duration, aclProvider aclmgmt.ACLProvider) *EventsServer {
 	if globalEventsServer != nil {
 		panic("Cannot create multiple event hub servers")
 	}
 	globalEventsServer = new(EventsServer)
-	initializeEvents(bufferSize, timeout)
+	initializeEvents(bufferSize, timeout, aclProvider)
 	//initializeCCEventProcessor(bufferSize, timeout)
 	return globalEventsServer
 }
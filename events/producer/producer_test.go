@@ -34,17 +34,26 @@ import (
 	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	mmsp "github.com/hyperledger/fabric/common/mocks/msp"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/config"
+	ledgerutil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/core/policy"
+	policymocks "github.com/hyperledger/fabric/core/policy/mocks"
 	coreutil "github.com/hyperledger/fabric/core/testutil"
 	"github.com/hyperledger/fabric/events/consumer"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
+	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
 	ehpb "github.com/hyperledger/fabric/protos/peer"
+	ptestutils "github.com/hyperledger/fabric/protos/testutils"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -340,11 +349,170 @@ func TestUnregister(t *testing.T) {
 
 }
 
+// newTestACLProvider returns the ACL provider of the test channels: the consumers
+// may read the blocks of the test chain, may only track the transactions of
+// "writerschannel" and may not receive the events of "deniedchannel"
+func newTestACLProvider() aclmgmt.ACLProvider {
+	denied := &mockpolicies.Policy{Err: errors.New("signature set did not satisfy policy")}
+	pmGetter := &policymocks.MockChannelPolicyManagerGetter{Managers: map[string]policies.Manager{
+		util.GetTestChainID(): &mockpolicies.Manager{Policy: &mockpolicies.Policy{}},
+		"writerschannel": &mockpolicies.Manager{PolicyMap: map[string]policies.Policy{
+			policies.ChannelApplicationReaders: denied,
+			policies.ChannelApplicationWriters: &mockpolicies.Policy{},
+		}},
+		"deniedchannel": &mockpolicies.Manager{Policy: denied},
+	}}
+	return aclmgmt.NewACLProvider(policy.NewPolicyChecker(pmGetter, nil, nil), nil)
+}
+
+func TestCheckChannelAccess(t *testing.T) {
+	signedData := []*common.SignedData{{Data: []byte("data"), Identity: signerSerialized, Signature: []byte("signature")}}
+
+	assert.NoError(t, checkChannelAccess(util.GetTestChainID(), peer.EventType_BLOCK, signedData))
+	assert.NoError(t, checkChannelAccess(util.GetTestChainID(), peer.EventType_FILTEREDBLOCK, signedData))
+	assert.Error(t, checkChannelAccess("writerschannel", peer.EventType_BLOCK, signedData))
+	assert.NoError(t, checkChannelAccess("writerschannel", peer.EventType_FILTEREDBLOCK, signedData))
+	assert.Error(t, checkChannelAccess("deniedchannel", peer.EventType_BLOCK, signedData))
+	assert.Error(t, checkChannelAccess("deniedchannel", peer.EventType_FILTEREDBLOCK, signedData))
+	assert.Error(t, checkChannelAccess("missingchannel", peer.EventType_FILTEREDBLOCK, signedData))
+	assert.Error(t, checkChannelAccess("", peer.EventType_BLOCK, signedData))
+	// the chaincode events are not bound to a channel
+	assert.NoError(t, checkChannelAccess("", peer.EventType_CHAINCODE, signedData))
+}
+
+type capturingStream struct {
+	mockstream
+	events chan *peer.Event
+}
+
+func (s *capturingStream) Send(e *peer.Event) error {
+	s.events <- e
+	return nil
+}
+
+func (s *capturingStream) expectEvent(t *testing.T) *peer.Event {
+	select {
+	case e := <-s.events:
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return nil
+	}
+}
+
+func (s *capturingStream) expectNoEvent(t *testing.T) {
+	select {
+	case e := <-s.events:
+		t.Fatalf("unexpected event %s", e)
+	case <-time.After(time.Second):
+	}
+}
+
+func newCapturingHandler(t *testing.T, interests []*peer.Interest) (*handler, *capturingStream) {
+	stream := &capturingStream{events: make(chan *peer.Event, 10)}
+	h, err := newEventHandler(stream)
+	assert.NoError(t, err)
+	signedData := []*common.SignedData{{Data: []byte("data"), Identity: signerSerialized, Signature: []byte("signature")}}
+	assert.NoError(t, h.register(interests, signedData))
+	return h, stream
+}
+
+// constructEventsTestBlock constructs a block of transactions setting the given
+// chaincode events, and returns it with the IDs of the transactions
+func constructEventsTestBlock(t *testing.T, channelID string, number uint64, ccEvents []*peer.ChaincodeEvent) (*common.Block, []string) {
+	block := common.NewBlock(number, nil)
+	var txIDs []string
+	for _, ccEvent := range ccEvents {
+		var eventBytes []byte
+		if ccEvent != nil {
+			var err error
+			eventBytes, err = proto.Marshal(ccEvent)
+			assert.NoError(t, err)
+		}
+		env, txID, err := ptestutils.ConstructUnsingedTxEnv(channelID, &peer.ChaincodeID{Name: "foo", Version: "v1"}, &peer.Response{Status: 200}, []byte("read/write set"), eventBytes, nil)
+		assert.NoError(t, err)
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(env))
+		txIDs = append(txIDs, txID)
+	}
+	return block, txIDs
+}
+
+func TestFilteredBlockEvent(t *testing.T) {
+	h, stream := newCapturingHandler(t, []*peer.Interest{
+		{EventType: peer.EventType_BLOCK, ChainID: "writerschannel"},
+		{EventType: peer.EventType_FILTEREDBLOCK, ChainID: "writerschannel"},
+	})
+	defer h.Stop()
+	// the consumer may not read the blocks of the channel
+	assert.Len(t, h.interestedEvents, 1)
+
+	block, txIDs := constructEventsTestBlock(t, "writerschannel", 5, []*peer.ChaincodeEvent{
+		{ChaincodeId: "foo", EventName: "transfer", Payload: []byte("private payload")},
+		nil,
+	})
+	txsFilter := ledgerutil.NewTxValidationFlags(2)
+	txsFilter.SetFlag(1, peer.TxValidationCode_MVCC_READ_CONFLICT)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+	assert.NoError(t, SendProducerBlockEvent(block))
+
+	expected := &peer.FilteredBlock{
+		ChannelId: "writerschannel",
+		Number:    5,
+		FilteredTx: []*peer.FilteredTransaction{
+			{
+				Txid:             txIDs[0],
+				Type:             common.HeaderType_ENDORSER_TRANSACTION,
+				TxValidationCode: peer.TxValidationCode_VALID,
+				ChaincodeEvents:  []*peer.FilteredChaincodeEvent{{ChaincodeId: "foo", EventName: "transfer"}},
+			},
+			{
+				Txid:             txIDs[1],
+				Type:             common.HeaderType_ENDORSER_TRANSACTION,
+				TxValidationCode: peer.TxValidationCode_MVCC_READ_CONFLICT,
+			},
+		},
+	}
+	// the block event, sent first, is not received
+	assert.Equal(t, expected, stream.expectEvent(t).GetFilteredBlock())
+	stream.expectNoEvent(t)
+}
+
+func TestBlockEventChannelAccess(t *testing.T) {
+	// the consumer registers for the events of all the channels
+	h, stream := newCapturingHandler(t, []*peer.Interest{
+		{EventType: peer.EventType_BLOCK},
+		{EventType: peer.EventType_FILTEREDBLOCK},
+	})
+	defer h.Stop()
+
+	block, _ := constructEventsTestBlock(t, "deniedchannel", 1, []*peer.ChaincodeEvent{nil})
+	assert.NoError(t, SendProducerBlockEvent(block))
+	stream.expectNoEvent(t)
+
+	block, _ = constructEventsTestBlock(t, "writerschannel", 1, []*peer.ChaincodeEvent{nil})
+	assert.NoError(t, SendProducerBlockEvent(block))
+	assert.NotNil(t, stream.expectEvent(t).GetFilteredBlock())
+	stream.expectNoEvent(t)
+
+	block, _ = constructEventsTestBlock(t, util.GetTestChainID(), 1, []*peer.ChaincodeEvent{nil})
+	assert.NoError(t, SendProducerBlockEvent(block))
+	assert.NotNil(t, stream.expectEvent(t).GetBlock())
+	assert.NotNil(t, stream.expectEvent(t).GetFilteredBlock())
+
+	// the consumer registered for a channel does not receive the events of the others
+	h2, stream2 := newCapturingHandler(t, []*peer.Interest{{EventType: peer.EventType_BLOCK, ChainID: util.GetTestChainID()}})
+	defer h2.Stop()
+	block, _ = constructEventsTestBlock(t, "writerschannel", 2, []*peer.ChaincodeEvent{nil})
+	assert.NoError(t, SendProducerBlockEvent(block))
+	stream2.expectNoEvent(t)
+}
+
 func TestNewEventsServer(t *testing.T) {
 	doubleCreation := func() {
 		NewEventsServer(
 			uint(viper.GetInt("peer.events.buffersize")),
-			viper.GetDuration("peer.events.timeout"))
+			viper.GetDuration("peer.events.timeout"),
+			newTestACLProvider())
 	}
 	assert.Panics(t, doubleCreation)
 
@@ -466,7 +634,8 @@ func TestMain(m *testing.M) {
 
 	ehServer = NewEventsServer(
 		uint(viper.GetInt("peer.events.buffersize")),
-		viper.GetDuration("peer.events.timeout"))
+		viper.GetDuration("peer.events.timeout"),
+		newTestACLProvider())
 	ehpb.RegisterEventsServer(grpcServer, ehServer)
 
 	go grpcServer.Serve(lis)
@@ -25,6 +25,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/peer/common"
 )
 
@@ -82,7 +83,7 @@ func initMSP(mainFlags *pflag.FlagSet) {
 	mainFlags.StringVarP(&mspMgrConfigDir, "mspcfgdir", "m", defaultMspDir, "Path to MSP dir")
 	mainFlags.StringVarP(&mspID, "mspid", "i", "DEFAULT", "MSP ID")
 
-	err = common.InitCrypto(mspMgrConfigDir, mspID)
+	err = common.InitCrypto(mspMgrConfigDir, mspID, msp.ProviderTypeToString(msp.FABRIC))
 	if err != nil {
 		panic(err.Error())
 	}
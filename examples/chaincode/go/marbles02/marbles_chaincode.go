@@ -36,35 +36,16 @@ under the License.
 //   peer chaincode query -C myc1 -n marbles -c '{"Args":["queryMarblesByOwner","tom"]}'
 //   peer chaincode query -C myc1 -n marbles -c '{"Args":["queryMarbles","{\"selector\":{\"owner\":\"tom\"}}"]}'
 
-//The following examples demonstrate creating indexes on CouchDB
-//Example hostname:port configurations
+//The indexes below are packaged with the chaincode in the directory META-INF/statedb/couchdb/indexes
+//and are created by the peer on the CouchDB database of the channel when the chaincode is
+//instantiated or upgraded, or when it is installed on a peer of a channel where it is already instantiated.
+//The peer prefixes the fields with the "data" wrapper, except chaincodeid which must be added for all queries
 //
-//Docker or vagrant environments:
-// http://couchdb:5984/
+// Index for chaincodeid, docType, owner (indexOwner.json).
+// {"index":{"fields":["chaincodeid","docType","owner"]},"ddoc":"indexOwnerDoc", "name":"indexOwner","type":"json"}
 //
-//Inside couchdb docker container
-// http://127.0.0.1:5984/
-
-// Index for chaincodeid, docType, owner.
-// Note that docType and owner fields must be prefixed with the "data" wrapper
-// chaincodeid must be added for all queries
-//
-// Definition for use with Fauxton interface
-// {"index":{"fields":["chaincodeid","data.docType","data.owner"]},"ddoc":"indexOwnerDoc", "name":"indexOwner","type":"json"}
-//
-// example curl definition for use with command line
-// curl -i -X POST -H "Content-Type: application/json" -d "{\"index\":{\"fields\":[\"chaincodeid\",\"data.docType\",\"data.owner\"]},\"name\":\"indexOwner\",\"ddoc\":\"indexOwnerDoc\",\"type\":\"json\"}" http://hostname:port/myc1/_index
-//
-
-// Index for chaincodeid, docType, owner, size (descending order).
-// Note that docType, owner and size fields must be prefixed with the "data" wrapper
-// chaincodeid must be added for all queries
-//
-// Definition for use with Fauxton interface
-// {"index":{"fields":[{"data.size":"desc"},{"chaincodeid":"desc"},{"data.docType":"desc"},{"data.owner":"desc"}]},"ddoc":"indexSizeSortDoc", "name":"indexSizeSortDesc","type":"json"}
-//
-// example curl definition for use with command line
-// curl -i -X POST -H "Content-Type: application/json" -d "{\"index\":{\"fields\":[{\"data.size\":\"desc\"},{\"chaincodeid\":\"desc\"},{\"data.docType\":\"desc\"},{\"data.owner\":\"desc\"}]},\"ddoc\":\"indexSizeSortDoc\", \"name\":\"indexSizeSortDesc\",\"type\":\"json\"}" http://hostname:port/myc1/_index
+// Index for chaincodeid, docType, owner, size (descending order) (indexSizeSortDesc.json).
+// {"index":{"fields":[{"size":"desc"},{"chaincodeid":"desc"},{"docType":"desc"},{"owner":"desc"}]},"ddoc":"indexSizeSortDoc", "name":"indexSizeSortDesc","type":"json"}
 
 // Rich Query with index design doc and index name specified (Only supported if CouchDB is used as state database):
 //   peer chaincode query -C myc1 -n marbles -c '{"Args":["queryMarbles","{\"selector\":{\"docType\":\"marble\",\"owner\":\"tom\"}, \"use_index\":[\"_design/indexOwnerDoc\", \"indexOwner\"]}"]}'
@@ -674,6 +674,9 @@ func (d *gossipDiscoveryImpl) periodicalCheckAlive() {
 			d.logger.Debugf("Got %v dead members: %v", len(dead), dead)
 			d.expireDeadMembers(dead)
 		}
+		d.lock.RLock()
+		aliveMembersCount.Set(float64(len(d.aliveLastTS)))
+		d.lock.RUnlock()
 	}
 }
 
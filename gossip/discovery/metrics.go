@@ -0,0 +1,16 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import "github.com/hyperledger/fabric/common/metrics"
+
+var aliveMembersCount = metrics.NewGauge(metrics.GaugeOpts{
+	Namespace: "gossip",
+	Subsystem: "membership",
+	Name:      "total_peers_known",
+	Help:      "The number of alive peers known by gossip, this peer excluded.",
+})
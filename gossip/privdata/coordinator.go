@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/transientstore"
+	"github.com/hyperledger/fabric/gossip/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+const (
+	transientBlockRetentionConfigKey = "peer.gossip.pvtData.transientstoreMaxBlockRetention"
+	transientBlockRetentionDefault   = 1000
+)
+
+// Coordinator orchestrates the flow of the new
+// blocks arrival and in flight transient data, responsible
+// to complete missing parts of transient data for given block.
+type Coordinator interface {
+	committer.Committer
+}
+
+type coordinator struct {
+	committer.Committer
+	store                   transientstore.Store
+	transientBlockRetention uint64
+}
+
+// NewCoordinator creates a new instance of coordinator which commits the
+// blocks through the given committer, along with the private data of their
+// transactions that was received and kept in the transient store
+func NewCoordinator(committer committer.Committer, store transientstore.Store) Coordinator {
+	transientBlockRetention := uint64(util.GetIntOrDefault(transientBlockRetentionConfigKey, transientBlockRetentionDefault))
+	return &coordinator{
+		Committer:               committer,
+		store:                   store,
+		transientBlockRetention: transientBlockRetention,
+	}
+}
+
+// Commit commits the block along with the private data that was
+// found in the transient store for the transactions of the block
+func (c *coordinator) Commit(block *common.Block) error {
+	if block == nil || block.Data == nil || block.Header == nil {
+		return fmt.Errorf("block is malformed")
+	}
+	pvtData, err := c.fetchPvtDataFromTransientStore(block)
+	if err != nil {
+		return err
+	}
+	return c.CommitWithPvtData(&ledger.BlockAndPvtData{Block: block, BlockPvtData: pvtData})
+}
+
+// CommitWithPvtData commits the block and the given private data, and then
+// purges the private data of the block's transactions from the transient store
+func (c *coordinator) CommitWithPvtData(blockAndPvtData *ledger.BlockAndPvtData) error {
+	if err := c.Committer.CommitWithPvtData(blockAndPvtData); err != nil {
+		return err
+	}
+
+	block := blockAndPvtData.Block
+	if err := c.store.PurgeByTxids(extractTxIDs(block)); err != nil {
+		logger.Error("Purging transactions of block", block.Header.Number, "from the transient store failed:", err)
+	}
+
+	seqNum := block.Header.Number
+	if seqNum > c.transientBlockRetention && seqNum%c.transientBlockRetention == 0 {
+		// Remove the private data that was received long ago and
+		// whose transactions have never made it into a block
+		if err := c.store.PurgeByHeight(seqNum - c.transientBlockRetention); err != nil {
+			logger.Error("Purging the transient store below height", seqNum-c.transientBlockRetention, "failed:", err)
+		}
+	}
+	return nil
+}
+
+// fetchPvtDataFromTransientStore collects the private read-write sets
+// of the block's transactions that were persisted in the transient store
+func (c *coordinator) fetchPvtDataFromTransientStore(block *common.Block) (map[uint64]*ledger.TxPvtData, error) {
+	pvtData := make(map[uint64]*ledger.TxPvtData)
+	for seqInBlock, txID := range extractTxIDs(block) {
+		if txID == "" {
+			continue
+		}
+		results, err := c.store.GetTxPvtRWSetByTxid(txID)
+		if err != nil {
+			return nil, fmt.Errorf("failed retrieving private data of txID %s from the transient store: %s", txID, err)
+		}
+		if len(results) == 0 {
+			continue
+		}
+		pvtData[uint64(seqInBlock)] = &ledger.TxPvtData{
+			SeqInBlock: uint64(seqInBlock),
+			WriteSet:   mergePvtRWSets(results),
+		}
+	}
+	return pvtData, nil
+}
+
+// mergePvtRWSets merges the private read-write sets that were received for a
+// transaction into a single one. Each collection is disseminated separately
+// and may be received from several endorsers, hence only the first
+// occurrence of every collection is kept
+func mergePvtRWSets(results []*transientstore.EndorserPvtSimulationResults) *rwset.TxPvtReadWriteSet {
+	merged := &rwset.TxPvtReadWriteSet{}
+	namespaces := make(map[string]*rwset.NsPvtReadWriteSet)
+	collections := make(map[string]struct{})
+	for _, res := range results {
+		if res.PvtSimulationResults == nil {
+			continue
+		}
+		merged.DataModel = res.PvtSimulationResults.DataModel
+		for _, nsRWSet := range res.PvtSimulationResults.NsPvtRwset {
+			ns, exists := namespaces[nsRWSet.Namespace]
+			if !exists {
+				ns = &rwset.NsPvtReadWriteSet{Namespace: nsRWSet.Namespace}
+				namespaces[nsRWSet.Namespace] = ns
+				merged.NsPvtRwset = append(merged.NsPvtRwset, ns)
+			}
+			for _, collRWSet := range nsRWSet.CollectionPvtRwset {
+				collKey := nsRWSet.Namespace + "~" + collRWSet.CollectionName
+				if _, exists := collections[collKey]; exists {
+					continue
+				}
+				collections[collKey] = struct{}{}
+				ns.CollectionPvtRwset = append(ns.CollectionPvtRwset, collRWSet)
+			}
+		}
+	}
+	return merged
+}
+
+// extractTxIDs returns the IDs of the transactions in the block, indexed by
+// their position in the block. Entries that cannot be parsed are left empty
+func extractTxIDs(block *common.Block) []string {
+	txIDs := make([]string, len(block.Data.Data))
+	for seqInBlock, envBytes := range block.Data.Data {
+		env, err := utils.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			logger.Warning("Invalid envelope at position", seqInBlock, "of block", block.Header.Number, ":", err)
+			continue
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil || payload.Header == nil {
+			logger.Warning("Invalid payload at position", seqInBlock, "of block", block.Header.Number, ":", err)
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			logger.Warning("Invalid channel header at position", seqInBlock, "of block", block.Header.Number, ":", err)
+			continue
+		}
+		txIDs[seqInBlock] = chdr.TxId
+	}
+	return txIDs
+}
@@ -0,0 +1,165 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/transientstore"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCommitter struct {
+	committed []*ledger.BlockAndPvtData
+	err       error
+}
+
+func (mc *mockCommitter) Commit(block *common.Block) error {
+	return mc.CommitWithPvtData(&ledger.BlockAndPvtData{Block: block})
+}
+
+func (mc *mockCommitter) CommitWithPvtData(blockAndPvtData *ledger.BlockAndPvtData) error {
+	if mc.err != nil {
+		return mc.err
+	}
+	mc.committed = append(mc.committed, blockAndPvtData)
+	return nil
+}
+
+func (mc *mockCommitter) LedgerHeight() (uint64, error) {
+	return uint64(len(mc.committed)), nil
+}
+
+func (mc *mockCommitter) GetBlocks(blockSeqs []uint64) []*common.Block {
+	return nil
+}
+
+func (mc *mockCommitter) Close() {
+}
+
+type mockTransientStore struct {
+	pvtData      map[string][]*transientstore.EndorserPvtSimulationResults
+	purgedTxIDs  []string
+	purgedHeight uint64
+}
+
+func newMockTransientStore() *mockTransientStore {
+	return &mockTransientStore{pvtData: make(map[string][]*transientstore.EndorserPvtSimulationResults)}
+}
+
+func (s *mockTransientStore) Persist(txid string, blockHeight uint64, privateSimulationResults *rwset.TxPvtReadWriteSet) error {
+	s.pvtData[txid] = append(s.pvtData[txid], &transientstore.EndorserPvtSimulationResults{
+		ReceivedAtBlockHeight: blockHeight,
+		PvtSimulationResults:  privateSimulationResults,
+	})
+	return nil
+}
+
+func (s *mockTransientStore) GetTxPvtRWSetByTxid(txid string) ([]*transientstore.EndorserPvtSimulationResults, error) {
+	return s.pvtData[txid], nil
+}
+
+func (s *mockTransientStore) PurgeByTxids(txids []string) error {
+	for _, txid := range txids {
+		delete(s.pvtData, txid)
+	}
+	s.purgedTxIDs = append(s.purgedTxIDs, txids...)
+	return nil
+}
+
+func (s *mockTransientStore) PurgeByHeight(maxBlockNumToRetain uint64) error {
+	s.purgedHeight = maxBlockNumToRetain
+	return nil
+}
+
+func (s *mockTransientStore) Shutdown() {
+}
+
+func pvtRWSet(ns string, collections ...string) *rwset.TxPvtReadWriteSet {
+	nsRWSet := &rwset.NsPvtReadWriteSet{Namespace: ns}
+	for _, coll := range collections {
+		nsRWSet.CollectionPvtRwset = append(nsRWSet.CollectionPvtRwset, &rwset.CollectionPvtReadWriteSet{
+			CollectionName: coll,
+			Rwset:          []byte(coll + "-rwset"),
+		})
+	}
+	return &rwset.TxPvtReadWriteSet{NsPvtRwset: []*rwset.NsPvtReadWriteSet{nsRWSet}}
+}
+
+func constructBlock(t *testing.T, blockNum uint64, numTx int) (*common.Block, []string) {
+	block := common.NewBlock(blockNum, []byte{})
+	var txIDs []string
+	for i := 0; i < numTx; i++ {
+		env, txID, err := testutil.ConstructTransaction(t, []byte("simRes"), false)
+		assert.NoError(t, err)
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(env))
+		txIDs = append(txIDs, txID)
+	}
+	return block, txIDs
+}
+
+func TestCoordinatorCommitWithPvtData(t *testing.T) {
+	committer := &mockCommitter{}
+	store := newMockTransientStore()
+	coordinator := NewCoordinator(committer, store)
+
+	block, txIDs := constructBlock(t, 1, 3)
+	// The private data of the second transaction is received from
+	// two endorsers, one of them sending only a single collection
+	store.Persist(txIDs[1], 1, pvtRWSet("mycc", "coll1", "coll2"))
+	store.Persist(txIDs[1], 1, pvtRWSet("mycc", "coll2"))
+	store.Persist("someOtherTxID", 1, pvtRWSet("mycc", "coll1"))
+
+	assert.NoError(t, coordinator.Commit(block))
+	assert.Len(t, committer.committed, 1)
+	committed := committer.committed[0]
+	assert.Equal(t, block, committed.Block)
+	assert.Len(t, committed.BlockPvtData, 1)
+	assert.Equal(t, uint64(1), committed.BlockPvtData[1].SeqInBlock)
+	assert.Equal(t, pvtRWSet("mycc", "coll1", "coll2"), committed.BlockPvtData[1].WriteSet)
+
+	// The private data of the committed transactions has been purged
+	assert.Equal(t, txIDs, store.purgedTxIDs)
+	assert.Contains(t, store.pvtData, "someOtherTxID")
+	assert.NotContains(t, store.pvtData, txIDs[1])
+}
+
+func TestCoordinatorPurgeByHeight(t *testing.T) {
+	committer := &mockCommitter{}
+	store := newMockTransientStore()
+	coordinator := NewCoordinator(committer, store).(*coordinator)
+	coordinator.transientBlockRetention = 10
+
+	block, _ := constructBlock(t, 15, 1)
+	assert.NoError(t, coordinator.Commit(block))
+	assert.Equal(t, uint64(0), store.purgedHeight)
+
+	block, _ = constructBlock(t, 20, 1)
+	assert.NoError(t, coordinator.Commit(block))
+	assert.Equal(t, uint64(10), store.purgedHeight)
+}
+
+func TestCoordinatorCommitFailure(t *testing.T) {
+	committer := &mockCommitter{err: errors.New("commit failed")}
+	store := newMockTransientStore()
+	coordinator := NewCoordinator(committer, store)
+
+	block, txIDs := constructBlock(t, 1, 1)
+	store.Persist(txIDs[0], 1, pvtRWSet("mycc", "coll1"))
+	assert.Error(t, coordinator.Commit(block))
+	// The private data is kept in the transient store if the commit failed
+	assert.Empty(t, store.purgedTxIDs)
+	assert.Contains(t, store.pvtData, txIDs[0])
+
+	assert.Error(t, coordinator.Commit(&common.Block{}))
+}
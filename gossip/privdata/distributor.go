@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/comm"
+	gossipCommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/gossip/util"
+	"github.com/hyperledger/fabric/protos/common"
+	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+)
+
+var logger = util.GetLogger(util.LoggingPrivModule, "")
+
+// PvtDataDistributor interface to defines API of distributing private data
+type PvtDataDistributor interface {
+	// Distribute broadcast reliably private data read write set based on policies
+	Distribute(txID string, privData *rwset.TxPvtReadWriteSet, blockHeight uint64) error
+}
+
+// gossipAdapter an adapter for API's required from gossip module
+type gossipAdapter interface {
+	// Send sends a message to remote peers
+	Send(msg *proto.GossipMessage, peers ...*comm.RemotePeer)
+
+	// PeersOfChannel returns the NetworkMembers considered alive
+	// and also subscribed to the channel given
+	PeersOfChannel(gossipCommon.ChainID) []discovery.NetworkMember
+}
+
+// OrgResolver returns the organization of the peer with the given PKI-ID,
+// or an empty organization if it isn't known
+type OrgResolver func(pkiID gossipCommon.PKIidType) api.OrgIdentityType
+
+// distributorImpl the implementation of the private data distributor interface
+type distributorImpl struct {
+	chainID     string
+	gossip      gossipAdapter
+	orgResolver OrgResolver
+	collStore   privdata.CollectionStore
+}
+
+// NewDistributor a constructor for private data distributor capable to send
+// private read write sets to the member peers of the underlying collections
+func NewDistributor(chainID string, gossip gossipAdapter, orgResolver OrgResolver, collStore privdata.CollectionStore) PvtDataDistributor {
+	return &distributorImpl{
+		chainID:     chainID,
+		gossip:      gossip,
+		orgResolver: orgResolver,
+		collStore:   collStore,
+	}
+}
+
+// Distribute broadcast reliably private data read write set based on policies
+func (d *distributorImpl) Distribute(txID string, privData *rwset.TxPvtReadWriteSet, blockHeight uint64) error {
+	for _, pvtRwset := range privData.NsPvtRwset {
+		namespace := pvtRwset.Namespace
+		for _, collection := range pvtRwset.CollectionPvtRwset {
+			collectionName := collection.CollectionName
+			cc := common.CollectionCriteria{
+				Channel:    d.chainID,
+				TxId:       txID,
+				Namespace:  namespace,
+				Collection: collectionName,
+			}
+			col, err := d.collStore.RetrieveCollection(cc)
+			if err != nil {
+				logger.Error("Could not find collection access policy for", cc, "error", err)
+				return fmt.Errorf("could not find collection [%s] of chaincode [%s]: %s", collectionName, namespace, err)
+			}
+
+			peers := d.eligiblePeers(col)
+			if len(peers) < col.RequiredPeerCount() {
+				return fmt.Errorf("required to disseminate collection [%s] of chaincode [%s] to at least %d peers, but only %d eligible peers found",
+					collectionName, namespace, col.RequiredPeerCount(), len(peers))
+			}
+			peers = selectPeers(peers, col.MaximumPeerCount())
+			if len(peers) == 0 {
+				logger.Debug("No remote peers to disseminate collection", collectionName, "of chaincode", namespace, "for txID", txID)
+				continue
+			}
+
+			msg := &proto.GossipMessage{
+				Channel: []byte(d.chainID),
+				Nonce:   util.RandomUInt64(),
+				Tag:     proto.GossipMessage_CHAN_ONLY,
+				Content: &proto.GossipMessage_PrivateData{
+					PrivateData: &proto.PrivateDataMessage{
+						Payload: &proto.PrivatePayload{
+							Namespace:        namespace,
+							CollectionName:   collectionName,
+							TxId:             txID,
+							PrivateRwset:     collection,
+							PrivateSimHeight: blockHeight,
+						},
+					},
+				},
+			}
+			logger.Debug("Disseminating collection", collectionName, "of chaincode", namespace, "for txID", txID, "to", len(peers), "peers")
+			d.gossip.Send(msg, peers...)
+		}
+	}
+	return nil
+}
+
+// eligiblePeers returns the peers of the channel that belong
+// to one of the member organizations of the given collection
+func (d *distributorImpl) eligiblePeers(col privdata.Collection) []*comm.RemotePeer {
+	memberOrgs := make(map[string]struct{})
+	for _, org := range col.MemberOrgs() {
+		memberOrgs[org] = struct{}{}
+	}
+
+	var peers []*comm.RemotePeer
+	for _, member := range d.gossip.PeersOfChannel(gossipCommon.ChainID(d.chainID)) {
+		org := d.orgResolver(member.PKIid)
+		if _, isMember := memberOrgs[string(org)]; !isMember {
+			continue
+		}
+		peers = append(peers, &comm.RemotePeer{Endpoint: member.PreferredEndpoint(), PKIID: member.PKIid})
+	}
+	return peers
+}
+
+// selectPeers randomly selects at most maxPeers out of the given peers
+func selectPeers(peers []*comm.RemotePeer, maxPeers int) []*comm.RemotePeer {
+	if len(peers) <= maxPeers {
+		return peers
+	}
+	var selected []*comm.RemotePeer
+	for _, i := range util.GetRandomIndices(maxPeers, len(peers)-1) {
+		selected = append(selected, peers[i])
+	}
+	return selected
+}
@@ -0,0 +1,163 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/comm"
+	gossipCommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/protos/common"
+	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/stretchr/testify/assert"
+)
+
+type sentMessage struct {
+	msg   *proto.GossipMessage
+	peers []*comm.RemotePeer
+}
+
+type gossipMock struct {
+	members []discovery.NetworkMember
+	sent    []sentMessage
+}
+
+func (g *gossipMock) Send(msg *proto.GossipMessage, peers ...*comm.RemotePeer) {
+	g.sent = append(g.sent, sentMessage{msg: msg, peers: peers})
+}
+
+func (g *gossipMock) PeersOfChannel(gossipCommon.ChainID) []discovery.NetworkMember {
+	return g.members
+}
+
+type collectionMock struct {
+	name     string
+	orgs     []string
+	required int
+	maximum  int
+}
+
+func (c *collectionMock) CollectionID() string {
+	return c.name
+}
+
+func (c *collectionMock) MemberOrgs() []string {
+	return c.orgs
+}
+
+func (c *collectionMock) RequiredPeerCount() int {
+	return c.required
+}
+
+func (c *collectionMock) MaximumPeerCount() int {
+	return c.maximum
+}
+
+type collectionStoreMock map[string]*collectionMock
+
+func (cs collectionStoreMock) RetrieveCollection(cc common.CollectionCriteria) (privdata.Collection, error) {
+	col, exists := cs[cc.Namespace+"~"+cc.Collection]
+	if !exists {
+		return nil, errors.New("collection not found")
+	}
+	return col, nil
+}
+
+func (cs collectionStoreMock) RetrieveCollectionConfigPackage(cc common.CollectionCriteria) (*common.CollectionConfigPackage, error) {
+	return nil, errors.New("not implemented")
+}
+
+// peers p1, p2 and p3 belong to Org1, peer p4 belongs to Org2
+var orgOfPeer = map[string]api.OrgIdentityType{
+	"p1": api.OrgIdentityType("Org1MSP"),
+	"p2": api.OrgIdentityType("Org1MSP"),
+	"p3": api.OrgIdentityType("Org1MSP"),
+	"p4": api.OrgIdentityType("Org2MSP"),
+}
+
+func orgResolverMock(pkiID gossipCommon.PKIidType) api.OrgIdentityType {
+	return orgOfPeer[string(pkiID)]
+}
+
+func newGossipMock() *gossipMock {
+	g := &gossipMock{}
+	for _, p := range []string{"p1", "p2", "p3", "p4"} {
+		g.members = append(g.members, discovery.NetworkMember{Endpoint: p + ":7051", PKIid: gossipCommon.PKIidType(p)})
+	}
+	return g
+}
+
+func TestDistributeToMemberOrgs(t *testing.T) {
+	g := newGossipMock()
+	cs := collectionStoreMock{
+		"mycc~coll1": {name: "coll1", orgs: []string{"Org1MSP"}, required: 1, maximum: 3},
+		"mycc~coll2": {name: "coll2", orgs: []string{"Org2MSP"}, required: 1, maximum: 3},
+	}
+	d := NewDistributor("testchainid", g, orgResolverMock, cs)
+
+	err := d.Distribute("tx1", pvtRWSet("mycc", "coll1", "coll2"), 5)
+	assert.NoError(t, err)
+	assert.Len(t, g.sent, 2)
+
+	coll1Msg := g.sent[0]
+	assert.Equal(t, proto.GossipMessage_CHAN_ONLY, coll1Msg.msg.Tag)
+	assert.Equal(t, []byte("testchainid"), coll1Msg.msg.Channel)
+	assert.True(t, coll1Msg.msg.IsPrivateDataMsg())
+	payload := coll1Msg.msg.GetPrivateData().Payload
+	assert.Equal(t, "tx1", payload.TxId)
+	assert.Equal(t, "mycc", payload.Namespace)
+	assert.Equal(t, "coll1", payload.CollectionName)
+	assert.Equal(t, uint64(5), payload.PrivateSimHeight)
+	assert.Equal(t, []byte("coll1-rwset"), payload.PrivateRwset.Rwset)
+	assert.Len(t, coll1Msg.peers, 3)
+	for _, p := range coll1Msg.peers {
+		assert.Equal(t, api.OrgIdentityType("Org1MSP"), orgOfPeer[string(p.PKIID)])
+	}
+
+	coll2Msg := g.sent[1]
+	assert.Equal(t, "coll2", coll2Msg.msg.GetPrivateData().Payload.CollectionName)
+	assert.Equal(t, []*comm.RemotePeer{{Endpoint: "p4:7051", PKIID: gossipCommon.PKIidType("p4")}}, coll2Msg.peers)
+}
+
+func TestDistributeMaximumPeerCount(t *testing.T) {
+	g := newGossipMock()
+	cs := collectionStoreMock{
+		"mycc~coll1": {name: "coll1", orgs: []string{"Org1MSP", "Org2MSP"}, required: 1, maximum: 2},
+		"mycc~coll2": {name: "coll2", orgs: []string{"Org1MSP"}, required: 0, maximum: 0},
+	}
+	d := NewDistributor("testchainid", g, orgResolverMock, cs)
+
+	assert.NoError(t, d.Distribute("tx1", pvtRWSet("mycc", "coll1"), 5))
+	assert.Len(t, g.sent, 1)
+	assert.Len(t, g.sent[0].peers, 2)
+
+	// No peer is allowed to receive the private data of coll2
+	assert.NoError(t, d.Distribute("tx2", pvtRWSet("mycc", "coll2"), 5))
+	assert.Len(t, g.sent, 1)
+}
+
+func TestDistributeFailures(t *testing.T) {
+	g := newGossipMock()
+	cs := collectionStoreMock{
+		"mycc~coll1": {name: "coll1", orgs: []string{"Org2MSP"}, required: 2, maximum: 3},
+	}
+	d := NewDistributor("testchainid", g, orgResolverMock, cs)
+
+	// Only a single peer of Org2 is available
+	err := d.Distribute("tx1", pvtRWSet("mycc", "coll1"), 5)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "at least 2 peers")
+
+	err = d.Distribute("tx1", pvtRWSet("mycc", "unknownColl"), 5)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not find collection")
+	assert.Empty(t, g.sent)
+}
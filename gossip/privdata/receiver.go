@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/hyperledger/fabric/core/transientstore"
+	"github.com/hyperledger/fabric/gossip/api"
+	gossipCommon "github.com/hyperledger/fabric/gossip/common"
+	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+)
+
+// receiverAdapter an adapter for API's required from gossip module
+// in order to receive the private data disseminated by other peers
+type receiverAdapter interface {
+	// Accept returns a dedicated read-only channel for messages sent by other nodes that match a certain predicate.
+	Accept(acceptor gossipCommon.MessageAcceptor, passThrough bool) (<-chan *proto.GossipMessage, <-chan proto.ReceivedMessage)
+}
+
+// PvtDataReceiver listens for the private data disseminated by the
+// endorsing peers of a channel and persists it into the transient store,
+// where it is kept until the corresponding block is committed
+type PvtDataReceiver interface {
+	// Stop stops listening for private data messages
+	Stop()
+}
+
+type receiverImpl struct {
+	chainID string
+	store   transientstore.Store
+	msgChan <-chan proto.ReceivedMessage
+	stopCh  chan struct{}
+	done    sync.WaitGroup
+	once    sync.Once
+}
+
+// NewPvtDataReceiver creates a receiver which persists the private data
+// that is sent to this peer for the given channel into the transient store
+func NewPvtDataReceiver(chainID string, g receiverAdapter, mcs api.MessageCryptoService, store transientstore.Store) PvtDataReceiver {
+	pvtDataMsgFilter := func(message interface{}) bool {
+		receivedMsg := message.(proto.ReceivedMessage)
+		msg := receivedMsg.GetGossipMessage()
+		if !msg.IsPrivateDataMsg() || !bytes.Equal(msg.Channel, []byte(chainID)) {
+			return false
+		}
+		// If we're not running with authentication, no point
+		// in enforcing access control
+		if !receivedMsg.GetConnectionInfo().IsAuthenticated() {
+			return true
+		}
+		connInfo := receivedMsg.GetConnectionInfo()
+		authErr := mcs.VerifyByChannel(msg.Channel, connInfo.Identity, connInfo.Auth.Signature, connInfo.Auth.SignedData)
+		if authErr != nil {
+			logger.Warning("Got unauthorized private data message from", string(connInfo.Identity))
+			return false
+		}
+		return true
+	}
+
+	_, msgChan := g.Accept(pvtDataMsgFilter, true)
+	r := &receiverImpl{
+		chainID: chainID,
+		store:   store,
+		msgChan: msgChan,
+		stopCh:  make(chan struct{}),
+	}
+	r.done.Add(1)
+	go r.listen()
+	return r
+}
+
+func (r *receiverImpl) listen() {
+	defer r.done.Done()
+	for {
+		select {
+		case msg := <-r.msgChan:
+			if msg == nil {
+				return
+			}
+			r.handlePvtDataMsg(msg.GetGossipMessage().GetPrivateData())
+		case <-r.stopCh:
+			logger.Debug("Stop listening for private data messages of channel", r.chainID)
+			return
+		}
+	}
+}
+
+func (r *receiverImpl) handlePvtDataMsg(msg *proto.PrivateDataMessage) {
+	payload := msg.GetPayload()
+	if payload == nil || payload.PrivateRwset == nil {
+		logger.Warning("Got private data message with an empty payload in channel", r.chainID)
+		return
+	}
+	if payload.TxId == "" || payload.Namespace == "" || payload.CollectionName == "" {
+		logger.Warning("Got private data message with missing txID, namespace or collection in channel", r.chainID)
+		return
+	}
+
+	pvtRWSet := &rwset.TxPvtReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+			{
+				Namespace:          payload.Namespace,
+				CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{payload.PrivateRwset},
+			},
+		},
+	}
+	if err := r.store.Persist(payload.TxId, payload.PrivateSimHeight, pvtRWSet); err != nil {
+		logger.Error("Failed persisting private data of txID", payload.TxId, "in channel", r.chainID, ":", err)
+		return
+	}
+	logger.Debug("Persisted private data of collection", payload.CollectionName, "for txID", payload.TxId)
+}
+
+// Stop stops listening for private data messages
+func (r *receiverImpl) Stop() {
+	r.once.Do(func() {
+		close(r.stopCh)
+		r.done.Wait()
+	})
+}
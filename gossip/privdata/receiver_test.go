@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"testing"
+
+	gossipCommon "github.com/hyperledger/fabric/gossip/common"
+	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/stretchr/testify/assert"
+)
+
+type receivedMsg struct {
+	msg *proto.SignedGossipMessage
+}
+
+func (m *receivedMsg) Respond(msg *proto.GossipMessage) {
+}
+
+func (m *receivedMsg) GetGossipMessage() *proto.SignedGossipMessage {
+	return m.msg
+}
+
+func (m *receivedMsg) GetSourceEnvelope() *proto.Envelope {
+	return nil
+}
+
+func (m *receivedMsg) GetConnectionInfo() *proto.ConnectionInfo {
+	return &proto.ConnectionInfo{ID: gossipCommon.PKIidType("p1")}
+}
+
+type receiverAdapterMock struct {
+	acceptor gossipCommon.MessageAcceptor
+	msgChan  chan proto.ReceivedMessage
+}
+
+func (g *receiverAdapterMock) Accept(acceptor gossipCommon.MessageAcceptor, passThrough bool) (<-chan *proto.GossipMessage, <-chan proto.ReceivedMessage) {
+	g.acceptor = acceptor
+	return nil, g.msgChan
+}
+
+func pvtDataMsg(channel string, payload *proto.PrivatePayload) proto.ReceivedMessage {
+	msg := &proto.GossipMessage{
+		Channel: []byte(channel),
+		Tag:     proto.GossipMessage_CHAN_ONLY,
+		Content: &proto.GossipMessage_PrivateData{
+			PrivateData: &proto.PrivateDataMessage{Payload: payload},
+		},
+	}
+	return &receivedMsg{msg: &proto.SignedGossipMessage{GossipMessage: msg}}
+}
+
+func TestPvtDataReceiver(t *testing.T) {
+	g := &receiverAdapterMock{msgChan: make(chan proto.ReceivedMessage)}
+	store := newMockTransientStore()
+	r := NewPvtDataReceiver("testchainid", g, nil, store)
+
+	collRWSet := &rwset.CollectionPvtReadWriteSet{CollectionName: "coll1", Rwset: []byte("rwset")}
+	msg := pvtDataMsg("testchainid", &proto.PrivatePayload{
+		Namespace:        "mycc",
+		CollectionName:   "coll1",
+		TxId:             "tx1",
+		PrivateRwset:     collRWSet,
+		PrivateSimHeight: 7,
+	})
+	assert.True(t, g.acceptor(msg))
+	assert.False(t, g.acceptor(pvtDataMsg("otherchainid", &proto.PrivatePayload{})))
+
+	g.msgChan <- msg
+	// A message without a txID is ignored
+	g.msgChan <- pvtDataMsg("testchainid", &proto.PrivatePayload{Namespace: "mycc", CollectionName: "coll1", PrivateRwset: collRWSet})
+	r.Stop()
+
+	assert.Len(t, store.pvtData, 1)
+	results := store.pvtData["tx1"]
+	assert.Len(t, results, 1)
+	assert.Equal(t, uint64(7), results[0].ReceivedAtBlockHeight)
+	assert.Equal(t, &rwset.TxPvtReadWriteSet{
+		NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+			{Namespace: "mycc", CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{collRWSet}},
+		},
+	}, results[0].PvtSimulationResults)
+}
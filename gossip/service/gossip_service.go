@@ -17,21 +17,26 @@ limitations under the License.
 package service
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/deliverservice"
 	"github.com/hyperledger/fabric/core/deliverservice/blocksprovider"
+	"github.com/hyperledger/fabric/core/transientstore"
 	"github.com/hyperledger/fabric/gossip/api"
 	gossipCommon "github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/election"
 	"github.com/hyperledger/fabric/gossip/gossip"
 	"github.com/hyperledger/fabric/gossip/identity"
 	"github.com/hyperledger/fabric/gossip/integration"
+	gossipPrivdata "github.com/hyperledger/fabric/gossip/privdata"
 	"github.com/hyperledger/fabric/gossip/state"
 	"github.com/hyperledger/fabric/gossip/util"
 	"github.com/hyperledger/fabric/protos/common"
 	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 )
@@ -50,11 +55,31 @@ type GossipService interface {
 	// NewConfigEventer creates a ConfigProcessor which the configtx.Manager can ultimately route config updates to
 	NewConfigEventer() ConfigProcessor
 	// InitializeChannel allocates the state provider and should be invoked once per channel per execution
-	InitializeChannel(chainID string, committer committer.Committer, endpoints []string)
+	InitializeChannel(chainID string, committer committer.Committer, endpoints []string, support Support)
 	// GetBlock returns block for given chain
 	GetBlock(chainID string, index uint64) *common.Block
 	// AddPayload appends message payload to for given chain
 	AddPayload(chainID string, payload *proto.Payload) error
+	// DistributePrivateData distributes private read write set inside the channel
+	// to the member peers of the collections the private data belongs to
+	DistributePrivateData(chainID string, txID string, privateData *rwset.TxPvtReadWriteSet) error
+}
+
+// Support aggregates the private data related resources of a channel
+// which are required in order to initialize it
+type Support struct {
+	// Store keeps the private data from endorsement time until commit time
+	Store transientstore.Store
+	// Cs retrieves the collections the private data of the channel belongs to
+	Cs privdata.CollectionStore
+}
+
+// privateHandler holds the private data related components of a channel
+type privateHandler struct {
+	committer   committer.Committer
+	store       transientstore.Store
+	distributor gossipPrivdata.PvtDataDistributor
+	receiver    gossipPrivdata.PvtDataReceiver
 }
 
 // DeliveryServiceFactory factory to create and initialize delivery service instance
@@ -80,6 +105,7 @@ func (*deliveryFactoryImpl) Service(g GossipService, endpoints []string, mcs api
 type gossipServiceImpl struct {
 	gossipSvc
 	chains          map[string]state.GossipStateProvider
+	privateHandlers map[string]privateHandler
 	leaderElection  map[string]election.LeaderElectionService
 	deliveryService deliverclient.DeliverService
 	deliveryFactory DeliveryServiceFactory
@@ -150,6 +176,7 @@ func InitGossipServiceCustomDeliveryFactory(peerIdentity []byte, endpoint string
 			mcs:             mcs,
 			gossipSvc:       gossip,
 			chains:          make(map[string]state.GossipStateProvider),
+			privateHandlers: make(map[string]privateHandler),
 			leaderElection:  make(map[string]election.LeaderElectionService),
 			deliveryFactory: factory,
 			idMapper:        idMapper,
@@ -170,12 +197,52 @@ func (g *gossipServiceImpl) NewConfigEventer() ConfigProcessor {
 	return newConfigEventer(g)
 }
 
+// DistributePrivateData distributes private read write set inside the channel
+// to the member peers of the collections the private data belongs to
+func (g *gossipServiceImpl) DistributePrivateData(chainID string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	g.lock.RLock()
+	handler, exists := g.privateHandlers[chainID]
+	g.lock.RUnlock()
+	if !exists {
+		return fmt.Errorf("no private data handler for %s", chainID)
+	}
+
+	height, err := handler.committer.LedgerHeight()
+	if err != nil {
+		return fmt.Errorf("failed obtaining ledger height of %s: %s", chainID, err)
+	}
+
+	// The endorsing peer keeps its own copy of the private data as well
+	if err := handler.store.Persist(txID, height, privateData); err != nil {
+		logger.Error("Failed persisting private data of txID", txID, "in channel", chainID, ":", err)
+		return err
+	}
+
+	if err := handler.distributor.Distribute(txID, privateData, height); err != nil {
+		logger.Error("Failed to distribute private collection, txID", txID, "channel", chainID, "due to", err)
+		return err
+	}
+	return nil
+}
+
 // InitializeChannel allocates the state provider and should be invoked once per channel per execution
-func (g *gossipServiceImpl) InitializeChannel(chainID string, committer committer.Committer, endpoints []string) {
+func (g *gossipServiceImpl) InitializeChannel(chainID string, committer committer.Committer, endpoints []string, support Support) {
 	g.lock.Lock()
 	defer g.lock.Unlock()
 	// Initialize new state provider for given committer
 	logger.Debug("Creating state provider for chainID", chainID)
+	if support.Store != nil && support.Cs != nil {
+		g.privateHandlers[chainID] = privateHandler{
+			committer:   committer,
+			store:       support.Store,
+			distributor: gossipPrivdata.NewDistributor(chainID, g, g.orgOfPeer, support.Cs),
+			receiver:    gossipPrivdata.NewPvtDataReceiver(chainID, g, g.mcs, support.Store),
+		}
+		// Blocks are committed along with the private data of their transactions
+		committer = gossipPrivdata.NewCoordinator(committer, support.Store)
+	} else {
+		logger.Warning("No transient store or collection store supplied, private data is disabled for channel", chainID)
+	}
 	g.chains[chainID] = state.NewGossipStateProvider(chainID, g, committer, g.mcs)
 	if g.deliveryService == nil {
 		var err error
@@ -264,6 +331,11 @@ func (g *gossipServiceImpl) Stop() {
 		ch.Stop()
 	}
 
+	for chainID, handler := range g.privateHandlers {
+		logger.Infof("Stopping private data receiver for %s", chainID)
+		handler.receiver.Stop()
+	}
+
 	for chainID, electionService := range g.leaderElection {
 		logger.Infof("Stopping leader election for %s", chainID)
 		electionService.Stop()
@@ -280,6 +352,16 @@ func (g *gossipServiceImpl) newLeaderElectionComponent(chainID string, callback
 	return election.NewLeaderElectionService(adapter, string(PKIid), callback)
 }
 
+// orgOfPeer returns the organization of the peer with the given PKI-ID
+func (g *gossipServiceImpl) orgOfPeer(pkiID gossipCommon.PKIidType) api.OrgIdentityType {
+	identity, err := g.idMapper.Get(pkiID)
+	if err != nil {
+		logger.Debug("Unable to find identity of peer", pkiID, ":", err)
+		return nil
+	}
+	return g.secAdv.OrgByPeerIdentity(identity)
+}
+
 func (g *gossipServiceImpl) amIinChannel(myOrg string, config Config) bool {
 	for _, orgName := range orgListFromConfig(config) {
 		if orgName == myOrg {
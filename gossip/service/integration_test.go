@@ -101,7 +101,7 @@ func TestLeaderYield(t *testing.T) {
 			secAdv:          &secAdvMock{},
 		}
 		gossipServiceInstance = gs
-		gs.InitializeChannel(channelName, &mockLedgerInfo{1}, []string{"localhost:7050"})
+		gs.InitializeChannel(channelName, &mockLedgerInfo{1}, []string{"localhost:7050"}, Support{})
 		return gs
 	}
 
@@ -30,6 +30,7 @@ import (
 	"github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/mocks/validator"
 	"github.com/hyperledger/fabric/gossip/api"
@@ -182,6 +183,10 @@ func (mc *mockCommitter) Commit(block *pcomm.Block) error {
 	return nil
 }
 
+func (mc *mockCommitter) CommitWithPvtData(blockAndPvtData *ledger.BlockAndPvtData) error {
+	return mc.Commit(blockAndPvtData.Block)
+}
+
 func (mc *mockCommitter) LedgerHeight() (uint64, error) {
 	mc.Lock()
 	m := mc.Mock
@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// NewCredential issues a new credential, which is the last step of the interactive
+// issuance protocol. All attribute values are added by the issuer at this step and
+// then signed together with a commitment to the user's secret key from a credential
+// request. The user must complete the credential with the blinding value of its
+// request before using it
+func NewCredential(key *IssuerKey, m *CredRequest, attrs []*big.Int, rng io.Reader) (*Credential, error) {
+	if err := m.Check(key.Ipk); err != nil {
+		return nil, err
+	}
+	if len(attrs) != len(key.Ipk.AttributeNames) {
+		return nil, fmt.Errorf("incorrect number of attribute values passed, expected %d, got %d", len(key.Ipk.AttributeNames), len(attrs))
+	}
+	for i, attr := range attrs {
+		if attr.Sign() < 0 || !inRange(attr, lm) {
+			return nil, fmt.Errorf("the value of attribute %s is out of range", key.Ipk.AttributeNames[i])
+		}
+	}
+
+	pk, err := key.Ipk.values()
+	if err != nil {
+		return nil, err
+	}
+	xs, err := bigsFromBytes(key.P, key.Q, m.U)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issuer key: %s", err)
+	}
+	p, q, u := xs[0], xs[1], xs[2]
+
+	// pick a random prime e in [2^(le-1), 2^(le-1) + 2^(lePrime-1)]
+	var e *big.Int
+	for e == nil || !e.ProbablyPrime(20) {
+		offset, err := randBits(rng, lePrime-1)
+		if err != nil {
+			return nil, err
+		}
+		e = new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), le-1), offset)
+	}
+
+	// v'' = 2^(lv-1) + a random value of lv-1 bits
+	vHat, err := randBits(rng, lv-1)
+	if err != nil {
+		return nil, err
+	}
+	vPrimePrime := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), lv-1), vHat)
+
+	// A = (Z / (U * S^v'' * prod(R_i^m_i)))^(1/e) mod n
+	bases := append([]*big.Int{u, pk.s}, pk.r...)
+	exps := append([]*big.Int{big.NewInt(1), vPrimePrime}, attrs...)
+	denominator := new(big.Int).ModInverse(multiExpMod(pk.n, bases, exps), pk.n)
+	if denominator == nil {
+		return nil, errors.New("invalid credential request: U is not invertible")
+	}
+	order := new(big.Int).Mul(new(big.Int).Rsh(p, 1), new(big.Int).Rsh(q, 1))
+	d := new(big.Int).ModInverse(e, order)
+	if d == nil {
+		return nil, errors.New("failed to invert e")
+	}
+	a := new(big.Int).Mul(pk.z, denominator)
+	a.Exp(a.Mod(a, pk.n), d, pk.n)
+
+	credAttrs := make([][]byte, len(attrs))
+	for i, attr := range attrs {
+		credAttrs[i] = BigToBytes(attr)
+	}
+	return &Credential{A: BigToBytes(a), E: BigToBytes(e), V: BigToBytes(vPrimePrime), Attrs: credAttrs}, nil
+}
+
+// Complete completes a credential returned by the issuer with the blinding value
+// of the credential request of the user
+func (cred *Credential) Complete(vPrime *big.Int) error {
+	v, err := BigFromBytes(cred.V)
+	if err != nil {
+		return fmt.Errorf("invalid credential: %s", err)
+	}
+	cred.V = BigToBytes(v.Add(v, vPrime))
+	return nil
+}
+
+// Ver cryptographically verifies the credential by verifying the CL signature
+// on the attribute values and the user's secret key
+func (cred *Credential) Ver(sk *big.Int, ipk *IssuerPublicKey) error {
+	pk, err := ipk.values()
+	if err != nil {
+		return err
+	}
+	if len(cred.Attrs) != len(ipk.AttributeNames) {
+		return fmt.Errorf("credential has %d attributes, expected %d", len(cred.Attrs), len(ipk.AttributeNames))
+	}
+	values, err := bigsFromBytes(append([][]byte{cred.A, cred.E, cred.V}, cred.Attrs...)...)
+	if err != nil {
+		return fmt.Errorf("invalid credential: %s", err)
+	}
+	a, e, v, attrs := values[0], values[1], values[2], values[3:]
+
+	lowerBound := new(big.Int).Lsh(big.NewInt(1), le-1)
+	upperBound := new(big.Int).Add(lowerBound, new(big.Int).Lsh(big.NewInt(1), lePrime-1))
+	if e.Cmp(lowerBound) < 0 || e.Cmp(upperBound) > 0 || !e.ProbablyPrime(20) {
+		return errors.New("credential has an invalid e value")
+	}
+
+	// Z = A^e * S^v * R_sk^sk * prod(R_i^m_i)
+	bases := append([]*big.Int{a, pk.s, pk.rSk}, pk.r...)
+	exps := append([]*big.Int{e, v, sk}, attrs...)
+	if multiExpMod(pk.n, bases, exps).Cmp(pk.z) != 0 {
+		return errors.New("credential is not cryptographically valid")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// credRequestLabel is the domain separation label of the proofs of the credential requests
+const credRequestLabel = "credRequest"
+
+// NewRandSk returns a random secret key for a user
+func NewRandSk(rng io.Reader) (*big.Int, error) {
+	return randBits(rng, lm)
+}
+
+// NewCredRequest creates a new Credential Request, the first message of the
+// interactive credential issuance protocol (from user to issuer). The user commits
+// to its secret key with the blinding value vPrime, which it must keep to complete
+// the credential returned by the issuer
+func NewCredRequest(sk *big.Int, issuerNonce []byte, ipk *IssuerPublicKey, rng io.Reader) (*CredRequest, *big.Int, error) {
+	pk, err := ipk.values()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vPrime, err := randBits(rng, ln+lStat)
+	if err != nil {
+		return nil, nil, err
+	}
+	// U = S^v' * R_sk^sk
+	u := multiExpMod(pk.n, []*big.Int{pk.s, pk.rSk}, []*big.Int{vPrime, sk})
+
+	// prove the knowledge of sk and v'
+	rSk, err := randBits(rng, lm+lStat+lH)
+	if err != nil {
+		return nil, nil, err
+	}
+	rV, err := randBits(rng, ln+2*lStat+lH)
+	if err != nil {
+		return nil, nil, err
+	}
+	t := multiExpMod(pk.n, []*big.Int{pk.s, pk.rSk}, []*big.Int{rV, rSk})
+	c := challenge([]byte(credRequestLabel), ipk.Hash, BigToBytes(u), BigToBytes(t), issuerNonce)
+
+	return &CredRequest{
+		U:           BigToBytes(u),
+		IssuerNonce: issuerNonce,
+		ProofC:      BigToBytes(c),
+		ProofSSk:    BigToBytes(new(big.Int).Add(rSk, new(big.Int).Mul(c, sk))),
+		ProofSV:     BigToBytes(new(big.Int).Add(rV, new(big.Int).Mul(c, vPrime))),
+	}, vPrime, nil
+}
+
+// Check checks that the credential request proves the knowledge of the
+// committed secret key
+func (m *CredRequest) Check(ipk *IssuerPublicKey) error {
+	pk, err := ipk.values()
+	if err != nil {
+		return err
+	}
+	xs, err := bigsFromBytes(m.U, m.ProofC, m.ProofSSk, m.ProofSV)
+	if err != nil {
+		return fmt.Errorf("invalid credential request: %s", err)
+	}
+	u, c, sSk, sV := xs[0], xs[1], xs[2], xs[3]
+
+	if u.Sign() <= 0 || u.Cmp(pk.n) >= 0 {
+		return errors.New("invalid credential request: U is not an element of Z_n")
+	}
+	if !inRange(sSk, lm+lStat+lH+1) {
+		return errors.New("invalid credential request: the secret key is out of range")
+	}
+
+	// T = U^-c * S^s_v * R_sk^s_sk
+	t := multiExpMod(pk.n, []*big.Int{u, pk.s, pk.rSk}, []*big.Int{new(big.Int).Neg(c), sV, sSk})
+	if challenge([]byte(credRequestLabel), ipk.Hash, m.U, BigToBytes(t), m.IssuerNonce).Cmp(c) != 0 {
+		return errors.New("zero knowledge proof is invalid")
+	}
+	return nil
+}
@@ -0,0 +1,399 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: idemix.proto
+
+/*
+Package idemix is a generated protocol buffer package.
+
+It is generated from these files:
+	idemix.proto
+
+It has these top-level messages:
+	IssuerPublicKey
+	IssuerKey
+	Credential
+	CredRequest
+	Signature
+	NymSignature
+*/
+package idemix
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// IssuerPublicKey specifies a public key of a CL signature scheme over
+// a special RSA modulus n, as used by the issuer of the credentials
+type IssuerPublicKey struct {
+	// attribute_names are the names of the attributes of the credentials
+	AttributeNames []string `protobuf:"bytes,1,rep,name=attribute_names,json=attributeNames" json:"attribute_names,omitempty"`
+	// n is the special RSA modulus of the issuer
+	N []byte `protobuf:"bytes,2,opt,name=n,proto3" json:"n,omitempty"`
+	// s is a generator of the quadratic residues modulo n
+	S []byte `protobuf:"bytes,3,opt,name=s,proto3" json:"s,omitempty"`
+	// z and the r_sk, r bases are powers of s
+	Z   []byte   `protobuf:"bytes,4,opt,name=z,proto3" json:"z,omitempty"`
+	RSk []byte   `protobuf:"bytes,5,opt,name=r_sk,json=rSk,proto3" json:"r_sk,omitempty"`
+	R   [][]byte `protobuf:"bytes,6,rep,name=r,proto3" json:"r,omitempty"`
+	// hash is the hash of the public key, binding the proofs to it
+	Hash []byte `protobuf:"bytes,7,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *IssuerPublicKey) Reset()                    { *m = IssuerPublicKey{} }
+func (m *IssuerPublicKey) String() string            { return proto.CompactTextString(m) }
+func (*IssuerPublicKey) ProtoMessage()               {}
+func (*IssuerPublicKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *IssuerPublicKey) GetAttributeNames() []string {
+	if m != nil {
+		return m.AttributeNames
+	}
+	return nil
+}
+
+func (m *IssuerPublicKey) GetN() []byte {
+	if m != nil {
+		return m.N
+	}
+	return nil
+}
+
+func (m *IssuerPublicKey) GetS() []byte {
+	if m != nil {
+		return m.S
+	}
+	return nil
+}
+
+func (m *IssuerPublicKey) GetZ() []byte {
+	if m != nil {
+		return m.Z
+	}
+	return nil
+}
+
+func (m *IssuerPublicKey) GetRSk() []byte {
+	if m != nil {
+		return m.RSk
+	}
+	return nil
+}
+
+func (m *IssuerPublicKey) GetR() [][]byte {
+	if m != nil {
+		return m.R
+	}
+	return nil
+}
+
+func (m *IssuerPublicKey) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+// IssuerKey specifies an issuer key pair
+type IssuerKey struct {
+	// p and q are the safe primes of the modulus of the public key
+	P   []byte           `protobuf:"bytes,1,opt,name=p,proto3" json:"p,omitempty"`
+	Q   []byte           `protobuf:"bytes,2,opt,name=q,proto3" json:"q,omitempty"`
+	Ipk *IssuerPublicKey `protobuf:"bytes,3,opt,name=ipk" json:"ipk,omitempty"`
+}
+
+func (m *IssuerKey) Reset()                    { *m = IssuerKey{} }
+func (m *IssuerKey) String() string            { return proto.CompactTextString(m) }
+func (*IssuerKey) ProtoMessage()               {}
+func (*IssuerKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *IssuerKey) GetP() []byte {
+	if m != nil {
+		return m.P
+	}
+	return nil
+}
+
+func (m *IssuerKey) GetQ() []byte {
+	if m != nil {
+		return m.Q
+	}
+	return nil
+}
+
+func (m *IssuerKey) GetIpk() *IssuerPublicKey {
+	if m != nil {
+		return m.Ipk
+	}
+	return nil
+}
+
+// Credential specifies a credential object, that is a CL signature
+// (a, e, v) on the secret key of the user and on the attribute values
+type Credential struct {
+	A     []byte   `protobuf:"bytes,1,opt,name=a,proto3" json:"a,omitempty"`
+	E     []byte   `protobuf:"bytes,2,opt,name=e,proto3" json:"e,omitempty"`
+	V     []byte   `protobuf:"bytes,3,opt,name=v,proto3" json:"v,omitempty"`
+	Attrs [][]byte `protobuf:"bytes,4,rep,name=attrs,proto3" json:"attrs,omitempty"`
+}
+
+func (m *Credential) Reset()                    { *m = Credential{} }
+func (m *Credential) String() string            { return proto.CompactTextString(m) }
+func (*Credential) ProtoMessage()               {}
+func (*Credential) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *Credential) GetA() []byte {
+	if m != nil {
+		return m.A
+	}
+	return nil
+}
+
+func (m *Credential) GetE() []byte {
+	if m != nil {
+		return m.E
+	}
+	return nil
+}
+
+func (m *Credential) GetV() []byte {
+	if m != nil {
+		return m.V
+	}
+	return nil
+}
+
+func (m *Credential) GetAttrs() [][]byte {
+	if m != nil {
+		return m.Attrs
+	}
+	return nil
+}
+
+// CredRequest specifies a credential request object, committing to the
+// secret key of the user and proving the knowledge of it
+type CredRequest struct {
+	U           []byte `protobuf:"bytes,1,opt,name=u,proto3" json:"u,omitempty"`
+	IssuerNonce []byte `protobuf:"bytes,2,opt,name=issuer_nonce,json=issuerNonce,proto3" json:"issuer_nonce,omitempty"`
+	ProofC      []byte `protobuf:"bytes,3,opt,name=proof_c,json=proofC,proto3" json:"proof_c,omitempty"`
+	ProofSSk    []byte `protobuf:"bytes,4,opt,name=proof_s_sk,json=proofSSk,proto3" json:"proof_s_sk,omitempty"`
+	ProofSV     []byte `protobuf:"bytes,5,opt,name=proof_s_v,json=proofSV,proto3" json:"proof_s_v,omitempty"`
+}
+
+func (m *CredRequest) Reset()                    { *m = CredRequest{} }
+func (m *CredRequest) String() string            { return proto.CompactTextString(m) }
+func (*CredRequest) ProtoMessage()               {}
+func (*CredRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *CredRequest) GetU() []byte {
+	if m != nil {
+		return m.U
+	}
+	return nil
+}
+
+func (m *CredRequest) GetIssuerNonce() []byte {
+	if m != nil {
+		return m.IssuerNonce
+	}
+	return nil
+}
+
+func (m *CredRequest) GetProofC() []byte {
+	if m != nil {
+		return m.ProofC
+	}
+	return nil
+}
+
+func (m *CredRequest) GetProofSSk() []byte {
+	if m != nil {
+		return m.ProofSSk
+	}
+	return nil
+}
+
+func (m *CredRequest) GetProofSV() []byte {
+	if m != nil {
+		return m.ProofSV
+	}
+	return nil
+}
+
+// Signature specifies a signature object proving the possession of a
+// credential bound to a pseudonym, disclosing some of its attributes
+type Signature struct {
+	APrime      []byte   `protobuf:"bytes,1,opt,name=a_prime,json=aPrime,proto3" json:"a_prime,omitempty"`
+	Nym         []byte   `protobuf:"bytes,2,opt,name=nym,proto3" json:"nym,omitempty"`
+	ProofC      []byte   `protobuf:"bytes,3,opt,name=proof_c,json=proofC,proto3" json:"proof_c,omitempty"`
+	ProofSE     []byte   `protobuf:"bytes,4,opt,name=proof_s_e,json=proofSE,proto3" json:"proof_s_e,omitempty"`
+	ProofSV     []byte   `protobuf:"bytes,5,opt,name=proof_s_v,json=proofSV,proto3" json:"proof_s_v,omitempty"`
+	ProofSSk    []byte   `protobuf:"bytes,6,opt,name=proof_s_sk,json=proofSSk,proto3" json:"proof_s_sk,omitempty"`
+	ProofSRNym  []byte   `protobuf:"bytes,7,opt,name=proof_s_r_nym,json=proofSRNym,proto3" json:"proof_s_r_nym,omitempty"`
+	ProofSAttrs [][]byte `protobuf:"bytes,8,rep,name=proof_s_attrs,json=proofSAttrs,proto3" json:"proof_s_attrs,omitempty"`
+	Nonce       []byte   `protobuf:"bytes,9,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (m *Signature) Reset()                    { *m = Signature{} }
+func (m *Signature) String() string            { return proto.CompactTextString(m) }
+func (*Signature) ProtoMessage()               {}
+func (*Signature) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *Signature) GetAPrime() []byte {
+	if m != nil {
+		return m.APrime
+	}
+	return nil
+}
+
+func (m *Signature) GetNym() []byte {
+	if m != nil {
+		return m.Nym
+	}
+	return nil
+}
+
+func (m *Signature) GetProofC() []byte {
+	if m != nil {
+		return m.ProofC
+	}
+	return nil
+}
+
+func (m *Signature) GetProofSE() []byte {
+	if m != nil {
+		return m.ProofSE
+	}
+	return nil
+}
+
+func (m *Signature) GetProofSV() []byte {
+	if m != nil {
+		return m.ProofSV
+	}
+	return nil
+}
+
+func (m *Signature) GetProofSSk() []byte {
+	if m != nil {
+		return m.ProofSSk
+	}
+	return nil
+}
+
+func (m *Signature) GetProofSRNym() []byte {
+	if m != nil {
+		return m.ProofSRNym
+	}
+	return nil
+}
+
+func (m *Signature) GetProofSAttrs() [][]byte {
+	if m != nil {
+		return m.ProofSAttrs
+	}
+	return nil
+}
+
+func (m *Signature) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+// NymSignature specifies a signature object signing a message with
+// respect to a pseudonym, without disclosing the credential
+type NymSignature struct {
+	ProofC     []byte `protobuf:"bytes,1,opt,name=proof_c,json=proofC,proto3" json:"proof_c,omitempty"`
+	ProofSSk   []byte `protobuf:"bytes,2,opt,name=proof_s_sk,json=proofSSk,proto3" json:"proof_s_sk,omitempty"`
+	ProofSRNym []byte `protobuf:"bytes,3,opt,name=proof_s_r_nym,json=proofSRNym,proto3" json:"proof_s_r_nym,omitempty"`
+	Nonce      []byte `protobuf:"bytes,4,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (m *NymSignature) Reset()                    { *m = NymSignature{} }
+func (m *NymSignature) String() string            { return proto.CompactTextString(m) }
+func (*NymSignature) ProtoMessage()               {}
+func (*NymSignature) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *NymSignature) GetProofC() []byte {
+	if m != nil {
+		return m.ProofC
+	}
+	return nil
+}
+
+func (m *NymSignature) GetProofSSk() []byte {
+	if m != nil {
+		return m.ProofSSk
+	}
+	return nil
+}
+
+func (m *NymSignature) GetProofSRNym() []byte {
+	if m != nil {
+		return m.ProofSRNym
+	}
+	return nil
+}
+
+func (m *NymSignature) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*IssuerPublicKey)(nil), "idemix.IssuerPublicKey")
+	proto.RegisterType((*IssuerKey)(nil), "idemix.IssuerKey")
+	proto.RegisterType((*Credential)(nil), "idemix.Credential")
+	proto.RegisterType((*CredRequest)(nil), "idemix.CredRequest")
+	proto.RegisterType((*Signature)(nil), "idemix.Signature")
+	proto.RegisterType((*NymSignature)(nil), "idemix.NymSignature")
+}
+
+func init() { proto.RegisterFile("idemix.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 469 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x53, 0x4d, 0x6f, 0xd4, 0x30,
+	0x10, 0x95, 0x37, 0x69, 0xda, 0xcc, 0x06, 0x0a, 0x16, 0x52, 0x2d, 0xc4, 0x61, 0x1b, 0x21, 0x28,
+	0x97, 0x5d, 0x09, 0x7e, 0x01, 0x54, 0x1c, 0x10, 0x68, 0x55, 0x25, 0x12, 0x07, 0x2e, 0x91, 0x93,
+	0x75, 0x77, 0xad, 0x6c, 0x3e, 0xd6, 0x4e, 0x56, 0xa4, 0x07, 0x0e, 0xfc, 0x03, 0x8e, 0xfc, 0x5b,
+	0x34, 0x76, 0x5c, 0xca, 0x22, 0xca, 0xcd, 0x6f, 0x66, 0xfc, 0xde, 0x9b, 0xe7, 0x04, 0x22, 0xb9,
+	0x12, 0x95, 0xfc, 0x3a, 0x6f, 0x55, 0xd3, 0x35, 0x34, 0xb0, 0x28, 0xfe, 0x49, 0xe0, 0xf4, 0x83,
+	0xd6, 0xbd, 0x50, 0x57, 0x7d, 0xbe, 0x95, 0xc5, 0x47, 0x31, 0xd0, 0x97, 0x70, 0xca, 0xbb, 0x4e,
+	0xc9, 0xbc, 0xef, 0x44, 0x56, 0xf3, 0x4a, 0x68, 0x46, 0x66, 0xde, 0x45, 0x98, 0x3c, 0xbc, 0x2d,
+	0x2f, 0xb1, 0x4a, 0x23, 0x20, 0x35, 0x9b, 0xcc, 0xc8, 0x45, 0x94, 0x90, 0x1a, 0x91, 0x66, 0x9e,
+	0x45, 0xa6, 0x77, 0xc3, 0x7c, 0x8b, 0x6e, 0xe8, 0x63, 0xf0, 0x55, 0xa6, 0x4b, 0x76, 0x64, 0x0a,
+	0x9e, 0x4a, 0x4b, 0x1c, 0x50, 0x2c, 0x98, 0x79, 0x38, 0xa0, 0x28, 0x05, 0x7f, 0xc3, 0xf5, 0x86,
+	0x1d, 0x9b, 0x01, 0x73, 0x8e, 0x13, 0x08, 0xad, 0x35, 0x34, 0x15, 0x01, 0x69, 0x19, 0xb1, 0x7c,
+	0x2d, 0xa2, 0x9d, 0x53, 0xde, 0xd1, 0x57, 0xe0, 0xc9, 0xb6, 0x34, 0xda, 0xd3, 0xd7, 0x67, 0xf3,
+	0x71, 0xd1, 0x83, 0xb5, 0x12, 0x9c, 0x89, 0x3f, 0x01, 0x5c, 0x2a, 0xb1, 0x12, 0x75, 0x27, 0xf9,
+	0x16, 0x69, 0xb8, 0x23, 0xe5, 0x88, 0x84, 0x23, 0x15, 0x88, 0xf6, 0x6e, 0x9d, 0x3d, 0x7d, 0x02,
+	0x47, 0xb8, 0xbc, 0x66, 0xbe, 0x71, 0x6c, 0x41, 0xfc, 0x83, 0xc0, 0x14, 0xe9, 0x12, 0xb1, 0xeb,
+	0x85, 0xee, 0xf0, 0x4e, 0xef, 0xf8, 0x7a, 0x7a, 0x0e, 0x91, 0x34, 0x1e, 0xb2, 0xba, 0xa9, 0x0b,
+	0x47, 0x3d, 0xb5, 0xb5, 0x25, 0x96, 0xe8, 0x19, 0x1c, 0xb7, 0xaa, 0x69, 0xae, 0xb3, 0x62, 0x94,
+	0x0a, 0x0c, 0xbc, 0xa4, 0xcf, 0x00, 0x6c, 0x43, 0x63, 0x6c, 0x36, 0xc7, 0x13, 0x53, 0x49, 0xd3,
+	0x92, 0x3e, 0x85, 0xd0, 0x75, 0xf7, 0x63, 0xa6, 0x96, 0x27, 0xfd, 0x1c, 0x7f, 0x9f, 0x40, 0x98,
+	0xca, 0x75, 0xcd, 0xbb, 0x5e, 0x19, 0x01, 0x9e, 0xb5, 0x4a, 0x56, 0x62, 0xf4, 0x15, 0xf0, 0x2b,
+	0x44, 0xf4, 0x11, 0x78, 0xf5, 0x50, 0x8d, 0x9e, 0xf0, 0xf8, 0x6f, 0x2f, 0x77, 0xd4, 0xc4, 0x68,
+	0x65, 0x54, 0x7b, 0x7f, 0x9f, 0x93, 0x83, 0x1d, 0x82, 0x83, 0x1d, 0xce, 0xe1, 0x81, 0xeb, 0xaa,
+	0x0c, 0xad, 0xd8, 0xa7, 0xb7, 0x57, 0xd2, 0x64, 0x39, 0x54, 0x34, 0xfe, 0x3d, 0x62, 0xc3, 0x3f,
+	0x31, 0xe1, 0x4f, 0xed, 0xc8, 0x5b, 0x2c, 0xe1, 0xc3, 0xd8, 0x74, 0x43, 0x73, 0xdd, 0x82, 0xf8,
+	0x1b, 0x44, 0xcb, 0xa1, 0xfa, 0x23, 0x06, 0xb7, 0x1b, 0xb9, 0x27, 0xe7, 0xc9, 0xff, 0x3c, 0x7a,
+	0x7f, 0x79, 0xbc, 0xd5, 0xf7, 0xef, 0xe8, 0xbf, 0x7b, 0xf1, 0xe5, 0xf9, 0x5a, 0x76, 0x9b, 0x3e,
+	0x9f, 0x17, 0x4d, 0xb5, 0xd8, 0x0c, 0xad, 0x50, 0x5b, 0xb1, 0x5a, 0x0b, 0xb5, 0xb8, 0xe6, 0xb9,
+	0x92, 0xc5, 0xc2, 0x7e, 0xa3, 0x79, 0x60, 0xfe, 0xc6, 0x37, 0xbf, 0x06, 0x00, 0x5b, 0xb8, 0xfe,
+	0x73, 0x9d, 0x03, 0x00, 0x00,
+}
@@ -0,0 +1,178 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"crypto/rand"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+// testdata/IssuerSecretKey is a pregenerated key with the attributes
+// OU, Role and EnrollmentID, as generating safe primes takes several seconds
+func loadIssuerKey(t *testing.T) *IssuerKey {
+	raw, err := ioutil.ReadFile("testdata/IssuerSecretKey")
+	assert.NoError(t, err)
+	key := &IssuerKey{}
+	assert.NoError(t, proto.Unmarshal(raw, key))
+	assert.NoError(t, key.Ipk.Check())
+	return key
+}
+
+func issueCredential(t *testing.T, key *IssuerKey, attrs []*big.Int) (*Credential, *big.Int) {
+	sk, err := NewRandSk(rand.Reader)
+	assert.NoError(t, err)
+
+	credReq, vPrime, err := NewCredRequest(sk, []byte("nonce"), key.Ipk, rand.Reader)
+	assert.NoError(t, err)
+	cred, err := NewCredential(key, credReq, attrs, rand.Reader)
+	assert.NoError(t, err)
+	assert.NoError(t, cred.Complete(vPrime))
+	return cred, sk
+}
+
+func TestBigEncoding(t *testing.T) {
+	for _, x := range []*big.Int{big.NewInt(0), big.NewInt(42), big.NewInt(-42), new(big.Int).Lsh(big.NewInt(-1), 300)} {
+		y, err := BigFromBytes(BigToBytes(x))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, x.Cmp(y))
+	}
+
+	_, err := BigFromBytes(nil)
+	assert.Error(t, err)
+	_, err = BigFromBytes([]byte{2, 1})
+	assert.Error(t, err)
+}
+
+func TestIssuerKey(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping issuer key generation in short mode")
+	}
+
+	_, err := NewIssuerKey([]string{"Attr1", "Attr1"}, rand.Reader)
+	assert.Error(t, err)
+
+	key, err := NewIssuerKey([]string{"Attr1", "Attr2"}, rand.Reader)
+	assert.NoError(t, err)
+	assert.NoError(t, key.Ipk.Check())
+
+	cred, sk := issueCredential(t, key, []*big.Int{big.NewInt(1), big.NewInt(2)})
+	assert.NoError(t, cred.Ver(sk, key.Ipk))
+}
+
+func TestIssuerPublicKeyCheck(t *testing.T) {
+	key := loadIssuerKey(t)
+
+	ipk := *key.Ipk
+	ipk.AttributeNames = []string{"OU", "Role"}
+	assert.Error(t, ipk.Check())
+
+	ipk = *key.Ipk
+	ipk.Hash = []byte("wrong hash")
+	assert.Error(t, ipk.Check())
+}
+
+func TestCredential(t *testing.T) {
+	key := loadIssuerKey(t)
+	attrs := []*big.Int{HashAttribute([]byte("OU1")), big.NewInt(0), HashAttribute([]byte("User1"))}
+
+	cred, sk := issueCredential(t, key, attrs)
+	assert.NoError(t, cred.Ver(sk, key.Ipk))
+
+	// the credential is bound to the secret key of the user
+	otherSk, err := NewRandSk(rand.Reader)
+	assert.NoError(t, err)
+	assert.Error(t, cred.Ver(otherSk, key.Ipk))
+
+	// the attributes cannot be changed
+	cred.Attrs[1] = BigToBytes(big.NewInt(1))
+	assert.Error(t, cred.Ver(sk, key.Ipk))
+
+	// an incomplete number of attributes is rejected by the issuer
+	credReq, _, err := NewCredRequest(sk, []byte("nonce"), key.Ipk, rand.Reader)
+	assert.NoError(t, err)
+	_, err = NewCredential(key, credReq, attrs[:2], rand.Reader)
+	assert.Error(t, err)
+	_, err = NewCredential(key, credReq, []*big.Int{attrs[0], big.NewInt(-1), attrs[2]}, rand.Reader)
+	assert.Error(t, err)
+}
+
+func TestCredRequest(t *testing.T) {
+	key := loadIssuerKey(t)
+	sk, err := NewRandSk(rand.Reader)
+	assert.NoError(t, err)
+
+	credReq, _, err := NewCredRequest(sk, []byte("nonce"), key.Ipk, rand.Reader)
+	assert.NoError(t, err)
+	assert.NoError(t, credReq.Check(key.Ipk))
+
+	// the proof is bound to the nonce of the issuer
+	credReq.IssuerNonce = []byte("other nonce")
+	assert.Error(t, credReq.Check(key.Ipk))
+}
+
+func TestSignature(t *testing.T) {
+	key := loadIssuerKey(t)
+	attrs := []*big.Int{HashAttribute([]byte("OU1")), big.NewInt(0), HashAttribute([]byte("User1"))}
+	cred, sk := issueCredential(t, key, attrs)
+
+	nym, rNym, err := MakeNym(sk, rand.Reader)
+	assert.NoError(t, err)
+
+	disclosure := []byte{1, 1, 0}
+	msg := []byte("TestMessage")
+	sig, err := NewSignature(cred, sk, nym, rNym, key.Ipk, disclosure, msg, rand.Reader)
+	assert.NoError(t, err)
+
+	// only the disclosed attributes are needed to verify
+	assert.NoError(t, sig.Ver(disclosure, key.Ipk, msg, []*big.Int{attrs[0], attrs[1], nil}))
+
+	assert.Error(t, sig.Ver(disclosure, key.Ipk, []byte("OtherMessage"), []*big.Int{attrs[0], attrs[1], nil}))
+	assert.Error(t, sig.Ver(disclosure, key.Ipk, msg, []*big.Int{attrs[0], big.NewInt(1), nil}))
+	assert.Error(t, sig.Ver(disclosure, key.Ipk, msg, []*big.Int{attrs[0], nil, nil}))
+	assert.Error(t, sig.Ver([]byte{1, 1, 1}, key.Ipk, msg, attrs))
+
+	// hiding all attributes
+	disclosure = []byte{0, 0, 0}
+	sig, err = NewSignature(cred, sk, nym, rNym, key.Ipk, disclosure, msg, rand.Reader)
+	assert.NoError(t, err)
+	assert.NoError(t, sig.Ver(disclosure, key.Ipk, msg, []*big.Int{nil, nil, nil}))
+
+	// the signature is bound to the pseudonym
+	otherNym, _, err := MakeNym(sk, rand.Reader)
+	assert.NoError(t, err)
+	sig.Nym = BigToBytes(otherNym)
+	assert.Error(t, sig.Ver(disclosure, key.Ipk, msg, []*big.Int{nil, nil, nil}))
+}
+
+func TestNymSignature(t *testing.T) {
+	key := loadIssuerKey(t)
+	sk, err := NewRandSk(rand.Reader)
+	assert.NoError(t, err)
+
+	nym, rNym, err := MakeNym(sk, rand.Reader)
+	assert.NoError(t, err)
+
+	msg := []byte("TestMessage")
+	sig, err := NewNymSignature(sk, nym, rNym, key.Ipk, msg, rand.Reader)
+	assert.NoError(t, err)
+	assert.NoError(t, sig.Ver(nym, key.Ipk, msg))
+	assert.Error(t, sig.Ver(nym, key.Ipk, []byte("OtherMessage")))
+
+	// a different secret key cannot sign for the pseudonym
+	otherSk, err := NewRandSk(rand.Reader)
+	assert.NoError(t, err)
+	sig, err = NewNymSignature(otherSk, nym, rNym, key.Ipk, msg, rand.Reader)
+	assert.NoError(t, err)
+	assert.Error(t, sig.Ver(nym, key.Ipk, msg))
+
+	assert.Error(t, sig.Ver(big.NewInt(1), key.Ipk, msg))
+}
@@ -0,0 +1,227 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// The Issuer secret key is the factorization of a special RSA modulus n = p * q,
+// where p = 2 * p' + 1 and q = 2 * q' + 1 are safe primes. The public key consists
+// of the modulus and of random elements of the quadratic residues modulo n:
+// S, generating them, and Z, R_sk and R_i, powers of S with exponents known only
+// to the Issuer. A credential is a CL signature (A, e, v) on the secret key sk
+// of the user and on the attribute values m_i, that is
+//
+//	Z = A^e * S^v * R_sk^sk * prod(R_i^m_i) mod n
+
+// NewIssuerKey creates a new issuer key pair taking an array of attribute names
+// that will be contained in credentials certified by this issuer (a credential
+// specification)
+func NewIssuerKey(attributeNames []string, rng io.Reader) (*IssuerKey, error) {
+	// check for duplicated attributes
+	attributeNamesMap := map[string]bool{}
+	for _, name := range attributeNames {
+		if attributeNamesMap[name] {
+			return nil, fmt.Errorf("attribute %s appears multiple times in attributeNames", name)
+		}
+		attributeNamesMap[name] = true
+	}
+
+	p, err := safePrime(rng, ln/2)
+	if err != nil {
+		return nil, fmt.Errorf("failed generating safe prime p: %s", err)
+	}
+	var q *big.Int
+	for q == nil || q.Cmp(p) == 0 {
+		if q, err = safePrime(rng, ln/2); err != nil {
+			return nil, fmt.Errorf("failed generating safe prime q: %s", err)
+		}
+	}
+	n := new(big.Int).Mul(p, q)
+	// the order of the quadratic residues is p' * q'
+	order := new(big.Int).Mul(new(big.Int).Rsh(p, 1), new(big.Int).Rsh(q, 1))
+
+	s, err := randomQRGenerator(rng, n)
+	if err != nil {
+		return nil, err
+	}
+	randomPower := func() (*big.Int, error) {
+		x, err := randInt(rng, order)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).Exp(s, x, n), nil
+	}
+
+	ipk := &IssuerPublicKey{
+		AttributeNames: attributeNames,
+		N:              BigToBytes(n),
+		S:              BigToBytes(s),
+	}
+	z, err := randomPower()
+	if err != nil {
+		return nil, err
+	}
+	ipk.Z = BigToBytes(z)
+	rSk, err := randomPower()
+	if err != nil {
+		return nil, err
+	}
+	ipk.RSk = BigToBytes(rSk)
+	for range attributeNames {
+		r, err := randomPower()
+		if err != nil {
+			return nil, err
+		}
+		ipk.R = append(ipk.R, BigToBytes(r))
+	}
+	if err := ipk.SetHash(); err != nil {
+		return nil, err
+	}
+
+	return &IssuerKey{P: BigToBytes(p), Q: BigToBytes(q), Ipk: ipk}, nil
+}
+
+// SetHash computes the hash of the issuer public key, to which the
+// credentials and the proofs are bound
+func (ipk *IssuerPublicKey) SetHash() error {
+	ipk.Hash = nil
+	serializedIpk, err := proto.Marshal(ipk)
+	if err != nil {
+		return fmt.Errorf("failed to marshal issuer public key: %s", err)
+	}
+	hash := sha256.Sum256(serializedIpk)
+	ipk.Hash = hash[:]
+	return nil
+}
+
+// Check checks that the issuer public key is well formed
+func (ipk *IssuerPublicKey) Check() error {
+	if len(ipk.R) != len(ipk.AttributeNames) {
+		return fmt.Errorf("issuer public key must have %d R bases, found %d", len(ipk.AttributeNames), len(ipk.R))
+	}
+	n, err := BigFromBytes(ipk.N)
+	if err != nil {
+		return fmt.Errorf("invalid modulus: %s", err)
+	}
+	if n.BitLen() != ln {
+		return fmt.Errorf("the modulus must be %d bits long, found %d", ln, n.BitLen())
+	}
+	for i, b := range append([][]byte{ipk.S, ipk.Z, ipk.RSk}, ipk.R...) {
+		x, err := BigFromBytes(b)
+		if err != nil {
+			return fmt.Errorf("invalid base %d: %s", i, err)
+		}
+		if x.Sign() <= 0 || x.Cmp(n) >= 0 {
+			return fmt.Errorf("base %d is not an element of Z_n", i)
+		}
+	}
+
+	hash := ipk.Hash
+	defer func() { ipk.Hash = hash }()
+	if err := ipk.SetHash(); err != nil {
+		return err
+	}
+	if !bytes.Equal(hash, ipk.Hash) {
+		return errors.New("the hash of the issuer public key does not match")
+	}
+	return nil
+}
+
+// issuerPublicKeyValues holds the decoded values of an issuer public key
+type issuerPublicKeyValues struct {
+	n, s, z, rSk *big.Int
+	r            []*big.Int
+}
+
+func (ipk *IssuerPublicKey) values() (*issuerPublicKeyValues, error) {
+	xs, err := bigsFromBytes(append([][]byte{ipk.N, ipk.S, ipk.Z, ipk.RSk}, ipk.R...)...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issuer public key: %s", err)
+	}
+	return &issuerPublicKeyValues{n: xs[0], s: xs[1], z: xs[2], rSk: xs[3], r: xs[4:]}, nil
+}
+
+// smallPrimes are used to sieve the candidates of the safe primes
+var smallPrimes = func() []uint64 {
+	var primes []uint64
+	for n := uint64(3); n < 2048; n += 2 {
+		if big.NewInt(int64(n)).ProbablyPrime(1) {
+			primes = append(primes, n)
+		}
+	}
+	return primes
+}()
+
+// safePrime returns a random prime p of the given length such that p' = (p - 1) / 2
+// is prime as well. The candidates p' are searched incrementally from a random
+// starting point, discarding those for which p' or p have a small factor
+func safePrime(rng io.Reader, bits int) (*big.Int, error) {
+	for {
+		start, err := randBits(rng, bits-1)
+		if err != nil {
+			return nil, err
+		}
+		// set the two top bits so that p is exactly bits long, and make p' odd
+		start.SetBit(start, bits-2, 1).SetBit(start, bits-3, 1).SetBit(start, 0, 1)
+
+		residues := make([]uint64, len(smallPrimes))
+		for i, prime := range smallPrimes {
+			residues[i] = new(big.Int).Mod(start, new(big.Int).SetUint64(prime)).Uint64()
+		}
+
+	search:
+		for delta := uint64(0); delta < 1<<20; delta += 2 {
+			for i, prime := range smallPrimes {
+				r := (residues[i] + delta) % prime
+				if r == 0 || (2*r+1)%prime == 0 {
+					continue search
+				}
+			}
+
+			pPrime := new(big.Int).Add(start, new(big.Int).SetUint64(delta))
+			p := new(big.Int).Lsh(pPrime, 1)
+			p.Add(p, big.NewInt(1))
+			if p.BitLen() != bits {
+				break
+			}
+			// a cheap Fermat test on p discards most of the candidates
+			if new(big.Int).Exp(big.NewInt(2), new(big.Int).Lsh(pPrime, 1), p).Cmp(big.NewInt(1)) != 0 {
+				continue
+			}
+			if pPrime.ProbablyPrime(20) && p.ProbablyPrime(20) {
+				return p, nil
+			}
+		}
+	}
+}
+
+// randomQRGenerator returns a random generator of the quadratic residues
+// modulo the special RSA modulus n
+func randomQRGenerator(rng io.Reader, n *big.Int) (*big.Int, error) {
+	one := big.NewInt(1)
+	for {
+		x, err := randInt(rng, n)
+		if err != nil {
+			return nil, err
+		}
+		s := new(big.Int).Exp(x, big.NewInt(2), n)
+		// s generates the quadratic residues unless s - 1 shares a factor with n
+		if new(big.Int).GCD(nil, nil, new(big.Int).Sub(s, one), n).Cmp(one) == 0 &&
+			new(big.Int).GCD(nil, nil, new(big.Int).Add(s, one), n).Cmp(one) == 0 {
+			return s, nil
+		}
+	}
+}
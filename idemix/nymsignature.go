@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// nymSignatureLabel is the domain separation label of the proofs of the pseudonymous signatures
+const nymSignatureLabel = "nymSign"
+
+// MakeNym creates a new unlinkable pseudonym of the user, committing to its
+// secret key with the randomness rNym
+func MakeNym(sk *big.Int, rng io.Reader) (*big.Int, *big.Int, error) {
+	rNym, err := randInt(rng, nymQ)
+	if err != nil {
+		return nil, nil, err
+	}
+	return multiExpMod(nymP, []*big.Int{nymG, nymH}, []*big.Int{sk, rNym}), rNym, nil
+}
+
+// NewNymSignature creates a new idemix pseudonymous signature, proving the
+// knowledge of the secret key and of the randomness of the pseudonym
+func NewNymSignature(sk *big.Int, nym *big.Int, rNym *big.Int, ipk *IssuerPublicKey, msg []byte, rng io.Reader) (*NymSignature, error) {
+	rSk, err := randInt(rng, nymQ)
+	if err != nil {
+		return nil, err
+	}
+	rRNym, err := randInt(rng, nymQ)
+	if err != nil {
+		return nil, err
+	}
+	t := multiExpMod(nymP, []*big.Int{nymG, nymH}, []*big.Int{rSk, rRNym})
+
+	nonce, err := randBits(rng, lStat)
+	if err != nil {
+		return nil, err
+	}
+	c := challenge([]byte(nymSignatureLabel), ipk.Hash, BigToBytes(nym), BigToBytes(t), msg, BigToBytes(nonce))
+
+	response := func(r, x *big.Int) []byte {
+		s := new(big.Int).Add(r, new(big.Int).Mul(c, x))
+		return BigToBytes(s.Mod(s, nymQ))
+	}
+	return &NymSignature{
+		ProofC:     BigToBytes(c),
+		ProofSSk:   response(rSk, sk),
+		ProofSRNym: response(rRNym, rNym),
+		Nonce:      BigToBytes(nonce),
+	}, nil
+}
+
+// Ver verifies an idemix pseudonymous signature on the message
+func (sig *NymSignature) Ver(nym *big.Int, ipk *IssuerPublicKey, msg []byte) error {
+	if err := checkNym(nym); err != nil {
+		return err
+	}
+	values, err := bigsFromBytes(sig.ProofC, sig.ProofSSk, sig.ProofSRNym, sig.Nonce)
+	if err != nil {
+		return fmt.Errorf("invalid pseudonym signature: %s", err)
+	}
+	c, sSk, sRNym, nonce := values[0], values[1], values[2], values[3]
+
+	// T = nym^-c * g^s_sk * h^s_rnym
+	t := multiExpMod(nymP, []*big.Int{nym, nymG, nymH}, []*big.Int{new(big.Int).Neg(c), sSk, sRNym})
+	if challenge([]byte(nymSignatureLabel), ipk.Hash, BigToBytes(nym), BigToBytes(t), msg, BigToBytes(nonce)).Cmp(c) != 0 {
+		return errors.New("pseudonym signature invalid: zero-knowledge proof is invalid")
+	}
+	return nil
+}
+
+// checkNym checks that the pseudonym is an element of the subgroup of order nymQ
+func checkNym(nym *big.Int) error {
+	if nym.Cmp(big.NewInt(1)) <= 0 || nym.Cmp(nymP) >= 0 || new(big.Int).Exp(nym, nymQ, nymP).Cmp(big.NewInt(1)) != 0 {
+		return errors.New("invalid pseudonym")
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// signatureLabel is the domain separation label of the proofs of the signatures
+const signatureLabel = "sign"
+
+// NewSignature creates a new idemix signature (Schnorr-type signature) on a message.
+// The signature proves the possession of a credential on the secret key bound to
+// the pseudonym nym, and discloses the attributes whose disclosure flag is 1.
+// The credential is randomized so that two signatures cannot be linked by the
+// verifiers, nor by the issuer
+func NewSignature(cred *Credential, sk *big.Int, nym *big.Int, rNym *big.Int, ipk *IssuerPublicKey, disclosure []byte, msg []byte, rng io.Reader) (*Signature, error) {
+	if len(disclosure) != len(ipk.AttributeNames) || len(cred.Attrs) != len(ipk.AttributeNames) {
+		return nil, errors.New("the disclosure and the credential must match the attributes of the issuer public key")
+	}
+	pk, err := ipk.values()
+	if err != nil {
+		return nil, err
+	}
+	values, err := bigsFromBytes(append([][]byte{cred.A, cred.E, cred.V}, cred.Attrs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential: %s", err)
+	}
+	a, e, v, attrs := values[0], values[1], values[2], values[3:]
+
+	// randomize the credential: A' = A * S^r_A and v' = v - e * r_A, so that
+	// Z = A'^e * S^v' * R_sk^sk * prod(R_i^m_i)
+	rA, err := randBits(rng, ln+lStat)
+	if err != nil {
+		return nil, err
+	}
+	aPrime := new(big.Int).Mul(a, new(big.Int).Exp(pk.s, rA, pk.n))
+	aPrime.Mod(aPrime, pk.n)
+	vPrime := new(big.Int).Sub(v, new(big.Int).Mul(e, rA))
+	ePrime := new(big.Int).Sub(e, new(big.Int).Lsh(big.NewInt(1), le-1))
+
+	// compute the commitments of the proof
+	rE, err := randBits(rng, lePrime+lStat+lH)
+	if err != nil {
+		return nil, err
+	}
+	rV, err := randBits(rng, lv+lStat+lH)
+	if err != nil {
+		return nil, err
+	}
+	rSk, err := randBits(rng, lm+lStat+lH)
+	if err != nil {
+		return nil, err
+	}
+	rRNym, err := randInt(rng, nymQ)
+	if err != nil {
+		return nil, err
+	}
+	bases := []*big.Int{aPrime, pk.s, pk.rSk}
+	exps := []*big.Int{rE, rV, rSk}
+	rAttrs := make([]*big.Int, len(attrs))
+	for i := range attrs {
+		if disclosure[i] == 1 {
+			continue
+		}
+		if rAttrs[i], err = randBits(rng, lm+lStat+lH); err != nil {
+			return nil, err
+		}
+		bases = append(bases, pk.r[i])
+		exps = append(exps, rAttrs[i])
+	}
+	tZ := multiExpMod(pk.n, bases, exps)
+	tNym := multiExpMod(nymP, []*big.Int{nymG, nymH}, []*big.Int{rSk, rRNym})
+
+	nonce, err := randBits(rng, lStat)
+	if err != nil {
+		return nil, err
+	}
+	disclosedAttrs := make([]*big.Int, len(attrs))
+	for i := range attrs {
+		if disclosure[i] == 1 {
+			disclosedAttrs[i] = attrs[i]
+		}
+	}
+	c := signatureChallenge(ipk, aPrime, nym, tZ, tNym, disclosure, disclosedAttrs, msg, nonce)
+
+	// compute the responses
+	response := func(r, x *big.Int) []byte {
+		return BigToBytes(new(big.Int).Add(r, new(big.Int).Mul(c, x)))
+	}
+	sig := &Signature{
+		APrime:     BigToBytes(aPrime),
+		Nym:        BigToBytes(nym),
+		ProofC:     BigToBytes(c),
+		ProofSE:    response(rE, ePrime),
+		ProofSV:    response(rV, vPrime),
+		ProofSSk:   response(rSk, sk),
+		ProofSRNym: BigToBytes(new(big.Int).Mod(new(big.Int).Add(rRNym, new(big.Int).Mul(c, rNym)), nymQ)),
+		Nonce:      BigToBytes(nonce),
+	}
+	for i := range attrs {
+		if disclosure[i] == 0 {
+			sig.ProofSAttrs = append(sig.ProofSAttrs, response(rAttrs[i], attrs[i]))
+		}
+	}
+	return sig, nil
+}
+
+// Ver verifies an idemix signature. The values of the disclosed attributes are
+// given in attributeValues, whose entries of the hidden attributes are ignored
+func (sig *Signature) Ver(disclosure []byte, ipk *IssuerPublicKey, msg []byte, attributeValues []*big.Int) error {
+	if len(disclosure) != len(ipk.AttributeNames) || len(attributeValues) != len(ipk.AttributeNames) {
+		return errors.New("the disclosure and the attribute values must match the attributes of the issuer public key")
+	}
+	hidden := 0
+	for i := range disclosure {
+		if disclosure[i] == 0 {
+			hidden++
+		} else if attributeValues[i] == nil {
+			return fmt.Errorf("the value of the disclosed attribute %s is missing", ipk.AttributeNames[i])
+		}
+	}
+	if len(sig.ProofSAttrs) != hidden {
+		return fmt.Errorf("signature has %d hidden attribute responses, expected %d", len(sig.ProofSAttrs), hidden)
+	}
+
+	pk, err := ipk.values()
+	if err != nil {
+		return err
+	}
+	values, err := bigsFromBytes(append([][]byte{sig.APrime, sig.Nym, sig.ProofC, sig.ProofSE, sig.ProofSV, sig.ProofSSk, sig.ProofSRNym, sig.Nonce}, sig.ProofSAttrs...)...)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %s", err)
+	}
+	aPrime, nym, c, sE, sV, sSk, sRNym, nonce, sAttrs := values[0], values[1], values[2], values[3], values[4], values[5], values[6], values[7], values[8:]
+
+	if aPrime.Sign() <= 0 || aPrime.Cmp(pk.n) >= 0 {
+		return errors.New("invalid signature: A' is not an element of Z_n")
+	}
+	if err := checkNym(nym); err != nil {
+		return err
+	}
+	if !inRange(sE, lePrime+lStat+lH+1) || !inRange(sSk, lm+lStat+lH+1) {
+		return errors.New("invalid signature: response out of range")
+	}
+	for _, sAttr := range sAttrs {
+		if !inRange(sAttr, lm+lStat+lH+1) {
+			return errors.New("invalid signature: response out of range")
+		}
+	}
+
+	// Z' = Z / (A'^(2^(le-1)) * prod_disclosed(R_i^m_i))
+	bases := []*big.Int{aPrime}
+	exps := []*big.Int{new(big.Int).Lsh(big.NewInt(1), le-1)}
+	for i := range disclosure {
+		if disclosure[i] == 1 {
+			bases = append(bases, pk.r[i])
+			exps = append(exps, attributeValues[i])
+		}
+	}
+	zPrime := new(big.Int).ModInverse(multiExpMod(pk.n, bases, exps), pk.n)
+	if zPrime == nil {
+		return errors.New("invalid signature: A' is not invertible")
+	}
+	zPrime.Mul(zPrime, pk.z).Mod(zPrime, pk.n)
+
+	// T_Z = Z'^-c * A'^s_e * S^s_v * R_sk^s_sk * prod_hidden(R_i^s_i)
+	bases = []*big.Int{zPrime, aPrime, pk.s, pk.rSk}
+	exps = []*big.Int{new(big.Int).Neg(c), sE, sV, sSk}
+	j := 0
+	for i := range disclosure {
+		if disclosure[i] == 0 {
+			bases = append(bases, pk.r[i])
+			exps = append(exps, sAttrs[j])
+			j++
+		}
+	}
+	tZ := multiExpMod(pk.n, bases, exps)
+
+	// T_nym = nym^-c * g^s_sk * h^s_rnym
+	tNym := multiExpMod(nymP, []*big.Int{nym, nymG, nymH}, []*big.Int{new(big.Int).Neg(c), sSk, sRNym})
+
+	disclosedAttrs := make([]*big.Int, len(disclosure))
+	for i := range disclosure {
+		if disclosure[i] == 1 {
+			disclosedAttrs[i] = attributeValues[i]
+		}
+	}
+	if signatureChallenge(ipk, aPrime, nym, tZ, tNym, disclosure, disclosedAttrs, msg, nonce).Cmp(c) != 0 {
+		return errors.New("signature invalid: zero-knowledge proof is invalid")
+	}
+	return nil
+}
+
+func signatureChallenge(ipk *IssuerPublicKey, aPrime, nym, tZ, tNym *big.Int, disclosure []byte, disclosedAttrs []*big.Int, msg []byte, nonce *big.Int) *big.Int {
+	values := [][]byte{[]byte(signatureLabel), ipk.Hash, BigToBytes(aPrime), BigToBytes(nym), BigToBytes(tZ), BigToBytes(tNym), disclosure}
+	for _, attr := range disclosedAttrs {
+		if attr != nil {
+			values = append(values, BigToBytes(attr))
+		}
+	}
+	return challenge(append(values, msg, BigToBytes(nonce))...)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// The lengths in bits of the system parameters, as specified by the
+// Identity Mixer protocols for a 2048 bits modulus
+const (
+	// ln is the length of the modulus of the issuer
+	ln = 2048
+	// lm is the length of the attribute values and of the secret keys
+	lm = 256
+	// le is the length of the e values of the credentials
+	le = 597
+	// lePrime is the length of the interval the e values are picked from
+	lePrime = 120
+	// lv is the length of the v values of the credentials
+	lv = 2724
+	// lStat is the security parameter of the statistical zero-knowledge
+	lStat = 80
+	// lH is the length of the challenges of the proofs
+	lH = 256
+)
+
+// The pseudonyms are Pedersen commitments g^sk * h^r to the secret key of the
+// user in the prime order subgroup of the 2048-bit MODP group of RFC 3526,
+// whose modulus is the safe prime nymP = 2 * nymQ + 1
+var (
+	nymP = bigFromHex(
+		"FFFFFFFFFFFFFFFFC90FDAA22168C234C4C6628B80DC1CD1" +
+			"29024E088A67CC74020BBEA63B139B22514A08798E3404DD" +
+			"EF9519B3CD3A431B302B0A6DF25F14374FE1356D6D51C245" +
+			"E485B576625E7EC6F44C42E9A637ED6B0BFF5CB6F406B7ED" +
+			"EE386BFB5A899FA5AE9F24117C4B1FE649286651ECE45B3D" +
+			"C2007CB8A163BF0598DA48361C55D39A69163FA8FD24CF5F" +
+			"83655D23DCA3AD961C62F356208552BB9ED529077096966D" +
+			"670C354E4ABC9804F1746C08CA18217C32905E462E36CE3B" +
+			"E39E772C180E86039B2783A2EC07A28FB5C55DF06F4C52C9" +
+			"DE2BCBF6955817183995497CEA956AE515D2261898FA0510" +
+			"15728E5A8AACAA68FFFFFFFFFFFFFFFF")
+	nymQ = new(big.Int).Rsh(nymP, 1)
+	nymG = big.NewInt(4)
+	nymH = hashToNymGroup([]byte("idemix pseudonym base h"))
+)
+
+func bigFromHex(s string) *big.Int {
+	x, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex constant " + s)
+	}
+	return x
+}
+
+// hashToNymGroup maps a seed to an element of the subgroup of the pseudonyms
+// whose discrete logarithm with respect to nymG is unknown
+func hashToNymGroup(seed []byte) *big.Int {
+	var digest []byte
+	for counter := uint32(0); len(digest) < ln/8+lStat/8; counter++ {
+		counterBytes := make([]byte, 4)
+		binary.BigEndian.PutUint32(counterBytes, counter)
+		h := sha256.Sum256(append(counterBytes, seed...))
+		digest = append(digest, h[:]...)
+	}
+	x := new(big.Int).Mod(new(big.Int).SetBytes(digest), nymP)
+	return x.Exp(x, big.NewInt(2), nymP)
+}
+
+// BigToBytes encodes a big integer, which may be negative
+func BigToBytes(x *big.Int) []byte {
+	sign := byte(0)
+	if x.Sign() < 0 {
+		sign = 1
+	}
+	return append([]byte{sign}, x.Bytes()...)
+}
+
+// BigFromBytes decodes a big integer encoded by BigToBytes
+func BigFromBytes(b []byte) (*big.Int, error) {
+	if len(b) == 0 || b[0] > 1 {
+		return nil, errors.New("invalid big integer encoding")
+	}
+	x := new(big.Int).SetBytes(b[1:])
+	if b[0] == 1 {
+		x.Neg(x)
+	}
+	return x, nil
+}
+
+// bigsFromBytes decodes the given big integers, failing on the first invalid one
+func bigsFromBytes(bs ...[]byte) ([]*big.Int, error) {
+	xs := make([]*big.Int, len(bs))
+	for i, b := range bs {
+		x, err := BigFromBytes(b)
+		if err != nil {
+			return nil, err
+		}
+		xs[i] = x
+	}
+	return xs, nil
+}
+
+// randBits returns a uniformly random integer in [0, 2^bits)
+func randBits(rng io.Reader, bits int) (*big.Int, error) {
+	return randInt(rng, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+}
+
+// randInt returns a uniformly random integer in [0, max)
+func randInt(rng io.Reader, max *big.Int) (*big.Int, error) {
+	b := make([]byte, (max.BitLen()+7)/8+lStat/8)
+	if _, err := io.ReadFull(rng, b); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mod(new(big.Int).SetBytes(b), max), nil
+}
+
+// expMod returns base^exp mod m, where exp may be negative
+func expMod(base, exp, m *big.Int) *big.Int {
+	if exp.Sign() >= 0 {
+		return new(big.Int).Exp(base, exp, m)
+	}
+	inv := new(big.Int).ModInverse(base, m)
+	if inv == nil {
+		return big.NewInt(0)
+	}
+	return inv.Exp(inv, new(big.Int).Neg(exp), m)
+}
+
+// multiExpMod returns the product of the bases[i]^exps[i] mod m
+func multiExpMod(m *big.Int, bases []*big.Int, exps []*big.Int) *big.Int {
+	res := big.NewInt(1)
+	for i := range bases {
+		res.Mul(res, expMod(bases[i], exps[i], m))
+		res.Mod(res, m)
+	}
+	return res
+}
+
+// challenge hashes the given values into a challenge of lH bits
+func challenge(values ...[]byte) *big.Int {
+	h := sha256.New()
+	for _, v := range values {
+		length := make([]byte, 8)
+		binary.BigEndian.PutUint64(length, uint64(len(v)))
+		h.Write(length)
+		h.Write(v)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// inRange checks that |x| < 2^bits
+func inRange(x *big.Int, bits int) bool {
+	return x.BitLen() <= bits
+}
+
+// HashAttribute maps a string attribute value to an integer of lm bits
+func HashAttribute(value []byte) *big.Int {
+	h := sha256.Sum256(value)
+	return new(big.Int).SetBytes(h[:])
+}
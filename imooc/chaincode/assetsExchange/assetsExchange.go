@@ -12,6 +12,9 @@ type AssertsExchangeCC struct{}
 
 const (
 	originOwner = "originOwnerPlaceholder"
+	// 用户数据中含有身份证号等敏感信息，所以存储在私有数据集合中
+	// 链上只保留其哈希值，明文只分发给集合成员组织的节点
+	userCollection = "collectionUsers"
 )
 
 // 在此处定义json tag 主要是我们在存储状态的时候
@@ -71,8 +74,8 @@ func userRegister(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 	}
 
 	// 套路3：验证数据是否存在 应该存在 or 不应该存在
-	// 通过SDK的GetState方法读取状态
-	userBytes, err := stub.GetState(constructUserKey(id))
+	// 通过SDK的GetPrivateData方法读取用户的私有数据
+	userBytes, err := stub.GetPrivateData(userCollection, constructUserKey(id))
 	if err == nil && len(userBytes) != 0 {
 		return shim.Error("user already exist!")
 	}
@@ -92,8 +95,8 @@ func userRegister(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 		return shim.Error(fmt.Sprintf("marshal user error %s", err))
 	}
 
-	// 将对象，写入状态数据库
-	if err := stub.PutState(constructUserKey(id), userBytes); err != nil {
+	// 将对象，写入私有数据集合
+	if err := stub.PutPrivateData(userCollection, constructUserKey(id), userBytes); err != nil {
 		return shim.Error(fmt.Sprintf("put user error %s", err))
 	}
 
@@ -116,14 +119,14 @@ func userDestroy(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 	}
 
 	// 套路3：验证数据是否存在 应该存在 or 不应该存在
-	// 通过SDK的GetState方法读取状态
-	userBytes, err := stub.GetState(constructUserKey(id))
+	// 通过SDK的GetPrivateData方法读取用户的私有数据
+	userBytes, err := stub.GetPrivateData(userCollection, constructUserKey(id))
 	if err != nil || len(userBytes) == 0 {
 		return shim.Error("user not found")
 	}
 
 	// 套路4：写入状态
-	if err := stub.DelState(constructUserKey(id)); err != nil {
+	if err := stub.DelPrivateData(userCollection, constructUserKey(id)); err != nil {
 		return shim.Error(fmt.Sprintf("delete user error: %s", err))
 	}
 
@@ -159,8 +162,8 @@ func assetEnroll(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 	}
 
 	// 套路3：验证数据是否存在 应该存在 or 不应该存在
-	// 通过SDK的GetState方法读取状态
-	userBytes, err := stub.GetState(constructUserKey(ownerId))
+	// 通过SDK的GetPrivateData方法读取用户的私有数据
+	userBytes, err := stub.GetPrivateData(userCollection, constructUserKey(ownerId))
 	if err != nil || len(userBytes) == 0 {
 		return shim.Error("user not found")
 	}
@@ -202,7 +205,7 @@ func assetEnroll(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 		return shim.Error(fmt.Sprintf("marshal user error: %s", err))
 	}
 	// 写入user
-	if err := stub.PutState(constructUserKey(user.Id), userBytes); err != nil {
+	if err := stub.PutPrivateData(userCollection, constructUserKey(user.Id), userBytes); err != nil {
 		return shim.Error(fmt.Sprintf("update user error: %s", err))
 	}
 
@@ -251,12 +254,12 @@ func assetExchange(stub shim.ChaincodeStubInterface, args []string) pb.Response
 
 	// 套路3：验证数据是否存在 应该存在 or 不应该存在
 	// 通过SDK的GetState方法读取状态
-	originOwnerBytes, err := stub.GetState(constructUserKey(ownerId))
+	originOwnerBytes, err := stub.GetPrivateData(userCollection, constructUserKey(ownerId))
 	if err != nil || len(originOwnerBytes) == 0 {
 		return shim.Error("user not found")
 	}
 
-	currentOwnerBytes, err := stub.GetState(constructUserKey(currentOwnerId))
+	currentOwnerBytes, err := stub.GetPrivateData(userCollection, constructUserKey(currentOwnerId))
 	if err != nil || len(currentOwnerBytes) == 0 {
 		return shim.Error("user not foud")
 	}
@@ -300,7 +303,7 @@ func assetExchange(stub shim.ChaincodeStubInterface, args []string) pb.Response
 	if err != nil {
 		return shim.Error(fmt.Sprintf("marshal user error: %s", err))
 	}
-	if err := stub.PutState(constructUserKey(ownerId), originOwnerBytes); err != nil {
+	if err := stub.PutPrivateData(userCollection, constructUserKey(ownerId), originOwnerBytes); err != nil {
 		return shim.Error(fmt.Sprintf("update user error: %s", err))
 	}
 
@@ -315,7 +318,7 @@ func assetExchange(stub shim.ChaincodeStubInterface, args []string) pb.Response
 	if err != nil {
 		return shim.Error(fmt.Sprintf("marshal user error: %s", err))
 	}
-	if err := stub.PutState(constructUserKey(currentOwnerId), currentOwnerBytes); err != nil {
+	if err := stub.PutPrivateData(userCollection, constructUserKey(currentOwnerId), currentOwnerBytes); err != nil {
 		return shim.Error(fmt.Sprintf("update user error: %s", err))
 	}
 
@@ -355,7 +358,7 @@ func queryUser(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 		return shim.Error("invalid args")
 	}
 
-	userBytes, err := stub.GetState(constructUserKey(ownerId))
+	userBytes, err := stub.GetPrivateData(userCollection, constructUserKey(ownerId))
 	if err != nil || len(userBytes) == 0 {
 		return shim.Error("user not found")
 	}
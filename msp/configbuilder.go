@@ -38,8 +38,19 @@ type OrganizationalUnitIdentifiersConfiguration struct {
 	OrganizationalUnitIdentifier string `yaml:"OrganizationalUnitIdentifier,omitempty"`
 }
 
+// NodeOUs contains the information to tell apart clients, peers, admins and
+// orderers based on the OUs of their certificates
+type NodeOUs struct {
+	Enable              bool                                        `yaml:"Enable,omitempty"`
+	ClientOUIdentifier  *OrganizationalUnitIdentifiersConfiguration `yaml:"ClientOUIdentifier,omitempty"`
+	PeerOUIdentifier    *OrganizationalUnitIdentifiersConfiguration `yaml:"PeerOUIdentifier,omitempty"`
+	AdminOUIdentifier   *OrganizationalUnitIdentifiersConfiguration `yaml:"AdminOUIdentifier,omitempty"`
+	OrdererOUIdentifier *OrganizationalUnitIdentifiersConfiguration `yaml:"OrdererOUIdentifier,omitempty"`
+}
+
 type Configuration struct {
 	OrganizationalUnitIdentifiers []*OrganizationalUnitIdentifiersConfiguration `yaml:"OrganizationalUnitIdentifiers,omitempty"`
+	NodeOUs                       *NodeOUs                                      `yaml:"NodeOUs,omitempty"`
 }
 
 func readFile(file string) ([]byte, error) {
@@ -111,6 +122,17 @@ const (
 	tlsintermediatecerts = "tlsintermediatecerts"
 )
 
+const (
+	// IdemixConfigDirMsp is the directory of the issuer public key of an idemix MSP
+	IdemixConfigDirMsp = "msp"
+	// IdemixConfigDirUser is the directory of the signer configuration of an idemix MSP
+	IdemixConfigDirUser = "user"
+	// IdemixConfigFileIssuerPublicKey is the file holding the issuer public key
+	IdemixConfigFileIssuerPublicKey = "IssuerPublicKey"
+	// IdemixConfigFileSigner is the file holding the credential and secret key of the signer
+	IdemixConfigFileSigner = "SignerConfig"
+)
+
 func SetupBCCSPKeystoreConfig(bccspConfig *factory.FactoryOpts, keystoreDir string) *factory.FactoryOpts {
 	if bccspConfig == nil {
 		bccspConfig = factory.GetDefaultOpts()
@@ -216,6 +238,7 @@ func getMspConfig(dir string, ID string, sigid *msp.SigningIdentityInfo) (*msp.M
 	// if the configuration file is there then load it
 	// otherwise skip it
 	var ouis []*msp.FabricOUIdentifier
+	var nodeOUs *msp.FabricNodeOUs
 	_, err = os.Stat(configFile)
 	if err == nil {
 		// load the file, if there is a failure in loading it then
@@ -246,6 +269,24 @@ func getMspConfig(dir string, ID string, sigid *msp.SigningIdentityInfo) (*msp.M
 				ouis = append(ouis, oui)
 			}
 		}
+
+		// Prepare NodeOUs
+		if configuration.NodeOUs != nil && configuration.NodeOUs.Enable {
+			mspLogger.Debug("Loading NodeOUs")
+			nodeOUs = &msp.FabricNodeOUs{Enable: true}
+			if nodeOUs.ClientOuIdentifier, err = loadNodeOUIdentifier(dir, configuration.NodeOUs.ClientOUIdentifier); err != nil {
+				return nil, err
+			}
+			if nodeOUs.PeerOuIdentifier, err = loadNodeOUIdentifier(dir, configuration.NodeOUs.PeerOUIdentifier); err != nil {
+				return nil, err
+			}
+			if nodeOUs.AdminOuIdentifier, err = loadNodeOUIdentifier(dir, configuration.NodeOUs.AdminOUIdentifier); err != nil {
+				return nil, err
+			}
+			if nodeOUs.OrdererOuIdentifier, err = loadNodeOUIdentifier(dir, configuration.NodeOUs.OrdererOUIdentifier); err != nil {
+				return nil, err
+			}
+		}
 	} else {
 		mspLogger.Debugf("MSP configuration file not found at [%s]: [%s]", configFile, err)
 	}
@@ -268,6 +309,7 @@ func getMspConfig(dir string, ID string, sigid *msp.SigningIdentityInfo) (*msp.M
 		CryptoConfig:                  cryptoConfig,
 		TlsRootCerts:                  tlsCACerts,
 		TlsIntermediateCerts:          tlsIntermediateCerts,
+		FabricNodeOus:                 nodeOUs,
 	}
 
 	fmpsjs, _ := proto.Marshal(fmspconf)
@@ -276,3 +318,54 @@ func getMspConfig(dir string, ID string, sigid *msp.SigningIdentityInfo) (*msp.M
 
 	return mspconf, nil
 }
+
+// loadNodeOUIdentifier loads the OU identifier of a node role. The certificate
+// is optional: if missing, the OU identifier is not bound to a chain of trust
+func loadNodeOUIdentifier(dir string, ouID *OrganizationalUnitIdentifiersConfiguration) (*msp.FabricOUIdentifier, error) {
+	if ouID == nil {
+		return nil, nil
+	}
+
+	oui := &msp.FabricOUIdentifier{OrganizationalUnitIdentifier: ouID.OrganizationalUnitIdentifier}
+	if ouID.Certificate != "" {
+		f := filepath.Join(dir, ouID.Certificate)
+		raw, err := readFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading NodeOU certificate at [%s]: [%s]", f, err)
+		}
+		oui.Certificate = raw
+	}
+
+	return oui, nil
+}
+
+// GetIdemixMspConfig returns the configuration for the Idemix MSP of the
+// specified directory; the signer configuration is optional
+func GetIdemixMspConfig(dir string, ID string) (*msp.MSPConfig, error) {
+	ipkBytes, err := readFile(filepath.Join(dir, IdemixConfigDirMsp, IdemixConfigFileIssuerPublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read issuer public key file: %s", err)
+	}
+
+	idemixConfig := &msp.IdemixMSPConfig{
+		Name: ID,
+		Ipk:  ipkBytes,
+	}
+
+	signerBytes, err := readFile(filepath.Join(dir, IdemixConfigDirUser, IdemixConfigFileSigner))
+	if err == nil {
+		signerConfig := &msp.IdemixMSPSignerConfig{}
+		err = proto.Unmarshal(signerBytes, signerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal idemix signer config: %s", err)
+		}
+		idemixConfig.Signer = signerConfig
+	}
+
+	confBytes, err := proto.Marshal(idemixConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &msp.MSPConfig{Config: confBytes, Type: int32(IDEMIX)}, nil
+}
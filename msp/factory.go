@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import "fmt"
+
+var mspTypeStrings = map[ProviderType]string{
+	FABRIC: "bccsp",
+	IDEMIX: "idemix",
+}
+
+// ProviderTypeToString returns a string that represents the ProviderType integer
+func ProviderTypeToString(id ProviderType) string {
+	if res, found := mspTypeStrings[id]; found {
+		return res
+	}
+
+	return ""
+}
+
+// ProviderTypeFromString returns the ProviderType represented by the string
+func ProviderTypeFromString(mspType string) (ProviderType, error) {
+	for id, s := range mspTypeStrings {
+		if s == mspType {
+			return id, nil
+		}
+	}
+
+	return OTHER, fmt.Errorf("unknown msp type %s", mspType)
+}
+
+// New creates a new MSP instance of the given provider type
+func New(mspType ProviderType) (MSP, error) {
+	switch mspType {
+	case FABRIC:
+		return NewBccspMsp()
+	case IDEMIX:
+		return NewIdemixMsp()
+	default:
+		return nil, fmt.Errorf("unsupported msp type %d", mspType)
+	}
+}
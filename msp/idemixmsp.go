@@ -0,0 +1,478 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/idemix"
+	m "github.com/hyperledger/fabric/protos/msp"
+	"github.com/op/go-logging"
+)
+
+const (
+	// AttributeNameOU is the attribute name of the Organization Unit attribute
+	AttributeNameOU = "OU"
+
+	// AttributeNameRole is the attribute name of the Role attribute
+	AttributeNameRole = "Role"
+
+	// AttributeNameEnrollmentId is the attribute name of the Enrollment ID attribute
+	AttributeNameEnrollmentId = "EnrollmentID"
+)
+
+// index of the attributes in the credentials
+const (
+	AttributeIndexOU = iota
+	AttributeIndexRole
+	AttributeIndexEnrollmentId
+)
+
+// discloseFlags will be passed to the idemix signing and verification routines.
+// It informs idemix to disclose both attributes (OU and Role) when signing,
+// while hiding the enrollment ID
+var discloseFlags = []byte{1, 1, 0}
+
+// AttributeNames are the attribute names of the credentials of the idemix MSPs
+var AttributeNames = []string{AttributeNameOU, AttributeNameRole, AttributeNameEnrollmentId}
+
+// idemixmsp implements the MSP interface for the Identity Mixer credentials,
+// through which the users prove their membership with zero-knowledge proofs
+// under unlinkable pseudonyms, disclosing only their OU and role
+type idemixmsp struct {
+	ipk    *idemix.IssuerPublicKey
+	signer *idemixSigningIdentity
+	name   string
+}
+
+// NewIdemixMsp creates a new instance of idemixmsp
+func NewIdemixMsp() (MSP, error) {
+	mspLogger.Debugf("Creating Idemix-based MSP instance")
+
+	return &idemixmsp{}, nil
+}
+
+func (msp *idemixmsp) Setup(conf1 *m.MSPConfig) error {
+	mspLogger.Debugf("Setting up Idemix-based MSP instance")
+
+	if conf1 == nil {
+		return fmt.Errorf("Setup error: nil conf reference")
+	}
+
+	if conf1.Type != int32(IDEMIX) {
+		return fmt.Errorf("setup error: config is not of type IDEMIX")
+	}
+
+	var conf m.IdemixMSPConfig
+	err := proto.Unmarshal(conf1.Config, &conf)
+	if err != nil {
+		return fmt.Errorf("Failed unmarshalling idemix msp config, err %s", err)
+	}
+
+	msp.name = conf.Name
+	mspLogger.Debugf("Setting up Idemix MSP instance %s", msp.name)
+
+	ipk := new(idemix.IssuerPublicKey)
+	err = proto.Unmarshal(conf.Ipk, ipk)
+	if err != nil {
+		return fmt.Errorf("Failed to unmarshal ipk from idemix msp config, err %s", err)
+	}
+	if err = ipk.Check(); err != nil {
+		return fmt.Errorf("Cannot setup idemix msp with invalid public key: %s", err)
+	}
+	if len(ipk.AttributeNames) != len(AttributeNames) {
+		return fmt.Errorf("ipk must have %d attributes, found %d", len(AttributeNames), len(ipk.AttributeNames))
+	}
+	for i, name := range AttributeNames {
+		if ipk.AttributeNames[i] != name {
+			return fmt.Errorf("ipk must have attribute %s at index %d, found %s", name, i, ipk.AttributeNames[i])
+		}
+	}
+	msp.ipk = ipk
+
+	if conf.Signer == nil {
+		// No credential in config, so we don't setup a default signer
+		mspLogger.Debug("idemix msp setup as verification only msp (no key material found)")
+		return nil
+	}
+
+	// A credential is present in the config, so we setup a default signer
+	role := &m.MSPRole{
+		MspIdentifier: msp.name,
+		Role:          m.MSPRole_MSPRoleType(conf.Signer.Role),
+	}
+	ou := &m.OrganizationUnit{
+		MspIdentifier:                msp.name,
+		OrganizationalUnitIdentifier: conf.Signer.OrganizationalUnitIdentifier,
+		CertifiersIdentifier:         ipk.Hash,
+	}
+
+	cred := new(idemix.Credential)
+	err = proto.Unmarshal(conf.Signer.Cred, cred)
+	if err != nil {
+		return fmt.Errorf("Failed unmarshalling credential from config, err %s", err)
+	}
+	sk, err := idemix.BigFromBytes(conf.Signer.Sk)
+	if err != nil {
+		return fmt.Errorf("Invalid secret key in config, err %s", err)
+	}
+
+	// Verify that the credential is valid and carries the attributes of the signer
+	if err = cred.Ver(sk, msp.ipk); err != nil {
+		return fmt.Errorf("Credential is not cryptographically valid, err %s", err)
+	}
+	attrs := signerAttributes(conf.Signer)
+	for i, attr := range attrs {
+		value, err := idemix.BigFromBytes(cred.Attrs[i])
+		if err != nil || value.Cmp(attr) != 0 {
+			return fmt.Errorf("Credential does not contain the %s of the signer", AttributeNames[i])
+		}
+	}
+
+	// Create the pseudonym of the default signer, and prove that it owns
+	// a credential disclosing its OU and role
+	nym, randNym, err := idemix.MakeNym(sk, rand.Reader)
+	if err != nil {
+		return fmt.Errorf("Failed creating pseudonym, err %s", err)
+	}
+	proof, err := idemix.NewSignature(cred, sk, nym, randNym, ipk, discloseFlags, nil, rand.Reader)
+	if err != nil {
+		return fmt.Errorf("Failed to setup cryptographic proof of identity, err %s", err)
+	}
+
+	msp.signer = &idemixSigningIdentity{
+		idemixidentity: newIdemixIdentity(msp, nym, role, ou, proof),
+		cred:           cred,
+		sk:             sk,
+		randNym:        randNym,
+	}
+
+	return nil
+}
+
+// signerAttributes returns the attribute values of the credential of a signer
+func signerAttributes(signer *m.IdemixMSPSignerConfig) []*big.Int {
+	attrs := make([]*big.Int, len(AttributeNames))
+	attrs[AttributeIndexOU] = idemix.HashAttribute([]byte(signer.OrganizationalUnitIdentifier))
+	attrs[AttributeIndexRole] = big.NewInt(int64(signer.Role))
+	attrs[AttributeIndexEnrollmentId] = idemix.HashAttribute([]byte(signer.EnrollmentId))
+	return attrs
+}
+
+// GetType returns the type for this MSP
+func (msp *idemixmsp) GetType() ProviderType {
+	return IDEMIX
+}
+
+// GetIdentifier returns the MSP identifier for this instance
+func (msp *idemixmsp) GetIdentifier() (string, error) {
+	return msp.name, nil
+}
+
+// GetSigningIdentity returns a signing identity corresponding to the provided identifier
+func (msp *idemixmsp) GetSigningIdentity(identifier *IdentityIdentifier) (SigningIdentity, error) {
+	return nil, errors.New("GetSigningIdentity not implemented")
+}
+
+// GetDefaultSigningIdentity returns the default signing identity
+// for this MSP (if any)
+func (msp *idemixmsp) GetDefaultSigningIdentity() (SigningIdentity, error) {
+	mspLogger.Debugf("Obtaining default idemix signing identity")
+
+	if msp.signer == nil {
+		return nil, errors.New("no default signer setup")
+	}
+	return msp.signer, nil
+}
+
+// GetTLSRootCerts returns the root certificates for this MSP, none for idemix
+func (msp *idemixmsp) GetTLSRootCerts() [][]byte {
+	return nil
+}
+
+// GetTLSIntermediateCerts returns the intermediate root certificates for this MSP, none for idemix
+func (msp *idemixmsp) GetTLSIntermediateCerts() [][]byte {
+	return nil
+}
+
+// DeserializeIdentity returns an identity given its serialized version supplied as argument
+func (msp *idemixmsp) DeserializeIdentity(serializedID []byte) (Identity, error) {
+	sID := &m.SerializedIdentity{}
+	err := proto.Unmarshal(serializedID, sID)
+	if err != nil {
+		return nil, fmt.Errorf("Could not deserialize a SerializedIdentity, err %s", err)
+	}
+
+	if sID.Mspid != msp.name {
+		return nil, fmt.Errorf("expected MSP ID %s, received %s", msp.name, sID.Mspid)
+	}
+
+	return msp.deserializeIdentityInternal(sID.GetIdBytes())
+}
+
+func (msp *idemixmsp) deserializeIdentityInternal(serializedID []byte) (Identity, error) {
+	mspLogger.Debug("idemixmsp: deserializing identity")
+	serialized := new(m.SerializedIdemixIdentity)
+	err := proto.Unmarshal(serializedID, serialized)
+	if err != nil {
+		return nil, fmt.Errorf("could not deserialize a SerializedIdemixIdentity, err %s", err)
+	}
+	nym, err := idemix.BigFromBytes(serialized.Nym)
+	if err != nil {
+		return nil, fmt.Errorf("unable to deserialize idemix identity: invalid pseudonym, err %s", err)
+	}
+
+	ou := &m.OrganizationUnit{}
+	err = proto.Unmarshal(serialized.Ou, ou)
+	if err != nil {
+		return nil, fmt.Errorf("cannot deserialize the OU of the identity, err %s", err)
+	}
+	role := &m.MSPRole{}
+	err = proto.Unmarshal(serialized.Role, role)
+	if err != nil {
+		return nil, fmt.Errorf("cannot deserialize the role of the identity, err %s", err)
+	}
+	proof := &idemix.Signature{}
+	err = proto.Unmarshal(serialized.Proof, proof)
+	if err != nil {
+		return nil, fmt.Errorf("cannot deserialize the proof of the identity, err %s", err)
+	}
+
+	return newIdemixIdentity(msp, nym, role, ou, proof), nil
+}
+
+// Validate attempts to determine whether the supplied identity is valid
+// according to this MSP's roots of trust
+func (msp *idemixmsp) Validate(id Identity) error {
+	identity, ok := id.(*idemixidentity)
+	if !ok {
+		if signingIdentity, isSigner := id.(*idemixSigningIdentity); isSigner {
+			identity = signingIdentity.idemixidentity
+		} else {
+			return errors.New("identity type not recognized")
+		}
+	}
+
+	mspLogger.Debugf("Validating identity %+v", identity)
+	if identity.GetMSPIdentifier() != msp.name {
+		return fmt.Errorf("the supplied identity does not belong to this msp")
+	}
+	return identity.verifyProof()
+}
+
+// SatisfiesPrincipal checks whether the identity matches
+// the description supplied in MSPPrincipal
+func (msp *idemixmsp) SatisfiesPrincipal(id Identity, principal *m.MSPPrincipal) error {
+	err := msp.Validate(id)
+	if err != nil {
+		return fmt.Errorf("identity is not valid with respect to this MSP, err %s", err)
+	}
+	identity := id.(interface{ idemixIdentity() *idemixidentity }).idemixIdentity()
+
+	switch principal.PrincipalClassification {
+	case m.MSPPrincipal_ROLE:
+		// Principal contains the msp role
+		mspRole := &m.MSPRole{}
+		err := proto.Unmarshal(principal.Principal, mspRole)
+		if err != nil {
+			return fmt.Errorf("Could not unmarshal MSPRole from principal, err %s", err)
+		}
+
+		if mspRole.MspIdentifier != msp.name {
+			return fmt.Errorf("The identity is a member of a different MSP (expected %s, got %s)", mspRole.MspIdentifier, id.GetMSPIdentifier())
+		}
+
+		switch mspRole.Role {
+		case m.MSPRole_MEMBER:
+			// in the case of member, we simply check
+			// whether this identity is valid for the MSP
+			mspLogger.Debugf("Checking if identity satisfies MEMBER role for %s", msp.name)
+			return nil
+		case m.MSPRole_ADMIN, m.MSPRole_CLIENT, m.MSPRole_PEER, m.MSPRole_ORDERER:
+			mspLogger.Debugf("Checking if identity satisfies %s role for %s", mspRole.Role, msp.name)
+			if identity.role.Role != mspRole.Role {
+				return fmt.Errorf("user is not %s", mspRole.Role)
+			}
+			return nil
+		default:
+			return fmt.Errorf("Invalid MSP role type %d", int32(mspRole.Role))
+		}
+	case m.MSPPrincipal_IDENTITY:
+		principalId, err := msp.DeserializeIdentity(principal.Principal)
+		if err != nil {
+			return fmt.Errorf("Invalid identity principal, err %s", err)
+		}
+
+		if identity.Nym.Cmp(principalId.(*idemixidentity).Nym) == 0 {
+			return nil
+		}
+		return errors.New("The identities do not match")
+	case m.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &m.OrganizationUnit{}
+		err := proto.Unmarshal(principal.Principal, ou)
+		if err != nil {
+			return fmt.Errorf("Could not unmarshal OrganizationUnit from principal, err %s", err)
+		}
+
+		if ou.MspIdentifier != msp.name {
+			return fmt.Errorf("The identity is a member of a different MSP (expected %s, got %s)", ou.MspIdentifier, id.GetMSPIdentifier())
+		}
+
+		if ou.OrganizationalUnitIdentifier != identity.ou.OrganizationalUnitIdentifier ||
+			!bytes.Equal(ou.CertifiersIdentifier, msp.ipk.Hash) {
+			return errors.New("user is not part of the desired organizational unit")
+		}
+		return nil
+	default:
+		return fmt.Errorf("Invalid principal type %d", int32(principal.PrincipalClassification))
+	}
+}
+
+// idemixidentity is an identity of an idemix MSP: a pseudonym of the user,
+// together with the proof that the user owns a credential with the given
+// OU and role
+type idemixidentity struct {
+	Nym  *big.Int
+	msp  *idemixmsp
+	id   *IdentityIdentifier
+	role *m.MSPRole
+	ou   *m.OrganizationUnit
+	// associationProof contains cryptographic proof that this identity
+	// belongs to the MSP id.msp, i.e., it proves that the pseudonym
+	// is constructed from a secret key on which the CA issued a credential.
+	associationProof *idemix.Signature
+}
+
+func newIdemixIdentity(msp *idemixmsp, nym *big.Int, role *m.MSPRole, ou *m.OrganizationUnit, proof *idemix.Signature) *idemixidentity {
+	id := &idemixidentity{Nym: nym, msp: msp, role: role, ou: ou, associationProof: proof}
+
+	digest := sha256.Sum256(idemix.BigToBytes(nym))
+	id.id = &IdentityIdentifier{Mspid: msp.name, Id: hex.EncodeToString(digest[:])}
+
+	return id
+}
+
+func (id *idemixidentity) idemixIdentity() *idemixidentity {
+	return id
+}
+
+func (id *idemixidentity) GetIdentifier() *IdentityIdentifier {
+	return id.id
+}
+
+func (id *idemixidentity) GetMSPIdentifier() string {
+	mspid, _ := id.msp.GetIdentifier()
+	return mspid
+}
+
+func (id *idemixidentity) GetOrganizationalUnits() []*OUIdentifier {
+	// we use the (serialized) public key of this MSP as the CertifiersIdentifier
+	return []*OUIdentifier{{CertifiersIdentifier: id.msp.ipk.Hash, OrganizationalUnitIdentifier: id.ou.OrganizationalUnitIdentifier}}
+}
+
+func (id *idemixidentity) Validate() error {
+	return id.msp.Validate(id)
+}
+
+// verifyProof verifies the association proof of the identity, which binds its
+// pseudonym to a credential issued with its OU and role
+func (id *idemixidentity) verifyProof() error {
+	if id.ou.MspIdentifier != id.msp.name || id.role.MspIdentifier != id.msp.name {
+		return errors.New("the OU and the role of the identity must belong to its MSP")
+	}
+	if !bytes.Equal(id.ou.CertifiersIdentifier, id.msp.ipk.Hash) {
+		return errors.New("the OU of the identity is not certified by the issuer of this MSP")
+	}
+	proofNym, err := idemix.BigFromBytes(id.associationProof.Nym)
+	if err != nil || proofNym.Cmp(id.Nym) != 0 {
+		return errors.New("the proof of the identity is not bound to its pseudonym")
+	}
+
+	attributeValues := make([]*big.Int, len(AttributeNames))
+	attributeValues[AttributeIndexOU] = idemix.HashAttribute([]byte(id.ou.OrganizationalUnitIdentifier))
+	attributeValues[AttributeIndexRole] = big.NewInt(int64(id.role.Role))
+	return id.associationProof.Ver(discloseFlags, id.msp.ipk, nil, attributeValues)
+}
+
+func (id *idemixidentity) Verify(msg []byte, sig []byte) error {
+	if mspIdentityLogger.IsEnabledFor(logging.DEBUG) {
+		mspIdentityLogger.Debugf("Verify Idemix sig: msg = %s", hex.Dump(msg))
+		mspIdentityLogger.Debugf("Verify Idemix sig: sig = %s", hex.Dump(sig))
+	}
+
+	signature := new(idemix.NymSignature)
+	err := proto.Unmarshal(sig, signature)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling signature, err %s", err)
+	}
+	return signature.Ver(id.Nym, id.msp.ipk, msg)
+}
+
+func (id *idemixidentity) SatisfiesPrincipal(principal *m.MSPPrincipal) error {
+	return id.msp.SatisfiesPrincipal(id, principal)
+}
+
+func (id *idemixidentity) Serialize() ([]byte, error) {
+	serialized := &m.SerializedIdemixIdentity{Nym: idemix.BigToBytes(id.Nym)}
+
+	var err error
+	serialized.Ou, err = proto.Marshal(id.ou)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal OU of identity %s, err %s", id.id, err)
+	}
+	serialized.Role, err = proto.Marshal(id.role)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal role of identity %s, err %s", id.id, err)
+	}
+	serialized.Proof, err = proto.Marshal(id.associationProof)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal proof of identity %s, err %s", id.id, err)
+	}
+
+	idemixIDBytes, err := proto.Marshal(serialized)
+	if err != nil {
+		return nil, err
+	}
+
+	sID := &m.SerializedIdentity{Mspid: id.GetMSPIdentifier(), IdBytes: idemixIDBytes}
+	idBytes, err := proto.Marshal(sID)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal a SerializedIdentity structure for identity %s, err %s", id.id, err)
+	}
+
+	return idBytes, nil
+}
+
+// idemixSigningIdentity is the default signing identity of an idemix MSP,
+// which signs the messages with pseudonymous signatures
+type idemixSigningIdentity struct {
+	*idemixidentity
+	cred    *idemix.Credential
+	sk      *big.Int
+	randNym *big.Int
+}
+
+func (id *idemixSigningIdentity) Sign(msg []byte) ([]byte, error) {
+	mspLogger.Debugf("Idemix identity %s is signing", id.GetIdentifier())
+
+	sig, err := idemix.NewNymSignature(id.sk, id.Nym, id.randNym, id.msp.ipk, msg, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(sig)
+}
+
+func (id *idemixSigningIdentity) GetPublicVersion() Identity {
+	return id.idemixidentity
+}
@@ -0,0 +1,267 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupIdemixMSP(t *testing.T, dir string, ID string) MSP {
+	conf, err := GetIdemixMspConfig(filepath.Join("testdata/idemix", dir), ID)
+	assert.NoError(t, err)
+
+	thisMSP, err := New(IDEMIX)
+	assert.NoError(t, err)
+	assert.NoError(t, thisMSP.Setup(conf))
+	return thisMSP
+}
+
+// setupVerifierIdemixMSP sets up an idemix MSP without a default signer
+func setupVerifierIdemixMSP(t *testing.T, dir string, ID string) MSP {
+	conf, err := GetIdemixMspConfig(filepath.Join("testdata/idemix", dir), ID)
+	assert.NoError(t, err)
+
+	idemixConf := &msp.IdemixMSPConfig{}
+	assert.NoError(t, proto.Unmarshal(conf.Config, idemixConf))
+	idemixConf.Signer = nil
+	conf.Config, err = proto.Marshal(idemixConf)
+	assert.NoError(t, err)
+
+	thisMSP, err := NewIdemixMsp()
+	assert.NoError(t, err)
+	assert.NoError(t, thisMSP.Setup(conf))
+	return thisMSP
+}
+
+func getIdemixSigner(t *testing.T, thisMSP MSP) SigningIdentity {
+	id, err := thisMSP.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	return id
+}
+
+func TestIdemixSetup(t *testing.T) {
+	thisMSP := setupIdemixMSP(t, "MSP1OU1", "MSP1")
+
+	assert.Equal(t, IDEMIX, thisMSP.GetType())
+	mspID, err := thisMSP.GetIdentifier()
+	assert.NoError(t, err)
+	assert.Equal(t, "MSP1", mspID)
+	assert.Nil(t, thisMSP.GetTLSRootCerts())
+	assert.Nil(t, thisMSP.GetTLSIntermediateCerts())
+
+	id := getIdemixSigner(t, thisMSP)
+	assert.NoError(t, id.Validate())
+	assert.Equal(t, "MSP1", id.GetMSPIdentifier())
+	assert.Equal(t, "MSP1", id.GetIdentifier().Mspid)
+	assert.Len(t, id.GetOrganizationalUnits(), 1)
+	assert.Equal(t, "OU1", id.GetOrganizationalUnits()[0].OrganizationalUnitIdentifier)
+
+	_, err = thisMSP.GetSigningIdentity(id.GetIdentifier())
+	assert.Error(t, err)
+}
+
+func TestIdemixSetupBad(t *testing.T) {
+	thisMSP, err := NewIdemixMsp()
+	assert.NoError(t, err)
+
+	err = thisMSP.Setup(nil)
+	assert.Error(t, err)
+
+	conf, err := GetIdemixMspConfig("testdata/idemix/MSP1OU1", "MSP1")
+	assert.NoError(t, err)
+
+	conf.Type = int32(FABRIC)
+	err = thisMSP.Setup(conf)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not of type IDEMIX")
+
+	conf.Type = int32(IDEMIX)
+	idemixConf := &msp.IdemixMSPConfig{}
+	assert.NoError(t, proto.Unmarshal(conf.Config, idemixConf))
+
+	// a tampered issuer public key is rejected
+	badConf := *idemixConf
+	badConf.Ipk = append([]byte{}, idemixConf.Ipk...)
+	badConf.Ipk[len(badConf.Ipk)-1] ^= 1
+	conf.Config, err = proto.Marshal(&badConf)
+	assert.NoError(t, err)
+	assert.Error(t, thisMSP.Setup(conf))
+
+	// the signer must own a credential on its own attributes
+	badConf = *idemixConf
+	signer := *idemixConf.Signer
+	signer.OrganizationalUnitIdentifier = "OU2"
+	badConf.Signer = &signer
+	conf.Config, err = proto.Marshal(&badConf)
+	assert.NoError(t, err)
+	err = thisMSP.Setup(conf)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not contain the OU")
+
+	signer = *idemixConf.Signer
+	signer.Role = int32(msp.MSPRole_ADMIN)
+	badConf.Signer = &signer
+	conf.Config, err = proto.Marshal(&badConf)
+	assert.NoError(t, err)
+	assert.Error(t, thisMSP.Setup(conf))
+
+	_, err = GetIdemixMspConfig("testdata/idemix/nonexistent", "MSP1")
+	assert.Error(t, err)
+}
+
+func TestIdemixVerifierOnly(t *testing.T) {
+	thisMSP := setupVerifierIdemixMSP(t, "MSP1OU1", "MSP1")
+
+	_, err := thisMSP.GetDefaultSigningIdentity()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no default signer setup")
+}
+
+func TestIdemixSignVerify(t *testing.T) {
+	thisMSP := setupIdemixMSP(t, "MSP1OU1", "MSP1")
+	id := getIdemixSigner(t, thisMSP)
+
+	msg := []byte("TestMessage")
+	sig, err := id.Sign(msg)
+	assert.NoError(t, err)
+
+	assert.NoError(t, id.Verify(msg, sig))
+	assert.NoError(t, id.GetPublicVersion().Verify(msg, sig))
+
+	err = id.Verify([]byte("OtherMessage"), sig)
+	assert.Error(t, err)
+
+	err = id.Verify(msg, []byte("garbage"))
+	assert.Error(t, err)
+
+	// signatures of another user do not verify under this identity
+	otherID := getIdemixSigner(t, setupIdemixMSP(t, "MSP1OU2", "MSP1"))
+	otherSig, err := otherID.Sign(msg)
+	assert.NoError(t, err)
+	assert.Error(t, id.Verify(msg, otherSig))
+}
+
+func TestIdemixSerializeDeserialize(t *testing.T) {
+	signerMSP := setupIdemixMSP(t, "MSP1OU1", "MSP1")
+	verifierMSP := setupVerifierIdemixMSP(t, "MSP1OU1", "MSP1")
+	id := getIdemixSigner(t, signerMSP)
+
+	serialized, err := id.Serialize()
+	assert.NoError(t, err)
+
+	deserialized, err := verifierMSP.DeserializeIdentity(serialized)
+	assert.NoError(t, err)
+	assert.NoError(t, verifierMSP.Validate(deserialized))
+	assert.Equal(t, id.GetIdentifier(), deserialized.GetIdentifier())
+
+	msg := []byte("TestMessage")
+	sig, err := id.Sign(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, deserialized.Verify(msg, sig))
+
+	// the same user has unlinkable pseudonyms in different setups
+	otherID := getIdemixSigner(t, setupIdemixMSP(t, "MSP1OU1", "MSP1"))
+	assert.NotEqual(t, id.GetIdentifier(), otherID.GetIdentifier())
+
+	_, err = verifierMSP.DeserializeIdentity([]byte("garbage"))
+	assert.Error(t, err)
+
+	// the identity of another MSP ID is not accepted
+	_, err = setupVerifierIdemixMSP(t, "MSP1OU1", "MSP2").DeserializeIdentity(serialized)
+	assert.Error(t, err)
+}
+
+func TestIdemixValidateBad(t *testing.T) {
+	verifierMSP := setupVerifierIdemixMSP(t, "MSP1OU1", "MSP1")
+
+	// an identity certified by another issuer does not validate
+	foreignID := getIdemixSigner(t, setupIdemixMSP(t, "MSP2OU1", "MSP1"))
+	serialized, err := foreignID.Serialize()
+	assert.NoError(t, err)
+	deserialized, err := verifierMSP.DeserializeIdentity(serialized)
+	assert.NoError(t, err)
+	assert.Error(t, verifierMSP.Validate(deserialized))
+
+	// a member cannot claim to be an admin
+	id := getIdemixSigner(t, setupIdemixMSP(t, "MSP1OU1", "MSP1"))
+	serialized, err = id.Serialize()
+	assert.NoError(t, err)
+	sID := &msp.SerializedIdentity{}
+	assert.NoError(t, proto.Unmarshal(serialized, sID))
+	idemixID := &msp.SerializedIdemixIdentity{}
+	assert.NoError(t, proto.Unmarshal(sID.IdBytes, idemixID))
+	idemixID.Role, err = proto.Marshal(&msp.MSPRole{MspIdentifier: "MSP1", Role: msp.MSPRole_ADMIN})
+	assert.NoError(t, err)
+	sID.IdBytes, err = proto.Marshal(idemixID)
+	assert.NoError(t, err)
+	serialized, err = proto.Marshal(sID)
+	assert.NoError(t, err)
+
+	deserialized, err = verifierMSP.DeserializeIdentity(serialized)
+	assert.NoError(t, err)
+	assert.Error(t, deserialized.Validate())
+}
+
+func TestIdemixSatisfiesPrincipal(t *testing.T) {
+	verifierMSP := setupVerifierIdemixMSP(t, "MSP1OU1", "MSP1")
+	member := getIdemixSigner(t, setupIdemixMSP(t, "MSP1OU1", "MSP1"))
+	admin := getIdemixSigner(t, setupIdemixMSP(t, "MSP1OU1Admin", "MSP1"))
+
+	rolePrincipal := func(role msp.MSPRole_MSPRoleType, mspID string) *msp.MSPPrincipal {
+		principalBytes, err := proto.Marshal(&msp.MSPRole{Role: role, MspIdentifier: mspID})
+		assert.NoError(t, err)
+		return &msp.MSPPrincipal{PrincipalClassification: msp.MSPPrincipal_ROLE, Principal: principalBytes}
+	}
+
+	assert.NoError(t, verifierMSP.SatisfiesPrincipal(member, rolePrincipal(msp.MSPRole_MEMBER, "MSP1")))
+	assert.NoError(t, verifierMSP.SatisfiesPrincipal(admin, rolePrincipal(msp.MSPRole_MEMBER, "MSP1")))
+	assert.NoError(t, verifierMSP.SatisfiesPrincipal(admin, rolePrincipal(msp.MSPRole_ADMIN, "MSP1")))
+	assert.Error(t, verifierMSP.SatisfiesPrincipal(member, rolePrincipal(msp.MSPRole_ADMIN, "MSP1")))
+	assert.Error(t, verifierMSP.SatisfiesPrincipal(member, rolePrincipal(msp.MSPRole_PEER, "MSP1")))
+	assert.Error(t, verifierMSP.SatisfiesPrincipal(member, rolePrincipal(msp.MSPRole_MEMBER, "MSP2")))
+
+	ouPrincipal := func(ou string) *msp.MSPPrincipal {
+		principalBytes, err := proto.Marshal(&msp.OrganizationUnit{
+			MspIdentifier:                "MSP1",
+			OrganizationalUnitIdentifier: ou,
+			CertifiersIdentifier:         member.GetOrganizationalUnits()[0].CertifiersIdentifier,
+		})
+		assert.NoError(t, err)
+		return &msp.MSPPrincipal{PrincipalClassification: msp.MSPPrincipal_ORGANIZATION_UNIT, Principal: principalBytes}
+	}
+
+	assert.NoError(t, member.SatisfiesPrincipal(ouPrincipal("OU1")))
+	assert.Error(t, member.SatisfiesPrincipal(ouPrincipal("OU2")))
+	ou2Member := getIdemixSigner(t, setupIdemixMSP(t, "MSP1OU2", "MSP1"))
+	assert.NoError(t, ou2Member.SatisfiesPrincipal(ouPrincipal("OU2")))
+
+	serialized, err := member.Serialize()
+	assert.NoError(t, err)
+	identityPrincipal := &msp.MSPPrincipal{PrincipalClassification: msp.MSPPrincipal_IDENTITY, Principal: serialized}
+	assert.NoError(t, verifierMSP.SatisfiesPrincipal(member, identityPrincipal))
+	assert.Error(t, verifierMSP.SatisfiesPrincipal(admin, identityPrincipal))
+}
+
+func TestIdemixInMSPManager(t *testing.T) {
+	msp1 := setupVerifierIdemixMSP(t, "MSP1OU1", "MSP1")
+	msp2 := setupVerifierIdemixMSP(t, "MSP2OU1", "MSP2")
+	mgr := NewMSPManager()
+	assert.NoError(t, mgr.Setup([]MSP{msp1, msp2}))
+
+	id := getIdemixSigner(t, setupIdemixMSP(t, "MSP2OU1", "MSP2"))
+	serialized, err := id.Serialize()
+	assert.NoError(t, err)
+	deserialized, err := mgr.DeserializeIdentity(serialized)
+	assert.NoError(t, err)
+	assert.NoError(t, deserialized.Validate())
+	assert.Equal(t, "MSP2", deserialized.GetMSPIdentifier())
+}
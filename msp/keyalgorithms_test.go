@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMSPWithSM2(t *testing.T) {
+	// testdata/sm2 contains the credentials of a peer generated by cryptogen
+	// with the SM2 key algorithm: the CA and the signcert are signed with SM2
+	testMSPWithKeyAlgorithm(t, "testdata/sm2")
+}
+
+func TestMSPWithED25519(t *testing.T) {
+	// testdata/ed25519 contains the credentials of a peer generated by
+	// cryptogen with the ED25519 key algorithm
+	testMSPWithKeyAlgorithm(t, "testdata/ed25519")
+}
+
+func testMSPWithKeyAlgorithm(t *testing.T, dir string) {
+	thisMSP := getLocalMSP(t, dir)
+
+	id, err := thisMSP.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+
+	// ensure that we validate correctly the identity
+	err = thisMSP.Validate(id.GetPublicVersion())
+	assert.NoError(t, err)
+
+	// ensure that the identity signs and verifies correctly, also once
+	// serialized and deserialized
+	msg := []byte("foo")
+	sig, err := id.Sign(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, id.Verify(msg, sig))
+	assert.Error(t, id.Verify([]byte("bar"), sig))
+
+	serialized, err := id.Serialize()
+	assert.NoError(t, err)
+	deserialized, err := thisMSP.DeserializeIdentity(serialized)
+	assert.NoError(t, err)
+	assert.NoError(t, deserialized.Validate())
+	assert.NoError(t, deserialized.Verify(msg, sig))
+
+	// ensure that the identities of this MSP and of the local MSP
+	// are not valid for each other
+	err = localMsp.Validate(id.GetPublicVersion())
+	assert.Error(t, err)
+
+	localMSPID, err := localMsp.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	err = thisMSP.Validate(localMSPID.GetPublicVersion())
+	assert.Error(t, err)
+}
@@ -21,6 +21,7 @@ import (
 	"sync"
 
 	"errors"
+	"fmt"
 
 	"github.com/hyperledger/fabric/bccsp/factory"
 	configvaluesmsp "github.com/hyperledger/fabric/common/config/msp"
@@ -43,6 +44,44 @@ func LoadLocalMsp(dir string, bccspConfig *factory.FactoryOpts, mspID string) er
 	return GetLocalMSP().Setup(conf)
 }
 
+// LoadLocalMspWithType loads the local MSP of the given type ("bccsp" or
+// "idemix") from the specified directory
+func LoadLocalMspWithType(dir string, bccspConfig *factory.FactoryOpts, mspID, mspType string) error {
+	if mspID == "" {
+		return errors.New("The local MSP must have an ID")
+	}
+
+	providerType, err := msp.ProviderTypeFromString(mspType)
+	if err != nil {
+		return err
+	}
+
+	switch providerType {
+	case msp.FABRIC:
+		return LoadLocalMsp(dir, bccspConfig, mspID)
+	case msp.IDEMIX:
+		conf, err := msp.GetIdemixMspConfig(dir, mspID)
+		if err != nil {
+			return err
+		}
+
+		idemixMsp, err := msp.New(providerType)
+		if err != nil {
+			return err
+		}
+		if err = idemixMsp.Setup(conf); err != nil {
+			return err
+		}
+
+		m.Lock()
+		defer m.Unlock()
+		localMsp = idemixMsp
+		return nil
+	default:
+		return fmt.Errorf("unsupported local MSP type %s", mspType)
+	}
+}
+
 // Loads the development local MSP for use in testing.  Not valid for production/runtime context
 func LoadDevMsp() error {
 	mspDir, err := config.GetDevMspDir()
@@ -68,3 +68,25 @@ func TestGetLocalSigningIdentityOrPanic(t *testing.T) {
 	sid := GetLocalSigningIdentityOrPanic()
 	assert.NotNil(t, sid)
 }
+
+func TestLoadLocalMspWithType(t *testing.T) {
+	m.Lock()
+	prevMsp := localMsp
+	m.Unlock()
+	defer func() {
+		m.Lock()
+		localMsp = prevMsp
+		m.Unlock()
+	}()
+
+	err := LoadLocalMspWithType("../testdata/idemix/MSP1OU1", nil, "", "idemix")
+	assert.Error(t, err)
+	err = LoadLocalMspWithType("../testdata/idemix/MSP1OU1", nil, "MSP1", "unknown")
+	assert.Error(t, err)
+
+	err = LoadLocalMspWithType("../testdata/idemix/MSP1OU1", nil, "MSP1", "idemix")
+	assert.NoError(t, err)
+	assert.Equal(t, msp.IDEMIX, GetLocalMSP().GetType())
+	sid := GetLocalSigningIdentityOrPanic()
+	assert.Equal(t, "MSP1", sid.GetMSPIdentifier())
+}
@@ -15,7 +15,6 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
-	"math/big"
 	"reflect"
 	"time"
 
@@ -23,6 +22,8 @@ import (
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/bccsp/signer"
+	"github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric/common/crl"
 	m "github.com/hyperledger/fabric/protos/msp"
 )
 
@@ -62,12 +63,28 @@ type bccspmsp struct {
 	// verification options for MSP members
 	opts *x509.VerifyOptions
 
-	// list of certificate revocation lists
-	CRL []*pkix.CertificateList
+	// sm2Roots and sm2Intermediates hold the certificates of opts, to
+	// verify the members whose certificates are signed with SM2
+	sm2Roots         *utils.SM2CertPool
+	sm2Intermediates *utils.SM2CertPool
+
+	// certificate revocation lists of the configuration, indexed by the
+	// Subject Key Identifier of the CA which issued and signed them
+	crls map[string][]*pkix.CertificateList
 
 	// list of OUs
 	ouIdentifiers map[string][][]byte
 
+	// NodeOUs configuration
+	nodeOUs bool
+
+	// clientOU, peerOU, adminOU and ordererOU are the OU identifiers
+	// of the node roles, set when NodeOUs are enabled
+	clientOU  *OUIdentifier
+	peerOU    *OUIdentifier
+	adminOU   *OUIdentifier
+	ordererOU *OUIdentifier
+
 	// cryptoConfig contains
 	cryptoConfig *m.FabricCryptoConfig
 }
@@ -99,7 +116,7 @@ func (msp *bccspmsp) getCertFromPem(idBytes []byte) (*x509.Certificate, error) {
 
 	// get a cert
 	var cert *x509.Certificate
-	cert, err := x509.ParseCertificate(pemCert.Bytes)
+	cert, err := utils.DERToX509Certificate(pemCert.Bytes)
 	if err != nil {
 		return nil, fmt.Errorf("getIdentityFromBytes error: failed to parse x509 cert, err %s", err)
 	}
@@ -191,49 +208,6 @@ func (msp *bccspmsp) getSigningIdentityFromConf(sidInfo *m.SigningIdentityInfo)
 	return newSigningIdentity(id, idPub.(*identity).cert, idPub.(*identity).pk, peerSigner, msp)
 }
 
-/*
-   This is the definition of the ASN.1 marshalling of AuthorityKeyIdentifier
-   from https://www.ietf.org/rfc/rfc5280.txt
-
-   AuthorityKeyIdentifier ::= SEQUENCE {
-      keyIdentifier             [0] KeyIdentifier           OPTIONAL,
-      authorityCertIssuer       [1] GeneralNames            OPTIONAL,
-      authorityCertSerialNumber [2] CertificateSerialNumber OPTIONAL  }
-
-   KeyIdentifier ::= OCTET STRING
-
-   CertificateSerialNumber  ::=  INTEGER
-
-*/
-
-type authorityKeyIdentifier struct {
-	KeyIdentifier             []byte  `asn1:"optional,tag:0"`
-	AuthorityCertIssuer       []byte  `asn1:"optional,tag:1"`
-	AuthorityCertSerialNumber big.Int `asn1:"optional,tag:2"`
-}
-
-// getAuthorityKeyIdentifierFromCrl returns the Authority Key Identifier
-// for the supplied CRL. The authority key identifier can be used to identify
-// the public key corresponding to the private key which was used to sign the CRL.
-func getAuthorityKeyIdentifierFromCrl(crl *pkix.CertificateList) ([]byte, error) {
-	aki := authorityKeyIdentifier{}
-
-	for _, ext := range crl.TBSCertList.Extensions {
-		// Authority Key Identifier is identified by the following ASN.1 tag
-		// authorityKeyIdentifier (2 5 29 35) (see https://tools.ietf.org/html/rfc3280.html)
-		if reflect.DeepEqual(ext.Id, asn1.ObjectIdentifier{2, 5, 29, 35}) {
-			_, err := asn1.Unmarshal(ext.Value, &aki)
-			if err != nil {
-				return nil, fmt.Errorf("Failed to unmarshal AKI, error %s", err)
-			}
-
-			return aki.KeyIdentifier, nil
-		}
-	}
-
-	return nil, errors.New("authorityKeyIdentifier not found in certificate")
-}
-
 // getSubjectKeyIdentifierFromCert returns the Subject Key Identifier for the supplied certificate
 // Subject Key Identifier is an identifier of the public key of this certificate
 func getSubjectKeyIdentifierFromCert(cert *x509.Certificate) ([]byte, error) {
@@ -325,6 +299,11 @@ func (msp *bccspmsp) Setup(conf1 *m.MSPConfig) error {
 		return err
 	}
 
+	// setup the NodeOUs
+	if err := msp.setupNodeOUs(conf); err != nil {
+		return err
+	}
+
 	// setup TLS CAs
 	if err := msp.setupTLSCAs(conf); err != nil {
 		return err
@@ -427,7 +406,7 @@ func (msp *bccspmsp) deserializeIdentityInternal(serializedIdentity []byte) (Ide
 	if bl == nil {
 		return nil, fmt.Errorf("Could not decode the PEM structure")
 	}
-	cert, err := x509.ParseCertificate(bl.Bytes)
+	cert, err := utils.DERToX509Certificate(bl.Bytes)
 	if err != nil {
 		return nil, fmt.Errorf("ParseCertificate failed %s", err)
 	}
@@ -466,7 +445,7 @@ func (msp *bccspmsp) deserializeIdentityInternal(serializedIdentity []byte) (Ide
 func (msp *bccspmsp) SatisfiesPrincipal(id Identity, principal *m.MSPPrincipal) error {
 	switch principal.PrincipalClassification {
 	// in this case, we have to check whether the
-	// identity has a role in the msp - member or admin
+	// identity has a role in the msp - member, admin, client, peer or orderer
 	case m.MSPPrincipal_ROLE:
 		// Principal contains the msp role
 		mspRole := &m.MSPRole{}
@@ -501,7 +480,21 @@ func (msp *bccspmsp) SatisfiesPrincipal(id Identity, principal *m.MSPPrincipal)
 				}
 			}
 
+			// with NodeOUs, the identities carrying the admin OU are admins as well
+			if msp.nodeOUs && msp.adminOU != nil {
+				return msp.satisfiesNodeRole(id, "admin", msp.adminOU)
+			}
+
 			return errors.New("This identity is not an admin")
+		case m.MSPRole_CLIENT:
+			mspLogger.Debugf("Checking if identity satisfies CLIENT role for %s", msp.name)
+			return msp.satisfiesNodeRole(id, "client", msp.clientOU)
+		case m.MSPRole_PEER:
+			mspLogger.Debugf("Checking if identity satisfies PEER role for %s", msp.name)
+			return msp.satisfiesNodeRole(id, "peer", msp.peerOU)
+		case m.MSPRole_ORDERER:
+			mspLogger.Debugf("Checking if identity satisfies ORDERER role for %s", msp.name)
+			return msp.satisfiesNodeRole(id, "orderer", msp.ordererOU)
 		default:
 			return fmt.Errorf("Invalid MSP role type %d", int32(mspRole.Role))
 		}
@@ -593,7 +586,13 @@ func (msp *bccspmsp) getUniqueValidationChain(cert *x509.Certificate, opts x509.
 	if msp.opts == nil {
 		return nil, fmt.Errorf("The supplied identity has no verify options")
 	}
-	validationChains, err := cert.Verify(opts)
+	var validationChains [][]*x509.Certificate
+	var err error
+	if utils.IsSM2SignedCertificate(cert) {
+		validationChains, err = utils.VerifySM2Certificate(cert, msp.sm2Roots, msp.sm2Intermediates, opts)
+	} else {
+		validationChains, err = cert.Verify(opts)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("The supplied identity is not valid, Verify() returned %s", err)
 	}
@@ -696,19 +695,24 @@ func (msp *bccspmsp) setupCAs(conf *m.FabricMSPConfig) error {
 	// CA certificates. After their sanitization is done, the opts
 	// will be recreated using the sanitized certs.
 	msp.opts = &x509.VerifyOptions{Roots: x509.NewCertPool(), Intermediates: x509.NewCertPool()}
+	msp.sm2Roots, msp.sm2Intermediates = utils.NewSM2CertPool(), utils.NewSM2CertPool()
 	for _, v := range conf.RootCerts {
 		cert, err := msp.getCertFromPem(v)
 		if err != nil {
 			return err
 		}
-		msp.opts.Roots.AddCert(cert)
+		if err := msp.addRootCert(cert); err != nil {
+			return err
+		}
 	}
 	for _, v := range conf.IntermediateCerts {
 		cert, err := msp.getCertFromPem(v)
 		if err != nil {
 			return err
 		}
-		msp.opts.Intermediates.AddCert(cert)
+		if err := msp.addIntermediateCert(cert); err != nil {
+			return err
+		}
 	}
 
 	// Load root and intermediate CA identities
@@ -736,11 +740,16 @@ func (msp *bccspmsp) setupCAs(conf *m.FabricMSPConfig) error {
 
 	// root CA and intermediate CA certificates are sanitized, they can be reimported
 	msp.opts = &x509.VerifyOptions{Roots: x509.NewCertPool(), Intermediates: x509.NewCertPool()}
+	msp.sm2Roots, msp.sm2Intermediates = utils.NewSM2CertPool(), utils.NewSM2CertPool()
 	for _, id := range msp.rootCerts {
-		msp.opts.Roots.AddCert(id.(*identity).cert)
+		if err := msp.addRootCert(id.(*identity).cert); err != nil {
+			return err
+		}
 	}
 	for _, id := range msp.intermediateCerts {
-		msp.opts.Intermediates.AddCert(id.(*identity).cert)
+		if err := msp.addIntermediateCert(id.(*identity).cert); err != nil {
+			return err
+		}
 	}
 
 	// make and fill the set of admin certs (if present)
@@ -772,21 +781,68 @@ func (msp *bccspmsp) setupAdmins(conf *m.FabricMSPConfig) error {
 	return nil
 }
 
+// addRootCert adds the root CA certificate to the verification options
+func (msp *bccspmsp) addRootCert(cert *x509.Certificate) error {
+	msp.opts.Roots.AddCert(cert)
+	if utils.IsSM2Certificate(cert) {
+		return msp.sm2Roots.AddCert(cert)
+	}
+	return nil
+}
+
+// addIntermediateCert adds the intermediate CA certificate to the verification options
+func (msp *bccspmsp) addIntermediateCert(cert *x509.Certificate) error {
+	msp.opts.Intermediates.AddCert(cert)
+	if utils.IsSM2Certificate(cert) {
+		return msp.sm2Intermediates.AddCert(cert)
+	}
+	return nil
+}
+
 func (msp *bccspmsp) setupCRLs(conf *m.FabricMSPConfig) error {
-	// setup the CRL (if present)
-	msp.CRL = make([]*pkix.CertificateList, len(conf.RevocationList))
-	for i, crlbytes := range conf.RevocationList {
-		crl, err := x509.ParseCRL(crlbytes)
+	// the CAs which may sign the CRLs; the TLS CAs are not set up yet
+	var cas []*x509.Certificate
+	for _, id := range append(append([]Identity{}, msp.rootCerts...), msp.intermediateCerts...) {
+		cas = append(cas, id.(*identity).cert)
+	}
+	for _, pemCert := range append(append([][]byte{}, conf.TlsRootCerts...), conf.TlsIntermediateCerts...) {
+		cert, err := msp.getCertFromPem(pemCert)
+		if err != nil {
+			return err
+		}
+		cas = append(cas, cert)
+	}
+
+	// setup the CRL (if present), after verifying that it was signed by
+	// the CA it was issued by, so that upon validation the CRLs can be
+	// looked up by the CA which signed the certificate to be validated
+	msp.crls = make(map[string][]*pkix.CertificateList)
+	for _, crlbytes := range conf.RevocationList {
+		list, err := x509.ParseCRL(crlbytes)
 		if err != nil {
 			return fmt.Errorf("Could not parse RevocationList, err %s", err)
 		}
 
-		// TODO: pre-verify the signature on the CRL and create a map
-		//       of CA certs to respective CRLs so that later upon
-		//       validation we can already look up the CRL given the
-		//       chain of the certificate to be validated
+		aki, err := crl.AuthorityKeyIdentifier(list)
+		if err != nil {
+			return fmt.Errorf("Could not obtain Authority Key Identifier for crl, err %s", err)
+		}
 
-		msp.CRL[i] = crl
+		signed := false
+		for _, ca := range cas {
+			if bytes.Equal(ca.SubjectKeyId, aki) && utils.CheckCRLSignature(ca, list) == nil {
+				signed = true
+				break
+			}
+		}
+		if !signed {
+			// the CRL cannot revoke any certificate, as none of our
+			// CAs signed it - skip
+			mspLogger.Warningf("Ignoring the CRL issued by %s, which was not signed by a CA of MSP %s", list.TBSCertList.Issuer, msp.name)
+			continue
+		}
+
+		msp.crls[string(aki)] = append(msp.crls[string(aki)], list)
 	}
 
 	return nil
@@ -839,62 +895,13 @@ func (msp *bccspmsp) setupOUs(conf *m.FabricMSPConfig) error {
 	msp.ouIdentifiers = make(map[string][][]byte)
 	for _, ou := range conf.OrganizationalUnitIdentifiers {
 
-		// 1. check that certificate is registered in msp.rootCerts or msp.intermediateCerts
-		cert, err := msp.getCertFromPem(ou.Certificate)
+		certifiersIdentitifer, err := msp.getCertifiersIdentifier(ou.Certificate)
 		if err != nil {
 			return fmt.Errorf("Failed getting certificate for [%v]: [%s]", ou, err)
 		}
 
-		// 2. Sanitize it to ensure like for like comparison
-		cert, err = msp.sanitizeCert(cert)
-		if err != nil {
-			return fmt.Errorf("sanitizeCert failed %s", err)
-		}
-
-		found := false
-		root := false
-		// Search among root certificates
-		for _, v := range msp.rootCerts {
-			if v.(*identity).cert.Equal(cert) {
-				found = true
-				root = true
-				break
-			}
-		}
-		if !found {
-			// Search among root intermediate certificates
-			for _, v := range msp.intermediateCerts {
-				if v.(*identity).cert.Equal(cert) {
-					found = true
-					break
-				}
-			}
-		}
-		if !found {
-			// Certificate not valid, reject configuration
-			return fmt.Errorf("Failed adding OU. Certificate [%v] not in root or intermediate certs.", ou.Certificate)
-		}
-
-		// 3. get the certification path for it
-		var certifiersIdentitifer []byte
-		var chain []*x509.Certificate
-		if root {
-			chain = []*x509.Certificate{cert}
-		} else {
-			chain, err = msp.getValidationChain(cert, true)
-			if err != nil {
-				return fmt.Errorf("Failed computing validation chain for [%v]. [%s]", cert, err)
-			}
-		}
-
-		// 4. compute the hash of the certification path
-		certifiersIdentitifer, err = msp.getCertificationChainIdentifierFromChain(chain)
-		if err != nil {
-			return fmt.Errorf("Failed computing Certifiers Identifier for [%v]. [%s]", ou.Certificate, err)
-		}
-
 		// Check for duplicates
-		found = false
+		found := false
 		for _, id := range msp.ouIdentifiers[ou.OrganizationalUnitIdentifier] {
 			if bytes.Equal(id, certifiersIdentitifer) {
 				mspLogger.Warningf("Duplicate found in ou identifiers [%s, %v]", ou.OrganizationalUnitIdentifier, id)
@@ -915,6 +922,115 @@ func (msp *bccspmsp) setupOUs(conf *m.FabricMSPConfig) error {
 	return nil
 }
 
+// getCertifiersIdentifier returns the identifier of the certification chain
+// of a root or intermediate certificate of this MSP
+func (msp *bccspmsp) getCertifiersIdentifier(certRaw []byte) ([]byte, error) {
+	// 1. check that certificate is registered in msp.rootCerts or msp.intermediateCerts
+	cert, err := msp.getCertFromPem(certRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Sanitize it to ensure like for like comparison
+	cert, err = msp.sanitizeCert(cert)
+	if err != nil {
+		return nil, fmt.Errorf("sanitizeCert failed %s", err)
+	}
+
+	found := false
+	root := false
+	// Search among root certificates
+	for _, v := range msp.rootCerts {
+		if v.(*identity).cert.Equal(cert) {
+			found = true
+			root = true
+			break
+		}
+	}
+	if !found {
+		// Search among root intermediate certificates
+		for _, v := range msp.intermediateCerts {
+			if v.(*identity).cert.Equal(cert) {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		// Certificate not valid, reject configuration
+		return nil, fmt.Errorf("Failed adding OU. Certificate [%v] not in root or intermediate certs.", certRaw)
+	}
+
+	// 3. get the certification path for it
+	var chain []*x509.Certificate
+	if root {
+		chain = []*x509.Certificate{cert}
+	} else {
+		chain, err = msp.getValidationChain(cert, true)
+		if err != nil {
+			return nil, fmt.Errorf("Failed computing validation chain for [%v]. [%s]", cert, err)
+		}
+	}
+
+	// 4. compute the hash of the certification path
+	certifiersIdentifier, err := msp.getCertificationChainIdentifierFromChain(chain)
+	if err != nil {
+		return nil, fmt.Errorf("Failed computing Certifiers Identifier for [%v]. [%s]", certRaw, err)
+	}
+
+	return certifiersIdentifier, nil
+}
+
+// setupNodeOUs sets up the OU identifiers of the node roles. The certifiers
+// identifier of a node OU is left empty when no certificate is given, in which
+// case any certification chain of the MSP is accepted for that OU
+func (msp *bccspmsp) setupNodeOUs(conf *m.FabricMSPConfig) error {
+	if conf.FabricNodeOus == nil || !conf.FabricNodeOus.Enable {
+		msp.nodeOUs = false
+		return nil
+	}
+	msp.nodeOUs = true
+
+	var err error
+	if msp.clientOU, err = msp.getNodeOUIdentifier(conf.FabricNodeOus.ClientOuIdentifier); err != nil {
+		return fmt.Errorf("Failed setting up client OU: [%s]", err)
+	}
+	if msp.peerOU, err = msp.getNodeOUIdentifier(conf.FabricNodeOus.PeerOuIdentifier); err != nil {
+		return fmt.Errorf("Failed setting up peer OU: [%s]", err)
+	}
+	if msp.adminOU, err = msp.getNodeOUIdentifier(conf.FabricNodeOus.AdminOuIdentifier); err != nil {
+		return fmt.Errorf("Failed setting up admin OU: [%s]", err)
+	}
+	if msp.ordererOU, err = msp.getNodeOUIdentifier(conf.FabricNodeOus.OrdererOuIdentifier); err != nil {
+		return fmt.Errorf("Failed setting up orderer OU: [%s]", err)
+	}
+	if msp.clientOU == nil && msp.peerOU == nil && msp.adminOU == nil && msp.ordererOU == nil {
+		return errors.New("NodeOUs are enabled but no node OU identifier is set")
+	}
+
+	return nil
+}
+
+func (msp *bccspmsp) getNodeOUIdentifier(ou *m.FabricOUIdentifier) (*OUIdentifier, error) {
+	if ou == nil {
+		return nil, nil
+	}
+	if ou.OrganizationalUnitIdentifier == "" {
+		return nil, errors.New("the organizational unit identifier is empty")
+	}
+
+	nodeOU := &OUIdentifier{OrganizationalUnitIdentifier: ou.OrganizationalUnitIdentifier}
+	if len(ou.Certificate) != 0 {
+		certifiersIdentifier, err := msp.getCertifiersIdentifier(ou.Certificate)
+		if err != nil {
+			return nil, err
+		}
+		nodeOU.CertifiersIdentifier = certifiersIdentifier
+	}
+
+	return nodeOU, nil
+}
+
 func (msp *bccspmsp) setupTLSCAs(conf *m.FabricMSPConfig) error {
 
 	opts := &x509.VerifyOptions{Roots: x509.NewCertPool(), Intermediates: x509.NewCertPool()}
@@ -957,6 +1073,11 @@ func (msp *bccspmsp) setupTLSCAs(conf *m.FabricMSPConfig) error {
 			return fmt.Errorf("CA Certificate did not have the Subject Key Identifier extension, (SN: %s)", cert.SerialNumber)
 		}
 
+		// TLS does not support SM2, so neither do the TLS CAs
+		if utils.IsSM2SignedCertificate(cert) {
+			return fmt.Errorf("CA Certificate is signed with SM2, which TLS does not support, (SN: %s)", cert.SerialNumber)
+		}
+
 		if err := msp.validateTLSCAIdentity(cert, opts); err != nil {
 			return fmt.Errorf("CA Certificate is not valid, (SN: %s) [%s]", cert.SerialNumber, err)
 		}
@@ -1070,46 +1191,24 @@ func (msp *bccspmsp) validateCertAgainstChain(cert *x509.Certificate, validation
 		return fmt.Errorf("Could not obtain Subject Key Identifier for signer cert, err %s", err)
 	}
 
-	// check whether one of the CRLs we have has this cert's
-	// SKI as its AuthorityKeyIdentifier
-	for _, crl := range msp.CRL {
-		aki, err := getAuthorityKeyIdentifierFromCrl(crl)
-		if err != nil {
-			return fmt.Errorf("Could not obtain Authority Key Identifier for crl, err %s", err)
-		}
-
-		// check if the SKI of the cert that signed us matches the AKI of any of the CRLs
-		if bytes.Equal(aki, SKI) {
-			// we have a CRL, check whether the serial number is revoked
-			for _, rc := range crl.TBSCertList.RevokedCertificates {
-				if rc.SerialNumber.Cmp(cert.SerialNumber) == 0 {
-					// We have found a CRL whose AKI matches the SKI of
-					// the CA (root or intermediate) that signed the
-					// certificate that is under validation. As a
-					// precaution, we verify that said CA is also the
-					// signer of this CRL.
-					err = validationChain[1].CheckCRLSignature(crl)
-					if err != nil {
-						// the CA cert that signed the certificate
-						// that is under validation did not sign the
-						// candidate CRL - skip
-						mspLogger.Warningf("Invalid signature over the identified CRL, error %s", err)
-						continue
-					}
-
-					// A CRL also includes a time of revocation so that
-					// the CA can say "this cert is to be revoked starting
-					// from this time"; however here we just assume that
-					// revocation applies instantaneously from the time
-					// the MSP config is committed and used so we will not
-					// make use of that field
-					return errors.New("The certificate has been revoked")
-				}
-			}
+	// check whether one of the CRLs of the CA that signed us, whose
+	// signature was verified upon setup, revokes this cert
+	for _, list := range msp.crls[string(SKI)] {
+		if crl.IsRevoked(list, cert) {
+			// A CRL also includes a time of revocation so that
+			// the CA can say "this cert is to be revoked starting
+			// from this time"; however here we just assume that
+			// revocation applies instantaneously from the time
+			// the MSP config is committed and used so we will not
+			// make use of that field
+			return errors.New("The certificate has been revoked")
 		}
 	}
 
-	return nil
+	// check the CRLs local to the node, which may be updated at any time
+	// without a config update, against the whole chain since they may revoke
+	// an intermediate CA validated at setup
+	return crl.LocalStore().CheckChain(validationChain)
 }
 
 func (msp *bccspmsp) validateIdentityOUs(id *identity) error {
@@ -1139,6 +1238,55 @@ func (msp *bccspmsp) validateIdentityOUs(id *identity) error {
 		}
 	}
 
+	if !msp.nodeOUs {
+		return nil
+	}
+
+	// Check that the identity has exactly one of the node roles
+	counter := 0
+	for _, nodeOU := range []*OUIdentifier{msp.clientOU, msp.peerOU, msp.adminOU, msp.ordererOU} {
+		if msp.hasNodeOU(id, nodeOU) {
+			counter++
+		}
+	}
+	if counter != 1 {
+		return fmt.Errorf("The identity must be a client, a peer, an admin or an orderer identity to be valid, not a combination of them. OUs: [%v], MSP: [%s]", id.GetOrganizationalUnits(), msp.name)
+	}
+
+	return nil
+}
+
+// hasNodeOU returns true if the identity carries the given node OU
+func (msp *bccspmsp) hasNodeOU(id Identity, nodeOU *OUIdentifier) bool {
+	if nodeOU == nil {
+		return false
+	}
+	for _, OU := range id.GetOrganizationalUnits() {
+		if OU.OrganizationalUnitIdentifier != nodeOU.OrganizationalUnitIdentifier {
+			continue
+		}
+		if len(nodeOU.CertifiersIdentifier) == 0 || bytes.Equal(OU.CertifiersIdentifier, nodeOU.CertifiersIdentifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// satisfiesNodeRole checks that the identity is valid and carries the OU of
+// the given node role
+func (msp *bccspmsp) satisfiesNodeRole(id Identity, role string, nodeOU *OUIdentifier) error {
+	if !msp.nodeOUs {
+		return fmt.Errorf("NodeOUs not activated. Cannot tell apart identities with the %s role", role)
+	}
+	if nodeOU == nil {
+		return fmt.Errorf("The %s OU is not defined in MSP %s", role, msp.name)
+	}
+	if err := msp.Validate(id); err != nil {
+		return fmt.Errorf("The identity is not valid under this MSP [%s]: %s", msp.name, err)
+	}
+	if !msp.hasNodeOU(id, nodeOU) {
+		return fmt.Errorf("The identity is not a %s under this MSP [%s]", role, msp.name)
+	}
 	return nil
 }
 
@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+)
+
+func getNodeOUsIdentity(t *testing.T, thisMSP MSP, name string) Identity {
+	raw, err := ioutil.ReadFile(filepath.Join("testdata/nodeous/users", name))
+	assert.NoError(t, err)
+	sid, err := proto.Marshal(&msp.SerializedIdentity{Mspid: "DEFAULT", IdBytes: raw})
+	assert.NoError(t, err)
+	id, err := thisMSP.DeserializeIdentity(sid)
+	assert.NoError(t, err)
+	return id
+}
+
+func rolePrincipal(t *testing.T, role msp.MSPRole_MSPRoleType) *msp.MSPPrincipal {
+	principalBytes, err := proto.Marshal(&msp.MSPRole{Role: role, MspIdentifier: "DEFAULT"})
+	assert.NoError(t, err)
+	return &msp.MSPPrincipal{PrincipalClassification: msp.MSPPrincipal_ROLE, Principal: principalBytes}
+}
+
+func TestNodeOUsConfig(t *testing.T) {
+	conf, err := GetLocalMspConfig("testdata/nodeous", nil, "DEFAULT")
+	assert.NoError(t, err)
+
+	fabricConf := &msp.FabricMSPConfig{}
+	assert.NoError(t, proto.Unmarshal(conf.Config, fabricConf))
+	assert.True(t, fabricConf.FabricNodeOus.Enable)
+	assert.Equal(t, "client", fabricConf.FabricNodeOus.ClientOuIdentifier.OrganizationalUnitIdentifier)
+	assert.Equal(t, "peer", fabricConf.FabricNodeOus.PeerOuIdentifier.OrganizationalUnitIdentifier)
+	assert.Equal(t, "admin", fabricConf.FabricNodeOus.AdminOuIdentifier.OrganizationalUnitIdentifier)
+	assert.Equal(t, "orderer", fabricConf.FabricNodeOus.OrdererOuIdentifier.OrganizationalUnitIdentifier)
+	assert.NotEmpty(t, fabricConf.FabricNodeOus.PeerOuIdentifier.Certificate)
+	assert.Empty(t, fabricConf.FabricNodeOus.OrdererOuIdentifier.Certificate)
+
+	// a node OU bound to a certificate which is not a CA of the MSP is rejected
+	fabricConf.FabricNodeOus.ClientOuIdentifier.Certificate = fabricConf.SigningIdentity.PublicSigner
+	conf.Config, err = proto.Marshal(fabricConf)
+	assert.NoError(t, err)
+	thisMSP, err := NewBccspMsp()
+	assert.NoError(t, err)
+	assert.Error(t, thisMSP.Setup(conf))
+
+	// enabling NodeOUs without any node OU is rejected
+	fabricConf.FabricNodeOus = &msp.FabricNodeOUs{Enable: true}
+	conf.Config, err = proto.Marshal(fabricConf)
+	assert.NoError(t, err)
+	thisMSP, err = NewBccspMsp()
+	assert.NoError(t, err)
+	assert.Error(t, thisMSP.Setup(conf))
+}
+
+func TestNodeOUsRoles(t *testing.T) {
+	thisMSP := getLocalMSP(t, "testdata/nodeous")
+
+	// the signing identity is a peer
+	peer, err := thisMSP.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	assert.NoError(t, peer.Validate())
+	assert.NoError(t, peer.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_MEMBER)))
+	assert.NoError(t, peer.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_PEER)))
+	assert.Error(t, peer.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_CLIENT)))
+	assert.Error(t, peer.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_ADMIN)))
+
+	client := getNodeOUsIdentity(t, thisMSP, "client.pem")
+	assert.NoError(t, client.Validate())
+	assert.NoError(t, client.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_CLIENT)))
+	assert.Error(t, client.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_PEER)))
+	assert.Error(t, client.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_ORDERER)))
+
+	// the orderer OU is not bound to a chain of trust
+	orderer := getNodeOUsIdentity(t, thisMSP, "orderer.pem")
+	assert.NoError(t, orderer.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_ORDERER)))
+	assert.Error(t, orderer.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_PEER)))
+
+	// an identity carrying the admin OU is an admin, even if not listed in the admincerts
+	admin := getNodeOUsIdentity(t, thisMSP, "admin.pem")
+	assert.NoError(t, admin.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_ADMIN)))
+	assert.Error(t, admin.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_CLIENT)))
+
+	// the identities must carry exactly one of the node OUs
+	noNode := getNodeOUsIdentity(t, thisMSP, "nonode.pem")
+	assert.Error(t, noNode.Validate())
+	assert.Error(t, noNode.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_MEMBER)))
+	clientPeer := getNodeOUsIdentity(t, thisMSP, "clientpeer.pem")
+	assert.Error(t, clientPeer.Validate())
+	assert.Error(t, clientPeer.SatisfiesPrincipal(rolePrincipal(t, msp.MSPRole_PEER)))
+}
+
+func TestNodeOUsDisabled(t *testing.T) {
+	thisMSP := getLocalMSP(t, "testdata/external")
+
+	id, err := thisMSP.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	for _, role := range []msp.MSPRole_MSPRoleType{msp.MSPRole_CLIENT, msp.MSPRole_PEER, msp.MSPRole_ORDERER} {
+		err = id.SatisfiesPrincipal(rolePrincipal(t, role))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "NodeOUs not activated")
+	}
+}
@@ -20,7 +20,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/crl"
 	"github.com/hyperledger/fabric/protos/msp"
 	"github.com/op/go-logging"
 	"github.com/stretchr/testify/assert"
@@ -99,3 +101,74 @@ func TestRevokedIntermediateCA(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "CA Certificate is not valid, ")
 }
+
+func TestCRLSignatureVerifiedOnSetup(t *testing.T) {
+	// the CRL of testdata/revocation is signed by the CA which issued it
+	thisMSP := getLocalMSP(t, "testdata/revocation")
+	assert.Len(t, thisMSP.(*bccspmsp).crls, 1)
+
+	// the signature on the CRL of testdata/revocation2 is invalid, so the
+	// CRL is discarded
+	thisMSP = getLocalMSP(t, "testdata/revocation2")
+	assert.Empty(t, thisMSP.(*bccspmsp).crls)
+}
+
+func TestLocalCRLs(t *testing.T) {
+	// testdata/revocation without the revocation list of its configuration
+	dir := "testdata/revocation"
+	thisMSP := getLocalMSPWithoutCRLs(t, dir)
+
+	id, err := thisMSP.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	assert.NoError(t, id.Validate())
+
+	defer crl.LocalStore().Update(nil)
+
+	// the local CRL revokes the identity without a config update
+	assert.NoError(t, crl.LocalStore().LoadDir(filepath.Join(dir, "crls")))
+	assert.Error(t, id.Validate(), "Identity not found revoked by the local CRL")
+
+	// the local CRL with an invalid signature is ignored
+	assert.NoError(t, crl.LocalStore().LoadDir("testdata/revocation2/crls"))
+	assert.NoError(t, id.Validate(), "Identity found revoked although the signature over the CRL is invalid")
+
+	assert.NoError(t, crl.LocalStore().Update(nil))
+	assert.NoError(t, id.Validate())
+}
+
+func TestLocalCRLsRevokedIntermediateCA(t *testing.T) {
+	// testdata/revokedica without the revocation list of its configuration,
+	// which revokes the intermediate CA issuing the signing identity
+	dir := "testdata/revokedica"
+	thisMSP := getLocalMSPWithoutCRLs(t, dir)
+
+	id, err := thisMSP.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	assert.NoError(t, id.Validate())
+
+	defer crl.LocalStore().Update(nil)
+	assert.NoError(t, crl.LocalStore().LoadDir(filepath.Join(dir, "crls")))
+	assert.Error(t, id.Validate(), "Identity issued by an intermediate CA revoked by the local CRL found valid")
+}
+
+// getLocalMSPWithoutCRLs returns the local MSP of the directory, set up
+// without the revocation list of its configuration
+func getLocalMSPWithoutCRLs(t *testing.T, dir string) MSP {
+	conf, err := GetLocalMspConfig(dir, nil, "DEFAULT")
+	assert.NoError(t, err)
+	fabricConf := &msp.FabricMSPConfig{}
+	assert.NoError(t, proto.Unmarshal(conf.Config, fabricConf))
+	fabricConf.RevocationList = nil
+	conf.Config, err = proto.Marshal(fabricConf)
+	assert.NoError(t, err)
+
+	thisMSP, err := NewBccspMsp()
+	assert.NoError(t, err)
+	ks, err := sw.NewFileBasedKeyStore(nil, filepath.Join(dir, "keystore"), true)
+	assert.NoError(t, err)
+	csp, err := sw.New(256, "SHA2", ks)
+	assert.NoError(t, err)
+	thisMSP.(*bccspmsp).bccsp = csp
+	assert.NoError(t, thisMSP.Setup(conf))
+	return thisMSP
+}
@@ -127,6 +127,7 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, c
 		// 单独切割当前交易
 		messageBatches = append(messageBatches, []*cb.Envelope{msg})
 		committerBatches = append(committerBatches, []filter.Committer{committer})
+		batchSize.Observe(1)
 
 		return
 	}
@@ -165,6 +166,9 @@ func (r *receiver) Cut() ([]*cb.Envelope, []filter.Committer) {
 	committers := r.pendingCommitters
 	r.pendingCommitters = nil
 	r.pendingBatchSizeBytes = 0
+	if len(batch) > 0 {
+		batchSize.Observe(float64(len(batch)))
+	}
 	return batch, committers
 }
 
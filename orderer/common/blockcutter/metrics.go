@@ -0,0 +1,16 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter
+
+import "github.com/hyperledger/fabric/common/metrics"
+
+var batchSize = metrics.NewHistogram(metrics.HistogramOpts{
+	Namespace: "blockcutter",
+	Name:      "batch_size",
+	Help:      "The number of messages of the batches cut.",
+	Buckets:   []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000},
+})
@@ -75,6 +75,9 @@ func NewHandlerImpl(sm SupportManager) Handler {
 // Handle starts a service thread for a given gRPC connection and services the broadcast connection
 func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 	logger.Debugf("Starting new broadcast loop")
+	streamsOpened.Add(1)
+	defer streamsClosed.Add(1)
+	srv = &statusCountingServer{srv}
 	for {
 		// 交易接收
 		msg, err := srv.Recv()
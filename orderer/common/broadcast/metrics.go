@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package broadcast
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+)
+
+var (
+	streamsOpened = metrics.NewCounter(metrics.CounterOpts{
+		Namespace: "broadcast",
+		Name:      "streams_opened",
+		Help:      "The number of broadcast streams opened.",
+	})
+	streamsClosed = metrics.NewCounter(metrics.CounterOpts{
+		Namespace: "broadcast",
+		Name:      "streams_closed",
+		Help:      "The number of broadcast streams closed.",
+	})
+	envelopesProcessed = metrics.NewCounter(metrics.CounterOpts{
+		Namespace:  "broadcast",
+		Name:       "processed_count",
+		Help:       "The number of envelopes processed, by status of the response.",
+		LabelNames: []string{"status"},
+	})
+)
+
+// statusCountingServer counts the responses sent on a broadcast stream by status
+type statusCountingServer struct {
+	ab.AtomicBroadcast_BroadcastServer
+}
+
+func (s *statusCountingServer) Send(resp *ab.BroadcastResponse) error {
+	envelopesProcessed.With(resp.Status.String()).Add(1)
+	return s.AtomicBroadcast_BroadcastServer.Send(resp)
+}
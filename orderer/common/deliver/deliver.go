@@ -71,6 +71,8 @@ func NewHandlerImpl(sm SupportManager) Handler {
 
 func (ds *deliverServer) Handle(srv ab.AtomicBroadcast_DeliverServer) error {
 	logger.Debugf("Starting new deliver loop")
+	streamsOpened.Add(1)
+	defer streamsClosed.Add(1)
 	for {
 		logger.Debugf("Attempting to read seek info message")
 		envelope, err := srv.Recv()
@@ -194,6 +196,7 @@ func (ds *deliverServer) Handle(srv ab.AtomicBroadcast_DeliverServer) error {
 				logger.Warningf("[channel: %s] Error sending to stream: %s", chdr.ChannelId, err)
 				return err
 			}
+			blocksSent.With(chdr.ChannelId).Add(1)
 
 			if stopNum == block.Header.Number {
 				break
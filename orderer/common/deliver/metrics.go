@@ -0,0 +1,28 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver
+
+import "github.com/hyperledger/fabric/common/metrics"
+
+var (
+	streamsOpened = metrics.NewCounter(metrics.CounterOpts{
+		Namespace: "deliver",
+		Name:      "streams_opened",
+		Help:      "The number of deliver streams opened.",
+	})
+	streamsClosed = metrics.NewCounter(metrics.CounterOpts{
+		Namespace: "deliver",
+		Name:      "streams_closed",
+		Help:      "The number of deliver streams closed.",
+	})
+	blocksSent = metrics.NewCounter(metrics.CounterOpts{
+		Namespace:  "deliver",
+		Name:       "blocks_sent",
+		Help:       "The number of blocks sent by the deliver service.",
+		LabelNames: []string{"channel"},
+	})
+)
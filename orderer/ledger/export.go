@@ -0,0 +1,219 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+)
+
+// The portable format of an exported chain is the magic string followed by the
+// format version as a uvarint, and then by the blocks of the chain in order,
+// each one marshaled and prefixed by its length as a uvarint
+const (
+	exportMagic   = "HLFOLEDG"
+	exportVersion = 1
+
+	// maxExportedBlockSize bounds the size of the blocks read from an export,
+	// so that a corrupted length prefix does not exhaust the memory
+	maxExportedBlockSize = 512 * 1024 * 1024
+)
+
+// BlockVerifier verifies the blocks of a chain which is being imported. It is
+// handed every block of the export in order, including the ones which are
+// already in the ledger, so that it can keep track of the chain configuration
+type BlockVerifier interface {
+	// VerifyBlock returns an error if the block must not be imported
+	VerifyBlock(block *cb.Block) error
+}
+
+// Export streams all the blocks of a chain available from the Reader, from the
+// oldest one to the newest one at the time of the call, to w in the portable
+// format, and returns the number of exported blocks
+func Export(r Reader, w io.Writer) (uint64, error) {
+	height := r.Height()
+	it, number := r.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}})
+
+	bw := bufio.NewWriter(w)
+	if err := writeExportHeader(bw); err != nil {
+		return 0, err
+	}
+
+	var exported uint64
+	for ; number < height; number++ {
+		block, status := it.Next()
+		if status != cb.Status_SUCCESS {
+			return exported, fmt.Errorf("Error reading block %d: %s", number, status)
+		}
+		if err := writeExportedBlock(bw, block); err != nil {
+			return exported, fmt.Errorf("Error writing block %d: %s", number, err)
+		}
+		exported++
+	}
+
+	return exported, bw.Flush()
+}
+
+// Import reads a chain in the portable format from r and appends its blocks to
+// the ledger, returning the number of appended blocks. The blocks must be hash
+// chained and consistent with their data; the ones which are already in the
+// ledger must be identical to the ledger ones and are skipped, so that an
+// interrupted import can be resumed. Every block is checked by the verifier,
+// if not nil, before it is appended
+func Import(r io.Reader, rw ReadWriter, verifier BlockVerifier) (uint64, error) {
+	br := bufio.NewReader(r)
+	if err := readExportHeader(br); err != nil {
+		return 0, err
+	}
+
+	height := rw.Height()
+	var lastHash []byte
+	if height > 0 {
+		last := GetBlock(rw, height-1)
+		if last == nil {
+			return 0, fmt.Errorf("Error reading the last block %d of the ledger", height-1)
+		}
+		lastHash = last.Header.Hash()
+	}
+
+	var imported uint64
+	var previous *cb.Block
+	for {
+		block, err := readExportedBlock(br)
+		if err == io.EOF {
+			return imported, nil
+		}
+		if err != nil {
+			return imported, err
+		}
+
+		if err = checkExportedBlock(block, previous); err != nil {
+			return imported, err
+		}
+		previous = block
+		number := block.Header.Number
+
+		if verifier != nil {
+			if err = verifier.VerifyBlock(block); err != nil {
+				return imported, fmt.Errorf("Block %d failed verification: %s", number, err)
+			}
+		}
+
+		switch {
+		case number < height:
+			existing := GetBlock(rw, number)
+			if existing == nil {
+				return imported, fmt.Errorf("Error reading block %d of the ledger", number)
+			}
+			if !bytes.Equal(existing.Header.Hash(), block.Header.Hash()) {
+				return imported, fmt.Errorf("Block %d conflicts with the block in the ledger", number)
+			}
+			// the block is already in the ledger
+		case number == height:
+			if height > 0 && !bytes.Equal(block.Header.PreviousHash, lastHash) {
+				return imported, fmt.Errorf("Block %d does not chain to the last block of the ledger", number)
+			}
+			if err = rw.Append(block); err != nil {
+				return imported, fmt.Errorf("Error appending block %d: %s", number, err)
+			}
+			lastHash = block.Header.Hash()
+			height++
+			imported++
+		default:
+			return imported, fmt.Errorf("Block %d is beyond the height %d of the ledger", number, height)
+		}
+	}
+}
+
+// checkExportedBlock checks that the block is consistent with its data and
+// that it is chained to the previous block of the export, if any
+func checkExportedBlock(block, previous *cb.Block) error {
+	if block.Header == nil || block.Data == nil {
+		return fmt.Errorf("Block is missing its header or data")
+	}
+	if !bytes.Equal(block.Data.Hash(), block.Header.DataHash) {
+		return fmt.Errorf("Data hash of block %d does not match its data", block.Header.Number)
+	}
+	if previous == nil {
+		return nil
+	}
+	if block.Header.Number != previous.Header.Number+1 {
+		return fmt.Errorf("Expected block %d, got block %d", previous.Header.Number+1, block.Header.Number)
+	}
+	if !bytes.Equal(block.Header.PreviousHash, previous.Header.Hash()) {
+		return fmt.Errorf("Block %d does not chain to block %d", block.Header.Number, previous.Header.Number)
+	}
+	return nil
+}
+
+func writeExportHeader(w io.Writer) error {
+	buf := make([]byte, len(exportMagic)+binary.MaxVarintLen64)
+	copy(buf, exportMagic)
+	n := binary.PutUvarint(buf[len(exportMagic):], exportVersion)
+	_, err := w.Write(buf[:len(exportMagic)+n])
+	return err
+}
+
+func readExportHeader(r *bufio.Reader) error {
+	magic := make([]byte, len(exportMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != exportMagic {
+		return fmt.Errorf("Not an exported orderer chain")
+	}
+	version, err := binary.ReadUvarint(r)
+	if err != nil {
+		return fmt.Errorf("Error reading the export format version: %s", err)
+	}
+	if version != exportVersion {
+		return fmt.Errorf("Unsupported export format version %d", version)
+	}
+	return nil
+}
+
+func writeExportedBlock(w io.Writer, block *cb.Block) error {
+	blockBytes, err := proto.Marshal(block)
+	if err != nil {
+		return err
+	}
+	length := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(length, uint64(len(blockBytes)))
+	if _, err = w.Write(length[:n]); err != nil {
+		return err
+	}
+	_, err = w.Write(blockBytes)
+	return err
+}
+
+// readExportedBlock returns io.EOF only if the export ends before the block
+func readExportedBlock(r *bufio.Reader) (*cb.Block, error) {
+	length, err := binary.ReadUvarint(r)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading block length: %s", err)
+	}
+	if length > maxExportedBlockSize {
+		return nil, fmt.Errorf("Block length %d exceeds the maximum of %d", length, maxExportedBlockSize)
+	}
+
+	blockBytes := make([]byte, length)
+	if _, err = io.ReadFull(r, blockBytes); err != nil {
+		return nil, fmt.Errorf("Error reading block: %s", err)
+	}
+	block := &cb.Block{}
+	if err = proto.Unmarshal(blockBytes, block); err != nil {
+		return nil, fmt.Errorf("Error unmarshaling block: %s", err)
+	}
+	return block, nil
+}
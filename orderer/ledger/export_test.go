@@ -0,0 +1,204 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/hyperledger/fabric/orderer/ledger"
+	jsonledger "github.com/hyperledger/fabric/orderer/ledger/json"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/stretchr/testify/assert"
+)
+
+const exportTestChainID = "exportchain"
+
+// newExportTestLedger returns a RAM ledger holding a chain of the given height
+func newExportTestLedger(t *testing.T, height int) ReadWriter {
+	rl, err := ramledger.New(height + 1).GetOrCreate(exportTestChainID)
+	assert.NoError(t, err)
+	for i := 0; i < height; i++ {
+		payload := []byte(fmt.Sprintf("block %d", i))
+		assert.NoError(t, rl.Append(CreateNextBlock(rl, []*cb.Envelope{{Payload: payload}})))
+	}
+	return rl
+}
+
+func exportChain(t *testing.T, r Reader) []byte {
+	var buf bytes.Buffer
+	exported, err := Export(r, &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, r.Height(), exported)
+	return buf.Bytes()
+}
+
+type mockBlockVerifier struct {
+	verified []uint64
+	reject   uint64
+}
+
+func (mv *mockBlockVerifier) VerifyBlock(block *cb.Block) error {
+	mv.verified = append(mv.verified, block.Header.Number)
+	if block.Header.Number == mv.reject {
+		return fmt.Errorf("rejected")
+	}
+	return nil
+}
+
+func TestExportImport(t *testing.T) {
+	source := newExportTestLedger(t, 5)
+	export := exportChain(t, source)
+
+	// migrate the chain to a json ledger
+	dir, err := ioutil.TempDir("", "hyperledger")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	target, err := jsonledger.New(dir).GetOrCreate(exportTestChainID)
+	assert.NoError(t, err)
+
+	verifier := &mockBlockVerifier{reject: 100}
+	imported, err := Import(bytes.NewReader(export), target, verifier)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), imported)
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4}, verifier.verified)
+	assert.Equal(t, source.Height(), target.Height())
+	for i := uint64(0); i < source.Height(); i++ {
+		assert.True(t, proto.Equal(GetBlock(source, i), GetBlock(target, i)), "block %d differs", i)
+	}
+
+	// the import is idempotent
+	imported, err = Import(bytes.NewReader(export), target, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), imported)
+	assert.Equal(t, uint64(5), target.Height())
+}
+
+func TestImportResume(t *testing.T) {
+	source := newExportTestLedger(t, 5)
+	export := exportChain(t, source)
+
+	target, err := ramledger.New(10).GetOrCreate(exportTestChainID)
+	assert.NoError(t, err)
+	for i := uint64(0); i < 2; i++ {
+		assert.NoError(t, target.Append(GetBlock(source, i)))
+	}
+
+	imported, err := Import(bytes.NewReader(export), target, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), imported)
+	assert.Equal(t, uint64(5), target.Height())
+}
+
+func TestImportVerifierRejects(t *testing.T) {
+	export := exportChain(t, newExportTestLedger(t, 5))
+
+	target, err := ramledger.New(10).GetOrCreate(exportTestChainID)
+	assert.NoError(t, err)
+	imported, err := Import(bytes.NewReader(export), target, &mockBlockVerifier{reject: 3})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Block 3 failed verification")
+	assert.Equal(t, uint64(3), imported)
+	assert.Equal(t, uint64(3), target.Height())
+}
+
+func TestImportConflictingLedger(t *testing.T) {
+	export := exportChain(t, newExportTestLedger(t, 3))
+
+	// a ledger with a different chain
+	target, err := ramledger.New(10).GetOrCreate(exportTestChainID)
+	assert.NoError(t, err)
+	assert.NoError(t, target.Append(CreateNextBlock(target, []*cb.Envelope{{Payload: []byte("other")}})))
+
+	_, err = Import(bytes.NewReader(export), target, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "conflicts")
+	assert.Equal(t, uint64(1), target.Height())
+}
+
+func TestImportBadExport(t *testing.T) {
+	source := newExportTestLedger(t, 3)
+	newTarget := func() ReadWriter {
+		target, err := ramledger.New(10).GetOrCreate(exportTestChainID)
+		assert.NoError(t, err)
+		return target
+	}
+
+	_, err := Import(bytes.NewReader([]byte("garbage")), newTarget(), nil)
+	assert.Error(t, err)
+
+	// a truncated export
+	export := exportChain(t, source)
+	_, err = Import(bytes.NewReader(export[:len(export)-1]), newTarget(), nil)
+	assert.Error(t, err)
+
+	// tampered block data
+	var buf bytes.Buffer
+	_, err = Export(source, &buf)
+	assert.NoError(t, err)
+	tampered := bytes.Replace(buf.Bytes(), []byte("block 1"), []byte("block X"), 1)
+	target := newTarget()
+	imported, err := Import(bytes.NewReader(tampered), target, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Data hash of block 1")
+	assert.Equal(t, uint64(1), imported)
+
+	// a missing block breaks the hash chain
+	chain := []*cb.Block{GetBlock(source, 0), GetBlock(source, 2)}
+	_, err = Import(bytes.NewReader(marshalExport(t, chain)), newTarget(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Expected block 1")
+
+	// an export starting beyond the height of the ledger
+	chain = []*cb.Block{GetBlock(source, 1), GetBlock(source, 2)}
+	_, err = Import(bytes.NewReader(marshalExport(t, chain)), newTarget(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "beyond the height")
+}
+
+// marshalExport exports the given blocks as they are, through a Reader which
+// does not check their chaining
+func marshalExport(t *testing.T, blocks []*cb.Block) []byte {
+	var buf bytes.Buffer
+	_, err := Export(&blocksReader{blocks: blocks}, &buf)
+	assert.NoError(t, err)
+	return buf.Bytes()
+}
+
+type blocksReader struct {
+	blocks []*cb.Block
+}
+
+func (br *blocksReader) Iterator(startType *ab.SeekPosition) (Iterator, uint64) {
+	return &blocksIterator{blocks: br.blocks}, 0
+}
+
+func (br *blocksReader) Height() uint64 {
+	return uint64(len(br.blocks))
+}
+
+type blocksIterator struct {
+	blocks []*cb.Block
+}
+
+func (bi *blocksIterator) Next() (*cb.Block, cb.Status) {
+	block := bi.blocks[0]
+	bi.blocks = bi.blocks[1:]
+	return block, cb.Status_SUCCESS
+}
+
+func (bi *blocksIterator) ReadyChan() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
@@ -50,6 +50,18 @@ func (mbsp *mockBlockStoreProvider) List() ([]string, error) {
 	return mbsp.list, mbsp.error
 }
 
+func (mbsp *mockBlockStoreProvider) IsPruned(ledgerid string) (bool, error) {
+	return false, mbsp.error
+}
+
+func (mbsp *mockBlockStoreProvider) Rollback(ledgerid string, blockNum uint64) error {
+	return mbsp.error
+}
+
+func (mbsp *mockBlockStoreProvider) DropIndex(ledgerid string) error {
+	return mbsp.error
+}
+
 func (mbsp *mockBlockStoreProvider) Close() {
 }
 
@@ -24,6 +24,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
 	cl "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/orderer/ledger"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -115,6 +116,22 @@ func (mbs *mockBlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxV
 	return mbs.txValidationCode, mbs.defaultError
 }
 
+func (mbs *mockBlockStore) Prune(retainFrom uint64) error {
+	return mbs.defaultError
+}
+
+func (mbs *mockBlockStore) FirstUnprunedBlockNumber() (uint64, error) {
+	return 0, mbs.defaultError
+}
+
+func (mbs *mockBlockStore) ExportTxIDs(writer blkstorage.TxIDWriter) error {
+	return mbs.defaultError
+}
+
+func (mbs *mockBlockStore) BootstrapFromSnapshot(lastBlock *cb.Block, lastConfigBlock *cb.Block, txIDs blkstorage.TxIDReader) error {
+	return mbs.defaultError
+}
+
 func (*mockBlockStore) Shutdown() {
 }
 
@@ -0,0 +1,260 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/hyperledger/fabric/common/configtx"
+	configtxapi "github.com/hyperledger/fabric/common/configtx/api"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	config "github.com/hyperledger/fabric/orderer/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// blockSignatureVerifier verifies the orderer signatures of the blocks of a
+// chain against the block validation policy of the chain configuration. The
+// configuration is taken from the genesis block, which is the root of trust
+// of the chain and must therefore be the expected one, and then updated with
+// the config blocks of the chain, whose updates must be authorized by the
+// configuration in force
+type blockSignatureVerifier struct {
+	chainID     string
+	genesisHash []byte
+	manager     configtxapi.Manager
+}
+
+func newBlockSignatureVerifier(chainID string, genesisHash []byte) *blockSignatureVerifier {
+	return &blockSignatureVerifier{chainID: chainID, genesisHash: genesisHash}
+}
+
+// VerifyBlock verifies the signatures of the block and applies its config, if any
+func (bv *blockSignatureVerifier) VerifyBlock(block *cb.Block) error {
+	if bv.manager == nil {
+		if block.Header.Number != 0 {
+			return fmt.Errorf("the chain must start from its genesis block to verify the signatures")
+		}
+		return bv.initialize(block)
+	}
+
+	metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return fmt.Errorf("failed unmarshaling the signatures: %s", err)
+	}
+	signatureSet := []*cb.SignedData{}
+	for _, metadataSignature := range metadata.Signatures {
+		shdr, err := utils.GetSignatureHeader(metadataSignature.SignatureHeader)
+		if err != nil {
+			return fmt.Errorf("failed unmarshaling a signature header: %s", err)
+		}
+		signatureSet = append(signatureSet, &cb.SignedData{
+			Identity:  shdr.Creator,
+			Data:      util.ConcatenateBytes(metadata.Value, metadataSignature.SignatureHeader, block.Header.Bytes()),
+			Signature: metadataSignature.Signature,
+		})
+	}
+
+	policy, ok := bv.manager.PolicyManager().GetPolicy(policies.BlockValidation)
+	if !ok {
+		return fmt.Errorf("the chain configuration has no block validation policy")
+	}
+	if err = policy.Evaluate(signatureSet); err != nil {
+		return fmt.Errorf("the signatures do not satisfy the block validation policy: %s", err)
+	}
+
+	if !utils.IsConfigBlock(block) {
+		return nil
+	}
+	configEnv, err := extractConfigEnvelope(block)
+	if err != nil {
+		return err
+	}
+	if err = bv.manager.Apply(configEnv); err != nil {
+		return fmt.Errorf("the config update is not valid: %s", err)
+	}
+	return nil
+}
+
+func (bv *blockSignatureVerifier) initialize(genesisBlock *cb.Block) error {
+	if !bytes.Equal(genesisBlock.Header.Hash(), bv.genesisHash) {
+		return fmt.Errorf("the genesis block is not the expected genesis block of the chain")
+	}
+	env, err := utils.ExtractEnvelope(genesisBlock, 0)
+	if err != nil {
+		return fmt.Errorf("failed extracting the config of the genesis block: %s", err)
+	}
+	manager, err := configtx.NewManagerImpl(env, configtx.NewInitializer(), nil)
+	if err != nil {
+		return fmt.Errorf("invalid genesis block config: %s", err)
+	}
+	if manager.ChainID() != bv.chainID {
+		return fmt.Errorf("the genesis block is for channel %s, not for channel %s", manager.ChainID(), bv.chainID)
+	}
+	bv.manager = manager
+	return nil
+}
+
+func extractConfigEnvelope(block *cb.Block) (*cb.ConfigEnvelope, error) {
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	return configtx.UnmarshalConfigEnvelope(payload.Data)
+}
+
+// exportChain writes the chain of the channel to w in the portable format
+func exportChain(lf ledger.Factory, chainID string, w io.Writer) (uint64, error) {
+	found := false
+	for _, id := range lf.ChainIDs() {
+		found = found || id == chainID
+	}
+	if !found {
+		return 0, fmt.Errorf("channel %s does not exist in the ledger", chainID)
+	}
+
+	rl, err := lf.GetOrCreate(chainID)
+	if err != nil {
+		return 0, err
+	}
+	return ledger.Export(rl, w)
+}
+
+// importChain appends the chain of the channel read from r to the ledger,
+// verifying the block signatures if requested. The verification trusts the
+// genesis block of the chain in the ledger, or else the expected genesis
+// block, without which a verified import into an empty ledger is refused
+func importChain(lf ledger.Factory, chainID string, r io.Reader, verifySignatures bool, genesisBlock *cb.Block) (uint64, error) {
+	found := false
+	for _, id := range lf.ChainIDs() {
+		found = found || id == chainID
+	}
+	if verifySignatures && !found && genesisBlock == nil {
+		return 0, fmt.Errorf("the expected genesis block of channel %s is required to verify an import into an empty ledger", chainID)
+	}
+
+	rl, err := lf.GetOrCreate(chainID)
+	if err != nil {
+		return 0, err
+	}
+
+	var verifier ledger.BlockVerifier
+	if verifySignatures {
+		if rl.Height() > 0 {
+			existing := ledger.GetBlock(rl, 0)
+			if existing == nil {
+				return 0, fmt.Errorf("Error reading the genesis block of the ledger")
+			}
+			if genesisBlock != nil && !bytes.Equal(existing.Header.Hash(), genesisBlock.Header.Hash()) {
+				return 0, fmt.Errorf("the expected genesis block does not match the genesis block of channel %s in the ledger", chainID)
+			}
+			genesisBlock = existing
+		} else if genesisBlock == nil {
+			return 0, fmt.Errorf("the expected genesis block of channel %s is required to verify an import into an empty ledger", chainID)
+		}
+		verifier = newBlockSignatureVerifier(chainID, genesisBlock.Header.Hash())
+	}
+	return ledger.Import(r, rl, verifier)
+}
+
+// expectedGenesisBlock reads the expected genesis block of the chain from the
+// file, if any, or else from the genesis file of the orderer if the orderer
+// bootstraps from a file and the block is the genesis block of the channel
+func expectedGenesisBlock(conf *config.TopLevel, chainID, path string) *cb.Block {
+	if path == "" {
+		if conf.General.GenesisMethod != "file" {
+			return nil
+		}
+		block := readGenesisBlock(conf.General.GenesisFile)
+		if id, err := utils.GetChainIDFromBlock(block); err != nil || id != chainID {
+			return nil
+		}
+		return block
+	}
+	return readGenesisBlock(path)
+}
+
+func readGenesisBlock(path string) *cb.Block {
+	blockBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		logger.Fatal("Failed to read the genesis block:", err)
+	}
+	block, err := utils.GetBlockFromBlockBytes(blockBytes)
+	if err != nil {
+		logger.Fatal("Failed to unmarshal the genesis block:", err)
+	}
+	return block
+}
+
+// ledgerFactoryFor creates the ledger factory of the configuration, with the
+// ledger type and location overridden if they are not empty
+func ledgerFactoryFor(conf *config.TopLevel, ledgerType, location string) ledger.Factory {
+	if ledgerType != "" {
+		conf.General.LedgerType = ledgerType
+	}
+	if location != "" {
+		conf.FileLedger.Location = location
+	}
+	if conf.General.LedgerType == "ram" {
+		logger.Fatal("A RAM ledger does not outlive the orderer and cannot be exported or imported")
+	}
+	if conf.FileLedger.Location == "" {
+		logger.Fatal("The ledger location must be set to export or import a chain")
+	}
+	lf, _ := createLedgerFactory(conf)
+	return lf
+}
+
+func runLedgerExport(conf *config.TopLevel) {
+	lf := ledgerFactoryFor(conf, *ledgerType, *ledgerLocation)
+	defer lf.Close()
+
+	f, err := os.Create(*exportFile)
+	if err != nil {
+		logger.Fatal("Failed to create the export file:", err)
+	}
+	exported, err := exportChain(lf, *ledgerChannelID, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		logger.Fatalf("Failed to export the chain of channel %s: %s", *ledgerChannelID, err)
+	}
+	fmt.Printf("Exported %d blocks of channel %s to %s\n", exported, *ledgerChannelID, *exportFile)
+}
+
+func runLedgerImport(conf *config.TopLevel) {
+	var genesisBlock *cb.Block
+	if !*importSkipVerify {
+		initializeLocalMsp(conf)
+		genesisBlock = expectedGenesisBlock(conf, *ledgerChannelID, *importGenesis)
+	}
+	lf := ledgerFactoryFor(conf, *ledgerType, *ledgerLocation)
+	defer lf.Close()
+
+	f, err := os.Open(*importFile)
+	if err != nil {
+		logger.Fatal("Failed to open the export file:", err)
+	}
+	defer f.Close()
+
+	imported, err := importChain(lf, *ledgerChannelID, f, !*importSkipVerify, genesisBlock)
+	if err != nil {
+		logger.Fatalf("Failed to import the chain of channel %s after %d blocks: %s", *ledgerChannelID, imported, err)
+	}
+	fmt.Printf("Imported %d blocks of channel %s from %s\n", imported, *ledgerChannelID, *importFile)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp/factory"
+	genesisconfig "github.com/hyperledger/fabric/common/configtx/tool/localconfig"
+	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/common/util"
+	coreconfig "github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	config "github.com/hyperledger/fabric/orderer/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLedgerCmdTestFactory(t *testing.T) (ledger.Factory, func()) {
+	location, err := ioutil.TempDir("", "test-ledger")
+	assert.NoError(t, err)
+	lf, _ := createLedgerFactory(&config.TopLevel{
+		General:    config.General{LedgerType: "file"},
+		FileLedger: config.FileLedger{Location: location},
+	})
+	return lf, func() {
+		lf.Close()
+		os.RemoveAll(location)
+	}
+}
+
+func signBlock(block *cb.Block, signer crypto.LocalSigner) {
+	blockSignature := &cb.MetadataSignature{
+		SignatureHeader: utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(signer)),
+	}
+	blockSignature.Signature = utils.SignOrPanic(signer, util.ConcatenateBytes(nil, blockSignature.SignatureHeader, block.Header.Bytes()))
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&cb.Metadata{
+		Signatures: []*cb.MetadataSignature{blockSignature},
+	})
+}
+
+// newSignedChain creates a chain of the test channel in the ledger, whose
+// blocks after the genesis one are signed by the signer, if not nil
+func newSignedChain(t *testing.T, lf ledger.Factory, signer crypto.LocalSigner) {
+	rl, err := lf.GetOrCreate(provisional.TestChainID)
+	assert.NoError(t, err)
+	assert.NoError(t, rl.Append(provisional.New(genesisconfig.Load("SampleSingleMSPSolo")).GenesisBlock()))
+	for i := 0; i < 3; i++ {
+		block := ledger.CreateNextBlock(rl, []*cb.Envelope{{Payload: []byte{byte(i)}}})
+		if signer != nil {
+			signBlock(block, signer)
+		}
+		assert.NoError(t, rl.Append(block))
+	}
+}
+
+func TestLedgerExportImport(t *testing.T) {
+	localMSPDir, _ := coreconfig.GetDevMspDir()
+	initializeLocalMsp(&config.TopLevel{
+		General: config.General{
+			LocalMSPDir: localMSPDir,
+			LocalMSPID:  "DEFAULT",
+			BCCSP: &factory.FactoryOpts{
+				ProviderName: "SW",
+				SwOpts: &factory.SwOpts{
+					HashFamily: "SHA2",
+					SecLevel:   256,
+					Ephemeral:  true,
+				},
+			},
+		},
+	})
+
+	source, cleanup := newLedgerCmdTestFactory(t)
+	defer cleanup()
+	newSignedChain(t, source, localmsp.NewSigner())
+
+	var export bytes.Buffer
+	_, err := exportChain(source, "nonexistent", &export)
+	assert.Error(t, err)
+	exported, err := exportChain(source, provisional.TestChainID, &export)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), exported)
+
+	rl, err := source.GetOrCreate(provisional.TestChainID)
+	assert.NoError(t, err)
+	genesisBlock := ledger.GetBlock(rl, 0)
+
+	t.Run("Verified", func(t *testing.T) {
+		target, cleanup := newLedgerCmdTestFactory(t)
+		defer cleanup()
+		imported, err := importChain(target, provisional.TestChainID, bytes.NewReader(export.Bytes()), true, genesisBlock)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(4), imported)
+		assert.Equal(t, []string{provisional.TestChainID}, target.ChainIDs())
+
+		// the genesis block of the ledger is trusted from now on
+		imported, err = importChain(target, provisional.TestChainID, bytes.NewReader(export.Bytes()), true, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), imported)
+		otherGenesisBlock := provisional.New(genesisconfig.Load("SampleInsecureSolo")).GenesisBlock()
+		_, err = importChain(target, provisional.TestChainID, bytes.NewReader(export.Bytes()), true, otherGenesisBlock)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match the genesis block")
+	})
+
+	t.Run("NoExpectedGenesisBlock", func(t *testing.T) {
+		target, cleanup := newLedgerCmdTestFactory(t)
+		defer cleanup()
+		imported, err := importChain(target, provisional.TestChainID, bytes.NewReader(export.Bytes()), true, nil)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected genesis block")
+		assert.Equal(t, uint64(0), imported)
+		assert.Empty(t, target.ChainIDs())
+	})
+
+	t.Run("UnexpectedGenesisBlock", func(t *testing.T) {
+		target, cleanup := newLedgerCmdTestFactory(t)
+		defer cleanup()
+		otherGenesisBlock := provisional.New(genesisconfig.Load("SampleInsecureSolo")).GenesisBlock()
+		imported, err := importChain(target, provisional.TestChainID, bytes.NewReader(export.Bytes()), true, otherGenesisBlock)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not the expected genesis block")
+		assert.Equal(t, uint64(0), imported)
+	})
+
+	t.Run("WrongChannel", func(t *testing.T) {
+		target, cleanup := newLedgerCmdTestFactory(t)
+		defer cleanup()
+		imported, err := importChain(target, "otherchannel", bytes.NewReader(export.Bytes()), true, genesisBlock)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "genesis block is for channel")
+		assert.Equal(t, uint64(0), imported)
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		unsigned, cleanup := newLedgerCmdTestFactory(t)
+		defer cleanup()
+		newSignedChain(t, unsigned, nil)
+		var unsignedExport bytes.Buffer
+		_, err := exportChain(unsigned, provisional.TestChainID, &unsignedExport)
+		assert.NoError(t, err)
+
+		rl, err := unsigned.GetOrCreate(provisional.TestChainID)
+		assert.NoError(t, err)
+
+		target, cleanup := newLedgerCmdTestFactory(t)
+		defer cleanup()
+		imported, err := importChain(target, provisional.TestChainID, bytes.NewReader(unsignedExport.Bytes()), true, ledger.GetBlock(rl, 0))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "block validation policy")
+		assert.Equal(t, uint64(1), imported)
+
+		// the signatures are not checked when the verification is skipped
+		imported, err = importChain(target, provisional.TestChainID, bytes.NewReader(unsignedExport.Bytes()), false, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), imported)
+	})
+}
+
+func TestExpectedGenesisBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-genesis")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	genesisBlock := provisional.New(genesisconfig.Load("SampleSingleMSPSolo")).GenesisBlock()
+	genesisFile := filepath.Join(dir, "genesisblock")
+	assert.NoError(t, ioutil.WriteFile(genesisFile, utils.MarshalOrPanic(genesisBlock), 0644))
+
+	conf := &config.TopLevel{General: config.General{GenesisMethod: "provisional", GenesisFile: genesisFile}}
+	assert.Nil(t, expectedGenesisBlock(conf, provisional.TestChainID, ""))
+	assert.Equal(t, genesisBlock.Header, expectedGenesisBlock(conf, provisional.TestChainID, genesisFile).Header)
+
+	// the genesis file of the orderer is used for its own channel only
+	conf.General.GenesisMethod = "file"
+	assert.Equal(t, genesisBlock.Header, expectedGenesisBlock(conf, provisional.TestChainID, "").Header)
+	assert.Nil(t, expectedGenesisBlock(conf, "otherchannel", ""))
+}
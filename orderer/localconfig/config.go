@@ -64,6 +64,9 @@ type TopLevel struct {
 	FileLedger FileLedger
 	RAMLedger  RAMLedger
 	Kafka      Kafka
+	Raft       Raft
+	Operations Operations
+	Metrics    Metrics
 }
 
 // General contains config which should be common among all orderer types.
@@ -80,6 +83,7 @@ type General struct {
 	LogFormat      string
 	LocalMSPDir    string
 	LocalMSPID     string
+	LocalCRLDir    string
 	BCCSP          *bccsp.FactoryOpts
 }
 
@@ -99,6 +103,18 @@ type Profile struct {
 	Address string
 }
 
+// Operations contains configuration for the operations server, which serves
+// the metrics, the health and the logging specification of the orderer.
+type Operations struct {
+	ListenAddress string
+	TLS           TLS
+}
+
+// Metrics contains configuration for the metrics of the orderer.
+type Metrics struct {
+	Provider string
+}
+
 // FileLedger contains configuration for the file-based ledger.
 type FileLedger struct {
 	Location string
@@ -122,6 +138,24 @@ type Kafka struct {
 // connection to the Kafka cluster cannot be established, or when Metadata
 // requests needs to be repeated (because the cluster is in the middle of a
 // leader election).
+// Raft contains config for the Raft-based consenter.
+type Raft struct {
+	NodeID           uint64
+	Nodes            []RaftNode
+	WALDir           string
+	SnapDir          string
+	TickInterval     time.Duration
+	ElectionTick     int
+	HeartbeatTick    int
+	SnapshotInterval uint64
+}
+
+// RaftNode identifies a member of the Raft cluster.
+type RaftNode struct {
+	ID      uint64
+	Address string
+}
+
 type Retry struct {
 	ShortInterval   time.Duration
 	ShortTotal      time.Duration
@@ -215,6 +249,20 @@ var defaults = TopLevel{
 			Enabled: false,
 		},
 	},
+	Raft: Raft{
+		WALDir:           "/var/hyperledger/production/orderer/raft/wal",
+		SnapDir:          "/var/hyperledger/production/orderer/raft/snapshot",
+		TickInterval:     100 * time.Millisecond,
+		ElectionTick:     10,
+		HeartbeatTick:    1,
+		SnapshotInterval: 1000,
+	},
+	Operations: Operations{
+		ListenAddress: "127.0.0.1:8443",
+	},
+	Metrics: Metrics{
+		Provider: "disabled",
+	},
 }
 
 // Load parses the orderer.yaml file and environment, producing a struct suitable for config use
@@ -253,6 +301,12 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		cf.TranslatePathInPlace(configDir, &c.General.TLS.Certificate)
 		cf.TranslatePathInPlace(configDir, &c.General.GenesisFile)
 		cf.TranslatePathInPlace(configDir, &c.General.LocalMSPDir)
+		if c.General.LocalCRLDir != "" {
+			cf.TranslatePathInPlace(configDir, &c.General.LocalCRLDir)
+		}
+		c.Operations.TLS.ClientRootCAs = translateCAs(configDir, c.Operations.TLS.ClientRootCAs)
+		cf.TranslatePathInPlace(configDir, &c.Operations.TLS.PrivateKey)
+		cf.TranslatePathInPlace(configDir, &c.Operations.TLS.Certificate)
 	}()
 
 	for {
@@ -349,6 +403,34 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("Kafka.Version unset, setting to %v", defaults.Kafka.Version)
 			c.Kafka.Version = defaults.Kafka.Version
 
+		case c.Raft.WALDir == "":
+			logger.Infof("Raft.WALDir unset, setting to %v", defaults.Raft.WALDir)
+			c.Raft.WALDir = defaults.Raft.WALDir
+		case c.Raft.SnapDir == "":
+			logger.Infof("Raft.SnapDir unset, setting to %v", defaults.Raft.SnapDir)
+			c.Raft.SnapDir = defaults.Raft.SnapDir
+		case c.Raft.TickInterval == 0*time.Millisecond:
+			logger.Infof("Raft.TickInterval unset, setting to %v", defaults.Raft.TickInterval)
+			c.Raft.TickInterval = defaults.Raft.TickInterval
+		case c.Raft.ElectionTick == 0:
+			logger.Infof("Raft.ElectionTick unset, setting to %v", defaults.Raft.ElectionTick)
+			c.Raft.ElectionTick = defaults.Raft.ElectionTick
+		case c.Raft.HeartbeatTick == 0:
+			logger.Infof("Raft.HeartbeatTick unset, setting to %v", defaults.Raft.HeartbeatTick)
+			c.Raft.HeartbeatTick = defaults.Raft.HeartbeatTick
+		case c.Raft.HeartbeatTick >= c.Raft.ElectionTick:
+			logger.Panicf("Raft.HeartbeatTick (%d) must be smaller than Raft.ElectionTick (%d)", c.Raft.HeartbeatTick, c.Raft.ElectionTick)
+		case c.Raft.SnapshotInterval == 0:
+			logger.Infof("Raft.SnapshotInterval unset, setting to %v", defaults.Raft.SnapshotInterval)
+			c.Raft.SnapshotInterval = defaults.Raft.SnapshotInterval
+
+		case c.Operations.ListenAddress == "":
+			logger.Infof("Operations.ListenAddress unset, setting to %s", defaults.Operations.ListenAddress)
+			c.Operations.ListenAddress = defaults.Operations.ListenAddress
+		case c.Metrics.Provider == "":
+			logger.Infof("Metrics.Provider unset, setting to %s", defaults.Metrics.Provider)
+			c.Metrics.Provider = defaults.Metrics.Provider
+
 		default:
 			return
 		}
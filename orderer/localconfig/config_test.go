@@ -116,3 +116,24 @@ func TestProfileConfig(t *testing.T) {
 	uconf.completeInitialization(DummyPath)
 	assert.Equal(t, defaults.General.Profile.Address, uconf.General.Profile.Address, "Expected profile address to be filled with default value")
 }
+
+func TestRaftConfig(t *testing.T) {
+	uconf := &TopLevel{}
+	uconf.completeInitialization(DummyPath)
+	assert.Equal(t, defaults.Raft.WALDir, uconf.Raft.WALDir, "Expected WALDir to be filled with default value")
+	assert.Equal(t, defaults.Raft.SnapDir, uconf.Raft.SnapDir, "Expected SnapDir to be filled with default value")
+	assert.Equal(t, defaults.Raft.TickInterval, uconf.Raft.TickInterval, "Expected TickInterval to be filled with default value")
+	assert.Equal(t, defaults.Raft.ElectionTick, uconf.Raft.ElectionTick, "Expected ElectionTick to be filled with default value")
+	assert.Equal(t, defaults.Raft.HeartbeatTick, uconf.Raft.HeartbeatTick, "Expected HeartbeatTick to be filled with default value")
+	assert.Equal(t, defaults.Raft.SnapshotInterval, uconf.Raft.SnapshotInterval, "Expected SnapshotInterval to be filled with default value")
+
+	uconf = &TopLevel{Raft: Raft{ElectionTick: 5, HeartbeatTick: 5}}
+	assert.Panics(t, func() { uconf.completeInitialization(DummyPath) }, "Should panic when HeartbeatTick is not smaller than ElectionTick")
+}
+
+func TestRaftNodesConfig(t *testing.T) {
+	config := Load()
+	assert.NotNil(t, config, "Could not load config")
+	assert.Equal(t, uint64(1), config.Raft.NodeID)
+	assert.Equal(t, []RaftNode{{ID: 1, Address: "127.0.0.1:7050"}}, config.Raft.Nodes)
+}
@@ -27,15 +27,18 @@ import (
 
 	genesisconfig "github.com/hyperledger/fabric/common/configtx/tool/localconfig"
 	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
+	"github.com/hyperledger/fabric/common/crl"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
 	"github.com/hyperledger/fabric/orderer/kafka"
 	"github.com/hyperledger/fabric/orderer/ledger"
 	"github.com/hyperledger/fabric/orderer/localconfig"
 	"github.com/hyperledger/fabric/orderer/metadata"
 	"github.com/hyperledger/fabric/orderer/multichain"
+	"github.com/hyperledger/fabric/orderer/raft"
 	"github.com/hyperledger/fabric/orderer/solo"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -56,6 +59,19 @@ var (
 
 	start   = app.Command("start", "Start the orderer node").Default()
 	version = app.Command("version", "Show version information")
+
+	ledgerCmd       = app.Command("ledger", "Export and import the chains of the ledger")
+	ledgerChannelID = ledgerCmd.Flag("channelID", "The channel of the chain").Short('c').Required().String()
+	ledgerType      = ledgerCmd.Flag("ledgerType", "The type of the ledger, overriding General.LedgerType").String()
+	ledgerLocation  = ledgerCmd.Flag("location", "The location of the ledger, overriding FileLedger.Location").String()
+
+	export     = ledgerCmd.Command("export", "Export the chain of a channel to a file")
+	exportFile = export.Flag("file", "The file to export the chain to").Short('f').Required().String()
+
+	importCmd        = ledgerCmd.Command("import", "Import the chain of a channel from a file")
+	importFile       = importCmd.Flag("file", "The file to import the chain from").Short('f').Required().String()
+	importSkipVerify = importCmd.Flag("skipVerification", "Do not verify the block signatures against the chain configuration").Bool()
+	importGenesis    = importCmd.Flag("genesisBlock", "The expected genesis block of the chain, required to verify an import into an empty ledger; defaults to General.GenesisFile if it is the genesis block of the channel").String()
 )
 
 func main() {
@@ -73,14 +89,21 @@ func main() {
 		// 初始化profile，profile是go语言内置的可以观察程序运行时的工具
 		// 可以通过http服务暴露出来
 		initializeProfilingService(conf)
+		// The operations server exposes the metrics and the health of the orderer
+		initializeOperationsSystem(conf)
 		// 初始化grpc服务端
 		grpcServer := initializeGrpcServer(conf)
 		// 载入msp证书
 		initializeLocalMsp(conf)
+		// The local CRLs apply to the MSPs and the TLS client certificates
+		initializeLocalCRLs(conf)
 		// msp证书用于签名者实例化
 		signer := localmsp.NewSigner()
+		// The Raft-based orderers replicate their chains over the same gRPC server
+		raftConsenter := raft.New(conf.Raft, conf.General.TLS)
+		ab.RegisterClusterServer(grpcServer.Server(), raftConsenter)
 		// 初始化多链manager管理者 重点关注
-		manager := initializeMultiChainManager(conf, signer)
+		manager := initializeMultiChainManager(conf, signer, raftConsenter)
 		// 实例化服务实现 重点关注
 		server := NewServer(manager, signer)
 		// 绑定服务器+服务实现
@@ -91,6 +114,16 @@ func main() {
 	// "version" command
 	case version.FullCommand():
 		fmt.Println(metadata.GetVersionInfo())
+	// "ledger export" command
+	case export.FullCommand():
+		conf := config.Load()
+		initializeLoggingLevel(conf)
+		runLedgerExport(conf)
+	// "ledger import" command
+	case importCmd.FullCommand():
+		conf := config.Load()
+		initializeLoggingLevel(conf)
+		runLedgerImport(conf)
 	}
 
 }
@@ -115,11 +148,31 @@ func initializeProfilingService(conf *config.TopLevel) {
 	}
 }
 
+// Start the operations server, which exposes the metrics, the health and the logging specification.
+func initializeOperationsSystem(conf *config.TopLevel) *operations.System {
+	opsSystem := operations.NewSystem(operations.Options{
+		ListenAddress:   conf.Operations.ListenAddress,
+		MetricsProvider: conf.Metrics.Provider,
+		TLS: operations.TLS{
+			Enabled:            conf.Operations.TLS.Enabled,
+			CertFile:           conf.Operations.TLS.Certificate,
+			KeyFile:            conf.Operations.TLS.PrivateKey,
+			ClientCertRequired: conf.Operations.TLS.ClientAuthEnabled,
+			ClientRootCAs:      conf.Operations.TLS.ClientRootCAs,
+		},
+	})
+	if err := opsSystem.Start(); err != nil {
+		logger.Panicf("Failed to start the operations server: %s", err)
+	}
+	return opsSystem
+}
+
 func initializeSecureServerConfig(conf *config.TopLevel) comm.SecureServerConfig {
 	// secure server config
 	secureConfig := comm.SecureServerConfig{
 		UseTLS:            conf.General.TLS.Enabled,
 		RequireClientCert: conf.General.TLS.ClientAuthEnabled,
+		CRLs:              crl.LocalStore(),
 	}
 	// check to see if TLS is enabled
 	if secureConfig.UseTLS {
@@ -215,7 +268,17 @@ func initializeLocalMsp(conf *config.TopLevel) {
 	}
 }
 
-func initializeMultiChainManager(conf *config.TopLevel, signer crypto.LocalSigner) multichain.Manager {
+func initializeLocalCRLs(conf *config.TopLevel) {
+	if conf.General.LocalCRLDir == "" {
+		return
+	}
+	// The directory is watched for as long as the orderer runs
+	if _, err := crl.LocalStore().WatchDir(conf.General.LocalCRLDir); err != nil {
+		logger.Fatal("Failed to load the local CRLs:", err)
+	}
+}
+
+func initializeMultiChainManager(conf *config.TopLevel, signer crypto.LocalSigner, raftConsenter multichain.Consenter) multichain.Manager {
 	// 创建账本工厂  存储order产生的临时区块  目前是三种实现file json ram(内存)
 	lf, _ := createLedgerFactory(conf)
 	// Are we bootstrapping?
@@ -231,6 +294,7 @@ func initializeMultiChainManager(conf *config.TopLevel, signer crypto.LocalSigne
 	consenters := make(map[string]multichain.Consenter)
 	consenters["solo"] = solo.New()
 	consenters["kafka"] = kafka.New(conf.Kafka.TLS, conf.Kafka.Retry, conf.Kafka.Version)
+	consenters["raft"] = raftConsenter
 
 	// 实例化manager 将manager当成所有链的中枢
 	return multichain.NewManagerImpl(lf, consenters, signer)
@@ -33,6 +33,7 @@ import (
 	"github.com/hyperledger/fabric/common/localmsp"
 	coreconfig "github.com/hyperledger/fabric/core/config"
 	config "github.com/hyperledger/fabric/orderer/localconfig"
+	"github.com/hyperledger/fabric/orderer/raft"
 	logging "github.com/op/go-logging"
 	"github.com/stretchr/testify/assert"
 )
@@ -76,6 +77,28 @@ func TestInitializeProfilingService(t *testing.T) {
 	}
 }
 
+func TestInitializeOperationsSystem(t *testing.T) {
+	opsSystem := initializeOperationsSystem(&config.TopLevel{
+		Operations: config.Operations{ListenAddress: "127.0.0.1:0"},
+		Metrics:    config.Metrics{Provider: "prometheus"},
+	})
+	defer opsSystem.Stop()
+
+	for _, path := range []string{"/healthz", "/metrics"} {
+		resp, err := http.Get("http://" + opsSystem.Addr() + path)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "unexpected status of %s", path)
+		resp.Body.Close()
+	}
+
+	assert.Panics(t, func() {
+		initializeOperationsSystem(&config.TopLevel{
+			Operations: config.Operations{ListenAddress: "127.0.0.1:0"},
+			Metrics:    config.Metrics{Provider: "statsd"},
+		})
+	})
+}
+
 func TestInitializeSecureServerConfig(t *testing.T) {
 	initializeSecureServerConfig(
 		&config.TopLevel{
@@ -239,7 +262,7 @@ func TestInitializeMultiChainManager(t *testing.T) {
 	}
 	assert.NotPanics(t, func() {
 		initializeLocalMsp(conf)
-		initializeMultiChainManager(conf, localmsp.NewSigner())
+		initializeMultiChainManager(conf, localmsp.NewSigner(), raft.New(conf.Raft, conf.General.TLS))
 	})
 }
 
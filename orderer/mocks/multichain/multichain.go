@@ -50,6 +50,9 @@ type ConsenterSupport struct {
 
 	// NextBlockVal stores the block created by the most recent CreateNextBlock() call
 	NextBlockVal *cb.Block
+
+	// BlockByIndex maps block numbers to the values returned by Block()
+	BlockByIndex map[uint64]*cb.Block
 }
 
 // BlockCutter returns BlockCutterVal
@@ -95,6 +98,11 @@ func (mcs *ConsenterSupport) Height() uint64 {
 	return mcs.HeightVal
 }
 
+// Block returns the block with the given number from BlockByIndex, or nil if there is none
+func (mcs *ConsenterSupport) Block(number uint64) *cb.Block {
+	return mcs.BlockByIndex[number]
+}
+
 // Sign returns the bytes passed in
 func (mcs *ConsenterSupport) Sign(message []byte) ([]byte, error) {
 	return message, nil
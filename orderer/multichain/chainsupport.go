@@ -78,6 +78,10 @@ type ConsenterSupport interface {
 	ChainID() string // ChainID returns the chain ID this specific consenter instance is associated with
 	// 链当前高度
 	Height() uint64  // Returns the number of blocks on the chain this specific consenter instance is associated with
+
+	// Block returns a block with the given number,
+	// or nil if such a block doesn't exist.
+	Block(number uint64) *cb.Block
 }
 
 // ChainSupport provides a wrapper for the resources backing a chain
@@ -318,3 +322,10 @@ func (cs *chainSupport) WriteBlock(block *cb.Block, committers []filter.Committe
 func (cs *chainSupport) Height() uint64 {
 	return cs.Reader().Height()
 }
+
+func (cs *chainSupport) Block(number uint64) *cb.Block {
+	if cs.Height() <= number {
+		return nil
+	}
+	return ledger.GetBlock(cs.Reader(), number)
+}
@@ -153,3 +153,11 @@ func TestWriteLastConfig(t *testing.T) {
 		assert.Equal(t, expected, lc, "Second block should have config block index of %d, but got %d")
 	})
 }
+
+func TestBlock(t *testing.T) {
+	_, rl := NewRAMLedgerAndFactory(10)
+	cs := &chainSupport{ledgerResources: &ledgerResources{ledger: rl}}
+
+	assert.Equal(t, genesisBlock.Header, cs.Block(0).Header, "Should return the genesis block")
+	assert.Nil(t, cs.Block(1), "Should return nil for a block beyond the height of the chain")
+}
@@ -0,0 +1,530 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// stepBufferSize is the number of messages from other nodes queued up for
+// the chain before new ones are dropped.
+const stepBufferSize = 256
+
+type submitRequest struct {
+	data    []byte
+	resultC chan submitResult
+}
+
+type submitResult struct {
+	ok   bool
+	lead uint64
+}
+
+func newChain(conf localconfig.Raft, peers []uint64, t transport, store *storage, support multichain.ConsenterSupport, lastIndexPersisted uint64) (*chainImpl, error) {
+	snapshot, hs, entries, err := store.load()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the Raft log of channel %s: %s", support.ChainID(), err)
+	}
+
+	log := newRaftLog(snapshot, entries)
+	if lastIndexPersisted < log.snapshot.Index {
+		logger.Panicf("[channel: %s] Ledger is behind the Raft snapshot: last persisted index %d, snapshot index %d",
+			support.ChainID(), lastIndexPersisted, log.snapshot.Index)
+	}
+	if lastIndexPersisted > log.lastIndex() {
+		logger.Panicf("[channel: %s] Raft log is behind the ledger: last persisted index %d, last index in log %d",
+			support.ChainID(), lastIndexPersisted, log.lastIndex())
+	}
+	// The entries up to lastIndexPersisted have been cut into blocks, the
+	// ones which follow are re-applied to restore the state of the block
+	// cutter.
+	log.applied = lastIndexPersisted
+
+	n := newNode(conf.NodeID, peers, support.ChainID(), conf.ElectionTick, conf.HeartbeatTick, log, hs)
+	if log.committed < log.applied {
+		log.committed = log.applied
+	}
+
+	lastCutBlockNumber := getLastCutBlockNumber(support.Height())
+	logger.Infof("[channel: %s] Starting Raft node %d with last persisted index %d and last recorded block %d",
+		support.ChainID(), conf.NodeID, lastIndexPersisted, lastCutBlockNumber)
+
+	errorC := make(chan struct{})
+	close(errorC) // Closed until a leader is known
+
+	return &chainImpl{
+		support:            support,
+		node:               n,
+		storage:            store,
+		transport:          t,
+		tickInterval:       conf.TickInterval,
+		snapshotInterval:   conf.SnapshotInterval,
+		lastIndexPersisted: lastIndexPersisted,
+		lastCutBlockNumber: lastCutBlockNumber,
+
+		submitC: make(chan *submitRequest),
+		stepC:   make(chan *ab.RaftMessage, stepBufferSize),
+		haltC:   make(chan struct{}),
+		doneC:   make(chan struct{}),
+		errorC:  errorC,
+	}, nil
+}
+
+// chainImpl feeds the committed entries of the Raft log of a channel into
+// the block cutter, the same way the Kafka-based chain does with the
+// messages of its partition. All the state below is owned by the goroutine
+// running the main loop.
+type chainImpl struct {
+	support   multichain.ConsenterSupport
+	node      *node
+	storage   *storage
+	transport transport
+
+	tickInterval     time.Duration
+	snapshotInterval uint64
+
+	lastIndexPersisted uint64
+	lastCutBlockNumber uint64
+	pending            bool
+	timer              <-chan time.Time
+
+	submitC chan *submitRequest
+	stepC   chan *ab.RaftMessage
+	// When a Halt() request comes, close the channel. Its closing triggers
+	// the exit of the main loop, which then closes doneC.
+	haltC chan struct{}
+	doneC chan struct{}
+
+	// errorC is closed while the chain doesn't know of any leader.
+	errorLock sync.RWMutex
+	errorC    chan struct{}
+	lead      uint64
+}
+
+// Errored returns a channel which is closed while there is no leader for
+// the channel. Checked by Deliver().
+func (chain *chainImpl) Errored() <-chan struct{} {
+	chain.errorLock.RLock()
+	defer chain.errorLock.RUnlock()
+	return chain.errorC
+}
+
+// Start launches the main loop of the chain. Implements the
+// multichain.Chain interface.
+func (chain *chainImpl) Start() {
+	go chain.run()
+}
+
+// Halt stops the main loop of the chain. Implements the multichain.Chain
+// interface.
+func (chain *chainImpl) Halt() {
+	select {
+	case <-chain.haltC:
+		logger.Warningf("[channel: %s] Halting of chain requested again", chain.support.ChainID())
+	default:
+		logger.Criticalf("[channel: %s] Halting of chain requested", chain.support.ChainID())
+		close(chain.haltC)
+	}
+}
+
+// Enqueue accepts a message and returns true on acceptance, or false
+// otherwise. A follower forwards the message to the leader. Implements the
+// multichain.Chain interface. Called by Broadcast().
+func (chain *chainImpl) Enqueue(env *cb.Envelope) bool {
+	logger.Debugf("[channel: %s] Enqueueing envelope...", chain.support.ChainID())
+	ok, lead := chain.submit(env)
+	if ok {
+		return true
+	}
+	if lead == 0 {
+		logger.Warningf("[channel: %s] Will not enqueue, there is no Raft leader", chain.support.ChainID())
+		return false
+	}
+
+	resp, err := chain.transport.submit(lead, &ab.SubmitRequest{Channel: chain.support.ChainID(), Envelope: env})
+	if err != nil {
+		logger.Errorf("[channel: %s] Cannot forward envelope to leader %d = %s", chain.support.ChainID(), lead, err)
+		return false
+	}
+	if resp.Status != cb.Status_SUCCESS {
+		logger.Warningf("[channel: %s] Leader %d did not accept envelope, status %s", chain.support.ChainID(), lead, resp.Status)
+		return false
+	}
+	return true
+}
+
+// submit proposes the envelope if this node is the leader, otherwise it
+// returns the leader it knows of, if any.
+func (chain *chainImpl) submit(env *cb.Envelope) (bool, uint64) {
+	marshaledEnv, err := utils.Marshal(env)
+	if err != nil {
+		logger.Errorf("[channel: %s] cannot enqueue, unable to marshal envelope = %s", chain.support.ChainID(), err)
+		return false, 0
+	}
+	req := &submitRequest{
+		data:    utils.MarshalOrPanic(newRegularProposal(marshaledEnv)),
+		resultC: make(chan submitResult, 1),
+	}
+	select {
+	case chain.submitC <- req:
+	case <-chain.doneC:
+		logger.Warningf("[channel: %s] Will not enqueue, consenter for this channel has been halted", chain.support.ChainID())
+		return false, 0
+	}
+	res := <-req.resultC
+	return res.ok, res.lead
+}
+
+// step queues up a message received from another node, it is dropped if
+// the chain is falling behind.
+func (chain *chainImpl) step(m *ab.RaftMessage) {
+	select {
+	case chain.stepC <- m:
+	default:
+		logger.Debugf("[channel: %s] Dropping %s message from node %d", chain.support.ChainID(), m.Type, m.From)
+	}
+}
+
+func (chain *chainImpl) run() {
+	ticker := time.NewTicker(chain.tickInterval)
+	defer func() {
+		ticker.Stop()
+		chain.storage.close()
+		chain.setLeader(0)
+		close(chain.doneC)
+	}()
+
+	// Re-apply the committed entries which haven't been cut into blocks yet
+	chain.processReady()
+
+	for {
+		select {
+		case req := <-chain.submitC:
+			ok := chain.node.propose(req.data)
+			req.resultC <- submitResult{ok: ok, lead: chain.node.lead}
+		case m := <-chain.stepC:
+			chain.node.step(m)
+		case <-ticker.C:
+			chain.node.tick()
+		case <-chain.timer:
+			chain.timer = nil
+			if chain.node.state == stateLeader {
+				logger.Debugf("[channel: %s] Time-to-cut block %d timer expired", chain.support.ChainID(), chain.lastCutBlockNumber+1)
+				chain.node.propose(utils.MarshalOrPanic(newTimeToCutProposal(chain.lastCutBlockNumber + 1)))
+			}
+		case <-chain.haltC:
+			logger.Warningf("[channel: %s] Consenter for channel exiting", chain.support.ChainID())
+			return
+		}
+		chain.processReady()
+	}
+}
+
+// processReady persists, sends and applies whatever the node has produced
+// until it has nothing left to do.
+func (chain *chainImpl) processReady() {
+	for {
+		rd := chain.node.ready()
+		if rd.hardState == nil && len(rd.entries) == 0 && rd.snapshot == nil && len(rd.messages) == 0 && len(rd.committed) == 0 {
+			return
+		}
+		if err := chain.storage.save(rd.hardState, rd.entries); err != nil {
+			logger.Panicf("[channel: %s] Cannot persist Raft log = %s", chain.support.ChainID(), err)
+		}
+		if rd.snapshot != nil {
+			if err := chain.catchUp(rd.snapshot); err != nil {
+				logger.Errorf("[channel: %s] Cannot catch up with snapshot at index %d = %s", chain.support.ChainID(), rd.snapshot.Index, err)
+			}
+		}
+		for _, m := range rd.messages {
+			chain.transport.send(m)
+		}
+		chain.apply(rd.committed)
+		chain.maybeSnapshot()
+		chain.setLeader(chain.node.lead)
+	}
+}
+
+// leader returns the leader of the channel, or 0 if there is none.
+func (chain *chainImpl) leader() uint64 {
+	chain.errorLock.RLock()
+	defer chain.errorLock.RUnlock()
+	return chain.lead
+}
+
+func (chain *chainImpl) setLeader(lead uint64) {
+	if lead == chain.lead {
+		return
+	}
+
+	chain.errorLock.Lock()
+	chain.lead = lead
+	if lead == 0 {
+		logger.Warningf("[channel: %s] Lost the Raft leader", chain.support.ChainID())
+		close(chain.errorC)
+	} else {
+		logger.Infof("[channel: %s] Raft leader is node %d", chain.support.ChainID(), lead)
+		select {
+		case <-chain.errorC:
+			chain.errorC = make(chan struct{})
+		default:
+		}
+	}
+	chain.errorLock.Unlock()
+
+	// The previous leader may have gone away before cutting the pending
+	// envelopes
+	if lead == chain.node.id && chain.pending && chain.timer == nil {
+		chain.timer = time.After(chain.support.SharedConfig().BatchTimeout())
+	}
+}
+
+func (chain *chainImpl) apply(entries []*ab.RaftLogEntry) {
+	for _, e := range entries {
+		if e.Index <= chain.node.log.applied {
+			continue
+		}
+		if len(e.Data) > 0 {
+			proposal := &ab.RaftProposal{}
+			if err := proto.Unmarshal(e.Data, proposal); err != nil {
+				logger.Criticalf("[channel: %s] Unable to unmarshal entry %d = %s", chain.support.ChainID(), e.Index, err)
+			} else {
+				switch proposal.Type.(type) {
+				case *ab.RaftProposal_Regular:
+					if err := chain.processRegular(proposal.GetRegular(), e.Index); err != nil {
+						logger.Warningf("[channel: %s] Error when processing entry %d of type REGULAR = %s", chain.support.ChainID(), e.Index, err)
+					}
+				case *ab.RaftProposal_TimeToCut:
+					if err := chain.processTimeToCut(proposal.GetTimeToCut(), e.Index); err != nil {
+						logger.Warningf("[channel: %s] %s", chain.support.ChainID(), err)
+					}
+				}
+			}
+		}
+		chain.node.log.applied = e.Index
+	}
+}
+
+func (chain *chainImpl) processRegular(regular *ab.RaftProposalRegular, index uint64) error {
+	env := new(cb.Envelope)
+	if err := proto.Unmarshal(regular.Payload, env); err != nil {
+		// This shouldn't happen, it should be filtered at ingress
+		return fmt.Errorf("unmarshal/%s", err)
+	}
+	batches, committers, ok, pending := chain.support.BlockCutter().Ordered(env)
+	logger.Debugf("[channel: %s] Ordering results: items in batch = %d, ok = %v, pending = %v", chain.support.ChainID(), len(batches), ok, pending)
+	if !ok {
+		return nil
+	}
+	chain.pending = pending
+
+	persisted := index
+	if pending || len(batches) == 2 {
+		// If the newest envelope is not encapsulated into the first batch,
+		// the LastIndexPersisted of first block should be index-1.
+		persisted--
+	}
+	for i, batch := range batches {
+		chain.writeBlock(batch, committers[i], persisted)
+		logger.Debugf("[channel: %s] Batch filled, just cut block %d - last persisted index is now %d", chain.support.ChainID(), chain.lastCutBlockNumber, persisted)
+		persisted++
+	}
+
+	if len(batches) > 0 {
+		chain.timer = nil
+	}
+	if pending && chain.timer == nil {
+		chain.timer = time.After(chain.support.SharedConfig().BatchTimeout())
+		logger.Debugf("[channel: %s] Just began %s batch timer", chain.support.ChainID(), chain.support.SharedConfig().BatchTimeout().String())
+	}
+	return nil
+}
+
+func (chain *chainImpl) processTimeToCut(ttc *ab.RaftProposalTimeToCut, index uint64) error {
+	ttcNumber := ttc.GetBlockNumber()
+	logger.Debugf("[channel: %s] It's a time-to-cut entry for block %d", chain.support.ChainID(), ttcNumber)
+	if ttcNumber == chain.lastCutBlockNumber+1 {
+		chain.timer = nil
+		batch, committers := chain.support.BlockCutter().Cut()
+		chain.pending = false
+		if len(batch) == 0 {
+			return fmt.Errorf("got right time-to-cut entry (for block %d),"+
+				" no pending requests though; this might indicate a bug", chain.lastCutBlockNumber+1)
+		}
+		chain.writeBlock(batch, committers, index)
+		logger.Debugf("[channel: %s] Proper time-to-cut received, just cut block %d", chain.support.ChainID(), chain.lastCutBlockNumber)
+		return nil
+	} else if ttcNumber > chain.lastCutBlockNumber+1 {
+		return fmt.Errorf("got larger time-to-cut entry (%d) than allowed/expected (%d)"+
+			" - this might indicate a bug", ttcNumber, chain.lastCutBlockNumber+1)
+	}
+	logger.Debugf("[channel: %s] Ignoring stale time-to-cut entry for block %d", chain.support.ChainID(), ttcNumber)
+	return nil
+}
+
+func (chain *chainImpl) writeBlock(batch []*cb.Envelope, committers []filter.Committer, lastIndexPersisted uint64) {
+	block := chain.support.CreateNextBlock(batch)
+	encodedLastIndexPersisted := utils.MarshalOrPanic(&ab.RaftMetadata{LastIndexPersisted: lastIndexPersisted})
+	chain.support.WriteBlock(block, committers, encodedLastIndexPersisted)
+	chain.lastCutBlockNumber++
+	chain.lastIndexPersisted = lastIndexPersisted
+}
+
+// maybeSnapshot compacts the log once SnapshotInterval entries have been
+// cut into blocks since the last snapshot.
+func (chain *chainImpl) maybeSnapshot() {
+	log := chain.node.log
+	if chain.snapshotInterval == 0 || chain.lastIndexPersisted < log.snapshot.Index+chain.snapshotInterval {
+		return
+	}
+	term, ok := log.term(chain.lastIndexPersisted)
+	if !ok {
+		return
+	}
+	block := chain.support.Block(chain.lastCutBlockNumber)
+	if block == nil {
+		logger.Panicf("[channel: %s] Cannot read back block %d", chain.support.ChainID(), chain.lastCutBlockNumber)
+	}
+	snapshot := &ab.RaftSnapshot{
+		Term:  term,
+		Index: chain.lastIndexPersisted,
+		Data:  utils.MarshalOrPanic(block),
+	}
+	log.compact(snapshot)
+	hs := chain.node.hardState()
+	if err := chain.storage.saveSnapshot(snapshot, &hs, log.entries); err != nil {
+		logger.Panicf("[channel: %s] Cannot save snapshot = %s", chain.support.ChainID(), err)
+	}
+	logger.Infof("[channel: %s] Took snapshot at index %d (block %d)", chain.support.ChainID(), snapshot.Index, block.Header.Number)
+}
+
+// catchUp pulls the blocks up to the one carried by the snapshot from the
+// leader, cuts them again from their envelopes and writes them, then
+// replaces the log with the snapshot.
+func (chain *chainImpl) catchUp(snapshot *ab.RaftSnapshot) error {
+	last, err := utils.GetBlockFromBlockBytes(snapshot.Data)
+	if err != nil {
+		return err
+	}
+	if last.Header == nil {
+		return fmt.Errorf("snapshot block has no header")
+	}
+
+	// Pull all the blocks first so that a failure leaves the chain as it is
+	var blocks []*cb.Block
+	for number := chain.support.Height(); number <= last.Header.Number; number++ {
+		resp, err := chain.transport.pull(chain.node.lead, &ab.PullRequest{Channel: chain.support.ChainID(), Number: number})
+		if err != nil {
+			return fmt.Errorf("cannot pull block %d from node %d: %s", number, chain.node.lead, err)
+		}
+		if resp.Status != cb.Status_SUCCESS || resp.Block == nil {
+			return fmt.Errorf("cannot pull block %d from node %d: status %s", number, chain.node.lead, resp.Status)
+		}
+		blocks = append(blocks, resp.Block)
+	}
+
+	// The pending envelopes are part of the pulled blocks
+	chain.support.BlockCutter().Cut()
+	chain.pending = false
+	chain.timer = nil
+
+	for _, block := range blocks {
+		batch, committers, err := chain.recut(block)
+		if err != nil {
+			logger.Panicf("[channel: %s] Cannot cut block %d again = %s", chain.support.ChainID(), block.Header.Number, err)
+		}
+		newBlock := chain.support.CreateNextBlock(batch)
+		if !bytes.Equal(newBlock.Header.DataHash, block.Header.DataHash) {
+			logger.Panicf("[channel: %s] Block %d cut again does not match the one of the leader", chain.support.ChainID(), block.Header.Number)
+		}
+		metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
+		if err != nil {
+			logger.Panicf("[channel: %s] Cannot extract orderer metadata of block %d = %s", chain.support.ChainID(), block.Header.Number, err)
+		}
+		chain.support.WriteBlock(newBlock, committers, metadata.Value)
+		chain.lastCutBlockNumber = block.Header.Number
+		logger.Infof("[channel: %s] Caught up with block %d", chain.support.ChainID(), block.Header.Number)
+	}
+
+	chain.lastIndexPersisted = snapshot.Index
+	chain.node.restore(snapshot)
+	hs := chain.node.hardState()
+	if err := chain.storage.saveSnapshot(snapshot, &hs, nil); err != nil {
+		logger.Panicf("[channel: %s] Cannot save snapshot = %s", chain.support.ChainID(), err)
+	}
+	return nil
+}
+
+// recut feeds the envelopes of the block to the block cutter, which is
+// expected to return them as a single batch.
+func (chain *chainImpl) recut(block *cb.Block) ([]*cb.Envelope, []filter.Committer, error) {
+	if block.Data == nil {
+		return nil, nil, fmt.Errorf("block has no data")
+	}
+	var batches [][]*cb.Envelope
+	var committers [][]filter.Committer
+	pending := false
+	for i, data := range block.Data.Data {
+		env, err := utils.UnmarshalEnvelope(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot unmarshal envelope %d: %s", i, err)
+		}
+		b, c, ok, p := chain.support.BlockCutter().Ordered(env)
+		if !ok {
+			return nil, nil, fmt.Errorf("envelope %d was rejected", i)
+		}
+		batches = append(batches, b...)
+		committers = append(committers, c...)
+		pending = p
+	}
+	if pending {
+		b, c := chain.support.BlockCutter().Cut()
+		batches = append(batches, b)
+		committers = append(committers, c)
+	}
+	if len(batches) != 1 {
+		return nil, nil, fmt.Errorf("envelopes were cut into %d batches", len(batches))
+	}
+	return batches[0], committers[0], nil
+}
+
+// Helper functions
+
+func getLastCutBlockNumber(blockchainHeight uint64) uint64 {
+	return blockchainHeight - 1
+}
+
+func newRegularProposal(payload []byte) *ab.RaftProposal {
+	return &ab.RaftProposal{
+		Type: &ab.RaftProposal_Regular{
+			Regular: &ab.RaftProposalRegular{
+				Payload: payload,
+			},
+		},
+	}
+}
+
+func newTimeToCutProposal(blockNumber uint64) *ab.RaftProposal {
+	return &ab.RaftProposal{
+		Type: &ab.RaftProposal_TimeToCut{
+			TimeToCut: &ab.RaftProposalTimeToCut{
+				BlockNumber: blockNumber,
+			},
+		},
+	}
+}
@@ -0,0 +1,518 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	logging "github.com/op/go-logging"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func init() {
+	logging.SetLevel(logging.INFO, pkgLogID)
+}
+
+const (
+	testChainID  = "testchain"
+	waitTimeout  = 10 * time.Second
+	pollInterval = 10 * time.Millisecond
+)
+
+// testSupport implements multichain.ConsenterSupport on top of a RAM ledger
+// and a real block cutter, so that the blocks of different nodes can be
+// compared.
+type testSupport struct {
+	sharedConfig *mockconfig.Orderer
+	cutter       blockcutter.Receiver
+
+	lock   sync.Mutex
+	ledger ledger.ReadWriter
+}
+
+func newTestSupport(sharedConfig *mockconfig.Orderer) *testSupport {
+	rl, _ := ramledger.New(1000).GetOrCreate(testChainID)
+	genesis := cb.NewBlock(0, nil)
+	genesis.Data = &cb.BlockData{}
+	genesis.Header.DataHash = genesis.Data.Hash()
+	rl.Append(genesis)
+	return &testSupport{
+		sharedConfig: sharedConfig,
+		cutter:       blockcutter.NewReceiverImpl(sharedConfig, filter.NewRuleSet([]filter.Rule{filter.EmptyRejectRule, filter.AcceptRule})),
+		ledger:       rl,
+	}
+}
+
+// restart discards the state of the block cutter, as a restart of the
+// orderer does.
+func (ts *testSupport) restart() {
+	ts.cutter = blockcutter.NewReceiverImpl(ts.sharedConfig, filter.NewRuleSet([]filter.Rule{filter.EmptyRejectRule, filter.AcceptRule}))
+}
+
+func (ts *testSupport) BlockCutter() blockcutter.Receiver { return ts.cutter }
+func (ts *testSupport) SharedConfig() config.Orderer      { return ts.sharedConfig }
+func (ts *testSupport) ChainID() string                   { return testChainID }
+
+func (ts *testSupport) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	return ledger.CreateNextBlock(ts.ledger, messages)
+}
+
+func (ts *testSupport) WriteBlock(block *cb.Block, committers []filter.Committer, encodedMetadataValue []byte) *cb.Block {
+	for _, committer := range committers {
+		committer.Commit()
+	}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{Value: encodedMetadataValue})
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	if err := ts.ledger.Append(block); err != nil {
+		panic(err)
+	}
+	return block
+}
+
+func (ts *testSupport) Height() uint64 {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	return ts.ledger.Height()
+}
+
+func (ts *testSupport) Block(number uint64) *cb.Block {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	if ts.ledger.Height() <= number {
+		return nil
+	}
+	return ledger.GetBlock(ts.ledger, number)
+}
+
+func (ts *testSupport) Sign(message []byte) ([]byte, error) { return message, nil }
+
+func (ts *testSupport) NewSignatureHeader() (*cb.SignatureHeader, error) {
+	return &cb.SignatureHeader{}, nil
+}
+
+// network routes the messages of the in-memory transports to the
+// consenters, messages from or to a disconnected node are dropped.
+type network struct {
+	lock       sync.RWMutex
+	consenters map[uint64]*consenterImpl
+	down       map[uint64]bool
+}
+
+func (n *network) consenter(from, to uint64) (*consenterImpl, error) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	if n.down[from] || n.down[to] {
+		return nil, fmt.Errorf("node %d is unreachable from node %d", to, from)
+	}
+	c, ok := n.consenters[to]
+	if !ok {
+		return nil, fmt.Errorf("unknown node %d", to)
+	}
+	return c, nil
+}
+
+type memTransport struct {
+	id  uint64
+	net *network
+}
+
+func (t *memTransport) send(m *ab.RaftMessage) {
+	if c, err := t.net.consenter(t.id, m.To); err == nil {
+		c.Step(context.Background(), m)
+	}
+}
+
+func (t *memTransport) submit(dest uint64, req *ab.SubmitRequest) (*ab.SubmitResponse, error) {
+	c, err := t.net.consenter(t.id, dest)
+	if err != nil {
+		return nil, err
+	}
+	return c.Submit(context.Background(), req)
+}
+
+func (t *memTransport) pull(dest uint64, req *ab.PullRequest) (*ab.PullResponse, error) {
+	c, err := t.net.consenter(t.id, dest)
+	if err != nil {
+		return nil, err
+	}
+	return c.Pull(context.Background(), req)
+}
+
+func (t *memTransport) stop() {}
+
+type testCluster struct {
+	t            *testing.T
+	dir          string
+	net          *network
+	sharedConfig *mockconfig.Orderer
+	conf         localconfig.Raft
+	supports     map[uint64]*testSupport
+	chains       map[uint64]*chainImpl
+}
+
+func newTestCluster(t *testing.T, size int, snapshotInterval uint64) *testCluster {
+	dir, err := ioutil.TempDir("", "raft-chain")
+	if err != nil {
+		t.Fatalf("Cannot create temp dir: %s", err)
+	}
+	tc := &testCluster{
+		t:   t,
+		dir: dir,
+		net: &network{consenters: make(map[uint64]*consenterImpl), down: make(map[uint64]bool)},
+		sharedConfig: &mockconfig.Orderer{
+			BatchTimeoutVal: 100 * time.Millisecond,
+			BatchSizeVal:    &ab.BatchSize{MaxMessageCount: 2, AbsoluteMaxBytes: 1024 * 1024, PreferredMaxBytes: 1024 * 1024},
+		},
+		conf: localconfig.Raft{
+			TickInterval:     10 * time.Millisecond,
+			ElectionTick:     10,
+			HeartbeatTick:    1,
+			SnapshotInterval: snapshotInterval,
+		},
+		supports: make(map[uint64]*testSupport),
+		chains:   make(map[uint64]*chainImpl),
+	}
+	for i := 1; i <= size; i++ {
+		tc.conf.Nodes = append(tc.conf.Nodes, localconfig.RaftNode{ID: uint64(i), Address: fmt.Sprintf("node%d", i)})
+	}
+	for i := 1; i <= size; i++ {
+		id := uint64(i)
+		tc.supports[id] = newTestSupport(tc.sharedConfig)
+		tc.start(id)
+	}
+	return tc
+}
+
+func (tc *testCluster) start(id uint64) {
+	conf := tc.conf
+	conf.NodeID = id
+	conf.WALDir = filepath.Join(tc.dir, fmt.Sprintf("node%d", id), "wal")
+	conf.SnapDir = filepath.Join(tc.dir, fmt.Sprintf("node%d", id), "snap")
+	consenter := newConsenter(conf, &memTransport{id: id, net: tc.net})
+
+	support := tc.supports[id]
+	lastBlock := support.Block(support.Height() - 1)
+	metadata, err := utils.GetMetadataFromBlock(lastBlock, cb.BlockMetadataIndex_ORDERER)
+	if err != nil {
+		tc.t.Fatalf("Cannot read metadata of last block: %s", err)
+	}
+	chain, err := consenter.HandleChain(support, metadata)
+	if err != nil {
+		tc.t.Fatalf("Cannot start node %d: %s", id, err)
+	}
+
+	tc.net.lock.Lock()
+	tc.net.consenters[id] = consenter
+	tc.net.lock.Unlock()
+	tc.chains[id] = chain.(*chainImpl)
+	chain.Start()
+}
+
+func (tc *testCluster) halt(id uint64) {
+	chain := tc.chains[id]
+	chain.Halt()
+	<-chain.doneC
+	tc.net.lock.Lock()
+	delete(tc.net.consenters, id)
+	tc.net.lock.Unlock()
+}
+
+func (tc *testCluster) stop() {
+	for id := range tc.chains {
+		tc.chains[id].Halt()
+		<-tc.chains[id].doneC
+	}
+	os.RemoveAll(tc.dir)
+}
+
+func (tc *testCluster) disconnect(id uint64) {
+	tc.net.lock.Lock()
+	tc.net.down[id] = true
+	tc.net.lock.Unlock()
+}
+
+func (tc *testCluster) connect(id uint64) {
+	tc.net.lock.Lock()
+	delete(tc.net.down, id)
+	tc.net.lock.Unlock()
+}
+
+// waitForLeader waits until all the given nodes agree on a leader among them.
+func (tc *testCluster) waitForLeader(ids ...uint64) uint64 {
+	var lead uint64
+	tc.eventually(func() bool {
+		lead = tc.chains[ids[0]].leader()
+		if lead == 0 {
+			return false
+		}
+		found := false
+		for _, id := range ids {
+			if tc.chains[id].leader() != lead {
+				return false
+			}
+			found = found || id == lead
+		}
+		return found
+	}, "nodes %v did not agree on a leader", ids)
+	return lead
+}
+
+func (tc *testCluster) waitForHeight(height uint64, ids ...uint64) {
+	tc.eventually(func() bool {
+		for _, id := range ids {
+			if tc.supports[id].Height() < height {
+				return false
+			}
+		}
+		return true
+	}, "nodes %v did not reach height %d", ids, height)
+}
+
+func (tc *testCluster) eventually(cond func() bool, format string, args ...interface{}) {
+	deadline := time.Now().Add(waitTimeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(pollInterval)
+	}
+	tc.t.Fatalf(format, args...)
+}
+
+// assertSameLedgers checks that the ledgers of the given nodes hold the same
+// blocks.
+func (tc *testCluster) assertSameLedgers(ids ...uint64) {
+	height := tc.supports[ids[0]].Height()
+	for _, id := range ids[1:] {
+		assert.Equal(tc.t, height, tc.supports[id].Height(), "Node %d", id)
+	}
+	for number := uint64(0); number < height; number++ {
+		expected := tc.supports[ids[0]].Block(number)
+		for _, id := range ids[1:] {
+			block := tc.supports[id].Block(number)
+			if assert.NotNil(tc.t, block, "Node %d has no block %d", id, number) {
+				assert.Equal(tc.t, expected.Header.Hash(), block.Header.Hash(), "Block %d of node %d", number, id)
+			}
+		}
+	}
+}
+
+func lastIndexPersisted(t *testing.T, block *cb.Block) uint64 {
+	metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
+	assert.NoError(t, err)
+	raftMetadata := &ab.RaftMetadata{}
+	assert.NoError(t, proto.Unmarshal(metadata.Value, raftMetadata))
+	return raftMetadata.LastIndexPersisted
+}
+
+func testEnvelope(i int) *cb.Envelope {
+	return &cb.Envelope{Payload: []byte(fmt.Sprintf("TEST_MESSAGE_%d", i))}
+}
+
+func TestSingleNode(t *testing.T) {
+	tc := newTestCluster(t, 1, 0)
+	defer tc.stop()
+
+	chain := tc.chains[1]
+	tc.waitForLeader(1)
+	select {
+	case <-chain.Errored():
+		t.Fatal("Errored should be open while there is a leader")
+	default:
+	}
+
+	assert.True(t, chain.Enqueue(testEnvelope(1)))
+	assert.True(t, chain.Enqueue(testEnvelope(2)))
+	tc.waitForHeight(2, 1)
+
+	block := tc.supports[1].Block(1)
+	assert.Len(t, block.Data.Data, 2, "Block should have been cut by size")
+	// The empty entry of the leader comes first
+	assert.Equal(t, uint64(3), lastIndexPersisted(t, block))
+
+	// A single envelope is cut by the batch timer
+	assert.True(t, chain.Enqueue(testEnvelope(3)))
+	tc.waitForHeight(3, 1)
+	block = tc.supports[1].Block(2)
+	assert.Len(t, block.Data.Data, 1)
+	assert.Equal(t, uint64(5), lastIndexPersisted(t, block), "Time-to-cut entry should be the last one persisted")
+}
+
+func TestEnqueueWithoutLeader(t *testing.T) {
+	tc := newTestCluster(t, 3, 0)
+	defer tc.stop()
+
+	for id := range tc.chains {
+		tc.disconnect(id)
+	}
+	chain := tc.chains[1]
+	select {
+	case <-chain.Errored():
+	default:
+		t.Fatal("Errored should be closed while there is no leader")
+	}
+	assert.False(t, chain.Enqueue(testEnvelope(1)))
+}
+
+func TestReplication(t *testing.T) {
+	tc := newTestCluster(t, 3, 0)
+	defer tc.stop()
+
+	lead := tc.waitForLeader(1, 2, 3)
+	follower := lead%3 + 1
+
+	// Envelopes are ordered no matter which node they are submitted to
+	assert.True(t, tc.chains[lead].Enqueue(testEnvelope(1)))
+	assert.True(t, tc.chains[follower].Enqueue(testEnvelope(2)))
+	assert.True(t, tc.chains[follower].Enqueue(testEnvelope(3)))
+	tc.waitForHeight(3, 1, 2, 3)
+	tc.assertSameLedgers(1, 2, 3)
+
+	var envelopes int
+	for number := uint64(1); number < 3; number++ {
+		envelopes += len(tc.supports[lead].Block(number).Data.Data)
+	}
+	assert.Equal(t, 3, envelopes)
+}
+
+func TestChainLeaderFailover(t *testing.T) {
+	tc := newTestCluster(t, 3, 0)
+	defer tc.stop()
+
+	lead := tc.waitForLeader(1, 2, 3)
+	assert.True(t, tc.chains[lead].Enqueue(testEnvelope(1)))
+	assert.True(t, tc.chains[lead].Enqueue(testEnvelope(2)))
+	tc.waitForHeight(2, 1, 2, 3)
+
+	tc.disconnect(lead)
+	var others []uint64
+	for id := range tc.chains {
+		if id != lead {
+			others = append(others, id)
+		}
+	}
+	newLead := tc.waitForLeader(others...)
+	assert.NotEqual(t, lead, newLead)
+
+	assert.True(t, tc.chains[others[0]].Enqueue(testEnvelope(3)))
+	assert.True(t, tc.chains[others[1]].Enqueue(testEnvelope(4)))
+	tc.waitForHeight(3, others...)
+
+	// The old leader catches up once it is back
+	tc.connect(lead)
+	tc.waitForLeader(1, 2, 3)
+	tc.waitForHeight(3, 1, 2, 3)
+	tc.assertSameLedgers(1, 2, 3)
+}
+
+func TestRestart(t *testing.T) {
+	tc := newTestCluster(t, 1, 0)
+	defer tc.stop()
+
+	tc.waitForLeader(1)
+	tc.sharedConfig.BatchTimeoutVal = time.Hour
+	for i := 1; i <= 3; i++ {
+		assert.True(t, tc.chains[1].Enqueue(testEnvelope(i)))
+	}
+	tc.waitForHeight(2, 1)
+
+	// The third envelope is pending in the block cutter, it has to be
+	// ordered again from the log after the restart
+	tc.halt(1)
+	tc.supports[1].restart()
+	tc.sharedConfig.BatchTimeoutVal = 100 * time.Millisecond
+	tc.start(1)
+	tc.waitForLeader(1)
+	tc.waitForHeight(3, 1)
+
+	block := tc.supports[1].Block(2)
+	assert.Len(t, block.Data.Data, 1)
+	assert.Equal(t, utils.MarshalOrPanic(testEnvelope(3)), block.Data.Data[0])
+}
+
+func TestSnapshotCatchUp(t *testing.T) {
+	tc := newTestCluster(t, 3, 4)
+	defer tc.stop()
+
+	lead := tc.waitForLeader(1, 2, 3)
+	lagging := lead%3 + 1
+	tc.disconnect(lagging)
+
+	for i := 1; i <= 12; i++ {
+		assert.True(t, tc.chains[lead].Enqueue(testEnvelope(i)))
+	}
+	var others []uint64
+	for id := range tc.chains {
+		if id != lagging {
+			others = append(others, id)
+		}
+	}
+	tc.waitForHeight(7, others...)
+	tc.eventually(func() bool {
+		_, err := os.Stat(filepath.Join(tc.dir, fmt.Sprintf("node%d", lead), "snap", testChainID, snapshotFileName))
+		return err == nil
+	}, "leader did not take a snapshot")
+
+	tc.connect(lagging)
+	tc.waitForHeight(7, 1, 2, 3)
+	tc.assertSameLedgers(1, 2, 3)
+
+	// The node which caught up keeps ordering
+	assert.True(t, tc.chains[lagging].Enqueue(testEnvelope(13)))
+	assert.True(t, tc.chains[lagging].Enqueue(testEnvelope(14)))
+	tc.waitForHeight(8, 1, 2, 3)
+	tc.assertSameLedgers(1, 2, 3)
+
+	// And restarts from its snapshot
+	tc.halt(lagging)
+	tc.supports[lagging].restart()
+	tc.start(lagging)
+	tc.waitForLeader(1, 2, 3)
+	assert.True(t, tc.chains[lead].Enqueue(testEnvelope(15)))
+	assert.True(t, tc.chains[lead].Enqueue(testEnvelope(16)))
+	tc.waitForHeight(9, 1, 2, 3)
+	tc.assertSameLedgers(1, 2, 3)
+}
+
+func TestHalt(t *testing.T) {
+	tc := newTestCluster(t, 1, 0)
+	defer tc.stop()
+
+	tc.waitForLeader(1)
+	chain := tc.chains[1]
+	chain.Halt()
+	chain.Halt() // Halting again is harmless
+	<-chain.doneC
+
+	select {
+	case <-chain.Errored():
+	default:
+		t.Fatal("Errored should be closed once the chain has been halted")
+	}
+	assert.False(t, chain.Enqueue(testEnvelope(1)))
+}
@@ -0,0 +1,172 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	logging "github.com/op/go-logging"
+	"golang.org/x/net/context"
+)
+
+const pkgLogID = "orderer/raft"
+
+var logger *logging.Logger
+
+func init() {
+	logger = flogging.MustGetLogger(pkgLogID)
+}
+
+// Consenter is a Raft-based consenter. Besides handling the chains of the
+// orderer, it serves the Cluster service through which the orderers which
+// make up the Raft cluster replicate the chains among themselves.
+type Consenter interface {
+	multichain.Consenter
+	ab.ClusterServer
+}
+
+// New creates a Raft-based consenter. Called by orderer's main.go.
+func New(conf localconfig.Raft, tlsConf localconfig.TLS) Consenter {
+	t, err := newGRPCTransport(conf.Nodes, tlsConf)
+	if err != nil {
+		logger.Panicf("Cannot set up the transport of the Raft cluster: %s", err)
+	}
+	return newConsenter(conf, t)
+}
+
+func newConsenter(conf localconfig.Raft, t transport) *consenterImpl {
+	return &consenterImpl{
+		conf:      conf,
+		transport: t,
+		chains:    make(map[string]*chainImpl),
+	}
+}
+
+// consenterImpl holds the chains of all the channels. Every channel is
+// replicated by all the nodes listed in the local configuration.
+type consenterImpl struct {
+	conf      localconfig.Raft
+	transport transport
+
+	lock   sync.RWMutex
+	chains map[string]*chainImpl
+}
+
+// HandleChain creates/returns a reference to a multichain.Chain object for the
+// given set of support resources. Implements the multichain.Consenter
+// interface.
+func (consenter *consenterImpl) HandleChain(support multichain.ConsenterSupport, metadata *cb.Metadata) (multichain.Chain, error) {
+	peers, err := consenter.peers()
+	if err != nil {
+		return nil, err
+	}
+
+	lastIndexPersisted := getLastIndexPersisted(metadata.Value, support.ChainID())
+	store, err := newStorage(
+		filepath.Join(consenter.conf.WALDir, support.ChainID()),
+		filepath.Join(consenter.conf.SnapDir, support.ChainID()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot set up storage for channel %s: %s", support.ChainID(), err)
+	}
+
+	chain, err := newChain(consenter.conf, peers, consenter.transport, store, support, lastIndexPersisted)
+	if err != nil {
+		store.close()
+		return nil, err
+	}
+
+	consenter.lock.Lock()
+	consenter.chains[support.ChainID()] = chain
+	consenter.lock.Unlock()
+	return chain, nil
+}
+
+func (consenter *consenterImpl) peers() ([]uint64, error) {
+	if consenter.conf.NodeID == 0 {
+		return nil, fmt.Errorf("Raft.NodeID must be set")
+	}
+	var peers []uint64
+	found := false
+	for _, n := range consenter.conf.Nodes {
+		if n.ID == 0 {
+			return nil, fmt.Errorf("node %s has no ID", n.Address)
+		}
+		if n.ID == consenter.conf.NodeID {
+			found = true
+		}
+		peers = append(peers, n.ID)
+	}
+	if !found {
+		return nil, fmt.Errorf("node %d is not listed in Raft.Nodes", consenter.conf.NodeID)
+	}
+	return peers, nil
+}
+
+func (consenter *consenterImpl) chain(chainID string) *chainImpl {
+	consenter.lock.RLock()
+	defer consenter.lock.RUnlock()
+	return consenter.chains[chainID]
+}
+
+// Step hands a message of another node over to the chain it belongs to.
+func (consenter *consenterImpl) Step(ctx context.Context, m *ab.RaftMessage) (*ab.StepResponse, error) {
+	chain := consenter.chain(m.Channel)
+	if chain == nil {
+		return nil, fmt.Errorf("channel %s does not exist", m.Channel)
+	}
+	chain.step(m)
+	return &ab.StepResponse{}, nil
+}
+
+// Submit orders an envelope forwarded by a follower, if this node is the
+// leader of the channel.
+func (consenter *consenterImpl) Submit(ctx context.Context, req *ab.SubmitRequest) (*ab.SubmitResponse, error) {
+	chain := consenter.chain(req.Channel)
+	if chain == nil {
+		return &ab.SubmitResponse{Status: cb.Status_NOT_FOUND}, nil
+	}
+	if req.Envelope == nil {
+		return &ab.SubmitResponse{Status: cb.Status_BAD_REQUEST}, nil
+	}
+	if ok, _ := chain.submit(req.Envelope); !ok {
+		return &ab.SubmitResponse{Status: cb.Status_SERVICE_UNAVAILABLE}, nil
+	}
+	return &ab.SubmitResponse{Status: cb.Status_SUCCESS}, nil
+}
+
+// Pull returns a block of the channel.
+func (consenter *consenterImpl) Pull(ctx context.Context, req *ab.PullRequest) (*ab.PullResponse, error) {
+	chain := consenter.chain(req.Channel)
+	if chain == nil {
+		return &ab.PullResponse{Status: cb.Status_NOT_FOUND}, nil
+	}
+	block := chain.support.Block(req.Number)
+	if block == nil {
+		return &ab.PullResponse{Status: cb.Status_NOT_FOUND}, nil
+	}
+	return &ab.PullResponse{Status: cb.Status_SUCCESS, Block: block}, nil
+}
+
+func getLastIndexPersisted(metadataValue []byte, chainID string) uint64 {
+	if metadataValue != nil {
+		raftMetadata := &ab.RaftMetadata{}
+		if err := proto.Unmarshal(metadataValue, raftMetadata); err != nil {
+			logger.Panicf("[channel: %s] Ledger may be corrupted: "+
+				"cannot unmarshal orderer metadata in most recent block", chainID)
+		}
+		return raftMetadata.LastIndexPersisted
+	}
+	return 0
+}
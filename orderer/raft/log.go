@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	ab "github.com/hyperledger/fabric/protos/orderer"
+)
+
+// raftLog holds the entries of the Raft log which follow the most recent
+// snapshot. The entries up to and including stable have been written to the
+// WAL, the entries up to and including applied have been cut into blocks.
+type raftLog struct {
+	snapshot *ab.RaftSnapshot
+	entries  []*ab.RaftLogEntry
+
+	committed uint64
+	applied   uint64
+	stable    uint64
+}
+
+func newRaftLog(snapshot *ab.RaftSnapshot, entries []*ab.RaftLogEntry) *raftLog {
+	if snapshot == nil {
+		snapshot = &ab.RaftSnapshot{}
+	}
+	l := &raftLog{snapshot: snapshot, entries: entries}
+	l.stable = l.lastIndex()
+	l.committed = snapshot.Index
+	l.applied = snapshot.Index
+	return l
+}
+
+func (l *raftLog) firstIndex() uint64 {
+	return l.snapshot.Index + 1
+}
+
+func (l *raftLog) lastIndex() uint64 {
+	return l.snapshot.Index + uint64(len(l.entries))
+}
+
+func (l *raftLog) lastTerm() uint64 {
+	t, _ := l.term(l.lastIndex())
+	return t
+}
+
+// term returns the term of the entry at the given index, the second return
+// value is false if the entry has been compacted or doesn't exist yet.
+func (l *raftLog) term(index uint64) (uint64, bool) {
+	switch {
+	case index == l.snapshot.Index:
+		return l.snapshot.Term, true
+	case index < l.snapshot.Index || index > l.lastIndex():
+		return 0, false
+	default:
+		return l.entries[index-l.firstIndex()].Term, true
+	}
+}
+
+// slice returns the entries in [lo, hi], lo must be greater than the index
+// of the snapshot.
+func (l *raftLog) slice(lo, hi uint64) []*ab.RaftLogEntry {
+	if hi > l.lastIndex() {
+		hi = l.lastIndex()
+	}
+	if lo > hi {
+		return nil
+	}
+	return l.entries[lo-l.firstIndex() : hi-l.firstIndex()+1]
+}
+
+// append adds the given entries to the log, dropping the existing entries
+// which conflict with them.
+func (l *raftLog) append(entries ...*ab.RaftLogEntry) {
+	for i, e := range entries {
+		if t, ok := l.term(e.Index); ok && t == e.Term {
+			continue
+		}
+		if e.Index <= l.committed {
+			logger.Panicf("Entry %d conflicts with the committed log (committed = %d)", e.Index, l.committed)
+		}
+		l.entries = append(l.entries[:e.Index-l.firstIndex()], entries[i:]...)
+		if l.stable >= e.Index {
+			l.stable = e.Index - 1
+		}
+		return
+	}
+}
+
+func (l *raftLog) commitTo(index uint64) {
+	if index > l.lastIndex() {
+		index = l.lastIndex()
+	}
+	if index > l.committed {
+		l.committed = index
+	}
+}
+
+// unstable returns the entries which haven't been written to the WAL yet.
+func (l *raftLog) unstable() []*ab.RaftLogEntry {
+	return l.slice(l.stable+1, l.lastIndex())
+}
+
+// nextCommitted returns the committed entries which haven't been applied yet.
+func (l *raftLog) nextCommitted() []*ab.RaftLogEntry {
+	if l.committed <= l.applied {
+		return nil
+	}
+	return l.slice(l.applied+1, l.committed)
+}
+
+// compact replaces the prefix of the log covered by the snapshot.
+func (l *raftLog) compact(snapshot *ab.RaftSnapshot) {
+	l.entries = append([]*ab.RaftLogEntry(nil), l.slice(snapshot.Index+1, l.lastIndex())...)
+	l.snapshot = snapshot
+}
+
+// restore replaces the whole log with the snapshot.
+func (l *raftLog) restore(snapshot *ab.RaftSnapshot) {
+	l.entries = nil
+	l.snapshot = snapshot
+	l.committed = snapshot.Index
+	l.applied = snapshot.Index
+	l.stable = snapshot.Index
+}
@@ -0,0 +1,452 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"math/rand"
+	"sort"
+
+	ab "github.com/hyperledger/fabric/protos/orderer"
+)
+
+// maxEntriesPerMessage caps the number of entries the leader sends in a
+// single APPEND message.
+const maxEntriesPerMessage = 64
+
+type stateType int
+
+const (
+	stateFollower stateType = iota
+	stateCandidate
+	stateLeader
+)
+
+var stateNames = map[stateType]string{
+	stateFollower:  "follower",
+	stateCandidate: "candidate",
+	stateLeader:    "leader",
+}
+
+func (s stateType) String() string {
+	return stateNames[s]
+}
+
+// progress is the view the leader has of the log of a follower.
+type progress struct {
+	match uint64
+	next  uint64
+}
+
+// uint64Slice sorts indexes in increasing order.
+type uint64Slice []uint64
+
+func (s uint64Slice) Len() int           { return len(s) }
+func (s uint64Slice) Less(i, j int) bool { return s[i] < s[j] }
+func (s uint64Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// ready carries the work the chain has to do after the node has been
+// stepped, ticked or has accepted a proposal: persist the hard state and the
+// entries, install the received snapshot, send the messages and apply the
+// committed entries, in that order.
+type ready struct {
+	hardState *ab.RaftHardState
+	entries   []*ab.RaftLogEntry
+	snapshot  *ab.RaftSnapshot
+	messages  []*ab.RaftMessage
+	committed []*ab.RaftLogEntry
+}
+
+// node is the Raft state machine of a single channel. It does no I/O on its
+// own; see ready.
+type node struct {
+	id      uint64
+	peers   []uint64
+	channel string
+
+	term  uint64
+	vote  uint64
+	lead  uint64
+	state stateType
+
+	log *raftLog
+
+	electionTick              int
+	heartbeatTick             int
+	electionElapsed           int
+	heartbeatElapsed          int
+	randomizedElectionTimeout int
+	rand                      *rand.Rand
+
+	votes    map[uint64]bool
+	progress map[uint64]*progress
+
+	msgs            []*ab.RaftMessage
+	pendingSnapshot *ab.RaftSnapshot
+	prevHardState   ab.RaftHardState
+}
+
+func newNode(id uint64, peers []uint64, channel string, electionTick, heartbeatTick int, log *raftLog, hs *ab.RaftHardState) *node {
+	n := &node{
+		id:            id,
+		peers:         peers,
+		channel:       channel,
+		log:           log,
+		electionTick:  electionTick,
+		heartbeatTick: heartbeatTick,
+		rand:          rand.New(rand.NewSource(int64(id) ^ rand.Int63())),
+	}
+	if hs != nil {
+		n.term = hs.Term
+		n.vote = hs.Vote
+		log.commitTo(hs.Commit)
+	}
+	n.prevHardState = n.hardState()
+	n.becomeFollower(n.term, 0)
+	return n
+}
+
+func (n *node) quorum() int {
+	return len(n.peers)/2 + 1
+}
+
+func (n *node) hardState() ab.RaftHardState {
+	return ab.RaftHardState{Term: n.term, Vote: n.vote, Commit: n.log.committed}
+}
+
+func (n *node) resetRandomizedElectionTimeout() {
+	n.randomizedElectionTimeout = n.electionTick + n.rand.Intn(n.electionTick)
+}
+
+func (n *node) reset(term uint64) {
+	if n.term != term {
+		n.term = term
+		n.vote = 0
+	}
+	n.lead = 0
+	n.electionElapsed = 0
+	n.heartbeatElapsed = 0
+	n.resetRandomizedElectionTimeout()
+	n.votes = make(map[uint64]bool)
+	n.progress = nil
+}
+
+func (n *node) becomeFollower(term uint64, lead uint64) {
+	n.reset(term)
+	n.state = stateFollower
+	n.lead = lead
+	logger.Debugf("[channel: %s] Node %d became follower at term %d", n.channel, n.id, n.term)
+}
+
+func (n *node) becomeCandidate() {
+	n.reset(n.term + 1)
+	n.state = stateCandidate
+	n.vote = n.id
+	n.votes[n.id] = true
+	logger.Infof("[channel: %s] Node %d became candidate at term %d", n.channel, n.id, n.term)
+}
+
+func (n *node) becomeLeader() {
+	n.reset(n.term)
+	n.state = stateLeader
+	n.lead = n.id
+	n.progress = make(map[uint64]*progress)
+	for _, id := range n.peers {
+		n.progress[id] = &progress{next: n.log.lastIndex() + 1}
+	}
+	logger.Infof("[channel: %s] Node %d became leader at term %d", n.channel, n.id, n.term)
+
+	// An empty entry of the new term lets the leader commit the entries
+	// of the previous terms, see section 5.4.2 of the Raft paper.
+	n.appendEntry(nil)
+}
+
+func (n *node) campaign() {
+	n.becomeCandidate()
+	if n.quorum() == 1 {
+		n.becomeLeader()
+		return
+	}
+	for _, id := range n.peers {
+		if id == n.id {
+			continue
+		}
+		n.send(&ab.RaftMessage{
+			Type:    ab.RaftMessage_VOTE,
+			To:      id,
+			Index:   n.log.lastIndex(),
+			LogTerm: n.log.lastTerm(),
+		})
+	}
+}
+
+// tick advances the logical clock of the node by a single tick.
+func (n *node) tick() {
+	if n.state == stateLeader {
+		n.heartbeatElapsed++
+		if n.heartbeatElapsed >= n.heartbeatTick {
+			n.heartbeatElapsed = 0
+			n.broadcastAppend()
+		}
+		return
+	}
+	n.electionElapsed++
+	if n.electionElapsed >= n.randomizedElectionTimeout {
+		n.campaign()
+	}
+}
+
+// propose appends data to the log, it returns false if the node is not the
+// leader.
+func (n *node) propose(data []byte) bool {
+	if n.state != stateLeader {
+		return false
+	}
+	n.appendEntry(data)
+	return true
+}
+
+func (n *node) appendEntry(data []byte) {
+	n.log.append(&ab.RaftLogEntry{Term: n.term, Index: n.log.lastIndex() + 1, Data: data})
+	n.progress[n.id].match = n.log.lastIndex()
+	n.progress[n.id].next = n.log.lastIndex() + 1
+	n.maybeCommit()
+	n.broadcastAppend()
+}
+
+func (n *node) maybeCommit() bool {
+	matches := make([]uint64, 0, len(n.peers))
+	for _, id := range n.peers {
+		matches = append(matches, n.progress[id].match)
+	}
+	sort.Sort(sort.Reverse(uint64Slice(matches)))
+	index := matches[n.quorum()-1]
+	if index <= n.log.committed {
+		return false
+	}
+	if t, _ := n.log.term(index); t != n.term {
+		return false
+	}
+	n.log.commitTo(index)
+	return true
+}
+
+func (n *node) broadcastAppend() {
+	for _, id := range n.peers {
+		if id != n.id {
+			n.sendAppend(id)
+		}
+	}
+}
+
+func (n *node) sendAppend(to uint64) {
+	pr := n.progress[to]
+	prevIndex := pr.next - 1
+	prevTerm, ok := n.log.term(prevIndex)
+	if !ok {
+		// The entries the follower needs have been compacted
+		n.send(&ab.RaftMessage{Type: ab.RaftMessage_SNAPSHOT, To: to, Snapshot: n.log.snapshot})
+		pr.next = n.log.snapshot.Index + 1
+		return
+	}
+	entries := n.log.slice(pr.next, pr.next+maxEntriesPerMessage-1)
+	n.send(&ab.RaftMessage{
+		Type:    ab.RaftMessage_APPEND,
+		To:      to,
+		Index:   prevIndex,
+		LogTerm: prevTerm,
+		Entries: entries,
+		Commit:  n.log.committed,
+	})
+	if len(entries) > 0 {
+		pr.next = entries[len(entries)-1].Index + 1
+	}
+}
+
+func (n *node) send(m *ab.RaftMessage) {
+	m.Channel = n.channel
+	m.From = n.id
+	m.Term = n.term
+	n.msgs = append(n.msgs, m)
+}
+
+// step processes a message received from another node.
+func (n *node) step(m *ab.RaftMessage) {
+	switch {
+	case m.Term > n.term:
+		switch m.Type {
+		case ab.RaftMessage_APPEND, ab.RaftMessage_SNAPSHOT:
+			n.becomeFollower(m.Term, m.From)
+		default:
+			n.becomeFollower(m.Term, 0)
+		}
+	case m.Term < n.term:
+		switch m.Type {
+		case ab.RaftMessage_APPEND, ab.RaftMessage_SNAPSHOT:
+			// Let the stale leader know about the new term
+			n.send(&ab.RaftMessage{Type: ab.RaftMessage_APPEND_RESPONSE, To: m.From})
+		case ab.RaftMessage_VOTE:
+			n.send(&ab.RaftMessage{Type: ab.RaftMessage_VOTE_RESPONSE, To: m.From, Reject: true})
+		}
+		return
+	}
+
+	switch m.Type {
+	case ab.RaftMessage_VOTE:
+		canVote := n.vote == m.From || (n.vote == 0 && n.lead == 0)
+		upToDate := m.LogTerm > n.log.lastTerm() || (m.LogTerm == n.log.lastTerm() && m.Index >= n.log.lastIndex())
+		if canVote && upToDate {
+			n.vote = m.From
+			n.electionElapsed = 0
+		}
+		n.send(&ab.RaftMessage{Type: ab.RaftMessage_VOTE_RESPONSE, To: m.From, Reject: !(canVote && upToDate)})
+
+	case ab.RaftMessage_VOTE_RESPONSE:
+		if n.state != stateCandidate {
+			return
+		}
+		n.votes[m.From] = !m.Reject
+		granted := 0
+		for _, v := range n.votes {
+			if v {
+				granted++
+			}
+		}
+		switch {
+		case granted >= n.quorum():
+			n.becomeLeader()
+		case len(n.votes)-granted >= n.quorum():
+			n.becomeFollower(n.term, 0)
+		}
+
+	case ab.RaftMessage_APPEND:
+		if n.state == stateLeader {
+			logger.Warningf("[channel: %s] Node %d ignoring APPEND from %d, there can't be two leaders at term %d", n.channel, n.id, m.From, n.term)
+			return
+		}
+		n.becomeFollowerOf(m.From)
+		n.handleAppend(m)
+
+	case ab.RaftMessage_SNAPSHOT:
+		if n.state == stateLeader {
+			return
+		}
+		n.becomeFollowerOf(m.From)
+		n.handleSnapshot(m)
+
+	case ab.RaftMessage_APPEND_RESPONSE:
+		if n.state == stateLeader {
+			n.handleAppendResponse(m)
+		}
+	}
+}
+
+func (n *node) becomeFollowerOf(lead uint64) {
+	if n.state != stateFollower {
+		n.becomeFollower(n.term, lead)
+	}
+	n.lead = lead
+	n.electionElapsed = 0
+}
+
+func (n *node) handleAppend(m *ab.RaftMessage) {
+	if m.Index < n.log.committed {
+		n.send(&ab.RaftMessage{Type: ab.RaftMessage_APPEND_RESPONSE, To: m.From, Index: n.log.committed})
+		return
+	}
+	if t, ok := n.log.term(m.Index); !ok || t != m.LogTerm {
+		logger.Debugf("[channel: %s] Node %d rejecting APPEND from %d at index %d (last index = %d)", n.channel, n.id, m.From, m.Index, n.log.lastIndex())
+		n.send(&ab.RaftMessage{
+			Type:       ab.RaftMessage_APPEND_RESPONSE,
+			To:         m.From,
+			Index:      m.Index,
+			Reject:     true,
+			RejectHint: n.log.lastIndex(),
+		})
+		return
+	}
+	n.log.append(m.Entries...)
+	lastNew := m.Index + uint64(len(m.Entries))
+	if m.Commit < lastNew {
+		n.log.commitTo(m.Commit)
+	} else {
+		n.log.commitTo(lastNew)
+	}
+	n.send(&ab.RaftMessage{Type: ab.RaftMessage_APPEND_RESPONSE, To: m.From, Index: lastNew})
+}
+
+func (n *node) handleSnapshot(m *ab.RaftMessage) {
+	if m.Snapshot == nil {
+		return
+	}
+	if m.Snapshot.Index <= n.log.committed {
+		n.send(&ab.RaftMessage{Type: ab.RaftMessage_APPEND_RESPONSE, To: m.From, Index: n.log.committed})
+		return
+	}
+	logger.Infof("[channel: %s] Node %d received snapshot at index %d from %d", n.channel, n.id, m.Snapshot.Index, m.From)
+	n.pendingSnapshot = m.Snapshot
+}
+
+func (n *node) handleAppendResponse(m *ab.RaftMessage) {
+	pr, ok := n.progress[m.From]
+	if !ok {
+		return
+	}
+	if m.Reject {
+		if m.Index < pr.match {
+			// Stale rejection of an APPEND which has been superseded
+			return
+		}
+		// Probe backwards from the last index of the follower
+		next := m.RejectHint + 1
+		if next > m.Index {
+			next = m.Index
+		}
+		if next <= pr.match {
+			next = pr.match + 1
+		}
+		pr.next = next
+		n.sendAppend(m.From)
+		return
+	}
+	if m.Index > pr.match {
+		pr.match = m.Index
+	}
+	if pr.next <= pr.match {
+		pr.next = pr.match + 1
+	}
+	if n.maybeCommit() {
+		n.broadcastAppend()
+	} else if pr.next <= n.log.lastIndex() {
+		n.sendAppend(m.From)
+	}
+}
+
+// restore installs the snapshot the chain has caught up with.
+func (n *node) restore(snapshot *ab.RaftSnapshot) {
+	n.log.restore(snapshot)
+	if n.lead != 0 {
+		n.send(&ab.RaftMessage{Type: ab.RaftMessage_APPEND_RESPONSE, To: n.lead, Index: snapshot.Index})
+	}
+}
+
+// ready returns the work which is pending since the previous call.
+func (n *node) ready() ready {
+	rd := ready{
+		entries:   n.log.unstable(),
+		snapshot:  n.pendingSnapshot,
+		messages:  n.msgs,
+		committed: n.log.nextCommitted(),
+	}
+	if hs := n.hardState(); hs != n.prevHardState {
+		rd.hardState = &hs
+		n.prevHardState = hs
+	}
+	n.msgs = nil
+	n.pendingSnapshot = nil
+	n.log.stable = n.log.lastIndex()
+	return rd
+}
@@ -0,0 +1,241 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"testing"
+
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/stretchr/testify/assert"
+)
+
+// testNodes is a set of nodes which exchange their messages synchronously.
+type testNodes struct {
+	nodes map[uint64]*node
+	down  map[uint64]bool
+}
+
+func newTestNodes(size int) *testNodes {
+	var peers []uint64
+	for i := 1; i <= size; i++ {
+		peers = append(peers, uint64(i))
+	}
+	tn := &testNodes{nodes: make(map[uint64]*node), down: make(map[uint64]bool)}
+	for _, id := range peers {
+		tn.nodes[id] = newNode(id, peers, "testchain", 10, 1, newRaftLog(nil, nil), nil)
+	}
+	return tn
+}
+
+// deliver drains the ready state of all the nodes and delivers the
+// messages until none is left.
+func (tn *testNodes) deliver() {
+	for {
+		var msgs []*ab.RaftMessage
+		for id, n := range tn.nodes {
+			rd := n.ready()
+			if !tn.down[id] {
+				msgs = append(msgs, rd.messages...)
+			}
+			if rd.snapshot != nil {
+				n.restore(rd.snapshot)
+			}
+			for _, e := range rd.committed {
+				n.log.applied = e.Index
+			}
+		}
+		if len(msgs) == 0 {
+			return
+		}
+		for _, m := range msgs {
+			if !tn.down[m.To] {
+				tn.nodes[m.To].step(m)
+			}
+		}
+	}
+}
+
+func TestSingleNodeElection(t *testing.T) {
+	n := newNode(1, []uint64{1}, "testchain", 10, 1, newRaftLog(nil, nil), nil)
+	assert.False(t, n.propose([]byte("data")), "Followers should not accept proposals")
+
+	n.campaign()
+	assert.Equal(t, stateLeader, n.state)
+	assert.Equal(t, uint64(1), n.term)
+
+	assert.True(t, n.propose([]byte("data")))
+	rd := n.ready()
+	assert.Len(t, rd.entries, 2, "Expected the empty entry of the leader and the proposal")
+	assert.Len(t, rd.committed, 2, "A single node should commit its entries right away")
+	assert.Equal(t, &ab.RaftHardState{Term: 1, Vote: 1, Commit: 2}, rd.hardState)
+}
+
+func TestElectionAndReplication(t *testing.T) {
+	tn := newTestNodes(3)
+	tn.nodes[1].campaign()
+	tn.deliver()
+
+	assert.Equal(t, stateLeader, tn.nodes[1].state)
+	for _, id := range []uint64{2, 3} {
+		assert.Equal(t, stateFollower, tn.nodes[id].state)
+		assert.Equal(t, uint64(1), tn.nodes[id].lead)
+	}
+
+	assert.True(t, tn.nodes[1].propose([]byte("data")))
+	tn.deliver()
+	for id, n := range tn.nodes {
+		assert.Equal(t, uint64(2), n.log.lastIndex(), "Node %d", id)
+		// Followers learn about the new commit index with the next heartbeat
+		if id == 1 {
+			assert.Equal(t, uint64(2), n.log.committed)
+		}
+	}
+
+	tn.nodes[1].tick()
+	tn.deliver()
+	for id, n := range tn.nodes {
+		assert.Equal(t, uint64(2), n.log.committed, "Node %d", id)
+		assert.Equal(t, []byte("data"), n.log.entries[1].Data, "Node %d", id)
+	}
+}
+
+func TestElectionTimeout(t *testing.T) {
+	tn := newTestNodes(3)
+	n := tn.nodes[2]
+	for i := 0; i < 2*n.electionTick && n.state == stateFollower; i++ {
+		n.tick()
+	}
+	assert.Equal(t, stateCandidate, n.state, "Follower should have started an election")
+	tn.deliver()
+	assert.Equal(t, stateLeader, n.state)
+}
+
+func TestNodeLeaderFailover(t *testing.T) {
+	tn := newTestNodes(3)
+	tn.nodes[1].campaign()
+	tn.deliver()
+	tn.nodes[1].propose([]byte("first"))
+	tn.deliver()
+
+	tn.down[1] = true
+	tn.nodes[2].campaign()
+	tn.deliver()
+	assert.Equal(t, stateLeader, tn.nodes[2].state)
+	assert.Equal(t, uint64(2), tn.nodes[2].term)
+
+	tn.nodes[2].propose([]byte("second"))
+	tn.deliver()
+	assert.Equal(t, tn.nodes[2].log.committed, tn.nodes[2].log.lastIndex(), "Two out of three nodes should commit")
+
+	// The old leader steps down and catches up once it is back
+	tn.down[1] = false
+	tn.nodes[2].tick()
+	tn.deliver()
+	tn.nodes[2].tick()
+	tn.deliver()
+	assert.Equal(t, stateFollower, tn.nodes[1].state)
+	assert.Equal(t, tn.nodes[2].log.lastIndex(), tn.nodes[1].log.lastIndex())
+	assert.Equal(t, tn.nodes[2].log.committed, tn.nodes[1].log.committed)
+}
+
+func TestVoteRejectedForStaleLog(t *testing.T) {
+	tn := newTestNodes(3)
+	tn.nodes[1].campaign()
+	tn.deliver()
+
+	// Node 3 misses an entry committed by nodes 1 and 2
+	tn.down[3] = true
+	tn.nodes[1].propose([]byte("data"))
+	tn.deliver()
+	tn.down[3] = false
+
+	tn.down[1] = true
+	tn.nodes[3].campaign()
+	tn.deliver()
+	assert.NotEqual(t, stateLeader, tn.nodes[3].state, "Node with a stale log must not be elected")
+
+	tn.nodes[2].campaign()
+	tn.deliver()
+	assert.Equal(t, stateLeader, tn.nodes[2].state)
+}
+
+func TestConflictingEntriesAreReplaced(t *testing.T) {
+	tn := newTestNodes(3)
+	tn.nodes[1].campaign()
+	tn.deliver()
+
+	// Node 1 appends entries nobody else sees, then loses the leadership
+	tn.down[1] = true
+	tn.nodes[1].propose([]byte("lost1"))
+	tn.nodes[1].propose([]byte("lost2"))
+	tn.deliver()
+
+	tn.nodes[2].campaign()
+	tn.deliver()
+	tn.nodes[2].propose([]byte("kept"))
+	tn.deliver()
+
+	tn.down[1] = false
+	for i := 0; i < 3; i++ {
+		tn.nodes[2].tick()
+		tn.deliver()
+	}
+	assert.Equal(t, tn.nodes[2].log.lastIndex(), tn.nodes[1].log.lastIndex())
+	for i, e := range tn.nodes[2].log.entries {
+		assert.Equal(t, e.Term, tn.nodes[1].log.entries[i].Term)
+		assert.Equal(t, e.Data, tn.nodes[1].log.entries[i].Data)
+	}
+}
+
+func TestSnapshotSentToLaggingFollower(t *testing.T) {
+	tn := newTestNodes(3)
+	tn.nodes[1].campaign()
+	tn.deliver()
+
+	tn.down[3] = true
+	for i := 0; i < 5; i++ {
+		tn.nodes[1].propose([]byte("data"))
+	}
+	tn.deliver()
+
+	leader := tn.nodes[1]
+	term, _ := leader.log.term(4)
+	leader.log.compact(&ab.RaftSnapshot{Term: term, Index: 4, Data: []byte("block")})
+
+	tn.down[3] = false
+	leader.tick()
+	tn.deliver()
+	leader.tick()
+	tn.deliver()
+
+	follower := tn.nodes[3]
+	assert.Equal(t, uint64(4), follower.log.snapshot.Index)
+	assert.Equal(t, leader.log.lastIndex(), follower.log.lastIndex())
+	assert.Equal(t, leader.log.committed, follower.log.committed)
+}
+
+func TestLogAppendConflictWithCommittedPanics(t *testing.T) {
+	l := newRaftLog(nil, nil)
+	l.append(&ab.RaftLogEntry{Term: 1, Index: 1}, &ab.RaftLogEntry{Term: 1, Index: 2})
+	l.commitTo(2)
+	assert.Panics(t, func() { l.append(&ab.RaftLogEntry{Term: 2, Index: 2}) })
+}
+
+func TestLogCompact(t *testing.T) {
+	l := newRaftLog(nil, nil)
+	for i := uint64(1); i <= 5; i++ {
+		l.append(&ab.RaftLogEntry{Term: 1, Index: i})
+	}
+	l.compact(&ab.RaftSnapshot{Term: 1, Index: 3})
+	assert.Equal(t, uint64(4), l.firstIndex())
+	assert.Equal(t, uint64(5), l.lastIndex())
+	_, ok := l.term(2)
+	assert.False(t, ok, "Compacted entries should be gone")
+	term, ok := l.term(3)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), term)
+}
@@ -0,0 +1,269 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+)
+
+const (
+	walFileName      = "wal"
+	snapshotFileName = "snapshot"
+	tmpFileSuffix    = ".tmp"
+
+	recordEntry     byte = 1
+	recordHardState byte = 2
+
+	// type, length and checksum of a record
+	recordHeaderSize = 9
+)
+
+// storage persists the Raft log of a channel. Entries and hard states are
+// appended to a write ahead log. Taking a snapshot writes the snapshot file
+// and then rewrites the WAL with the entries which follow the snapshot.
+//
+// A WAL record is laid out as: type (1 byte) | length of the data (4 bytes)
+// | CRC-32 of the data (4 bytes) | data. An entry record overrides all the
+// entries with the same or a greater index that precede it in the WAL.
+type storage struct {
+	walDir  string
+	snapDir string
+	wal     *os.File
+}
+
+func newStorage(walDir, snapDir string) (*storage, error) {
+	for _, dir := range []string{walDir, snapDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("cannot create directory %s: %s", dir, err)
+		}
+	}
+	return &storage{walDir: walDir, snapDir: snapDir}, nil
+}
+
+// load reads back the snapshot, the hard state and the entries following
+// the snapshot, and opens the WAL for appending. A torn record at the tail
+// of the WAL, left by a crash in the middle of a write, is discarded.
+func (s *storage) load() (*ab.RaftSnapshot, *ab.RaftHardState, []*ab.RaftLogEntry, error) {
+	snapshot, err := s.loadSnapshot()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.walDir, walFileName), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot open WAL: %s", err)
+	}
+
+	var hs *ab.RaftHardState
+	var entries []*ab.RaftLogEntry
+	var offset int64
+	r := bufio.NewReader(f)
+	for {
+		typ, data, err := readRecord(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			logger.Warningf("Discarding the tail of WAL %s after offset %d: %s", f.Name(), offset, err)
+			if err := f.Truncate(offset); err != nil {
+				f.Close()
+				return nil, nil, nil, fmt.Errorf("cannot truncate WAL: %s", err)
+			}
+			break
+		}
+		offset += int64(recordHeaderSize + len(data))
+
+		switch typ {
+		case recordHardState:
+			hs = &ab.RaftHardState{}
+			if err := proto.Unmarshal(data, hs); err != nil {
+				f.Close()
+				return nil, nil, nil, fmt.Errorf("cannot unmarshal hard state: %s", err)
+			}
+		case recordEntry:
+			entry := &ab.RaftLogEntry{}
+			if err := proto.Unmarshal(data, entry); err != nil {
+				f.Close()
+				return nil, nil, nil, fmt.Errorf("cannot unmarshal entry: %s", err)
+			}
+			if snapshot != nil && entry.Index <= snapshot.Index {
+				continue
+			}
+			first := uint64(1)
+			if snapshot != nil {
+				first = snapshot.Index + 1
+			}
+			if entry.Index > first+uint64(len(entries)) {
+				f.Close()
+				return nil, nil, nil, fmt.Errorf("missing entries before entry %d", entry.Index)
+			}
+			entries = append(entries[:entry.Index-first], entry)
+		default:
+			f.Close()
+			return nil, nil, nil, fmt.Errorf("unknown WAL record type %d", typ)
+		}
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, nil, nil, fmt.Errorf("cannot seek WAL: %s", err)
+	}
+	s.wal = f
+	return snapshot, hs, entries, nil
+}
+
+func (s *storage) loadSnapshot() (*ab.RaftSnapshot, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(s.snapDir, snapshotFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read snapshot: %s", err)
+	}
+	typ, data, err := readRecord(bytes.NewReader(raw))
+	if err != nil || typ != 0 {
+		return nil, fmt.Errorf("snapshot is corrupted: %v", err)
+	}
+	snapshot := &ab.RaftSnapshot{}
+	if err := proto.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal snapshot: %s", err)
+	}
+	return snapshot, nil
+}
+
+// save appends the entries and the hard state, if any, to the WAL and syncs
+// it to disk.
+func (s *storage) save(hs *ab.RaftHardState, entries []*ab.RaftLogEntry) error {
+	if hs == nil && len(entries) == 0 {
+		return nil
+	}
+	if err := writeRecords(s.wal, hs, entries); err != nil {
+		return err
+	}
+	return s.wal.Sync()
+}
+
+// saveSnapshot writes the snapshot and replaces the WAL with one holding
+// only the hard state and the entries which follow the snapshot.
+func (s *storage) saveSnapshot(snapshot *ab.RaftSnapshot, hs *ab.RaftHardState, entries []*ab.RaftLogEntry) error {
+	data, err := proto.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("cannot marshal snapshot: %s", err)
+	}
+	err = writeFileAtomically(filepath.Join(s.snapDir, snapshotFileName), func(f *os.File) error {
+		return writeRecord(f, 0, data)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot write snapshot: %s", err)
+	}
+
+	walPath := filepath.Join(s.walDir, walFileName)
+	err = writeFileAtomically(walPath, func(f *os.File) error {
+		return writeRecords(f, hs, entries)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot rewrite WAL: %s", err)
+	}
+
+	f, err := os.OpenFile(walPath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open WAL: %s", err)
+	}
+	s.wal.Close()
+	s.wal = f
+	return nil
+}
+
+func (s *storage) close() error {
+	if s.wal == nil {
+		return nil
+	}
+	return s.wal.Close()
+}
+
+func writeFileAtomically(path string, write func(f *os.File) error) error {
+	tmp := path + tmpFileSuffix
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func writeRecords(w io.Writer, hs *ab.RaftHardState, entries []*ab.RaftLogEntry) error {
+	for _, entry := range entries {
+		data, err := proto.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("cannot marshal entry: %s", err)
+		}
+		if err := writeRecord(w, recordEntry, data); err != nil {
+			return err
+		}
+	}
+	if hs != nil {
+		data, err := proto.Marshal(hs)
+		if err != nil {
+			return fmt.Errorf("cannot marshal hard state: %s", err)
+		}
+		if err := writeRecord(w, recordHardState, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeRecord(w io.Writer, typ byte, data []byte) error {
+	header := make([]byte, recordHeaderSize)
+	header[0] = typ
+	binary.BigEndian.PutUint32(header[1:5], uint32(len(data)))
+	binary.BigEndian.PutUint32(header[5:9], crc32.ChecksumIEEE(data))
+	if _, err := w.Write(append(header, data...)); err != nil {
+		return fmt.Errorf("cannot write record: %s", err)
+	}
+	return nil
+}
+
+func readRecord(r io.Reader) (byte, []byte, error) {
+	header := make([]byte, recordHeaderSize)
+	n, err := io.ReadFull(r, header)
+	if n == 0 && err == io.EOF {
+		return 0, nil, io.EOF
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("truncated record header")
+	}
+	data := make([]byte, binary.BigEndian.Uint32(header[1:5]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, fmt.Errorf("truncated record")
+	}
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[5:9]) {
+		return 0, nil, fmt.Errorf("checksum mismatch")
+	}
+	return header[0], data, nil
+}
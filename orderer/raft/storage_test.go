@@ -0,0 +1,181 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStorage(t *testing.T) (*storage, string) {
+	dir, err := ioutil.TempDir("", "raft-storage")
+	if err != nil {
+		t.Fatalf("Cannot create temp dir: %s", err)
+	}
+	s, err := newStorage(filepath.Join(dir, "wal"), filepath.Join(dir, "snap"))
+	if err != nil {
+		t.Fatalf("Cannot create storage: %s", err)
+	}
+	return s, dir
+}
+
+func reopen(t *testing.T, s *storage) *storage {
+	s.close()
+	s, err := newStorage(s.walDir, s.snapDir)
+	if err != nil {
+		t.Fatalf("Cannot create storage: %s", err)
+	}
+	return s
+}
+
+func TestStorageEmpty(t *testing.T) {
+	s, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+
+	snapshot, hs, entries, err := s.load()
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+	assert.Nil(t, hs)
+	assert.Empty(t, entries)
+	s.close()
+}
+
+func TestStorageSaveLoad(t *testing.T) {
+	s, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+
+	_, _, _, err := s.load()
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.save(&ab.RaftHardState{Term: 1, Vote: 1}, []*ab.RaftLogEntry{
+		{Term: 1, Index: 1},
+		{Term: 1, Index: 2, Data: []byte("two")},
+		{Term: 1, Index: 3, Data: []byte("three")},
+	}))
+	// Entry 3 is overridden by a later term
+	assert.NoError(t, s.save(&ab.RaftHardState{Term: 2, Vote: 2, Commit: 2}, []*ab.RaftLogEntry{
+		{Term: 2, Index: 3, Data: []byte("new three")},
+	}))
+
+	s = reopen(t, s)
+	snapshot, hs, entries, err := s.load()
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+	assert.Equal(t, &ab.RaftHardState{Term: 2, Vote: 2, Commit: 2}, hs)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, []byte("new three"), entries[2].Data)
+	assert.Equal(t, uint64(2), entries[2].Term)
+
+	// The WAL is appended to after being loaded
+	assert.NoError(t, s.save(nil, []*ab.RaftLogEntry{{Term: 2, Index: 4}}))
+	s = reopen(t, s)
+	_, _, entries, err = s.load()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+	s.close()
+}
+
+func TestStorageTornTail(t *testing.T) {
+	s, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+
+	_, _, _, err := s.load()
+	assert.NoError(t, err)
+	assert.NoError(t, s.save(&ab.RaftHardState{Term: 1}, []*ab.RaftLogEntry{{Term: 1, Index: 1}}))
+	s.close()
+
+	// Simulate a crash in the middle of writing a record
+	walPath := filepath.Join(s.walDir, walFileName)
+	f, err := os.OpenFile(walPath, os.O_WRONLY|os.O_APPEND, 0644)
+	assert.NoError(t, err)
+	_, err = f.Write([]byte{recordEntry, 0, 0, 0, 42, 1, 2})
+	assert.NoError(t, err)
+	f.Close()
+
+	s, err = newStorage(s.walDir, s.snapDir)
+	assert.NoError(t, err)
+	_, hs, entries, err := s.load()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), hs.Term)
+	assert.Len(t, entries, 1)
+
+	// New records follow the last intact one
+	assert.NoError(t, s.save(nil, []*ab.RaftLogEntry{{Term: 1, Index: 2}}))
+	s = reopen(t, s)
+	_, _, entries, err = s.load()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	s.close()
+}
+
+func TestStorageCorruptedRecord(t *testing.T) {
+	s, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+
+	_, _, _, err := s.load()
+	assert.NoError(t, err)
+	assert.NoError(t, s.save(nil, []*ab.RaftLogEntry{{Term: 1, Index: 1}, {Term: 1, Index: 2, Data: []byte("data")}}))
+	s.close()
+
+	walPath := filepath.Join(s.walDir, walFileName)
+	raw, err := ioutil.ReadFile(walPath)
+	assert.NoError(t, err)
+	raw[len(raw)-1] ^= 0xff
+	assert.NoError(t, ioutil.WriteFile(walPath, raw, 0644))
+
+	s, err = newStorage(s.walDir, s.snapDir)
+	assert.NoError(t, err)
+	_, _, entries, err := s.load()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "The record with a bad checksum should have been discarded")
+	s.close()
+}
+
+func TestStorageSnapshot(t *testing.T) {
+	s, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+
+	_, _, _, err := s.load()
+	assert.NoError(t, err)
+	var entries []*ab.RaftLogEntry
+	for i := uint64(1); i <= 5; i++ {
+		entries = append(entries, &ab.RaftLogEntry{Term: 1, Index: i})
+	}
+	assert.NoError(t, s.save(&ab.RaftHardState{Term: 1, Commit: 5}, entries))
+
+	snapshot := &ab.RaftSnapshot{Term: 1, Index: 3, Data: []byte("block")}
+	assert.NoError(t, s.saveSnapshot(snapshot, &ab.RaftHardState{Term: 1, Commit: 5}, entries[3:]))
+	assert.NoError(t, s.save(nil, []*ab.RaftLogEntry{{Term: 1, Index: 6}}))
+
+	s = reopen(t, s)
+	loadedSnapshot, hs, loadedEntries, err := s.load()
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, loadedSnapshot)
+	assert.Equal(t, uint64(5), hs.Commit)
+	if assert.Len(t, loadedEntries, 3) {
+		assert.Equal(t, uint64(4), loadedEntries[0].Index)
+		assert.Equal(t, uint64(6), loadedEntries[2].Index)
+	}
+	s.close()
+
+	_, err = os.Stat(filepath.Join(s.walDir, walFileName+tmpFileSuffix))
+	assert.True(t, os.IsNotExist(err), "Temporary WAL should have been renamed")
+}
+
+func TestStorageCorruptedSnapshot(t *testing.T) {
+	s, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(s.snapDir, snapshotFileName), []byte("garbage"), 0644))
+	_, _, _, err := s.load()
+	assert.Error(t, err)
+}
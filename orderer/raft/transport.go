@@ -0,0 +1,202 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// sendBufferSize is the number of messages queued up for a single node
+	// before new messages to it are dropped. Raft tolerates message loss.
+	sendBufferSize = 256
+
+	rpcTimeout = 5 * time.Second
+)
+
+// transport carries the messages of the Raft nodes of all the channels.
+type transport interface {
+	// send queues up the message for its destination, it never blocks.
+	send(m *ab.RaftMessage)
+	// submit forwards an envelope to the given node.
+	submit(dest uint64, req *ab.SubmitRequest) (*ab.SubmitResponse, error)
+	// pull fetches a block from the given node.
+	pull(dest uint64, req *ab.PullRequest) (*ab.PullResponse, error)
+	// stop closes all the connections.
+	stop()
+}
+
+// grpcTransport implements transport on top of the Cluster service of the
+// other orderers.
+type grpcTransport struct {
+	addresses map[uint64]string
+	creds     credentials.TransportCredentials
+
+	lock    sync.Mutex
+	clients map[uint64]*remoteNode
+	stopped bool
+}
+
+type remoteNode struct {
+	id     uint64
+	conn   *grpc.ClientConn
+	client ab.ClusterClient
+	sendC  chan *ab.RaftMessage
+	doneC  chan struct{}
+}
+
+func newGRPCTransport(nodes []localconfig.RaftNode, tlsConf localconfig.TLS) (*grpcTransport, error) {
+	t := &grpcTransport{
+		addresses: make(map[uint64]string),
+		clients:   make(map[uint64]*remoteNode),
+	}
+	for _, n := range nodes {
+		t.addresses[n.ID] = n.Address
+	}
+	if tlsConf.Enabled {
+		creds, err := clientCredentials(tlsConf)
+		if err != nil {
+			return nil, err
+		}
+		t.creds = creds
+	}
+	return t, nil
+}
+
+func clientCredentials(tlsConf localconfig.TLS) (credentials.TransportCredentials, error) {
+	cert, err := ioutil.ReadFile(tlsConf.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read TLS certificate %s: %s", tlsConf.Certificate, err)
+	}
+	key, err := ioutil.ReadFile(tlsConf.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read TLS private key %s: %s", tlsConf.PrivateKey, err)
+	}
+	keyPair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode TLS key pair: %s", err)
+	}
+	rootCAs := x509.NewCertPool()
+	for _, file := range tlsConf.RootCAs {
+		root, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read TLS root certificate %s: %s", file, err)
+		}
+		if !rootCAs.AppendCertsFromPEM(root) {
+			return nil, fmt.Errorf("cannot parse TLS root certificate %s", file)
+		}
+	}
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		RootCAs:      rootCAs,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+func (t *grpcTransport) remote(id uint64) (*remoteNode, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.stopped {
+		return nil, fmt.Errorf("transport has been stopped")
+	}
+	if r, ok := t.clients[id]; ok {
+		return r, nil
+	}
+	address, ok := t.addresses[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown node %d", id)
+	}
+	conn, err := comm.NewClientConnectionWithAddress(address, false, t.creds != nil, t.creds)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to node %d at %s: %s", id, address, err)
+	}
+	r := &remoteNode{
+		id:     id,
+		conn:   conn,
+		client: ab.NewClusterClient(conn),
+		sendC:  make(chan *ab.RaftMessage, sendBufferSize),
+		doneC:  make(chan struct{}),
+	}
+	go r.run()
+	t.clients[id] = r
+	return r, nil
+}
+
+func (t *grpcTransport) send(m *ab.RaftMessage) {
+	r, err := t.remote(m.To)
+	if err != nil {
+		logger.Warningf("[channel: %s] Dropping message to node %d: %s", m.Channel, m.To, err)
+		return
+	}
+	select {
+	case r.sendC <- m:
+	default:
+		logger.Debugf("[channel: %s] Dropping message to node %d, its queue is full", m.Channel, m.To)
+	}
+}
+
+func (t *grpcTransport) submit(dest uint64, req *ab.SubmitRequest) (*ab.SubmitResponse, error) {
+	r, err := t.remote(dest)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	return r.client.Submit(ctx, req)
+}
+
+func (t *grpcTransport) pull(dest uint64, req *ab.PullRequest) (*ab.PullResponse, error) {
+	r, err := t.remote(dest)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	return r.client.Pull(ctx, req)
+}
+
+func (t *grpcTransport) stop() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.stopped = true
+	for id, r := range t.clients {
+		close(r.doneC)
+		r.conn.Close()
+		delete(t.clients, id)
+	}
+}
+
+// run delivers the queued up messages to the remote node one at a time, so
+// that they arrive in the order they were sent.
+func (r *remoteNode) run() {
+	for {
+		select {
+		case m := <-r.sendC:
+			ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+			if _, err := r.client.Step(ctx, m); err != nil {
+				logger.Debugf("[channel: %s] Failed sending %s to node %d: %s", m.Channel, m.Type, r.id, err)
+			}
+			cancel()
+		case <-r.doneC:
+			return
+		}
+	}
+}
@@ -59,23 +59,25 @@ func Cmd(cf *ChaincodeCmdFactory) *cobra.Command {
 
 // Chaincode-related variables.
 var (
-	chaincodeLang     string
-	chaincodeCtorJSON string
-	chaincodePath     string
-	chaincodeName     string
-	chaincodeUsr      string // Not used
-	chaincodeQueryRaw bool
-	chaincodeQueryHex bool
-	customIDGenAlg    string
-	chainID           string
-	chaincodeVersion  string
-	policy            string
-	escc              string
-	vscc              string
-	policyMarhsalled  []byte
-	orderingEndpoint  string
-	tls               bool
-	caFile            string
+	chaincodeLang         string
+	chaincodeCtorJSON     string
+	chaincodePath         string
+	chaincodeName         string
+	chaincodeUsr          string // Not used
+	chaincodeQueryRaw     bool
+	chaincodeQueryHex     bool
+	customIDGenAlg        string
+	chainID               string
+	chaincodeVersion      string
+	policy                string
+	escc                  string
+	vscc                  string
+	policyMarhsalled      []byte
+	orderingEndpoint      string
+	collectionsConfigFile string
+	collectionConfigBytes []byte
+	tls                   bool
+	caFile                string
 )
 
 var chaincodeCmd = &cobra.Command{
@@ -116,6 +118,8 @@ func resetFlags() {
 		fmt.Sprint("The name of the endorsement system chaincode to be used for this chaincode"))
 	flags.StringVarP(&vscc, "vscc", "V", common.UndefinedParamValue,
 		fmt.Sprint("The name of the verification system chaincode to be used for this chaincode"))
+	flags.StringVar(&collectionsConfigFile, "collections-config", common.UndefinedParamValue,
+		fmt.Sprint("The file containing the configuration for the chaincode's collection"))
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
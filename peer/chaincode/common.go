@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 
@@ -160,6 +161,65 @@ func chaincodeInvokeOrQuery(cmd *cobra.Command, args []string, invoke bool, cf *
 	return nil
 }
 
+// collectionConfigJson is the JSON representation of the
+// configuration of a collection, as supplied to the CLI
+type collectionConfigJson struct {
+	Name          string `json:"name"`
+	Policy        string `json:"policy"`
+	RequiredCount int32  `json:"requiredPeerCount"`
+	MaxPeerCount  int32  `json:"maxPeerCount"`
+}
+
+// getCollectionConfigFromFile retrieves the collection configuration
+// from the supplied file; the supplied file must contain a
+// json-formatted array of collectionConfigJson elements
+func getCollectionConfigFromFile(ccFile string) ([]byte, error) {
+	fileBytes, err := ioutil.ReadFile(ccFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read file '%s': %s", ccFile, err)
+	}
+
+	return getCollectionConfigFromBytes(fileBytes)
+}
+
+// getCollectionConfigFromBytes converts the JSON collection
+// configuration into a marshalled CollectionConfigPackage
+func getCollectionConfigFromBytes(cconfBytes []byte) ([]byte, error) {
+	cconf := []collectionConfigJson{}
+	if err := json.Unmarshal(cconfBytes, &cconf); err != nil {
+		return nil, fmt.Errorf("could not parse the collection configuration: %s", err)
+	}
+
+	ccarray := make([]*pcommon.CollectionConfig, 0, len(cconf))
+	for _, cconfitem := range cconf {
+		p, err := cauthdsl.FromString(cconfitem.Policy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy %s of collection %s: %s", cconfitem.Policy, cconfitem.Name, err)
+		}
+
+		cpc := &pcommon.CollectionPolicyConfig{
+			Payload: &pcommon.CollectionPolicyConfig_SignaturePolicy{
+				SignaturePolicy: p,
+			},
+		}
+
+		cc := &pcommon.CollectionConfig{
+			Payload: &pcommon.CollectionConfig_StaticCollectionConfig{
+				StaticCollectionConfig: &pcommon.StaticCollectionConfig{
+					Name:              cconfitem.Name,
+					MemberOrgsPolicy:  cpc,
+					RequiredPeerCount: cconfitem.RequiredCount,
+					MaximumPeerCount:  cconfitem.MaxPeerCount,
+				},
+			},
+		}
+
+		ccarray = append(ccarray, cc)
+	}
+
+	return putils.Marshal(&pcommon.CollectionConfigPackage{Config: ccarray})
+}
+
 func checkChaincodeCmdParams(cmd *cobra.Command) error {
 	//we need chaincode name for everything, including deploy
 	if chaincodeName == common.UndefinedParamValue {
@@ -192,9 +252,22 @@ func checkChaincodeCmdParams(cmd *cobra.Command) error {
 		if err != nil {
 			return fmt.Errorf("Invalid policy %s", policy)
 		}
+		str, err := cauthdsl.ToString(p)
+		if err != nil {
+			return fmt.Errorf("Invalid policy %s: %s", policy, err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Endorsement policy: %s\n", str)
 		policyMarhsalled = putils.MarshalOrPanic(p)
 	}
 
+	if collectionsConfigFile != common.UndefinedParamValue {
+		var err error
+		collectionConfigBytes, err = getCollectionConfigFromFile(collectionsConfigFile)
+		if err != nil {
+			return fmt.Errorf("Invalid collection configuration in file %s: %s", collectionsConfigFile, err)
+		}
+	}
+
 	// Check that non-empty chaincode parameters contain only Args as a key.
 	// Type checking is done later when the JSON is actually unmarshaled
 	// into a pb.ChaincodeInput. To better understand what's going
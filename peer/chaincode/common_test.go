@@ -17,13 +17,16 @@
 package chaincode
 
 import (
+	"bytes"
 	"encoding/json"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	genesisconfig "github.com/hyperledger/fabric/common/configtx/tool/localconfig"
 	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
 	"github.com/hyperledger/fabric/peer/common"
+	pcommon "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/cobra"
@@ -81,6 +84,24 @@ func TestCheckChaincodeCmdParamsEmptyCtor(t *testing.T) {
 	require.Error(result)
 }
 
+func TestCheckChaincodeCmdParamsPolicy(t *testing.T) {
+	defer func() { policy = common.UndefinedParamValue }()
+	chaincodeCtorJSON = `{ "Args":["func", "param"] }`
+	chaincodePath = "some/path"
+	chaincodeName = "somename"
+	cmd := &cobra.Command{}
+	out := &bytes.Buffer{}
+	cmd.SetOutput(out)
+
+	// the policy is printed back in the policy language
+	policy = "OutOf(2, 'Org1.member', AND('Org2.member', 'Org3.member'), OR('Org1.member', 'Org3.member'))"
+	require.NoError(t, checkChaincodeCmdParams(cmd))
+	assert.Equal(t, "Endorsement policy: OutOf(2, 'Org1.member', AND('Org2.member', 'Org3.member'), OR('Org1.member', 'Org3.member'))\n", out.String())
+
+	policy = "OutOf(3, 'Org1.member', 'Org2.member')"
+	require.Error(t, checkChaincodeCmdParams(cmd))
+}
+
 func TestCheckValidJSON(t *testing.T) {
 	validJSON := `{"Args":["a","b","c"]}`
 	input := &pb.ChaincodeInput{}
@@ -173,3 +194,49 @@ func TestGetOrdererEndpointFail(t *testing.T) {
 	_, err = common.GetOrdererEndpointOfChain(mockchain, signer, mockEndorserClient)
 	assert.Error(t, err, "GetOrdererEndpointOfChain from invalid response")
 }
+
+func TestCollectionParsing(t *testing.T) {
+	cc, err := getCollectionConfigFromBytes([]byte(sampleCollectionConfigGood))
+	assert.NoError(t, err)
+	assert.NotNil(t, cc)
+	ccp := &pcommon.CollectionConfigPackage{}
+	err = proto.Unmarshal(cc, ccp)
+	assert.NoError(t, err)
+	assert.Len(t, ccp.Config, 1)
+	conf := ccp.Config[0].GetStaticCollectionConfig()
+	assert.NotNil(t, conf)
+	assert.Equal(t, "foo", conf.Name)
+	assert.Equal(t, int32(3), conf.RequiredPeerCount)
+	assert.Equal(t, int32(483279847), conf.MaximumPeerCount)
+	assert.NotNil(t, conf.MemberOrgsPolicy.GetSignaturePolicy())
+
+	cc, err = getCollectionConfigFromBytes([]byte(sampleCollectionConfigBad))
+	assert.Error(t, err)
+	assert.Nil(t, cc)
+
+	cc, err = getCollectionConfigFromBytes([]byte("barf"))
+	assert.Error(t, err)
+	assert.Nil(t, cc)
+
+	cc, err = getCollectionConfigFromFile("/nonexistent/collections.json")
+	assert.Error(t, err)
+	assert.Nil(t, cc)
+}
+
+const sampleCollectionConfigGood = `[
+	{
+		"name": "foo",
+		"policy": "OR('A.member', 'B.member')",
+		"requiredPeerCount": 3,
+		"maxPeerCount": 483279847
+	}
+]`
+
+const sampleCollectionConfigBad = `[
+	{
+		"name": "foo",
+		"policy": "barf",
+		"requiredPeerCount": 3,
+		"maxPeerCount": 483279847
+	}
+]`
@@ -34,8 +34,8 @@ import (
 
 const (
 	channelFuncName = "channel"
-	shortDes        = "Operate a channel: create|fetch|join|list|update."
-	longDes         = "Operate a channel: create|fetch|join|list|update."
+	shortDes        = "Operate a channel: create|fetch|join|joinbysnapshot|list|update."
+	longDes         = "Operate a channel: create|fetch|join|joinbysnapshot|list|update."
 )
 
 var logger = flogging.MustGetLogger("channelCmd")
@@ -53,6 +53,7 @@ const (
 var (
 	// join related variables.
 	genesisBlockPath string
+	snapshotPath     string
 
 	// create related variables
 	chainID          string
@@ -70,6 +71,7 @@ func Cmd(cf *ChannelCmdFactory) *cobra.Command {
 	channelCmd.AddCommand(createCmd(cf))
 	channelCmd.AddCommand(fetchCmd(cf))
 	channelCmd.AddCommand(joinCmd(cf))
+	channelCmd.AddCommand(joinBySnapshotCmd(cf))
 	channelCmd.AddCommand(listCmd(cf))
 	channelCmd.AddCommand(updateCmd(cf))
 
@@ -96,6 +98,7 @@ func resetFlags() {
 	flags = &pflag.FlagSet{}
 
 	flags.StringVarP(&genesisBlockPath, "blockpath", "b", common.UndefinedParamValue, "Path to file containing genesis block")
+	flags.StringVarP(&snapshotPath, "snapshotpath", "", common.UndefinedParamValue, "Path on the peer to the directory containing the ledger snapshot")
 	flags.StringVarP(&chainID, "channelID", "c", common.UndefinedParamValue, "In case of a newChain command, the channel ID to create.")
 	flags.StringVarP(&channelTxFile, "file", "f", "", "Configuration transaction file generated by a tool such as configtxgen for submitting to orderer")
 	flags.IntVarP(&timeout, "timeout", "t", 5, "Channel creation timeout")
@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/scc/cscc"
+	"github.com/hyperledger/fabric/peer/common"
+	pcommon "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+const joinBySnapshotDescription = "Joins the peer to a chain from a ledger snapshot."
+
+func joinBySnapshotCmd(cf *ChannelCmdFactory) *cobra.Command {
+	// Set the flags on the channel joinbysnapshot command.
+	joinBySnapshotCmd := &cobra.Command{
+		Use:   "joinbysnapshot",
+		Short: joinBySnapshotDescription,
+		Long: joinBySnapshotDescription + ` The snapshot is exported with "peer node snapshot" and must be ` +
+			`available on the file system of the peer. The ledger of the peer starts at the last block of the snapshot.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return joinBySnapshot(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"snapshotpath",
+	}
+	attachFlags(joinBySnapshotCmd, flagList)
+
+	return joinBySnapshotCmd
+}
+
+func getJoinBySnapshotCCSpec() *pb.ChaincodeSpec {
+	input := &pb.ChaincodeInput{Args: [][]byte{[]byte(cscc.JoinChainBySnapshot), []byte(snapshotPath)}}
+
+	return &pb.ChaincodeSpec{
+		Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value["GOLANG"]),
+		ChaincodeId: &pb.ChaincodeID{Name: "cscc"},
+		Input:       input,
+	}
+}
+
+func executeJoinBySnapshot(cf *ChannelCmdFactory) error {
+	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: getJoinBySnapshotCCSpec()}
+
+	creator, err := cf.Signer.Serialize()
+	if err != nil {
+		return fmt.Errorf("Error serializing identity for %s: %s", cf.Signer.GetIdentifier(), err)
+	}
+
+	prop, _, err := putils.CreateProposalFromCIS(pcommon.HeaderType_CONFIG, "", invocation, creator)
+	if err != nil {
+		return fmt.Errorf("Error creating proposal for join by snapshot %s", err)
+	}
+
+	signedProp, err := putils.GetSignedProposal(prop, cf.Signer)
+	if err != nil {
+		return fmt.Errorf("Error creating signed proposal %s", err)
+	}
+
+	proposalResp, err := cf.EndorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return ProposalFailedErr(err.Error())
+	}
+
+	if proposalResp == nil {
+		return ProposalFailedErr("nil proposal response")
+	}
+
+	if proposalResp.Response.Status != 0 && proposalResp.Response.Status != 200 {
+		return ProposalFailedErr(fmt.Sprintf("bad proposal response %d: %s", proposalResp.Response.Status,
+			proposalResp.Response.Message))
+	}
+	logger.Infof("Peer joined the channel from the snapshot!")
+	return nil
+}
+
+func joinBySnapshot(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
+	if snapshotPath == common.UndefinedParamValue {
+		return errors.New("Must supply snapshot path")
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserRequired, OrdererNotRequired)
+		if err != nil {
+			return err
+		}
+	}
+	return executeJoinBySnapshot(cf)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/scc/cscc"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestMissingSnapshotPath(t *testing.T) {
+	resetFlags()
+
+	cmd := joinBySnapshotCmd(nil)
+	AddFlags(cmd)
+	args := []string{}
+	cmd.SetArgs(args)
+
+	assert.Error(t, cmd.Execute(), "expected joinbysnapshot command to fail due to missing snapshotpath")
+}
+
+// mockSnapshotEndorserClient records the chaincode input of the proposal it receives
+type mockSnapshotEndorserClient struct {
+	response *pb.ProposalResponse
+	input    *pb.ChaincodeInput
+}
+
+func (m *mockSnapshotEndorserClient) ProcessProposal(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	prop, err := putils.GetProposal(in.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+	cis, err := putils.GetChaincodeInvocationSpec(prop)
+	if err != nil {
+		return nil, err
+	}
+	m.input = cis.ChaincodeSpec.Input
+	return m.response, nil
+}
+
+func TestJoinBySnapshot(t *testing.T) {
+	InitMSP()
+	resetFlags()
+
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err, "Get default signer error: %v", err)
+
+	mockEndorserClient := &mockSnapshotEndorserClient{
+		response: &pb.ProposalResponse{
+			Response:    &pb.Response{Status: 200},
+			Endorsement: &pb.Endorsement{},
+		},
+	}
+
+	mockCF := &ChannelCmdFactory{
+		EndorserClient:   mockEndorserClient,
+		BroadcastFactory: mockBroadcastClientFactory,
+		Signer:           signer,
+	}
+
+	cmd := joinBySnapshotCmd(mockCF)
+	AddFlags(cmd)
+
+	args := []string{"--snapshotpath", "/var/hyperledger/snapshots/mychannel"}
+	cmd.SetArgs(args)
+
+	assert.NoError(t, cmd.Execute(), "expected joinbysnapshot command to succeed")
+	assert.True(t, proto.Equal(&pb.ChaincodeInput{
+		Args: [][]byte{[]byte(cscc.JoinChainBySnapshot), []byte("/var/hyperledger/snapshots/mychannel")},
+	}, mockEndorserClient.input))
+}
+
+func TestJoinBySnapshotBadProposalResponse(t *testing.T) {
+	InitMSP()
+	resetFlags()
+
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err, "Get default signer error: %v", err)
+
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 500, Message: "Cannot create ledger from snapshot"},
+		Endorsement: &pb.Endorsement{},
+	}
+
+	mockCF := &ChannelCmdFactory{
+		EndorserClient:   common.GetMockEndorserClient(mockResponse, nil),
+		BroadcastFactory: mockBroadcastClientFactory,
+		Signer:           signer,
+	}
+
+	cmd := joinBySnapshotCmd(mockCF)
+	AddFlags(cmd)
+
+	args := []string{"--snapshotpath", "/var/hyperledger/snapshots/mychannel"}
+	cmd.SetArgs(args)
+
+	err = cmd.Execute()
+	assert.Error(t, err, "expected joinbysnapshot command to fail")
+	assert.IsType(t, ProposalFailedErr(err.Error()), err, "expected error type of ProposalFailedErr")
+	assert.Contains(t, err.Error(), "Cannot create ledger from snapshot")
+}
@@ -85,7 +85,7 @@ func InitConfig(cmdRoot string) error {
 }
 
 //InitCrypto initializes crypto for this peer
-func InitCrypto(mspMgrConfigDir string, localMSPID string) error {
+func InitCrypto(mspMgrConfigDir, localMSPID, localMSPType string) error {
 	var err error
 	// Check whenever msp folder exists
 	_, err = os.Stat(mspMgrConfigDir)
@@ -101,7 +101,7 @@ func InitCrypto(mspMgrConfigDir string, localMSPID string) error {
 		return fmt.Errorf("could not parse YAML config [%s]", err)
 	}
 
-	err = mspmgmt.LoadLocalMsp(mspMgrConfigDir, bccspConfig, localMSPID)
+	err = mspmgmt.LoadLocalMspWithType(mspMgrConfigDir, bccspConfig, localMSPID, localMSPType)
 	if err != nil {
 		return fmt.Errorf("error when setting up MSP from directory %s: err %s", mspMgrConfigDir, err)
 	}
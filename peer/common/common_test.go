@@ -55,7 +55,7 @@ func TestInitConfig(t *testing.T) {
 
 func TestINitCryptoMissingDir(t *testing.T) {
 	dir := os.TempDir() + "/" + util.GenerateUUID()
-	err := common.InitCrypto(dir, "DEFAULT")
+	err := common.InitCrypto(dir, "DEFAULT", "bccsp")
 	assert.Error(t, err, "Should be able to initialize crypto with non-existing directory")
 	assert.Contains(t, err.Error(), fmt.Sprintf("missing %s folder", dir))
 }
@@ -64,12 +64,12 @@ func TestInitCrypto(t *testing.T) {
 
 	mspConfigPath, err := config.GetDevMspDir()
 	localMspId := "DEFAULT"
-	err = common.InitCrypto(mspConfigPath, localMspId)
+	err = common.InitCrypto(mspConfigPath, localMspId, "bccsp")
 	assert.NoError(t, err, "Unexpected error [%s] calling InitCrypto()", err)
-	err = common.InitCrypto("/etc/foobaz", localMspId)
+	err = common.InitCrypto("/etc/foobaz", localMspId, "bccsp")
 	assert.Error(t, err, "Expected error [%s] calling InitCrypto()", err)
 	localMspId = ""
-	err = common.InitCrypto(mspConfigPath, localMspId)
+	err = common.InitCrypto(mspConfigPath, localMspId, "bccsp")
 	assert.Error(t, err, "Expected error [%s] calling InitCrypto()", err)
 }
 
@@ -25,8 +25,8 @@ import (
 
 const (
 	nodeFuncName = "node"
-	shortDes     = "Operate a peer node: start|status."
-	longDes      = "Operate a peer node: start|status."
+	shortDes     = "Operate a peer node: start|status|prune|snapshot|rollback|rebuild-dbs."
+	longDes      = "Operate a peer node: start|status|prune|snapshot|rollback|rebuild-dbs."
 )
 
 var logger = flogging.MustGetLogger("nodeCmd")
@@ -35,6 +35,10 @@ var logger = flogging.MustGetLogger("nodeCmd")
 func Cmd() *cobra.Command {
 	nodeCmd.AddCommand(startCmd())
 	nodeCmd.AddCommand(statusCmd())
+	nodeCmd.AddCommand(pruneCmd())
+	nodeCmd.AddCommand(snapshotCmd())
+	nodeCmd.AddCommand(rollbackCmd())
+	nodeCmd.AddCommand(rebuildDBsCmd())
 
 	return nodeCmd
 }
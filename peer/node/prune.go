@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/cobra"
+)
+
+var pruneChainID string
+var pruneRetainBlocks uint64
+var pruneRetainSince string
+
+func pruneCmd() *cobra.Command {
+	// Set the flags on the node prune command.
+	flags := nodePruneCmd.Flags()
+	flags.StringVarP(&pruneChainID, "channelID", "c", common.UndefinedParamValue,
+		"Channel whose ledger is to be pruned")
+	flags.Uint64VarP(&pruneRetainBlocks, "retain-blocks", "", 0,
+		"Number of most recent blocks to retain")
+	flags.StringVarP(&pruneRetainSince, "retain-since", "", "",
+		"Retain the blocks created at or after this time, in RFC3339 format (e.g. 2017-06-01T00:00:00Z)")
+
+	return nodePruneCmd
+}
+
+var nodePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Prunes the blocks of a channel's ledger.",
+	Long: `Removes the block files of a channel's ledger that only hold blocks older than the retention limit. ` +
+		`Config blocks are always retained. Must be run while the peer is stopped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy, err := getPrunePolicy()
+		if err != nil {
+			return err
+		}
+		return prune(pruneChainID, policy)
+	},
+}
+
+func getPrunePolicy() (commonledger.PrunePolicy, error) {
+	if pruneRetainBlocks != 0 && pruneRetainSince != "" {
+		return nil, errors.New("Only one of --retain-blocks and --retain-since must be specified")
+	}
+	if pruneRetainBlocks != 0 {
+		return &commonledger.PruneByBlockCount{RetainBlocks: pruneRetainBlocks}, nil
+	}
+	if pruneRetainSince != "" {
+		retainSince, err := time.Parse(time.RFC3339, pruneRetainSince)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid --retain-since value: %s", err)
+		}
+		return &commonledger.PruneByTimestamp{RetainSince: retainSince}, nil
+	}
+	return nil, errors.New("One of --retain-blocks and --retain-since must be specified")
+}
+
+func prune(chainID string, policy commonledger.PrunePolicy) error {
+	if chainID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+
+	ledgermgmt.Initialize()
+	defer ledgermgmt.Close()
+	l, err := ledgermgmt.OpenLedger(chainID)
+	if err != nil {
+		return fmt.Errorf("Error opening ledger for channel %s: %s", chainID, err)
+	}
+	defer l.Close()
+
+	if err = l.Prune(policy); err != nil {
+		return fmt.Errorf("Error pruning ledger for channel %s: %s", chainID, err)
+	}
+	logger.Infof("Pruned ledger for channel %s", chainID)
+	return nil
+}
@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/spf13/cobra"
+)
+
+func rebuildDBsCmd() *cobra.Command {
+	return nodeRebuildDBsCmd
+}
+
+var nodeRebuildDBsCmd = &cobra.Command{
+	Use:   "rebuild-dbs",
+	Short: "Drops the databases of all the channels' ledgers.",
+	Long: `Drops the state database, the history database and the block index of all the channels' ledgers, ` +
+		`which are rebuilt from the block files on the next start of the peer. Must be run while the peer is stopped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rebuildDBs()
+	},
+}
+
+func rebuildDBs() error {
+	ledgermgmt.Initialize()
+	defer ledgermgmt.Close()
+	if err := ledgermgmt.RebuildDBs(); err != nil {
+		return fmt.Errorf("Error dropping ledger databases: %s", err)
+	}
+	logger.Info("Dropped ledger databases, they are rebuilt on the next start of the peer")
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/cobra"
+)
+
+var rollbackChainID string
+var rollbackBlockNumber uint64
+
+func rollbackCmd() *cobra.Command {
+	// Set the flags on the node rollback command.
+	flags := nodeRollbackCmd.Flags()
+	flags.StringVarP(&rollbackChainID, "channelID", "c", common.UndefinedParamValue,
+		"Channel whose ledger is to be rolled back")
+	flags.Uint64VarP(&rollbackBlockNumber, "blockNumber", "b", 0,
+		"Number of the block to roll the ledger back to, which becomes its last block")
+
+	return nodeRollbackCmd
+}
+
+var nodeRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Rolls back a channel's ledger to a block.",
+	Long: `Removes the blocks that follow the given block from a channel's ledger and drops its state and history ` +
+		`databases, which are rebuilt from the remaining blocks on the next start of the peer. The blocks removed are ` +
+		`fetched again from the ordering service. The private data written by the blocks removed cannot be ` +
+		`recovered and is removed as well. Must be run while the peer is stopped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cmd.Flags().Changed("blockNumber") {
+			return errors.New("Must supply block number")
+		}
+		return rollback(rollbackChainID, rollbackBlockNumber)
+	},
+}
+
+func rollback(chainID string, blockNum uint64) error {
+	if chainID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+
+	ledgermgmt.Initialize()
+	defer ledgermgmt.Close()
+	if err := ledgermgmt.RollbackLedger(chainID, blockNum); err != nil {
+		return fmt.Errorf("Error rolling back ledger for channel %s: %s", chainID, err)
+	}
+	logger.Infof("Rolled back ledger for channel %s to block %d", chainID, blockNum)
+	return nil
+}
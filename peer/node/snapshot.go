@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/cobra"
+)
+
+var snapshotChainID string
+var snapshotDir string
+
+func snapshotCmd() *cobra.Command {
+	// Set the flags on the node snapshot command.
+	flags := nodeSnapshotCmd.Flags()
+	flags.StringVarP(&snapshotChainID, "channelID", "c", common.UndefinedParamValue,
+		"Channel whose ledger is to be exported")
+	flags.StringVarP(&snapshotDir, "snapshotpath", "", common.UndefinedParamValue,
+		"Directory to export the snapshot to, which must not exist or be empty")
+
+	return nodeSnapshotCmd
+}
+
+var nodeSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Exports a snapshot of a channel's ledger.",
+	Long: `Exports a snapshot of a channel's ledger at its last block: the state with the versions of the keys, ` +
+		`the IDs of the transactions, the last block and the last config block. Other peers join the channel ` +
+		`from the snapshot with "peer channel joinbysnapshot". Only supported with the goleveldb state database. ` +
+		`Must be run while the peer is stopped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return snapshot(snapshotChainID, snapshotDir)
+	},
+}
+
+func snapshot(chainID string, dir string) error {
+	if chainID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+	if dir == common.UndefinedParamValue {
+		return errors.New("Must supply snapshot path")
+	}
+	// the state of all the namespaces cannot be iterated over in CouchDB
+	if ledgerconfig.IsCouchDBEnabled() {
+		return errors.New("Exporting snapshots is not supported with the CouchDB state database")
+	}
+
+	ledgermgmt.Initialize()
+	defer ledgermgmt.Close()
+	l, err := ledgermgmt.OpenLedger(chainID)
+	if err != nil {
+		return fmt.Errorf("Error opening ledger for channel %s: %s", chainID, err)
+	}
+	defer l.Close()
+
+	if err = l.ExportSnapshot(dir); err != nil {
+		return fmt.Errorf("Error exporting snapshot of ledger for channel %s: %s", chainID, err)
+	}
+	logger.Infof("Exported snapshot of ledger for channel %s to %s", chainID, dir)
+	return nil
+}
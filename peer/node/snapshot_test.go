@@ -0,0 +1,26 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotCmd(t *testing.T) {
+	assert.Error(t, snapshot(common.UndefinedParamValue, "/tmp/snapshot"), "The channel ID should be required")
+	assert.Error(t, snapshot("mychannel", common.UndefinedParamValue), "The snapshot path should be required")
+
+	viper.Set("ledger.state.stateDatabase", "CouchDB")
+	defer viper.Set("ledger.state.stateDatabase", "goleveldb")
+	err := snapshot("mychannel", "/tmp/snapshot")
+	assert.Error(t, err, "Exporting a snapshot with CouchDB should have been rejected")
+	assert.Contains(t, err.Error(), "CouchDB")
+}
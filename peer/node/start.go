@@ -18,16 +18,21 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/hyperledger/fabric/common/crl"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/localmsp"
 	"github.com/hyperledger/fabric/core"
+	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/endorser"
+	"github.com/hyperledger/fabric/core/handlers/library"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/policy"
 	"github.com/hyperledger/fabric/core/scc"
 	"github.com/hyperledger/fabric/events/producer"
 	"github.com/hyperledger/fabric/gossip/service"
@@ -35,6 +40,7 @@ import (
 	"github.com/hyperledger/fabric/peer/common"
 	peergossip "github.com/hyperledger/fabric/peer/gossip"
 	"github.com/hyperledger/fabric/peer/version"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -100,6 +106,21 @@ func serve(args []string) error {
 		return err
 	}
 
+	opsSystem := newOperationsSystem()
+	if err := opsSystem.Start(); err != nil {
+		return fmt.Errorf("Failed to start the operations server: %s", err)
+	}
+	defer opsSystem.Stop()
+
+	// The local CRLs apply to the MSPs and the TLS client certificates
+	if crlDir := config.GetPath("peer.localCrlDir"); crlDir != "" {
+		stopWatchingCRLs, err := crl.LocalStore().WatchDir(crlDir)
+		if err != nil {
+			return fmt.Errorf("Failed to load the local CRLs: %s", err)
+		}
+		defer stopWatchingCRLs()
+	}
+
 	peerEndpoint, err := peer.GetPeerEndpoint()
 	if err != nil {
 		err = fmt.Errorf("Failed to get Peer Endpoint: %s", err)
@@ -142,10 +163,42 @@ func serve(args []string) error {
 	// Register the Admin server
 	pb.RegisterAdminServer(peerServer.Server(), core.NewAdminServer())
 
+	// Load the endorsement and validation plugins which take the place of
+	// the ESCCs and VSCCs of the chaincodes
+	handlersConfig, err := library.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("Failed to read the configuration of the handlers: %s", err)
+	}
+	handlersRegistry, err := library.InitRegistry(handlersConfig)
+	if err != nil {
+		return fmt.Errorf("Failed to load the handlers: %s", err)
+	}
+
+	// Limit the proposals processed concurrently for each chaincode and each
+	// client, reloading the limits whenever the configuration file changes
+	throttleConfig, err := endorser.LoadThrottleConfig(viper.GetViper())
+	if err != nil {
+		return fmt.Errorf("Failed to read the endorsement limits: %s", err)
+	}
+	endorsementThrottle := endorser.NewThrottle(throttleConfig)
+	if configFile := viper.ConfigFileUsed(); configFile != "" {
+		stopWatchingLimits, err := endorsementThrottle.WatchConfigFile(configFile)
+		if err != nil {
+			return fmt.Errorf("Failed to watch the endorsement limits: %s", err)
+		}
+		defer stopWatchingLimits()
+	}
+
 	// Register the Endorser server
-	serverEndorser := endorser.NewEndorserServer()
+	privDataDist := func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+		return service.GetGossipService().DistributePrivateData(channel, txID, privateData)
+	}
+	serverEndorser := endorser.NewEndorserServer(privDataDist, handlersRegistry, endorsementThrottle)
 	pb.RegisterEndorserServer(peerServer.Server(), serverEndorser)
 
+	// Register the Deliver server, which serves the blocks of the channels to the client applications
+	pb.RegisterDeliverServer(peerServer.Server(), peer.NewDeliverEventsServer())
+
 	// Initialize gossip component
 	bootstrap := viper.GetStringSlice("peer.gossip.bootstrap")
 
@@ -191,7 +244,7 @@ func serve(args []string) error {
 	peer.Initialize(func(cid string) {
 		logger.Debugf("Deploying system CC, for chain <%s>", cid)
 		scc.DeploySysCCs(cid)
-	})
+	}, handlersRegistry)
 
 	logger.Infof("Starting peer with ID=[%s], network ID=[%s], address=[%s]",
 		peerEndpoint.Id, viper.GetString("peer.networkId"), peerEndpoint.Address)
@@ -257,6 +310,26 @@ func serve(args []string) error {
 	return <-serve
 }
 
+// newOperationsSystem creates the operations server, which exposes the metrics
+// and the health of the peer, from the operations and metrics sections of core.yaml
+func newOperationsSystem() *operations.System {
+	var clientRootCAs []string
+	for _, caFile := range viper.GetStringSlice("operations.tls.clientRootCAs.files") {
+		clientRootCAs = append(clientRootCAs, config.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), caFile))
+	}
+	return operations.NewSystem(operations.Options{
+		ListenAddress:   viper.GetString("operations.listenAddress"),
+		MetricsProvider: viper.GetString("metrics.provider"),
+		TLS: operations.TLS{
+			Enabled:            viper.GetBool("operations.tls.enabled"),
+			CertFile:           config.GetPath("operations.tls.cert.file"),
+			KeyFile:            config.GetPath("operations.tls.key.file"),
+			ClientCertRequired: viper.GetBool("operations.tls.clientAuthRequired"),
+			ClientRootCAs:      clientRootCAs,
+		},
+	})
+}
+
 //create a CC listener using peer.chaincodeListenAddress (and if that's not set use peer.peerAddress)
 func createChaincodeServer(peerServer comm.GRPCServer, peerListenAddress string) (comm.GRPCServer, ccEndpointFunc) {
 	cclistenAddress := viper.GetString("peer.chaincodeListenAddress")
@@ -360,7 +433,15 @@ func createEventHubServer(secureConfig comm.SecureServerConfig) (comm.GRPCServer
 	}
 	ehServer := producer.NewEventsServer(
 		uint(viper.GetInt("peer.events.buffersize")),
-		viper.GetDuration("peer.events.timeout"))
+		viper.GetDuration("peer.events.timeout"),
+		aclmgmt.NewACLProvider(
+			policy.NewPolicyChecker(
+				peer.NewChannelPolicyManagerGetter(),
+				mgmt.GetLocalMSP(),
+				mgmt.NewLocalMSPPrincipalGetter(),
+			),
+			peer.NewChannelACLsGetter(),
+		))
 
 	pb.RegisterEventsServer(grpcServer.Server(), ehServer)
 	return grpcServer, nil
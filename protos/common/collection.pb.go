@@ -0,0 +1,353 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: common/collection.proto
+
+package common
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// CollectionConfigPackage represents an array of CollectionConfig
+// messages; the extra struct is required because repeated oneof is
+// forbidden by the protobuf syntax
+type CollectionConfigPackage struct {
+	Config []*CollectionConfig `protobuf:"bytes,1,rep,name=config" json:"config,omitempty"`
+}
+
+func (m *CollectionConfigPackage) Reset()                    { *m = CollectionConfigPackage{} }
+func (m *CollectionConfigPackage) String() string            { return proto.CompactTextString(m) }
+func (*CollectionConfigPackage) ProtoMessage()               {}
+func (*CollectionConfigPackage) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{0} }
+
+func (m *CollectionConfigPackage) GetConfig() []*CollectionConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+// CollectionConfig defines the configuration of a collection object;
+// it currently contains a single, static type.
+// Dynamic collections are deferred.
+type CollectionConfig struct {
+	// Types that are valid to be assigned to Payload:
+	//	*CollectionConfig_StaticCollectionConfig
+	Payload isCollectionConfig_Payload `protobuf_oneof:"payload"`
+}
+
+func (m *CollectionConfig) Reset()                    { *m = CollectionConfig{} }
+func (m *CollectionConfig) String() string            { return proto.CompactTextString(m) }
+func (*CollectionConfig) ProtoMessage()               {}
+func (*CollectionConfig) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{1} }
+
+type isCollectionConfig_Payload interface {
+	isCollectionConfig_Payload()
+}
+
+type CollectionConfig_StaticCollectionConfig struct {
+	StaticCollectionConfig *StaticCollectionConfig `protobuf:"bytes,1,opt,name=static_collection_config,json=staticCollectionConfig,oneof"`
+}
+
+func (*CollectionConfig_StaticCollectionConfig) isCollectionConfig_Payload() {}
+
+func (m *CollectionConfig) GetPayload() isCollectionConfig_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *CollectionConfig) GetStaticCollectionConfig() *StaticCollectionConfig {
+	if x, ok := m.GetPayload().(*CollectionConfig_StaticCollectionConfig); ok {
+		return x.StaticCollectionConfig
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*CollectionConfig) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _CollectionConfig_OneofMarshaler, _CollectionConfig_OneofUnmarshaler, _CollectionConfig_OneofSizer, []interface{}{
+		(*CollectionConfig_StaticCollectionConfig)(nil),
+	}
+}
+
+func _CollectionConfig_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*CollectionConfig)
+	// payload
+	switch x := m.Payload.(type) {
+	case *CollectionConfig_StaticCollectionConfig:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.StaticCollectionConfig); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("CollectionConfig.Payload has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _CollectionConfig_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*CollectionConfig)
+	switch tag {
+	case 1: // payload.static_collection_config
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(StaticCollectionConfig)
+		err := b.DecodeMessage(msg)
+		m.Payload = &CollectionConfig_StaticCollectionConfig{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _CollectionConfig_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*CollectionConfig)
+	// payload
+	switch x := m.Payload.(type) {
+	case *CollectionConfig_StaticCollectionConfig:
+		s := proto.Size(x.StaticCollectionConfig)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+// StaticCollectionConfig constitutes the configuration parameters of a
+// static collection object. Static collections are collections that are
+// known at chaincode instantiation time, and that cannot be changed.
+type StaticCollectionConfig struct {
+	// the name of the collection inside the denoted chaincode
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// a reference to a policy residing / managed in the config block
+	// to define which orgs have access to this collection's private data
+	MemberOrgsPolicy *CollectionPolicyConfig `protobuf:"bytes,2,opt,name=member_orgs_policy,json=memberOrgsPolicy" json:"member_orgs_policy,omitempty"`
+	// The minimum number of peers private data will be sent to upon
+	// endorsement. The endorsement would fail if dissemination to at least
+	// this number of peers is not achieved.
+	RequiredPeerCount int32 `protobuf:"varint,3,opt,name=required_peer_count,json=requiredPeerCount" json:"required_peer_count,omitempty"`
+	// The maximum number of peers that private data will be sent to
+	// upon endorsement. This number has to be bigger than required_peer_count.
+	MaximumPeerCount int32 `protobuf:"varint,4,opt,name=maximum_peer_count,json=maximumPeerCount" json:"maximum_peer_count,omitempty"`
+}
+
+func (m *StaticCollectionConfig) Reset()                    { *m = StaticCollectionConfig{} }
+func (m *StaticCollectionConfig) String() string            { return proto.CompactTextString(m) }
+func (*StaticCollectionConfig) ProtoMessage()               {}
+func (*StaticCollectionConfig) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{2} }
+
+func (m *StaticCollectionConfig) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *StaticCollectionConfig) GetMemberOrgsPolicy() *CollectionPolicyConfig {
+	if m != nil {
+		return m.MemberOrgsPolicy
+	}
+	return nil
+}
+
+func (m *StaticCollectionConfig) GetRequiredPeerCount() int32 {
+	if m != nil {
+		return m.RequiredPeerCount
+	}
+	return 0
+}
+
+func (m *StaticCollectionConfig) GetMaximumPeerCount() int32 {
+	if m != nil {
+		return m.MaximumPeerCount
+	}
+	return 0
+}
+
+// Collection policy configuration. Initially, the configuration can only
+// contain a SignaturePolicy. In the future, the SignaturePolicy may be a
+// more general Policy. Instead of containing the actual policy, the
+// configuration may in the future contain a string reference to a policy.
+type CollectionPolicyConfig struct {
+	// Types that are valid to be assigned to Payload:
+	//	*CollectionPolicyConfig_SignaturePolicy
+	Payload isCollectionPolicyConfig_Payload `protobuf_oneof:"payload"`
+}
+
+func (m *CollectionPolicyConfig) Reset()                    { *m = CollectionPolicyConfig{} }
+func (m *CollectionPolicyConfig) String() string            { return proto.CompactTextString(m) }
+func (*CollectionPolicyConfig) ProtoMessage()               {}
+func (*CollectionPolicyConfig) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{3} }
+
+type isCollectionPolicyConfig_Payload interface {
+	isCollectionPolicyConfig_Payload()
+}
+
+type CollectionPolicyConfig_SignaturePolicy struct {
+	SignaturePolicy *SignaturePolicyEnvelope `protobuf:"bytes,1,opt,name=signature_policy,json=signaturePolicy,oneof"`
+}
+
+func (*CollectionPolicyConfig_SignaturePolicy) isCollectionPolicyConfig_Payload() {}
+
+func (m *CollectionPolicyConfig) GetPayload() isCollectionPolicyConfig_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *CollectionPolicyConfig) GetSignaturePolicy() *SignaturePolicyEnvelope {
+	if x, ok := m.GetPayload().(*CollectionPolicyConfig_SignaturePolicy); ok {
+		return x.SignaturePolicy
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*CollectionPolicyConfig) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _CollectionPolicyConfig_OneofMarshaler, _CollectionPolicyConfig_OneofUnmarshaler, _CollectionPolicyConfig_OneofSizer, []interface{}{
+		(*CollectionPolicyConfig_SignaturePolicy)(nil),
+	}
+}
+
+func _CollectionPolicyConfig_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*CollectionPolicyConfig)
+	// payload
+	switch x := m.Payload.(type) {
+	case *CollectionPolicyConfig_SignaturePolicy:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SignaturePolicy); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("CollectionPolicyConfig.Payload has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _CollectionPolicyConfig_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*CollectionPolicyConfig)
+	switch tag {
+	case 1: // payload.signature_policy
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(SignaturePolicyEnvelope)
+		err := b.DecodeMessage(msg)
+		m.Payload = &CollectionPolicyConfig_SignaturePolicy{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _CollectionPolicyConfig_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*CollectionPolicyConfig)
+	// payload
+	switch x := m.Payload.(type) {
+	case *CollectionPolicyConfig_SignaturePolicy:
+		s := proto.Size(x.SignaturePolicy)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+// CollectionCriteria defines an element of a private data that corresponds
+// to a certain transaction and collection
+type CollectionCriteria struct {
+	Channel    string `protobuf:"bytes,1,opt,name=channel" json:"channel,omitempty"`
+	TxId       string `protobuf:"bytes,2,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	Collection string `protobuf:"bytes,3,opt,name=collection" json:"collection,omitempty"`
+	Namespace  string `protobuf:"bytes,4,opt,name=namespace" json:"namespace,omitempty"`
+}
+
+func (m *CollectionCriteria) Reset()                    { *m = CollectionCriteria{} }
+func (m *CollectionCriteria) String() string            { return proto.CompactTextString(m) }
+func (*CollectionCriteria) ProtoMessage()               {}
+func (*CollectionCriteria) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{4} }
+
+func (m *CollectionCriteria) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *CollectionCriteria) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *CollectionCriteria) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+func (m *CollectionCriteria) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*CollectionConfigPackage)(nil), "common.CollectionConfigPackage")
+	proto.RegisterType((*CollectionConfig)(nil), "common.CollectionConfig")
+	proto.RegisterType((*StaticCollectionConfig)(nil), "common.StaticCollectionConfig")
+	proto.RegisterType((*CollectionPolicyConfig)(nil), "common.CollectionPolicyConfig")
+	proto.RegisterType((*CollectionCriteria)(nil), "common.CollectionCriteria")
+}
+
+func init() { proto.RegisterFile("common/collection.proto", fileDescriptor5) }
+
+var fileDescriptor5 = []byte{
+	// 426 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0xc1, 0x6a, 0xdb, 0x40,
+	0x10, 0x86, 0xa3, 0xc6, 0x71, 0xd0, 0xe4, 0x50, 0x77, 0x42, 0x1d, 0x51, 0x4a, 0x1a, 0x4c, 0x0f,
+	0x86, 0x16, 0xa9, 0xa4, 0x6f, 0x10, 0x53, 0x48, 0x69, 0xa0, 0x46, 0xb9, 0xe5, 0x22, 0xd6, 0xab,
+	0x89, 0xbc, 0x54, 0xda, 0x55, 0x76, 0x57, 0xc5, 0x3e, 0xf6, 0x2d, 0xfb, 0x38, 0xc1, 0xbb, 0x92,
+	0x2d, 0x1b, 0xdf, 0x3c, 0xf3, 0x7f, 0xf3, 0x7b, 0xe6, 0x5f, 0xc1, 0x15, 0x57, 0x55, 0xa5, 0x64,
+	0xc2, 0x55, 0x59, 0x12, 0xb7, 0x42, 0xc9, 0xb8, 0xd6, 0xca, 0x2a, 0x1c, 0x7a, 0xe1, 0xc3, 0xfb,
+	0x16, 0xa8, 0x55, 0x29, 0xb8, 0x20, 0xe3, 0xe5, 0xc9, 0x2f, 0xb8, 0x9a, 0x6d, 0x47, 0x66, 0x4a,
+	0x3e, 0x8b, 0x62, 0xce, 0xf8, 0x1f, 0x56, 0x10, 0x7e, 0x83, 0x21, 0x77, 0x8d, 0x28, 0xb8, 0x39,
+	0x9d, 0x5e, 0xdc, 0x46, 0xb1, 0xb7, 0x88, 0x0f, 0x07, 0xd2, 0x96, 0x9b, 0xac, 0x61, 0x74, 0xa8,
+	0xe1, 0x13, 0x44, 0xc6, 0x32, 0x2b, 0x78, 0xb6, 0x5b, 0x2d, 0xdb, 0xfa, 0x06, 0xd3, 0x8b, 0xdb,
+	0xeb, 0xce, 0xf7, 0xd1, 0x71, 0x87, 0x0e, 0xf7, 0x27, 0xe9, 0xd8, 0x1c, 0x55, 0xee, 0x42, 0x38,
+	0xaf, 0xd9, 0xba, 0x54, 0x2c, 0x9f, 0xfc, 0x0f, 0x60, 0x7c, 0x7c, 0x1e, 0x11, 0x06, 0x92, 0x55,
+	0xe4, 0xfe, 0x2d, 0x4c, 0xdd, 0x6f, 0x7c, 0x00, 0xac, 0xa8, 0x5a, 0x90, 0xce, 0x94, 0x2e, 0x4c,
+	0xe6, 0x42, 0x59, 0x47, 0x6f, 0xf6, 0xf7, 0xd9, 0x39, 0xcd, 0x9d, 0xde, 0x5e, 0x3b, 0xf2, 0x93,
+	0xbf, 0x75, 0x61, 0x7c, 0x1f, 0x63, 0xb8, 0xd4, 0xf4, 0xd2, 0x08, 0x4d, 0x79, 0x56, 0x13, 0xe9,
+	0x8c, 0xab, 0x46, 0xda, 0xe8, 0xf4, 0x26, 0x98, 0x9e, 0xa5, 0xef, 0x3a, 0x69, 0x4e, 0xa4, 0x67,
+	0x1b, 0x01, 0xbf, 0x02, 0x56, 0x6c, 0x25, 0xaa, 0xa6, 0xea, 0xe3, 0x03, 0x87, 0x8f, 0x5a, 0x65,
+	0x4b, 0x4f, 0x5e, 0x60, 0x7c, 0x7c, 0x13, 0x7c, 0x80, 0x91, 0x11, 0x85, 0x64, 0xb6, 0xd1, 0xd4,
+	0xdd, 0xe0, 0x33, 0xfd, 0xb4, 0xcd, 0xb4, 0xd3, 0xfd, 0xe0, 0x0f, 0xf9, 0x97, 0x4a, 0x55, 0xd3,
+	0xfd, 0x49, 0xfa, 0xd6, 0xec, 0x4b, 0xfd, 0x34, 0xff, 0x05, 0x80, 0xbd, 0x1c, 0xb5, 0xb0, 0xa4,
+	0x05, 0xc3, 0x08, 0xce, 0xf9, 0x92, 0x49, 0x49, 0x65, 0x1b, 0x66, 0x57, 0xe2, 0x25, 0x9c, 0xd9,
+	0x55, 0x26, 0x72, 0x17, 0x61, 0x98, 0x0e, 0xec, 0xea, 0x67, 0x8e, 0xd7, 0x00, 0xbb, 0x37, 0x77,
+	0x69, 0x84, 0x69, 0xaf, 0x83, 0x1f, 0x21, 0xdc, 0x3c, 0x86, 0xa9, 0x19, 0x27, 0x77, 0x7d, 0x98,
+	0xee, 0x1a, 0x77, 0x8f, 0xf0, 0x59, 0xe9, 0x22, 0x5e, 0xae, 0x6b, 0xd2, 0x25, 0xe5, 0x05, 0xe9,
+	0xf8, 0x99, 0x2d, 0xb4, 0xe0, 0xfe, 0xcb, 0x35, 0xed, 0x85, 0x4f, 0x5f, 0x0a, 0x61, 0x97, 0xcd,
+	0x62, 0x53, 0x26, 0x3d, 0x38, 0xf1, 0x70, 0xe2, 0xe1, 0xc4, 0xc3, 0x8b, 0xa1, 0x2b, 0xbf, 0xbf,
+	0x0e, 0x00, 0xa8, 0x98, 0x7f, 0x6c, 0x2f, 0x03, 0x00, 0x00,
+}
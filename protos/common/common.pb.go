@@ -10,6 +10,7 @@ It is generated from these files:
 	common/configuration.proto
 	common/ledger.proto
 	common/policies.proto
+	common/collection.proto
 
 It has these top-level messages:
 	LastConfig
@@ -44,6 +45,11 @@ It has these top-level messages:
 	SignaturePolicyEnvelope
 	SignaturePolicy
 	ImplicitMetaPolicy
+	CollectionConfigPackage
+	CollectionConfig
+	StaticCollectionConfig
+	CollectionPolicyConfig
+	CollectionCriteria
 */
 package common
 
@@ -231,6 +231,11 @@ func (m *GossipMessage) IsLeadershipMsg() bool {
 	return m.GetLeadershipMsg() != nil
 }
 
+// IsPrivateDataMsg returns whether this GossipMessage carries private data
+func (m *GossipMessage) IsPrivateDataMsg() bool {
+	return m.GetPrivateData() != nil
+}
+
 // MsgConsumer invokes code given a SignedGossipMessage
 type MsgConsumer func(message *SignedGossipMessage)
 
@@ -295,6 +300,13 @@ func (m *GossipMessage) IsTagLegal() error {
 		return nil
 	}
 
+	if m.IsPrivateDataMsg() {
+		if m.Tag != GossipMessage_CHAN_ONLY {
+			return fmt.Errorf("Tag should be %s", GossipMessage_Tag_name[int32(GossipMessage_CHAN_ONLY)])
+		}
+		return nil
+	}
+
 	return fmt.Errorf("Unknown message type: %v", m)
 }
 
@@ -673,6 +673,22 @@ func TestGossipMessageLeadershipMessageTagType(t *testing.T) {
 	assert.Error(t, msg.IsTagLegal())
 }
 
+func TestGossipMessagePrivateDataMessageTagType(t *testing.T) {
+	var msg *SignedGossipMessage
+	channelID := "testID1"
+
+	msg = signedGossipMessage(channelID, GossipMessage_CHAN_ONLY, &GossipMessage_PrivateData{
+		PrivateData: &PrivateDataMessage{},
+	})
+	assert.True(t, msg.IsPrivateDataMsg())
+	assert.NoError(t, msg.IsTagLegal())
+
+	msg = signedGossipMessage(channelID, GossipMessage_CHAN_AND_ORG, &GossipMessage_PrivateData{
+		PrivateData: &PrivateDataMessage{},
+	})
+	assert.Error(t, msg.IsTagLegal())
+}
+
 func TestConnectionInfo_IsAuthenticated(t *testing.T) {
 	connInfo := &ConnectionInfo{
 		ID: common.PKIidType("peerID"),
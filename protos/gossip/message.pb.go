@@ -32,12 +32,15 @@ It has these top-level messages:
 	Empty
 	RemoteStateRequest
 	RemoteStateResponse
+	PrivateDataMessage
+	PrivatePayload
 */
 package gossip
 
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import rwset "github.com/hyperledger/fabric/protos/ledger/rwset"
 
 import (
 	context "golang.org/x/net/context"
@@ -296,6 +299,7 @@ type GossipMessage struct {
 	//	*GossipMessage_StateResponse
 	//	*GossipMessage_LeadershipMsg
 	//	*GossipMessage_PeerIdentity
+	//	*GossipMessage_PrivateData
 	Content isGossipMessage_Content `protobuf_oneof:"content"`
 }
 
@@ -359,6 +363,9 @@ type GossipMessage_LeadershipMsg struct {
 type GossipMessage_PeerIdentity struct {
 	PeerIdentity *PeerIdentity `protobuf:"bytes,21,opt,name=peer_identity,json=peerIdentity,oneof"`
 }
+type GossipMessage_PrivateData struct {
+	PrivateData *PrivateDataMessage `protobuf:"bytes,22,opt,name=private_data,json=privateData,oneof"`
+}
 
 func (*GossipMessage_AliveMsg) isGossipMessage_Content()         {}
 func (*GossipMessage_MemReq) isGossipMessage_Content()           {}
@@ -377,6 +384,7 @@ func (*GossipMessage_StateRequest) isGossipMessage_Content()     {}
 func (*GossipMessage_StateResponse) isGossipMessage_Content()    {}
 func (*GossipMessage_LeadershipMsg) isGossipMessage_Content()    {}
 func (*GossipMessage_PeerIdentity) isGossipMessage_Content()     {}
+func (*GossipMessage_PrivateData) isGossipMessage_Content()      {}
 
 func (m *GossipMessage) GetContent() isGossipMessage_Content {
 	if m != nil {
@@ -525,6 +533,13 @@ func (m *GossipMessage) GetPeerIdentity() *PeerIdentity {
 	return nil
 }
 
+func (m *GossipMessage) GetPrivateData() *PrivateDataMessage {
+	if x, ok := m.GetContent().(*GossipMessage_PrivateData); ok {
+		return x.PrivateData
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*GossipMessage) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _GossipMessage_OneofMarshaler, _GossipMessage_OneofUnmarshaler, _GossipMessage_OneofSizer, []interface{}{
@@ -545,6 +560,7 @@ func (*GossipMessage) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer)
 		(*GossipMessage_StateResponse)(nil),
 		(*GossipMessage_LeadershipMsg)(nil),
 		(*GossipMessage_PeerIdentity)(nil),
+		(*GossipMessage_PrivateData)(nil),
 	}
 }
 
@@ -637,6 +653,11 @@ func _GossipMessage_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.PeerIdentity); err != nil {
 			return err
 		}
+	case *GossipMessage_PrivateData:
+		b.EncodeVarint(22<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.PrivateData); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("GossipMessage.Content has unexpected type %T", x)
@@ -783,6 +804,14 @@ func _GossipMessage_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.
 		err := b.DecodeMessage(msg)
 		m.Content = &GossipMessage_PeerIdentity{msg}
 		return true, err
+	case 22: // content.private_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(PrivateDataMessage)
+		err := b.DecodeMessage(msg)
+		m.Content = &GossipMessage_PrivateData{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -877,6 +906,11 @@ func _GossipMessage_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(21<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *GossipMessage_PrivateData:
+		s := proto.Size(x.PrivateData)
+		n += proto.SizeVarint(22<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -1446,6 +1480,76 @@ func (m *RemoteStateResponse) GetPayloads() []*Payload {
 	return nil
 }
 
+// PrivateDataMessage message which includes private
+// data information to distributed once transaction
+// has been endorsed
+type PrivateDataMessage struct {
+	Payload *PrivatePayload `protobuf:"bytes,1,opt,name=payload" json:"payload,omitempty"`
+}
+
+func (m *PrivateDataMessage) Reset()                    { *m = PrivateDataMessage{} }
+func (m *PrivateDataMessage) String() string            { return proto.CompactTextString(m) }
+func (*PrivateDataMessage) ProtoMessage()               {}
+func (*PrivateDataMessage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *PrivateDataMessage) GetPayload() *PrivatePayload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+// PrivatePayload payload to encapsulate private
+// data with collection name to enable routing
+// based on collection partitioning
+type PrivatePayload struct {
+	CollectionName   string                           `protobuf:"bytes,1,opt,name=collection_name,json=collectionName" json:"collection_name,omitempty"`
+	Namespace        string                           `protobuf:"bytes,2,opt,name=namespace" json:"namespace,omitempty"`
+	TxId             string                           `protobuf:"bytes,3,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	PrivateRwset     *rwset.CollectionPvtReadWriteSet `protobuf:"bytes,4,opt,name=private_rwset,json=privateRwset" json:"private_rwset,omitempty"`
+	PrivateSimHeight uint64                           `protobuf:"varint,5,opt,name=private_sim_height,json=privateSimHeight" json:"private_sim_height,omitempty"`
+}
+
+func (m *PrivatePayload) Reset()                    { *m = PrivatePayload{} }
+func (m *PrivatePayload) String() string            { return proto.CompactTextString(m) }
+func (*PrivatePayload) ProtoMessage()               {}
+func (*PrivatePayload) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *PrivatePayload) GetCollectionName() string {
+	if m != nil {
+		return m.CollectionName
+	}
+	return ""
+}
+
+func (m *PrivatePayload) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *PrivatePayload) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *PrivatePayload) GetPrivateRwset() *rwset.CollectionPvtReadWriteSet {
+	if m != nil {
+		return m.PrivateRwset
+	}
+	return nil
+}
+
+func (m *PrivatePayload) GetPrivateSimHeight() uint64 {
+	if m != nil {
+		return m.PrivateSimHeight
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Envelope)(nil), "gossip.Envelope")
 	proto.RegisterType((*SecretEnvelope)(nil), "gossip.SecretEnvelope")
@@ -1471,6 +1575,8 @@ func init() {
 	proto.RegisterType((*Empty)(nil), "gossip.Empty")
 	proto.RegisterType((*RemoteStateRequest)(nil), "gossip.RemoteStateRequest")
 	proto.RegisterType((*RemoteStateResponse)(nil), "gossip.RemoteStateResponse")
+	proto.RegisterType((*PrivateDataMessage)(nil), "gossip.PrivateDataMessage")
+	proto.RegisterType((*PrivatePayload)(nil), "gossip.PrivatePayload")
 	proto.RegisterEnum("gossip.PullMsgType", PullMsgType_name, PullMsgType_value)
 	proto.RegisterEnum("gossip.GossipMessage_Tag", GossipMessage_Tag_name, GossipMessage_Tag_value)
 }
@@ -1620,91 +1726,101 @@ var _Gossip_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("gossip/message.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1366 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6f, 0xdc, 0x44,
-	0x10, 0x3f, 0x27, 0xf7, 0x39, 0xf7, 0x91, 0xcb, 0x26, 0x05, 0x13, 0x2a, 0x88, 0x2c, 0x5a, 0x05,
-	0x52, 0x2e, 0x55, 0xca, 0x47, 0xa5, 0x82, 0xd0, 0x25, 0x77, 0xe4, 0x22, 0x7a, 0x49, 0xe4, 0xa4,
-	0x82, 0xf2, 0x62, 0x6d, 0xce, 0x13, 0x9f, 0xa9, 0xbd, 0x76, 0xbc, 0x7b, 0x85, 0x3c, 0x22, 0xde,
-	0x78, 0xe1, 0x95, 0x3f, 0x17, 0x79, 0xd7, 0xf6, 0xd9, 0x75, 0x52, 0xa9, 0x95, 0x78, 0xf3, 0x7c,
-	0xfc, 0x66, 0x66, 0x67, 0xe7, 0x63, 0x0d, 0x9b, 0x4e, 0xc0, 0xb9, 0x1b, 0xee, 0xf9, 0xc8, 0x39,
-	0x75, 0x70, 0x10, 0x46, 0x81, 0x08, 0x48, 0x5d, 0x71, 0x8d, 0xbf, 0x34, 0x68, 0x8e, 0xd9, 0x6b,
-	0xf4, 0x82, 0x10, 0x89, 0x0e, 0x8d, 0x90, 0xde, 0x78, 0x01, 0xb5, 0x75, 0x6d, 0x5b, 0xdb, 0xe9,
-	0x98, 0x29, 0x49, 0xee, 0x43, 0x8b, 0xbb, 0x0e, 0xa3, 0x62, 0x11, 0xa1, 0xbe, 0x22, 0x65, 0x4b,
-	0x06, 0xf9, 0x01, 0xd6, 0x38, 0xce, 0x22, 0x14, 0x16, 0x26, 0xa6, 0xf4, 0xd5, 0x6d, 0x6d, 0xa7,
-	0xbd, 0xff, 0xc1, 0x40, 0xb9, 0x19, 0x9c, 0x4b, 0x71, 0xea, 0xc8, 0xec, 0xf1, 0x02, 0x6d, 0x4c,
-	0xa0, 0x57, 0xd4, 0x78, 0xdf, 0x50, 0x8c, 0x21, 0xd4, 0x95, 0x25, 0xf2, 0x08, 0xfa, 0x2e, 0x13,
-	0x18, 0x31, 0xea, 0x8d, 0x99, 0x1d, 0x06, 0x2e, 0x13, 0xd2, 0x54, 0x6b, 0x52, 0x31, 0x4b, 0x92,
-	0x83, 0x16, 0x34, 0x66, 0x01, 0x13, 0xc8, 0x84, 0xf1, 0x6f, 0x0b, 0xba, 0x47, 0x32, 0xec, 0xa9,
-	0x4a, 0x19, 0xd9, 0x84, 0x1a, 0x0b, 0xd8, 0x0c, 0x25, 0xbe, 0x6a, 0x2a, 0x22, 0x0e, 0x71, 0x36,
-	0xa7, 0x8c, 0xa1, 0x97, 0x84, 0x91, 0x92, 0x64, 0x17, 0x56, 0x05, 0x75, 0x64, 0x0e, 0x7a, 0xfb,
-	0x1f, 0xa5, 0x39, 0x28, 0xd8, 0x1c, 0x5c, 0x50, 0xc7, 0x8c, 0xb5, 0xc8, 0x13, 0x68, 0x51, 0xcf,
-	0x7d, 0x8d, 0x96, 0xcf, 0x1d, 0xbd, 0x26, 0xd3, 0xb6, 0x99, 0x42, 0x86, 0xb1, 0x20, 0x41, 0x4c,
-	0x2a, 0x66, 0x53, 0x2a, 0x4e, 0xb9, 0x43, 0xbe, 0x82, 0x86, 0x8f, 0xbe, 0x15, 0xe1, 0xb5, 0x5e,
-	0x97, 0x90, 0xcc, 0xcb, 0x14, 0xfd, 0x4b, 0x8c, 0xf8, 0xdc, 0x0d, 0x4d, 0xbc, 0x5e, 0x20, 0x17,
-	0x93, 0x8a, 0x59, 0xf7, 0xd1, 0x37, 0xf1, 0x9a, 0x7c, 0x9d, 0xa2, 0xb8, 0xde, 0x90, 0xa8, 0xad,
-	0xdb, 0x50, 0x3c, 0x0c, 0x18, 0xc7, 0x0c, 0xc6, 0xc9, 0x63, 0x68, 0xda, 0x54, 0x50, 0x19, 0x60,
-	0x53, 0xe2, 0x36, 0x52, 0xdc, 0x88, 0x0a, 0xba, 0x8c, 0xaf, 0x11, 0xab, 0xc5, 0xe1, 0xed, 0x42,
-	0x6d, 0x8e, 0x9e, 0x17, 0xe8, 0xad, 0xa2, 0xba, 0x4a, 0xc1, 0x24, 0x16, 0x4d, 0x2a, 0xa6, 0xd2,
-	0x21, 0x7b, 0x89, 0x79, 0xdb, 0x75, 0x74, 0x90, 0xfa, 0x24, 0x6f, 0x7e, 0xe4, 0x3a, 0xea, 0x14,
-	0xd2, 0xfa, 0xc8, 0x75, 0xb2, 0x78, 0xe2, 0xd3, 0xb7, 0xcb, 0xf1, 0x2c, 0xcf, 0x2d, 0x11, 0xea,
-	0xe0, 0x6d, 0x89, 0x58, 0x84, 0x36, 0x15, 0xa8, 0x77, 0xca, 0x5e, 0x5e, 0x48, 0xc9, 0xa4, 0x62,
-	0x82, 0x9d, 0x51, 0xe4, 0x01, 0xd4, 0xd0, 0x0f, 0xc5, 0x8d, 0xde, 0x95, 0x80, 0x6e, 0x0a, 0x18,
-	0xc7, 0xcc, 0xf8, 0x00, 0x52, 0x4a, 0x76, 0xa1, 0x3a, 0x0b, 0x18, 0xd3, 0x7b, 0x52, 0xeb, 0x5e,
-	0xaa, 0x75, 0x18, 0x30, 0x36, 0xe6, 0x82, 0x5e, 0x7a, 0x2e, 0x9f, 0x4f, 0x2a, 0xa6, 0x54, 0x22,
-	0xfb, 0x00, 0x5c, 0x50, 0x81, 0x96, 0xcb, 0xae, 0x02, 0x7d, 0x4d, 0x42, 0xd6, 0xb3, 0x36, 0x89,
-	0x25, 0xc7, 0xec, 0x2a, 0xce, 0x4e, 0x8b, 0xa7, 0x04, 0x39, 0x80, 0x9e, 0xc2, 0x70, 0x46, 0x43,
-	0x3e, 0x0f, 0x84, 0xde, 0x2f, 0x5e, 0x7a, 0x86, 0x3b, 0x4f, 0x14, 0x26, 0x15, 0xb3, 0x2b, 0x21,
-	0x29, 0x83, 0x4c, 0x61, 0x63, 0xe9, 0xd7, 0x0a, 0x17, 0x9e, 0x27, 0xf3, 0xb7, 0x2e, 0x0d, 0xdd,
-	0x2f, 0x19, 0x3a, 0x5b, 0x78, 0xde, 0x32, 0x91, 0x7d, 0xfe, 0x06, 0x9f, 0x0c, 0x41, 0xd9, 0x8f,
-	0x8d, 0xc4, 0x4a, 0x3a, 0x29, 0x16, 0x94, 0x89, 0x7e, 0x20, 0x50, 0x9a, 0x5b, 0x9a, 0xe9, 0xf0,
-	0x1c, 0x4d, 0x46, 0xe9, 0xa9, 0xa2, 0xa4, 0xe4, 0xf4, 0x0d, 0x69, 0xe3, 0xe3, 0x5b, 0x6d, 0x64,
-	0x55, 0xd9, 0xe5, 0x79, 0x46, 0x9c, 0x1b, 0x0f, 0xa9, 0xad, 0x8a, 0x57, 0x96, 0xe8, 0x66, 0x31,
-	0x37, 0xcf, 0x33, 0xe9, 0xb2, 0x50, 0xbb, 0x4b, 0x48, 0x5c, 0xae, 0xcf, 0xa0, 0x1b, 0x22, 0x46,
-	0x96, 0x6b, 0x23, 0x13, 0xae, 0xb8, 0xd1, 0xef, 0x15, 0xdb, 0xf0, 0x0c, 0x31, 0x3a, 0x4e, 0x64,
-	0xf1, 0x31, 0xc2, 0x1c, 0x6d, 0x58, 0xb0, 0x7a, 0x41, 0x1d, 0xd2, 0x85, 0xd6, 0x8b, 0x93, 0xd1,
-	0xf8, 0xc7, 0xe3, 0x93, 0xf1, 0xa8, 0x5f, 0x21, 0x2d, 0xa8, 0x8d, 0xa7, 0x67, 0x17, 0x2f, 0xfb,
-	0x1a, 0xe9, 0x40, 0xf3, 0xd4, 0x3c, 0xb2, 0x4e, 0x4f, 0x9e, 0xbf, 0xec, 0xaf, 0xc4, 0x7a, 0x87,
-	0x93, 0xe1, 0x89, 0x22, 0x57, 0x49, 0x1f, 0x3a, 0x92, 0x1c, 0x9e, 0x8c, 0xac, 0x53, 0xf3, 0xa8,
-	0x5f, 0x25, 0x6b, 0xd0, 0x56, 0x0a, 0xa6, 0x64, 0xd4, 0xf2, 0xa3, 0xe9, 0x1f, 0x0d, 0x5a, 0xd9,
-	0x15, 0x91, 0x2d, 0x68, 0xfa, 0x28, 0x68, 0x5c, 0xb0, 0xc9, 0x90, 0xcc, 0x68, 0x32, 0x80, 0x96,
-	0x70, 0x7d, 0xe4, 0x82, 0xfa, 0xa1, 0x1c, 0x4f, 0xed, 0xfd, 0x7e, 0xfe, 0x38, 0x17, 0xae, 0x8f,
-	0xe6, 0x52, 0x85, 0xdc, 0x83, 0x7a, 0xf8, 0xca, 0xb5, 0x5c, 0x5b, 0x4e, 0xad, 0x8e, 0x59, 0x0b,
-	0x5f, 0xb9, 0xc7, 0x36, 0xf9, 0x14, 0xda, 0xc9, 0x50, 0xb3, 0xa6, 0xc3, 0x43, 0xbd, 0x2a, 0x65,
-	0x90, 0xb0, 0xa6, 0xc3, 0x43, 0x63, 0x08, 0xeb, 0xa5, 0xe2, 0x23, 0x8f, 0xa0, 0x89, 0x1e, 0xfa,
-	0xc8, 0x04, 0xd7, 0xb5, 0xed, 0xd5, 0xbc, 0xef, 0x6c, 0x05, 0x64, 0x1a, 0xc6, 0xb7, 0xb0, 0x79,
-	0x5b, 0xd9, 0xbd, 0xe9, 0x5b, 0x2b, 0xf9, 0xbe, 0x82, 0x6e, 0xa1, 0xc7, 0x72, 0x87, 0xd0, 0xf2,
-	0x87, 0xd8, 0x82, 0x66, 0x76, 0xb3, 0x6a, 0x52, 0x67, 0x34, 0x31, 0xa0, 0x2b, 0x3c, 0x6e, 0xcd,
-	0x30, 0x12, 0xd6, 0x9c, 0xf2, 0x79, 0x72, 0xfc, 0xb6, 0xf0, 0xf8, 0x21, 0x46, 0x62, 0x42, 0xf9,
-	0xdc, 0x78, 0x01, 0x9d, 0x7c, 0x05, 0xdc, 0xe5, 0x86, 0x40, 0x35, 0x36, 0x93, 0xb8, 0x90, 0xdf,
-	0x85, 0x2b, 0x5a, 0x2d, 0x5e, 0x91, 0xe1, 0x43, 0x3b, 0x37, 0xae, 0xee, 0x5e, 0x32, 0xb6, 0x1c,
-	0x80, 0x5c, 0x5f, 0xd9, 0x5e, 0xdd, 0x69, 0x99, 0x29, 0x49, 0x06, 0xd0, 0xf4, 0xb9, 0x63, 0x89,
-	0x9b, 0x64, 0xdb, 0xf6, 0x96, 0x53, 0x30, 0xce, 0xe2, 0x94, 0x3b, 0x17, 0x37, 0x21, 0x9a, 0x0d,
-	0x5f, 0x7d, 0x18, 0x01, 0xb4, 0x73, 0xe3, 0xf7, 0x0e, 0x77, 0xf9, 0x78, 0x57, 0x4a, 0x25, 0xf5,
-	0x6e, 0x0e, 0xff, 0x00, 0x58, 0x4e, 0xd6, 0x3b, 0xfc, 0x7d, 0x06, 0xd5, 0xc4, 0xd7, 0xed, 0x55,
-	0x52, 0x7d, 0x2f, 0xcf, 0x9e, 0xf2, 0xac, 0x36, 0xc7, 0xff, 0x9e, 0xd8, 0xa7, 0xea, 0x1e, 0xd3,
-	0xc7, 0xc2, 0xe7, 0xc5, 0x97, 0x4b, 0x7b, 0x7f, 0x2d, 0x43, 0x2b, 0x76, 0xf6, 0x94, 0x31, 0xbe,
-	0x81, 0x46, 0xc2, 0x23, 0x1f, 0x42, 0x83, 0xe3, 0xb5, 0xc5, 0x16, 0x7e, 0x12, 0x66, 0x9d, 0xe3,
-	0xf5, 0xc9, 0xc2, 0x8f, 0xab, 0x2a, 0x77, 0x1b, 0xf2, 0xdb, 0xf8, 0x5b, 0x83, 0x4e, 0xfe, 0x69,
-	0x40, 0x06, 0x00, 0x7e, 0xb6, 0xc1, 0x13, 0xb7, 0xbd, 0xe2, 0x6e, 0x37, 0x73, 0x1a, 0xef, 0x3c,
-	0x1d, 0xf2, 0x1d, 0x54, 0x2d, 0x76, 0x90, 0xf1, 0xa7, 0x06, 0xeb, 0xa5, 0x19, 0x7b, 0x57, 0x8f,
-	0xbc, 0xab, 0xe3, 0x07, 0xd0, 0x73, 0xb9, 0x65, 0xe3, 0xcc, 0xa3, 0x11, 0x15, 0x6e, 0xc0, 0xe4,
-	0x8d, 0x34, 0xcd, 0xae, 0xcb, 0x47, 0x4b, 0xa6, 0xf1, 0x1d, 0x34, 0x53, 0x74, 0x9c, 0x49, 0x97,
-	0xcd, 0xf2, 0x99, 0x74, 0xd9, 0x2c, 0xce, 0x64, 0x2e, 0xc5, 0x2b, 0xf9, 0x14, 0x1b, 0x57, 0xb0,
-	0x5e, 0x7a, 0x35, 0x91, 0x67, 0xd0, 0xe7, 0xe8, 0x5d, 0xc9, 0x75, 0x19, 0xf9, 0xca, 0xb7, 0x56,
-	0x0c, 0x38, 0xab, 0xd2, 0xb5, 0x58, 0xf3, 0x78, 0xa9, 0x18, 0x97, 0xdc, 0x2b, 0x16, 0xfc, 0xce,
-	0x64, 0x69, 0x75, 0x4c, 0x45, 0x18, 0x97, 0x40, 0xca, 0xef, 0x2c, 0xf2, 0x10, 0x6a, 0xf2, 0x59,
-	0x77, 0xe7, 0xa4, 0x54, 0x62, 0xd9, 0x2a, 0x48, 0xed, 0xb7, 0xb4, 0x0a, 0x52, 0xdb, 0xf8, 0x19,
-	0xea, 0xca, 0x47, 0x7c, 0x67, 0x58, 0x78, 0xf7, 0x9a, 0x19, 0xfd, 0xd6, 0x36, 0xbf, 0x7d, 0x13,
-	0x18, 0x0d, 0xa8, 0xc9, 0x67, 0x8f, 0xf1, 0x0b, 0x90, 0xf2, 0x72, 0x8f, 0xe7, 0x28, 0x17, 0x34,
-	0x12, 0x56, 0xb1, 0x8a, 0xdb, 0x92, 0x79, 0xae, 0x4a, 0xf9, 0x13, 0x68, 0x23, 0xb3, 0xad, 0xe2,
-	0x25, 0xb4, 0x90, 0xd9, 0x4a, 0x6e, 0x1c, 0xc0, 0xc6, 0x2d, 0x2b, 0x9f, 0xec, 0x42, 0x33, 0x69,
-	0x98, 0x74, 0x9b, 0x94, 0x3a, 0x2a, 0x53, 0xf8, 0xe2, 0x7b, 0x68, 0xe7, 0x9a, 0xf4, 0xcd, 0xad,
-	0xdc, 0x85, 0xd6, 0xc1, 0xf3, 0xd3, 0xc3, 0x9f, 0xac, 0xe9, 0xf9, 0x51, 0x5f, 0x8b, 0x97, 0xef,
-	0xf1, 0x68, 0x7c, 0x72, 0x71, 0x7c, 0xf1, 0x52, 0x72, 0x56, 0xf6, 0x7f, 0x83, 0xba, 0x1a, 0x92,
-	0xe4, 0x29, 0x74, 0xd4, 0xd7, 0xb9, 0x88, 0x90, 0xfa, 0xa4, 0x94, 0xf0, 0xad, 0x12, 0xc7, 0xa8,
-	0xec, 0x68, 0x8f, 0x35, 0xf2, 0x10, 0xaa, 0x67, 0x2e, 0x73, 0x48, 0xf1, 0xb9, 0xb8, 0x55, 0x24,
-	0x8d, 0xca, 0xc1, 0x97, 0xbf, 0xee, 0x3a, 0xae, 0x98, 0x2f, 0x2e, 0x07, 0xb3, 0xc0, 0xdf, 0x9b,
-	0xdf, 0x84, 0x18, 0x79, 0x68, 0x3b, 0x18, 0xed, 0x5d, 0xd1, 0xcb, 0xc8, 0x9d, 0xed, 0xc9, 0x3f,
-	0x35, 0xbe, 0xa7, 0x60, 0x97, 0x75, 0x49, 0x3e, 0xf9, 0x2f, 0x00, 0x00, 0xff, 0xff, 0x74, 0x25,
-	0x05, 0xf1, 0xd0, 0x0d, 0x00, 0x00,
+	// 1534 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6f, 0xdb, 0xc8,
+	0x11, 0x17, 0x6d, 0x7d, 0x71, 0x28, 0xc9, 0xf2, 0xda, 0x49, 0x59, 0xf7, 0xd0, 0x1a, 0x44, 0xef,
+	0x9a, 0xd6, 0xa9, 0x1c, 0xf8, 0xfa, 0x71, 0xc0, 0xb5, 0x38, 0xd8, 0x96, 0x2e, 0x32, 0x1a, 0x39,
+	0x06, 0xed, 0xe0, 0x9a, 0xbe, 0x10, 0x6b, 0x71, 0x4c, 0xb1, 0x21, 0x97, 0x34, 0x77, 0xe5, 0x8b,
+	0x1f, 0x8b, 0xbe, 0xf5, 0xa5, 0x7f, 0x5f, 0x1f, 0xfb, 0x9f, 0x14, 0xdc, 0xe5, 0xa7, 0x69, 0x1f,
+	0x90, 0x03, 0xfa, 0x22, 0x68, 0x3e, 0x7e, 0x33, 0xb3, 0xb3, 0x33, 0xb3, 0x43, 0xd8, 0xf5, 0x22,
+	0xce, 0xfd, 0xf8, 0x30, 0x44, 0xce, 0xa9, 0x87, 0x93, 0x38, 0x89, 0x44, 0x44, 0xba, 0x8a, 0xbb,
+	0x67, 0x06, 0xe8, 0x7a, 0x98, 0x1c, 0x26, 0xdf, 0x73, 0x14, 0xea, 0x57, 0x69, 0x58, 0xff, 0xd4,
+	0xa0, 0x3f, 0x63, 0x77, 0x18, 0x44, 0x31, 0x12, 0x13, 0x7a, 0x31, 0xbd, 0x0f, 0x22, 0xea, 0x9a,
+	0xda, 0xbe, 0xf6, 0x62, 0x60, 0xe7, 0x24, 0xf9, 0x0c, 0x74, 0xee, 0x7b, 0x8c, 0x8a, 0x75, 0x82,
+	0xe6, 0x86, 0x94, 0x95, 0x0c, 0xf2, 0x0d, 0x6c, 0x71, 0x5c, 0x26, 0x28, 0x1c, 0xcc, 0x4c, 0x99,
+	0x9b, 0xfb, 0xda, 0x0b, 0xe3, 0xe8, 0xf9, 0x44, 0x05, 0x30, 0xb9, 0x94, 0xe2, 0xdc, 0x91, 0x3d,
+	0xe2, 0x35, 0xda, 0x9a, 0xc3, 0xa8, 0xae, 0xf1, 0x63, 0x43, 0xb1, 0x8e, 0xa1, 0xab, 0x2c, 0x91,
+	0x97, 0x30, 0xf6, 0x99, 0xc0, 0x84, 0xd1, 0x60, 0xc6, 0xdc, 0x38, 0xf2, 0x99, 0x90, 0xa6, 0xf4,
+	0x79, 0xcb, 0x6e, 0x48, 0x4e, 0x74, 0xe8, 0x2d, 0x23, 0x26, 0x90, 0x09, 0xeb, 0x3f, 0x3a, 0x0c,
+	0x5f, 0xcb, 0xb0, 0x17, 0x2a, 0x99, 0x64, 0x17, 0x3a, 0x2c, 0x62, 0x4b, 0x94, 0xf8, 0xb6, 0xad,
+	0x88, 0x34, 0xc4, 0xe5, 0x8a, 0x32, 0x86, 0x41, 0x16, 0x46, 0x4e, 0x92, 0x03, 0xd8, 0x14, 0xd4,
+	0x93, 0x39, 0x18, 0x1d, 0xfd, 0x34, 0xcf, 0x41, 0xcd, 0xe6, 0xe4, 0x8a, 0x7a, 0x76, 0xaa, 0x45,
+	0xbe, 0x04, 0x9d, 0x06, 0xfe, 0x1d, 0x3a, 0x21, 0xf7, 0xcc, 0x8e, 0x4c, 0xdb, 0x6e, 0x0e, 0x39,
+	0x4e, 0x05, 0x19, 0x62, 0xde, 0xb2, 0xfb, 0x52, 0x71, 0xc1, 0x3d, 0xf2, 0x3b, 0xe8, 0x85, 0x18,
+	0x3a, 0x09, 0xde, 0x9a, 0x5d, 0x09, 0x29, 0xbc, 0x2c, 0x30, 0xbc, 0xc6, 0x84, 0xaf, 0xfc, 0xd8,
+	0xc6, 0xdb, 0x35, 0x72, 0x31, 0x6f, 0xd9, 0xdd, 0x10, 0x43, 0x1b, 0x6f, 0xc9, 0xef, 0x73, 0x14,
+	0x37, 0x7b, 0x12, 0xb5, 0xf7, 0x18, 0x8a, 0xc7, 0x11, 0xe3, 0x58, 0xc0, 0x38, 0x79, 0x05, 0x7d,
+	0x97, 0x0a, 0x2a, 0x03, 0xec, 0x4b, 0xdc, 0x4e, 0x8e, 0x9b, 0x52, 0x41, 0xcb, 0xf8, 0x7a, 0xa9,
+	0x5a, 0x1a, 0xde, 0x01, 0x74, 0x56, 0x18, 0x04, 0x91, 0xa9, 0xd7, 0xd5, 0x55, 0x0a, 0xe6, 0xa9,
+	0x68, 0xde, 0xb2, 0x95, 0x0e, 0x39, 0xcc, 0xcc, 0xbb, 0xbe, 0x67, 0x82, 0xd4, 0x27, 0x55, 0xf3,
+	0x53, 0xdf, 0x53, 0xa7, 0x90, 0xd6, 0xa7, 0xbe, 0x57, 0xc4, 0x93, 0x9e, 0xde, 0x68, 0xc6, 0x53,
+	0x9e, 0x5b, 0x22, 0xd4, 0xc1, 0x0d, 0x89, 0x58, 0xc7, 0x2e, 0x15, 0x68, 0x0e, 0x9a, 0x5e, 0xde,
+	0x49, 0xc9, 0xbc, 0x65, 0x83, 0x5b, 0x50, 0xe4, 0x73, 0xe8, 0x60, 0x18, 0x8b, 0x7b, 0x73, 0x28,
+	0x01, 0xc3, 0x1c, 0x30, 0x4b, 0x99, 0xe9, 0x01, 0xa4, 0x94, 0x1c, 0x40, 0x7b, 0x19, 0x31, 0x66,
+	0x8e, 0xa4, 0xd6, 0xb3, 0x5c, 0xeb, 0x34, 0x62, 0x6c, 0xc6, 0x05, 0xbd, 0x0e, 0x7c, 0xbe, 0x9a,
+	0xb7, 0x6c, 0xa9, 0x44, 0x8e, 0x00, 0xb8, 0xa0, 0x02, 0x1d, 0x9f, 0xdd, 0x44, 0xe6, 0x96, 0x84,
+	0x6c, 0x17, 0x6d, 0x92, 0x4a, 0xce, 0xd8, 0x4d, 0x9a, 0x1d, 0x9d, 0xe7, 0x04, 0x39, 0x81, 0x91,
+	0xc2, 0x70, 0x46, 0x63, 0xbe, 0x8a, 0x84, 0x39, 0xae, 0x5f, 0x7a, 0x81, 0xbb, 0xcc, 0x14, 0xe6,
+	0x2d, 0x7b, 0x28, 0x21, 0x39, 0x83, 0x2c, 0x60, 0xa7, 0xf4, 0xeb, 0xc4, 0xeb, 0x20, 0x90, 0xf9,
+	0xdb, 0x96, 0x86, 0x3e, 0x6b, 0x18, 0xba, 0x58, 0x07, 0x41, 0x99, 0xc8, 0x31, 0x7f, 0xc0, 0x27,
+	0xc7, 0xa0, 0xec, 0x3b, 0x89, 0x52, 0x32, 0x49, 0xbd, 0xa0, 0x6c, 0x0c, 0x23, 0x81, 0xd2, 0x5c,
+	0x69, 0x66, 0xc0, 0x2b, 0x34, 0x99, 0xe6, 0xa7, 0x4a, 0xb2, 0x92, 0x33, 0x77, 0xa4, 0x8d, 0x9f,
+	0x3d, 0x6a, 0xa3, 0xa8, 0xca, 0x21, 0xaf, 0x32, 0xd2, 0xdc, 0x04, 0x48, 0x5d, 0x55, 0xbc, 0xb2,
+	0x44, 0x77, 0xeb, 0xb9, 0x79, 0x53, 0x48, 0xcb, 0x42, 0x1d, 0x96, 0x90, 0xb4, 0x5c, 0xbf, 0x86,
+	0x61, 0x8c, 0x98, 0x38, 0xbe, 0x8b, 0x4c, 0xf8, 0xe2, 0xde, 0x7c, 0x56, 0x6f, 0xc3, 0x0b, 0xc4,
+	0xe4, 0x2c, 0x93, 0xa5, 0xc7, 0x88, 0x2b, 0x34, 0xf9, 0x06, 0x06, 0x71, 0xe2, 0xdf, 0xa5, 0x07,
+	0x49, 0x4b, 0xc7, 0x7c, 0x5e, 0x4f, 0xc4, 0x85, 0x92, 0xd5, 0x1b, 0xc5, 0x88, 0x4b, 0xae, 0xe5,
+	0xc0, 0xe6, 0x15, 0xf5, 0xc8, 0x10, 0xf4, 0x77, 0xe7, 0xd3, 0xd9, 0xb7, 0x67, 0xe7, 0xb3, 0xe9,
+	0xb8, 0x45, 0x74, 0xe8, 0xcc, 0x16, 0x17, 0x57, 0xef, 0xc7, 0x1a, 0x19, 0x40, 0xff, 0xad, 0xfd,
+	0xda, 0x79, 0x7b, 0xfe, 0xe6, 0xfd, 0x78, 0x23, 0xd5, 0x3b, 0x9d, 0x1f, 0x9f, 0x2b, 0x72, 0x93,
+	0x8c, 0x61, 0x20, 0xc9, 0xe3, 0xf3, 0xa9, 0xf3, 0xd6, 0x7e, 0x3d, 0x6e, 0x93, 0x2d, 0x30, 0x94,
+	0x82, 0x2d, 0x19, 0x9d, 0xea, 0x6c, 0xfb, 0xb7, 0x06, 0x7a, 0x71, 0xc7, 0x64, 0x0f, 0xfa, 0x21,
+	0x0a, 0x2a, 0xc3, 0x56, 0x53, 0xb6, 0xa0, 0xc9, 0x04, 0x74, 0xe1, 0x87, 0xc8, 0x05, 0x0d, 0x63,
+	0x39, 0xdf, 0x8c, 0xa3, 0x71, 0x35, 0x1f, 0x57, 0x7e, 0x88, 0x76, 0xa9, 0x42, 0x9e, 0x41, 0x37,
+	0xfe, 0xe0, 0x3b, 0xbe, 0x2b, 0xc7, 0xde, 0xc0, 0xee, 0xc4, 0x1f, 0xfc, 0x33, 0x97, 0xfc, 0x02,
+	0x8c, 0x6c, 0x2a, 0x3a, 0x8b, 0xe3, 0x53, 0xb3, 0x2d, 0x65, 0x90, 0xb1, 0x16, 0xc7, 0xa7, 0xd6,
+	0x31, 0x6c, 0x37, 0xaa, 0x97, 0xbc, 0x84, 0x3e, 0x06, 0x18, 0x22, 0x13, 0xdc, 0xd4, 0xf6, 0x37,
+	0xab, 0xbe, 0x8b, 0x37, 0xa4, 0xd0, 0xb0, 0xfe, 0x08, 0xbb, 0x8f, 0xd5, 0xed, 0x43, 0xdf, 0x5a,
+	0xc3, 0xf7, 0x0d, 0x0c, 0x6b, 0x4d, 0x5a, 0x39, 0x84, 0x56, 0x3d, 0xc4, 0x1e, 0xf4, 0x8b, 0xd2,
+	0x50, 0xa3, 0xbe, 0xa0, 0x89, 0x05, 0x43, 0x11, 0x70, 0x67, 0x89, 0x89, 0x70, 0x56, 0x94, 0xaf,
+	0xb2, 0xe3, 0x1b, 0x22, 0xe0, 0xa7, 0x98, 0x88, 0x39, 0xe5, 0x2b, 0xeb, 0x1d, 0x0c, 0xaa, 0x25,
+	0xf4, 0x94, 0x1b, 0x02, 0xed, 0xd4, 0x4c, 0xe6, 0x42, 0xfe, 0xaf, 0x5d, 0xd1, 0x66, 0xfd, 0x8a,
+	0xac, 0x10, 0x8c, 0xca, 0xbc, 0x7b, 0xfa, 0x95, 0x72, 0xe5, 0x04, 0xe5, 0xe6, 0xc6, 0xfe, 0xe6,
+	0x0b, 0xdd, 0xce, 0x49, 0x32, 0x81, 0x7e, 0xc8, 0x3d, 0x47, 0xdc, 0x67, 0xcf, 0xf5, 0xa8, 0x1c,
+	0xa3, 0x69, 0x16, 0x17, 0xdc, 0xbb, 0xba, 0x8f, 0xd1, 0xee, 0x85, 0xea, 0x8f, 0x15, 0x81, 0x51,
+	0x99, 0xdf, 0x4f, 0xb8, 0xab, 0xc6, 0xbb, 0xd1, 0x28, 0xa9, 0x4f, 0x73, 0xf8, 0x11, 0xa0, 0x1c,
+	0xcd, 0x4f, 0xf8, 0xfb, 0x25, 0xb4, 0x33, 0x5f, 0x8f, 0x57, 0x49, 0xfb, 0x47, 0x79, 0x0e, 0x00,
+	0xca, 0xa7, 0xe7, 0xff, 0x9e, 0xd8, 0xaf, 0xc0, 0xa8, 0x8c, 0x07, 0xf2, 0xeb, 0xfa, 0xea, 0x63,
+	0x1c, 0x6d, 0x15, 0x68, 0xc5, 0x2e, 0x76, 0x21, 0xeb, 0x0f, 0xd0, 0xcb, 0x78, 0xe4, 0x27, 0xd0,
+	0xe3, 0x78, 0xeb, 0xb0, 0x75, 0x98, 0x85, 0xd9, 0xe5, 0x78, 0x7b, 0xbe, 0x0e, 0xd3, 0xaa, 0xaa,
+	0xdc, 0x86, 0xfc, 0x6f, 0xfd, 0x4b, 0x83, 0x41, 0x75, 0xb7, 0x20, 0x13, 0x80, 0xb0, 0x58, 0x01,
+	0x32, 0xb7, 0xa3, 0xfa, 0x72, 0x60, 0x57, 0x34, 0x3e, 0x79, 0x3a, 0x54, 0x3b, 0xa8, 0x5d, 0xef,
+	0x20, 0xeb, 0x1f, 0x1a, 0x6c, 0x37, 0x86, 0xf4, 0x53, 0x3d, 0xf2, 0xa9, 0x8e, 0x3f, 0x87, 0x91,
+	0xcf, 0x1d, 0x17, 0x97, 0x01, 0x4d, 0xa8, 0xf0, 0x23, 0x26, 0x6f, 0xa4, 0x6f, 0x0f, 0x7d, 0x3e,
+	0x2d, 0x99, 0xd6, 0x9f, 0xa0, 0x9f, 0xa3, 0xd3, 0x4c, 0xfa, 0x6c, 0x59, 0xcd, 0xa4, 0xcf, 0x96,
+	0x69, 0x26, 0x2b, 0x29, 0xde, 0xa8, 0xa6, 0xd8, 0xba, 0x81, 0xed, 0xc6, 0xda, 0x45, 0xbe, 0x86,
+	0x31, 0xc7, 0xe0, 0x46, 0xbe, 0xb7, 0x49, 0xa8, 0x7c, 0x6b, 0xfb, 0xda, 0xa3, 0x55, 0xba, 0x95,
+	0x6a, 0x9e, 0x95, 0x8a, 0x69, 0xc9, 0x7d, 0x60, 0xd1, 0xf7, 0x4c, 0x96, 0xd6, 0xc0, 0x56, 0x84,
+	0x75, 0x0d, 0xa4, 0xb9, 0xa8, 0x91, 0x2f, 0xa0, 0x23, 0xf7, 0xc2, 0x27, 0x27, 0xa5, 0x12, 0xcb,
+	0x56, 0x41, 0xea, 0xfe, 0x40, 0xab, 0x20, 0x75, 0xad, 0xef, 0xa0, 0xab, 0x7c, 0xa4, 0x77, 0x86,
+	0xb5, 0xc5, 0xd9, 0x2e, 0xe8, 0x1f, 0x6c, 0xf3, 0xc7, 0x5f, 0x02, 0xab, 0x07, 0x1d, 0xb9, 0x37,
+	0x59, 0x7f, 0x05, 0xd2, 0xdc, 0x0e, 0xd2, 0x39, 0xca, 0x05, 0x4d, 0x84, 0x53, 0xaf, 0x62, 0x43,
+	0x32, 0x2f, 0x55, 0x29, 0xff, 0x1c, 0x0c, 0x64, 0xae, 0x53, 0xbf, 0x04, 0x1d, 0x99, 0xab, 0xe4,
+	0xd6, 0x09, 0xec, 0x3c, 0xb2, 0x33, 0x90, 0x03, 0xe8, 0x67, 0x0d, 0x93, 0xbf, 0x26, 0x8d, 0x8e,
+	0x2a, 0x14, 0xac, 0x6f, 0x81, 0x34, 0x9f, 0x6c, 0xf2, 0xea, 0x61, 0x4f, 0x3e, 0x7f, 0xf0, 0xbe,
+	0x37, 0x5a, 0xf3, 0xbf, 0x1a, 0x8c, 0xea, 0x32, 0xf2, 0x2b, 0xd8, 0x5a, 0x46, 0x41, 0x80, 0xcb,
+	0xf4, 0x8a, 0x1d, 0x46, 0x43, 0xcc, 0xf2, 0x3a, 0x2a, 0xd9, 0xe7, 0x34, 0xc4, 0xf4, 0x13, 0x27,
+	0x95, 0xf2, 0x98, 0x2e, 0xd5, 0x27, 0x8e, 0x6e, 0x97, 0x0c, 0xb2, 0x03, 0x1d, 0xf1, 0x31, 0x4f,
+	0xaf, 0x6e, 0xb7, 0xc5, 0xc7, 0x33, 0x97, 0xcc, 0x60, 0x98, 0x6f, 0x21, 0xf2, 0xf3, 0x4e, 0x76,
+	0x99, 0x71, 0xb4, 0x3f, 0x91, 0xd4, 0xe4, 0xb4, 0x70, 0x70, 0x71, 0x27, 0x6c, 0xa4, 0xee, 0x77,
+	0x89, 0x2f, 0xf0, 0x12, 0x85, 0x9d, 0x2f, 0x2f, 0x76, 0xaa, 0x47, 0x5e, 0x02, 0xc9, 0xcd, 0x70,
+	0x3f, 0x74, 0x56, 0xe8, 0x7b, 0x2b, 0x21, 0xbf, 0x4a, 0xda, 0xf6, 0x38, 0x93, 0x5c, 0xfa, 0xe1,
+	0x5c, 0xf2, 0x7f, 0xf3, 0x67, 0x30, 0x2a, 0x03, 0xed, 0xe1, 0x06, 0x33, 0x04, 0xfd, 0xe4, 0xcd,
+	0xdb, 0xd3, 0xbf, 0x38, 0x8b, 0xcb, 0xd7, 0x63, 0x2d, 0x5d, 0x54, 0xce, 0xa6, 0xb3, 0xf3, 0xab,
+	0xb3, 0xab, 0xf7, 0x92, 0xb3, 0x71, 0xf4, 0x77, 0xe8, 0xaa, 0x07, 0x85, 0x7c, 0x05, 0x03, 0xf5,
+	0xef, 0x52, 0x24, 0x48, 0x43, 0xd2, 0x28, 0xce, 0xbd, 0x06, 0xc7, 0x6a, 0xbd, 0xd0, 0x5e, 0x69,
+	0xe4, 0x0b, 0x68, 0x5f, 0xf8, 0xcc, 0x23, 0xf5, 0xdd, 0x7c, 0xaf, 0x4e, 0x5a, 0xad, 0x93, 0xdf,
+	0xfe, 0xed, 0xc0, 0xf3, 0xc5, 0x6a, 0x7d, 0x3d, 0x59, 0x46, 0xe1, 0xe1, 0xea, 0x3e, 0xc6, 0x24,
+	0xfb, 0x26, 0xbe, 0xa1, 0xd7, 0x89, 0xbf, 0x3c, 0x94, 0x9f, 0xc3, 0xfc, 0x50, 0xc1, 0xae, 0xbb,
+	0x92, 0xfc, 0xf2, 0x7f, 0x03, 0x00, 0xbd, 0xb0, 0xf3, 0x2f, 0x57, 0x0f, 0x00, 0x00,
 }
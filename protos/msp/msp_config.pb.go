@@ -91,6 +91,9 @@ type FabricMSPConfig struct {
 	// List of TLS intermediate certificates trusted by this MSP;
 	// They are returned by GetTLSIntermediateCerts.
 	TlsIntermediateCerts [][]byte `protobuf:"bytes,10,rep,name=tls_intermediate_certs,json=tlsIntermediateCerts,proto3" json:"tls_intermediate_certs,omitempty"`
+	// FabricNodeOUs contains the configuration to distinguish clients, peers
+	// and orderers based on the OUs of their certificates
+	FabricNodeOus *FabricNodeOUs `protobuf:"bytes,11,opt,name=fabric_node_ous,json=fabricNodeOus" json:"fabric_node_ous,omitempty"`
 }
 
 func (m *FabricMSPConfig) Reset()                    { *m = FabricMSPConfig{} }
@@ -168,6 +171,13 @@ func (m *FabricMSPConfig) GetTlsIntermediateCerts() [][]byte {
 	return nil
 }
 
+func (m *FabricMSPConfig) GetFabricNodeOus() *FabricNodeOUs {
+	if m != nil {
+		return m.FabricNodeOus
+	}
+	return nil
+}
+
 // FabricCryptoConfig contains configuration parameters
 // for the cryptographic algorithms used by the MSP
 // this configuration refers to
@@ -301,6 +311,155 @@ func (m *FabricOUIdentifier) GetOrganizationalUnitIdentifier() string {
 	return ""
 }
 
+// FabricNodeOUs contains configuration to tell apart clients, peers, admins
+// and orderers based on the OUs. If the NodeOUs recognition is enabled,
+// every identity of the MSP must carry exactly one of the client, peer,
+// admin or orderer OUs.
+type FabricNodeOUs struct {
+	// If true then an msp identity that does not contain exactly one of the
+	// node OUs is considered invalid
+	Enable bool `protobuf:"varint,1,opt,name=enable" json:"enable,omitempty"`
+	// OU Identifier of the clients
+	ClientOuIdentifier *FabricOUIdentifier `protobuf:"bytes,2,opt,name=client_ou_identifier,json=clientOuIdentifier" json:"client_ou_identifier,omitempty"`
+	// OU Identifier of the peers
+	PeerOuIdentifier *FabricOUIdentifier `protobuf:"bytes,3,opt,name=peer_ou_identifier,json=peerOuIdentifier" json:"peer_ou_identifier,omitempty"`
+	// OU Identifier of the admins
+	AdminOuIdentifier *FabricOUIdentifier `protobuf:"bytes,4,opt,name=admin_ou_identifier,json=adminOuIdentifier" json:"admin_ou_identifier,omitempty"`
+	// OU Identifier of the orderers
+	OrdererOuIdentifier *FabricOUIdentifier `protobuf:"bytes,5,opt,name=orderer_ou_identifier,json=ordererOuIdentifier" json:"orderer_ou_identifier,omitempty"`
+}
+
+func (m *FabricNodeOUs) Reset()                    { *m = FabricNodeOUs{} }
+func (m *FabricNodeOUs) String() string            { return proto.CompactTextString(m) }
+func (*FabricNodeOUs) ProtoMessage()               {}
+func (*FabricNodeOUs) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{6} }
+
+func (m *FabricNodeOUs) GetEnable() bool {
+	if m != nil {
+		return m.Enable
+	}
+	return false
+}
+
+func (m *FabricNodeOUs) GetClientOuIdentifier() *FabricOUIdentifier {
+	if m != nil {
+		return m.ClientOuIdentifier
+	}
+	return nil
+}
+
+func (m *FabricNodeOUs) GetPeerOuIdentifier() *FabricOUIdentifier {
+	if m != nil {
+		return m.PeerOuIdentifier
+	}
+	return nil
+}
+
+func (m *FabricNodeOUs) GetAdminOuIdentifier() *FabricOUIdentifier {
+	if m != nil {
+		return m.AdminOuIdentifier
+	}
+	return nil
+}
+
+func (m *FabricNodeOUs) GetOrdererOuIdentifier() *FabricOUIdentifier {
+	if m != nil {
+		return m.OrdererOuIdentifier
+	}
+	return nil
+}
+
+// IdemixMSPConfig collects all the configuration information for
+// an Idemix MSP.
+type IdemixMSPConfig struct {
+	// Name holds the identifier of the MSP
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// ipk represents the (serialized) issuer public key
+	Ipk []byte `protobuf:"bytes,2,opt,name=ipk,proto3" json:"ipk,omitempty"`
+	// signer may contain crypto material to configure a default signer
+	Signer *IdemixMSPSignerConfig `protobuf:"bytes,3,opt,name=signer" json:"signer,omitempty"`
+}
+
+func (m *IdemixMSPConfig) Reset()                    { *m = IdemixMSPConfig{} }
+func (m *IdemixMSPConfig) String() string            { return proto.CompactTextString(m) }
+func (*IdemixMSPConfig) ProtoMessage()               {}
+func (*IdemixMSPConfig) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{7} }
+
+func (m *IdemixMSPConfig) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *IdemixMSPConfig) GetIpk() []byte {
+	if m != nil {
+		return m.Ipk
+	}
+	return nil
+}
+
+func (m *IdemixMSPConfig) GetSigner() *IdemixMSPSignerConfig {
+	if m != nil {
+		return m.Signer
+	}
+	return nil
+}
+
+// IdemixMSPSIgnerConfig contains the crypto material to set up an idemix signing identity
+type IdemixMSPSignerConfig struct {
+	// cred represents the serialized idemix credential of the default signer
+	Cred []byte `protobuf:"bytes,1,opt,name=cred,proto3" json:"cred,omitempty"`
+	// sk is the secret key of the default signer, corresponding to credential Cred
+	Sk []byte `protobuf:"bytes,2,opt,name=sk,proto3" json:"sk,omitempty"`
+	// organizational_unit_identifier defines the organizational unit the default signer is in
+	OrganizationalUnitIdentifier string `protobuf:"bytes,3,opt,name=organizational_unit_identifier,json=organizationalUnitIdentifier" json:"organizational_unit_identifier,omitempty"`
+	// role defines the MSP role of the default signer (e.g., ADMIN or MEMBER)
+	Role int32 `protobuf:"varint,4,opt,name=role" json:"role,omitempty"`
+	// enrollment_id contains the enrollment id of this signer
+	EnrollmentId string `protobuf:"bytes,5,opt,name=enrollment_id,json=enrollmentId" json:"enrollment_id,omitempty"`
+}
+
+func (m *IdemixMSPSignerConfig) Reset()                    { *m = IdemixMSPSignerConfig{} }
+func (m *IdemixMSPSignerConfig) String() string            { return proto.CompactTextString(m) }
+func (*IdemixMSPSignerConfig) ProtoMessage()               {}
+func (*IdemixMSPSignerConfig) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{8} }
+
+func (m *IdemixMSPSignerConfig) GetCred() []byte {
+	if m != nil {
+		return m.Cred
+	}
+	return nil
+}
+
+func (m *IdemixMSPSignerConfig) GetSk() []byte {
+	if m != nil {
+		return m.Sk
+	}
+	return nil
+}
+
+func (m *IdemixMSPSignerConfig) GetOrganizationalUnitIdentifier() string {
+	if m != nil {
+		return m.OrganizationalUnitIdentifier
+	}
+	return ""
+}
+
+func (m *IdemixMSPSignerConfig) GetRole() int32 {
+	if m != nil {
+		return m.Role
+	}
+	return 0
+}
+
+func (m *IdemixMSPSignerConfig) GetEnrollmentId() string {
+	if m != nil {
+		return m.EnrollmentId
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*MSPConfig)(nil), "msp.MSPConfig")
 	proto.RegisterType((*FabricMSPConfig)(nil), "msp.FabricMSPConfig")
@@ -308,48 +467,64 @@ func init() {
 	proto.RegisterType((*SigningIdentityInfo)(nil), "msp.SigningIdentityInfo")
 	proto.RegisterType((*KeyInfo)(nil), "msp.KeyInfo")
 	proto.RegisterType((*FabricOUIdentifier)(nil), "msp.FabricOUIdentifier")
+	proto.RegisterType((*FabricNodeOUs)(nil), "msp.FabricNodeOUs")
+	proto.RegisterType((*IdemixMSPConfig)(nil), "msp.IdemixMSPConfig")
+	proto.RegisterType((*IdemixMSPSignerConfig)(nil), "msp.IdemixMSPSignerConfig")
 }
 
 func init() { proto.RegisterFile("msp/msp_config.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 599 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x54, 0xdf, 0x6e, 0xd3, 0x3c,
-	0x14, 0x57, 0xd7, 0xad, 0xfb, 0x7a, 0x9a, 0x76, 0xfb, 0xbc, 0x31, 0x72, 0xc1, 0x46, 0x16, 0x40,
-	0xe4, 0x86, 0x54, 0xda, 0x90, 0xb8, 0xe1, 0x6a, 0x45, 0x13, 0x15, 0x54, 0x4c, 0xa9, 0x76, 0xc3,
-	0x4d, 0xe4, 0xa6, 0x4e, 0x6a, 0x35, 0xb1, 0x23, 0xdb, 0x9d, 0x14, 0xc4, 0x5b, 0xf0, 0xaa, 0x3c,
-	0x00, 0x8a, 0xed, 0xb6, 0x29, 0x4c, 0xbb, 0xb3, 0x7f, 0x7f, 0x4e, 0x8f, 0x7f, 0xe7, 0x34, 0x70,
-	0x5a, 0xc8, 0x72, 0x58, 0xc8, 0x32, 0x4e, 0x38, 0x4b, 0x69, 0x16, 0x96, 0x82, 0x2b, 0x8e, 0xda,
-	0x85, 0x2c, 0xfd, 0x0f, 0xd0, 0x9d, 0x4c, 0xef, 0x46, 0x1a, 0x47, 0x08, 0xf6, 0x55, 0x55, 0x12,
-	0xb7, 0xe5, 0xb5, 0x82, 0x83, 0x48, 0x9f, 0xd1, 0x19, 0x74, 0x8c, 0xcb, 0xdd, 0xf3, 0x5a, 0x81,
-	0x13, 0xd9, 0x9b, 0xff, 0xbb, 0x0d, 0x47, 0xb7, 0x78, 0x26, 0x68, 0xb2, 0xe3, 0x67, 0xb8, 0x30,
-	0xfe, 0x6e, 0xa4, 0xcf, 0xe8, 0x1c, 0x40, 0x70, 0xae, 0xe2, 0x84, 0x08, 0x25, 0xdd, 0x3d, 0xaf,
-	0x1d, 0x38, 0x51, 0xb7, 0x46, 0x46, 0x35, 0x80, 0xde, 0x01, 0xa2, 0x4c, 0x11, 0x51, 0x90, 0x39,
-	0xc5, 0x8a, 0x58, 0x59, 0x5b, 0xcb, 0xfe, 0x6f, 0x32, 0x46, 0x7e, 0x06, 0x1d, 0x3c, 0x2f, 0x28,
-	0x93, 0xee, 0xbe, 0x96, 0xd8, 0x1b, 0x7a, 0x0b, 0x47, 0x82, 0x3c, 0xf0, 0x04, 0x2b, 0xca, 0x59,
-	0x9c, 0x53, 0xa9, 0xdc, 0x03, 0x2d, 0x18, 0x6c, 0xe1, 0xaf, 0x54, 0x2a, 0x34, 0x82, 0x63, 0x49,
-	0x33, 0x46, 0x59, 0x16, 0xd3, 0x39, 0x61, 0x8a, 0xaa, 0xca, 0xed, 0x78, 0xad, 0xa0, 0x77, 0xe5,
-	0x86, 0x85, 0x2c, 0xc3, 0xa9, 0x21, 0xc7, 0x96, 0x1b, 0xb3, 0x94, 0x47, 0x47, 0x72, 0x17, 0x44,
-	0x31, 0xbc, 0xe4, 0x22, 0xc3, 0x8c, 0xfe, 0xd0, 0x85, 0x71, 0x1e, 0xaf, 0x18, 0x55, 0xb6, 0x60,
-	0x4a, 0x89, 0x90, 0xee, 0xa1, 0xd7, 0x0e, 0x7a, 0x57, 0xcf, 0x75, 0x4d, 0x13, 0xd3, 0xb7, 0xfb,
-	0xf1, 0x86, 0x8f, 0xce, 0x77, 0xfd, 0xf7, 0x8c, 0xaa, 0x2d, 0x2b, 0xd1, 0x47, 0xe8, 0x27, 0xa2,
-	0x2a, 0x15, 0xb7, 0x13, 0x73, 0xff, 0xd3, 0x2d, 0x36, 0xcb, 0x8d, 0x34, 0x6f, 0x82, 0x8f, 0x9c,
-	0xa4, 0x71, 0x43, 0xaf, 0x61, 0xa0, 0x72, 0x19, 0x37, 0x62, 0xef, 0xea, 0x2c, 0x1c, 0x95, 0xcb,
-	0x68, 0x93, 0xfc, 0x7b, 0x38, 0xab, 0x55, 0x8f, 0xa4, 0x0f, 0x5a, 0x7d, 0xaa, 0x72, 0x39, 0xfe,
-	0x7b, 0x00, 0xfe, 0xaf, 0x16, 0xa0, 0x7f, 0x1b, 0x40, 0x57, 0xf0, 0xac, 0x0e, 0x09, 0xab, 0x95,
-	0x20, 0xf1, 0x02, 0xcb, 0x45, 0x9c, 0xe2, 0x82, 0xe6, 0x95, 0x5d, 0x85, 0x93, 0x0d, 0xf9, 0x19,
-	0xcb, 0xc5, 0xad, 0xa6, 0xd0, 0x18, 0x2e, 0xd7, 0x23, 0x68, 0x44, 0x67, 0xdd, 0x2b, 0x96, 0xd4,
-	0xd1, 0xe8, 0xa5, 0xeb, 0x46, 0x17, 0x6b, 0xe1, 0x36, 0x24, 0x5d, 0xc8, 0xaa, 0x7c, 0x0e, 0x27,
-	0x8f, 0x0c, 0x0e, 0xbd, 0x82, 0x7e, 0xb9, 0x9a, 0xe5, 0x34, 0x89, 0xeb, 0xdf, 0x27, 0x42, 0x77,
-	0xe3, 0x44, 0x8e, 0x01, 0xa7, 0x1a, 0x43, 0xd7, 0x30, 0x28, 0x05, 0x7d, 0xa8, 0x9f, 0x6f, 0x55,
-	0x7b, 0x3a, 0x6c, 0x47, 0x87, 0xfd, 0x85, 0x98, 0x1d, 0xe8, 0x5b, 0x8d, 0x31, 0xf9, 0x53, 0x38,
-	0xb4, 0x0c, 0x7a, 0x03, 0x83, 0x25, 0x69, 0xbe, 0xc0, 0xbe, 0xb9, 0xbf, 0x24, 0x8d, 0x76, 0xd1,
-	0x25, 0x38, 0xb5, 0xac, 0xc0, 0x8a, 0x08, 0x8a, 0x73, 0xfb, 0x6f, 0xea, 0x2d, 0x49, 0x35, 0xb1,
-	0x90, 0xff, 0x73, 0x1d, 0x6d, 0x73, 0x55, 0x90, 0x07, 0xbd, 0x7a, 0x2c, 0x34, 0xa5, 0x09, 0x56,
-	0xc4, 0x3e, 0xa1, 0x09, 0xa1, 0x4f, 0x70, 0xf1, 0xf4, 0x3a, 0xda, 0x14, 0x5f, 0x3c, 0xb5, 0x74,
-	0x37, 0x31, 0x5c, 0x72, 0x91, 0x85, 0x8b, 0xaa, 0x24, 0x22, 0x27, 0xf3, 0x8c, 0x88, 0x30, 0xd5,
-	0xdd, 0x98, 0xcf, 0x85, 0xac, 0xe3, 0xb8, 0x39, 0x9e, 0xc8, 0xd2, 0x8c, 0xfc, 0x0e, 0x27, 0x4b,
-	0x9c, 0x91, 0xef, 0x41, 0x46, 0xd5, 0x62, 0x35, 0x0b, 0x13, 0x5e, 0x0c, 0x1b, 0xde, 0xa1, 0xf1,
-	0x0e, 0x8d, 0xb7, 0xfe, 0xf8, 0xcc, 0x3a, 0xfa, 0x7c, 0xfd, 0x27, 0x00, 0x00, 0xff, 0xff, 0x2e,
-	0xb1, 0x83, 0xaa, 0x8e, 0x04, 0x00, 0x00,
+	// 813 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x55, 0xdd, 0x8e, 0xe3, 0x34,
+	0x14, 0x56, 0xdb, 0x99, 0xee, 0xf6, 0x34, 0x6d, 0x67, 0x3d, 0x3f, 0x44, 0x88, 0x5d, 0x3a, 0x01,
+	0x44, 0x6f, 0x68, 0xa5, 0x2e, 0x12, 0x12, 0xe2, 0x6a, 0x0b, 0x0b, 0xd1, 0x32, 0xcc, 0x2a, 0xd5,
+	0xdc, 0x70, 0x13, 0xb9, 0x89, 0x9b, 0x5a, 0x4d, 0xec, 0xc8, 0x76, 0x56, 0x14, 0xf1, 0x16, 0xbc,
+	0x08, 0xd7, 0xbc, 0x04, 0xaf, 0x84, 0xfc, 0x33, 0x6d, 0xda, 0x8e, 0x0a, 0x77, 0xf6, 0x39, 0xdf,
+	0xf9, 0x8e, 0xfd, 0x9d, 0x2f, 0x0e, 0x5c, 0x15, 0xb2, 0x9c, 0x14, 0xb2, 0x8c, 0x13, 0xce, 0x96,
+	0x34, 0x1b, 0x97, 0x82, 0x2b, 0x8e, 0x5a, 0x85, 0x2c, 0x83, 0x6f, 0xa0, 0x73, 0x37, 0x7f, 0x3f,
+	0x33, 0x71, 0x84, 0xe0, 0x4c, 0x6d, 0x4a, 0xe2, 0x37, 0x86, 0x8d, 0xd1, 0x79, 0x64, 0xd6, 0xe8,
+	0x06, 0xda, 0xb6, 0xca, 0x6f, 0x0e, 0x1b, 0x23, 0x2f, 0x72, 0xbb, 0xe0, 0xaf, 0x33, 0x18, 0xbc,
+	0xc5, 0x0b, 0x41, 0x93, 0xbd, 0x7a, 0x86, 0x0b, 0x5b, 0xdf, 0x89, 0xcc, 0x1a, 0xbd, 0x04, 0x10,
+	0x9c, 0xab, 0x38, 0x21, 0x42, 0x49, 0xbf, 0x39, 0x6c, 0x8d, 0xbc, 0xa8, 0xa3, 0x23, 0x33, 0x1d,
+	0x40, 0x5f, 0x01, 0xa2, 0x4c, 0x11, 0x51, 0x90, 0x94, 0x62, 0x45, 0x1c, 0xac, 0x65, 0x60, 0x2f,
+	0xea, 0x19, 0x0b, 0xbf, 0x81, 0x36, 0x4e, 0x0b, 0xca, 0xa4, 0x7f, 0x66, 0x20, 0x6e, 0x87, 0xbe,
+	0x84, 0x81, 0x20, 0x1f, 0x78, 0x82, 0x15, 0xe5, 0x2c, 0xce, 0xa9, 0x54, 0xfe, 0xb9, 0x01, 0xf4,
+	0x77, 0xe1, 0x9f, 0xa9, 0x54, 0x68, 0x06, 0x17, 0x92, 0x66, 0x8c, 0xb2, 0x2c, 0xa6, 0x29, 0x61,
+	0x8a, 0xaa, 0x8d, 0xdf, 0x1e, 0x36, 0x46, 0xdd, 0xa9, 0x3f, 0x2e, 0x64, 0x39, 0x9e, 0xdb, 0x64,
+	0xe8, 0x72, 0x21, 0x5b, 0xf2, 0x68, 0x20, 0xf7, 0x83, 0x28, 0x86, 0x4f, 0xb9, 0xc8, 0x30, 0xa3,
+	0xbf, 0x1b, 0x62, 0x9c, 0xc7, 0x15, 0xa3, 0xca, 0x11, 0x2e, 0x29, 0x11, 0xd2, 0x7f, 0x36, 0x6c,
+	0x8d, 0xba, 0xd3, 0x8f, 0x0c, 0xa7, 0x95, 0xe9, 0xfe, 0x21, 0xdc, 0xe6, 0xa3, 0x97, 0xfb, 0xf5,
+	0x0f, 0x8c, 0xaa, 0x5d, 0x56, 0xa2, 0xef, 0xa0, 0x97, 0x88, 0x4d, 0xa9, 0xb8, 0x9b, 0x98, 0xff,
+	0x7c, 0xd8, 0x38, 0xa0, 0x9b, 0x99, 0xbc, 0x15, 0x3e, 0xf2, 0x92, 0xda, 0x0e, 0x7d, 0x0e, 0x7d,
+	0x95, 0xcb, 0xb8, 0x26, 0x7b, 0xc7, 0x68, 0xe1, 0xa9, 0x5c, 0x46, 0x5b, 0xe5, 0xbf, 0x86, 0x1b,
+	0x8d, 0x7a, 0x42, 0x7d, 0x30, 0xe8, 0x2b, 0x95, 0xcb, 0xf0, 0x68, 0x00, 0xdf, 0xc2, 0x60, 0x69,
+	0xfa, 0xc7, 0x8c, 0xa7, 0x24, 0xe6, 0x95, 0xf4, 0xbb, 0xe6, 0x6c, 0xa8, 0x76, 0xb6, 0x5f, 0x78,
+	0x4a, 0xee, 0x1f, 0x64, 0xd4, 0x5b, 0xee, 0xb6, 0x95, 0x0c, 0xfe, 0x6c, 0x00, 0x3a, 0x3e, 0x3c,
+	0x9a, 0xc2, 0xb5, 0x16, 0x18, 0xab, 0x4a, 0x90, 0x78, 0x85, 0xe5, 0x2a, 0x5e, 0xe2, 0x82, 0xe6,
+	0x1b, 0x67, 0xa3, 0xcb, 0x6d, 0xf2, 0x27, 0x2c, 0x57, 0x6f, 0x4d, 0x0a, 0x85, 0x70, 0xfb, 0x38,
+	0xbe, 0x9a, 0xec, 0xae, 0xba, 0x62, 0x89, 0x96, 0xd5, 0x18, 0xb6, 0x13, 0xbd, 0x7a, 0x04, 0xee,
+	0x04, 0x36, 0x44, 0x0e, 0x15, 0x70, 0xb8, 0x7c, 0x62, 0xe8, 0xe8, 0x33, 0xe8, 0x95, 0xd5, 0x22,
+	0xa7, 0x49, 0xac, 0xfb, 0x13, 0x61, 0x4e, 0xe3, 0x45, 0x9e, 0x0d, 0xce, 0x4d, 0x0c, 0xbd, 0x86,
+	0x7e, 0x29, 0xe8, 0x07, 0x2d, 0x9d, 0x43, 0x35, 0x8d, 0x18, 0x9e, 0x11, 0xe3, 0x1d, 0xb1, 0xfe,
+	0xe9, 0x39, 0x8c, 0x2d, 0x0a, 0xe6, 0xf0, 0xcc, 0x65, 0xd0, 0x17, 0xd0, 0x5f, 0x93, 0xfa, 0x0d,
+	0xdc, 0x9d, 0x7b, 0x6b, 0x52, 0x3b, 0x2e, 0xba, 0x05, 0x4f, 0xc3, 0x0a, 0xac, 0x88, 0xa0, 0x38,
+	0x77, 0x5f, 0x62, 0x77, 0x4d, 0x36, 0x77, 0x2e, 0x14, 0xfc, 0x01, 0xe8, 0xd8, 0x66, 0x68, 0x08,
+	0x5d, 0x3d, 0x52, 0xba, 0xa4, 0x09, 0x56, 0xc4, 0x5d, 0xa1, 0x1e, 0x42, 0xdf, 0xc3, 0xab, 0xd3,
+	0x56, 0x76, 0x2a, 0x7e, 0x72, 0xca, 0xb0, 0xc1, 0x3f, 0x4d, 0xe8, 0xed, 0x8d, 0x5e, 0x7f, 0xa8,
+	0x84, 0xe1, 0x45, 0x6e, 0x9b, 0x3e, 0x8f, 0xdc, 0x0e, 0x85, 0x70, 0x95, 0xe4, 0x94, 0x30, 0x15,
+	0xf3, 0xea, 0xb0, 0xcb, 0x89, 0xef, 0x05, 0xd9, 0xa2, 0xfb, 0xaa, 0x76, 0xb9, 0x1f, 0x00, 0x95,
+	0x84, 0x88, 0x03, 0xa2, 0xd6, 0x69, 0xa2, 0x0b, 0x5d, 0xb2, 0x47, 0xf3, 0x23, 0x5c, 0x9a, 0x47,
+	0xe4, 0x80, 0xe7, 0xec, 0x34, 0xcf, 0x0b, 0x53, 0xb3, 0x47, 0xf4, 0x0e, 0xae, 0xb9, 0x48, 0x89,
+	0x38, 0x3a, 0xd2, 0xf9, 0x69, 0xaa, 0x4b, 0x57, 0x55, 0x27, 0x0b, 0xd6, 0x30, 0x08, 0x53, 0x52,
+	0xd0, 0xdf, 0x4e, 0xbf, 0xae, 0x17, 0xd0, 0xa2, 0xe5, 0xda, 0x19, 0x42, 0x2f, 0xd1, 0x14, 0xda,
+	0xce, 0x8a, 0x56, 0x89, 0x8f, 0x4d, 0xdb, 0x2d, 0x97, 0xf5, 0xa0, 0x7b, 0x36, 0x1c, 0x32, 0xf8,
+	0xbb, 0x01, 0xd7, 0x4f, 0x22, 0x74, 0xcf, 0x44, 0x90, 0xd4, 0x39, 0xc7, 0xac, 0x51, 0x1f, 0x9a,
+	0xf2, 0xb1, 0x65, 0x53, 0xae, 0xff, 0x87, 0x85, 0x5a, 0xff, 0x6d, 0x21, 0xdd, 0x49, 0xf0, 0x9c,
+	0x18, 0xdd, 0xcf, 0x23, 0xb3, 0xd6, 0xdf, 0x20, 0x61, 0x82, 0xe7, 0x79, 0xa1, 0x0d, 0x43, 0x53,
+	0xa3, 0x64, 0x27, 0xf2, 0x76, 0xc1, 0x30, 0x7d, 0x13, 0xc3, 0x2d, 0x17, 0xd9, 0x78, 0xb5, 0x29,
+	0x89, 0xc8, 0x49, 0x9a, 0x11, 0x31, 0xb6, 0xcf, 0x8e, 0xfd, 0xcd, 0x49, 0x7d, 0xff, 0x37, 0x17,
+	0x77, 0xb2, 0xb4, 0x57, 0x7a, 0x8f, 0x93, 0x35, 0xce, 0xc8, 0xaf, 0xa3, 0x8c, 0xaa, 0x55, 0xb5,
+	0x18, 0x27, 0xbc, 0x98, 0xd4, 0x6a, 0x27, 0xb6, 0x76, 0x62, 0x6b, 0xf5, 0x4f, 0x73, 0xd1, 0x36,
+	0xeb, 0xd7, 0xff, 0x0e, 0x00, 0xdb, 0x74, 0x42, 0xbb, 0x46, 0x07, 0x00, 0x00,
 }
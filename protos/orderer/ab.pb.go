@@ -8,6 +8,7 @@ It is generated from these files:
 	orderer/ab.proto
 	orderer/configuration.proto
 	orderer/kafka.proto
+	orderer/raft.proto
 
 It has these top-level messages:
 	BroadcastResponse
@@ -27,6 +28,19 @@ It has these top-level messages:
 	KafkaMessageTimeToCut
 	KafkaMessageConnect
 	KafkaMetadata
+	RaftProposal
+	RaftProposalRegular
+	RaftProposalTimeToCut
+	RaftMetadata
+	RaftLogEntry
+	RaftHardState
+	RaftSnapshot
+	RaftMessage
+	StepResponse
+	SubmitRequest
+	SubmitResponse
+	PullRequest
+	PullResponse
 */
 package orderer
 
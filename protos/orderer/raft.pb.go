@@ -0,0 +1,743 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: orderer/raft.proto
+
+package orderer
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import common "github.com/hyperledger/fabric/protos/common"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+type RaftMessage_Type int32
+
+const (
+	RaftMessage_APPEND          RaftMessage_Type = 0
+	RaftMessage_APPEND_RESPONSE RaftMessage_Type = 1
+	RaftMessage_VOTE            RaftMessage_Type = 2
+	RaftMessage_VOTE_RESPONSE   RaftMessage_Type = 3
+	RaftMessage_SNAPSHOT        RaftMessage_Type = 4
+)
+
+var RaftMessage_Type_name = map[int32]string{
+	0: "APPEND",
+	1: "APPEND_RESPONSE",
+	2: "VOTE",
+	3: "VOTE_RESPONSE",
+	4: "SNAPSHOT",
+}
+var RaftMessage_Type_value = map[string]int32{
+	"APPEND":          0,
+	"APPEND_RESPONSE": 1,
+	"VOTE":            2,
+	"VOTE_RESPONSE":   3,
+	"SNAPSHOT":        4,
+}
+
+func (x RaftMessage_Type) String() string {
+	return proto.EnumName(RaftMessage_Type_name, int32(x))
+}
+func (RaftMessage_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptor3, []int{7, 0} }
+
+// RaftProposal is the payload of an entry in the Raft log of a channel.
+// Every orderer feeds the committed entries of the log to its block
+// cutter, exactly like the Kafka-based orderer does with its partition.
+type RaftProposal struct {
+	// Types that are valid to be assigned to Type:
+	//	*RaftProposal_Regular
+	//	*RaftProposal_TimeToCut
+	Type isRaftProposal_Type `protobuf_oneof:"Type"`
+}
+
+func (m *RaftProposal) Reset()                    { *m = RaftProposal{} }
+func (m *RaftProposal) String() string            { return proto.CompactTextString(m) }
+func (*RaftProposal) ProtoMessage()               {}
+func (*RaftProposal) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{0} }
+
+type isRaftProposal_Type interface {
+	isRaftProposal_Type()
+}
+
+type RaftProposal_Regular struct {
+	Regular *RaftProposalRegular `protobuf:"bytes,1,opt,name=regular,oneof"`
+}
+type RaftProposal_TimeToCut struct {
+	TimeToCut *RaftProposalTimeToCut `protobuf:"bytes,2,opt,name=time_to_cut,json=timeToCut,oneof"`
+}
+
+func (*RaftProposal_Regular) isRaftProposal_Type()   {}
+func (*RaftProposal_TimeToCut) isRaftProposal_Type() {}
+
+func (m *RaftProposal) GetType() isRaftProposal_Type {
+	if m != nil {
+		return m.Type
+	}
+	return nil
+}
+
+func (m *RaftProposal) GetRegular() *RaftProposalRegular {
+	if x, ok := m.GetType().(*RaftProposal_Regular); ok {
+		return x.Regular
+	}
+	return nil
+}
+
+func (m *RaftProposal) GetTimeToCut() *RaftProposalTimeToCut {
+	if x, ok := m.GetType().(*RaftProposal_TimeToCut); ok {
+		return x.TimeToCut
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*RaftProposal) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _RaftProposal_OneofMarshaler, _RaftProposal_OneofUnmarshaler, _RaftProposal_OneofSizer, []interface{}{
+		(*RaftProposal_Regular)(nil),
+		(*RaftProposal_TimeToCut)(nil),
+	}
+}
+
+func _RaftProposal_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*RaftProposal)
+	// Type
+	switch x := m.Type.(type) {
+	case *RaftProposal_Regular:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Regular); err != nil {
+			return err
+		}
+	case *RaftProposal_TimeToCut:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.TimeToCut); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("RaftProposal.Type has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _RaftProposal_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*RaftProposal)
+	switch tag {
+	case 1: // Type.regular
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(RaftProposalRegular)
+		err := b.DecodeMessage(msg)
+		m.Type = &RaftProposal_Regular{msg}
+		return true, err
+	case 2: // Type.time_to_cut
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(RaftProposalTimeToCut)
+		err := b.DecodeMessage(msg)
+		m.Type = &RaftProposal_TimeToCut{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _RaftProposal_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*RaftProposal)
+	// Type
+	switch x := m.Type.(type) {
+	case *RaftProposal_Regular:
+		s := proto.Size(x.Regular)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *RaftProposal_TimeToCut:
+		s := proto.Size(x.TimeToCut)
+		n += proto.SizeVarint(2<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+// RaftProposalRegular wraps a marshalled envelope.
+type RaftProposalRegular struct {
+	Payload []byte `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+}
+
+func (m *RaftProposalRegular) Reset()                    { *m = RaftProposalRegular{} }
+func (m *RaftProposalRegular) String() string            { return proto.CompactTextString(m) }
+func (*RaftProposalRegular) ProtoMessage()               {}
+func (*RaftProposalRegular) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
+
+func (m *RaftProposalRegular) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+// RaftProposalTimeToCut is proposed by the leader to signal to the
+// orderers that it is time to cut block <block_number>.
+type RaftProposalTimeToCut struct {
+	BlockNumber uint64 `protobuf:"varint,1,opt,name=block_number,json=blockNumber" json:"block_number,omitempty"`
+}
+
+func (m *RaftProposalTimeToCut) Reset()                    { *m = RaftProposalTimeToCut{} }
+func (m *RaftProposalTimeToCut) String() string            { return proto.CompactTextString(m) }
+func (*RaftProposalTimeToCut) ProtoMessage()               {}
+func (*RaftProposalTimeToCut) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2} }
+
+func (m *RaftProposalTimeToCut) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+// RaftMetadata is the encoded value for the Metadata message which is
+// encoded in the ORDERER block metadata index for the case of the
+// Raft-based orderer.
+type RaftMetadata struct {
+	LastIndexPersisted uint64 `protobuf:"varint,1,opt,name=last_index_persisted,json=lastIndexPersisted" json:"last_index_persisted,omitempty"`
+}
+
+func (m *RaftMetadata) Reset()                    { *m = RaftMetadata{} }
+func (m *RaftMetadata) String() string            { return proto.CompactTextString(m) }
+func (*RaftMetadata) ProtoMessage()               {}
+func (*RaftMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{3} }
+
+func (m *RaftMetadata) GetLastIndexPersisted() uint64 {
+	if m != nil {
+		return m.LastIndexPersisted
+	}
+	return 0
+}
+
+// RaftLogEntry is a single entry of the replicated log.
+type RaftLogEntry struct {
+	Term  uint64 `protobuf:"varint,1,opt,name=term" json:"term,omitempty"`
+	Index uint64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	Data  []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *RaftLogEntry) Reset()                    { *m = RaftLogEntry{} }
+func (m *RaftLogEntry) String() string            { return proto.CompactTextString(m) }
+func (*RaftLogEntry) ProtoMessage()               {}
+func (*RaftLogEntry) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
+
+func (m *RaftLogEntry) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *RaftLogEntry) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *RaftLogEntry) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// RaftHardState is the part of the Raft state which has to be persisted
+// before any message is sent out.
+type RaftHardState struct {
+	Term   uint64 `protobuf:"varint,1,opt,name=term" json:"term,omitempty"`
+	Vote   uint64 `protobuf:"varint,2,opt,name=vote" json:"vote,omitempty"`
+	Commit uint64 `protobuf:"varint,3,opt,name=commit" json:"commit,omitempty"`
+}
+
+func (m *RaftHardState) Reset()                    { *m = RaftHardState{} }
+func (m *RaftHardState) String() string            { return proto.CompactTextString(m) }
+func (*RaftHardState) ProtoMessage()               {}
+func (*RaftHardState) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
+
+func (m *RaftHardState) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *RaftHardState) GetVote() uint64 {
+	if m != nil {
+		return m.Vote
+	}
+	return 0
+}
+
+func (m *RaftHardState) GetCommit() uint64 {
+	if m != nil {
+		return m.Commit
+	}
+	return 0
+}
+
+// RaftSnapshot replaces the prefix of the log up to and including index.
+// The data is the last block which was cut from that prefix.
+type RaftSnapshot struct {
+	Term  uint64 `protobuf:"varint,1,opt,name=term" json:"term,omitempty"`
+	Index uint64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	Data  []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *RaftSnapshot) Reset()                    { *m = RaftSnapshot{} }
+func (m *RaftSnapshot) String() string            { return proto.CompactTextString(m) }
+func (*RaftSnapshot) ProtoMessage()               {}
+func (*RaftSnapshot) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{6} }
+
+func (m *RaftSnapshot) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *RaftSnapshot) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *RaftSnapshot) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// RaftMessage is exchanged between the Raft nodes of a channel.
+type RaftMessage struct {
+	Channel    string           `protobuf:"bytes,1,opt,name=channel" json:"channel,omitempty"`
+	Type       RaftMessage_Type `protobuf:"varint,2,opt,name=type,enum=orderer.RaftMessage_Type" json:"type,omitempty"`
+	From       uint64           `protobuf:"varint,3,opt,name=from" json:"from,omitempty"`
+	To         uint64           `protobuf:"varint,4,opt,name=to" json:"to,omitempty"`
+	Term       uint64           `protobuf:"varint,5,opt,name=term" json:"term,omitempty"`
+	LogTerm    uint64           `protobuf:"varint,6,opt,name=log_term,json=logTerm" json:"log_term,omitempty"`
+	Index      uint64           `protobuf:"varint,7,opt,name=index" json:"index,omitempty"`
+	Entries    []*RaftLogEntry  `protobuf:"bytes,8,rep,name=entries" json:"entries,omitempty"`
+	Commit     uint64           `protobuf:"varint,9,opt,name=commit" json:"commit,omitempty"`
+	Reject     bool             `protobuf:"varint,10,opt,name=reject" json:"reject,omitempty"`
+	RejectHint uint64           `protobuf:"varint,11,opt,name=reject_hint,json=rejectHint" json:"reject_hint,omitempty"`
+	Snapshot   *RaftSnapshot    `protobuf:"bytes,12,opt,name=snapshot" json:"snapshot,omitempty"`
+}
+
+func (m *RaftMessage) Reset()                    { *m = RaftMessage{} }
+func (m *RaftMessage) String() string            { return proto.CompactTextString(m) }
+func (*RaftMessage) ProtoMessage()               {}
+func (*RaftMessage) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
+
+func (m *RaftMessage) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *RaftMessage) GetType() RaftMessage_Type {
+	if m != nil {
+		return m.Type
+	}
+	return RaftMessage_APPEND
+}
+
+func (m *RaftMessage) GetFrom() uint64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *RaftMessage) GetTo() uint64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+func (m *RaftMessage) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *RaftMessage) GetLogTerm() uint64 {
+	if m != nil {
+		return m.LogTerm
+	}
+	return 0
+}
+
+func (m *RaftMessage) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *RaftMessage) GetEntries() []*RaftLogEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *RaftMessage) GetCommit() uint64 {
+	if m != nil {
+		return m.Commit
+	}
+	return 0
+}
+
+func (m *RaftMessage) GetReject() bool {
+	if m != nil {
+		return m.Reject
+	}
+	return false
+}
+
+func (m *RaftMessage) GetRejectHint() uint64 {
+	if m != nil {
+		return m.RejectHint
+	}
+	return 0
+}
+
+func (m *RaftMessage) GetSnapshot() *RaftSnapshot {
+	if m != nil {
+		return m.Snapshot
+	}
+	return nil
+}
+
+type StepResponse struct {
+}
+
+func (m *StepResponse) Reset()                    { *m = StepResponse{} }
+func (m *StepResponse) String() string            { return proto.CompactTextString(m) }
+func (*StepResponse) ProtoMessage()               {}
+func (*StepResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
+
+// SubmitRequest carries an envelope which a follower forwards to the
+// leader of the channel.
+type SubmitRequest struct {
+	Channel  string           `protobuf:"bytes,1,opt,name=channel" json:"channel,omitempty"`
+	Envelope *common.Envelope `protobuf:"bytes,2,opt,name=envelope" json:"envelope,omitempty"`
+}
+
+func (m *SubmitRequest) Reset()                    { *m = SubmitRequest{} }
+func (m *SubmitRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubmitRequest) ProtoMessage()               {}
+func (*SubmitRequest) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{9} }
+
+func (m *SubmitRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *SubmitRequest) GetEnvelope() *common.Envelope {
+	if m != nil {
+		return m.Envelope
+	}
+	return nil
+}
+
+type SubmitResponse struct {
+	Status common.Status `protobuf:"varint,1,opt,name=status,enum=common.Status" json:"status,omitempty"`
+}
+
+func (m *SubmitResponse) Reset()                    { *m = SubmitResponse{} }
+func (m *SubmitResponse) String() string            { return proto.CompactTextString(m) }
+func (*SubmitResponse) ProtoMessage()               {}
+func (*SubmitResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{10} }
+
+func (m *SubmitResponse) GetStatus() common.Status {
+	if m != nil {
+		return m.Status
+	}
+	return common.Status_UNKNOWN
+}
+
+// PullRequest asks for a block of the channel, it is used by orderers
+// which have fallen behind the snapshot of the leader.
+type PullRequest struct {
+	Channel string `protobuf:"bytes,1,opt,name=channel" json:"channel,omitempty"`
+	Number  uint64 `protobuf:"varint,2,opt,name=number" json:"number,omitempty"`
+}
+
+func (m *PullRequest) Reset()                    { *m = PullRequest{} }
+func (m *PullRequest) String() string            { return proto.CompactTextString(m) }
+func (*PullRequest) ProtoMessage()               {}
+func (*PullRequest) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{11} }
+
+func (m *PullRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *PullRequest) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+type PullResponse struct {
+	Status common.Status `protobuf:"varint,1,opt,name=status,enum=common.Status" json:"status,omitempty"`
+	Block  *common.Block `protobuf:"bytes,2,opt,name=block" json:"block,omitempty"`
+}
+
+func (m *PullResponse) Reset()                    { *m = PullResponse{} }
+func (m *PullResponse) String() string            { return proto.CompactTextString(m) }
+func (*PullResponse) ProtoMessage()               {}
+func (*PullResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{12} }
+
+func (m *PullResponse) GetStatus() common.Status {
+	if m != nil {
+		return m.Status
+	}
+	return common.Status_UNKNOWN
+}
+
+func (m *PullResponse) GetBlock() *common.Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*RaftProposal)(nil), "orderer.RaftProposal")
+	proto.RegisterType((*RaftProposalRegular)(nil), "orderer.RaftProposalRegular")
+	proto.RegisterType((*RaftProposalTimeToCut)(nil), "orderer.RaftProposalTimeToCut")
+	proto.RegisterType((*RaftMetadata)(nil), "orderer.RaftMetadata")
+	proto.RegisterType((*RaftLogEntry)(nil), "orderer.RaftLogEntry")
+	proto.RegisterType((*RaftHardState)(nil), "orderer.RaftHardState")
+	proto.RegisterType((*RaftSnapshot)(nil), "orderer.RaftSnapshot")
+	proto.RegisterType((*RaftMessage)(nil), "orderer.RaftMessage")
+	proto.RegisterType((*StepResponse)(nil), "orderer.StepResponse")
+	proto.RegisterType((*SubmitRequest)(nil), "orderer.SubmitRequest")
+	proto.RegisterType((*SubmitResponse)(nil), "orderer.SubmitResponse")
+	proto.RegisterType((*PullRequest)(nil), "orderer.PullRequest")
+	proto.RegisterType((*PullResponse)(nil), "orderer.PullResponse")
+	proto.RegisterEnum("orderer.RaftMessage_Type", RaftMessage_Type_name, RaftMessage_Type_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Cluster service
+
+type ClusterClient interface {
+	Step(ctx context.Context, in *RaftMessage, opts ...grpc.CallOption) (*StepResponse, error)
+	Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error)
+	Pull(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (*PullResponse, error)
+}
+
+type clusterClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewClusterClient(cc *grpc.ClientConn) ClusterClient {
+	return &clusterClient{cc}
+}
+
+func (c *clusterClient) Step(ctx context.Context, in *RaftMessage, opts ...grpc.CallOption) (*StepResponse, error) {
+	out := new(StepResponse)
+	err := grpc.Invoke(ctx, "/orderer.Cluster/Step", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clusterClient) Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error) {
+	out := new(SubmitResponse)
+	err := grpc.Invoke(ctx, "/orderer.Cluster/Submit", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clusterClient) Pull(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (*PullResponse, error) {
+	out := new(PullResponse)
+	err := grpc.Invoke(ctx, "/orderer.Cluster/Pull", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Cluster service
+
+type ClusterServer interface {
+	Step(context.Context, *RaftMessage) (*StepResponse, error)
+	Submit(context.Context, *SubmitRequest) (*SubmitResponse, error)
+	Pull(context.Context, *PullRequest) (*PullResponse, error)
+}
+
+func RegisterClusterServer(s *grpc.Server, srv ClusterServer) {
+	s.RegisterService(&_Cluster_serviceDesc, srv)
+}
+
+func _Cluster_Step_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RaftMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServer).Step(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/orderer.Cluster/Step",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServer).Step(ctx, req.(*RaftMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cluster_Submit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServer).Submit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/orderer.Cluster/Submit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServer).Submit(ctx, req.(*SubmitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cluster_Pull_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PullRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServer).Pull(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/orderer.Cluster/Pull",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServer).Pull(ctx, req.(*PullRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Cluster_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "orderer.Cluster",
+	HandlerType: (*ClusterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Step",
+			Handler:    _Cluster_Step_Handler,
+		},
+		{
+			MethodName: "Submit",
+			Handler:    _Cluster_Submit_Handler,
+		},
+		{
+			MethodName: "Pull",
+			Handler:    _Cluster_Pull_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "orderer/raft.proto",
+}
+
+func init() { proto.RegisterFile("orderer/raft.proto", fileDescriptor3) }
+
+var fileDescriptor3 = []byte{
+	// 778 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x55, 0xdd, 0x6e, 0xe3, 0x44,
+	0x14, 0x6e, 0x5a, 0xd7, 0x49, 0x8f, 0xd3, 0x10, 0xa6, 0x3f, 0x78, 0x2b, 0x04, 0xc5, 0x08, 0xd4,
+	0x8b, 0x25, 0x86, 0xa0, 0x95, 0x56, 0x70, 0xc1, 0x6e, 0x97, 0x48, 0x41, 0x5a, 0xda, 0x68, 0x1c,
+	0x40, 0x82, 0x0b, 0x6b, 0x92, 0x4c, 0x1d, 0x83, 0xed, 0x31, 0x33, 0xc7, 0x2b, 0xf2, 0x1a, 0x3c,
+	0x0b, 0x0f, 0xc1, 0x63, 0x21, 0xcf, 0x8c, 0x5b, 0x87, 0x46, 0x20, 0xc4, 0x55, 0xce, 0xf9, 0xce,
+	0x77, 0xbe, 0xf3, 0x67, 0x3b, 0x40, 0x84, 0x5c, 0x71, 0xc9, 0x65, 0x28, 0xd9, 0x1d, 0x8e, 0x4a,
+	0x29, 0x50, 0x90, 0xae, 0xc5, 0x2e, 0x4e, 0x96, 0x22, 0xcf, 0x45, 0x11, 0x9a, 0x1f, 0x13, 0x0d,
+	0x7e, 0xef, 0x40, 0x9f, 0xb2, 0x3b, 0x9c, 0x49, 0x51, 0x0a, 0xc5, 0x32, 0xf2, 0x1c, 0xba, 0x92,
+	0x27, 0x55, 0xc6, 0xa4, 0xdf, 0xb9, 0xec, 0x5c, 0x79, 0xe3, 0x77, 0x47, 0x56, 0x60, 0xd4, 0xe6,
+	0x51, 0xc3, 0x99, 0xee, 0xd1, 0x86, 0x4e, 0x5e, 0x80, 0x87, 0x69, 0xce, 0x63, 0x14, 0xf1, 0xb2,
+	0x42, 0x7f, 0x5f, 0x67, 0xbf, 0xb7, 0x33, 0x7b, 0x9e, 0xe6, 0x7c, 0x2e, 0x5e, 0x55, 0x38, 0xdd,
+	0xa3, 0x47, 0xd8, 0x38, 0xd7, 0x2e, 0x38, 0xf3, 0x4d, 0xc9, 0x83, 0x10, 0x4e, 0x76, 0xd4, 0x22,
+	0x3e, 0x74, 0x4b, 0xb6, 0xc9, 0x04, 0x5b, 0xe9, 0xd6, 0xfa, 0xb4, 0x71, 0x83, 0x2f, 0xe0, 0x6c,
+	0xa7, 0x3c, 0xf9, 0x00, 0xfa, 0x8b, 0x4c, 0x2c, 0x7f, 0x89, 0x8b, 0x2a, 0x5f, 0x70, 0x33, 0x92,
+	0x43, 0x3d, 0x8d, 0xdd, 0x68, 0x28, 0x78, 0x61, 0x16, 0xf0, 0x2d, 0x47, 0xb6, 0x62, 0xc8, 0xc8,
+	0xa7, 0x70, 0x9a, 0x31, 0x85, 0x71, 0x5a, 0xac, 0xf8, 0x6f, 0x71, 0xc9, 0xa5, 0x4a, 0x15, 0xf2,
+	0x95, 0x4d, 0x25, 0x75, 0xec, 0x9b, 0x3a, 0x34, 0x6b, 0x22, 0xc1, 0x6b, 0xa3, 0xf0, 0x5a, 0x24,
+	0x93, 0x02, 0xe5, 0x86, 0x10, 0x70, 0x90, 0xcb, 0xdc, 0x66, 0x68, 0x9b, 0x9c, 0xc2, 0xa1, 0x16,
+	0xd4, 0x6b, 0x71, 0xa8, 0x71, 0x6a, 0x66, 0x5d, 0xd3, 0x3f, 0xd0, 0xe3, 0x68, 0x3b, 0xb8, 0x85,
+	0xe3, 0x5a, 0x6d, 0xca, 0xe4, 0x2a, 0x42, 0x86, 0x7c, 0xa7, 0x1c, 0x01, 0xe7, 0x8d, 0x40, 0x6e,
+	0xd5, 0xb4, 0x4d, 0xce, 0xc1, 0xad, 0x4f, 0x9b, 0xa2, 0x96, 0x73, 0xa8, 0xf5, 0x9a, 0xf6, 0xa2,
+	0x82, 0x95, 0x6a, 0x2d, 0xf0, 0x7f, 0xb6, 0xf7, 0xe7, 0x01, 0x78, 0x66, 0x5f, 0x4a, 0xb1, 0x84,
+	0xd7, 0x47, 0x59, 0xae, 0x59, 0x51, 0xf0, 0x4c, 0x0b, 0x1e, 0xd1, 0xc6, 0x25, 0x9f, 0x80, 0x83,
+	0x9b, 0xd2, 0xf4, 0x38, 0x18, 0x3f, 0xd9, 0x7a, 0x10, 0x6c, 0xf6, 0xa8, 0x3e, 0x37, 0xd5, 0xb4,
+	0xba, 0xd8, 0x9d, 0x14, 0xb9, 0x6d, 0x5e, 0xdb, 0x64, 0x00, 0xfb, 0x28, 0x7c, 0x47, 0x23, 0xfb,
+	0x28, 0xee, 0x5b, 0x3f, 0x6c, 0xb5, 0xfe, 0x04, 0x7a, 0x99, 0x48, 0x62, 0x8d, 0xbb, 0x1a, 0xef,
+	0x66, 0x22, 0x99, 0x6f, 0x4d, 0xd5, 0x6d, 0x4f, 0x15, 0x42, 0x97, 0x17, 0x28, 0x53, 0xae, 0xfc,
+	0xde, 0xe5, 0xc1, 0x95, 0x37, 0x3e, 0xdb, 0x6a, 0xad, 0x39, 0x23, 0x6d, 0x58, 0xad, 0xc5, 0x1e,
+	0xb5, 0x17, 0x5b, 0xe3, 0x92, 0xff, 0xcc, 0x97, 0xe8, 0xc3, 0x65, 0xe7, 0xaa, 0x47, 0xad, 0x47,
+	0xde, 0x07, 0xcf, 0x58, 0xf1, 0x3a, 0x2d, 0xd0, 0xf7, 0x74, 0x12, 0x18, 0x68, 0x9a, 0x16, 0x48,
+	0x3e, 0x83, 0x9e, 0xb2, 0xd7, 0xf0, 0xfb, 0x97, 0x9d, 0x47, 0x2d, 0x34, 0xa7, 0xa2, 0xf7, 0xb4,
+	0x80, 0x9a, 0x57, 0x83, 0x00, 0xb8, 0x2f, 0x67, 0xb3, 0xc9, 0xcd, 0xd7, 0xc3, 0x3d, 0x72, 0x02,
+	0x6f, 0x19, 0x3b, 0xa6, 0x93, 0x68, 0x76, 0x7b, 0x13, 0x4d, 0x86, 0x1d, 0xd2, 0x03, 0xe7, 0xfb,
+	0xdb, 0xf9, 0x64, 0xb8, 0x4f, 0xde, 0x86, 0xe3, 0xda, 0x7a, 0x08, 0x1e, 0x90, 0x3e, 0xf4, 0xa2,
+	0x9b, 0x97, 0xb3, 0x68, 0x7a, 0x3b, 0x1f, 0x3a, 0xc1, 0x00, 0xfa, 0x11, 0xf2, 0x92, 0x72, 0x55,
+	0x8a, 0x42, 0xf1, 0xe0, 0x07, 0x38, 0x8e, 0xaa, 0x45, 0x9e, 0x22, 0xe5, 0xbf, 0x56, 0x5c, 0xe1,
+	0x3f, 0xdc, 0xf6, 0x29, 0xf4, 0x78, 0xf1, 0x86, 0x67, 0xc2, 0xde, 0xd7, 0x1b, 0x0f, 0x47, 0xf6,
+	0xbb, 0x32, 0xb1, 0x38, 0xbd, 0x67, 0x04, 0xcf, 0x61, 0xd0, 0x08, 0x9b, 0x52, 0xe4, 0x63, 0x70,
+	0x15, 0x32, 0xac, 0x94, 0x16, 0x1e, 0x8c, 0x07, 0x4d, 0x76, 0xa4, 0x51, 0x6a, 0xa3, 0xc1, 0x57,
+	0xe0, 0xcd, 0xaa, 0x2c, 0xfb, 0xf7, 0x86, 0xce, 0xc1, 0xb5, 0xaf, 0xb8, 0x79, 0x82, 0xad, 0x17,
+	0xfc, 0x04, 0x7d, 0x23, 0xf0, 0xdf, 0x0a, 0x93, 0x0f, 0xe1, 0x50, 0x7f, 0x24, 0xec, 0x74, 0xc7,
+	0x0d, 0xed, 0xba, 0x06, 0xa9, 0x89, 0x8d, 0xff, 0xe8, 0x40, 0xf7, 0x55, 0x56, 0x29, 0xe4, 0x92,
+	0x3c, 0x03, 0xa7, 0x5e, 0x26, 0x39, 0xdd, 0xf5, 0x9c, 0x5f, 0x3c, 0xdc, 0x77, 0x6b, 0xe3, 0x7b,
+	0xe4, 0x4b, 0x70, 0xcd, 0x6a, 0xc8, 0xf9, 0x03, 0xa5, 0x7d, 0x84, 0x8b, 0x77, 0x1e, 0xe1, 0xf7,
+	0xc9, 0xcf, 0xc0, 0xa9, 0x87, 0x6b, 0xd5, 0x6c, 0x2d, 0xeb, 0xe2, 0xec, 0x6f, 0x68, 0x93, 0x76,
+	0xfd, 0x1d, 0x7c, 0x24, 0x64, 0x32, 0x5a, 0x6f, 0x4a, 0x2e, 0x33, 0xbe, 0x4a, 0xb8, 0x1c, 0xdd,
+	0xb1, 0x85, 0x4c, 0x97, 0xe6, 0x3f, 0x41, 0x35, 0x79, 0x3f, 0x3e, 0x4d, 0x52, 0x5c, 0x57, 0x8b,
+	0x7a, 0xf6, 0xb0, 0xc5, 0x0e, 0x0d, 0x3b, 0x34, 0xec, 0xd0, 0xb2, 0x17, 0xae, 0xf6, 0x3f, 0xff,
+	0x6b, 0x00, 0xf2, 0xb3, 0x0c, 0x06, 0x85, 0x06, 0x00, 0x00,
+}
@@ -30,19 +30,31 @@ It has these top-level messages:
 	ChaincodeEvent
 	ChaincodeMessage
 	PutStateInfo
+	PrivateDataInfo
+	GetStateMetadata
+	PutStateMetadata
+	StateMetadata
+	StateMetadataResult
 	GetStateByRange
 	GetQueryResult
+	QueryMetadata
 	GetHistoryForKey
 	QueryStateNext
 	QueryStateClose
 	QueryResultBytes
 	QueryResponse
+	QueryResponseMetadata
 	AnchorPeers
 	AnchorPeer
+	ACLs
+	APIResource
 	ChaincodeReg
 	Interest
 	Register
 	Rejection
+	FilteredBlock
+	FilteredTransaction
+	FilteredChaincodeEvent
 	Unregister
 	SignedEvent
 	Event
@@ -54,6 +66,7 @@ It has these top-level messages:
 	ChaincodeProposalPayload
 	ChaincodeAction
 	ProposalResponse
+	CrossChannelResponse
 	Response
 	ProposalResponsePayload
 	Endorsement
@@ -68,6 +81,7 @@ It has these top-level messages:
 	TransactionAction
 	ChaincodeActionPayload
 	ChaincodeEndorsedAction
+	CrossChannelRecord
 */
 package peer
 
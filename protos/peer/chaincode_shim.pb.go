@@ -18,6 +18,25 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+// MetaDataKeys lists the well-known names of the metadata entries of a key
+type MetaDataKeys int32
+
+const (
+	MetaDataKeys_VALIDATION_PARAMETER MetaDataKeys = 0
+)
+
+var MetaDataKeys_name = map[int32]string{
+	0: "VALIDATION_PARAMETER",
+}
+var MetaDataKeys_value = map[string]int32{
+	"VALIDATION_PARAMETER": 0,
+}
+
+func (x MetaDataKeys) String() string {
+	return proto.EnumName(MetaDataKeys_name, int32(x))
+}
+func (MetaDataKeys) EnumDescriptor() ([]byte, []int) { return fileDescriptor3, []int{0} }
+
 type ChaincodeMessage_Type int32
 
 const (
@@ -40,6 +59,11 @@ const (
 	ChaincodeMessage_QUERY_STATE_CLOSE   ChaincodeMessage_Type = 17
 	ChaincodeMessage_KEEPALIVE           ChaincodeMessage_Type = 18
 	ChaincodeMessage_GET_HISTORY_FOR_KEY ChaincodeMessage_Type = 19
+	ChaincodeMessage_GET_PRIVATE_DATA    ChaincodeMessage_Type = 20
+	ChaincodeMessage_PUT_PRIVATE_DATA    ChaincodeMessage_Type = 21
+	ChaincodeMessage_DEL_PRIVATE_DATA    ChaincodeMessage_Type = 22
+	ChaincodeMessage_GET_STATE_METADATA  ChaincodeMessage_Type = 23
+	ChaincodeMessage_PUT_STATE_METADATA  ChaincodeMessage_Type = 24
 )
 
 var ChaincodeMessage_Type_name = map[int32]string{
@@ -62,6 +86,11 @@ var ChaincodeMessage_Type_name = map[int32]string{
 	17: "QUERY_STATE_CLOSE",
 	18: "KEEPALIVE",
 	19: "GET_HISTORY_FOR_KEY",
+	20: "GET_PRIVATE_DATA",
+	21: "PUT_PRIVATE_DATA",
+	22: "DEL_PRIVATE_DATA",
+	23: "GET_STATE_METADATA",
+	24: "PUT_STATE_METADATA",
 }
 var ChaincodeMessage_Type_value = map[string]int32{
 	"UNDEFINED":           0,
@@ -83,6 +112,11 @@ var ChaincodeMessage_Type_value = map[string]int32{
 	"QUERY_STATE_CLOSE":   17,
 	"KEEPALIVE":           18,
 	"GET_HISTORY_FOR_KEY": 19,
+	"GET_PRIVATE_DATA":    20,
+	"PUT_PRIVATE_DATA":    21,
+	"DEL_PRIVATE_DATA":    22,
+	"GET_STATE_METADATA":  23,
+	"PUT_STATE_METADATA":  24,
 }
 
 func (x ChaincodeMessage_Type) String() string {
@@ -173,15 +207,134 @@ func (m *PutStateInfo) GetValue() []byte {
 	return nil
 }
 
+// PrivateDataInfo is the payload of the GET_PRIVATE_DATA, PUT_PRIVATE_DATA
+// and DEL_PRIVATE_DATA messages. The value is only set for PUT_PRIVATE_DATA
+type PrivateDataInfo struct {
+	Collection string `protobuf:"bytes,1,opt,name=collection" json:"collection,omitempty"`
+	Key        string `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+	Value      []byte `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *PrivateDataInfo) Reset()                    { *m = PrivateDataInfo{} }
+func (m *PrivateDataInfo) String() string            { return proto.CompactTextString(m) }
+func (*PrivateDataInfo) ProtoMessage()               {}
+func (*PrivateDataInfo) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2} }
+
+func (m *PrivateDataInfo) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+func (m *PrivateDataInfo) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *PrivateDataInfo) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// GetStateMetadata is the payload of the GET_STATE_METADATA message
+type GetStateMetadata struct {
+	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *GetStateMetadata) Reset()                    { *m = GetStateMetadata{} }
+func (m *GetStateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*GetStateMetadata) ProtoMessage()               {}
+func (*GetStateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{3} }
+
+func (m *GetStateMetadata) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+// PutStateMetadata is the payload of the PUT_STATE_METADATA message
+type PutStateMetadata struct {
+	Key      string         `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Metadata *StateMetadata `protobuf:"bytes,2,opt,name=metadata" json:"metadata,omitempty"`
+}
+
+func (m *PutStateMetadata) Reset()                    { *m = PutStateMetadata{} }
+func (m *PutStateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*PutStateMetadata) ProtoMessage()               {}
+func (*PutStateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
+
+func (m *PutStateMetadata) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *PutStateMetadata) GetMetadata() *StateMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// StateMetadata is a named entry of the metadata of a key
+type StateMetadata struct {
+	Metakey string `protobuf:"bytes,1,opt,name=metakey" json:"metakey,omitempty"`
+	Value   []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *StateMetadata) Reset()                    { *m = StateMetadata{} }
+func (m *StateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*StateMetadata) ProtoMessage()               {}
+func (*StateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
+
+func (m *StateMetadata) GetMetakey() string {
+	if m != nil {
+		return m.Metakey
+	}
+	return ""
+}
+
+func (m *StateMetadata) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// StateMetadataResult is the response to a GET_STATE_METADATA message
+type StateMetadataResult struct {
+	Entries []*StateMetadata `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *StateMetadataResult) Reset()                    { *m = StateMetadataResult{} }
+func (m *StateMetadataResult) String() string            { return proto.CompactTextString(m) }
+func (*StateMetadataResult) ProtoMessage()               {}
+func (*StateMetadataResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{6} }
+
+func (m *StateMetadataResult) GetEntries() []*StateMetadata {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
 type GetStateByRange struct {
 	StartKey string `protobuf:"bytes,1,opt,name=startKey" json:"startKey,omitempty"`
 	EndKey   string `protobuf:"bytes,2,opt,name=endKey" json:"endKey,omitempty"`
+	Metadata []byte `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *GetStateByRange) Reset()                    { *m = GetStateByRange{} }
 func (m *GetStateByRange) String() string            { return proto.CompactTextString(m) }
 func (*GetStateByRange) ProtoMessage()               {}
-func (*GetStateByRange) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2} }
+func (*GetStateByRange) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
 
 func (m *GetStateByRange) GetStartKey() string {
 	if m != nil {
@@ -197,14 +350,22 @@ func (m *GetStateByRange) GetEndKey() string {
 	return ""
 }
 
+func (m *GetStateByRange) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type GetQueryResult struct {
-	Query string `protobuf:"bytes,1,opt,name=query" json:"query,omitempty"`
+	Query    string `protobuf:"bytes,1,opt,name=query" json:"query,omitempty"`
+	Metadata []byte `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *GetQueryResult) Reset()                    { *m = GetQueryResult{} }
 func (m *GetQueryResult) String() string            { return proto.CompactTextString(m) }
 func (*GetQueryResult) ProtoMessage()               {}
-func (*GetQueryResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{3} }
+func (*GetQueryResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
 
 func (m *GetQueryResult) GetQuery() string {
 	if m != nil {
@@ -213,6 +374,39 @@ func (m *GetQueryResult) GetQuery() string {
 	return ""
 }
 
+func (m *GetQueryResult) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// QueryMetadata is the metadata of a GetStateByRange and GetQueryResult request.
+// It is set only for paginated queries.
+type QueryMetadata struct {
+	PageSize int32  `protobuf:"varint,1,opt,name=pageSize" json:"pageSize,omitempty"`
+	Bookmark string `protobuf:"bytes,2,opt,name=bookmark" json:"bookmark,omitempty"`
+}
+
+func (m *QueryMetadata) Reset()                    { *m = QueryMetadata{} }
+func (m *QueryMetadata) String() string            { return proto.CompactTextString(m) }
+func (*QueryMetadata) ProtoMessage()               {}
+func (*QueryMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{9} }
+
+func (m *QueryMetadata) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *QueryMetadata) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
 type GetHistoryForKey struct {
 	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 }
@@ -220,7 +414,7 @@ type GetHistoryForKey struct {
 func (m *GetHistoryForKey) Reset()                    { *m = GetHistoryForKey{} }
 func (m *GetHistoryForKey) String() string            { return proto.CompactTextString(m) }
 func (*GetHistoryForKey) ProtoMessage()               {}
-func (*GetHistoryForKey) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
+func (*GetHistoryForKey) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{10} }
 
 func (m *GetHistoryForKey) GetKey() string {
 	if m != nil {
@@ -236,7 +430,7 @@ type QueryStateNext struct {
 func (m *QueryStateNext) Reset()                    { *m = QueryStateNext{} }
 func (m *QueryStateNext) String() string            { return proto.CompactTextString(m) }
 func (*QueryStateNext) ProtoMessage()               {}
-func (*QueryStateNext) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
+func (*QueryStateNext) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{11} }
 
 func (m *QueryStateNext) GetId() string {
 	if m != nil {
@@ -252,7 +446,7 @@ type QueryStateClose struct {
 func (m *QueryStateClose) Reset()                    { *m = QueryStateClose{} }
 func (m *QueryStateClose) String() string            { return proto.CompactTextString(m) }
 func (*QueryStateClose) ProtoMessage()               {}
-func (*QueryStateClose) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{6} }
+func (*QueryStateClose) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{12} }
 
 func (m *QueryStateClose) GetId() string {
 	if m != nil {
@@ -268,7 +462,7 @@ type QueryResultBytes struct {
 func (m *QueryResultBytes) Reset()                    { *m = QueryResultBytes{} }
 func (m *QueryResultBytes) String() string            { return proto.CompactTextString(m) }
 func (*QueryResultBytes) ProtoMessage()               {}
-func (*QueryResultBytes) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
+func (*QueryResultBytes) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{13} }
 
 func (m *QueryResultBytes) GetResultBytes() []byte {
 	if m != nil {
@@ -278,15 +472,16 @@ func (m *QueryResultBytes) GetResultBytes() []byte {
 }
 
 type QueryResponse struct {
-	Results []*QueryResultBytes `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
-	HasMore bool                `protobuf:"varint,2,opt,name=has_more,json=hasMore" json:"has_more,omitempty"`
-	Id      string              `protobuf:"bytes,3,opt,name=id" json:"id,omitempty"`
+	Results  []*QueryResultBytes `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	HasMore  bool                `protobuf:"varint,2,opt,name=has_more,json=hasMore" json:"has_more,omitempty"`
+	Id       string              `protobuf:"bytes,3,opt,name=id" json:"id,omitempty"`
+	Metadata []byte              `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *QueryResponse) Reset()                    { *m = QueryResponse{} }
 func (m *QueryResponse) String() string            { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()               {}
-func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
+func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{14} }
 
 func (m *QueryResponse) GetResults() []*QueryResultBytes {
 	if m != nil {
@@ -309,16 +504,57 @@ func (m *QueryResponse) GetId() string {
 	return ""
 }
 
+func (m *QueryResponse) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// QueryResponseMetadata is the metadata of a QueryResponse of a paginated query.
+// The bookmark is used to fetch the next page of results.
+type QueryResponseMetadata struct {
+	FetchedRecordsCount int32  `protobuf:"varint,1,opt,name=fetched_records_count,json=fetchedRecordsCount" json:"fetched_records_count,omitempty"`
+	Bookmark            string `protobuf:"bytes,2,opt,name=bookmark" json:"bookmark,omitempty"`
+}
+
+func (m *QueryResponseMetadata) Reset()                    { *m = QueryResponseMetadata{} }
+func (m *QueryResponseMetadata) String() string            { return proto.CompactTextString(m) }
+func (*QueryResponseMetadata) ProtoMessage()               {}
+func (*QueryResponseMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{15} }
+
+func (m *QueryResponseMetadata) GetFetchedRecordsCount() int32 {
+	if m != nil {
+		return m.FetchedRecordsCount
+	}
+	return 0
+}
+
+func (m *QueryResponseMetadata) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ChaincodeMessage)(nil), "protos.ChaincodeMessage")
 	proto.RegisterType((*PutStateInfo)(nil), "protos.PutStateInfo")
+	proto.RegisterType((*PrivateDataInfo)(nil), "protos.PrivateDataInfo")
+	proto.RegisterType((*GetStateMetadata)(nil), "protos.GetStateMetadata")
+	proto.RegisterType((*PutStateMetadata)(nil), "protos.PutStateMetadata")
+	proto.RegisterType((*StateMetadata)(nil), "protos.StateMetadata")
+	proto.RegisterType((*StateMetadataResult)(nil), "protos.StateMetadataResult")
 	proto.RegisterType((*GetStateByRange)(nil), "protos.GetStateByRange")
 	proto.RegisterType((*GetQueryResult)(nil), "protos.GetQueryResult")
+	proto.RegisterType((*QueryMetadata)(nil), "protos.QueryMetadata")
 	proto.RegisterType((*GetHistoryForKey)(nil), "protos.GetHistoryForKey")
 	proto.RegisterType((*QueryStateNext)(nil), "protos.QueryStateNext")
 	proto.RegisterType((*QueryStateClose)(nil), "protos.QueryStateClose")
 	proto.RegisterType((*QueryResultBytes)(nil), "protos.QueryResultBytes")
 	proto.RegisterType((*QueryResponse)(nil), "protos.QueryResponse")
+	proto.RegisterType((*QueryResponseMetadata)(nil), "protos.QueryResponseMetadata")
+	proto.RegisterEnum("protos.MetaDataKeys", MetaDataKeys_name, MetaDataKeys_value)
 	proto.RegisterEnum("protos.ChaincodeMessage_Type", ChaincodeMessage_Type_name, ChaincodeMessage_Type_value)
 }
 
@@ -429,54 +665,70 @@ var _ChaincodeSupport_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor3) }
 
 var fileDescriptor3 = []byte{
-	// 771 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x94, 0x6f, 0x6f, 0xe2, 0x46,
-	0x10, 0xc6, 0x8f, 0x3f, 0x49, 0x60, 0x20, 0xb0, 0xb7, 0xb9, 0xa6, 0x3e, 0xa4, 0xaa, 0xd4, 0xaa,
-	0x2a, 0xfa, 0xc6, 0xb4, 0xb4, 0xaa, 0xfa, 0xae, 0x22, 0xb0, 0x21, 0x56, 0x88, 0xcd, 0xad, 0x9d,
-	0xd3, 0xd1, 0x37, 0x96, 0x03, 0x1b, 0x63, 0x15, 0x58, 0x77, 0x77, 0x39, 0x9d, 0x3f, 0x61, 0xbf,
-	0x4c, 0x3f, 0xc4, 0x69, 0x6d, 0x4c, 0xb8, 0x9c, 0xf2, 0xca, 0x7e, 0x66, 0x7e, 0xf3, 0xcc, 0xec,
-	0x6a, 0x35, 0xf0, 0x36, 0x61, 0x4c, 0xf4, 0x17, 0xab, 0x30, 0xde, 0x2e, 0xf8, 0x92, 0x05, 0x72,
-	0x15, 0x6f, 0xac, 0x44, 0x70, 0xc5, 0xf1, 0x69, 0xf6, 0x91, 0x9d, 0xce, 0x33, 0x84, 0x7d, 0x64,
-	0x5b, 0x95, 0x33, 0x9d, 0x8b, 0x2c, 0x97, 0x08, 0x9e, 0x70, 0x19, 0xae, 0xf7, 0xc1, 0xef, 0x23,
-	0xce, 0xa3, 0x35, 0xeb, 0x67, 0xea, 0x61, 0xf7, 0xd8, 0x57, 0xf1, 0x86, 0x49, 0x15, 0x6e, 0x92,
-	0x1c, 0x30, 0xff, 0xaf, 0x02, 0x1a, 0x15, 0x7e, 0x77, 0x4c, 0xca, 0x30, 0x62, 0xf8, 0x57, 0xa8,
-	0xaa, 0x34, 0x61, 0x46, 0xa9, 0x5b, 0xea, 0xb5, 0x06, 0xdf, 0xe5, 0xa8, 0xb4, 0x9e, 0x73, 0x96,
-	0x9f, 0x26, 0x8c, 0x66, 0x28, 0xfe, 0x13, 0xea, 0x07, 0x6b, 0xa3, 0xdc, 0x2d, 0xf5, 0x1a, 0x83,
-	0x8e, 0x95, 0x37, 0xb7, 0x8a, 0xe6, 0x96, 0x5f, 0x10, 0xf4, 0x09, 0xc6, 0x06, 0x9c, 0x25, 0x61,
-	0xba, 0xe6, 0xe1, 0xd2, 0xa8, 0x74, 0x4b, 0xbd, 0x26, 0x2d, 0x24, 0xc6, 0x50, 0x55, 0x9f, 0xe2,
-	0xa5, 0x51, 0xed, 0x96, 0x7a, 0x75, 0x9a, 0xfd, 0xe3, 0x01, 0xd4, 0x8a, 0x23, 0x1a, 0x27, 0x59,
-	0x9b, 0xcb, 0x62, 0x3c, 0x2f, 0x8e, 0xb6, 0x6c, 0x39, 0xdb, 0x67, 0xe9, 0x81, 0xc3, 0x7f, 0x41,
-	0xfb, 0xd9, 0x95, 0x19, 0xa7, 0x5f, 0x96, 0x1e, 0x4e, 0x46, 0x74, 0x96, 0xb6, 0x16, 0x5f, 0x68,
-	0xf3, 0xbf, 0x32, 0x54, 0xf5, 0x59, 0xf1, 0x39, 0xd4, 0xef, 0x9d, 0x31, 0xb9, 0xb6, 0x1d, 0x32,
-	0x46, 0xaf, 0x70, 0x13, 0x6a, 0x94, 0x4c, 0x6c, 0xcf, 0x27, 0x14, 0x95, 0x70, 0x0b, 0xa0, 0x50,
-	0x64, 0x8c, 0xca, 0xb8, 0x06, 0x55, 0xdb, 0xb1, 0x7d, 0x54, 0xc1, 0x75, 0x38, 0xa1, 0x64, 0x38,
-	0x9e, 0xa3, 0x2a, 0x6e, 0x43, 0xc3, 0xa7, 0x43, 0xc7, 0x1b, 0x8e, 0x7c, 0xdb, 0x75, 0xd0, 0x89,
-	0xb6, 0x1c, 0xb9, 0x77, 0xb3, 0x29, 0xf1, 0xc9, 0x18, 0x9d, 0x6a, 0x94, 0x50, 0xea, 0x52, 0x74,
-	0xa6, 0x33, 0x13, 0xe2, 0x07, 0x9e, 0x3f, 0xf4, 0x09, 0xaa, 0x69, 0x39, 0xbb, 0x2f, 0x64, 0x5d,
-	0xcb, 0x31, 0x99, 0xee, 0x25, 0xe0, 0x37, 0x80, 0x6c, 0xe7, 0xbd, 0x7b, 0x4b, 0x82, 0xd1, 0xcd,
-	0xd0, 0x76, 0x46, 0xee, 0x98, 0xa0, 0x46, 0x3e, 0xa0, 0x37, 0x73, 0x1d, 0x8f, 0xa0, 0x73, 0x7c,
-	0x09, 0xf8, 0x60, 0x18, 0x5c, 0xcd, 0x03, 0x3a, 0x74, 0x26, 0x04, 0xb5, 0x74, 0xad, 0x8e, 0xbf,
-	0xbb, 0x27, 0x74, 0x1e, 0x50, 0xe2, 0xdd, 0x4f, 0x7d, 0xd4, 0xd6, 0xd1, 0x3c, 0x92, 0xf3, 0x0e,
-	0xf9, 0xe0, 0x23, 0x84, 0xbf, 0x81, 0xd7, 0xc7, 0xd1, 0xd1, 0xd4, 0xf5, 0x08, 0x7a, 0xad, 0xa7,
-	0xb9, 0x25, 0x64, 0x36, 0x9c, 0xda, 0xef, 0x09, 0xc2, 0xf8, 0x5b, 0xb8, 0xd0, 0x8e, 0x37, 0xb6,
-	0xe7, 0xbb, 0x74, 0x1e, 0x5c, 0xbb, 0x34, 0xb8, 0x25, 0x73, 0x74, 0x61, 0xfe, 0x01, 0xcd, 0xd9,
-	0x4e, 0x79, 0x2a, 0x54, 0xcc, 0xde, 0x3e, 0x72, 0x8c, 0xa0, 0xf2, 0x0f, 0x4b, 0xb3, 0x87, 0x56,
-	0xa7, 0xfa, 0x17, 0xbf, 0x81, 0x93, 0x8f, 0xe1, 0x7a, 0xc7, 0xb2, 0x47, 0xd4, 0xa4, 0xb9, 0x30,
-	0x09, 0xb4, 0x27, 0x2c, 0xaf, 0xbb, 0x4a, 0x69, 0xb8, 0x8d, 0x18, 0xee, 0x40, 0x4d, 0xaa, 0x50,
-	0xa8, 0xdb, 0x43, 0xfd, 0x41, 0xe3, 0x4b, 0x38, 0x65, 0xdb, 0xa5, 0xce, 0x94, 0xb3, 0xcc, 0x5e,
-	0x99, 0x3f, 0x41, 0x6b, 0xc2, 0xd4, 0xbb, 0x1d, 0x13, 0x29, 0x65, 0x72, 0xb7, 0x56, 0xba, 0xdd,
-	0xbf, 0x5a, 0xee, 0x2d, 0x72, 0x61, 0xfe, 0x08, 0x68, 0xc2, 0xd4, 0x4d, 0x2c, 0x15, 0x17, 0xe9,
-	0x35, 0x17, 0xda, 0xf3, 0xab, 0x51, 0xcd, 0x2e, 0xb4, 0x32, 0xab, 0x6c, 0x2c, 0x87, 0x7d, 0x52,
-	0xb8, 0x05, 0xe5, 0x78, 0xb9, 0x47, 0xca, 0xf1, 0xd2, 0xfc, 0x01, 0xda, 0x4f, 0xc4, 0x68, 0xcd,
-	0x25, 0xfb, 0x0a, 0xf9, 0x1d, 0xd0, 0xd1, 0x3c, 0x57, 0xa9, 0x62, 0x12, 0x77, 0xa1, 0x21, 0x9e,
-	0x64, 0x06, 0x37, 0xe9, 0x71, 0xc8, 0xdc, 0xc2, 0x79, 0x51, 0x95, 0xf0, 0xad, 0x64, 0x78, 0x00,
-	0x67, 0x79, 0x5e, 0xe3, 0x95, 0x5e, 0x63, 0x60, 0x14, 0x6f, 0xfb, 0xb9, 0x3b, 0x2d, 0x40, 0xfc,
-	0x16, 0x6a, 0xab, 0x50, 0x06, 0x1b, 0x2e, 0xf2, 0xdb, 0xae, 0xd1, 0xb3, 0x55, 0x28, 0xef, 0xb8,
-	0x28, 0xa6, 0xac, 0x14, 0x53, 0x0e, 0x3e, 0x1c, 0x6d, 0x09, 0x6f, 0x97, 0x24, 0x5c, 0x28, 0x3c,
-	0x86, 0x1a, 0x65, 0x51, 0x2c, 0x15, 0x13, 0xd8, 0x78, 0x69, 0x47, 0x74, 0x5e, 0xcc, 0x98, 0xaf,
-	0x7a, 0xa5, 0x5f, 0x4a, 0x57, 0x2e, 0x98, 0x5c, 0x44, 0xd6, 0x2a, 0x4d, 0x98, 0x58, 0xb3, 0x65,
-	0xc4, 0x84, 0xf5, 0x18, 0x3e, 0x88, 0x78, 0x51, 0xd4, 0xe9, 0xb5, 0xf6, 0xf7, 0xcf, 0x51, 0xac,
-	0x56, 0xbb, 0x07, 0x6b, 0xc1, 0x37, 0xfd, 0x23, 0xb4, 0x9f, 0xa3, 0xf9, 0x7a, 0x93, 0x7d, 0x8d,
-	0x3e, 0xe4, 0xbb, 0xf2, 0xb7, 0xcf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xad, 0x9f, 0x80, 0x68, 0x4f,
-	0x05, 0x00, 0x00,
+	// 1032 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x56, 0xdb, 0x72, 0xe2, 0x46,
+	0x13, 0x5e, 0x4e, 0x06, 0xda, 0x18, 0x66, 0xc7, 0x87, 0xd5, 0x52, 0xf5, 0xff, 0x21, 0x54, 0x2e,
+	0x48, 0x2e, 0x20, 0x4b, 0x52, 0xa9, 0xdc, 0x6d, 0xc9, 0x30, 0xc6, 0x2a, 0x4e, 0xda, 0x91, 0x70,
+	0x96, 0xdc, 0xa8, 0x64, 0x18, 0x83, 0xca, 0xc0, 0x28, 0xd2, 0xe0, 0x5a, 0xf2, 0x08, 0x79, 0xa3,
+	0xbc, 0x4c, 0x9e, 0x25, 0x35, 0x3a, 0x19, 0x70, 0x9c, 0x5c, 0xa1, 0xef, 0xeb, 0xaf, 0xbf, 0xe9,
+	0xee, 0x91, 0x66, 0x80, 0xf7, 0x2e, 0x63, 0x5e, 0x6b, 0xb6, 0xb4, 0x9d, 0xcd, 0x8c, 0xcf, 0x99,
+	0xe5, 0x2f, 0x9d, 0x75, 0xd3, 0xf5, 0xb8, 0xe0, 0xf8, 0x24, 0xf8, 0xf1, 0xab, 0xd5, 0x23, 0x09,
+	0x7b, 0x62, 0x1b, 0x11, 0x6a, 0xaa, 0xe7, 0x41, 0xcc, 0xf5, 0xb8, 0xcb, 0x7d, 0x7b, 0x15, 0x91,
+	0x5f, 0x2d, 0x38, 0x5f, 0xac, 0x58, 0x2b, 0x40, 0xf7, 0xdb, 0x87, 0x96, 0x70, 0xd6, 0xcc, 0x17,
+	0xf6, 0xda, 0x0d, 0x05, 0xf5, 0xbf, 0x72, 0x80, 0x3a, 0xb1, 0xdf, 0x90, 0xf9, 0xbe, 0xbd, 0x60,
+	0xf8, 0x03, 0x64, 0xc5, 0xce, 0x65, 0x4a, 0xaa, 0x96, 0x6a, 0x94, 0xdb, 0xff, 0x0b, 0xa5, 0x7e,
+	0xf3, 0x58, 0xd7, 0x34, 0x77, 0x2e, 0xa3, 0x81, 0x14, 0xff, 0x0c, 0xc5, 0xc4, 0x5a, 0x49, 0xd7,
+	0x52, 0x8d, 0xd3, 0x76, 0xb5, 0x19, 0x2e, 0xde, 0x8c, 0x17, 0x6f, 0x9a, 0xb1, 0x82, 0x3e, 0x8b,
+	0xb1, 0x02, 0x79, 0xd7, 0xde, 0xad, 0xb8, 0x3d, 0x57, 0x32, 0xb5, 0x54, 0xa3, 0x44, 0x63, 0x88,
+	0x31, 0x64, 0xc5, 0x17, 0x67, 0xae, 0x64, 0x6b, 0xa9, 0x46, 0x91, 0x06, 0xcf, 0xb8, 0x0d, 0x85,
+	0xb8, 0x45, 0x25, 0x17, 0x2c, 0x73, 0x15, 0x97, 0x67, 0x38, 0x8b, 0x0d, 0x9b, 0xeb, 0x51, 0x94,
+	0x26, 0x3a, 0xfc, 0x11, 0x2a, 0x47, 0x23, 0x53, 0x4e, 0x0e, 0x53, 0x93, 0xce, 0x88, 0x8c, 0xd2,
+	0xf2, 0xec, 0x00, 0xd7, 0xff, 0xcc, 0x40, 0x56, 0xf6, 0x8a, 0xcf, 0xa0, 0x38, 0x19, 0x75, 0xc9,
+	0x8d, 0x36, 0x22, 0x5d, 0xf4, 0x06, 0x97, 0xa0, 0x40, 0x49, 0x4f, 0x33, 0x4c, 0x42, 0x51, 0x0a,
+	0x97, 0x01, 0x62, 0x44, 0xba, 0x28, 0x8d, 0x0b, 0x90, 0xd5, 0x46, 0x9a, 0x89, 0x32, 0xb8, 0x08,
+	0x39, 0x4a, 0xd4, 0xee, 0x14, 0x65, 0x71, 0x05, 0x4e, 0x4d, 0xaa, 0x8e, 0x0c, 0xb5, 0x63, 0x6a,
+	0xe3, 0x11, 0xca, 0x49, 0xcb, 0xce, 0x78, 0xa8, 0x0f, 0x88, 0x49, 0xba, 0xe8, 0x44, 0x4a, 0x09,
+	0xa5, 0x63, 0x8a, 0xf2, 0x32, 0xd2, 0x23, 0xa6, 0x65, 0x98, 0xaa, 0x49, 0x50, 0x41, 0x42, 0x7d,
+	0x12, 0xc3, 0xa2, 0x84, 0x5d, 0x32, 0x88, 0x20, 0xe0, 0x0b, 0x40, 0xda, 0xe8, 0x6e, 0xdc, 0x27,
+	0x56, 0xe7, 0x56, 0xd5, 0x46, 0x9d, 0x71, 0x97, 0xa0, 0xd3, 0xb0, 0x40, 0x43, 0x1f, 0x8f, 0x0c,
+	0x82, 0xce, 0xf0, 0x15, 0xe0, 0xc4, 0xd0, 0xba, 0x9e, 0x5a, 0x54, 0x1d, 0xf5, 0x08, 0x2a, 0xcb,
+	0x5c, 0xc9, 0x7f, 0x9a, 0x10, 0x3a, 0xb5, 0x28, 0x31, 0x26, 0x03, 0x13, 0x55, 0x24, 0x1b, 0x32,
+	0xa1, 0x7e, 0x44, 0x3e, 0x9b, 0x08, 0xe1, 0x4b, 0x78, 0xbb, 0xcf, 0x76, 0x06, 0x63, 0x83, 0xa0,
+	0xb7, 0xb2, 0x9a, 0x3e, 0x21, 0xba, 0x3a, 0xd0, 0xee, 0x08, 0xc2, 0xf8, 0x1d, 0x9c, 0x4b, 0xc7,
+	0x5b, 0xcd, 0x30, 0xc7, 0x74, 0x6a, 0xdd, 0x8c, 0xa9, 0xd5, 0x27, 0x53, 0x74, 0x1e, 0x2f, 0xa5,
+	0x53, 0xed, 0x4e, 0xa6, 0x77, 0x55, 0x53, 0x45, 0x17, 0x92, 0xd5, 0x27, 0x47, 0xec, 0xa5, 0x64,
+	0x65, 0x87, 0x07, 0xec, 0xd5, 0x61, 0x13, 0x43, 0x62, 0xaa, 0x01, 0xff, 0x4e, 0xf2, 0xfa, 0xe4,
+	0x05, 0xaf, 0xd4, 0x7f, 0x82, 0x92, 0xbe, 0x15, 0x86, 0xb0, 0x05, 0xd3, 0x36, 0x0f, 0x1c, 0x23,
+	0xc8, 0x3c, 0xb2, 0x5d, 0xf0, 0x6a, 0x17, 0xa9, 0x7c, 0xc4, 0x17, 0x90, 0x7b, 0xb2, 0x57, 0x5b,
+	0x16, 0xbc, 0xb6, 0x25, 0x1a, 0x82, 0xfa, 0x14, 0x2a, 0xba, 0xe7, 0x3c, 0xd9, 0x82, 0x75, 0x6d,
+	0x61, 0x07, 0xa9, 0xff, 0x07, 0x98, 0xf1, 0xd5, 0x8a, 0xcd, 0x84, 0xc3, 0x37, 0x91, 0xc3, 0x1e,
+	0x13, 0x5b, 0xa7, 0xff, 0xc1, 0x3a, 0xb3, 0x6f, 0xfd, 0x0d, 0xa0, 0x1e, 0x0b, 0x4b, 0x1a, 0x32,
+	0x61, 0xcf, 0x6d, 0x61, 0xbf, 0x2c, 0xab, 0xfe, 0x0b, 0x20, 0x7d, 0xfb, 0x5f, 0x2a, 0xfc, 0x01,
+	0x0a, 0xeb, 0x28, 0x1a, 0x7d, 0x76, 0x97, 0xc9, 0xf7, 0xb0, 0x9f, 0x4a, 0x13, 0x59, 0xfd, 0x23,
+	0x9c, 0x1d, 0xba, 0x2a, 0x90, 0x97, 0xc1, 0x67, 0xe7, 0x18, 0xbe, 0x32, 0x9a, 0x1b, 0x38, 0x3f,
+	0xf4, 0x66, 0xfe, 0x76, 0x25, 0x70, 0x0b, 0xf2, 0x6c, 0x23, 0x3c, 0x87, 0xf9, 0x4a, 0xaa, 0x96,
+	0x79, 0xbd, 0x92, 0x58, 0x55, 0xb7, 0xa1, 0x12, 0xcf, 0xe1, 0x7a, 0x47, 0xed, 0xcd, 0x82, 0xe1,
+	0x2a, 0x14, 0x7c, 0x61, 0x7b, 0xa2, 0x9f, 0xd4, 0x92, 0x60, 0x7c, 0x05, 0x27, 0x6c, 0x33, 0xef,
+	0x27, 0x13, 0x8e, 0x90, 0xcc, 0x49, 0x46, 0x10, 0xce, 0xf9, 0xb9, 0xd7, 0x6b, 0x28, 0xf7, 0x98,
+	0xf8, 0xb4, 0x65, 0xde, 0x2e, 0xaa, 0xf2, 0x02, 0x72, 0xbf, 0x49, 0x18, 0xd9, 0x87, 0xe0, 0xc0,
+	0x23, 0x7d, 0xe4, 0xd1, 0x83, 0xb3, 0xc0, 0x20, 0x99, 0x57, 0x15, 0x0a, 0xae, 0xbd, 0x60, 0x86,
+	0xf3, 0x7b, 0x78, 0x44, 0xe6, 0x68, 0x82, 0x65, 0xec, 0x9e, 0xf3, 0xc7, 0xb5, 0xed, 0x3d, 0x46,
+	0x65, 0x26, 0x38, 0xda, 0xf7, 0x5b, 0xc7, 0x17, 0xdc, 0xdb, 0xdd, 0x70, 0x4f, 0x16, 0xff, 0x72,
+	0xdf, 0x6b, 0x50, 0x0e, 0x96, 0x0b, 0xe6, 0x32, 0x62, 0x5f, 0x04, 0x2e, 0x43, 0xda, 0x99, 0x47,
+	0x92, 0xb4, 0x33, 0xaf, 0x7f, 0x0d, 0x95, 0x67, 0x45, 0x67, 0xc5, 0x7d, 0xf6, 0x42, 0xf2, 0x23,
+	0xa0, 0xbd, 0xa6, 0xaf, 0x77, 0x82, 0xf9, 0xb8, 0x06, 0xa7, 0xde, 0x33, 0x0c, 0xc4, 0x25, 0xba,
+	0x4f, 0xd5, 0xff, 0x48, 0x45, 0xad, 0x52, 0xe6, 0xbb, 0x7c, 0xe3, 0x33, 0xdc, 0x86, 0x7c, 0x28,
+	0x88, 0xf7, 0x54, 0x89, 0xf7, 0xf4, 0xd8, 0x9e, 0xc6, 0x42, 0xfc, 0x1e, 0x0a, 0x4b, 0xdb, 0xb7,
+	0xd6, 0xdc, 0x0b, 0xdf, 0x9b, 0x02, 0xcd, 0x2f, 0x6d, 0x7f, 0xc8, 0xbd, 0xb8, 0xcc, 0x4c, 0x5c,
+	0xe6, 0xc1, 0xd8, 0xb3, 0x47, 0x63, 0x5f, 0xc0, 0xe5, 0x41, 0x2d, 0xc9, 0xf8, 0xdb, 0x70, 0xf9,
+	0xc0, 0xc4, 0x6c, 0xc9, 0xe6, 0x96, 0xc7, 0x66, 0xdc, 0x9b, 0xfb, 0xd6, 0x8c, 0x6f, 0x37, 0x22,
+	0xda, 0x8b, 0xf3, 0x28, 0x48, 0xc3, 0x58, 0x47, 0x86, 0xfe, 0x6d, 0x5b, 0xbe, 0x6b, 0x40, 0x49,
+	0x7a, 0xcb, 0xcf, 0xbc, 0xcf, 0x76, 0x3e, 0x56, 0xe0, 0xe2, 0x4e, 0x1d, 0x68, 0x5d, 0x55, 0x9e,
+	0xd0, 0x96, 0xae, 0x52, 0x75, 0x48, 0xe4, 0x09, 0xff, 0xa6, 0xfd, 0x79, 0xef, 0xae, 0x34, 0xb6,
+	0xae, 0xcb, 0x3d, 0x81, 0xbb, 0x50, 0xa0, 0x6c, 0xe1, 0xf8, 0x82, 0x79, 0x58, 0x79, 0xed, 0xa6,
+	0xac, 0xbe, 0x1a, 0xa9, 0xbf, 0x69, 0xa4, 0xbe, 0x4f, 0x5d, 0x8f, 0xa1, 0xce, 0xbd, 0x45, 0x73,
+	0xb9, 0x73, 0x99, 0xb7, 0x62, 0xf3, 0x05, 0xf3, 0x9a, 0x0f, 0xf6, 0xbd, 0xe7, 0xcc, 0xe2, 0x3c,
+	0x79, 0xb9, 0xff, 0xfa, 0xed, 0xc2, 0x11, 0xcb, 0xed, 0x7d, 0x73, 0xc6, 0xd7, 0xad, 0x3d, 0x69,
+	0x2b, 0x94, 0x86, 0x97, 0xbc, 0xdf, 0x92, 0xd2, 0xfb, 0xf0, 0x1f, 0xc3, 0x0f, 0x7f, 0x0f, 0x00,
+	0xb5, 0x54, 0x6a, 0xf5, 0x55, 0x08, 0x00, 0x00,
 }
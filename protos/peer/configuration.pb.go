@@ -57,25 +57,74 @@ func (m *AnchorPeer) GetPort() int32 {
 	return 0
 }
 
+// ACLs maps the names of the resources of the peer to the policies which
+// determine the access to them on a channel
+type ACLs struct {
+	Acls map[string]*APIResource `protobuf:"bytes,1,rep,name=acls" json:"acls,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *ACLs) Reset()                    { *m = ACLs{} }
+func (m *ACLs) String() string            { return proto.CompactTextString(m) }
+func (*ACLs) ProtoMessage()               {}
+func (*ACLs) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{2} }
+
+func (m *ACLs) GetAcls() map[string]*APIResource {
+	if m != nil {
+		return m.Acls
+	}
+	return nil
+}
+
+// APIResource represents a resource of the peer, whose access is granted to
+// the requests satisfying one of the policies it refers to
+type APIResource struct {
+	// The references to the policies: the absolute paths of channel policies,
+	// such as /Channel/Application/Readers, or the names of the policies of
+	// the local MSP, Admins or Members
+	PolicyRefs []string `protobuf:"bytes,1,rep,name=policy_refs,json=policyRefs" json:"policy_refs,omitempty"`
+}
+
+func (m *APIResource) Reset()                    { *m = APIResource{} }
+func (m *APIResource) String() string            { return proto.CompactTextString(m) }
+func (*APIResource) ProtoMessage()               {}
+func (*APIResource) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{3} }
+
+func (m *APIResource) GetPolicyRefs() []string {
+	if m != nil {
+		return m.PolicyRefs
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*AnchorPeers)(nil), "protos.AnchorPeers")
 	proto.RegisterType((*AnchorPeer)(nil), "protos.AnchorPeer")
+	proto.RegisterType((*ACLs)(nil), "protos.ACLs")
+	proto.RegisterMapType((map[string]*APIResource)(nil), "protos.ACLs.AclsEntry")
+	proto.RegisterType((*APIResource)(nil), "protos.APIResource")
 }
 
 func init() { proto.RegisterFile("peer/configuration.proto", fileDescriptor4) }
 
 var fileDescriptor4 = []byte{
-	// 192 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x8f, 0x3d, 0x8f, 0xc3, 0x20,
-	0x0c, 0x86, 0xc5, 0x7d, 0x49, 0x47, 0x6e, 0x62, 0x62, 0x8c, 0x32, 0xe5, 0x16, 0x90, 0xee, 0xe3,
-	0x07, 0xb4, 0xea, 0xde, 0x2a, 0x63, 0x97, 0x8a, 0x50, 0x02, 0x48, 0x6d, 0x8c, 0x0c, 0x19, 0xfa,
-	0xef, 0x2b, 0x40, 0x55, 0x3a, 0xf1, 0x62, 0x3f, 0x8f, 0x6c, 0x53, 0x1e, 0x8c, 0x41, 0xa9, 0x61,
-	0x9e, 0xbc, 0x5d, 0x50, 0x25, 0x0f, 0xb3, 0x08, 0x08, 0x09, 0xd8, 0x47, 0x79, 0x62, 0xb7, 0xa3,
-	0xcd, 0x66, 0xd6, 0x0e, 0xf0, 0x60, 0x0c, 0x46, 0xf6, 0x4f, 0xbf, 0x54, 0xf9, 0x9e, 0xb2, 0x19,
-	0x39, 0x69, 0x5f, 0xfb, 0xe6, 0x87, 0x55, 0x29, 0x8a, 0x15, 0x1d, 0x1a, 0xb5, 0x6a, 0xdd, 0x1f,
-	0xa5, 0x6b, 0x8b, 0x31, 0xfa, 0xe6, 0x20, 0x26, 0x4e, 0x5a, 0xd2, 0x7f, 0x0e, 0x25, 0xe7, 0x5a,
-	0x00, 0x4c, 0xfc, 0xa5, 0x25, 0xfd, 0xfb, 0x50, 0xf2, 0x76, 0x4f, 0x3b, 0x40, 0x2b, 0xdc, 0x2d,
-	0x18, 0xbc, 0x98, 0xb3, 0x35, 0x28, 0x26, 0x35, 0xa2, 0xd7, 0x8f, 0x71, 0x79, 0x87, 0xe3, 0xb7,
-	0xf5, 0xc9, 0x2d, 0xa3, 0xd0, 0x70, 0x95, 0x4f, 0xa8, 0xac, 0xa8, 0xac, 0xa8, 0xcc, 0xe8, 0x58,
-	0x8f, 0xfa, 0xbd, 0x07, 0x00, 0x00, 0xff, 0xff, 0x6d, 0xf2, 0x7b, 0x6f, 0xf7, 0x00, 0x00, 0x00,
+	// 291 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x91, 0x4b, 0x4b, 0x03, 0x31,
+	0x14, 0x85, 0x99, 0x3e, 0x84, 0xde, 0xb8, 0x90, 0x08, 0x32, 0xb8, 0xb1, 0xcc, 0xaa, 0x75, 0x91,
+	0x81, 0xaa, 0x20, 0xee, 0x6a, 0x75, 0x21, 0x14, 0x2c, 0x59, 0xba, 0x29, 0x69, 0xbc, 0xf3, 0xc0,
+	0x71, 0x32, 0xdc, 0x64, 0x84, 0xd9, 0xf9, 0xd3, 0x65, 0x12, 0xfb, 0x58, 0xe5, 0xe4, 0xdc, 0xef,
+	0xe4, 0x84, 0x04, 0xe2, 0x06, 0x91, 0x52, 0x6d, 0xea, 0xac, 0xcc, 0x5b, 0x52, 0xae, 0x34, 0xb5,
+	0x68, 0xc8, 0x38, 0xc3, 0xcf, 0xfc, 0x62, 0x93, 0x17, 0x60, 0xcb, 0x5a, 0x17, 0x86, 0x36, 0x88,
+	0x64, 0xf9, 0x03, 0x9c, 0x2b, 0xbf, 0xdd, 0xf6, 0x49, 0x1b, 0x47, 0xd3, 0xe1, 0x8c, 0x2d, 0x78,
+	0x08, 0x59, 0x71, 0x44, 0x25, 0x53, 0xc7, 0x58, 0x72, 0x0f, 0x70, 0x1c, 0x71, 0x0e, 0xa3, 0xc2,
+	0x58, 0x17, 0x47, 0xd3, 0x68, 0x36, 0x91, 0x5e, 0xf7, 0x5e, 0x63, 0xc8, 0xc5, 0x83, 0x69, 0x34,
+	0x1b, 0x4b, 0xaf, 0x93, 0xdf, 0x08, 0x46, 0xcb, 0xd5, 0xda, 0xf2, 0x5b, 0x18, 0x29, 0x5d, 0xed,
+	0xdb, 0xae, 0x0e, 0x6d, 0xab, 0xb5, 0x15, 0x4b, 0x5d, 0xd9, 0xd7, 0xda, 0x51, 0x27, 0x3d, 0x73,
+	0xbd, 0x86, 0xc9, 0xc1, 0xe2, 0x17, 0x30, 0xfc, 0xc2, 0xee, 0xbf, 0xa8, 0x97, 0x7c, 0x0e, 0xe3,
+	0x1f, 0x55, 0xb5, 0xe8, 0x8b, 0xd8, 0xe2, 0xf2, 0x70, 0xd6, 0xe6, 0x4d, 0xa2, 0x35, 0x2d, 0x69,
+	0x94, 0x81, 0x78, 0x1a, 0x3c, 0x46, 0x89, 0x00, 0x76, 0x32, 0xe1, 0x37, 0xc0, 0x1a, 0x53, 0x95,
+	0xba, 0xdb, 0x12, 0x66, 0xe1, 0x3e, 0x13, 0x09, 0xc1, 0x92, 0x98, 0xd9, 0xe7, 0x77, 0x48, 0x0c,
+	0xe5, 0xa2, 0xe8, 0x1a, 0xa4, 0x0a, 0x3f, 0x73, 0x24, 0x91, 0xa9, 0x1d, 0x95, 0x7a, 0xdf, 0xd3,
+	0x3f, 0xdb, 0xc7, 0x3c, 0x2f, 0x5d, 0xd1, 0xee, 0x84, 0x36, 0xdf, 0xe9, 0x09, 0x9a, 0x06, 0x34,
+	0x0d, 0x68, 0xda, 0xa3, 0xbb, 0xf0, 0x0f, 0x77, 0x7f, 0x03, 0x00, 0x77, 0x29, 0x5c, 0x36, 0xaa,
+	0x01, 0x00, 0x00,
 }
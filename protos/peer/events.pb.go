@@ -7,6 +7,7 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import common "github.com/hyperledger/fabric/protos/common"
+import orderer "github.com/hyperledger/fabric/protos/orderer"
 
 import (
 	context "golang.org/x/net/context"
@@ -21,10 +22,11 @@ var _ = math.Inf
 type EventType int32
 
 const (
-	EventType_REGISTER  EventType = 0
-	EventType_BLOCK     EventType = 1
-	EventType_CHAINCODE EventType = 2
-	EventType_REJECTION EventType = 3
+	EventType_REGISTER      EventType = 0
+	EventType_BLOCK         EventType = 1
+	EventType_CHAINCODE     EventType = 2
+	EventType_REJECTION     EventType = 3
+	EventType_FILTEREDBLOCK EventType = 4
 )
 
 var EventType_name = map[int32]string{
@@ -32,12 +34,14 @@ var EventType_name = map[int32]string{
 	1: "BLOCK",
 	2: "CHAINCODE",
 	3: "REJECTION",
+	4: "FILTEREDBLOCK",
 }
 var EventType_value = map[string]int32{
-	"REGISTER":  0,
-	"BLOCK":     1,
-	"CHAINCODE": 2,
-	"REJECTION": 3,
+	"REGISTER":      0,
+	"BLOCK":         1,
+	"CHAINCODE":     2,
+	"REJECTION":     3,
+	"FILTEREDBLOCK": 4,
 }
 
 func (x EventType) String() string {
@@ -228,6 +232,107 @@ func (m *Rejection) GetErrorMsg() string {
 }
 
 // ---------- producer events ---------
+// FilteredBlock is sent to the consumers registered for FILTEREDBLOCK
+// events. Unlike the block, it does not carry the read/write sets nor
+// the payloads of the transactions and of the chaincode events
+type FilteredBlock struct {
+	ChannelId  string                 `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	Number     uint64                 `protobuf:"varint,2,opt,name=number" json:"number,omitempty"`
+	FilteredTx []*FilteredTransaction `protobuf:"bytes,3,rep,name=filtered_tx,json=filteredTx" json:"filtered_tx,omitempty"`
+}
+
+func (m *FilteredBlock) Reset()                    { *m = FilteredBlock{} }
+func (m *FilteredBlock) String() string            { return proto.CompactTextString(m) }
+func (*FilteredBlock) ProtoMessage()               {}
+func (*FilteredBlock) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{4} }
+
+func (m *FilteredBlock) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *FilteredBlock) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *FilteredBlock) GetFilteredTx() []*FilteredTransaction {
+	if m != nil {
+		return m.FilteredTx
+	}
+	return nil
+}
+
+// FilteredTransaction is the summary of a transaction of a FilteredBlock
+type FilteredTransaction struct {
+	Txid             string                    `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Type             common.HeaderType         `protobuf:"varint,2,opt,name=type,enum=common.HeaderType" json:"type,omitempty"`
+	TxValidationCode TxValidationCode          `protobuf:"varint,3,opt,name=tx_validation_code,json=txValidationCode,enum=protos.TxValidationCode" json:"tx_validation_code,omitempty"`
+	ChaincodeEvents  []*FilteredChaincodeEvent `protobuf:"bytes,4,rep,name=chaincode_events,json=chaincodeEvents" json:"chaincode_events,omitempty"`
+}
+
+func (m *FilteredTransaction) Reset()                    { *m = FilteredTransaction{} }
+func (m *FilteredTransaction) String() string            { return proto.CompactTextString(m) }
+func (*FilteredTransaction) ProtoMessage()               {}
+func (*FilteredTransaction) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{5} }
+
+func (m *FilteredTransaction) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *FilteredTransaction) GetType() common.HeaderType {
+	if m != nil {
+		return m.Type
+	}
+	return common.HeaderType_MESSAGE
+}
+
+func (m *FilteredTransaction) GetTxValidationCode() TxValidationCode {
+	if m != nil {
+		return m.TxValidationCode
+	}
+	return TxValidationCode_VALID
+}
+
+func (m *FilteredTransaction) GetChaincodeEvents() []*FilteredChaincodeEvent {
+	if m != nil {
+		return m.ChaincodeEvents
+	}
+	return nil
+}
+
+// FilteredChaincodeEvent identifies a chaincode event set by a transaction
+type FilteredChaincodeEvent struct {
+	ChaincodeId string `protobuf:"bytes,1,opt,name=chaincode_id,json=chaincodeId" json:"chaincode_id,omitempty"`
+	EventName   string `protobuf:"bytes,2,opt,name=event_name,json=eventName" json:"event_name,omitempty"`
+}
+
+func (m *FilteredChaincodeEvent) Reset()                    { *m = FilteredChaincodeEvent{} }
+func (m *FilteredChaincodeEvent) String() string            { return proto.CompactTextString(m) }
+func (*FilteredChaincodeEvent) ProtoMessage()               {}
+func (*FilteredChaincodeEvent) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{6} }
+
+func (m *FilteredChaincodeEvent) GetChaincodeId() string {
+	if m != nil {
+		return m.ChaincodeId
+	}
+	return ""
+}
+
+func (m *FilteredChaincodeEvent) GetEventName() string {
+	if m != nil {
+		return m.EventName
+	}
+	return ""
+}
+
 type Unregister struct {
 	Events []*Interest `protobuf:"bytes,1,rep,name=events" json:"events,omitempty"`
 }
@@ -235,7 +340,7 @@ type Unregister struct {
 func (m *Unregister) Reset()                    { *m = Unregister{} }
 func (m *Unregister) String() string            { return proto.CompactTextString(m) }
 func (*Unregister) ProtoMessage()               {}
-func (*Unregister) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{4} }
+func (*Unregister) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{7} }
 
 func (m *Unregister) GetEvents() []*Interest {
 	if m != nil {
@@ -255,7 +360,7 @@ type SignedEvent struct {
 func (m *SignedEvent) Reset()                    { *m = SignedEvent{} }
 func (m *SignedEvent) String() string            { return proto.CompactTextString(m) }
 func (*SignedEvent) ProtoMessage()               {}
-func (*SignedEvent) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{5} }
+func (*SignedEvent) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{8} }
 
 func (m *SignedEvent) GetSignature() []byte {
 	if m != nil {
@@ -281,6 +386,7 @@ type Event struct {
 	//	*Event_ChaincodeEvent
 	//	*Event_Rejection
 	//	*Event_Unregister
+	//	*Event_FilteredBlock
 	Event isEvent_Event `protobuf_oneof:"Event"`
 	// Creator of the event, specified as a certificate chain
 	Creator []byte `protobuf:"bytes,6,opt,name=creator,proto3" json:"creator,omitempty"`
@@ -289,7 +395,7 @@ type Event struct {
 func (m *Event) Reset()                    { *m = Event{} }
 func (m *Event) String() string            { return proto.CompactTextString(m) }
 func (*Event) ProtoMessage()               {}
-func (*Event) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{6} }
+func (*Event) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{9} }
 
 type isEvent_Event interface {
 	isEvent_Event()
@@ -310,12 +416,16 @@ type Event_Rejection struct {
 type Event_Unregister struct {
 	Unregister *Unregister `protobuf:"bytes,5,opt,name=unregister,oneof"`
 }
+type Event_FilteredBlock struct {
+	FilteredBlock *FilteredBlock `protobuf:"bytes,7,opt,name=filtered_block,json=filteredBlock,oneof"`
+}
 
 func (*Event_Register) isEvent_Event()       {}
 func (*Event_Block) isEvent_Event()          {}
 func (*Event_ChaincodeEvent) isEvent_Event() {}
 func (*Event_Rejection) isEvent_Event()      {}
 func (*Event_Unregister) isEvent_Event()     {}
+func (*Event_FilteredBlock) isEvent_Event()  {}
 
 func (m *Event) GetEvent() isEvent_Event {
 	if m != nil {
@@ -359,6 +469,13 @@ func (m *Event) GetUnregister() *Unregister {
 	return nil
 }
 
+func (m *Event) GetFilteredBlock() *FilteredBlock {
+	if x, ok := m.GetEvent().(*Event_FilteredBlock); ok {
+		return x.FilteredBlock
+	}
+	return nil
+}
+
 func (m *Event) GetCreator() []byte {
 	if m != nil {
 		return m.Creator
@@ -374,6 +491,7 @@ func (*Event) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error,
 		(*Event_ChaincodeEvent)(nil),
 		(*Event_Rejection)(nil),
 		(*Event_Unregister)(nil),
+		(*Event_FilteredBlock)(nil),
 	}
 }
 
@@ -406,6 +524,11 @@ func _Event_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Unregister); err != nil {
 			return err
 		}
+	case *Event_FilteredBlock:
+		b.EncodeVarint(7<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.FilteredBlock); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Event.Event has unexpected type %T", x)
@@ -456,6 +579,14 @@ func _Event_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer)
 		err := b.DecodeMessage(msg)
 		m.Event = &Event_Unregister{msg}
 		return true, err
+	case 7: // Event.filtered_block
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FilteredBlock)
+		err := b.DecodeMessage(msg)
+		m.Event = &Event_FilteredBlock{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -490,6 +621,11 @@ func _Event_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(5<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *Event_FilteredBlock:
+		s := proto.Size(x.FilteredBlock)
+		n += proto.SizeVarint(7<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -502,6 +638,9 @@ func init() {
 	proto.RegisterType((*Interest)(nil), "protos.Interest")
 	proto.RegisterType((*Register)(nil), "protos.Register")
 	proto.RegisterType((*Rejection)(nil), "protos.Rejection")
+	proto.RegisterType((*FilteredBlock)(nil), "protos.FilteredBlock")
+	proto.RegisterType((*FilteredTransaction)(nil), "protos.FilteredTransaction")
+	proto.RegisterType((*FilteredChaincodeEvent)(nil), "protos.FilteredChaincodeEvent")
 	proto.RegisterType((*Unregister)(nil), "protos.Unregister")
 	proto.RegisterType((*SignedEvent)(nil), "protos.SignedEvent")
 	proto.RegisterType((*Event)(nil), "protos.Event")
@@ -614,47 +753,162 @@ var _Events_serviceDesc = grpc.ServiceDesc{
 	Metadata: "peer/events.proto",
 }
 
+// Client API for Deliver service
+
+type DeliverClient interface {
+	// deliver first requires an Envelope of type DELIVER_SEEK_INFO with Payload data as a marshaled orderer.SeekInfo message,
+	// then a stream of block replies is received.
+	Deliver(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverClient, error)
+}
+
+type deliverClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDeliverClient(cc *grpc.ClientConn) DeliverClient {
+	return &deliverClient{cc}
+}
+
+func (c *deliverClient) Deliver(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Deliver_serviceDesc.Streams[0], c.cc, "/protos.Deliver/Deliver", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deliverDeliverClient{stream}
+	return x, nil
+}
+
+type Deliver_DeliverClient interface {
+	Send(*common.Envelope) error
+	Recv() (*orderer.DeliverResponse, error)
+	grpc.ClientStream
+}
+
+type deliverDeliverClient struct {
+	grpc.ClientStream
+}
+
+func (x *deliverDeliverClient) Send(m *common.Envelope) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *deliverDeliverClient) Recv() (*orderer.DeliverResponse, error) {
+	m := new(orderer.DeliverResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Deliver service
+
+type DeliverServer interface {
+	// deliver first requires an Envelope of type DELIVER_SEEK_INFO with Payload data as a marshaled orderer.SeekInfo message,
+	// then a stream of block replies is received.
+	Deliver(Deliver_DeliverServer) error
+}
+
+func RegisterDeliverServer(s *grpc.Server, srv DeliverServer) {
+	s.RegisterService(&_Deliver_serviceDesc, srv)
+}
+
+func _Deliver_Deliver_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DeliverServer).Deliver(&deliverDeliverServer{stream})
+}
+
+type Deliver_DeliverServer interface {
+	Send(*orderer.DeliverResponse) error
+	Recv() (*common.Envelope, error)
+	grpc.ServerStream
+}
+
+type deliverDeliverServer struct {
+	grpc.ServerStream
+}
+
+func (x *deliverDeliverServer) Send(m *orderer.DeliverResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *deliverDeliverServer) Recv() (*common.Envelope, error) {
+	m := new(common.Envelope)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Deliver_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "protos.Deliver",
+	HandlerType: (*DeliverServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Deliver",
+			Handler:       _Deliver_Deliver_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "peer/events.proto",
+}
+
 func init() { proto.RegisterFile("peer/events.proto", fileDescriptor5) }
 
 var fileDescriptor5 = []byte{
-	// 611 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x54, 0x4f, 0x6f, 0x9b, 0x4e,
-	0x10, 0x05, 0x27, 0x76, 0xcc, 0xd8, 0xce, 0xcf, 0xd9, 0xfc, 0x14, 0x21, 0xf7, 0x8f, 0x52, 0xaa,
-	0x4a, 0x6e, 0x0f, 0x26, 0xa5, 0x51, 0x0f, 0xbd, 0x05, 0x82, 0x0a, 0x4d, 0xf3, 0x47, 0x1b, 0xf7,
-	0xd2, 0x43, 0x2d, 0x8c, 0x27, 0x98, 0x26, 0x06, 0x6b, 0xd9, 0x54, 0xf1, 0x27, 0xea, 0xa9, 0xdf,
-	0xb1, 0x62, 0x61, 0x81, 0xb4, 0xa7, 0x9e, 0xf0, 0xcc, 0xbc, 0x37, 0xfb, 0xf6, 0xcd, 0xac, 0x61,
-	0x6f, 0x8d, 0xc8, 0x4c, 0xfc, 0x81, 0x09, 0xcf, 0x26, 0x6b, 0x96, 0xf2, 0x94, 0x74, 0xc4, 0x27,
-	0x1b, 0xed, 0x87, 0xe9, 0x6a, 0x95, 0x26, 0x66, 0xf1, 0x29, 0x8a, 0xa3, 0x91, 0xc0, 0x87, 0xcb,
-	0x20, 0x4e, 0xc2, 0x74, 0x81, 0x33, 0xc1, 0x2c, 0x6b, 0x07, 0xa2, 0xc6, 0x59, 0x90, 0x64, 0x41,
-	0xc8, 0x63, 0xc9, 0x31, 0xae, 0xa0, 0xef, 0x48, 0x02, 0xc5, 0x88, 0xbc, 0x80, 0x7e, 0xdd, 0x20,
-	0x5e, 0xe8, 0xea, 0xa1, 0x3a, 0xd6, 0x68, 0xaf, 0xca, 0xf9, 0x0b, 0xf2, 0x0c, 0x40, 0x74, 0x9e,
-	0x25, 0xc1, 0x0a, 0xf5, 0x96, 0x00, 0x68, 0x22, 0x73, 0x11, 0xac, 0xd0, 0xf8, 0xa9, 0x42, 0xd7,
-	0x4f, 0x38, 0x32, 0xcc, 0x38, 0x39, 0x92, 0x58, 0xbe, 0x59, 0xa3, 0x68, 0xb6, 0x6b, 0xed, 0x15,
-	0x47, 0x67, 0x13, 0x37, 0xaf, 0x4c, 0x37, 0x6b, 0x2c, 0xe9, 0xf9, 0x4f, 0x72, 0x0a, 0xa4, 0x16,
-	0xc0, 0x30, 0x9a, 0xc5, 0xc9, 0x4d, 0x2a, 0x4e, 0xe9, 0x59, 0xff, 0x4b, 0x66, 0x53, 0xb2, 0xa7,
-	0xd0, 0x61, 0xd8, 0x88, 0xfd, 0xe4, 0x26, 0x25, 0x3a, 0xec, 0x88, 0x9c, 0x7f, 0xaa, 0x6f, 0x09,
-	0x81, 0x32, 0xb4, 0x35, 0xd8, 0x29, 0x41, 0xc6, 0x31, 0x74, 0x29, 0x46, 0x71, 0xc6, 0x91, 0x91,
-	0x31, 0x74, 0x0a, 0xa3, 0x75, 0xf5, 0x70, 0x6b, 0xdc, 0xb3, 0x86, 0xf2, 0x28, 0x79, 0x15, 0x5a,
-	0xd6, 0x8d, 0x73, 0xd0, 0x28, 0x7e, 0x47, 0x61, 0x22, 0x79, 0x09, 0x2d, 0xfe, 0x20, 0xee, 0xd5,
-	0xb3, 0xf6, 0x25, 0x65, 0x5a, 0xbb, 0x4c, 0x5b, 0xfc, 0x81, 0x3c, 0x01, 0x0d, 0x19, 0x4b, 0xd9,
-	0x6c, 0x95, 0x45, 0xa5, 0x5f, 0x5d, 0x91, 0x38, 0xcf, 0x22, 0xe3, 0x3d, 0xc0, 0x97, 0x84, 0xfd,
-	0xbb, 0x8c, 0x33, 0xe8, 0x5d, 0xc7, 0x51, 0x82, 0x0b, 0xe1, 0x22, 0x79, 0x0a, 0x5a, 0x16, 0x47,
-	0x49, 0xc0, 0xef, 0x59, 0xe1, 0x73, 0x9f, 0xd6, 0x09, 0xf2, 0xbc, 0x1c, 0x83, 0xbd, 0xe1, 0x98,
-	0x09, 0x09, 0x7d, 0xda, 0xc8, 0x18, 0xbf, 0x5a, 0xd0, 0x2e, 0xfa, 0x4c, 0xa0, 0x2b, 0xc5, 0x94,
-	0xd7, 0xaa, 0x24, 0x48, 0xaf, 0x3c, 0x85, 0x56, 0x18, 0xf2, 0x0a, 0xda, 0xf3, 0xbb, 0x34, 0xbc,
-	0x2d, 0x27, 0x34, 0x98, 0x94, 0x1b, 0x69, 0xe7, 0x49, 0x4f, 0xa1, 0x45, 0x95, 0x9c, 0xc0, 0x7f,
-	0x7f, 0xec, 0xa5, 0x98, 0x4b, 0xcf, 0x3a, 0xf8, 0x6b, 0xa4, 0x42, 0x87, 0xa7, 0xd0, 0xdd, 0xf0,
-	0x51, 0x86, 0xbc, 0x05, 0x8d, 0x49, 0xdf, 0xf5, 0x6d, 0x41, 0xde, 0xab, 0xa5, 0x95, 0x05, 0x4f,
-	0xa1, 0x35, 0x8a, 0x1c, 0x03, 0xdc, 0x57, 0xde, 0xea, 0x6d, 0xc1, 0x21, 0x92, 0x53, 0xbb, 0xee,
-	0x29, 0xb4, 0x81, 0x13, 0xbb, 0xc3, 0x30, 0xe0, 0x29, 0xd3, 0x3b, 0xc2, 0x29, 0x19, 0xda, 0x3b,
-	0xa5, 0x4b, 0x6f, 0x6c, 0xd0, 0xaa, 0xe5, 0x25, 0x7d, 0xe8, 0x52, 0xf7, 0xa3, 0x7f, 0x3d, 0x75,
-	0xe9, 0x50, 0x21, 0x1a, 0xb4, 0xed, 0xcf, 0x97, 0xce, 0xd9, 0x50, 0x25, 0x03, 0xd0, 0x1c, 0xef,
-	0xc4, 0xbf, 0x70, 0x2e, 0x4f, 0xdd, 0x61, 0x2b, 0x0f, 0xa9, 0xfb, 0xc9, 0x75, 0xa6, 0xfe, 0xe5,
-	0xc5, 0x70, 0xcb, 0xfa, 0x00, 0x1d, 0xd1, 0x23, 0x23, 0x47, 0xb0, 0xed, 0x2c, 0x03, 0x4e, 0xaa,
-	0x05, 0x6a, 0x0c, 0x76, 0x34, 0x78, 0xf4, 0x5a, 0x0c, 0x65, 0xac, 0x1e, 0xa9, 0xf6, 0x37, 0x30,
-	0x52, 0x16, 0x4d, 0x96, 0x9b, 0x35, 0xb2, 0x3b, 0x5c, 0x44, 0xc8, 0x26, 0x37, 0xc1, 0x9c, 0xc5,
-	0xa1, 0x04, 0xe7, 0xaf, 0xdd, 0x1e, 0x14, 0xfd, 0xaf, 0x82, 0xf0, 0x36, 0x88, 0xf0, 0xeb, 0xeb,
-	0x28, 0xe6, 0xcb, 0xfb, 0x79, 0x3e, 0x21, 0xb3, 0xc1, 0x34, 0x0b, 0xa6, 0x59, 0x30, 0xcd, 0x9c,
-	0x39, 0x2f, 0xfe, 0x66, 0xde, 0xfd, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x19, 0x83, 0x34, 0xd3, 0x82,
-	0x04, 0x00, 0x00,
+	// 856 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x55, 0x51, 0x8f, 0xda, 0x46,
+	0x10, 0x06, 0x8e, 0x03, 0x3c, 0xc0, 0xc5, 0x37, 0xd7, 0x9e, 0x2c, 0xd2, 0x46, 0xa9, 0xab, 0x56,
+	0xd7, 0x3e, 0xc0, 0x95, 0x46, 0x7d, 0x68, 0xab, 0x4a, 0x01, 0x7c, 0xc5, 0x4d, 0x72, 0x17, 0x6d,
+	0x48, 0x1f, 0xf2, 0x50, 0x64, 0xec, 0xc1, 0xb8, 0x01, 0x1b, 0xad, 0xf7, 0x4e, 0xdc, 0x7b, 0xff,
+	0x4b, 0xff, 0x58, 0x5f, 0xfb, 0x1f, 0x2a, 0xaf, 0x77, 0x6d, 0x8e, 0xe6, 0xa5, 0x52, 0x9f, 0xf0,
+	0xce, 0xcc, 0x37, 0xf3, 0xcd, 0xcc, 0xb7, 0x0b, 0x9c, 0x6e, 0x89, 0xf8, 0x80, 0xee, 0x28, 0x16,
+	0x69, 0x7f, 0xcb, 0x13, 0x91, 0x60, 0x43, 0xfe, 0xa4, 0xbd, 0x33, 0x3f, 0xd9, 0x6c, 0x92, 0x78,
+	0x90, 0xff, 0xe4, 0xce, 0x9e, 0x99, 0xf0, 0x80, 0x38, 0xf1, 0x81, 0xb7, 0x50, 0x96, 0x9e, 0xcc,
+	0xe0, 0xaf, 0xbc, 0x28, 0xf6, 0x93, 0x80, 0xe6, 0x32, 0x97, 0xf2, 0x9d, 0x4b, 0x9f, 0xe0, 0x5e,
+	0x9c, 0x7a, 0xbe, 0x88, 0x74, 0x16, 0xfb, 0x35, 0x74, 0xc6, 0x1a, 0xc0, 0x28, 0xc4, 0xcf, 0xa0,
+	0x53, 0x26, 0x88, 0x02, 0xab, 0xfa, 0xb4, 0x7a, 0x61, 0xb0, 0x76, 0x61, 0x73, 0x03, 0xfc, 0x14,
+	0x40, 0x66, 0x9e, 0xc7, 0xde, 0x86, 0xac, 0x9a, 0x0c, 0x30, 0xa4, 0xe5, 0xda, 0xdb, 0x90, 0xfd,
+	0x67, 0x15, 0x5a, 0x6e, 0x2c, 0x88, 0x53, 0x2a, 0xf0, 0x52, 0xc7, 0x8a, 0xfb, 0x2d, 0xc9, 0x64,
+	0x27, 0xc3, 0xd3, 0xbc, 0x74, 0xda, 0x77, 0x32, 0xcf, 0xec, 0x7e, 0x4b, 0x0a, 0x9e, 0x7d, 0xe2,
+	0x04, 0xb0, 0x24, 0xc0, 0x29, 0x9c, 0x47, 0xf1, 0x32, 0x91, 0x55, 0xda, 0xc3, 0x8f, 0x34, 0x72,
+	0x9f, 0xf2, 0xb4, 0xc2, 0x4c, 0x7f, 0xef, 0xec, 0xc6, 0xcb, 0x04, 0x2d, 0x68, 0x4a, 0x9b, 0x3b,
+	0xb1, 0x8e, 0x24, 0x41, 0x7d, 0x1c, 0x19, 0xd0, 0x54, 0x41, 0xf6, 0x33, 0x68, 0x31, 0x0a, 0xa3,
+	0x54, 0x10, 0xc7, 0x0b, 0x68, 0xe4, 0xa3, 0xb7, 0xaa, 0x4f, 0x8f, 0x2e, 0xda, 0x43, 0x53, 0x97,
+	0xd2, 0xad, 0x30, 0xe5, 0xb7, 0x5f, 0x81, 0xc1, 0xe8, 0x77, 0x92, 0x43, 0xc4, 0xcf, 0xa1, 0x26,
+	0x76, 0xb2, 0xaf, 0xf6, 0xf0, 0x4c, 0x43, 0x66, 0xe5, 0x94, 0x59, 0x4d, 0xec, 0xf0, 0x31, 0x18,
+	0xc4, 0x79, 0xc2, 0xe7, 0x9b, 0x34, 0x54, 0xf3, 0x6a, 0x49, 0xc3, 0xab, 0x34, 0xb4, 0xff, 0xa8,
+	0x42, 0xf7, 0x2a, 0x5a, 0x67, 0x45, 0x82, 0xd1, 0x3a, 0xf1, 0xdf, 0x67, 0xf3, 0xf5, 0x57, 0x5e,
+	0x1c, 0xd3, 0xba, 0x5c, 0x80, 0xa1, 0x2c, 0x6e, 0x80, 0xe7, 0xd0, 0x88, 0x6f, 0x37, 0x0b, 0xe2,
+	0x32, 0x55, 0x9d, 0xa9, 0x13, 0xfe, 0x08, 0xed, 0xa5, 0xca, 0x33, 0x17, 0x3b, 0xeb, 0x48, 0xb6,
+	0xf1, 0x58, 0x73, 0xd2, 0x25, 0xf6, 0xb9, 0x81, 0x8e, 0x9f, 0xed, 0xec, 0xbf, 0xaa, 0x70, 0xf6,
+	0x81, 0x18, 0x44, 0xa8, 0x8b, 0x5d, 0x41, 0x43, 0x7e, 0xe3, 0x97, 0x50, 0x97, 0xeb, 0xac, 0xc9,
+	0x75, 0x62, 0x5f, 0xc9, 0x72, 0x4a, 0x5e, 0x40, 0x5c, 0xee, 0x53, 0xfa, 0xf1, 0x0a, 0x50, 0xec,
+	0xe6, 0x77, 0xde, 0x3a, 0x0a, 0xbc, 0x2c, 0xd9, 0x3c, 0xdb, 0x90, 0xdc, 0xc7, 0xc9, 0xd0, 0x2a,
+	0x86, 0xb5, 0xfb, 0xb5, 0x08, 0x18, 0x67, 0x1b, 0x34, 0xc5, 0x81, 0x05, 0x5d, 0x30, 0x0f, 0x44,
+	0x9d, 0x5a, 0x75, 0xd9, 0xde, 0x93, 0xc3, 0xf6, 0x0a, 0x61, 0x48, 0x6d, 0xb1, 0x47, 0xfe, 0x83,
+	0x73, 0x6a, 0xbf, 0x83, 0xf3, 0x0f, 0x87, 0xfe, 0x0f, 0xc2, 0xff, 0x0e, 0xe0, 0x6d, 0xcc, 0xff,
+	0xbb, 0xa0, 0x5e, 0x40, 0xfb, 0x4d, 0x14, 0xc6, 0x14, 0xe4, 0x44, 0x3e, 0x01, 0x23, 0x8d, 0xc2,
+	0xd8, 0x13, 0xb7, 0x3c, 0xbf, 0x31, 0x1d, 0x56, 0x1a, 0xf0, 0x89, 0xe2, 0x30, 0xba, 0x17, 0x94,
+	0x4a, 0x0e, 0x1d, 0xb6, 0x67, 0xb1, 0xff, 0xae, 0xc1, 0x71, 0x9e, 0xa7, 0x0f, 0x2d, 0x4d, 0x46,
+	0x09, 0xb4, 0xa0, 0xa0, 0x55, 0x3f, 0xad, 0xb0, 0x22, 0x06, 0xbf, 0x80, 0xe3, 0x45, 0xa6, 0x3f,
+	0x75, 0xd7, 0xba, 0x7a, 0xad, 0x52, 0x94, 0xd3, 0x0a, 0xcb, 0xbd, 0xf8, 0x1c, 0x1e, 0x1d, 0x2c,
+	0x43, 0x6e, 0xb4, 0x3d, 0x3c, 0xff, 0xd7, 0xe5, 0x94, 0x3c, 0xa6, 0x15, 0x76, 0xf2, 0x70, 0x0b,
+	0xf8, 0x0d, 0x18, 0x5c, 0xdf, 0x20, 0xab, 0x2e, 0xc1, 0xa7, 0x25, 0x35, 0xe5, 0x98, 0x56, 0x58,
+	0x19, 0x85, 0xcf, 0x00, 0x6e, 0x8b, 0xd9, 0x5a, 0xc7, 0x12, 0x83, 0x1a, 0x53, 0x4e, 0x7d, 0x5a,
+	0x61, 0x7b, 0x71, 0xf8, 0x13, 0x9c, 0x14, 0x57, 0x22, 0xef, 0xad, 0x29, 0x91, 0x1f, 0x1f, 0xca,
+	0x46, 0xf7, 0xd8, 0x5d, 0x3e, 0xb8, 0x89, 0xd9, 0x2b, 0xc2, 0xc9, 0x13, 0x09, 0xb7, 0x1a, 0x72,
+	0xd2, 0xfa, 0x38, 0x6a, 0xaa, 0x29, 0x7f, 0xfd, 0x16, 0x8c, 0xe2, 0x19, 0xc3, 0x0e, 0xb4, 0x98,
+	0xf3, 0xb3, 0xfb, 0x66, 0xe6, 0x30, 0xb3, 0x82, 0x06, 0x1c, 0x8f, 0x5e, 0xde, 0x8c, 0x5f, 0x98,
+	0x55, 0xec, 0x82, 0x31, 0x9e, 0x3e, 0x77, 0xaf, 0xc7, 0x37, 0x13, 0xc7, 0xac, 0x65, 0x47, 0xe6,
+	0xfc, 0xe2, 0x8c, 0x67, 0xee, 0xcd, 0xb5, 0x79, 0x84, 0xa7, 0xd0, 0xbd, 0x72, 0x5f, 0xce, 0x1c,
+	0xe6, 0x4c, 0x72, 0x40, 0x7d, 0xf8, 0x3d, 0x34, 0x72, 0xc5, 0xe2, 0x25, 0xd4, 0xc7, 0x2b, 0x4f,
+	0x60, 0xf1, 0xba, 0xec, 0x69, 0xa5, 0xd7, 0x7d, 0xf0, 0x94, 0xda, 0x95, 0x8b, 0xea, 0x65, 0x75,
+	0x78, 0x05, 0xcd, 0x09, 0xad, 0xa3, 0x3b, 0xe2, 0xf8, 0x43, 0xf9, 0x69, 0xea, 0x7d, 0x3a, 0xf1,
+	0x1d, 0xad, 0x93, 0x2d, 0xf5, 0xac, 0xbe, 0xfa, 0x07, 0xe9, 0xab, 0x18, 0x46, 0xe9, 0x36, 0x89,
+	0x53, 0xca, 0xf3, 0x8c, 0x7e, 0x03, 0x3b, 0xe1, 0x61, 0x7f, 0x75, 0xbf, 0x25, 0xbe, 0xa6, 0x20,
+	0x24, 0xde, 0x5f, 0x7a, 0x0b, 0x1e, 0xf9, 0xba, 0xe8, 0x96, 0x88, 0x8f, 0xba, 0x39, 0xcf, 0xd7,
+	0x9e, 0xff, 0xde, 0x0b, 0xe9, 0xdd, 0x57, 0x61, 0x24, 0x56, 0xb7, 0x8b, 0xac, 0xd8, 0x60, 0x0f,
+	0x39, 0xc8, 0x91, 0x83, 0x1c, 0x39, 0xc8, 0x90, 0x8b, 0xfc, 0xdf, 0xed, 0xdb, 0x7f, 0x06, 0x00,
+	0xf7, 0x05, 0x84, 0xed, 0xf9, 0x06, 0x00, 0x00,
 }
@@ -35,6 +35,9 @@ type ProposalResponse struct {
 	// The endorsement of the proposal, basically
 	// the endorser's signature over the payload
 	Endorsement *Endorsement `protobuf:"bytes,6,opt,name=endorsement" json:"endorsement,omitempty"`
+	// The responses to the parts of the proposal on the other
+	// channels written by the chaincodes it calls, if any
+	CrossChannelResponses []*CrossChannelResponse `protobuf:"bytes,7,rep,name=cross_channel_responses,json=crossChannelResponses" json:"cross_channel_responses,omitempty"`
 }
 
 func (m *ProposalResponse) Reset()                    { *m = ProposalResponse{} }
@@ -77,6 +80,55 @@ func (m *ProposalResponse) GetEndorsement() *Endorsement {
 	return nil
 }
 
+func (m *ProposalResponse) GetCrossChannelResponses() []*CrossChannelResponse {
+	if m != nil {
+		return m.CrossChannelResponses
+	}
+	return nil
+}
+
+// CrossChannelResponse is the response to the part of a proposal on a
+// channel other than the one of the proposal, which is written by a chaincode
+// called by the chaincode the proposal invokes. The part is submitted as a
+// transaction of its own on that channel, with the same transaction ID, and
+// takes effect once the cross-channel transaction is committed on every one
+// of its channels
+type CrossChannelResponse struct {
+	// The channel of the part
+	ChannelId string `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	// The bytes of the Proposal of the part, from which the transaction
+	// of the part is created along with the response of each endorser
+	Proposal []byte `protobuf:"bytes,2,opt,name=proposal,proto3" json:"proposal,omitempty"`
+	// The endorsement of the part, whose payload is the response to its proposal
+	Response *ProposalResponse `protobuf:"bytes,3,opt,name=response" json:"response,omitempty"`
+}
+
+func (m *CrossChannelResponse) Reset()                    { *m = CrossChannelResponse{} }
+func (m *CrossChannelResponse) String() string            { return proto.CompactTextString(m) }
+func (*CrossChannelResponse) ProtoMessage()               {}
+func (*CrossChannelResponse) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{1} }
+
+func (m *CrossChannelResponse) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *CrossChannelResponse) GetProposal() []byte {
+	if m != nil {
+		return m.Proposal
+	}
+	return nil
+}
+
+func (m *CrossChannelResponse) GetResponse() *ProposalResponse {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
 // A response with a representation similar to an HTTP response that can
 // be used within another message.
 type Response struct {
@@ -91,7 +143,7 @@ type Response struct {
 func (m *Response) Reset()                    { *m = Response{} }
 func (m *Response) String() string            { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()               {}
-func (*Response) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{1} }
+func (*Response) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{2} }
 
 func (m *Response) GetStatus() int32 {
 	if m != nil {
@@ -147,7 +199,7 @@ type ProposalResponsePayload struct {
 func (m *ProposalResponsePayload) Reset()                    { *m = ProposalResponsePayload{} }
 func (m *ProposalResponsePayload) String() string            { return proto.CompactTextString(m) }
 func (*ProposalResponsePayload) ProtoMessage()               {}
-func (*ProposalResponsePayload) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{2} }
+func (*ProposalResponsePayload) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{3} }
 
 func (m *ProposalResponsePayload) GetProposalHash() []byte {
 	if m != nil {
@@ -183,7 +235,7 @@ type Endorsement struct {
 func (m *Endorsement) Reset()                    { *m = Endorsement{} }
 func (m *Endorsement) String() string            { return proto.CompactTextString(m) }
 func (*Endorsement) ProtoMessage()               {}
-func (*Endorsement) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{3} }
+func (*Endorsement) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{4} }
 
 func (m *Endorsement) GetEndorser() []byte {
 	if m != nil {
@@ -201,6 +253,7 @@ func (m *Endorsement) GetSignature() []byte {
 
 func init() {
 	proto.RegisterType((*ProposalResponse)(nil), "protos.ProposalResponse")
+	proto.RegisterType((*CrossChannelResponse)(nil), "protos.CrossChannelResponse")
 	proto.RegisterType((*Response)(nil), "protos.Response")
 	proto.RegisterType((*ProposalResponsePayload)(nil), "protos.ProposalResponsePayload")
 	proto.RegisterType((*Endorsement)(nil), "protos.Endorsement")
@@ -209,28 +262,33 @@ func init() {
 func init() { proto.RegisterFile("peer/proposal_response.proto", fileDescriptor8) }
 
 var fileDescriptor8 = []byte{
-	// 367 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x92, 0x51, 0x4b, 0xfb, 0x30,
-	0x14, 0xc5, 0xe9, 0xfe, 0xff, 0xcd, 0x2d, 0x9b, 0x30, 0x2a, 0x68, 0x19, 0x03, 0x47, 0x7d, 0x99,
-	0x20, 0x29, 0x28, 0x82, 0xcf, 0x03, 0xd1, 0xc7, 0x11, 0xc4, 0x07, 0x11, 0x24, 0xdd, 0xee, 0xd2,
-	0x62, 0xdb, 0x84, 0xdc, 0x54, 0xdc, 0x07, 0xf6, 0x7b, 0x48, 0xd3, 0xa6, 0xab, 0xe2, 0xd3, 0x38,
-	0x77, 0x27, 0xbf, 0x7b, 0xcf, 0xed, 0x25, 0x73, 0x05, 0xa0, 0x23, 0xa5, 0xa5, 0x92, 0xc8, 0xb3,
-	0x37, 0x0d, 0xa8, 0x64, 0x81, 0x40, 0x95, 0x96, 0x46, 0xfa, 0x03, 0xfb, 0x83, 0xb3, 0x73, 0x21,
-	0xa5, 0xc8, 0x20, 0xb2, 0x32, 0x2e, 0x77, 0x91, 0x49, 0x73, 0x40, 0xc3, 0x73, 0x55, 0x1b, 0xc3,
-	0x2f, 0x8f, 0x4c, 0xd7, 0x0d, 0x84, 0x35, 0x0c, 0x3f, 0x20, 0x47, 0x1f, 0xa0, 0x31, 0x95, 0x45,
-	0xe0, 0x2d, 0xbc, 0x65, 0x9f, 0x39, 0xe9, 0xdf, 0x91, 0x51, 0x4b, 0x08, 0x7a, 0x0b, 0x6f, 0x39,
-	0xbe, 0x9e, 0xd1, 0xba, 0x07, 0x75, 0x3d, 0xe8, 0x93, 0x73, 0xb0, 0x83, 0xd9, 0xbf, 0x22, 0x43,
-	0x37, 0x63, 0xf0, 0xdf, 0x3e, 0x9c, 0xd6, 0x2f, 0x90, 0xba, 0xbe, 0xac, 0x75, 0x54, 0x13, 0x28,
-	0xbe, 0xcf, 0x24, 0xdf, 0x06, 0xfd, 0x85, 0xb7, 0x9c, 0x30, 0x27, 0xfd, 0x5b, 0x32, 0x86, 0x62,
-	0x2b, 0x35, 0x42, 0x0e, 0x85, 0x09, 0x06, 0x16, 0x75, 0xe2, 0x50, 0xf7, 0x87, 0xbf, 0x58, 0xd7,
-	0x17, 0x3e, 0x93, 0x61, 0x1b, 0xef, 0x94, 0x0c, 0xd0, 0x70, 0x53, 0x62, 0x93, 0xae, 0x51, 0x55,
-	0xd3, 0x1c, 0x10, 0xb9, 0x00, 0x1b, 0x6d, 0xc4, 0x9c, 0xec, 0x8e, 0xf3, 0xef, 0xc7, 0x38, 0xe1,
-	0x2b, 0x39, 0xfb, 0xbd, 0xbe, 0x75, 0x33, 0xe9, 0x05, 0x39, 0x6e, 0x3f, 0x4f, 0xc2, 0x31, 0xb1,
-	0xdd, 0x26, 0x6c, 0xe2, 0x8a, 0x8f, 0x1c, 0x13, 0x7f, 0x4e, 0x46, 0xf0, 0x69, 0xa0, 0xb0, 0xcb,
-	0xee, 0x59, 0xc3, 0xa1, 0x10, 0x3e, 0x90, 0x71, 0x27, 0x91, 0x3f, 0x23, 0xc3, 0x26, 0x93, 0x6e,
-	0x60, 0xad, 0xae, 0x40, 0x98, 0x8a, 0x82, 0x9b, 0x52, 0x83, 0x03, 0xb5, 0x85, 0x55, 0x42, 0x42,
-	0xa9, 0x05, 0x4d, 0xf6, 0x0a, 0x74, 0x06, 0x5b, 0x01, 0x9a, 0xee, 0x78, 0xac, 0xd3, 0x8d, 0x5b,
-	0x5c, 0x75, 0x4d, 0xab, 0x3f, 0xa2, 0x6c, 0xde, 0xb9, 0x80, 0x97, 0x4b, 0x91, 0x9a, 0xa4, 0x8c,
-	0xe9, 0x46, 0xe6, 0x51, 0x87, 0x11, 0xd5, 0x8c, 0xfa, 0xba, 0x30, 0xaa, 0x18, 0x71, 0x7d, 0x79,
-	0x37, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x0e, 0x52, 0x0b, 0x35, 0xa0, 0x02, 0x00, 0x00,
+	// 448 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x53, 0x4d, 0x6b, 0xdb, 0x40,
+	0x10, 0x45, 0x71, 0xe3, 0x58, 0x63, 0x17, 0xc2, 0xf6, 0x23, 0xc2, 0xb8, 0xd4, 0xa8, 0x17, 0x17,
+	0x8a, 0x04, 0x69, 0x0b, 0x3d, 0x27, 0x94, 0xb6, 0xb7, 0xb0, 0x84, 0x1e, 0x4a, 0x21, 0xac, 0xa5,
+	0x89, 0x24, 0x6a, 0x69, 0xc5, 0xce, 0xba, 0x34, 0xbf, 0xa0, 0x3f, 0xaa, 0x7f, 0xae, 0x68, 0x3f,
+	0x24, 0xc5, 0xf8, 0x24, 0xde, 0xe8, 0xed, 0x7b, 0xb3, 0x6f, 0x76, 0x60, 0xd5, 0x22, 0xaa, 0xb4,
+	0x55, 0xb2, 0x95, 0x24, 0x76, 0x77, 0x0a, 0xa9, 0x95, 0x0d, 0x61, 0xd2, 0x2a, 0xa9, 0x25, 0x9b,
+	0x9a, 0x0f, 0x2d, 0x5f, 0x17, 0x52, 0x16, 0x3b, 0x4c, 0x0d, 0xdc, 0xee, 0xef, 0x53, 0x5d, 0xd5,
+	0x48, 0x5a, 0xd4, 0xad, 0x25, 0xc6, 0xff, 0x4e, 0xe0, 0xfc, 0xc6, 0x89, 0x70, 0xa7, 0xc1, 0x22,
+	0x38, 0xfb, 0x8d, 0x8a, 0x2a, 0xd9, 0x44, 0xc1, 0x3a, 0xd8, 0x9c, 0x72, 0x0f, 0xd9, 0x27, 0x08,
+	0x7b, 0x85, 0xe8, 0x64, 0x1d, 0x6c, 0xe6, 0x97, 0xcb, 0xc4, 0x7a, 0x24, 0xde, 0x23, 0xb9, 0xf5,
+	0x0c, 0x3e, 0x90, 0xd9, 0x3b, 0x98, 0xf9, 0x1e, 0xa3, 0x27, 0xe6, 0xe0, 0xb9, 0x3d, 0x41, 0x89,
+	0xf7, 0xe5, 0x33, 0x35, 0xea, 0xa0, 0x15, 0x0f, 0x3b, 0x29, 0xf2, 0xe8, 0x74, 0x1d, 0x6c, 0x16,
+	0xdc, 0x43, 0xf6, 0x11, 0xe6, 0xd8, 0xe4, 0x52, 0x11, 0xd6, 0xd8, 0xe8, 0x68, 0x6a, 0xa4, 0x9e,
+	0x79, 0xa9, 0xcf, 0xc3, 0x2f, 0x3e, 0xe6, 0xb1, 0x5b, 0xb8, 0xc8, 0x94, 0x24, 0xba, 0xcb, 0x4a,
+	0xd1, 0x34, 0x38, 0x04, 0x46, 0xd1, 0xd9, 0x7a, 0xb2, 0x99, 0x5f, 0xae, 0xbc, 0xc4, 0x75, 0x47,
+	0xbb, 0xb6, 0xac, 0xbe, 0xb3, 0x17, 0xd9, 0x91, 0x2a, 0xc5, 0x7f, 0x03, 0x78, 0x7e, 0x8c, 0xcf,
+	0x5e, 0x01, 0x78, 0xa3, 0x2a, 0x37, 0x21, 0x86, 0x3c, 0x74, 0x95, 0x6f, 0x39, 0x5b, 0xc2, 0xcc,
+	0x4f, 0xce, 0xa4, 0xb8, 0xe0, 0x3d, 0x66, 0x1f, 0x46, 0x41, 0x4d, 0xcc, 0xed, 0x22, 0xdf, 0xda,
+	0xe1, 0xa0, 0x86, 0xc0, 0xe2, 0xef, 0x30, 0xeb, 0xcd, 0x5f, 0xc2, 0x94, 0xb4, 0xd0, 0x7b, 0x72,
+	0xd3, 0x73, 0xa8, 0x0b, 0xb5, 0x46, 0x22, 0x51, 0xa0, 0x31, 0x0d, 0xb9, 0x87, 0xe3, 0xb8, 0x27,
+	0x8f, 0xe2, 0x8e, 0x7f, 0xc2, 0xc5, 0xa1, 0xeb, 0x8d, 0x9b, 0xc4, 0x1b, 0x78, 0xda, 0x3f, 0xbf,
+	0x52, 0x50, 0x69, 0xdc, 0x16, 0x7c, 0xe1, 0x8b, 0x5f, 0x05, 0x95, 0x6c, 0x05, 0x21, 0xfe, 0xd1,
+	0xd8, 0x98, 0xc7, 0x64, 0xaf, 0x3a, 0x14, 0xe2, 0x2f, 0x30, 0x1f, 0x4d, 0xac, 0x8b, 0xc5, 0xcd,
+	0x4c, 0x39, 0xb1, 0x1e, 0x77, 0x42, 0x54, 0x15, 0x8d, 0xd0, 0x7b, 0x85, 0x5e, 0xa8, 0x2f, 0x5c,
+	0x95, 0x10, 0x4b, 0x55, 0x24, 0xe5, 0x43, 0x8b, 0x6a, 0x87, 0x79, 0x81, 0x2a, 0xb9, 0x17, 0x5b,
+	0x55, 0x65, 0x3e, 0xba, 0x6e, 0x5b, 0xae, 0x8e, 0x5c, 0x25, 0xfb, 0x25, 0x0a, 0xfc, 0xf1, 0xb6,
+	0xa8, 0x74, 0xb9, 0xdf, 0x26, 0x99, 0xac, 0xd3, 0x91, 0x46, 0x6a, 0x35, 0xec, 0xf6, 0x50, 0xda,
+	0x69, 0x6c, 0xed, 0x66, 0xbd, 0xff, 0x3f, 0x00, 0x91, 0x77, 0x49, 0x2c, 0x80, 0x03, 0x00, 0x00,
 }
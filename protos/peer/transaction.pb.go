@@ -17,31 +17,33 @@ var _ = math.Inf
 type TxValidationCode int32
 
 const (
-	TxValidationCode_VALID                        TxValidationCode = 0
-	TxValidationCode_NIL_ENVELOPE                 TxValidationCode = 1
-	TxValidationCode_BAD_PAYLOAD                  TxValidationCode = 2
-	TxValidationCode_BAD_COMMON_HEADER            TxValidationCode = 3
-	TxValidationCode_BAD_CREATOR_SIGNATURE        TxValidationCode = 4
-	TxValidationCode_INVALID_ENDORSER_TRANSACTION TxValidationCode = 5
-	TxValidationCode_INVALID_CONFIG_TRANSACTION   TxValidationCode = 6
-	TxValidationCode_UNSUPPORTED_TX_PAYLOAD       TxValidationCode = 7
-	TxValidationCode_BAD_PROPOSAL_TXID            TxValidationCode = 8
-	TxValidationCode_DUPLICATE_TXID               TxValidationCode = 9
-	TxValidationCode_ENDORSEMENT_POLICY_FAILURE   TxValidationCode = 10
-	TxValidationCode_MVCC_READ_CONFLICT           TxValidationCode = 11
-	TxValidationCode_PHANTOM_READ_CONFLICT        TxValidationCode = 12
-	TxValidationCode_UNKNOWN_TX_TYPE              TxValidationCode = 13
-	TxValidationCode_TARGET_CHAIN_NOT_FOUND       TxValidationCode = 14
-	TxValidationCode_MARSHAL_TX_ERROR             TxValidationCode = 15
-	TxValidationCode_NIL_TXACTION                 TxValidationCode = 16
-	TxValidationCode_EXPIRED_CHAINCODE            TxValidationCode = 17
-	TxValidationCode_CHAINCODE_VERSION_CONFLICT   TxValidationCode = 18
-	TxValidationCode_BAD_HEADER_EXTENSION         TxValidationCode = 19
-	TxValidationCode_BAD_CHANNEL_HEADER           TxValidationCode = 20
-	TxValidationCode_BAD_RESPONSE_PAYLOAD         TxValidationCode = 21
-	TxValidationCode_BAD_RWSET                    TxValidationCode = 22
-	TxValidationCode_ILLEGAL_WRITESET             TxValidationCode = 23
-	TxValidationCode_INVALID_OTHER_REASON         TxValidationCode = 255
+	TxValidationCode_VALID                            TxValidationCode = 0
+	TxValidationCode_NIL_ENVELOPE                     TxValidationCode = 1
+	TxValidationCode_BAD_PAYLOAD                      TxValidationCode = 2
+	TxValidationCode_BAD_COMMON_HEADER                TxValidationCode = 3
+	TxValidationCode_BAD_CREATOR_SIGNATURE            TxValidationCode = 4
+	TxValidationCode_INVALID_ENDORSER_TRANSACTION     TxValidationCode = 5
+	TxValidationCode_INVALID_CONFIG_TRANSACTION       TxValidationCode = 6
+	TxValidationCode_UNSUPPORTED_TX_PAYLOAD           TxValidationCode = 7
+	TxValidationCode_BAD_PROPOSAL_TXID                TxValidationCode = 8
+	TxValidationCode_DUPLICATE_TXID                   TxValidationCode = 9
+	TxValidationCode_ENDORSEMENT_POLICY_FAILURE       TxValidationCode = 10
+	TxValidationCode_MVCC_READ_CONFLICT               TxValidationCode = 11
+	TxValidationCode_PHANTOM_READ_CONFLICT            TxValidationCode = 12
+	TxValidationCode_UNKNOWN_TX_TYPE                  TxValidationCode = 13
+	TxValidationCode_TARGET_CHAIN_NOT_FOUND           TxValidationCode = 14
+	TxValidationCode_MARSHAL_TX_ERROR                 TxValidationCode = 15
+	TxValidationCode_NIL_TXACTION                     TxValidationCode = 16
+	TxValidationCode_EXPIRED_CHAINCODE                TxValidationCode = 17
+	TxValidationCode_CHAINCODE_VERSION_CONFLICT       TxValidationCode = 18
+	TxValidationCode_BAD_HEADER_EXTENSION             TxValidationCode = 19
+	TxValidationCode_BAD_CHANNEL_HEADER               TxValidationCode = 20
+	TxValidationCode_BAD_RESPONSE_PAYLOAD             TxValidationCode = 21
+	TxValidationCode_BAD_RWSET                        TxValidationCode = 22
+	TxValidationCode_ILLEGAL_WRITESET                 TxValidationCode = 23
+	TxValidationCode_CROSS_CHANNEL_LOCK_CONFLICT      TxValidationCode = 24
+	TxValidationCode_INVALID_CROSS_CHANNEL_TRANSITION TxValidationCode = 25
+	TxValidationCode_INVALID_OTHER_REASON             TxValidationCode = 255
 )
 
 var TxValidationCode_name = map[int32]string{
@@ -69,34 +71,38 @@ var TxValidationCode_name = map[int32]string{
 	21:  "BAD_RESPONSE_PAYLOAD",
 	22:  "BAD_RWSET",
 	23:  "ILLEGAL_WRITESET",
+	24:  "CROSS_CHANNEL_LOCK_CONFLICT",
+	25:  "INVALID_CROSS_CHANNEL_TRANSITION",
 	255: "INVALID_OTHER_REASON",
 }
 var TxValidationCode_value = map[string]int32{
-	"VALID":                        0,
-	"NIL_ENVELOPE":                 1,
-	"BAD_PAYLOAD":                  2,
-	"BAD_COMMON_HEADER":            3,
-	"BAD_CREATOR_SIGNATURE":        4,
-	"INVALID_ENDORSER_TRANSACTION": 5,
-	"INVALID_CONFIG_TRANSACTION":   6,
-	"UNSUPPORTED_TX_PAYLOAD":       7,
-	"BAD_PROPOSAL_TXID":            8,
-	"DUPLICATE_TXID":               9,
-	"ENDORSEMENT_POLICY_FAILURE":   10,
-	"MVCC_READ_CONFLICT":           11,
-	"PHANTOM_READ_CONFLICT":        12,
-	"UNKNOWN_TX_TYPE":              13,
-	"TARGET_CHAIN_NOT_FOUND":       14,
-	"MARSHAL_TX_ERROR":             15,
-	"NIL_TXACTION":                 16,
-	"EXPIRED_CHAINCODE":            17,
-	"CHAINCODE_VERSION_CONFLICT":   18,
-	"BAD_HEADER_EXTENSION":         19,
-	"BAD_CHANNEL_HEADER":           20,
-	"BAD_RESPONSE_PAYLOAD":         21,
-	"BAD_RWSET":                    22,
-	"ILLEGAL_WRITESET":             23,
-	"INVALID_OTHER_REASON":         255,
+	"VALID":                            0,
+	"NIL_ENVELOPE":                     1,
+	"BAD_PAYLOAD":                      2,
+	"BAD_COMMON_HEADER":                3,
+	"BAD_CREATOR_SIGNATURE":            4,
+	"INVALID_ENDORSER_TRANSACTION":     5,
+	"INVALID_CONFIG_TRANSACTION":       6,
+	"UNSUPPORTED_TX_PAYLOAD":           7,
+	"BAD_PROPOSAL_TXID":                8,
+	"DUPLICATE_TXID":                   9,
+	"ENDORSEMENT_POLICY_FAILURE":       10,
+	"MVCC_READ_CONFLICT":               11,
+	"PHANTOM_READ_CONFLICT":            12,
+	"UNKNOWN_TX_TYPE":                  13,
+	"TARGET_CHAIN_NOT_FOUND":           14,
+	"MARSHAL_TX_ERROR":                 15,
+	"NIL_TXACTION":                     16,
+	"EXPIRED_CHAINCODE":                17,
+	"CHAINCODE_VERSION_CONFLICT":       18,
+	"BAD_HEADER_EXTENSION":             19,
+	"BAD_CHANNEL_HEADER":               20,
+	"BAD_RESPONSE_PAYLOAD":             21,
+	"BAD_RWSET":                        22,
+	"ILLEGAL_WRITESET":                 23,
+	"CROSS_CHANNEL_LOCK_CONFLICT":      24,
+	"INVALID_CROSS_CHANNEL_TRANSITION": 25,
+	"INVALID_OTHER_REASON":             255,
 }
 
 func (x TxValidationCode) String() string {
@@ -104,6 +110,32 @@ func (x TxValidationCode) String() string {
 }
 func (TxValidationCode) EnumDescriptor() ([]byte, []int) { return fileDescriptor11, []int{0} }
 
+type CrossChannelRecord_Status int32
+
+const (
+	CrossChannelRecord_PREPARED  CrossChannelRecord_Status = 0
+	CrossChannelRecord_COMMITTED CrossChannelRecord_Status = 1
+	CrossChannelRecord_ABORTED   CrossChannelRecord_Status = 2
+)
+
+var CrossChannelRecord_Status_name = map[int32]string{
+	0: "PREPARED",
+	1: "COMMITTED",
+	2: "ABORTED",
+}
+var CrossChannelRecord_Status_value = map[string]int32{
+	"PREPARED":  0,
+	"COMMITTED": 1,
+	"ABORTED":   2,
+}
+
+func (x CrossChannelRecord_Status) String() string {
+	return proto.EnumName(CrossChannelRecord_Status_name, int32(x))
+}
+func (CrossChannelRecord_Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor11, []int{6, 0}
+}
+
 // This message is necessary to facilitate the verification of the signature
 // (in the signature field) over the bytes of the transaction (in the
 // transactionBytes field).
@@ -295,6 +327,49 @@ func (m *ChaincodeEndorsedAction) GetEndorsements() []*Endorsement {
 	return nil
 }
 
+// CrossChannelRecord is the record of a cross-channel transaction, which
+// writes to several channels, kept in the namespace of XSCC on each of its
+// channels under its transaction ID. The part of the transaction on a channel
+// is held as PREPARED once it is validated, and its writes are applied if the
+// transaction is then COMMITTED or dropped if it is ABORTED, as decided by a
+// transaction of XSCC on every one of the channels
+type CrossChannelRecord struct {
+	Status CrossChannelRecord_Status `protobuf:"varint,1,opt,name=status,enum=protos.CrossChannelRecord_Status" json:"status,omitempty"`
+	// The channels the transaction writes to, starting with the channel of
+	// the proposal, which coordinates the transaction: the transaction is
+	// decided there first, and then likewise on the other channels
+	Channels []string `protobuf:"bytes,2,rep,name=channels" json:"channels,omitempty"`
+	// The read-write set of the part of the transaction on the channel,
+	// which is kept by the ledger while the part is PREPARED
+	Pending []byte `protobuf:"bytes,3,opt,name=pending,proto3" json:"pending,omitempty"`
+}
+
+func (m *CrossChannelRecord) Reset()                    { *m = CrossChannelRecord{} }
+func (m *CrossChannelRecord) String() string            { return proto.CompactTextString(m) }
+func (*CrossChannelRecord) ProtoMessage()               {}
+func (*CrossChannelRecord) Descriptor() ([]byte, []int) { return fileDescriptor11, []int{6} }
+
+func (m *CrossChannelRecord) GetStatus() CrossChannelRecord_Status {
+	if m != nil {
+		return m.Status
+	}
+	return CrossChannelRecord_PREPARED
+}
+
+func (m *CrossChannelRecord) GetChannels() []string {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+func (m *CrossChannelRecord) GetPending() []byte {
+	if m != nil {
+		return m.Pending
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*SignedTransaction)(nil), "protos.SignedTransaction")
 	proto.RegisterType((*ProcessedTransaction)(nil), "protos.ProcessedTransaction")
@@ -302,64 +377,73 @@ func init() {
 	proto.RegisterType((*TransactionAction)(nil), "protos.TransactionAction")
 	proto.RegisterType((*ChaincodeActionPayload)(nil), "protos.ChaincodeActionPayload")
 	proto.RegisterType((*ChaincodeEndorsedAction)(nil), "protos.ChaincodeEndorsedAction")
+	proto.RegisterType((*CrossChannelRecord)(nil), "protos.CrossChannelRecord")
 	proto.RegisterEnum("protos.TxValidationCode", TxValidationCode_name, TxValidationCode_value)
+	proto.RegisterEnum("protos.CrossChannelRecord_Status", CrossChannelRecord_Status_name, CrossChannelRecord_Status_value)
 }
 
 func init() { proto.RegisterFile("peer/transaction.proto", fileDescriptor11) }
 
 var fileDescriptor11 = []byte{
-	// 833 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x54, 0xd1, 0x6e, 0xe2, 0x46,
-	0x14, 0x2d, 0xd9, 0x4d, 0xd2, 0x0c, 0xd9, 0x64, 0x32, 0x10, 0x42, 0x50, 0xd4, 0x5d, 0xf1, 0x50,
-	0x6d, 0x5b, 0x09, 0xa4, 0xec, 0x43, 0xa5, 0xaa, 0x2f, 0x83, 0x3d, 0x09, 0x56, 0xcd, 0x8c, 0x35,
-	0x1e, 0x08, 0xe9, 0x43, 0x47, 0x06, 0xcf, 0x12, 0x54, 0xb0, 0x2d, 0xdb, 0x59, 0x35, 0xaf, 0xfd,
-	0x80, 0xf6, 0x3f, 0xfb, 0x13, 0xad, 0xc6, 0x63, 0x03, 0xc9, 0x76, 0x5f, 0x30, 0x73, 0xcf, 0xb9,
-	0xf7, 0x9c, 0x7b, 0xaf, 0x3d, 0xa0, 0x95, 0x28, 0x95, 0xf6, 0xf3, 0x34, 0x88, 0xb2, 0x60, 0x9e,
-	0x2f, 0xe3, 0xa8, 0x97, 0xa4, 0x71, 0x1e, 0xa3, 0x83, 0xe2, 0x91, 0x75, 0xde, 0x2e, 0xe2, 0x78,
-	0xb1, 0x52, 0xfd, 0xe2, 0x38, 0x7b, 0xfc, 0xd8, 0xcf, 0x97, 0x6b, 0x95, 0xe5, 0xc1, 0x3a, 0x31,
-	0xc4, 0xce, 0x55, 0x51, 0x20, 0x49, 0xe3, 0x24, 0xce, 0x82, 0x95, 0x4c, 0x55, 0x96, 0xc4, 0x51,
-	0xa6, 0x4a, 0xb4, 0x31, 0x8f, 0xd7, 0xeb, 0x38, 0xea, 0x9b, 0x87, 0x09, 0x76, 0x7f, 0x03, 0x67,
-	0xfe, 0x72, 0x11, 0xa9, 0x50, 0x6c, 0x65, 0xd1, 0x0f, 0xe0, 0x6c, 0xc7, 0x85, 0x9c, 0x3d, 0xe5,
-	0x2a, 0x6b, 0xd7, 0xde, 0xd5, 0xde, 0x1f, 0x73, 0xb8, 0x03, 0x0c, 0x74, 0x1c, 0x5d, 0x81, 0xa3,
-	0x6c, 0xb9, 0x88, 0x82, 0xfc, 0x31, 0x55, 0xed, 0xbd, 0x82, 0xb4, 0x0d, 0x74, 0xff, 0xac, 0x81,
-	0xa6, 0x97, 0xc6, 0x73, 0x95, 0x65, 0xcf, 0x35, 0x06, 0xa0, 0xb1, 0x53, 0x8a, 0x44, 0x9f, 0xd4,
-	0x2a, 0x4e, 0x54, 0xa1, 0x52, 0xbf, 0x86, 0xbd, 0xd2, 0x64, 0x15, 0xe7, 0xff, 0x47, 0x46, 0xdf,
-	0x82, 0x93, 0x4f, 0xc1, 0x6a, 0x19, 0x06, 0x3a, 0x6a, 0xc5, 0xa1, 0xd1, 0xdf, 0xe7, 0x2f, 0xa2,
-	0xdd, 0x01, 0xa8, 0xef, 0x4a, 0x7f, 0x00, 0x87, 0xe6, 0x9f, 0x6e, 0xea, 0xd5, 0xfb, 0xfa, 0xf5,
-	0xa5, 0x19, 0x46, 0xd6, 0xdb, 0x61, 0xe1, 0xe2, 0x97, 0x57, 0xcc, 0x2e, 0x01, 0x67, 0x9f, 0xa1,
-	0xa8, 0x05, 0x0e, 0x1e, 0x54, 0x10, 0xaa, 0xb4, 0x9c, 0x4e, 0x79, 0x42, 0x6d, 0x70, 0x98, 0x04,
-	0x4f, 0xab, 0x38, 0x08, 0xcb, 0x89, 0x54, 0xc7, 0xee, 0xdf, 0x35, 0xd0, 0xb2, 0x1e, 0x82, 0x65,
-	0x34, 0x8f, 0x43, 0x65, 0xaa, 0x78, 0x06, 0x42, 0x3f, 0x83, 0xce, 0xbc, 0x42, 0xe4, 0x66, 0x89,
-	0x55, 0x1d, 0x23, 0xd0, 0xde, 0x30, 0xbc, 0x92, 0x50, 0x65, 0xff, 0x08, 0x0e, 0x8c, 0xb5, 0x42,
-	0xb1, 0x7e, 0xfd, 0xb6, 0xea, 0x69, 0xa3, 0x46, 0xa2, 0x30, 0x4e, 0x33, 0x15, 0x96, 0x9d, 0x95,
-	0xf4, 0xee, 0x5f, 0x35, 0x70, 0xf1, 0x05, 0x0e, 0xfa, 0x09, 0x5c, 0x7e, 0xf6, 0x36, 0xbd, 0x70,
-	0x74, 0x51, 0x11, 0x78, 0x89, 0x6f, 0x0d, 0x1d, 0x2b, 0x53, 0x6d, 0xad, 0xa2, 0x3c, 0x6b, 0xef,
-	0x15, 0xa3, 0x6e, 0x54, 0xb6, 0xc8, 0x16, 0xe3, 0xcf, 0x88, 0xdf, 0xff, 0xf3, 0x1a, 0x40, 0xf1,
-	0xc7, 0xe4, 0xd9, 0x0a, 0xd1, 0x11, 0xd8, 0x9f, 0x60, 0xd7, 0xb1, 0xe1, 0x57, 0x08, 0x82, 0x63,
-	0xea, 0xb8, 0x92, 0xd0, 0x09, 0x71, 0x99, 0x47, 0x60, 0x0d, 0x9d, 0x82, 0xfa, 0x00, 0xdb, 0xd2,
-	0xc3, 0xf7, 0x2e, 0xc3, 0x36, 0xdc, 0x43, 0xe7, 0xe0, 0x4c, 0x07, 0x2c, 0x36, 0x1a, 0x31, 0x2a,
-	0x87, 0x04, 0xdb, 0x84, 0xc3, 0x57, 0xe8, 0x12, 0x9c, 0x17, 0x61, 0x4e, 0xb0, 0x60, 0x5c, 0xfa,
-	0xce, 0x2d, 0xc5, 0x62, 0xcc, 0x09, 0x7c, 0x8d, 0xde, 0x81, 0x2b, 0x87, 0x16, 0x0a, 0x92, 0x50,
-	0x9b, 0x71, 0x9f, 0x70, 0x29, 0x38, 0xa6, 0x3e, 0xb6, 0x84, 0xc3, 0x28, 0xdc, 0x47, 0xdf, 0x80,
-	0x4e, 0xc5, 0xb0, 0x18, 0xbd, 0x71, 0x6e, 0x9f, 0xe1, 0x07, 0xa8, 0x03, 0x5a, 0x63, 0xea, 0x8f,
-	0x3d, 0x8f, 0x71, 0x41, 0x6c, 0x29, 0xa6, 0x1b, 0x3f, 0x87, 0x95, 0x1f, 0x8f, 0x33, 0x8f, 0xf9,
-	0xd8, 0x95, 0x62, 0xea, 0xd8, 0xf0, 0x6b, 0x84, 0xc0, 0x89, 0x3d, 0xf6, 0x5c, 0xc7, 0xc2, 0x82,
-	0x98, 0xd8, 0x91, 0x96, 0x29, 0x0d, 0x8c, 0x08, 0x15, 0xd2, 0x63, 0xae, 0x63, 0xdd, 0xcb, 0x1b,
-	0xec, 0xb8, 0xda, 0x28, 0x40, 0x2d, 0x80, 0x46, 0x13, 0xcb, 0x92, 0x9c, 0x60, 0x63, 0xc4, 0x75,
-	0x2c, 0x01, 0xeb, 0xba, 0x37, 0x6f, 0x88, 0xa9, 0x60, 0xa3, 0x17, 0xd0, 0x31, 0x6a, 0x80, 0xd3,
-	0x31, 0xfd, 0x85, 0xb2, 0x3b, 0xaa, 0x5d, 0x89, 0x7b, 0x8f, 0xc0, 0x37, 0xda, 0xae, 0xc0, 0xfc,
-	0x96, 0x08, 0x69, 0x0d, 0xb1, 0x43, 0x25, 0x65, 0x42, 0xde, 0xb0, 0x31, 0xb5, 0xe1, 0x09, 0x6a,
-	0x02, 0x38, 0xc2, 0xdc, 0x1f, 0x16, 0x4e, 0x25, 0xe1, 0x9c, 0x71, 0x78, 0x5a, 0xcd, 0x5d, 0x4c,
-	0xcb, 0x96, 0xa1, 0x6e, 0x8b, 0x4c, 0x3d, 0x87, 0x13, 0xdb, 0x14, 0xb1, 0x98, 0x4d, 0xe0, 0x99,
-	0x6e, 0x61, 0x73, 0x94, 0x13, 0xc2, 0x7d, 0x87, 0xd1, 0xad, 0x1f, 0x84, 0xda, 0xa0, 0xa9, 0xa7,
-	0x61, 0xd6, 0x22, 0xc9, 0x54, 0x10, 0xaa, 0x29, 0xb0, 0xa1, 0x9b, 0x2b, 0x16, 0x34, 0xc4, 0x94,
-	0x12, 0xb7, 0x5a, 0x5c, 0xb3, 0xca, 0xe0, 0xc4, 0xf7, 0x18, 0xf5, 0xc9, 0x66, 0xb2, 0xe7, 0xe8,
-	0x0d, 0x38, 0x2a, 0x90, 0x3b, 0x9f, 0x08, 0xd8, 0xd2, 0xce, 0x1d, 0xd7, 0x25, 0xb7, 0xd8, 0x95,
-	0x77, 0xdc, 0x11, 0x44, 0x47, 0x2f, 0xd0, 0x25, 0x68, 0x56, 0xab, 0x63, 0x62, 0x48, 0xb8, 0x9e,
-	0x90, 0xcf, 0x28, 0xfc, 0xb7, 0x36, 0x98, 0x83, 0x6e, 0x9c, 0x2e, 0x7a, 0x0f, 0x4f, 0x89, 0x4a,
-	0x57, 0x2a, 0x5c, 0xa8, 0xb4, 0xf7, 0x31, 0x98, 0xa5, 0xcb, 0x79, 0xf5, 0x9e, 0xea, 0x2b, 0x75,
-	0x80, 0x76, 0x3e, 0x7d, 0x2f, 0x98, 0xff, 0x1e, 0x2c, 0xd4, 0xaf, 0xdf, 0x2d, 0x96, 0xf9, 0xc3,
-	0xe3, 0x4c, 0xdf, 0x54, 0xfd, 0x9d, 0xf4, 0xbe, 0x49, 0x37, 0x97, 0x74, 0xd6, 0xd7, 0xe9, 0x33,
-	0x73, 0x81, 0x7f, 0xf8, 0x2f, 0x00, 0x00, 0xff, 0xff, 0x19, 0x1c, 0xb2, 0xe4, 0xe1, 0x05, 0x00,
-	0x00,
+	// 956 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x55, 0xdf, 0x4e, 0xeb, 0xc6,
+	0x13, 0xfe, 0x85, 0xf3, 0x23, 0xc0, 0x84, 0x03, 0xcb, 0x02, 0x21, 0xa4, 0xa8, 0xd0, 0xa8, 0xaa,
+	0x4e, 0x5b, 0x29, 0x91, 0x38, 0x17, 0x55, 0xab, 0xde, 0x6c, 0xec, 0x85, 0x58, 0xc7, 0xd9, 0xb5,
+	0xd6, 0x1b, 0xfe, 0xf4, 0xa2, 0x96, 0x89, 0xf7, 0x84, 0xa8, 0xc1, 0x8e, 0x6c, 0x73, 0x54, 0x6e,
+	0xfb, 0x00, 0xed, 0xb3, 0xf4, 0xb2, 0x4f, 0xd7, 0x6a, 0xbd, 0x76, 0x12, 0xa0, 0xbd, 0x89, 0x33,
+	0x33, 0xdf, 0xcc, 0x7c, 0xdf, 0xcc, 0xda, 0x0b, 0xcd, 0xb9, 0x52, 0x69, 0x2f, 0x4f, 0xc3, 0x38,
+	0x0b, 0xc7, 0xf9, 0x34, 0x89, 0xbb, 0xf3, 0x34, 0xc9, 0x13, 0x5c, 0x2f, 0x1e, 0x59, 0xfb, 0x74,
+	0x92, 0x24, 0x93, 0x99, 0xea, 0x15, 0xe6, 0xdd, 0xe3, 0xc7, 0x5e, 0x3e, 0x7d, 0x50, 0x59, 0x1e,
+	0x3e, 0xcc, 0x0d, 0xb0, 0x7d, 0x52, 0x14, 0x98, 0xa7, 0xc9, 0x3c, 0xc9, 0xc2, 0x59, 0x90, 0xaa,
+	0x6c, 0x9e, 0xc4, 0x99, 0x2a, 0xa3, 0xfb, 0xe3, 0xe4, 0xe1, 0x21, 0x89, 0x7b, 0xe6, 0x61, 0x9c,
+	0x9d, 0x9f, 0x61, 0xcf, 0x9f, 0x4e, 0x62, 0x15, 0xc9, 0x65, 0x5b, 0xfc, 0x2d, 0xec, 0xad, 0xb0,
+	0x08, 0xee, 0x9e, 0x72, 0x95, 0xb5, 0x6a, 0x67, 0xb5, 0x77, 0xdb, 0x02, 0xad, 0x04, 0xfa, 0xda,
+	0x8f, 0x4f, 0x60, 0x2b, 0x9b, 0x4e, 0xe2, 0x30, 0x7f, 0x4c, 0x55, 0x6b, 0xad, 0x00, 0x2d, 0x1d,
+	0x9d, 0xdf, 0x6a, 0x70, 0xe0, 0xa5, 0xc9, 0x58, 0x65, 0xd9, 0xf3, 0x1e, 0x7d, 0xd8, 0x5f, 0x29,
+	0x45, 0xe3, 0x4f, 0x6a, 0x96, 0xcc, 0x55, 0xd1, 0xa5, 0x71, 0x8e, 0xba, 0x25, 0xc9, 0xca, 0x2f,
+	0xfe, 0x0d, 0x8c, 0xbf, 0x82, 0x9d, 0x4f, 0xe1, 0x6c, 0x1a, 0x85, 0xda, 0x6b, 0x25, 0x91, 0xe9,
+	0xbf, 0x2e, 0x5e, 0x78, 0x3b, 0x7d, 0x68, 0xac, 0xb6, 0x7e, 0x0f, 0x1b, 0xe6, 0x9f, 0x16, 0xf5,
+	0xe6, 0x5d, 0xe3, 0xfc, 0xd8, 0x0c, 0x23, 0xeb, 0xae, 0xa0, 0x48, 0xf1, 0x2b, 0x2a, 0x64, 0x87,
+	0xc2, 0xde, 0xab, 0x28, 0x6e, 0x42, 0xfd, 0x5e, 0x85, 0x91, 0x4a, 0xcb, 0xe9, 0x94, 0x16, 0x6e,
+	0xc1, 0xc6, 0x3c, 0x7c, 0x9a, 0x25, 0x61, 0x54, 0x4e, 0xa4, 0x32, 0x3b, 0x7f, 0xd4, 0xa0, 0x69,
+	0xdd, 0x87, 0xd3, 0x78, 0x9c, 0x44, 0xca, 0x54, 0xf1, 0x4c, 0x08, 0xff, 0x08, 0xed, 0x71, 0x15,
+	0x09, 0x16, 0x4b, 0xac, 0xea, 0x98, 0x06, 0xad, 0x05, 0xc2, 0x2b, 0x01, 0x55, 0xf6, 0x77, 0x50,
+	0x37, 0xd4, 0x8a, 0x8e, 0x8d, 0xf3, 0xd3, 0x4a, 0xd3, 0xa2, 0x1b, 0x8d, 0xa3, 0x24, 0xcd, 0x54,
+	0x54, 0x2a, 0x2b, 0xe1, 0x9d, 0xdf, 0x6b, 0x70, 0xf4, 0x1f, 0x18, 0xfc, 0x03, 0x1c, 0xbf, 0x3a,
+	0x4d, 0x2f, 0x18, 0x1d, 0x55, 0x00, 0x51, 0xc6, 0x97, 0x84, 0xb6, 0x95, 0xa9, 0xf6, 0xa0, 0xe2,
+	0x3c, 0x6b, 0xad, 0x15, 0xa3, 0xde, 0xaf, 0x68, 0xd1, 0x65, 0x4c, 0x3c, 0x03, 0x76, 0xfe, 0xaa,
+	0x01, 0xb6, 0xd2, 0x24, 0xcb, 0xac, 0xfb, 0x30, 0x8e, 0xd5, 0x4c, 0xa8, 0x71, 0x92, 0x46, 0xf8,
+	0x7b, 0xa8, 0x67, 0x79, 0x98, 0x3f, 0x9a, 0x93, 0xb8, 0x73, 0xfe, 0xc5, 0x42, 0xe0, 0x2b, 0x6c,
+	0xd7, 0x2f, 0x80, 0xa2, 0x4c, 0xc0, 0x6d, 0xd8, 0x1c, 0x9b, 0xb8, 0xa1, 0xb1, 0x25, 0x16, 0x76,
+	0xb1, 0x2a, 0x15, 0x47, 0xd3, 0x78, 0xd2, 0x7a, 0x53, 0xae, 0xca, 0x98, 0x9d, 0x73, 0xa8, 0x9b,
+	0x3a, 0x78, 0x1b, 0x36, 0x3d, 0x41, 0x3d, 0x22, 0xa8, 0x8d, 0xfe, 0x87, 0xdf, 0xc2, 0x96, 0xc5,
+	0x87, 0x43, 0x47, 0x4a, 0x6a, 0xa3, 0x1a, 0x6e, 0xc0, 0x06, 0xe9, 0x73, 0xa1, 0x8d, 0xb5, 0x6f,
+	0xfe, 0x5c, 0x07, 0x24, 0x7f, 0xbd, 0x7a, 0x76, 0xfc, 0xf0, 0x16, 0xac, 0x5f, 0x11, 0xd7, 0xd1,
+	0xb9, 0x08, 0xb6, 0x99, 0xe3, 0x06, 0x94, 0x5d, 0x51, 0x97, 0x7b, 0x14, 0xd5, 0xf0, 0x2e, 0x34,
+	0xfa, 0xc4, 0x0e, 0x3c, 0x72, 0xeb, 0x72, 0x62, 0xa3, 0x35, 0x7c, 0x08, 0x7b, 0xda, 0xa1, 0x5b,
+	0x70, 0x16, 0x0c, 0x28, 0xb1, 0xa9, 0x40, 0x6f, 0xf0, 0x31, 0x1c, 0x16, 0x6e, 0x41, 0x89, 0xe4,
+	0x22, 0xf0, 0x9d, 0x4b, 0x46, 0xe4, 0x48, 0x50, 0xf4, 0x7f, 0x7c, 0x06, 0x27, 0x0e, 0x2b, 0x3a,
+	0x04, 0x94, 0xd9, 0x5c, 0xf8, 0x54, 0x04, 0x52, 0x10, 0xe6, 0x13, 0x4b, 0x3a, 0x9c, 0xa1, 0x75,
+	0xfc, 0x39, 0xb4, 0x2b, 0x84, 0xc5, 0xd9, 0x85, 0x73, 0xf9, 0x2c, 0x5e, 0xc7, 0x6d, 0x68, 0x8e,
+	0x98, 0x3f, 0xf2, 0xbc, 0x42, 0x47, 0x20, 0x6f, 0x16, 0x7c, 0x36, 0x2a, 0x3e, 0x9e, 0xe0, 0x1e,
+	0xf7, 0x89, 0x1b, 0xc8, 0x1b, 0xc7, 0x46, 0x9b, 0x18, 0xc3, 0x8e, 0x3d, 0xf2, 0x5c, 0xc7, 0x22,
+	0x92, 0x1a, 0xdf, 0x96, 0x6e, 0x53, 0x12, 0x18, 0x52, 0x26, 0x03, 0x8f, 0xbb, 0x8e, 0x75, 0x1b,
+	0x5c, 0x10, 0xc7, 0xd5, 0x44, 0x01, 0x37, 0x01, 0x0f, 0xaf, 0x2c, 0x2b, 0x10, 0x94, 0x18, 0x22,
+	0xae, 0x63, 0x49, 0xd4, 0xd0, 0xda, 0xbc, 0x01, 0x61, 0x92, 0x0f, 0x5f, 0x84, 0xb6, 0xf1, 0x3e,
+	0xec, 0x8e, 0xd8, 0x07, 0xc6, 0xaf, 0x99, 0x66, 0x25, 0x6f, 0x3d, 0x8a, 0xde, 0x6a, 0xba, 0x92,
+	0x88, 0x4b, 0x2a, 0x03, 0x6b, 0x40, 0x1c, 0x16, 0x30, 0x2e, 0x83, 0x0b, 0x3e, 0x62, 0x36, 0xda,
+	0xc1, 0x07, 0x80, 0x86, 0x44, 0xf8, 0x83, 0x82, 0x69, 0x40, 0x85, 0xe0, 0x02, 0xed, 0x56, 0x73,
+	0x97, 0x37, 0xa5, 0x64, 0xa4, 0x65, 0xd1, 0x1b, 0xcf, 0x11, 0xd4, 0x36, 0x45, 0x2c, 0x6e, 0x53,
+	0xb4, 0xa7, 0x25, 0x2c, 0xcc, 0xe0, 0x8a, 0x0a, 0xdf, 0xe1, 0x6c, 0xc9, 0x07, 0xe3, 0x16, 0x1c,
+	0xe8, 0x69, 0x98, 0xb5, 0x04, 0xf4, 0x46, 0x52, 0xa6, 0x21, 0x68, 0x5f, 0x8b, 0x2b, 0x16, 0x34,
+	0x20, 0x8c, 0x51, 0xb7, 0x5a, 0xdc, 0x41, 0x95, 0x21, 0xa8, 0xef, 0x71, 0xe6, 0xd3, 0xc5, 0x64,
+	0x0f, 0xf5, 0x41, 0x2a, 0x22, 0xd7, 0x3e, 0x95, 0xa8, 0xa9, 0x99, 0x3b, 0xae, 0x4b, 0x2f, 0x89,
+	0x1b, 0x5c, 0x0b, 0x47, 0x52, 0xed, 0x3d, 0xc2, 0xa7, 0xf0, 0x99, 0x25, 0xb8, 0xef, 0x2f, 0x0a,
+	0xbb, 0xdc, 0xfa, 0xb0, 0x64, 0xd4, 0xc2, 0x5f, 0xc2, 0xd9, 0x62, 0xb7, 0xcf, 0x80, 0xc5, 0x8a,
+	0x9d, 0x42, 0xee, 0x31, 0x3e, 0x86, 0x83, 0x0a, 0xc5, 0xe5, 0x80, 0x0a, 0x3d, 0x68, 0x9f, 0x33,
+	0xf4, 0x77, 0xad, 0x3f, 0x86, 0x4e, 0x92, 0x4e, 0xba, 0xf7, 0x4f, 0x73, 0x95, 0xce, 0x54, 0x34,
+	0x51, 0x69, 0xf7, 0x63, 0x78, 0x97, 0x4e, 0xc7, 0xd5, 0x0b, 0xa6, 0x6f, 0x95, 0x3e, 0x5e, 0xf9,
+	0xfa, 0x79, 0xe1, 0xf8, 0x97, 0x70, 0xa2, 0x7e, 0xfa, 0x7a, 0x32, 0xcd, 0xef, 0x1f, 0xef, 0xf4,
+	0xc7, 0xba, 0xb7, 0x92, 0xde, 0x33, 0xe9, 0xe6, 0x9e, 0xca, 0x7a, 0x3a, 0xfd, 0xce, 0xdc, 0x61,
+	0xef, 0xff, 0x19, 0x00, 0xf7, 0x31, 0xb7, 0xe5, 0xe4, 0x06, 0x00, 0x00,
 }
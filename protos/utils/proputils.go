@@ -422,21 +422,22 @@ func CreateProposalFromCIS(typ common.HeaderType, chainID string, cis *peer.Chai
 
 // CreateInstallProposalFromCDS returns a install proposal given a serialized identity and a ChaincodeDeploymentSpec
 func CreateInstallProposalFromCDS(ccpack proto.Message, creator []byte) (*peer.Proposal, string, error) {
-	return createProposalFromCDS("", ccpack, creator, nil, nil, nil, "install")
+	return createProposalFromCDS("", ccpack, creator, nil, nil, nil, nil, "install")
 }
 
 // CreateDeployProposalFromCDS returns a deploy proposal given a serialized identity and a ChaincodeDeploymentSpec
-func CreateDeployProposalFromCDS(chainID string, cds *peer.ChaincodeDeploymentSpec, creator []byte, policy []byte, escc []byte, vscc []byte) (*peer.Proposal, string, error) {
-	return createProposalFromCDS(chainID, cds, creator, policy, escc, vscc, "deploy")
+func CreateDeployProposalFromCDS(chainID string, cds *peer.ChaincodeDeploymentSpec, creator []byte, policy []byte, escc []byte, vscc []byte, collectionConfig []byte) (*peer.Proposal, string, error) {
+	return createProposalFromCDS(chainID, cds, creator, policy, escc, vscc, collectionConfig, "deploy")
 }
 
 // CreateUpgradeProposalFromCDS returns a upgrade proposal given a serialized identity and a ChaincodeDeploymentSpec
-func CreateUpgradeProposalFromCDS(chainID string, cds *peer.ChaincodeDeploymentSpec, creator []byte, policy []byte, escc []byte, vscc []byte) (*peer.Proposal, string, error) {
-	return createProposalFromCDS(chainID, cds, creator, policy, escc, vscc, "upgrade")
+func CreateUpgradeProposalFromCDS(chainID string, cds *peer.ChaincodeDeploymentSpec, creator []byte, policy []byte, escc []byte, vscc []byte, collectionConfig []byte) (*peer.Proposal, string, error) {
+	return createProposalFromCDS(chainID, cds, creator, policy, escc, vscc, collectionConfig, "upgrade")
 }
 
-// createProposalFromCDS returns a deploy or upgrade proposal given a serialized identity and a ChaincodeDeploymentSpec
-func createProposalFromCDS(chainID string, msg proto.Message, creator []byte, policy []byte, escc []byte, vscc []byte, propType string) (*peer.Proposal, string, error) {
+// createProposalFromCDS returns a deploy or upgrade proposal given a serialized identity and a ChaincodeDeploymentSpec.
+// The collection configuration, if any, is appended to the arguments of deploy and upgrade proposals
+func createProposalFromCDS(chainID string, msg proto.Message, creator []byte, policy []byte, escc []byte, vscc []byte, collectionConfig []byte, propType string) (*peer.Proposal, string, error) {
 	//in the new mode, cds will be nil, "deploy" and "upgrade" are instantiates.
 	var ccinp *peer.ChaincodeInput
 	var b []byte
@@ -456,6 +457,9 @@ func createProposalFromCDS(chainID string, msg proto.Message, creator []byte, po
 			return nil, "", fmt.Errorf("invalid message for creating lifecycle chaincode proposal from")
 		}
 		ccinp = &peer.ChaincodeInput{Args: [][]byte{[]byte(propType), []byte(chainID), b, policy, escc, vscc}}
+		if collectionConfig != nil {
+			ccinp.Args = append(ccinp.Args, collectionConfig)
+		}
 	case "install":
 		ccinp = &peer.ChaincodeInput{Args: [][]byte{[]byte(propType), b}}
 	}
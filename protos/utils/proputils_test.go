@@ -210,13 +210,24 @@ func TestCDSProposals(t *testing.T) {
 	assert.NotEqual(t, "", txid, "txid should not be empty")
 
 	// deploy
-	prop, txid, err = utils.CreateDeployProposalFromCDS(chainID, cds, creator, policy, escc, vscc)
+	prop, txid, err = utils.CreateDeployProposalFromCDS(chainID, cds, creator, policy, escc, vscc, nil)
 	assert.NotNil(t, prop, "Deploy proposal should not be nil")
 	assert.NoError(t, err, "Unexpected error creating deploy proposal")
 	assert.NotEqual(t, "", txid, "txid should not be empty")
 
+	// deploy with collections
+	collectionConfig := []byte("collections")
+	prop, txid, err = utils.CreateDeployProposalFromCDS(chainID, cds, creator, policy, escc, vscc, collectionConfig)
+	assert.NotNil(t, prop, "Deploy proposal should not be nil")
+	assert.NoError(t, err, "Unexpected error creating deploy proposal")
+	assert.NotEqual(t, "", txid, "txid should not be empty")
+	cis, err := utils.GetChaincodeInvocationSpec(prop)
+	assert.NoError(t, err, "Unexpected error getting chaincode invocation spec")
+	assert.Len(t, cis.ChaincodeSpec.Input.Args, 7, "Deploy proposal should carry the collection configuration")
+	assert.Equal(t, collectionConfig, cis.ChaincodeSpec.Input.Args[6])
+
 	// upgrade
-	prop, txid, err = utils.CreateUpgradeProposalFromCDS(chainID, cds, creator, policy, escc, vscc)
+	prop, txid, err = utils.CreateUpgradeProposalFromCDS(chainID, cds, creator, policy, escc, vscc, nil)
 	assert.NotNil(t, prop, "Upgrade proposal should not be nil")
 	assert.NoError(t, err, "Unexpected error creating upgrade proposal")
 	assert.NotEqual(t, "", txid, "txid should not be empty")
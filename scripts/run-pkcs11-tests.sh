@@ -0,0 +1,52 @@
+#!/bin/bash
+#
+# Copyright IBM Corp. All Rights Reserved.
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+
+# Runs the PKCS11 BCCSP tests against a throwaway SoftHSM token, so that
+# the pkcs11 implementation and factory are exercised without real hardware.
+# Requires softhsm2 (installed in the testenv image).
+
+set -e
+
+TEST_PKGS=${TEST_PKGS:-"./bccsp/pkcs11/... ./bccsp/factory/..."}
+
+if [ -z "$PKCS11_LIB" ]; then
+  for lib in /usr/lib/softhsm/libsofthsm2.so \
+             /usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so \
+             /usr/lib/s390x-linux-gnu/softhsm/libsofthsm2.so \
+             /usr/lib/powerpc64le-linux-gnu/softhsm/libsofthsm2.so \
+             /usr/local/lib/softhsm/libsofthsm2.so; do
+    if [ -f "$lib" ]; then
+      PKCS11_LIB=$lib
+      break
+    fi
+  done
+fi
+
+if [ -z "$PKCS11_LIB" ]; then
+  echo "SoftHSM library not found. Install softhsm2 or set PKCS11_LIB."
+  exit 1
+fi
+
+SOFTHSM_DIR=$(mktemp -d)
+trap "rm -rf $SOFTHSM_DIR" EXIT
+
+mkdir -p $SOFTHSM_DIR/tokens
+cat > $SOFTHSM_DIR/softhsm2.conf <<EOF
+directories.tokendir = $SOFTHSM_DIR/tokens
+objectstore.backend = file
+log.level = ERROR
+EOF
+
+export SOFTHSM2_CONF=$SOFTHSM_DIR/softhsm2.conf
+export PKCS11_LIB
+export PKCS11_PIN=${PKCS11_PIN:-98765432}
+export PKCS11_LABEL=${PKCS11_LABEL:-ForFabric}
+
+softhsm2-util --init-token --slot 0 --label "$PKCS11_LABEL" --so-pin 1234 --pin "$PKCS11_PIN"
+
+echo "Running PKCS11 tests with $PKCS11_LIB..."
+go test -cover -timeout=20m $TEST_PKGS